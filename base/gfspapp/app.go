@@ -7,6 +7,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspclient"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptls"
 	"github.com/bnb-chain/greenfield-storage-provider/core/consensus"
 	corelifecycle "github.com/bnb-chain/greenfield-storage-provider/core/lifecycle"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
@@ -26,8 +27,9 @@ type GfSpBaseApp struct {
 	operatorAddress string
 	chainID         string
 
-	server *grpc.Server
-	client *gfspclient.GfSpClient
+	server      *grpc.Server
+	client      *gfspclient.GfSpClient
+	tlsReloader *gfsptls.CertReloader
 
 	gfSpDB       spdb.SPDB
 	gfBsDB       bsdb.BSDB
@@ -64,6 +66,7 @@ type GfSpBaseApp struct {
 	gcObjectTimeout     int64
 	gcZombieTimeout     int64
 	gcMetaTimeout       int64
+	scrubGVGTimeout     int64
 	migratePieceTimeout int64
 	migrateGVGTimeout   int64
 
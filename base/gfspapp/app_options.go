@@ -1,14 +1,19 @@
 package gfspapp
 
 import (
+	"fmt"
 	"math"
 	"os"
 	"strings"
+	"time"
+
+	"google.golang.org/grpc"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspclient"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfsppieceop"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfsprcmgr"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptls"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptqueue"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspvgmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gnfd"
@@ -156,6 +161,7 @@ func DefaultStaticOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 	app.gcObjectTimeout = cfg.Task.GcObjectTaskTimeout
 	app.gcZombieTimeout = cfg.Task.GcZombieTaskTimeout
 	app.gcMetaTimeout = cfg.Task.GcMetaTaskTimeout
+	app.scrubGVGTimeout = cfg.Task.ScrubGVGTaskTimeout
 	app.sealObjectRetry = cfg.Task.SealObjectTaskRetry
 	app.replicateRetry = cfg.Task.ReplicateTaskRetry
 	app.receiveConfirmRetry = cfg.Task.ReceiveConfirmTaskRetry
@@ -173,10 +179,56 @@ func DefaultStaticOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 	app.signer = &coremodule.NilModular{}
 	app.metrics = &coremodule.NilModular{}
 	app.pprof = &coremodule.NilModular{}
-	app.newRpcServer()
+	if !cfg.TLS.Enable {
+		app.newRpcServer()
+		return nil
+	}
+	reloader, err := NewTLSReloader(&cfg.TLS)
+	if err != nil {
+		log.Errorw("failed to load tls files", "error", err)
+		return err
+	}
+	app.tlsReloader = reloader
+	app.newRpcServer(grpc.Creds(reloader.ServerCredentials(cfg.TLS.AllowedClientIdentities)))
 	return nil
 }
 
+// NewTLSReloader returns the reloader of the mutual TLS files.
+func NewTLSReloader(cfg *gfspconfig.TLSConfig) (*gfsptls.CertReloader, error) {
+	return gfsptls.NewCertReloader(cfg.CAFile, cfg.CertFile, cfg.KeyFile, time.Duration(cfg.ReloadIntervalSec)*time.Second)
+}
+
+// TLSIdentities returns the expected identities of the endpoints keyed by the endpoint address. The modules
+// sharing an address are served by the same server, so an error is returned if they expect different identities.
+func TLSIdentities(cfg *gfspconfig.EndpointConfig) (map[string]string, error) {
+	identities := make(map[string]string)
+	for _, endpoint := range []struct {
+		name     string
+		address  string
+		identity string
+	}{
+		{"ApproverIdentity", cfg.ApproverEndpoint, cfg.ApproverIdentity},
+		{"ManagerIdentity", cfg.ManagerEndpoint, cfg.ManagerIdentity},
+		{"DownloaderIdentity", cfg.DownloaderEndpoint, cfg.DownloaderIdentity},
+		{"ReceiverIdentity", cfg.ReceiverEndpoint, cfg.ReceiverIdentity},
+		{"MetadataIdentity", cfg.MetadataEndpoint, cfg.MetadataIdentity},
+		{"UploaderIdentity", cfg.UploaderEndpoint, cfg.UploaderIdentity},
+		{"P2PIdentity", cfg.P2PEndpoint, cfg.P2PIdentity},
+		{"SignerIdentity", cfg.SignerEndpoint, cfg.SignerIdentity},
+		{"AuthenticatorIdentity", cfg.AuthenticatorEndpoint, cfg.AuthenticatorIdentity},
+	} {
+		if endpoint.identity == "" {
+			continue
+		}
+		if identity, ok := identities[endpoint.address]; ok && identity != endpoint.identity {
+			return nil, fmt.Errorf("%s %q conflicts with the identity %q of the same endpoint %s",
+				endpoint.name, endpoint.identity, identity, endpoint.address)
+		}
+		identities[endpoint.address] = endpoint.identity
+	}
+	return identities, nil
+}
+
 func DefaultGfSpClientOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 	if cfg.Endpoint.ApproverEndpoint == "" {
 		cfg.Endpoint.ApproverEndpoint = cfg.GRPCAddress
@@ -219,6 +271,14 @@ func DefaultGfSpClientOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error
 		cfg.Endpoint.SignerEndpoint,
 		cfg.Endpoint.AuthenticatorEndpoint,
 		!cfg.Monitor.DisableMetrics)
+	if app.tlsReloader != nil {
+		identities, err := TLSIdentities(&cfg.Endpoint)
+		if err != nil {
+			log.Errorw("failed to check tls identities", "error", err)
+			return err
+		}
+		app.client.SetTLS(app.tlsReloader, identities)
+	}
 	return nil
 }
 
@@ -227,6 +287,9 @@ func DefaultGfSpDBOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 		app.gfSpDB = cfg.Customize.GfSpDB
 		return nil
 	}
+	if val, ok := os.LookupEnv(sqldb.SpDBDriver); ok {
+		cfg.SpDB.Driver = val
+	}
 	if val, ok := os.LookupEnv(sqldb.SpDBUser); ok {
 		cfg.SpDB.User = val
 	}
@@ -274,6 +337,9 @@ func DefaultGfSpDBOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 }
 
 func DefaultGfBsDBOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
+	if val, ok := os.LookupEnv(bsdb.BsDBDriver); ok {
+		cfg.BsDB.Driver = val
+	}
 	if val, ok := os.LookupEnv(bsdb.BsDBUser); ok {
 		cfg.BsDB.User = val
 	}
@@ -286,6 +352,9 @@ func DefaultGfBsDBOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error {
 	if val, ok := os.LookupEnv(bsdb.BsDBDataBase); ok {
 		cfg.BsDB.Database = val
 	}
+	if val, ok := os.LookupEnv(bsdb.BsDBSwitchedDriver); ok {
+		cfg.BsDBBackup.Driver = val
+	}
 	if val, ok := os.LookupEnv(bsdb.BsDBSwitchedUser); ok {
 		cfg.BsDBBackup.User = val
 	}
@@ -392,7 +461,11 @@ func DefaultGfSpTQueueOption(app *GfSpBaseApp, cfg *gfspconfig.GfSpConfig) error
 		cfg.Customize.NewStrategyTQueueFunc = gfsptqueue.NewGfSpTQueue
 	}
 	if cfg.Customize.NewStrategyTQueueWithLimitFunc == nil {
-		cfg.Customize.NewStrategyTQueueWithLimitFunc = gfsptqueue.NewGfSpTQueueWithLimit
+		if cfg.Manager.EnablePersistentTaskQueue && app.gfSpDB != nil {
+			cfg.Customize.NewStrategyTQueueWithLimitFunc = gfsptqueue.NewGfSpDBTQueueWithLimitFunc(app.gfSpDB)
+		} else {
+			cfg.Customize.NewStrategyTQueueWithLimitFunc = gfsptqueue.NewGfSpTQueueWithLimit
+		}
 	}
 	if cfg.Customize.NewVirtualGroupManagerFunc == nil {
 		cfg.Customize.NewVirtualGroupManagerFunc = gfspvgmgr.NewVirtualGroupManager
@@ -0,0 +1,28 @@
+package gfspapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+)
+
+func TestTLSIdentities(t *testing.T) {
+	// the modules sharing an address expect the same identity, or leave it empty
+	identities, err := TLSIdentities(&gfspconfig.EndpointConfig{
+		ApproverEndpoint: "sp:9333", ApproverIdentity: "sp",
+		ManagerEndpoint: "sp:9333",
+		SignerEndpoint:  "sp:9333", SignerIdentity: "sp",
+		MetadataEndpoint: "metadata:9333", MetadataIdentity: "metadata",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"sp:9333": "sp", "metadata:9333": "metadata"}, identities)
+
+	_, err = TLSIdentities(&gfspconfig.EndpointConfig{
+		ApproverEndpoint: "sp:9333", ApproverIdentity: "approver",
+		SignerEndpoint: "sp:9333", SignerIdentity: "signer",
+	})
+	assert.ErrorContains(t, err, `SignerIdentity "signer" conflicts with the identity "approver"`)
+}
@@ -2,6 +2,7 @@ package gfspapp
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"time"
 
@@ -61,6 +62,67 @@ func (g *GfSpBaseApp) OnDownloadObjectTask(ctx context.Context, downloadObjectTa
 	return data, nil
 }
 
+func (g *GfSpBaseApp) GfSpDownloadObjectStream(req *gfspserver.GfSpDownloadObjectRequest,
+	stream gfspserver.GfSpDownloadService_GfSpDownloadObjectStreamServer) error {
+	downloadObjectTask := req.GetDownloadObjectTask()
+	if downloadObjectTask == nil {
+		log.Error("failed to stream download object due to task pointer dangling")
+		return stream.Send(&gfspserver.GfSpDownloadObjectResponse{Err: ErrDownloadTaskDangling})
+	}
+	ctx := log.WithValue(stream.Context(), log.CtxKeyTask, downloadObjectTask.Key().String())
+	// only one segment piece is held in memory at a time, reserve the memory by segment size
+	span, err := g.downloader.ReserveResource(ctx, downloadObjectTask.EstimateStreamLimit().ScopeStat())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to reserve stream download object resource", "error", err)
+		return stream.Send(&gfspserver.GfSpDownloadObjectResponse{Err: ErrDownloadExhaustResource})
+	}
+	defer span.Done()
+	reader, err := g.OnDownloadObjectTaskStream(ctx, downloadObjectTask)
+	if err != nil {
+		return stream.Send(&gfspserver.GfSpDownloadObjectResponse{Err: gfsperrors.MakeGfSpError(err)})
+	}
+	defer reader.Close()
+	sendSize, err := io.Copy(&downloadStreamWriter{stream: stream}, reader)
+	log.CtxDebugw(ctx, "finished to stream download object", "send_size", sendSize, "error", err)
+	if err != nil {
+		return stream.Send(&gfspserver.GfSpDownloadObjectResponse{Err: gfsperrors.MakeGfSpError(err)})
+	}
+	g.downloader.PostDownloadObject(ctx, downloadObjectTask)
+	return nil
+}
+
+func (g *GfSpBaseApp) OnDownloadObjectTaskStream(ctx context.Context, downloadObjectTask task.DownloadObjectTask) (
+	io.ReadCloser, error) {
+	if downloadObjectTask == nil || downloadObjectTask.GetObjectInfo() == nil {
+		log.CtxError(ctx, "failed to stream download object due to task pointer dangling")
+		return nil, ErrDownloadTaskDangling
+	}
+	err := g.downloader.PreDownloadObject(ctx, downloadObjectTask)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to pre download object", "task_info", downloadObjectTask.Info(), "error", err)
+		return nil, err
+	}
+	reader, err := g.downloader.HandleDownloadObjectTaskStream(ctx, downloadObjectTask)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to stream download object", "error", err)
+		return nil, err
+	}
+	return reader, nil
+}
+
+// downloadStreamWriter sends every written chunk as a response of the download object stream.
+type downloadStreamWriter struct {
+	stream gfspserver.GfSpDownloadService_GfSpDownloadObjectStreamServer
+}
+
+func (w *downloadStreamWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&gfspserver.GfSpDownloadObjectResponse{Data: p}); err != nil {
+		log.CtxErrorw(w.stream.Context(), "failed to send download object stream data", "error", err)
+		return 0, ErrExceptionsStream
+	}
+	return len(p), nil
+}
+
 func (g *GfSpBaseApp) GfSpDownloadPiece(ctx context.Context, req *gfspserver.GfSpDownloadPieceRequest) (
 	*gfspserver.GfSpDownloadPieceResponse, error) {
 	downloadPieceTask := req.GetDownloadPieceTask()
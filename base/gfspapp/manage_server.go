@@ -167,6 +167,10 @@ func (g *GfSpBaseApp) GfSpAskTask(ctx context.Context, req *gfspserver.GfSpAskTa
 		resp.Response = &gfspserver.GfSpAskTaskResponse_MigrateGvgTask{
 			MigrateGvgTask: t,
 		}
+	case *gfsptask.GfSpScrubGVGTask:
+		resp.Response = &gfspserver.GfSpAskTaskResponse_ScrubGvgTask{
+			ScrubGvgTask: t,
+		}
 	default:
 		log.CtxErrorw(ctx, "[BUG] Unsupported task type to dispatch")
 		return &gfspserver.GfSpAskTaskResponse{Err: ErrUnsupportedTaskType}, nil
@@ -303,6 +307,12 @@ func (g *GfSpBaseApp) GfSpReportTask(ctx context.Context, req *gfspserver.GfSpRe
 		task.SetAddress(GetRPCRemoteAddress(ctx))
 		log.CtxInfow(ctx, "begin to handle reported migrate gvg task", "task_info", task.Info())
 		err = g.manager.HandleMigrateGVGTask(ctx, t.MigrateGvgTask)
+	case *gfspserver.GfSpReportTaskRequest_ScrubGvgTask:
+		task := t.ScrubGvgTask
+		ctx = log.WithValue(ctx, log.CtxKeyTask, task.Key().String())
+		task.SetAddress(GetRPCRemoteAddress(ctx))
+		log.CtxInfow(ctx, "begin to handle reported task", "task_info", task.Info())
+		err = g.manager.HandleScrubGVGTask(ctx, t.ScrubGvgTask)
 	default:
 		log.CtxError(ctx, "receive unsupported task type")
 		return &gfspserver.GfSpReportTaskResponse{Err: ErrUnsupportedTaskType}, nil
@@ -334,3 +344,32 @@ func (g *GfSpBaseApp) GfSpNotifyMigrateSwapOut(ctx context.Context,
 	}
 	return &gfspserver.GfSpNotifyMigrateSwapOutResponse{}, nil
 }
+
+func (g *GfSpBaseApp) GfSpReportSPCallRecords(ctx context.Context, req *gfspserver.GfSpReportSPCallRecordsRequest) (
+	*gfspserver.GfSpReportSPCallRecordsResponse, error) {
+	if err := g.manager.ReportSPCallRecords(ctx, req.GetRecords()); err != nil {
+		log.CtxErrorw(ctx, "failed to report sp call records", "error", err)
+		return &gfspserver.GfSpReportSPCallRecordsResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	return &gfspserver.GfSpReportSPCallRecordsResponse{}, nil
+}
+
+func (g *GfSpBaseApp) GfSpQuerySPHealth(ctx context.Context, req *gfspserver.GfSpQuerySPHealthRequest) (
+	*gfspserver.GfSpQuerySPHealthResponse, error) {
+	healths, err := g.manager.QuerySPHealth(ctx, req.GetSpEndpoint())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to query sp health", "sp_endpoint", req.GetSpEndpoint(), "error", err)
+		return &gfspserver.GfSpQuerySPHealthResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	return &gfspserver.GfSpQuerySPHealthResponse{SpHealths: healths}, nil
+}
+
+func (g *GfSpBaseApp) GfSpQueryScrubReport(ctx context.Context, req *gfspserver.GfSpQueryScrubReportRequest) (
+	*gfspserver.GfSpQueryScrubReportResponse, error) {
+	reports, pieces, err := g.manager.QueryScrubReport(ctx, req.GetGvgId(), req.GetPieceLimit())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to query scrub report", "gvg_id", req.GetGvgId(), "error", err)
+		return &gfspserver.GfSpQueryScrubReportResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	return &gfspserver.GfSpQueryScrubReportResponse{Reports: reports, Pieces: pieces}, nil
+}
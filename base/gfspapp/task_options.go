@@ -43,6 +43,10 @@ const (
 	MinGCMetaTime int64 = 300
 	// MaxGCMetaTime defines the max timeout to gc meta.
 	MaxGCMetaTime int64 = 600
+	// MinScrubGVGTime defines the min timeout to scrub gvg.
+	MinScrubGVGTime int64 = 300
+	// MaxScrubGVGTime defines the max timeout to scrub gvg.
+	MaxScrubGVGTime int64 = 600
 	// MinRecoveryTime defines the min timeout to recovery object.
 	MinRecoveryTime int64 = 10
 	// MaxRecoveryTime defines the max timeout to replicate object.
@@ -175,6 +179,14 @@ func (g *GfSpBaseApp) TaskTimeout(task coretask.Task, size uint64) int64 {
 			return MaxGCMetaTime
 		}
 		return g.gcMetaTimeout
+	case coretask.TypeTaskScrubGVG:
+		if g.scrubGVGTimeout < MinScrubGVGTime {
+			return MinScrubGVGTime
+		}
+		if g.scrubGVGTimeout > MaxScrubGVGTime {
+			return MaxScrubGVGTime
+		}
+		return g.scrubGVGTimeout
 	case coretask.TypeTaskRecoverPiece:
 		timeout := int64(size)/(g.replicateSpeed+1)/(MinSpeed) + 100
 		if timeout < MinRecoveryTime {
@@ -321,6 +333,8 @@ func (g *GfSpBaseApp) TaskPriority(task coretask.Task) coretask.TPriority {
 		return coretask.UnSchedulingPriority
 	case coretask.TypeTaskGCMeta:
 		return coretask.UnSchedulingPriority
+	case coretask.TypeTaskScrubGVG:
+		return coretask.UnSchedulingPriority
 	case coretask.TypeTaskRecoverPiece:
 		return coretask.DefaultSmallerPriority / 4
 	case coretask.TypeTaskMigrateGVG:
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
 )
@@ -239,3 +241,154 @@ func (g *GfSpBaseApp) GfSpResumableUploadObject(stream gfspserver.GfSpUploadServ
 	log.CtxDebugw(ctx, "succeed to upload object")
 	return nil
 }
+
+// GfSpUploadObjectPart receives the part data by the stream, the first message carries the task, it is received
+// before the data, so the closed stream is responded at once. The following payloads are received by another
+// goroutine and passed to the uploader by the pipe, the goroutine does not share any variable with the handler.
+func (g *GfSpBaseApp) GfSpUploadObjectPart(stream gfspserver.GfSpUploadService_GfSpUploadObjectPartServer) error {
+	var (
+		span        rcmgr.ResourceScopeSpan
+		partNumber  uint32
+		resp        = &gfspserver.GfSpUploadObjectPartResponse{}
+		ctx, cancel = context.WithCancel(context.Background())
+		err         error
+		receiveSize atomic.Int64
+	)
+	defer func() {
+		defer cancel()
+		if span != nil {
+			span.Done()
+		}
+		log.CtxDebugw(ctx, "finish to receive object part stream data", "part_number", partNumber,
+			"receive_size", receiveSize.Load(), "error", err)
+		if err != nil {
+			resp.Err = gfsperrors.MakeGfSpError(err)
+		}
+
+		err = stream.SendAndClose(resp)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to close upload object part stream", "error", err)
+		}
+	}()
+
+	req, err := stream.Recv()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to receive first object part stream data", "error", err)
+		if err == io.EOF {
+			err = ErrUploadObjectDangling
+		} else {
+			err = ErrExceptionsStream
+		}
+		return nil
+	}
+	task := req.GetResumableUploadObjectTask()
+	if task == nil {
+		log.CtxErrorw(ctx, "[BUG] failed to receive object part, upload object task pointer dangling !!!")
+		err = ErrUploadObjectDangling
+		return nil
+	}
+	partNumber = req.GetPartNumber()
+	ctx = log.WithValue(ctx, log.CtxKeyTask, task.Key().String())
+	if span, err = g.uploader.ReserveResource(ctx, task.EstimateLimit().ScopeStat()); err != nil {
+		log.CtxErrorw(ctx, "failed to reserve resource", "error", err)
+		err = ErrUploadExhaustResource
+		return nil
+	}
+
+	pRead, pWrite := io.Pipe()
+	go func(payload []byte) {
+		for {
+			receiveSize.Add(int64(len(payload)))
+			if _, writeErr := pWrite.Write(payload); writeErr != nil {
+				// the uploader stops reading the part
+				return
+			}
+			msg, recvErr := stream.Recv()
+			if recvErr == io.EOF {
+				log.CtxDebugw(ctx, "received last upload part stream data")
+				pWrite.Close()
+				return
+			}
+			if recvErr != nil {
+				log.CtxErrorw(ctx, "failed to receive object part", "error", recvErr)
+				pWrite.CloseWithError(ErrExceptionsStream)
+				return
+			}
+			payload = msg.GetPayload()
+		}
+	}(req.GetPayload())
+
+	resp.Etag, err = g.uploader.HandleUploadObjectPart(ctx, task, partNumber, pRead)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to upload object part data", "error", err)
+		pRead.CloseWithError(err)
+		return nil
+	}
+	log.CtxDebugw(ctx, "succeed to upload object part")
+	return nil
+}
+
+func (g *GfSpBaseApp) GfSpListMultipartUploadParts(ctx context.Context, req *gfspserver.GfSpListMultipartUploadPartsRequest) (
+	*gfspserver.GfSpListMultipartUploadPartsResponse, error) {
+	parts, err := g.uploader.ListMultipartUploadParts(ctx, req.GetObjectId())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list multipart upload parts", "object_id", req.GetObjectId(), "error", err)
+		return &gfspserver.GfSpListMultipartUploadPartsResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	resp := &gfspserver.GfSpListMultipartUploadPartsResponse{}
+	for _, part := range parts {
+		resp.Parts = append(resp.Parts, &gfspserver.GfSpMultipartUploadPart{
+			PartNumber:            part.PartNumber,
+			Size_:                 part.Size,
+			Etag:                  part.ETag,
+			UpdateTimestampSecond: part.UpdateTimestampSecond,
+		})
+	}
+	return resp, nil
+}
+
+func (g *GfSpBaseApp) GfSpCompleteMultipartUpload(ctx context.Context, req *gfspserver.GfSpCompleteMultipartUploadRequest) (
+	*gfspserver.GfSpCompleteMultipartUploadResponse, error) {
+	task := req.GetResumableUploadObjectTask()
+	if task == nil {
+		log.CtxErrorw(ctx, "[BUG] failed to complete multipart upload, upload object task pointer dangling !!!")
+		return &gfspserver.GfSpCompleteMultipartUploadResponse{Err: ErrUploadObjectDangling}, nil
+	}
+	ctx = log.WithValue(ctx, log.CtxKeyTask, task.Key().String())
+	// the resource is reserved by the uploader until the parts are assembled in the background
+	if err := g.uploader.PreResumableUploadObject(ctx, task); err != nil {
+		log.CtxErrorw(ctx, "failed to pre complete multipart upload", "error", err)
+		return &gfspserver.GfSpCompleteMultipartUploadResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	defer g.uploader.PostResumableUploadObject(ctx, task)
+	parts := make([]*corespdb.MultipartUploadPart, 0, len(req.GetParts()))
+	for _, part := range req.GetParts() {
+		parts = append(parts, &corespdb.MultipartUploadPart{
+			ObjectID:   task.GetObjectInfo().Id.Uint64(),
+			PartNumber: part.GetPartNumber(),
+			Size:       part.GetSize_(),
+			ETag:       part.GetEtag(),
+		})
+	}
+	if err := g.uploader.HandleCompleteMultipartUpload(ctx, task, parts); err != nil {
+		log.CtxErrorw(ctx, "failed to complete multipart upload", "error", err)
+		return &gfspserver.GfSpCompleteMultipartUploadResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	log.CtxDebugw(ctx, "succeed to start completing multipart upload")
+	return &gfspserver.GfSpCompleteMultipartUploadResponse{}, nil
+}
+
+func (g *GfSpBaseApp) GfSpAbortMultipartUpload(ctx context.Context, req *gfspserver.GfSpAbortMultipartUploadRequest) (
+	*gfspserver.GfSpAbortMultipartUploadResponse, error) {
+	task := req.GetResumableUploadObjectTask()
+	if task == nil {
+		log.CtxErrorw(ctx, "[BUG] failed to abort multipart upload, upload object task pointer dangling !!!")
+		return &gfspserver.GfSpAbortMultipartUploadResponse{Err: ErrUploadObjectDangling}, nil
+	}
+	ctx = log.WithValue(ctx, log.CtxKeyTask, task.Key().String())
+	if err := g.uploader.HandleAbortMultipartUpload(ctx, task); err != nil {
+		log.CtxErrorw(ctx, "failed to abort multipart upload", "error", err)
+		return &gfspserver.GfSpAbortMultipartUploadResponse{Err: gfsperrors.MakeGfSpError(err)}, nil
+	}
+	return &gfspserver.GfSpAbortMultipartUploadResponse{}, nil
+}
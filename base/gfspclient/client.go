@@ -9,6 +9,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptls"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	utilgrpc "github.com/bnb-chain/greenfield-storage-provider/util/grpc"
@@ -53,6 +54,9 @@ type GfSpClient struct {
 	signerConn   *grpc.ClientConn
 	httpClient   *http.Client
 	metrics      bool
+
+	tlsReloader *gfsptls.CertReloader
+	identities  map[string]string
 }
 
 func NewGfSpClient(
@@ -80,8 +84,19 @@ func NewGfSpClient(
 	}
 }
 
+// SetTLS enables the mutual TLS of the connections, identities maps the endpoint address to the
+// expected identity of the endpoint's certificate. It should be called before any connection.
+func (s *GfSpClient) SetTLS(reloader *gfsptls.CertReloader, identities map[string]string) {
+	s.tlsReloader = reloader
+	s.identities = identities
+}
+
 func (s *GfSpClient) Connection(ctx context.Context, address string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	options := append(DefaultClientOptions(), opts...)
+	if s.tlsReloader != nil {
+		// overwrites the insecure credentials of the default options
+		options = append(options, grpc.WithTransportCredentials(s.tlsReloader.ClientCredentials(s.identities[address])))
+	}
 	return grpc.DialContext(ctx, address, options...)
 }
 
@@ -2,6 +2,8 @@ package gfspclient
 
 import (
 	"context"
+	"io"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
@@ -35,6 +37,112 @@ func (s *GfSpClient) GetObject(ctx context.Context, downloadObjectTask coretask.
 	return resp.GetData(), nil
 }
 
+// GetObjectStream downloads the object by the server streaming call, the returned reader
+// yields the data as it arrives from the downloader. The first response is received before
+// returning, so the errors such as quota exceeded are returned before any data is consumed.
+// The caller must close the reader to release the connection.
+func (s *GfSpClient) GetObjectStream(ctx context.Context, downloadObjectTask coretask.DownloadObjectTask,
+	opts ...grpc.DialOption) (io.ReadCloser, error) {
+	conn, connErr := s.Connection(ctx, s.downloaderEndpoint, opts...)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect downloader", "error", connErr)
+		return nil, ErrRpcUnknown
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	req := &gfspserver.GfSpDownloadObjectRequest{
+		DownloadObjectTask: downloadObjectTask.(*gfsptask.GfSpDownloadObjectTask),
+	}
+	stream, err := gfspserver.NewGfSpDownloadServiceClient(conn).GfSpDownloadObjectStream(ctx, req)
+	if err != nil {
+		cancel()
+		conn.Close()
+		log.CtxErrorw(ctx, "client failed to stream download object", "error", err)
+		return nil, ErrRpcUnknown
+	}
+	reader := &downloadObjectStreamReader{
+		ctx:    ctx,
+		stream: stream,
+		closer: func() {
+			cancel()
+			conn.Close()
+		},
+	}
+	if err = reader.recv(); err != nil && err != io.EOF {
+		reader.Close()
+		return nil, err
+	}
+	return reader, nil
+}
+
+// downloadObjectStreamReader reads the object data from the download object stream.
+type downloadObjectStreamReader struct {
+	ctx    context.Context
+	stream gfspserver.GfSpDownloadService_GfSpDownloadObjectStreamClient
+	data   []byte
+	err    error
+	closer func()
+	once   sync.Once
+}
+
+// recv receives the next response from the stream, the error is kept and returned to
+// all the following calls.
+func (r *downloadObjectStreamReader) recv() error {
+	if r.err != nil {
+		return r.err
+	}
+	resp, err := r.stream.Recv()
+	switch {
+	case err == io.EOF:
+		r.err = io.EOF
+	case err != nil:
+		log.CtxErrorw(r.ctx, "client failed to receive download object stream", "error", err)
+		r.err = ErrExceptionsStream
+	case resp.GetErr() != nil:
+		r.err = resp.GetErr()
+	default:
+		r.data = resp.GetData()
+	}
+	return r.err
+}
+
+func (r *downloadObjectStreamReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if err := r.recv(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// WriteTo writes every received chunk to w directly, it avoids the extra copy buffer
+// when the reader is consumed by io.Copy.
+func (r *downloadObjectStreamReader) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for {
+		if len(r.data) == 0 {
+			if err := r.recv(); err == io.EOF {
+				return total, nil
+			} else if err != nil {
+				return total, err
+			}
+			continue
+		}
+		n, err := w.Write(r.data)
+		total += int64(n)
+		r.data = r.data[n:]
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+func (r *downloadObjectStreamReader) Close() error {
+	r.once.Do(r.closer)
+	return nil
+}
+
 func (s *GfSpClient) GetPiece(ctx context.Context, downloadPieceTask coretask.DownloadPieceTask, opts ...grpc.DialOption) (
 	[]byte, error) {
 	conn, connErr := s.Connection(ctx, s.downloaderEndpoint, opts...)
@@ -90,6 +90,8 @@ func (s *GfSpClient) AskTask(ctx context.Context, limit corercmgr.Limit) (coreta
 		return t.RecoverPieceTask, nil
 	case *gfspserver.GfSpAskTaskResponse_MigrateGvgTask:
 		return t.MigrateGvgTask, nil
+	case *gfspserver.GfSpAskTaskResponse_ScrubGvgTask:
+		return t.ScrubGvgTask, nil
 	default:
 		return nil, ErrTypeMismatch
 	}
@@ -151,6 +153,10 @@ func (s *GfSpClient) ReportTask(ctx context.Context, report coretask.Task) error
 		req.Request = &gfspserver.GfSpReportTaskRequest_MigrateGvgTask{
 			MigrateGvgTask: t,
 		}
+	case *gfsptask.GfSpScrubGVGTask:
+		req.Request = &gfspserver.GfSpReportTaskRequest_ScrubGvgTask{
+			ScrubGvgTask: t,
+		}
 	default:
 		log.CtxErrorw(ctx, "unsupported task type to report")
 		return ErrTypeMismatch
@@ -202,3 +208,69 @@ func (s *GfSpClient) NotifyMigrateSwapOut(ctx context.Context, swapOut *virtualg
 	}
 	return nil
 }
+
+func (s *GfSpClient) ReportSPCallRecords(ctx context.Context, records []*gfspserver.GfSpSPCallRecord) error {
+	conn, connErr := s.ManagerConn(ctx)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect manager", "error", connErr)
+		return ErrRpcUnknown
+	}
+	req := &gfspserver.GfSpReportSPCallRecordsRequest{
+		Records: records,
+	}
+	resp, err := gfspserver.NewGfSpManageServiceClient(conn).GfSpReportSPCallRecords(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to report sp call records", "error", err)
+		return ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return resp.GetErr()
+	}
+	return nil
+}
+
+// QuerySPHealth returns the healths of the sps which are scored by manager, the health of the specified sp is
+// returned if spEndpoint is not empty.
+func (s *GfSpClient) QuerySPHealth(ctx context.Context, spEndpoint string) ([]*gfspserver.GfSpSPHealth, error) {
+	conn, connErr := s.ManagerConn(ctx)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect manager", "error", connErr)
+		return nil, ErrRpcUnknown
+	}
+	req := &gfspserver.GfSpQuerySPHealthRequest{
+		SpEndpoint: spEndpoint,
+	}
+	resp, err := gfspserver.NewGfSpManageServiceClient(conn).GfSpQuerySPHealth(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to query sp health", "error", err)
+		return nil, ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return nil, resp.GetErr()
+	}
+	return resp.GetSpHealths(), nil
+}
+
+// QueryScrubReport returns the scrub reports of the gvgs, the report of the specified gvg and at most
+// pieceLimit bad pieces of it are returned if gvgID is not 0.
+func (s *GfSpClient) QueryScrubReport(ctx context.Context, gvgID uint32, pieceLimit uint32) (
+	[]*gfspserver.GfSpScrubGVGReport, []*gfspserver.GfSpScrubPiece, error) {
+	conn, connErr := s.ManagerConn(ctx)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect manager", "error", connErr)
+		return nil, nil, ErrRpcUnknown
+	}
+	req := &gfspserver.GfSpQueryScrubReportRequest{
+		GvgId:      gvgID,
+		PieceLimit: pieceLimit,
+	}
+	resp, err := gfspserver.NewGfSpManageServiceClient(conn).GfSpQueryScrubReport(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to query scrub report", "error", err)
+		return nil, nil, ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return nil, nil, resp.GetErr()
+	}
+	return resp.GetReports(), resp.GetPieces(), nil
+}
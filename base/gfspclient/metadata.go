@@ -32,6 +32,20 @@ func (s *GfSpClient) GetUserBucketsCount(ctx context.Context, account string, in
 	return resp.GetCount(), nil
 }
 
+func (s *GfSpClient) CountBucketsByVgfID(ctx context.Context, vgfIDs []uint32, opts ...grpc.DialOption) (map[uint32]int64, error) {
+	conn, err := s.Connection(ctx, s.metadataEndpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	req := &types.GfSpCountBucketsByVgfIDRequest{VgfIds: vgfIDs}
+	resp, err := types.NewGfSpMetadataServiceClient(conn).GfSpCountBucketsByVgfID(ctx, req)
+	if err != nil {
+		return nil, ErrRpcUnknown
+	}
+	return resp.GetCounts(), nil
+}
+
 func (s *GfSpClient) ListDeletedObjectsByBlockNumberRange(ctx context.Context, spOperatorAddress string, startBlockNumber uint64,
 	endBlockNumber uint64, includePrivate bool, opts ...grpc.DialOption) ([]*types.Object, uint64, error) {
 	conn, err := s.Connection(ctx, s.metadataEndpoint, opts...)
@@ -100,6 +114,25 @@ func (s *GfSpClient) ListObjectsByBucketName(ctx context.Context, bucketName str
 		resp.GetName(), resp.GetPrefix(), resp.GetDelimiter(), resp.GetCommonPrefixes(), resp.GetContinuationToken(), nil
 }
 
+// ListObjectsByBucketNameWithFilter list objects info by a bucket name with the filters, the order and the stats
+// of the request
+func (s *GfSpClient) ListObjectsByBucketNameWithFilter(ctx context.Context, req *types.GfSpListObjectsByBucketNameRequest,
+	opts ...grpc.DialOption) (*types.GfSpListObjectsByBucketNameResponse, error) {
+	conn, err := s.Connection(ctx, s.metadataEndpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	resp, err := types.NewGfSpMetadataServiceClient(conn).GfSpListObjectsByBucketName(ctx, req)
+	ctx = log.Context(ctx, resp)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to send list objects by bucket name rpc", "error", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetBucketByBucketName get bucket info by a bucket name
 func (s *GfSpClient) GetBucketByBucketName(ctx context.Context, bucketName string, includePrivate bool,
 	opts ...grpc.DialOption) (*types.Bucket, error) {
@@ -155,3 +155,129 @@ func (s *GfSpClient) ResumableUploadObject(ctx context.Context, task coretask.Re
 		}
 	}
 }
+
+func (s *GfSpClient) UploadObjectPart(ctx context.Context, task coretask.ResumableUploadObjectTask, partNumber uint32,
+	stream io.Reader) (string, error) {
+	conn, connErr := s.Connection(ctx, s.uploaderEndpoint)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect uploader", "error", connErr)
+		return "", ErrRpcUnknown
+	}
+	var sendSize = 0
+	defer func() {
+		conn.Close()
+		log.CtxDebugw(ctx, "finished to send part payload data", "part_number", partNumber, "send_size", sendSize)
+	}()
+	client, err := gfspserver.NewGfSpUploadServiceClient(conn).GfSpUploadObjectPart(ctx)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to new uploader stream client", "error", err)
+		return "", ErrRpcUnknown
+	}
+	buf := make([]byte, DefaultStreamBufSize)
+	for {
+		n, streamErr := stream.Read(buf)
+		sendSize += n
+		if streamErr != nil && streamErr != io.EOF {
+			log.CtxErrorw(ctx, "failed to read upload part data stream", "error", streamErr)
+			return "", ErrExceptionsStream
+		}
+		if n != 0 {
+			req := &gfspserver.GfSpUploadObjectPartRequest{
+				ResumableUploadObjectTask: task.(*gfsptask.GfSpResumableUploadObjectTask),
+				PartNumber:                partNumber,
+				Payload:                   buf[0:n],
+			}
+			if err = client.Send(req); err != nil {
+				log.CtxErrorw(ctx, "failed to send the upload part stream data", "error", err)
+				return "", ErrRpcUnknown
+			}
+		}
+		if streamErr == io.EOF {
+			break
+		}
+	}
+	if sendSize == 0 {
+		// the task must be sent at least once to let uploader know the part
+		req := &gfspserver.GfSpUploadObjectPartRequest{
+			ResumableUploadObjectTask: task.(*gfsptask.GfSpResumableUploadObjectTask),
+			PartNumber:                partNumber,
+		}
+		if err = client.Send(req); err != nil {
+			log.CtxErrorw(ctx, "failed to send the upload part stream data", "error", err)
+			return "", ErrRpcUnknown
+		}
+	}
+	resp, err := client.CloseAndRecv()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to close upload part stream", "error", err)
+		return "", ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return "", resp.GetErr()
+	}
+	return resp.GetEtag(), nil
+}
+
+func (s *GfSpClient) ListMultipartUploadParts(ctx context.Context, objectID uint64) (
+	[]*gfspserver.GfSpMultipartUploadPart, error) {
+	conn, connErr := s.Connection(ctx, s.uploaderEndpoint)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect uploader", "error", connErr)
+		return nil, ErrRpcUnknown
+	}
+	defer conn.Close()
+	req := &gfspserver.GfSpListMultipartUploadPartsRequest{ObjectId: objectID}
+	resp, err := gfspserver.NewGfSpUploadServiceClient(conn).GfSpListMultipartUploadParts(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to list multipart upload parts", "error", err)
+		return nil, ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return nil, resp.GetErr()
+	}
+	return resp.GetParts(), nil
+}
+
+func (s *GfSpClient) CompleteMultipartUpload(ctx context.Context, task coretask.ResumableUploadObjectTask,
+	parts []*gfspserver.GfSpMultipartUploadPart) error {
+	conn, connErr := s.Connection(ctx, s.uploaderEndpoint)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect uploader", "error", connErr)
+		return ErrRpcUnknown
+	}
+	defer conn.Close()
+	req := &gfspserver.GfSpCompleteMultipartUploadRequest{
+		ResumableUploadObjectTask: task.(*gfsptask.GfSpResumableUploadObjectTask),
+		Parts:                     parts,
+	}
+	resp, err := gfspserver.NewGfSpUploadServiceClient(conn).GfSpCompleteMultipartUpload(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to complete multipart upload", "error", err)
+		return ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return resp.GetErr()
+	}
+	return nil
+}
+
+func (s *GfSpClient) AbortMultipartUpload(ctx context.Context, task coretask.ResumableUploadObjectTask) error {
+	conn, connErr := s.Connection(ctx, s.uploaderEndpoint)
+	if connErr != nil {
+		log.CtxErrorw(ctx, "client failed to connect uploader", "error", connErr)
+		return ErrRpcUnknown
+	}
+	defer conn.Close()
+	req := &gfspserver.GfSpAbortMultipartUploadRequest{
+		ResumableUploadObjectTask: task.(*gfsptask.GfSpResumableUploadObjectTask),
+	}
+	resp, err := gfspserver.NewGfSpUploadServiceClient(conn).GfSpAbortMultipartUpload(ctx, req)
+	if err != nil {
+		log.CtxErrorw(ctx, "client failed to abort multipart upload", "error", err)
+		return ErrRpcUnknown
+	}
+	if resp.GetErr() != nil {
+		return resp.GetErr()
+	}
+	return nil
+}
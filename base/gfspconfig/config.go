@@ -61,6 +61,7 @@ type GfSpConfig struct {
 	BlockSyncer    BlockSyncerConfig
 	APIRateLimiter localhttp.RateLimiterConfig
 	Manager        ManagerConfig
+	TLS            TLSConfig
 }
 
 // Apply sets the customized implement to the GfSp configuration, it will be called
@@ -121,6 +122,19 @@ type EndpointConfig struct {
 	P2PEndpoint           string
 	SignerEndpoint        string
 	AuthenticatorEndpoint string
+
+	// the expected identities of the endpoints' certificates if TLS is enabled, the identity
+	// matches the DNS SAN, URI SAN or common name of the certificate, empty means any
+	// certificate issued by the CA is accepted.
+	ApproverIdentity      string
+	ManagerIdentity       string
+	DownloaderIdentity    string
+	ReceiverIdentity      string
+	MetadataIdentity      string
+	UploaderIdentity      string
+	P2PIdentity           string
+	SignerIdentity        string
+	AuthenticatorIdentity string
 }
 
 type ApprovalConfig struct {
@@ -139,6 +153,14 @@ type BucketConfig struct {
 type GatewayConfig struct {
 	DomainName  string
 	HTTPAddress string
+	// S3HTTPAddress defines the listen address of the s3 compatible api, the api is disabled if it is empty
+	S3HTTPAddress string
+	// S3DomainName defines the domain name of the s3 compatible api for the virtual hosted style requests
+	S3DomainName string
+	// S3Region defines the region in the credential scope of the s3 compatible api signatures
+	S3Region string
+	// CORS defines the default cors policy of the gateway, it can be overridden by the bucket policy
+	CORS localhttp.CORSConfig
 }
 
 type ExecutorConfig struct {
@@ -149,6 +171,25 @@ type ExecutorConfig struct {
 	ListenSealTimeoutHeight      int
 	ListenSealRetryTimeout       int
 	MaxListenSealRetry           int
+	GCZombiePieceBatchNumber     int64
+	GCZombiePieceSafeTime        int64
+
+	GCMetaBatchNumber             int64
+	GCMetaUploadProgressRetention int64
+	GCMetaUploadEventRetention    int64
+	GCMetaReadRecordRetention     int64
+	GCMetaMigrateRetention        int64
+	// GCMetaMultipartUploadRetention defines the retention time in seconds of the multipart uploads which are not
+	// completed, the staged parts are discarded if no part is uploaded within the retention time.
+	GCMetaMultipartUploadRetention int64
+	// SPCallReportInterval defines the interval in seconds of reporting the results of the calls to the other sps
+	// to manager, which are used to score the healths of the sps.
+	SPCallReportInterval int
+	// ScrubObjectBatchNumber defines the number of objects that are listed and scrubbed in one batch by scrub gvg task.
+	ScrubObjectBatchNumber uint32
+	// ScrubRateLimit defines the max bytes per second of the piece data that is read by scrubbing, which keeps the
+	// scrubbing from competing with the downloads for the piece store bandwidth.
+	ScrubRateLimit int
 }
 
 type P2PConfig struct {
@@ -178,8 +219,12 @@ type ParallelConfig struct {
 	GlobalGcObjectBlockInterval        uint64
 	GlobalGcObjectSafeBlockDistance    uint64
 	GlobalSyncConsensusInfoInterval    uint64
+	GlobalBatchGcZombiePieceInterval   int
+	GlobalGcZombiePieceLimit           uint64
+	GlobalBatchGcMetaTimeInterval      int
 
 	UploadObjectParallelPerNode         int
+	UploadSegmentParallelPerObject      int
 	ReceivePieceParallelPerNode         int
 	DownloadObjectParallelPerNode       int
 	ChallengePieceParallelPerNode       int
@@ -203,6 +248,7 @@ type TaskConfig struct {
 	GcObjectTaskTimeout     int64
 	GcZombieTaskTimeout     int64
 	GcMetaTaskTimeout       int64
+	ScrubGVGTaskTimeout     int64
 	SealObjectTaskRetry     int64
 	ReplicateTaskRetry      int64
 	ReceiveConfirmTaskRetry int64
@@ -244,8 +290,62 @@ type MetadataConfig struct {
 
 type ManagerConfig struct {
 	EnableLoadTask                         bool
+	EnablePersistentTaskQueue              bool
 	SubscribeSPExitEventIntervalSec        int
 	SubscribeSwapOutExitEventIntervalSec   int
 	SubscribeBucketMigrateEventIntervalSec int
 	GVGPreferSPList                        []uint32
+	// ReadUsageRollupIntervalSec defines how often the read records are rolled up for the read usage reports.
+	ReadUsageRollupIntervalSec int
+	// ReadUsageRetentionDays defines how long the rollups of the read records are kept.
+	ReadUsageRetentionDays int
+	// ReadQuotaAlertThresholds defines the consumed percents of the read quota, e.g. [80, 90, 100], a bucket
+	// is notified once when it crosses a threshold.
+	ReadQuotaAlertThresholds []uint32
+	// ReadQuotaAlertWebhook defines the url which the read quota alerts are posted to, the alerts are
+	// disabled if it is empty.
+	ReadQuotaAlertWebhook string
+	// EventDeliveryIntervalMillisecond defines how often the object event notifications are delivered.
+	EventDeliveryIntervalMillisecond int
+	// EventDeliveryMaxAttempts defines the max attempts of delivering a notification, then it is moved to
+	// the dead letters.
+	EventDeliveryMaxAttempts int
+	// EventDeliveryMaxBackoffSec defines the max interval between the attempts of delivering a notification.
+	EventDeliveryMaxBackoffSec int
+	// VirtualGroupPickPolicy defines the policy of picking the vgf for creating bucket and the gvg for replicating
+	// object, it is one of free-storage(default), even-buckets, replicate-health, dedicated-family and bucket-cap.
+	VirtualGroupPickPolicy string
+	// DedicatedVGFAccounts defines the "account:vgf_id" entries used by the dedicated-family policy, the buckets of
+	// the accounts are kept in their dedicated vgfs, and the other buckets are not put into the dedicated vgfs.
+	DedicatedVGFAccounts []string
+	// MaxBucketNumberPerVGF defines the max number of the buckets in a vgf used by the bucket-cap policy.
+	MaxBucketNumberPerVGF uint64
+	// SPHealthMinCallNumber defines the min number of the calls to a sp before it can be regarded as unhealthy.
+	SPHealthMinCallNumber uint64
+	// SPUnhealthyScore defines the health score in (0, 1) below which a sp is avoided as secondary sp.
+	SPUnhealthyScore float64
+	// SPSlowCallLatencySec defines the latency above which a successful call to a sp is regarded as slow, the
+	// rate of the slow calls lowers the health score of the sp.
+	SPSlowCallLatencySec int64
+	// EnableScrubGVG defines whether to scrub the pieces stored by the sp in background, the pieces are checked
+	// against the integrity metas, and the bad pieces are recovered.
+	EnableScrubGVG bool
+	// ScrubGVGIntervalSec defines how often a gvg is picked to scrub if no gvg is being scrubbed.
+	ScrubGVGIntervalSec int
+	// ScrubRoundIntervalSec defines the min interval between two rounds of scrubbing the same gvg.
+	ScrubRoundIntervalSec int64
+}
+
+// TLSConfig defines the mutual TLS configuration of the grpc between the modules, the files are
+// reloaded without restart if they are changed.
+type TLSConfig struct {
+	Enable   bool
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	// ReloadIntervalSec defines the interval of checking whether the files changed.
+	ReloadIntervalSec int64
+	// AllowedClientIdentities defines the identities of clients allowed to call the grpc server,
+	// empty means any client certificate issued by the CA is allowed.
+	AllowedClientIdentities []string
 }
@@ -0,0 +1,65 @@
+package gfsphealth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+)
+
+// DefaultMaxPendingRecords defines the max number of the records which are not reported, the oldest records are
+// dropped if the reporter is unreachable for a long time.
+const DefaultMaxPendingRecords = 10000
+
+// CallRecorder records the results of the calls to the other sps in a local tracker, and keeps them until they
+// are taken to report to the manager which makes the decisions by the sp healths.
+type CallRecorder struct {
+	*Tracker
+	mux     sync.Mutex
+	pending []*gfspserver.GfSpSPCallRecord
+}
+
+// NewCallRecorder returns a sp call recorder with the default tracker.
+func NewCallRecorder() *CallRecorder {
+	return &CallRecorder{Tracker: NewTracker(0, 0, 0)}
+}
+
+// RecordCall records the result of calling the sp which is started at the startTime.
+func (r *CallRecorder) RecordCall(spEndpoint, callType string, startTime time.Time, err error) {
+	record := &gfspserver.GfSpSPCallRecord{
+		SpEndpoint:   spEndpoint,
+		CallType:     callType,
+		LatencyMs:    time.Since(startTime).Milliseconds(),
+		TimestampSec: time.Now().Unix(),
+	}
+	if err != nil {
+		record.Failed = true
+		record.Error = err.Error()
+	}
+	r.Tracker.Record(record)
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if len(r.pending) >= DefaultMaxPendingRecords {
+		r.pending = r.pending[1:]
+	}
+	r.pending = append(r.pending, record)
+}
+
+// TakePending returns the records which are not reported and clears them.
+func (r *CallRecorder) TakePending() []*gfspserver.GfSpSPCallRecord {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	records := r.pending
+	r.pending = nil
+	return records
+}
+
+// Restore puts back the records which fail to be reported.
+func (r *CallRecorder) Restore(records []*gfspserver.GfSpSPCallRecord) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.pending = append(records, r.pending...)
+	if len(r.pending) > DefaultMaxPendingRecords {
+		r.pending = r.pending[len(r.pending)-DefaultMaxPendingRecords:]
+	}
+}
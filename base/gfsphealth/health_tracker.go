@@ -0,0 +1,190 @@
+package gfsphealth
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+)
+
+const (
+	// ReplicateCallType is the call of replicating piece data to the secondary sp.
+	ReplicateCallType = "replicate"
+	// ReceiveCallType is the call of asking the secondary sp to finish receiving the pieces and sign the object.
+	ReceiveCallType = "receive"
+	// RecoveryCallType is the call of getting the piece data from the other sps to recover piece.
+	RecoveryCallType = "recovery"
+
+	// DefaultHealthDecay defines the weight of the latest call in the moving averages of the error rate, the
+	// slow rate and the latency, so that the recovered sps are regarded as healthy again.
+	DefaultHealthDecay = 0.1
+	// DefaultMinCallNumber defines the min number of the calls before a sp can be regarded as unhealthy.
+	DefaultMinCallNumber = 10
+	// DefaultUnhealthyScore defines the score below which a sp is regarded as unhealthy.
+	DefaultUnhealthyScore = 0.5
+	// DefaultSlowCallLatency defines the latency above which a successful call is regarded as slow, the calls
+	// close to the timeout are counted so that the sps which are about to time out are avoided.
+	DefaultSlowCallLatency = 30 * time.Second
+)
+
+// Score returns the health score in [0, 1] by the error rate and the slow rate which is the rate of the successful
+// calls taking longer than the slow call latency, the higher the healthier. The latency of the normal calls which
+// grows with the payload size does not lower the score.
+func Score(errorRate float64, slowRate float64) float64 {
+	score := 1 - errorRate - slowRate
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
+
+// spHealth is the health statistics of calling a sp.
+type spHealth struct {
+	callCount        uint64
+	failureCount     uint64
+	errorRate        float64
+	slowRate         float64
+	latency          float64 // seconds
+	lastFailureTime  int64
+	lastFailureError string
+}
+
+// Tracker records the results of the calls to the other sps, and scores the sps by the moving averages of the
+// error rate and the slow rate. The sps which have not been called are regarded as healthy.
+type Tracker struct {
+	mux             sync.RWMutex
+	minCallNumber   uint64
+	unhealthyScore  float64
+	slowCallLatency time.Duration
+	healths         map[string]*spHealth
+}
+
+// NewTracker returns a sp health tracker, the defaults are used if minCallNumber, unhealthyScore or
+// slowCallLatency is zero.
+func NewTracker(minCallNumber uint64, unhealthyScore float64, slowCallLatency time.Duration) *Tracker {
+	if minCallNumber == 0 {
+		minCallNumber = DefaultMinCallNumber
+	}
+	if unhealthyScore == 0 {
+		unhealthyScore = DefaultUnhealthyScore
+	}
+	if slowCallLatency == 0 {
+		slowCallLatency = DefaultSlowCallLatency
+	}
+	return &Tracker{
+		minCallNumber:   minCallNumber,
+		unhealthyScore:  unhealthyScore,
+		slowCallLatency: slowCallLatency,
+		healths:         make(map[string]*spHealth),
+	}
+}
+
+// Record records the result of a call to the sp.
+func (t *Tracker) Record(record *gfspserver.GfSpSPCallRecord) {
+	if record.GetSpEndpoint() == "" {
+		return
+	}
+	failure, slow := 0.0, 0.0
+	latency := time.Duration(record.GetLatencyMs()) * time.Millisecond
+	if record.GetFailed() {
+		failure = 1
+	} else if latency >= t.slowCallLatency {
+		slow = 1
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	health, ok := t.healths[record.GetSpEndpoint()]
+	if !ok {
+		health = &spHealth{errorRate: failure, slowRate: slow, latency: latency.Seconds()}
+		t.healths[record.GetSpEndpoint()] = health
+	} else {
+		health.errorRate += DefaultHealthDecay * (failure - health.errorRate)
+		health.slowRate += DefaultHealthDecay * (slow - health.slowRate)
+		health.latency += DefaultHealthDecay * (latency.Seconds() - health.latency)
+	}
+	health.callCount++
+	if record.GetFailed() {
+		health.failureCount++
+		if record.GetTimestampSec() >= health.lastFailureTime {
+			health.lastFailureTime = record.GetTimestampSec()
+			health.lastFailureError = record.GetError()
+		}
+	}
+}
+
+// Score returns the health score of the sp, the sp which has not been called has the full score.
+func (t *Tracker) Score(spEndpoint string) float64 {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	health, ok := t.healths[spEndpoint]
+	if !ok {
+		return 1
+	}
+	return health.score()
+}
+
+// IsHealthy returns false if the sp has been called enough times and its score is below the unhealthy score.
+func (t *Tracker) IsHealthy(spEndpoint string) bool {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	health, ok := t.healths[spEndpoint]
+	if !ok {
+		return true
+	}
+	return t.isHealthy(health)
+}
+
+// Health returns the health of the sp, ok is false if the sp has not been called.
+func (t *Tracker) Health(spEndpoint string) (*gfspserver.GfSpSPHealth, bool) {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	health, ok := t.healths[spEndpoint]
+	if !ok {
+		return nil, false
+	}
+	return t.toProto(spEndpoint, health), true
+}
+
+// Healths returns the healths of all the called sps, the unhealthiest is the first.
+func (t *Tracker) Healths() []*gfspserver.GfSpSPHealth {
+	t.mux.RLock()
+	healths := make([]*gfspserver.GfSpSPHealth, 0, len(t.healths))
+	for spEndpoint, health := range t.healths {
+		healths = append(healths, t.toProto(spEndpoint, health))
+	}
+	t.mux.RUnlock()
+	sort.Slice(healths, func(i, j int) bool {
+		if healths[i].GetScore() != healths[j].GetScore() {
+			return healths[i].GetScore() < healths[j].GetScore()
+		}
+		return healths[i].GetSpEndpoint() < healths[j].GetSpEndpoint()
+	})
+	return healths
+}
+
+func (t *Tracker) isHealthy(health *spHealth) bool {
+	return health.callCount < t.minCallNumber || health.score() >= t.unhealthyScore
+}
+
+func (t *Tracker) toProto(spEndpoint string, health *spHealth) *gfspserver.GfSpSPHealth {
+	return &gfspserver.GfSpSPHealth{
+		SpEndpoint:              spEndpoint,
+		CallCount:               health.callCount,
+		FailureCount:            health.failureCount,
+		ErrorRate:               health.errorRate,
+		SlowRate:                health.slowRate,
+		AvgLatencyMs:            int64(health.latency * 1000),
+		LastFailureTimestampSec: health.lastFailureTime,
+		LastFailureError:        health.lastFailureError,
+		Score:                   health.score(),
+		Healthy:                 t.isHealthy(health),
+	}
+}
+
+func (h *spHealth) score() float64 {
+	return Score(h.errorRate, h.slowRate)
+}
@@ -0,0 +1,100 @@
+package gfsphealth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+)
+
+const (
+	testHealthyEndpoint   = "https://healthy.sp"
+	testUnhealthyEndpoint = "https://unhealthy.sp"
+	testSlowEndpoint      = "https://slow.sp"
+)
+
+func TestScore(t *testing.T) {
+	require.Equal(t, 1.0, Score(0, 0))
+	require.Equal(t, 0.5, Score(0.5, 0))
+	require.Equal(t, 0.5, Score(0, 0.5))
+	require.Equal(t, 0.0, Score(1, 0))
+	require.Equal(t, 0.0, Score(0.6, 0.6))
+}
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker(5, 0.5, 10*time.Second)
+	require.True(t, tracker.IsHealthy(testUnhealthyEndpoint))
+	require.Equal(t, 1.0, tracker.Score(testUnhealthyEndpoint))
+
+	for i := 0; i < 4; i++ {
+		tracker.Record(&gfspserver.GfSpSPCallRecord{SpEndpoint: testHealthyEndpoint, LatencyMs: 100})
+		tracker.Record(&gfspserver.GfSpSPCallRecord{SpEndpoint: testUnhealthyEndpoint, Failed: true,
+			Error: "timeout", TimestampSec: int64(i)})
+	}
+	// the sp is not regarded as unhealthy before it is called enough times
+	require.True(t, tracker.IsHealthy(testUnhealthyEndpoint))
+	tracker.Record(&gfspserver.GfSpSPCallRecord{SpEndpoint: testUnhealthyEndpoint, Failed: true,
+		Error: "refused", TimestampSec: 10})
+	require.False(t, tracker.IsHealthy(testUnhealthyEndpoint))
+	require.True(t, tracker.IsHealthy(testHealthyEndpoint))
+
+	health, ok := tracker.Health(testUnhealthyEndpoint)
+	require.True(t, ok)
+	require.Equal(t, uint64(5), health.GetCallCount())
+	require.Equal(t, uint64(5), health.GetFailureCount())
+	require.Equal(t, int64(10), health.GetLastFailureTimestampSec())
+	require.Equal(t, "refused", health.GetLastFailureError())
+
+	healths := tracker.Healths()
+	require.Len(t, healths, 2)
+	require.Equal(t, testUnhealthyEndpoint, healths[0].GetSpEndpoint())
+	require.Equal(t, testHealthyEndpoint, healths[1].GetSpEndpoint())
+	require.Equal(t, int64(100), healths[1].GetAvgLatencyMs())
+
+	// the sp recovers after enough successful calls
+	for i := 0; i < 10; i++ {
+		tracker.Record(&gfspserver.GfSpSPCallRecord{SpEndpoint: testUnhealthyEndpoint})
+	}
+	require.True(t, tracker.IsHealthy(testUnhealthyEndpoint))
+}
+
+func TestTracker_Latency(t *testing.T) {
+	tracker := NewTracker(5, 0.5, 10*time.Second)
+	// the latency of the large payloads does not lower the score if the calls are not slow
+	for i := 0; i < 10; i++ {
+		tracker.Record(&gfspserver.GfSpSPCallRecord{SpEndpoint: testHealthyEndpoint, LatencyMs: 5000})
+		tracker.Record(&gfspserver.GfSpSPCallRecord{SpEndpoint: testSlowEndpoint, LatencyMs: 12000})
+	}
+	require.Equal(t, 1.0, tracker.Score(testHealthyEndpoint))
+	require.True(t, tracker.IsHealthy(testHealthyEndpoint))
+	require.False(t, tracker.IsHealthy(testSlowEndpoint))
+
+	health, ok := tracker.Health(testSlowEndpoint)
+	require.True(t, ok)
+	require.Equal(t, 1.0, health.GetSlowRate())
+	require.Equal(t, 0.0, health.GetErrorRate())
+	require.Equal(t, int64(12000), health.GetAvgLatencyMs())
+}
+
+func TestCallRecorder(t *testing.T) {
+	recorder := NewCallRecorder()
+	recorder.RecordCall(testHealthyEndpoint, ReplicateCallType, time.Now(), nil)
+	recorder.RecordCall(testUnhealthyEndpoint, ReceiveCallType, time.Now(), errors.New("timeout"))
+
+	_, ok := recorder.Health(testUnhealthyEndpoint)
+	require.True(t, ok)
+	records := recorder.TakePending()
+	require.Len(t, records, 2)
+	require.True(t, records[1].GetFailed())
+	require.Equal(t, "timeout", records[1].GetError())
+	require.Empty(t, recorder.TakePending())
+
+	recorder.RecordCall(testHealthyEndpoint, RecoveryCallType, time.Now(), nil)
+	recorder.Restore(records)
+	records = recorder.TakePending()
+	require.Len(t, records, 3)
+	require.Equal(t, RecoveryCallType, records[2].GetCallType())
+}
@@ -28,6 +28,22 @@ func (p *GfSpPieceOp) ChallengePieceKey(objectID uint64, segmentIdx uint32, redu
 	return p.ECPieceKey(objectID, segmentIdx, uint32(redundancyIdx))
 }
 
+func (p *GfSpPieceOp) MultipartPieceKey(objectID uint64, partNumber uint32, uploadID string, chunkIdx uint32) string {
+	if uploadID == "" {
+		// the chunks staged before the upload id is introduced
+		return fmt.Sprintf("m%d_p%d_c%d", objectID, partNumber, chunkIdx)
+	}
+	return fmt.Sprintf("m%d_p%d_u%s_c%d", objectID, partNumber, uploadID, chunkIdx)
+}
+
+func (p *GfSpPieceOp) MultipartPieceKeyPrefix(objectID uint64) string {
+	return fmt.Sprintf("m%d_", objectID)
+}
+
+func (p *GfSpPieceOp) IsMultipartPieceKey(pieceKey string) bool {
+	return strings.HasPrefix(pieceKey, "m")
+}
+
 func (p *GfSpPieceOp) MaxSegmentPieceSize(payloadSize uint64, maxSegmentSize uint64) int64 {
 	if payloadSize > maxSegmentSize {
 		return int64(maxSegmentSize)
@@ -117,3 +133,17 @@ func (p *GfSpPieceOp) ParseChallengeIdx(challengeKey string) (uint32, int32, err
 
 	return 0, 0, fmt.Errorf("invalid challenge key: %s", challengeKey)
 }
+
+func (p *GfSpPieceOp) ParseObjectID(pieceKey string) (uint64, error) {
+	keyParts := strings.Split(pieceKey, "_")
+	// the multipart piece key with the upload id has four parts
+	if len(keyParts) != 2 && len(keyParts) != 3 && (len(keyParts) != 4 || !p.IsMultipartPieceKey(pieceKey)) {
+		return 0, fmt.Errorf("invalid piece key: %s", pieceKey)
+	}
+	// the segment piece key starts with "s", the ec piece key starts with "e" and the multipart piece key
+	// starts with "m"
+	if len(keyParts[0]) < 2 || (keyParts[0][0] != 's' && keyParts[0][0] != 'e' && keyParts[0][0] != 'm') {
+		return 0, fmt.Errorf("invalid piece key: %s", pieceKey)
+	}
+	return strconv.ParseUint(keyParts[0][1:], 10, 64)
+}
@@ -0,0 +1,230 @@
+package gfsptls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+// DefaultReloadInterval defines the default interval of checking whether the certificate files changed.
+const DefaultReloadInterval = 60 * time.Second
+
+var (
+	ErrNoPeerCertificate = errors.New("no peer certificate")
+	ErrInvalidCAFile     = errors.New("no valid certificate in ca file")
+)
+
+// CertReloader loads the CA, certificate and key from files and reloads them when the files are
+// changed, the new handshakes use the reloaded files without restarting the server or the client.
+type CertReloader struct {
+	caFile   string
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mux       sync.RWMutex
+	cert      *tls.Certificate
+	pool      *x509.CertPool
+	modTimes  []time.Time
+	lastCheck time.Time
+}
+
+// NewCertReloader returns the CertReloader and loads the files at once, the files are checked
+// again during handshakes if the interval elapsed since the last check.
+func NewCertReloader(caFile, certFile, keyFile string, interval time.Duration) (*CertReloader, error) {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	r := &CertReloader{
+		caFile:   caFile,
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+	}
+	modTimes, err := r.stat()
+	if err != nil {
+		return nil, err
+	}
+	if err = r.load(modTimes); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *CertReloader) stat() ([]time.Time, error) {
+	var modTimes []time.Time
+	for _, file := range []string{r.caFile, r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	return modTimes, nil
+}
+
+func (r *CertReloader) load(modTimes []time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	caPEM, err := os.ReadFile(r.caFile)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return ErrInvalidCAFile
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.cert = &cert
+	r.pool = pool
+	r.modTimes = modTimes
+	r.lastCheck = time.Now()
+	return nil
+}
+
+// maybeReload reloads the files if the interval elapsed and any file is changed, the previous
+// certificate is kept if the files fail to reload, e.g. the files are being rewritten.
+func (r *CertReloader) maybeReload() {
+	r.mux.Lock()
+	if time.Since(r.lastCheck) < r.interval {
+		r.mux.Unlock()
+		return
+	}
+	r.lastCheck = time.Now()
+	previous := r.modTimes
+	r.mux.Unlock()
+
+	modTimes, err := r.stat()
+	if err != nil {
+		log.Errorw("failed to stat tls files", "error", err)
+		return
+	}
+	changed := false
+	for i := range modTimes {
+		if !modTimes[i].Equal(previous[i]) {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return
+	}
+	if err = r.load(modTimes); err != nil {
+		log.Errorw("failed to reload tls files", "error", err)
+		return
+	}
+	log.Infow("succeed to reload tls files", "cert_file", r.certFile)
+}
+
+func (r *CertReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.maybeReload()
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.cert, r.pool
+}
+
+// ServerCredentials returns the grpc server credentials that require and verify the client
+// certificate, if allowedIdentities is not empty, the client identity must be one of them.
+func (r *CertReloader) ServerCredentials(allowedIdentities []string) credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    pool,
+				VerifyPeerCertificate: func(_ [][]byte, chains [][]*x509.Certificate) error {
+					if len(chains) == 0 || len(chains[0]) == 0 {
+						return ErrNoPeerCertificate
+					}
+					return verifyIdentity(chains[0][0], allowedIdentities)
+				},
+			}, nil
+		},
+	})
+}
+
+// ClientCredentials returns the grpc client credentials that present the client certificate and
+// verify the server certificate, if identity is not empty, the server identity must match it.
+func (r *CertReloader) ClientCredentials(identity string) credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		},
+		// the server certificate is verified by VerifyPeerCertificate against the reloaded CA,
+		// and the server name is replaced by the configured identity.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			_, pool := r.current()
+			return verifyServer(rawCerts, pool, identity)
+		},
+	})
+}
+
+func verifyServer(rawCerts [][]byte, pool *x509.CertPool, identity string) error {
+	if len(rawCerts) == 0 {
+		return ErrNoPeerCertificate
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}); err != nil {
+		return err
+	}
+	if identity == "" {
+		return nil
+	}
+	return verifyIdentity(certs[0], []string{identity})
+}
+
+// verifyIdentity checks the certificate matches one of the identities by DNS SAN, URI SAN or
+// subject common name, any verified certificate is accepted if the identities are empty.
+func verifyIdentity(cert *x509.Certificate, identities []string) error {
+	if len(identities) == 0 {
+		return nil
+	}
+	for _, identity := range identities {
+		if cert.Subject.CommonName == identity {
+			return nil
+		}
+		for _, name := range cert.DNSNames {
+			if name == identity {
+				return nil
+			}
+		}
+		for _, uri := range cert.URIs {
+			if uri.String() == identity {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("peer identity is not allowed, common name: %s", cert.Subject.CommonName)
+}
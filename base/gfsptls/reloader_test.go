@@ -0,0 +1,156 @@
+package gfsptls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// writeCert issues the certificate of the name by the ca and writes the ca, cert and key files to dir.
+func (ca *testCA) writeCert(t *testing.T, dir, name string) (string, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(caFile, ca.pem, 0600))
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return caFile, certFile, keyFile
+}
+
+func newTestReloader(t *testing.T, ca *testCA, name string) (*CertReloader, error) {
+	caFile, certFile, keyFile := ca.writeCert(t, t.TempDir(), name)
+	return NewCertReloader(caFile, certFile, keyFile, DefaultReloadInterval)
+}
+
+func handshake(server, client credentials.TransportCredentials) (credentials.AuthInfo, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+	errCh := make(chan error, 1)
+	go func() {
+		serverConn, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			errCh <- acceptErr
+			return
+		}
+		defer serverConn.Close()
+		_, _, handshakeErr := server.ServerHandshake(serverConn)
+		errCh <- handshakeErr
+	}()
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+	defer clientConn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, authInfo, clientErr := client.ClientHandshake(ctx, "localhost", clientConn)
+	serverErr := <-errCh
+	if clientErr != nil {
+		return nil, clientErr
+	}
+	return authInfo, serverErr
+}
+
+func TestCertReloaderMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverReloader, err := newTestReloader(t, ca, "manager")
+	require.NoError(t, err)
+	clientReloader, err := newTestReloader(t, ca, "gateway")
+	require.NoError(t, err)
+
+	_, err = handshake(serverReloader.ServerCredentials([]string{"gateway"}), clientReloader.ClientCredentials("manager"))
+	require.NoError(t, err)
+
+	// the server identity mismatches the expected one
+	_, err = handshake(serverReloader.ServerCredentials(nil), clientReloader.ClientCredentials("signer"))
+	require.Error(t, err)
+
+	// the client identity is not allowed by the server
+	_, err = handshake(serverReloader.ServerCredentials([]string{"uploader"}), clientReloader.ClientCredentials(""))
+	require.Error(t, err)
+
+	// the client certificate is issued by the untrusted ca
+	otherReloader, err := newTestReloader(t, newTestCA(t), "gateway")
+	require.NoError(t, err)
+	_, err = handshake(serverReloader.ServerCredentials(nil), otherReloader.ClientCredentials(""))
+	require.Error(t, err)
+}
+
+func TestCertReloaderReload(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	caFile, certFile, keyFile := ca.writeCert(t, dir, "manager")
+	reloader, err := NewCertReloader(caFile, certFile, keyFile, time.Millisecond)
+	require.NoError(t, err)
+	cert, _ := reloader.current()
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, "manager", leaf.Subject.CommonName)
+
+	ca.writeCert(t, dir, "uploader")
+	future := time.Now().Add(time.Minute)
+	for _, file := range []string{caFile, certFile, keyFile} {
+		require.NoError(t, os.Chtimes(file, future, future))
+	}
+	time.Sleep(2 * time.Millisecond)
+	cert, _ = reloader.current()
+	leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, "uploader", leaf.Subject.CommonName)
+}
@@ -0,0 +1,157 @@
+package gfsptqueue
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+// persistentTask is the task that can be encoded to persist in db, all the gfsp tasks are protobuf
+// messages and implement it.
+type persistentTask interface {
+	coretask.Task
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+// newPersistentTask returns the empty task by the task type to decode the persisted task.
+func newPersistentTask(taskType coretask.TType) (persistentTask, error) {
+	switch taskType {
+	case coretask.TypeTaskReplicatePiece:
+		return &gfsptask.GfSpReplicatePieceTask{}, nil
+	case coretask.TypeTaskSealObject:
+		return &gfsptask.GfSpSealObjectTask{}, nil
+	case coretask.TypeTaskReceivePiece:
+		return &gfsptask.GfSpReceivePieceTask{}, nil
+	case coretask.TypeTaskGCObject:
+		return &gfsptask.GfSpGCObjectTask{}, nil
+	case coretask.TypeTaskGCZombiePiece:
+		return &gfsptask.GfSpGCZombiePieceTask{}, nil
+	case coretask.TypeTaskGCMeta:
+		return &gfsptask.GfSpGCMetaTask{}, nil
+	case coretask.TypeTaskRecoverPiece:
+		return &gfsptask.GfSpRecoverPieceTask{}, nil
+	case coretask.TypeTaskMigrateGVG:
+		return &gfsptask.GfSpMigrateGVGTask{}, nil
+	case coretask.TypeTaskScrubGVG:
+		return &gfsptask.GfSpScrubGVGTask{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported persistent task type: %s", coretask.TaskTypeName(taskType))
+	}
+}
+
+// NewGfSpDBTQueueWithLimitFunc returns the new func of the task queue that persists the tasks in
+// db, the tasks in the queue are loaded from db when the queue is created, so they survive the
+// restart of SP.
+func NewGfSpDBTQueueWithLimitFunc(db corespdb.TaskQueueDB) taskqueue.NewTQueueOnStrategyWithLimit {
+	return func(name string, cap int) taskqueue.TQueueOnStrategyWithLimit {
+		return NewGfSpDBTQueueWithLimit(db, name, cap)
+	}
+}
+
+// NewGfSpDBTQueueWithLimit returns the task queue that persists the tasks in db. The tasks are
+// still scheduled in memory, db is only written through on adding, dispatching and deleting tasks.
+// The dispatched task is leased in db until it is popped by key, so the running tasks are loaded
+// again after the restart of SP.
+func NewGfSpDBTQueueWithLimit(db corespdb.TaskQueueDB, name string, cap int) taskqueue.TQueueOnStrategyWithLimit {
+	queue := &GfSpTQueueWithLimit{
+		name:   name,
+		cap:    cap,
+		tasks:  make(map[coretask.TKey]coretask.Task),
+		leased: make(map[coretask.TKey]coretask.Task),
+	}
+	loadTasks(db, queue)
+	queue.addFunc = func(task coretask.Task) {
+		meta, err := encodeTask(name, task)
+		if err != nil {
+			log.Errorw("failed to encode task", "queue", name, "task_key", task.Key().String(), "error", err)
+			return
+		}
+		if err = db.UpdateQueueTask(meta); err != nil {
+			log.Errorw("failed to persist task", "queue", name, "task_key", task.Key().String(), "error", err)
+		}
+	}
+	queue.leaseFunc = func(task coretask.Task) {
+		if err := db.LeaseQueueTask(name, task.Key().String(), time.Now().Unix()); err != nil {
+			log.Errorw("failed to lease persisted task", "queue", name, "task_key", task.Key().String(), "error", err)
+		}
+	}
+	queue.deleteFunc = func(task coretask.Task) {
+		if err := db.DeleteQueueTask(name, task.Key().String()); err != nil {
+			log.Errorw("failed to delete persisted task", "queue", name, "task_key", task.Key().String(), "error", err)
+		}
+	}
+	return queue
+}
+
+// loadTasks loads the persisted tasks to the queue, the tasks that can not be decoded are dropped.
+func loadTasks(db corespdb.TaskQueueDB, queue *GfSpTQueueWithLimit) {
+	metas, err := db.ListQueueTasks(queue.name)
+	if err != nil {
+		log.Errorw("failed to load persisted tasks", "queue", queue.name, "error", err)
+		return
+	}
+	for _, meta := range metas {
+		task, decodeErr := decodeTask(meta)
+		if decodeErr != nil {
+			log.Errorw("failed to decode persisted task", "queue", queue.name, "task_key", meta.TaskKey, "error", decodeErr)
+			if err = db.DeleteQueueTask(queue.name, meta.TaskKey); err != nil {
+				log.Errorw("failed to delete persisted task", "queue", queue.name, "task_key", meta.TaskKey, "error", err)
+			}
+			continue
+		}
+		if len(queue.tasks) >= queue.cap {
+			log.Warnw("queue exceed when loading persisted tasks", "queue", queue.name, "cap", queue.cap)
+			break
+		}
+		if meta.LeaseTimestampSecond != 0 {
+			// the task was dispatched before the restart, the dispatcher increases the retry and
+			// refreshes the update time after popping, which are not persisted.
+			task.IncRetry()
+			task.SetUpdateTime(meta.LeaseTimestampSecond)
+		}
+		queue.add(task)
+	}
+	log.Infow("succeed to load persisted tasks", "queue", queue.name, "task_number", len(queue.tasks))
+}
+
+func encodeTask(queueName string, task coretask.Task) (*corespdb.TaskQueueMeta, error) {
+	pTask, ok := task.(persistentTask)
+	if !ok {
+		return nil, fmt.Errorf("unsupported persistent task type: %s", coretask.TaskTypeName(task.Type()))
+	}
+	data, err := pTask.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &corespdb.TaskQueueMeta{
+		QueueName:             queueName,
+		TaskKey:               task.Key().String(),
+		TaskType:              int32(task.Type()),
+		Priority:              uint32(task.GetPriority()),
+		Retry:                 task.GetRetry(),
+		MaxRetry:              task.GetMaxRetry(),
+		Logs:                  task.GetLogs(),
+		TaskData:              data,
+		CreateTimestampSecond: task.GetCreateTime(),
+	}, nil
+}
+
+func decodeTask(meta *corespdb.TaskQueueMeta) (coretask.Task, error) {
+	task, err := newPersistentTask(coretask.TType(meta.TaskType))
+	if err != nil {
+		return nil, err
+	}
+	if err = task.Unmarshal(meta.TaskData); err != nil {
+		return nil, err
+	}
+	if task.Key().String() != meta.TaskKey {
+		return nil, fmt.Errorf("mismatch task key: %s", task.Key().String())
+	}
+	return task, nil
+}
@@ -0,0 +1,206 @@
+package gfsptqueue
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsplimit"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	virtualgrouptypes "github.com/bnb-chain/greenfield/x/virtualgroup/types"
+)
+
+var maxTestLimit = &gfsplimit.GfSpLimit{
+	Memory:              math.MaxInt64,
+	Tasks:               math.MaxInt32,
+	TasksHighPriority:   math.MaxInt32,
+	TasksMediumPriority: math.MaxInt32,
+	TasksLowPriority:    math.MaxInt32,
+	Fd:                  math.MaxInt32,
+	Conns:               math.MaxInt32,
+	ConnsInbound:        math.MaxInt32,
+	ConnsOutbound:       math.MaxInt32,
+}
+
+// fakeTaskQueueDB keeps the persisted tasks in memory, it outlives the queues to simulate the restart.
+type fakeTaskQueueDB struct {
+	mux   sync.Mutex
+	metas map[string]map[string]*spdb.TaskQueueMeta
+}
+
+func newFakeTaskQueueDB() *fakeTaskQueueDB {
+	return &fakeTaskQueueDB{metas: make(map[string]map[string]*spdb.TaskQueueMeta)}
+}
+
+func (db *fakeTaskQueueDB) UpdateQueueTask(meta *spdb.TaskQueueMeta) error {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	if db.metas[meta.QueueName] == nil {
+		db.metas[meta.QueueName] = make(map[string]*spdb.TaskQueueMeta)
+	}
+	persisted := *meta
+	persisted.LeaseTimestampSecond = 0
+	if old, ok := db.metas[meta.QueueName][meta.TaskKey]; ok {
+		persisted.CreateTimestampSecond = old.CreateTimestampSecond
+	}
+	db.metas[meta.QueueName][meta.TaskKey] = &persisted
+	return nil
+}
+
+func (db *fakeTaskQueueDB) LeaseQueueTask(queueName string, taskKey string, leaseTimestampSecond int64) error {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	if meta, ok := db.metas[queueName][taskKey]; ok {
+		meta.LeaseTimestampSecond = leaseTimestampSecond
+	}
+	return nil
+}
+
+func (db *fakeTaskQueueDB) DeleteQueueTask(queueName string, taskKey string) error {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	delete(db.metas[queueName], taskKey)
+	return nil
+}
+
+func (db *fakeTaskQueueDB) ListQueueTasks(queueName string) ([]*spdb.TaskQueueMeta, error) {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	metas := make([]*spdb.TaskQueueMeta, 0, len(db.metas[queueName]))
+	for _, meta := range db.metas[queueName] {
+		persisted := *meta
+		metas = append(metas, &persisted)
+	}
+	sort.Slice(metas, func(i, j int) bool { return metas[i].CreateTimestampSecond < metas[j].CreateTimestampSecond })
+	return metas, nil
+}
+
+func TestDBTQueueWithLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := spdb.NewMockSPDB(ctrl)
+
+	task := &gfsptask.GfSpGCMetaTask{}
+	task.InitGCMetaTask(coretask.UnSchedulingPriority, 1, 100, 10)
+	task.SetMaxRetry(3)
+	task.SetRetry(2)
+	task.AppendLog("test-log")
+	persisted, err := encodeTask("test-queue", task)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), persisted.Retry)
+	require.True(t, strings.Contains(persisted.Logs, "test-log"))
+
+	// the persisted task is loaded when the queue is created
+	db.EXPECT().ListQueueTasks("test-queue").Return([]*spdb.TaskQueueMeta{persisted}, nil)
+	queue := NewGfSpDBTQueueWithLimit(db, "test-queue", 10)
+	require.Equal(t, 1, queue.Len())
+	require.True(t, queue.Has(task.Key()))
+
+	// the popped task is leased instead of deleted from db
+	db.EXPECT().LeaseQueueTask("test-queue", task.Key().String(), gomock.Any()).Return(nil)
+	loaded := queue.PopByLimit(maxTestLimit)
+	require.NotNil(t, loaded)
+	require.Equal(t, 0, queue.Len())
+	require.Equal(t, int64(2), loaded.GetRetry())
+	require.Equal(t, int64(3), loaded.GetMaxRetry())
+	require.Equal(t, persisted.Logs, loaded.GetLogs())
+
+	// the pushed back task is persisted again
+	db.EXPECT().UpdateQueueTask(gomock.Any()).DoAndReturn(func(meta *spdb.TaskQueueMeta) error {
+		require.Equal(t, persisted.TaskKey, meta.TaskKey)
+		require.Equal(t, int32(coretask.TypeTaskGCMeta), meta.TaskType)
+		return nil
+	})
+	require.NoError(t, queue.Push(loaded))
+	require.Equal(t, 1, queue.Len())
+
+	// the task popped by key is deleted from db
+	db.EXPECT().DeleteQueueTask("test-queue", task.Key().String()).Return(nil)
+	require.NotNil(t, queue.PopByKey(task.Key()))
+	require.Equal(t, 0, queue.Len())
+}
+
+func TestDBTQueueWithLimit_SurviveRestart(t *testing.T) {
+	objectInfo := &storagetypes.ObjectInfo{BucketName: "bucket", ObjectName: "object", Id: sdkmath.NewUint(1)}
+	params := &storagetypes.Params{}
+	cases := []struct {
+		name    string
+		newTask func() coretask.Task
+	}{
+		{
+			name: "recovery",
+			newTask: func() coretask.Task {
+				task := &gfsptask.GfSpRecoverPieceTask{}
+				task.InitRecoverPieceTask(objectInfo, params, coretask.DefaultSmallerPriority, 1, 2, 1024, 10, 3)
+				return task
+			},
+		},
+		{
+			name: "receive",
+			newTask: func() coretask.Task {
+				task := &gfsptask.GfSpReceivePieceTask{}
+				task.InitReceivePieceTask(1, objectInfo, params, coretask.DefaultSmallerPriority, 1, 2, 1024)
+				return task
+			},
+		},
+		{
+			name: "migrate",
+			newTask: func() coretask.Task {
+				task := &gfsptask.GfSpMigrateGVGTask{}
+				task.InitMigrateGVGTask(coretask.UnSchedulingPriority, 1,
+					&virtualgrouptypes.GlobalVirtualGroup{Id: 1}, -1, nil, 10, 3)
+				return task
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db := newFakeTaskQueueDB()
+			queue := NewGfSpDBTQueueWithLimit(db, c.name, 10)
+			task := c.newTask()
+			require.NoError(t, queue.Push(task))
+
+			// the reserved task is pushed back after dispatching
+			reserved := queue.PopByLimit(maxTestLimit)
+			require.NotNil(t, reserved)
+			require.NoError(t, queue.Push(reserved))
+			require.Equal(t, 1, queue.Len())
+
+			// the dispatched task is running when the manager restarts
+			dispatched := queue.PopByLimit(maxTestLimit)
+			require.NotNil(t, dispatched)
+			require.Equal(t, 0, queue.Len())
+			metas, err := db.ListQueueTasks(c.name)
+			require.NoError(t, err)
+			require.Len(t, metas, 1)
+			leaseTime := metas[0].LeaseTimestampSecond
+			require.NotZero(t, leaseTime)
+
+			restarted := NewGfSpDBTQueueWithLimit(db, c.name, 10)
+			require.Equal(t, 1, restarted.Len())
+			require.True(t, restarted.Has(task.Key()))
+			restarted.ScanTask(func(loaded coretask.Task) {
+				// the retry increased by dispatching is counted
+				require.Equal(t, task.GetRetry()+1, loaded.GetRetry())
+				require.Equal(t, leaseTime, loaded.GetUpdateTime())
+			})
+
+			// the task is deleted after it is finished
+			dispatched = restarted.PopByLimit(maxTestLimit)
+			require.NotNil(t, dispatched)
+			require.Nil(t, restarted.PopByKey(task.Key()))
+			metas, err = db.ListQueueTasks(c.name)
+			require.NoError(t, err)
+			require.Empty(t, metas)
+			require.Equal(t, 0, NewGfSpDBTQueueWithLimit(db, c.name, 10).Len())
+		})
+	}
+}
@@ -25,6 +25,15 @@ type GfSpTQueueWithLimit struct {
 
 	gcFunc     func(task2 coretask.Task) bool
 	filterFunc func(task2 coretask.Task) bool
+
+	// addFunc and deleteFunc are called after the task is added to or deleted from the queue,
+	// leaseFunc is called after the task is popped to dispatch, they are used to persist the tasks
+	// of the queue. The leased tasks are kept until they are popped by key, pushed again or retired,
+	// so the persisted tasks are not deleted before they are finished.
+	addFunc    func(task2 coretask.Task)
+	deleteFunc func(task2 coretask.Task)
+	leaseFunc  func(task2 coretask.Task)
+	leased     map[coretask.TKey]coretask.Task
 }
 
 func NewGfSpTQueueWithLimit(name string, cap int) taskqueue.TQueueOnStrategyWithLimit {
@@ -81,7 +90,7 @@ func (t *GfSpTQueueWithLimit) PopByLimit(limit corercmgr.Limit) coretask.Task {
 	}()
 	task := t.topByLimit(limit)
 	if task != nil {
-		t.delete(task)
+		t.lease(task)
 	}
 	return task
 }
@@ -94,6 +103,10 @@ func (t *GfSpTQueueWithLimit) PopByKey(key coretask.TKey) coretask.Task {
 		t.mux.Unlock()
 		metrics.QueueTime.WithLabelValues(t.name + "-pop_by_key").Observe(time.Since(startTime).Seconds())
 	}()
+	if leased, ok := t.leased[key]; ok {
+		// the task is finished or reported after dispatching, which is not in the queue.
+		t.unlease(leased)
+	}
 	if !t.has(key) {
 		return nil
 	}
@@ -116,6 +129,8 @@ func (t *GfSpTQueueWithLimit) Push(task coretask.Task) error {
 	if t.has(task.Key()) {
 		return ErrTaskRepeated
 	}
+	// the leased task is pushed back, it is persisted again by adding
+	delete(t.leased, task.Key())
 	if t.exceed() {
 		if t.gcFunc == nil {
 			log.Warnw("queue exceed", "queue", t.name, "cap", t.cap, "len", len(t.tasks))
@@ -153,26 +168,60 @@ func (t *GfSpTQueueWithLimit) add(task coretask.Task) {
 		return
 	}
 	t.tasks[task.Key()] = task
+	if t.addFunc != nil {
+		t.addFunc(task)
+	}
 }
 
 func (t *GfSpTQueueWithLimit) delete(task coretask.Task) {
 	if task == nil || !t.has(task.Key()) {
 		return
 	}
-	defer func() {
-		metrics.QueueSizeGauge.WithLabelValues(t.name).Set(float64(len(t.tasks)))
-		metrics.QueueCapGauge.WithLabelValues(t.name).Set(float64(t.cap))
-		metrics.TaskInQueueTime.WithLabelValues(t.name).Observe(
-			time.Since(time.Unix(task.GetCreateTime(), 0)).Seconds())
-	}()
+	defer t.observeDelete(task)
+	t.remove(task)
+}
+
+// lease deletes the popped task from the queue, the persisted task is leased instead of deleted
+// until the task is finished.
+func (t *GfSpTQueueWithLimit) lease(task coretask.Task) {
+	if t.leaseFunc == nil {
+		t.delete(task)
+		return
+	}
+	defer t.observeDelete(task)
+	delete(t.tasks, task.Key())
+	t.leased[task.Key()] = task
+	t.leaseFunc(task)
+}
+
+// unlease deletes the leased task and its persisted task.
+func (t *GfSpTQueueWithLimit) unlease(task coretask.Task) {
+	delete(t.leased, task.Key())
+	if t.deleteFunc != nil {
+		t.deleteFunc(task)
+	}
+}
+
+func (t *GfSpTQueueWithLimit) observeDelete(task coretask.Task) {
+	metrics.QueueSizeGauge.WithLabelValues(t.name).Set(float64(len(t.tasks)))
+	metrics.QueueCapGauge.WithLabelValues(t.name).Set(float64(t.cap))
+	metrics.TaskInQueueTime.WithLabelValues(t.name).Observe(
+		time.Since(time.Unix(task.GetCreateTime(), 0)).Seconds())
+}
+
+// remove deletes the task from the queue without recording metrics.
+func (t *GfSpTQueueWithLimit) remove(task coretask.Task) {
 	delete(t.tasks, task.Key())
+	if t.deleteFunc != nil {
+		t.deleteFunc(task)
+	}
 }
 
 func (t *GfSpTQueueWithLimit) has(key coretask.TKey) bool {
 	task, ok := t.tasks[key]
 	if ok && t.gcFunc != nil {
 		if t.gcFunc(task) {
-			delete(t.tasks, task.Key())
+			t.remove(task)
 			return false
 		}
 	}
@@ -187,9 +236,15 @@ func (t *GfSpTQueueWithLimit) topByLimit(limit corercmgr.Limit) coretask.Task {
 	var gcTasks []coretask.Task
 	defer func() {
 		for _, task := range gcTasks {
-			delete(t.tasks, task.Key())
+			t.remove(task)
 		}
 	}()
+	// the leased tasks which are never reported, e.g. the executor is gone, are retired as well
+	for _, task := range t.leased {
+		if t.gcFunc != nil && t.gcFunc(task) {
+			t.unlease(task)
+		}
+	}
 
 	for _, task := range t.tasks {
 		if t.gcFunc != nil {
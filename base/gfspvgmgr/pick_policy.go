@@ -0,0 +1,267 @@
+package gfspvgmgr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/vgmgr"
+)
+
+const (
+	// FreeStorageSizePolicyName picks the vgf and the gvg by the weight of the free staking storage size.
+	FreeStorageSizePolicyName = "free-storage"
+	// EvenBucketsPolicyName picks the vgf which has the fewest buckets to spread the buckets evenly.
+	EvenBucketsPolicyName = "even-buckets"
+	// ReplicateHealthPolicyName prefers the gvg whose secondary sps have the higher health scores.
+	ReplicateHealthPolicyName = "replicate-health"
+	// DedicatedFamilyPolicyName keeps the buckets of the specified accounts in their dedicated vgfs.
+	DedicatedFamilyPolicyName = "dedicated-family"
+	// BucketCapPolicyName caps the number of the buckets in a vgf.
+	BucketCapPolicyName = "bucket-cap"
+)
+
+// PickPolicyConfig defines the config of the built-in virtual group pick policies.
+type PickPolicyConfig struct {
+	// Name is the name of the policy, empty means the free-storage policy.
+	Name string
+	// DedicatedFamilies maps the lower case account address to its dedicated vgf, is used by the dedicated-family policy.
+	DedicatedFamilies map[string]uint32
+	// MaxBucketNumber is the max number of the buckets in a vgf, is used by the bucket-cap policy.
+	MaxBucketNumber uint64
+}
+
+// NewVirtualGroupPickPolicy returns the built-in virtual group pick policy by the config.
+func NewVirtualGroupPickPolicy(cfg *PickPolicyConfig) (vgmgr.VirtualGroupPickPolicy, error) {
+	switch cfg.Name {
+	case "", FreeStorageSizePolicyName:
+		return &freeStorageSizePolicy{}, nil
+	case EvenBucketsPolicyName:
+		return &evenBucketsPolicy{}, nil
+	case ReplicateHealthPolicyName:
+		return &replicateHealthPolicy{}, nil
+	case DedicatedFamilyPolicyName:
+		if len(cfg.DedicatedFamilies) == 0 {
+			return nil, fmt.Errorf("no dedicated family is configured for %s policy", DedicatedFamilyPolicyName)
+		}
+		policy := &dedicatedFamilyPolicy{
+			accountToFamily: make(map[string]uint32, len(cfg.DedicatedFamilies)),
+			dedicated:       make(map[uint32]bool, len(cfg.DedicatedFamilies)),
+		}
+		for account, vgfID := range cfg.DedicatedFamilies {
+			policy.accountToFamily[strings.ToLower(account)] = vgfID
+			policy.dedicated[vgfID] = true
+		}
+		return policy, nil
+	case BucketCapPolicyName:
+		if cfg.MaxBucketNumber == 0 {
+			return nil, fmt.Errorf("max bucket number is not configured for %s policy", BucketCapPolicyName)
+		}
+		return &bucketCapPolicy{maxBucketNumber: cfg.MaxBucketNumber}, nil
+	default:
+		return nil, fmt.Errorf("unknown virtual group pick policy: %s", cfg.Name)
+	}
+}
+
+// ParseDedicatedFamilies parses the "account:vgf_id" entries to the dedicated families of the accounts.
+func ParseDedicatedFamilies(entries []string) (map[string]uint32, error) {
+	families := make(map[string]uint32, len(entries))
+	for _, entry := range entries {
+		fields := strings.Split(entry, ":")
+		if len(fields) != 2 || fields[0] == "" {
+			return nil, fmt.Errorf("invalid dedicated family entry: %s", entry)
+		}
+		vgfID, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil || vgfID == 0 {
+			return nil, fmt.Errorf("invalid dedicated family entry: %s", entry)
+		}
+		families[strings.ToLower(fields[0])] = uint32(vgfID)
+	}
+	return families, nil
+}
+
+var _ vgmgr.VirtualGroupPickPolicy = &freeStorageSizePolicy{}
+
+// freeStorageSizePolicy is the default policy, the more free storage size, the greater the probability of being picked.
+type freeStorageSizePolicy struct{}
+
+func (p *freeStorageSizePolicy) Name() string {
+	return FreeStorageSizePolicyName
+}
+
+func (p *freeStorageSizePolicy) PickVirtualGroupFamily(_ *vgmgr.PickVirtualGroupFamilyRequest,
+	candidates []*vgmgr.VirtualGroupFamilyMeta, _ vgmgr.VirtualGroupStats) (*vgmgr.VirtualGroupFamilyMeta, error) {
+	picker := &FreeStorageSizeWeightPicker{freeStorageSizeWeightMap: make(map[uint32]float64)}
+	families := make(map[uint32]*vgmgr.VirtualGroupFamilyMeta, len(candidates))
+	for _, vgf := range candidates {
+		picker.addVirtualGroupFamily(vgf)
+		families[vgf.ID] = vgf
+	}
+	familyID, err := picker.pickIndex()
+	if err != nil {
+		return nil, err
+	}
+	return families[familyID], nil
+}
+
+func (p *freeStorageSizePolicy) PickGlobalVirtualGroup(_ *vgmgr.VirtualGroupFamilyMeta,
+	candidates []*vgmgr.GlobalVirtualGroupMeta, _ vgmgr.VirtualGroupStats) (*vgmgr.GlobalVirtualGroupMeta, error) {
+	picker := &FreeStorageSizeWeightPicker{freeStorageSizeWeightMap: make(map[uint32]float64)}
+	gvgs := make(map[uint32]*vgmgr.GlobalVirtualGroupMeta, len(candidates))
+	for _, gvg := range candidates {
+		picker.addGlobalVirtualGroup(gvg)
+		gvgs[gvg.ID] = gvg
+	}
+	gvgID, err := picker.pickIndex()
+	if err != nil {
+		return nil, err
+	}
+	return gvgs[gvgID], nil
+}
+
+var _ vgmgr.VirtualGroupPickPolicy = &evenBucketsPolicy{}
+
+// evenBucketsPolicy picks the vgf which has the fewest buckets, the vgf which has more free storage size is
+// picked if the numbers of the buckets are equal.
+type evenBucketsPolicy struct {
+	freeStorageSizePolicy
+}
+
+func (p *evenBucketsPolicy) Name() string {
+	return EvenBucketsPolicyName
+}
+
+func (p *evenBucketsPolicy) PickVirtualGroupFamily(_ *vgmgr.PickVirtualGroupFamilyRequest,
+	candidates []*vgmgr.VirtualGroupFamilyMeta, stats vgmgr.VirtualGroupStats) (*vgmgr.VirtualGroupFamilyMeta, error) {
+	var (
+		picked      *vgmgr.VirtualGroupFamilyMeta
+		pickedCount uint64
+		pickedFree  float64
+	)
+	for _, vgf := range candidates {
+		free, ok := familyFreeStorageSizeWeight(vgf)
+		if !ok {
+			continue
+		}
+		count := stats.FamilyBucketCount(vgf.ID)
+		if picked == nil || count < pickedCount || (count == pickedCount && free > pickedFree) {
+			picked, pickedCount, pickedFree = vgf, count, free
+		}
+	}
+	if picked == nil {
+		return nil, fmt.Errorf("no family has enough free storage size")
+	}
+	return picked, nil
+}
+
+var _ vgmgr.VirtualGroupPickPolicy = &replicateHealthPolicy{}
+
+// replicateHealthPolicy weights the gvg by its free storage size and the lowest health score of its secondary sps,
+// the secondary sps which have not been replicated to are regarded as healthy.
+type replicateHealthPolicy struct {
+	freeStorageSizePolicy
+}
+
+func (p *replicateHealthPolicy) Name() string {
+	return ReplicateHealthPolicyName
+}
+
+func (p *replicateHealthPolicy) PickGlobalVirtualGroup(_ *vgmgr.VirtualGroupFamilyMeta,
+	candidates []*vgmgr.GlobalVirtualGroupMeta, stats vgmgr.VirtualGroupStats) (*vgmgr.GlobalVirtualGroupMeta, error) {
+	picker := &FreeStorageSizeWeightPicker{freeStorageSizeWeightMap: make(map[uint32]float64)}
+	gvgs := make(map[uint32]*vgmgr.GlobalVirtualGroupMeta, len(candidates))
+	for _, gvg := range candidates {
+		free, ok := gvgFreeStorageSizeWeight(gvg)
+		if !ok {
+			continue
+		}
+		minScore := 1.0
+		for _, spID := range gvg.SecondarySPIDs {
+			if score, observed := stats.SPHealthScore(spID); observed && score < minScore {
+				minScore = score
+			}
+		}
+		weight := free * minScore
+		if weight <= 0 {
+			continue
+		}
+		picker.freeStorageSizeWeightMap[gvg.ID] = weight
+		gvgs[gvg.ID] = gvg
+	}
+	gvgID, err := picker.pickIndex()
+	if err != nil {
+		return nil, err
+	}
+	return gvgs[gvgID], nil
+}
+
+var _ vgmgr.VirtualGroupPickPolicy = &dedicatedFamilyPolicy{}
+
+// dedicatedFamilyPolicy picks the dedicated vgf for the buckets of the specified accounts, the buckets of the other
+// accounts are not put into the dedicated vgfs.
+type dedicatedFamilyPolicy struct {
+	freeStorageSizePolicy
+	accountToFamily map[string]uint32
+	dedicated       map[uint32]bool
+}
+
+func (p *dedicatedFamilyPolicy) Name() string {
+	return DedicatedFamilyPolicyName
+}
+
+func (p *dedicatedFamilyPolicy) PickVirtualGroupFamily(req *vgmgr.PickVirtualGroupFamilyRequest,
+	candidates []*vgmgr.VirtualGroupFamilyMeta, stats vgmgr.VirtualGroupStats) (*vgmgr.VirtualGroupFamilyMeta, error) {
+	if vgfID, ok := p.DedicatedFamily(req.Owner); ok {
+		for _, vgf := range candidates {
+			if vgf.ID == vgfID {
+				return vgf, nil
+			}
+		}
+		return nil, fmt.Errorf("dedicated family %d of %s has no enough free storage size", vgfID, req.Owner)
+	}
+	shared := make([]*vgmgr.VirtualGroupFamilyMeta, 0, len(candidates))
+	for _, vgf := range candidates {
+		if !p.dedicated[vgf.ID] {
+			shared = append(shared, vgf)
+		}
+	}
+	return p.freeStorageSizePolicy.PickVirtualGroupFamily(req, shared, stats)
+}
+
+// DedicatedFamily returns the dedicated vgf of the account.
+func (p *dedicatedFamilyPolicy) DedicatedFamily(account string) (uint32, bool) {
+	vgfID, ok := p.accountToFamily[strings.ToLower(account)]
+	return vgfID, ok
+}
+
+var _ vgmgr.VirtualGroupPickPolicy = &bucketCapPolicy{}
+
+// bucketCapPolicy picks the vgf whose number of the buckets does not reach the cap by the free storage size.
+type bucketCapPolicy struct {
+	freeStorageSizePolicy
+	maxBucketNumber uint64
+}
+
+func (p *bucketCapPolicy) Name() string {
+	return BucketCapPolicyName
+}
+
+func (p *bucketCapPolicy) PickVirtualGroupFamily(req *vgmgr.PickVirtualGroupFamilyRequest,
+	candidates []*vgmgr.VirtualGroupFamilyMeta, stats vgmgr.VirtualGroupStats) (*vgmgr.VirtualGroupFamilyMeta, error) {
+	uncapped := make([]*vgmgr.VirtualGroupFamilyMeta, 0, len(candidates))
+	for _, vgf := range candidates {
+		if stats.FamilyBucketCount(vgf.ID) < p.maxBucketNumber {
+			uncapped = append(uncapped, vgf)
+		}
+	}
+	return p.freeStorageSizePolicy.PickVirtualGroupFamily(req, uncapped, stats)
+}
+
+// nullVirtualGroupStats is used if no stats is provided, no bucket and no replicate is observed.
+type nullVirtualGroupStats struct{}
+
+func (nullVirtualGroupStats) FamilyBucketCount(uint32) uint64 { return 0 }
+
+func (nullVirtualGroupStats) SPHealthScore(uint32) (float64, bool) {
+	return 0, false
+}
@@ -0,0 +1,196 @@
+package gfspvgmgr
+
+import (
+	"context"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/consensus"
+	"github.com/bnb-chain/greenfield-storage-provider/core/vgmgr"
+	sptypes "github.com/bnb-chain/greenfield/x/sp/types"
+	virtualgrouptypes "github.com/bnb-chain/greenfield/x/virtualgroup/types"
+)
+
+const (
+	testSelfOperatorAddress = "0x0000000000000000000000000000000000000001"
+	testPickTimes           = 100
+)
+
+// fakeConsensus serves the sps, the vgfs and the gvgs of the primary sp 1.
+type fakeConsensus struct {
+	consensus.NullConsensus
+	families []*virtualgrouptypes.GlobalVirtualGroupFamily
+	gvgs     map[uint32]*virtualgrouptypes.GlobalVirtualGroup
+}
+
+func (c *fakeConsensus) ListSPs(context.Context) ([]*sptypes.StorageProvider, error) {
+	sps := []*sptypes.StorageProvider{{Id: 1, OperatorAddress: testSelfOperatorAddress, Status: sptypes.STATUS_IN_SERVICE}}
+	for id := uint32(2); id <= 5; id++ {
+		sps = append(sps, &sptypes.StorageProvider{Id: id, Status: sptypes.STATUS_IN_SERVICE})
+	}
+	return sps, nil
+}
+
+func (c *fakeConsensus) QueryVirtualGroupParams(context.Context) (*virtualgrouptypes.Params, error) {
+	return &virtualgrouptypes.Params{GvgStakingPerBytes: sdkmath.NewInt(1)}, nil
+}
+
+func (c *fakeConsensus) ListVirtualGroupFamilies(context.Context, uint32) ([]*virtualgrouptypes.GlobalVirtualGroupFamily, error) {
+	return c.families, nil
+}
+
+func (c *fakeConsensus) QueryGlobalVirtualGroup(_ context.Context, gvgID uint32) (*virtualgrouptypes.GlobalVirtualGroup, error) {
+	return c.gvgs[gvgID], nil
+}
+
+// newFakeConsensus returns the vgf 1 which has the gvg 11 and 12, and the vgf 2 which has the gvg 21, every gvg
+// stakes 100 bytes and stores the size of the storedSizes.
+func newFakeConsensus(storedSizes map[uint32]uint64) *fakeConsensus {
+	c := &fakeConsensus{
+		families: []*virtualgrouptypes.GlobalVirtualGroupFamily{
+			{Id: 1, PrimarySpId: 1, GlobalVirtualGroupIds: []uint32{11, 12}},
+			{Id: 2, PrimarySpId: 1, GlobalVirtualGroupIds: []uint32{21}},
+		},
+		gvgs: make(map[uint32]*virtualgrouptypes.GlobalVirtualGroup),
+	}
+	secondarySPIDs := map[uint32][]uint32{11: {2, 3}, 12: {4, 5}, 21: {2, 4}}
+	for gvgID, spIDs := range secondarySPIDs {
+		c.gvgs[gvgID] = &virtualgrouptypes.GlobalVirtualGroup{
+			Id:             gvgID,
+			PrimarySpId:    1,
+			SecondarySpIds: spIDs,
+			StoredSize:     storedSizes[gvgID],
+			TotalDeposit:   sdkmath.NewInt(100),
+		}
+	}
+	return c
+}
+
+type fakeVirtualGroupStats struct {
+	bucketNumbers map[uint32]uint64
+	scores        map[uint32]float64
+}
+
+func (s *fakeVirtualGroupStats) FamilyBucketCount(vgfID uint32) uint64 {
+	return s.bucketNumbers[vgfID]
+}
+
+func (s *fakeVirtualGroupStats) SPHealthScore(spID uint32) (float64, bool) {
+	score, ok := s.scores[spID]
+	return score, ok
+}
+
+func newTestVirtualGroupManager(t *testing.T, chainClient consensus.Consensus, cfg *PickPolicyConfig,
+	stats vgmgr.VirtualGroupStats) *virtualGroupManager {
+	policy, err := NewVirtualGroupPickPolicy(cfg)
+	require.NoError(t, err)
+	vgm := &virtualGroupManager{
+		selfOperatorAddress: testSelfOperatorAddress,
+		chainClient:         chainClient,
+		pickPolicy:          policy,
+		stats:               stats,
+	}
+	vgm.refreshMeta()
+	require.NotNil(t, vgm.vgfManager)
+	return vgm
+}
+
+func pickFamilies(t *testing.T, vgm *virtualGroupManager, owner string) map[uint32]int {
+	picked := make(map[uint32]int)
+	for i := 0; i < testPickTimes; i++ {
+		vgf, err := vgm.PickVirtualGroupFamily(&vgmgr.PickVirtualGroupFamilyRequest{BucketName: "bucket", Owner: owner})
+		require.NoError(t, err)
+		picked[vgf.ID]++
+	}
+	return picked
+}
+
+func TestNewVirtualGroupPickPolicy(t *testing.T) {
+	for _, name := range []string{"", FreeStorageSizePolicyName, EvenBucketsPolicyName, ReplicateHealthPolicyName} {
+		_, err := NewVirtualGroupPickPolicy(&PickPolicyConfig{Name: name})
+		require.NoError(t, err)
+	}
+	_, err := NewVirtualGroupPickPolicy(&PickPolicyConfig{Name: DedicatedFamilyPolicyName})
+	require.Error(t, err)
+	_, err = NewVirtualGroupPickPolicy(&PickPolicyConfig{Name: BucketCapPolicyName})
+	require.Error(t, err)
+	_, err = NewVirtualGroupPickPolicy(&PickPolicyConfig{Name: "unknown"})
+	require.Error(t, err)
+
+	families, err := ParseDedicatedFamilies([]string{"0xABC:1", "0xdef:2"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint32{"0xabc": 1, "0xdef": 2}, families)
+	for _, entry := range []string{"0xabc", "0xabc:0", ":1", "0xabc:x"} {
+		_, err = ParseDedicatedFamilies([]string{entry})
+		require.Error(t, err, entry)
+	}
+}
+
+func TestFreeStorageSizePolicy(t *testing.T) {
+	// the vgf 1 is full, the gvg 11 is full
+	chainClient := newFakeConsensus(map[uint32]uint64{11: 100, 12: 100})
+	vgm := newTestVirtualGroupManager(t, chainClient, &PickPolicyConfig{}, &fakeVirtualGroupStats{})
+	require.Equal(t, map[uint32]int{2: testPickTimes}, pickFamilies(t, vgm, ""))
+
+	chainClient = newFakeConsensus(map[uint32]uint64{11: 100})
+	vgm = newTestVirtualGroupManager(t, chainClient, &PickPolicyConfig{}, &fakeVirtualGroupStats{})
+	for i := 0; i < testPickTimes; i++ {
+		gvg, err := vgm.PickGlobalVirtualGroup(1)
+		require.NoError(t, err)
+		require.Equal(t, uint32(12), gvg.ID)
+	}
+	_, err := vgm.PickGlobalVirtualGroup(3)
+	require.ErrorIs(t, err, ErrStaledMetadata)
+}
+
+func TestEvenBucketsPolicy(t *testing.T) {
+	stats := &fakeVirtualGroupStats{bucketNumbers: map[uint32]uint64{1: 5, 2: 1}}
+	vgm := newTestVirtualGroupManager(t, newFakeConsensus(nil), &PickPolicyConfig{Name: EvenBucketsPolicyName}, stats)
+	require.Equal(t, map[uint32]int{2: testPickTimes}, pickFamilies(t, vgm, ""))
+
+	// the vgf which has more free storage size is picked if the numbers of the buckets are equal
+	stats.bucketNumbers[2] = 5
+	vgm = newTestVirtualGroupManager(t, newFakeConsensus(map[uint32]uint64{21: 50}),
+		&PickPolicyConfig{Name: EvenBucketsPolicyName}, stats)
+	require.Equal(t, map[uint32]int{1: testPickTimes}, pickFamilies(t, vgm, ""))
+}
+
+func TestReplicateHealthPolicy(t *testing.T) {
+	// the sp 3 always fails to replicate
+	stats := &fakeVirtualGroupStats{scores: map[uint32]float64{2: 1, 3: 0, 4: 0.9}}
+	vgm := newTestVirtualGroupManager(t, newFakeConsensus(nil), &PickPolicyConfig{Name: ReplicateHealthPolicyName}, stats)
+	for i := 0; i < testPickTimes; i++ {
+		gvg, err := vgm.PickGlobalVirtualGroup(1)
+		require.NoError(t, err)
+		require.Equal(t, uint32(12), gvg.ID)
+	}
+
+	stats.scores[4] = 0
+	_, err := vgm.PickGlobalVirtualGroup(1)
+	require.ErrorIs(t, err, ErrFailedPickGVG)
+}
+
+func TestDedicatedFamilyPolicy(t *testing.T) {
+	cfg := &PickPolicyConfig{Name: DedicatedFamilyPolicyName, DedicatedFamilies: map[string]uint32{"0xabc": 1}}
+	vgm := newTestVirtualGroupManager(t, newFakeConsensus(nil), cfg, &fakeVirtualGroupStats{})
+	require.Equal(t, map[uint32]int{1: testPickTimes}, pickFamilies(t, vgm, "0xABC"))
+	require.Equal(t, map[uint32]int{2: testPickTimes}, pickFamilies(t, vgm, "0xdef"))
+
+	// the dedicated family is full
+	vgm = newTestVirtualGroupManager(t, newFakeConsensus(map[uint32]uint64{11: 100, 12: 100}), cfg, &fakeVirtualGroupStats{})
+	_, err := vgm.PickVirtualGroupFamily(&vgmgr.PickVirtualGroupFamilyRequest{Owner: "0xabc"})
+	require.ErrorIs(t, err, ErrFailedPickVGF)
+}
+
+func TestBucketCapPolicy(t *testing.T) {
+	stats := &fakeVirtualGroupStats{bucketNumbers: map[uint32]uint64{1: 10, 2: 9}}
+	cfg := &PickPolicyConfig{Name: BucketCapPolicyName, MaxBucketNumber: 10}
+	vgm := newTestVirtualGroupManager(t, newFakeConsensus(nil), cfg, stats)
+	require.Equal(t, map[uint32]int{2: testPickTimes}, pickFamilies(t, vgm, ""))
+
+	stats.bucketNumbers[2] = 10
+	_, err := vgm.PickVirtualGroupFamily(&vgmgr.PickVirtualGroupFamilyRequest{})
+	require.ErrorIs(t, err, ErrFailedPickVGF)
+}
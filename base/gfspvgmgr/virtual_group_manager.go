@@ -49,18 +49,34 @@ type FreeStorageSizeWeightPicker struct {
 	freeStorageSizeWeightMap map[uint32]float64
 }
 
-func (picker *FreeStorageSizeWeightPicker) addVirtualGroupFamily(vgf *vgmgr.VirtualGroupFamilyMeta) {
+// familyFreeStorageSizeWeight returns the free ratio of the vgf staking storage size, ok is false if the vgf
+// has no enough free storage size.
+func familyFreeStorageSizeWeight(vgf *vgmgr.VirtualGroupFamilyMeta) (float64, bool) {
 	if float64(vgf.FamilyUsedStorageSize) >= MaxStorageUsageRatio*float64(vgf.FamilyStakingStorageSize) || vgf.FamilyStakingStorageSize == 0 {
-		return
+		return 0, false
 	}
-	picker.freeStorageSizeWeightMap[vgf.ID] = float64(vgf.FamilyStakingStorageSize-vgf.FamilyUsedStorageSize) / float64(vgf.FamilyStakingStorageSize)
+	return float64(vgf.FamilyStakingStorageSize-vgf.FamilyUsedStorageSize) / float64(vgf.FamilyStakingStorageSize), true
 }
 
-func (picker *FreeStorageSizeWeightPicker) addGlobalVirtualGroup(gvg *vgmgr.GlobalVirtualGroupMeta) {
+// gvgFreeStorageSizeWeight returns the free ratio of the gvg staking storage size, ok is false if the gvg
+// has no enough free storage size.
+func gvgFreeStorageSizeWeight(gvg *vgmgr.GlobalVirtualGroupMeta) (float64, bool) {
 	if float64(gvg.UsedStorageSize) >= MaxStorageUsageRatio*float64(gvg.StakingStorageSize) {
-		return
+		return 0, false
+	}
+	return float64(gvg.StakingStorageSize-gvg.UsedStorageSize) / float64(gvg.StakingStorageSize), true
+}
+
+func (picker *FreeStorageSizeWeightPicker) addVirtualGroupFamily(vgf *vgmgr.VirtualGroupFamilyMeta) {
+	if weight, ok := familyFreeStorageSizeWeight(vgf); ok {
+		picker.freeStorageSizeWeightMap[vgf.ID] = weight
+	}
+}
+
+func (picker *FreeStorageSizeWeightPicker) addGlobalVirtualGroup(gvg *vgmgr.GlobalVirtualGroupMeta) {
+	if weight, ok := gvgFreeStorageSizeWeight(gvg); ok {
+		picker.freeStorageSizeWeightMap[gvg.ID] = weight
 	}
-	picker.freeStorageSizeWeightMap[gvg.ID] = float64(gvg.StakingStorageSize-gvg.UsedStorageSize) / float64(gvg.StakingStorageSize)
 }
 
 func (picker *FreeStorageSizeWeightPicker) pickIndex() (uint32, error) {
@@ -82,42 +98,40 @@ func (picker *FreeStorageSizeWeightPicker) pickIndex() (uint32, error) {
 	return 0, fmt.Errorf("failed to pick weighted random index")
 }
 
-func (vgfm *virtualGroupFamilyManager) pickVirtualGroupFamily() (*vgmgr.VirtualGroupFamilyMeta, error) {
-	var (
-		picker   FreeStorageSizeWeightPicker
-		familyID uint32
-		err      error
-	)
-	picker.freeStorageSizeWeightMap = make(map[uint32]float64)
+func (vgfm *virtualGroupFamilyManager) pickVirtualGroupFamily(req *vgmgr.PickVirtualGroupFamilyRequest,
+	policy vgmgr.VirtualGroupPickPolicy, stats vgmgr.VirtualGroupStats) (*vgmgr.VirtualGroupFamilyMeta, error) {
+	candidates := make([]*vgmgr.VirtualGroupFamilyMeta, 0, len(vgfm.vgfIDToVgf))
 	for _, f := range vgfm.vgfIDToVgf {
-		picker.addVirtualGroupFamily(f)
+		if _, ok := familyFreeStorageSizeWeight(f); ok {
+			candidates = append(candidates, f)
+		}
 	}
-	if familyID, err = picker.pickIndex(); err != nil {
-		log.Errorw("failed to pick vgf", "error", err)
+	vgf, err := policy.PickVirtualGroupFamily(req, candidates, stats)
+	if err != nil || vgf == nil {
+		log.Errorw("failed to pick vgf", "policy", policy.Name(), "error", err)
 		return nil, ErrFailedPickVGF
 	}
-	return vgfm.vgfIDToVgf[familyID], nil
+	return vgf, nil
 }
 
-func (vgfm *virtualGroupFamilyManager) pickGlobalVirtualGroup(vgfID uint32) (*vgmgr.GlobalVirtualGroupMeta, error) {
-	var (
-		picker               FreeStorageSizeWeightPicker
-		globalVirtualGroupID uint32
-		err                  error
-	)
-	picker.freeStorageSizeWeightMap = make(map[uint32]float64)
-	if _, existed := vgfm.vgfIDToVgf[vgfID]; !existed {
+func (vgfm *virtualGroupFamilyManager) pickGlobalVirtualGroup(vgfID uint32, policy vgmgr.VirtualGroupPickPolicy,
+	stats vgmgr.VirtualGroupStats) (*vgmgr.GlobalVirtualGroupMeta, error) {
+	vgf, existed := vgfm.vgfIDToVgf[vgfID]
+	if !existed {
 		return nil, ErrStaledMetadata
 	}
-	for _, g := range vgfm.vgfIDToVgf[vgfID].GVGMap {
-		picker.addGlobalVirtualGroup(g)
+	candidates := make([]*vgmgr.GlobalVirtualGroupMeta, 0, len(vgf.GVGMap))
+	for _, g := range vgf.GVGMap {
+		if _, ok := gvgFreeStorageSizeWeight(g); ok {
+			candidates = append(candidates, g)
+		}
 	}
-
-	if globalVirtualGroupID, err = picker.pickIndex(); err != nil {
-		log.Errorw("failed to pick gvg", "vgf_id", vgfID, "error", err)
+	gvg, err := policy.PickGlobalVirtualGroup(vgf, candidates, stats)
+	if err != nil || gvg == nil {
+		log.Errorw("failed to pick gvg", "vgf_id", vgfID, "policy", policy.Name(), "error", err)
 		return nil, ErrFailedPickGVG
 	}
-	return vgfm.vgfIDToVgf[vgfID].GVGMap[globalVirtualGroupID], nil
+	return gvg, nil
 }
 
 func (vgfm *virtualGroupFamilyManager) pickGlobalVirtualGroupForBucketMigrate(filter vgmgr.GVGPickFilter) (*vgmgr.GlobalVirtualGroupMeta, error) {
@@ -208,13 +222,25 @@ type virtualGroupManager struct {
 	spManager           *spManager // is used to generate a new gvg
 	vgParams            *virtualgrouptypes.Params
 	vgfManager          *virtualGroupFamilyManager
+	pickPolicy          vgmgr.VirtualGroupPickPolicy
+	stats               vgmgr.VirtualGroupStats
 }
 
-// NewVirtualGroupManager returns a virtual group manager interface.
-func NewVirtualGroupManager(selfOperatorAddress string, chainClient consensus.Consensus) (vgmgr.VirtualGroupManager, error) {
+// NewVirtualGroupManager returns a virtual group manager interface, the free-storage policy is used if the
+// policy is nil.
+func NewVirtualGroupManager(selfOperatorAddress string, chainClient consensus.Consensus,
+	policy vgmgr.VirtualGroupPickPolicy, stats vgmgr.VirtualGroupStats) (vgmgr.VirtualGroupManager, error) {
+	if policy == nil {
+		policy = &freeStorageSizePolicy{}
+	}
+	if stats == nil {
+		stats = nullVirtualGroupStats{}
+	}
 	vgm := &virtualGroupManager{
 		selfOperatorAddress: selfOperatorAddress,
 		chainClient:         chainClient,
+		pickPolicy:          policy,
+		stats:               stats,
 	}
 	vgm.refreshMeta()
 	go func() {
@@ -336,10 +362,10 @@ func (vgm *virtualGroupManager) refreshMetaByChain() {
 
 // PickVirtualGroupFamily pick a virtual group family(If failed to pick,
 // new VGF will be automatically created on the chain) in get create bucket approval workflow.
-func (vgm *virtualGroupManager) PickVirtualGroupFamily() (*vgmgr.VirtualGroupFamilyMeta, error) {
+func (vgm *virtualGroupManager) PickVirtualGroupFamily(req *vgmgr.PickVirtualGroupFamilyRequest) (*vgmgr.VirtualGroupFamilyMeta, error) {
 	vgm.mutex.RLock()
 	defer vgm.mutex.RUnlock()
-	return vgm.vgfManager.pickVirtualGroupFamily()
+	return vgm.vgfManager.pickVirtualGroupFamily(req, vgm.pickPolicy, vgm.stats)
 }
 
 // PickGlobalVirtualGroup picks a global virtual group(If failed to pick,
@@ -347,7 +373,7 @@ func (vgm *virtualGroupManager) PickVirtualGroupFamily() (*vgmgr.VirtualGroupFam
 func (vgm *virtualGroupManager) PickGlobalVirtualGroup(vgfID uint32) (*vgmgr.GlobalVirtualGroupMeta, error) {
 	vgm.mutex.RLock()
 	defer vgm.mutex.RUnlock()
-	return vgm.vgfManager.pickGlobalVirtualGroup(vgfID)
+	return vgm.vgfManager.pickGlobalVirtualGroup(vgfID, vgm.pickPolicy, vgm.stats)
 }
 
 // PickGlobalVirtualGroupForBucketMigrate picks a global virtual group(If failed to pick,
@@ -363,7 +389,7 @@ func (vgm *virtualGroupManager) PickGlobalVirtualGroupForBucketMigrate(filter vg
 func (vgm *virtualGroupManager) PickMigrateDestGlobalVirtualGroup(vgfID uint32) (*vgmgr.GlobalVirtualGroupMeta, error) {
 	vgm.mutex.RLock()
 	defer vgm.mutex.RUnlock()
-	return vgm.vgfManager.pickGlobalVirtualGroup(vgfID)
+	return vgm.vgfManager.pickGlobalVirtualGroup(vgfID, vgm.pickPolicy, vgm.stats)
 }
 
 // ForceRefreshMeta is used to query metadata service and refresh the virtual group manager meta.
@@ -128,6 +128,21 @@ func (m *GfSpDownloadObjectTask) EstimateLimit() corercmgr.Limit {
 	return l
 }
 
+// EstimateStreamLimit returns the resource limit of the streaming download, only one
+// segment piece is held in memory at a time, so the memory scales with the segment size.
+func (m *GfSpDownloadObjectTask) EstimateStreamLimit() corercmgr.Limit {
+	memory := m.GetSize()
+	if params := m.GetStorageParams(); params != nil {
+		segmentSize := int64(params.VersionedParams.GetMaxSegmentSize())
+		if segmentSize > 0 && segmentSize < memory {
+			memory = segmentSize
+		}
+	}
+	l := &gfsplimit.GfSpLimit{Memory: memory}
+	l.Add(LimitEstimateByPriority(m.GetPriority()))
+	return l
+}
+
 func (m *GfSpDownloadObjectTask) GetUserAddress() string {
 	return m.GetTask().GetUserAddress()
 }
@@ -171,6 +171,17 @@ func (m *GfSpGCObjectTask) SetLastDeletedObjectId(object uint64) {
 	m.LastDeletedObjectId = object
 }
 
+func (m *GfSpGCZombiePieceTask) InitGCZombiePieceTask(priority coretask.TPriority, startAfter string, limit uint64, timeout int64) {
+	m.Reset()
+	m.Task = &GfSpTask{}
+	m.StartAfter = startAfter
+	m.Limit = limit
+	m.SetPriority(priority)
+	m.SetCreateTime(time.Now().Unix())
+	m.SetUpdateTime(time.Now().Unix())
+	m.SetTimeout(timeout)
+}
+
 func (m *GfSpGCZombiePieceTask) Key() coretask.TKey {
 	return GfSpGCZombiePieceTaskKey(m.GetCreateTime())
 }
@@ -180,9 +191,11 @@ func (m *GfSpGCZombiePieceTask) Type() coretask.TType {
 }
 
 func (m *GfSpGCZombiePieceTask) Info() string {
-	return fmt.Sprintf("key[%s], type[%s], priority[%d], limit[%s], %s",
-		m.Key(), coretask.TaskTypeName(m.Type()), m.GetPriority(),
-		m.EstimateLimit().String(), m.GetTask().Info())
+	return fmt.Sprintf(
+		"key[%s], type[%s], priority[%d], limit[%s], start_after[%s], piece_limit[%d], last_piece_key[%s], last_object_id[%d], delete_count[%d], finished[%t], %s",
+		m.Key(), coretask.TaskTypeName(m.Type()), m.GetPriority(), m.EstimateLimit().String(),
+		m.GetStartAfter(), m.GetLimit(), m.GetLastPieceKey(), m.GetObjectId(), m.GetDeleteCount(),
+		m.GetFinished(), m.GetTask().Info())
 }
 
 func (m *GfSpGCZombiePieceTask) GetAddress() string {
@@ -298,6 +311,25 @@ func (m *GfSpGCZombiePieceTask) SetGCZombiePieceStatus(object uint64, delete uin
 	m.DeleteCount = delete
 }
 
+func (m *GfSpGCZombiePieceTask) SetLastPieceKey(key string) {
+	m.LastPieceKey = key
+}
+
+func (m *GfSpGCZombiePieceTask) SetFinished(finished bool) {
+	m.Finished = finished
+}
+
+func (m *GfSpGCMetaTask) InitGCMetaTask(priority coretask.TPriority, currentIdx uint64, lastObjectID uint64, timeout int64) {
+	m.Reset()
+	m.Task = &GfSpTask{}
+	m.CurrentIdx = currentIdx
+	m.LastObjectId = lastObjectID
+	m.SetPriority(priority)
+	m.SetCreateTime(time.Now().Unix())
+	m.SetUpdateTime(time.Now().Unix())
+	m.SetTimeout(timeout)
+}
+
 func (m *GfSpGCMetaTask) Key() coretask.TKey {
 	return GfSpGfSpGCMetaTaskKey(m.GetCreateTime())
 }
@@ -307,9 +339,10 @@ func (m *GfSpGCMetaTask) Type() coretask.TType {
 }
 
 func (m *GfSpGCMetaTask) Info() string {
-	return fmt.Sprintf("key[%s], type[%s], priority[%d], limit[%s], %s",
-		m.Key(), coretask.TaskTypeName(m.Type()), m.GetPriority(),
-		m.EstimateLimit().String(), m.GetTask().Info())
+	return fmt.Sprintf(
+		"key[%s], type[%s], priority[%d], limit[%s], current_idx[%d], last_object_id[%d], delete_count[%d], finished[%t], %s",
+		m.Key(), coretask.TaskTypeName(m.Type()), m.GetPriority(), m.EstimateLimit().String(),
+		m.GetCurrentIdx(), m.GetLastObjectId(), m.GetDeleteCount(), m.GetFinished(), m.GetTask().Info())
 }
 
 func (m *GfSpGCMetaTask) GetAddress() string {
@@ -424,3 +457,11 @@ func (m *GfSpGCMetaTask) SetGCMetaStatus(current uint64, delete uint64) {
 	m.CurrentIdx = current
 	m.DeleteCount = delete
 }
+
+func (m *GfSpGCMetaTask) SetLastObjectId(objectID uint64) {
+	m.LastObjectId = objectID
+}
+
+func (m *GfSpGCMetaTask) SetFinished(finished bool) {
+	m.Finished = finished
+}
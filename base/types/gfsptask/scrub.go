@@ -0,0 +1,164 @@
+package gfsptask
+
+import (
+	"fmt"
+	"time"
+
+	corercmgr "github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+)
+
+var _ coretask.ScrubGVGTask = &GfSpScrubGVGTask{}
+
+func (m *GfSpScrubGVGTask) InitScrubGVGTask(priority coretask.TPriority, gvgID uint32, redundancyIdx int32,
+	lastScrubbedObjectID uint64, timeout int64) {
+	m.Reset()
+	m.Task = &GfSpTask{}
+	m.GvgId = gvgID
+	m.RedundancyIdx = redundancyIdx
+	m.LastScrubbedObjectId = lastScrubbedObjectID
+	m.SetPriority(priority)
+	m.SetCreateTime(time.Now().Unix())
+	m.SetUpdateTime(time.Now().Unix())
+	m.SetTimeout(timeout)
+}
+
+func (m *GfSpScrubGVGTask) Key() coretask.TKey {
+	return GfSpScrubGVGTaskKey(m.GetGvgId(), m.GetCreateTime())
+}
+
+func (m *GfSpScrubGVGTask) Type() coretask.TType {
+	return coretask.TypeTaskScrubGVG
+}
+
+func (m *GfSpScrubGVGTask) Info() string {
+	return fmt.Sprintf(
+		"key[%s], type[%s], priority[%d], limit[%s], gvg_id[%d], redundancy_idx[%d], last_scrubbed_object_id[%d], scrubbed_object_count[%d], scrubbed_piece_count[%d], corrupted_piece_count[%d], missing_piece_count[%d], finished[%t], %s",
+		m.Key(), coretask.TaskTypeName(m.Type()), m.GetPriority(), m.EstimateLimit().String(),
+		m.GetGvgId(), m.GetRedundancyIdx(), m.GetLastScrubbedObjectId(), m.GetScrubbedObjectCount(),
+		m.GetScrubbedPieceCount(), m.GetCorruptedPieceCount(), m.GetMissingPieceCount(),
+		m.GetFinished(), m.GetTask().Info())
+}
+
+func (m *GfSpScrubGVGTask) GetAddress() string {
+	return m.GetTask().GetAddress()
+}
+
+func (m *GfSpScrubGVGTask) SetAddress(address string) {
+	m.GetTask().SetAddress(address)
+}
+
+func (m *GfSpScrubGVGTask) GetCreateTime() int64 {
+	return m.GetTask().GetCreateTime()
+}
+
+func (m *GfSpScrubGVGTask) SetCreateTime(time int64) {
+	m.GetTask().SetCreateTime(time)
+}
+
+func (m *GfSpScrubGVGTask) GetUpdateTime() int64 {
+	return m.GetTask().GetUpdateTime()
+}
+
+func (m *GfSpScrubGVGTask) SetUpdateTime(time int64) {
+	m.GetTask().SetUpdateTime(time)
+}
+
+func (m *GfSpScrubGVGTask) GetTimeout() int64 {
+	return m.GetTask().GetTimeout()
+}
+
+func (m *GfSpScrubGVGTask) SetTimeout(time int64) {
+	m.GetTask().SetTimeout(time)
+}
+
+func (m *GfSpScrubGVGTask) ExceedTimeout() bool {
+	return m.GetTask().ExceedTimeout()
+}
+
+func (m *GfSpScrubGVGTask) GetRetry() int64 {
+	return m.GetTask().GetRetry()
+}
+
+func (m *GfSpScrubGVGTask) IncRetry() {
+	m.GetTask().IncRetry()
+}
+
+func (m *GfSpScrubGVGTask) SetRetry(retry int) {
+	m.GetTask().SetRetry(retry)
+}
+
+func (m *GfSpScrubGVGTask) GetMaxRetry() int64 {
+	return m.GetTask().GetMaxRetry()
+}
+
+func (m *GfSpScrubGVGTask) SetMaxRetry(limit int64) {
+	m.GetTask().SetMaxRetry(limit)
+}
+
+func (m *GfSpScrubGVGTask) ExceedRetry() bool {
+	return m.GetTask().ExceedRetry()
+}
+
+func (m *GfSpScrubGVGTask) Expired() bool {
+	return m.GetTask().Expired()
+}
+
+func (m *GfSpScrubGVGTask) GetPriority() coretask.TPriority {
+	return m.GetTask().GetPriority()
+}
+
+func (m *GfSpScrubGVGTask) SetPriority(priority coretask.TPriority) {
+	m.GetTask().SetPriority(priority)
+}
+
+func (m *GfSpScrubGVGTask) EstimateLimit() corercmgr.Limit {
+	return LimitEstimateByPriority(m.GetPriority())
+}
+
+func (m *GfSpScrubGVGTask) GetUserAddress() string {
+	return m.GetTask().GetUserAddress()
+}
+
+func (m *GfSpScrubGVGTask) SetUserAddress(address string) {
+	m.GetTask().SetUserAddress(address)
+}
+
+func (m *GfSpScrubGVGTask) SetLogs(logs string) {
+	m.GetTask().SetLogs(logs)
+}
+
+func (m *GfSpScrubGVGTask) GetLogs() string {
+	return m.GetTask().GetLogs()
+}
+
+func (m *GfSpScrubGVGTask) AppendLog(log string) {
+	m.GetTask().AppendLog(log)
+}
+
+func (m *GfSpScrubGVGTask) Error() error {
+	return m.GetTask().Error()
+}
+
+func (m *GfSpScrubGVGTask) SetError(err error) {
+	m.GetTask().SetError(err)
+}
+
+func (m *GfSpScrubGVGTask) SetLastScrubbedObjectId(objectID uint64) {
+	m.LastScrubbedObjectId = objectID
+}
+
+func (m *GfSpScrubGVGTask) SetFinished(finished bool) {
+	m.Finished = finished
+}
+
+func (m *GfSpScrubGVGTask) GetScrubStatus() (uint64, uint64, uint64, uint64) {
+	return m.GetScrubbedObjectCount(), m.GetScrubbedPieceCount(), m.GetCorruptedPieceCount(), m.GetMissingPieceCount()
+}
+
+func (m *GfSpScrubGVGTask) SetScrubStatus(object, piece, corrupted, missing uint64) {
+	m.ScrubbedObjectCount = object
+	m.ScrubbedPieceCount = piece
+	m.CorruptedPieceCount = corrupted
+	m.MissingPieceCount = missing
+}
@@ -27,6 +27,7 @@ const (
 	KeyPrefixGfSpGfSpGCMetaTask             = "GCMeta"
 	KeyPrefixGfSpMigrateGVGTask             = "MigrateGVG"
 	KeyPrefixGfSpMigratePieceTask           = "MigratePiece"
+	KeyPrefixGfSpScrubGVGTask               = "ScrubGVG"
 )
 
 func GfSpCreateBucketApprovalTaskKey(bucket string, account string, visibility int32) task.TKey {
@@ -123,6 +124,10 @@ func GfSpMigratePieceTaskKey(object, id string, redundancyIdx uint32, ecIdx int3
 		fmt.Sprint(redundancyIdx), "redundancyIndex:", fmt.Sprint(ecIdx)))
 }
 
+func GfSpScrubGVGTaskKey(gvgID uint32, time int64) task.TKey {
+	return task.TKey(KeyPrefixGfSpScrubGVGTask + CombineKey("gvgID"+fmt.Sprint(gvgID), "time"+fmt.Sprint(time)))
+}
+
 func CombineKey(field ...string) string {
 	key := ""
 	for _, f := range field {
@@ -11,6 +11,9 @@ fi
 
 buf generate
 
+# the sqlite driver of sp db and bs db is built on mattn/go-sqlite3 which requires cgo and a c compiler
+export CGO_ENABLED=1
+
 go build -ldflags "\
   -X 'main.Version=${Version}' \
   -X 'main.CommitID=${CommitID}' \
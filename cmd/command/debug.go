@@ -88,7 +88,10 @@ func createBucketApproval(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 
 	msg := &storagetypes.MsgCreateBucket{
 		BucketName:        DebugCommandPrefix + util.GetRandomBucketName(),
@@ -114,7 +117,10 @@ func createObjectApproval(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 
 	msg := &storagetypes.MsgCreateObject{
 		BucketName:        DebugCommandPrefix + util.GetRandomBucketName(),
@@ -142,7 +148,10 @@ func replicatePieceApprovalAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 
 	objectInfo := &storagetypes.ObjectInfo{
 		Id:         sdk.NewUint(uint64(util.RandInt64(0, 100000))),
@@ -180,7 +189,10 @@ func putObjectAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 	filePath := ctx.String(fileFlag.Name)
 	data, err := os.ReadFile(filePath)
 	if err != nil {
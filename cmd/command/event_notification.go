@@ -0,0 +1,62 @@
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+)
+
+var deadLetterBucketNameFlag = &cli.StringFlag{
+	Name:     "bucket",
+	Usage:    "The bucket name",
+	Required: true,
+}
+
+var deadLetterLimitFlag = &cli.IntFlag{
+	Name:  "limit",
+	Usage: "The max number of the listed dead letters",
+	Value: 50,
+}
+
+var ListEventDeadLetterCmd = &cli.Command{
+	Action: listEventDeadLetterAction,
+	Name:   "event.deadletter",
+	Usage:  "List the object event notifications which failed to be delivered",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		deadLetterBucketNameFlag,
+		deadLetterLimitFlag,
+	},
+	Category: "EVENT COMMANDS",
+	Description: `The event.deadletter command lists the latest object event notifications of the bucket which are
+moved to the dead letters after the max delivery attempts, including the payload and the last delivery error.`,
+}
+
+func listEventDeadLetterAction(ctx *cli.Context) error {
+	limit := ctx.Int(deadLetterLimitFlag.Name)
+	if limit <= 0 {
+		return fmt.Errorf("invalid limit: %d", limit)
+	}
+	cfg, err := utils.MakeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := utils.MakeSPDB(cfg)
+	if err != nil {
+		return err
+	}
+	deliveries, err := db.ListDeadLetterEventDeliveries(ctx.String(deadLetterBucketNameFlag.Name), limit)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range deliveries {
+		fmt.Printf("id: %d, subscription: %s, event: %s, created: %s, attempts: %d\n", delivery.DeliveryID,
+			delivery.SubscriptionID, delivery.EventType,
+			time.Unix(delivery.CreateTimestampSecond, 0).UTC().Format(time.RFC3339), delivery.Attempts)
+		fmt.Printf("  last error: %s\n  payload: %s\n", delivery.LastError, delivery.Payload)
+	}
+	return nil
+}
@@ -31,7 +31,10 @@ func spExit(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 	operatorAddress := ctx.String(spOperatorAddressFlag.Name)
 	// TODO: add more verification for cli args
 	if operatorAddress != cfg.SpAccount.SpOperatorAddress {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/urfave/cli/v2"
 
@@ -150,6 +151,54 @@ var QuerySPExitCmd = &cli.Command{
 get sp exit swap plan and migrate gvg task status.`,
 }
 
+var spEndpointFlag = &cli.StringFlag{
+	Name:  "e",
+	Usage: "The endpoint of the SP, empty means all the observed SPs",
+	Value: "",
+}
+
+var QuerySPHealthCmd = &cli.Command{
+	Action: querySPHealthAction,
+	Name:   "query.sp.health",
+	Usage:  "Query the health scores of the SPs which are called by replicate, receive and recovery",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		spEndpointFlag,
+	},
+	Category: "QUERY COMMANDS",
+	Description: `The query.sp.health command send rpc request to manager 
+get the latency, error rate, last failure and health score of the SPs.`,
+}
+
+var gvgIDFlag = &cli.UintFlag{
+	Name:  "g",
+	Usage: "The ID of the global virtual group, 0 means all the scrubbed gvgs",
+	Value: 0,
+}
+
+var scrubPieceLimitFlag = &cli.UintFlag{
+	Name:  "l",
+	Usage: "The max number of the bad pieces to list, only works with the gvg id",
+	Value: 100,
+}
+
+var QueryScrubReportCmd = &cli.Command{
+	Action: queryScrubReportAction,
+	Name:   "query.scrub.report",
+	Usage:  "Query the reports of scrubbing the pieces stored by the SP",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		gvgIDFlag,
+		scrubPieceLimitFlag,
+	},
+	Category: "QUERY COMMANDS",
+	Description: `The query.scrub.report command send rpc request to manager 
+get the scrub progress and the numbers of corrupted and missing pieces of the gvgs,
+and the bad pieces of the gvg if the gvg id is specified. The bad pieces of the replica
+objects are not recovered automatically, they are listed with recovering false and need
+to be repaired manually.`,
+}
+
 func listModularAction(ctx *cli.Context) error {
 	fmt.Print(gfspapp.GetRegisterModulusDescription())
 	return nil
@@ -203,7 +252,10 @@ func getObjectAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 	chain, err := utils.MakeGnfd(cfg)
 	if err != nil {
 		return err
@@ -245,7 +297,10 @@ func challengePieceAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 	chain, err := utils.MakeGnfd(cfg)
 	if err != nil {
 		return err
@@ -317,7 +372,10 @@ func getSegmentIntegrityAction(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("invalid object id, it should be an unsigned integer")
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 	objectInfo, err := client.GetObjectByID(ctx.Context, objectID)
 	if err != nil {
 		return fmt.Errorf("failed to query object info, error: %v", err)
@@ -412,3 +470,66 @@ func getSPExitAction(ctx *cli.Context) error {
 
 	return nil
 }
+
+func querySPHealthAction(ctx *cli.Context) error {
+	cfg, err := utils.MakeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
+	healths, err := client.QuerySPHealth(context.Background(), ctx.String(spEndpointFlag.Name))
+	if err != nil {
+		return err
+	}
+	if len(healths) == 0 {
+		return fmt.Errorf("no call to the SPs is observed")
+	}
+	for _, health := range healths {
+		lastFailure := "-"
+		if health.GetLastFailureTimestampSec() != 0 {
+			lastFailure = fmt.Sprintf("%s %s", time.Unix(health.GetLastFailureTimestampSec(), 0).Format(time.RFC3339),
+				health.GetLastFailureError())
+		}
+		fmt.Printf("endpoint: %s, healthy: %t, score: %.3f, calls: %d, failures: %d, error_rate: %.3f, "+
+			"slow_rate: %.3f, avg_latency_ms: %d, last_failure: %s\n", health.GetSpEndpoint(), health.GetHealthy(),
+			health.GetScore(), health.GetCallCount(), health.GetFailureCount(), health.GetErrorRate(), health.GetSlowRate(),
+			health.GetAvgLatencyMs(), lastFailure)
+	}
+	return nil
+}
+
+func queryScrubReportAction(ctx *cli.Context) error {
+	cfg, err := utils.MakeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
+	reports, pieces, err := client.QueryScrubReport(context.Background(), uint32(ctx.Uint(gvgIDFlag.Name)),
+		uint32(ctx.Uint(scrubPieceLimitFlag.Name)))
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		return fmt.Errorf("no gvg has been scrubbed")
+	}
+	for _, report := range reports {
+		fmt.Printf("gvg_id: %d, redundancy_idx: %d, finished: %t, last_object_id: %d, objects: %d, pieces: %d, "+
+			"corrupted: %d, missing: %d, start_time: %s, update_time: %s\n", report.GetGvgId(), report.GetRedundancyIdx(),
+			report.GetFinished(), report.GetLastScrubbedObjectId(), report.GetScrubbedObjectCount(),
+			report.GetScrubbedPieceCount(), report.GetCorruptedPieceCount(), report.GetMissingPieceCount(),
+			time.Unix(report.GetStartTimestampSec(), 0).Format(time.RFC3339),
+			time.Unix(report.GetUpdateTimestampSec(), 0).Format(time.RFC3339))
+	}
+	for _, piece := range pieces {
+		fmt.Printf("piece_key: %s, object_id: %d, segment_idx: %d, redundancy_idx: %d, state: %s, recovering: %t, "+
+			"detect_time: %s\n", piece.GetPieceKey(), piece.GetObjectId(), piece.GetSegmentIdx(), piece.GetRedundancyIdx(),
+			piece.GetState(), piece.GetRecovering(), time.Unix(piece.GetDetectTimestampSec(), 0).Format(time.RFC3339))
+	}
+	return nil
+}
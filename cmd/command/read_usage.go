@@ -0,0 +1,158 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+var readUsageBucketIDFlag = &cli.Uint64Flag{
+	Name:  "bucket",
+	Usage: "The bucket id, the usage of all the buckets is reported if it is not specified",
+}
+
+var readUsageDimensionFlag = &cli.StringFlag{
+	Name:  "dimension",
+	Usage: "The dimension of the read usage, bucket, object or user",
+	Value: string(spdb.ReadUsageByBucket),
+}
+
+var readUsageGranularityFlag = &cli.StringFlag{
+	Name:  "granularity",
+	Usage: "The granularity of the read usage, hour or day, the top consumers are reported if it is not specified",
+}
+
+var readUsageHoursFlag = &cli.Int64Flag{
+	Name:  "hours",
+	Usage: "The number of the recent hours to report",
+	Value: 24,
+}
+
+var readUsageTopFlag = &cli.IntFlag{
+	Name:  "top",
+	Usage: "The max number of the reported usages, 0 means unlimited",
+	Value: 20,
+}
+
+var readUsageRollupFlag = &cli.BoolFlag{
+	Name:  "rollup",
+	Usage: "Roll up the read records of the reported hours before reporting",
+}
+
+var ReadUsageReportCmd = &cli.Command{
+	Action: readUsageReportAction,
+	Name:   "quota.report",
+	Usage:  "Report the read usage and the read quota of the buckets",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		readUsageBucketIDFlag,
+		readUsageDimensionFlag,
+		readUsageGranularityFlag,
+		readUsageHoursFlag,
+		readUsageTopFlag,
+		readUsageRollupFlag,
+	},
+	Category: "QUOTA COMMANDS",
+	Description: `The quota.report command reports the hourly or daily read usage, or the top consumers by bucket,
+object or user from the rollups of the read records in the sp db. The rollups are updated by the manager
+periodically, the --rollup flag recomputes them for the reported hours, e.g. after the manager is down for a
+while. If the bucket is specified, its read quota and the projected exhaustion of the quota are reported too.`,
+}
+
+func readUsageReportAction(ctx *cli.Context) error {
+	query := &spdb.ReadUsageQuery{
+		Dimension: spdb.ReadUsageDimension(ctx.String(readUsageDimensionFlag.Name)),
+		BucketID:  ctx.Uint64(readUsageBucketIDFlag.Name),
+		LimitNum:  ctx.Int(readUsageTopFlag.Name),
+	}
+	switch query.Dimension {
+	case spdb.ReadUsageByBucket, spdb.ReadUsageByObject, spdb.ReadUsageByUser:
+	default:
+		return fmt.Errorf("invalid dimension: %s", query.Dimension)
+	}
+	switch granularity := ctx.String(readUsageGranularityFlag.Name); granularity {
+	case "hour":
+		query.PeriodUs = spdb.ReadUsageHourUs
+	case "day":
+		query.PeriodUs = spdb.ReadUsageDayUs
+	case "":
+	default:
+		return fmt.Errorf("invalid granularity: %s", granularity)
+	}
+	hours := ctx.Int64(readUsageHoursFlag.Name)
+	if hours <= 0 {
+		return fmt.Errorf("invalid hours: %d", hours)
+	}
+	now := time.Now()
+	query.EndTimestampUs = now.UnixMicro()
+	query.StartTimestampUs = query.EndTimestampUs - hours*spdb.ReadUsageHourUs
+
+	cfg, err := utils.MakeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := utils.MakeSPDB(cfg)
+	if err != nil {
+		return err
+	}
+	if ctx.Bool(readUsageRollupFlag.Name) {
+		if err = db.RollupReadRecord(query.StartTimestampUs, query.EndTimestampUs); err != nil {
+			return err
+		}
+		fmt.Printf("succeed to rollup the read records of the last %d hours\n", hours)
+	}
+
+	if query.BucketID != 0 {
+		traffic, err := db.GetBucketTraffic(query.BucketID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			fmt.Printf("bucket %d has not been read\n", query.BucketID)
+		} else if err != nil {
+			return err
+		} else {
+			fmt.Printf("bucket: %s, id: %d\n", traffic.BucketName, traffic.BucketID)
+			fmt.Printf("charged quota: %d, free quota: %d, consumed: %d (%d%%)\n", traffic.ChargedQuotaSize,
+				traffic.FreeQuotaSize, traffic.ReadConsumedSize, traffic.ConsumedPercent())
+			windowStart := spdb.ReadQuotaProjectionStart(now)
+			recent, err := db.GetReadUsage(&spdb.ReadUsageQuery{
+				Dimension:        spdb.ReadUsageByBucket,
+				BucketID:         query.BucketID,
+				StartTimestampUs: windowStart.UnixMicro(),
+				EndTimestampUs:   now.UnixMicro(),
+			})
+			if err != nil {
+				return err
+			}
+			var recentReadSize uint64
+			if len(recent) > 0 {
+				recentReadSize = recent[0].ReadSize
+			}
+			if exhaustion, ok := traffic.ProjectQuotaExhaustion(recentReadSize, now.Sub(windowStart),
+				now); ok {
+				fmt.Printf("projected exhaustion: %s\n", exhaustion.Format(time.RFC3339))
+			} else {
+				fmt.Printf("projected exhaustion: never before the read quota is reset at %s\n",
+					spdb.ReadQuotaResetTime(now).Format(time.RFC3339))
+			}
+		}
+	}
+
+	usages, err := db.GetReadUsage(query)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-25s %-66s %-40s %20s %12s\n", "period", string(query.Dimension), "name", "read size", "read count")
+	for _, usage := range usages {
+		period := "total"
+		if query.PeriodUs > 0 {
+			period = time.UnixMicro(usage.PeriodStartTimestampUs).UTC().Format(time.RFC3339)
+		}
+		fmt.Printf("%-25s %-66s %-40s %20d %12d\n", period, usage.UsageKey, usage.Name, usage.ReadSize, usage.ReadCount)
+	}
+	return nil
+}
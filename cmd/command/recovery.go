@@ -71,7 +71,10 @@ func recoverObjectAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 
 	objectName := ctx.String(objectFlag.Name)
 	bucketName := ctx.String(bucketFlag.Name)
@@ -117,7 +120,10 @@ func recoverPieceAction(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	client := utils.MakeGfSpClient(cfg)
+	client, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return err
+	}
 
 	objectName := ctx.String(objectFlag.Name)
 	bucketName := ctx.String(bucketFlag.Name)
@@ -164,7 +170,10 @@ func getReplicateIdxBySP(bucketInfo *types.BucketInfo, objectInfo *types.ObjectI
 	if err != nil {
 		return 0, err
 	}
-	spClient := utils.MakeGfSpClient(cfg)
+	spClient, err := utils.MakeGfSpClient(cfg)
+	if err != nil {
+		return 0, err
+	}
 	sp, err := chain.QuerySP(context.Background(), cfg.SpAccount.SpOperatorAddress)
 	if err != nil {
 		return 0, err
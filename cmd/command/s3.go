@@ -0,0 +1,110 @@
+package command
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/urfave/cli/v2"
+
+	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+const (
+	s3AccessKeyIDPrefix    = "GNFD"
+	s3AccessKeyIDLength    = 16
+	s3SecretAccessKeyBytes = 30
+)
+
+var s3AccountFlag = &cli.StringFlag{
+	Name:     "account",
+	Usage:    "The greenfield account address which the access key is mapped to",
+	Required: true,
+}
+
+var s3AccessKeyIDFlag = &cli.StringFlag{
+	Name:     "key",
+	Usage:    "The access key id of the s3 compatible api",
+	Required: true,
+}
+
+var S3CreateKeyCmd = &cli.Command{
+	Action: s3CreateKeyAction,
+	Name:   "s3.create.key",
+	Usage:  "Create the access key of the s3 compatible api for the greenfield account",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		s3AccountFlag,
+	},
+	Category: "S3 COMMANDS",
+	Description: `The s3.create.key command creates a random access key id and secret access key, and records
+them in the sp db. The requests of the s3 compatible api signed by the access key act as the greenfield account,
+the secret access key is only printed once.`,
+}
+
+var S3DeleteKeyCmd = &cli.Command{
+	Action: s3DeleteKeyAction,
+	Name:   "s3.delete.key",
+	Usage:  "Delete the access key of the s3 compatible api",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		s3AccessKeyIDFlag,
+	},
+	Category:    "S3 COMMANDS",
+	Description: `The s3.delete.key command deletes the access key of the s3 compatible api from the sp db.`,
+}
+
+func s3CreateKeyAction(ctx *cli.Context) error {
+	account, err := sdk.AccAddressFromHexUnsafe(ctx.String(s3AccountFlag.Name))
+	if err != nil {
+		return fmt.Errorf("invalid account address: %s", err)
+	}
+	cfg, err := utils.MakeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := utils.MakeSPDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, s3AccessKeyIDLength+s3SecretAccessKeyBytes)
+	if _, err = rand.Read(buf); err != nil {
+		return err
+	}
+	accessKeyID := s3AccessKeyIDPrefix + strings.ToUpper(
+		base64.RawURLEncoding.EncodeToString(buf[:s3AccessKeyIDLength]))[:s3AccessKeyIDLength]
+	accessKeyID = strings.NewReplacer("-", "A", "_", "Z").Replace(accessKeyID)
+	secretAccessKey := base64.StdEncoding.EncodeToString(buf[s3AccessKeyIDLength:])
+	if err = db.InsertS3AccessKey(&spdb.S3AccessKey{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		AccountAddress:  account.String(),
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("account: %s\n", account.String())
+	fmt.Printf("access key id: %s\n", accessKeyID)
+	fmt.Printf("secret access key: %s\n", secretAccessKey)
+	return nil
+}
+
+func s3DeleteKeyAction(ctx *cli.Context) error {
+	cfg, err := utils.MakeConfig(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := utils.MakeSPDB(cfg)
+	if err != nil {
+		return err
+	}
+	accessKeyID := ctx.String(s3AccessKeyIDFlag.Name)
+	if err = db.DeleteS3AccessKey(accessKeyID); err != nil {
+		return err
+	}
+	fmt.Printf("succeed to delete access key id: %s\n", accessKeyID)
+	return nil
+}
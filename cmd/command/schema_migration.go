@@ -0,0 +1,125 @@
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/bnb-chain/greenfield-storage-provider/cmd/utils"
+	"github.com/bnb-chain/greenfield-storage-provider/store/sqldb"
+)
+
+var schemaApplyVersionFlag = &cli.UintFlag{
+	Name:  "version",
+	Usage: "The schema version to migrate to, 0 means the latest version expected by the binary",
+	Value: 0,
+}
+
+var schemaRollbackVersionFlag = &cli.UintFlag{
+	Name:     "version",
+	Usage:    "The schema version to roll back to, the migrations after it are rolled back",
+	Required: true,
+}
+
+var SchemaMigrateStatusCmd = &cli.Command{
+	Action: schemaMigrateStatusAction,
+	Name:   "spdb.migrate.status",
+	Usage:  "Show the schema migrations of the SP DB",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+	},
+	Category: "SPDB COMMANDS",
+	Description: `The spdb.migrate.status command shows the current schema version of the SP DB, the version expected
+by the binary and whether each schema migration has been applied.`,
+}
+
+var SchemaMigrateApplyCmd = &cli.Command{
+	Action: schemaMigrateApplyAction,
+	Name:   "spdb.migrate.apply",
+	Usage:  "Apply the schema migrations to the SP DB",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		schemaApplyVersionFlag,
+	},
+	Category: "SPDB COMMANDS",
+	Description: `The spdb.migrate.apply command applies the schema migrations after the current schema version of
+the SP DB in order, the SP services refuse to start until the SP DB is migrated to the version expected by the binary.`,
+}
+
+var SchemaMigrateRollbackCmd = &cli.Command{
+	Action: schemaMigrateRollbackAction,
+	Name:   "spdb.migrate.rollback",
+	Usage:  "Roll back the schema migrations of the SP DB",
+	Flags: []cli.Flag{
+		utils.ConfigFileFlag,
+		schemaRollbackVersionFlag,
+	},
+	Category: "SPDB COMMANDS",
+	Description: `The spdb.migrate.rollback command rolls back the schema migrations after the specified version in
+the reverse order, it is used before downgrading the binary. The migrations which can not be rolled back are refused.`,
+}
+
+func makeSchemaMigrator(ctx *cli.Context) (*sqldb.SchemaMigrator, error) {
+	cfg, err := utils.MakeConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqldb.NewSchemaMigrator(&cfg.SpDB)
+}
+
+func schemaMigrateStatusAction(ctx *cli.Context) error {
+	migrator, err := makeSchemaMigrator(ctx)
+	if err != nil {
+		return err
+	}
+	current, err := migrator.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("current schema version: %d, expected schema version: %d\n", current, sqldb.LatestSchemaVersion())
+	for _, s := range status {
+		applied := "pending"
+		if s.Applied {
+			applied = "applied at " + time.Unix(s.AppliedTimestampSecond, 0).UTC().Format(time.RFC3339)
+		}
+		fmt.Printf("version: %d, %s, description: %s\n", s.Version, applied, s.Description)
+	}
+	return nil
+}
+
+func schemaMigrateApplyAction(ctx *cli.Context) error {
+	migrator, err := makeSchemaMigrator(ctx)
+	if err != nil {
+		return err
+	}
+	if err = migrator.Apply(uint32(ctx.Uint(schemaApplyVersionFlag.Name))); err != nil {
+		return err
+	}
+	current, err := migrator.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("succeed to migrate the schema to version %d\n", current)
+	return nil
+}
+
+func schemaMigrateRollbackAction(ctx *cli.Context) error {
+	migrator, err := makeSchemaMigrator(ctx)
+	if err != nil {
+		return err
+	}
+	if err = migrator.Rollback(uint32(ctx.Uint(schemaRollbackVersionFlag.Name))); err != nil {
+		return err
+	}
+	current, err := migrator.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("succeed to roll back the schema to version %d\n", current)
+	return nil
+}
@@ -106,6 +106,8 @@ func init() {
 		command.GetSegmentIntegrityCmd,
 		command.QueryBucketMigrateCmd,
 		command.QuerySPExitCmd,
+		command.QuerySPHealthCmd,
+		command.QueryScrubReportCmd,
 		// p2p category commands
 		command.P2PCreateKeysCmd,
 		// miscellaneous category commands
@@ -122,6 +124,16 @@ func init() {
 		command.SPExitCmd,
 		// update quota
 		command.SetQuotaCmd,
+		command.ReadUsageReportCmd,
+		// event notification
+		command.ListEventDeadLetterCmd,
+		// sp db schema migration commands
+		command.SchemaMigrateStatusCmd,
+		command.SchemaMigrateApplyCmd,
+		command.SchemaMigrateRollbackCmd,
+		// s3 category commands
+		command.S3CreateKeyCmd,
+		command.S3DeleteKeyCmd,
 	}
 	registerModular()
 }
@@ -95,7 +95,9 @@ func initLog(ctx *cli.Context, cfg *gfspconfig.GfSpConfig) error {
 	return nil
 }
 
-func MakeGfSpClient(cfg *gfspconfig.GfSpConfig) *gfspclient.GfSpClient {
+// MakeGfSpClient returns the client to call the sp services, an error is returned if the tls is enabled but the
+// tls files fail to be loaded.
+func MakeGfSpClient(cfg *gfspconfig.GfSpConfig) (*gfspclient.GfSpClient, error) {
 	if len(cfg.GRPCAddress) == 0 {
 		cfg.GRPCAddress = gfspapp.DefaultGRPCAddress
 	}
@@ -137,7 +139,20 @@ func MakeGfSpClient(cfg *gfspconfig.GfSpConfig) *gfspclient.GfSpClient {
 		cfg.Endpoint.SignerEndpoint,
 		cfg.Endpoint.AuthenticatorEndpoint,
 		false)
-	return client
+	if cfg.TLS.Enable {
+		reloader, err := gfspapp.NewTLSReloader(&cfg.TLS)
+		if err != nil {
+			log.Errorw("failed to load tls files", "error", err)
+			return nil, err
+		}
+		identities, err := gfspapp.TLSIdentities(&cfg.Endpoint)
+		if err != nil {
+			log.Errorw("failed to check tls identities", "error", err)
+			return nil, err
+		}
+		client.SetTLS(reloader, identities)
+	}
+	return client, nil
 }
 
 func MakeGnfd(cfg *gfspconfig.GfSpConfig) (*gnfd.Gnfd, error) {
@@ -111,6 +111,10 @@ type Downloader interface {
 	PreDownloadObject(ctx context.Context, task task.DownloadObjectTask) error
 	// HandleDownloadObjectTask handles the DownloadObject and get data from piece store.
 	HandleDownloadObjectTask(ctx context.Context, task task.DownloadObjectTask) ([]byte, error)
+	// HandleDownloadObjectTaskStream is the streaming variant of HandleDownloadObjectTask, the returned
+	// reader yields the segment pieces one by one as they come out of the piece store instead of holding
+	// the whole payload in memory. The caller must close the reader after consuming it.
+	HandleDownloadObjectTaskStream(ctx context.Context, task task.DownloadObjectTask) (io.ReadCloser, error)
 	// PostDownloadObject is called after HandleDownloadObjectTask, it can recycle
 	// resources, make statistics and do some other operations..
 	PostDownloadObject(ctx context.Context, task task.DownloadObjectTask)
@@ -138,7 +142,8 @@ type Downloader interface {
 
 // TaskExecutor is an abstract interface to handle background tasks.
 // It will ask tasks from manager modular, handle tasks and report the results or status to the manager modular
-// It can handle these tasks: ReplicatePieceTask, SealObjectTask, ReceivePieceTask, GCObjectTask, GCZombiePieceTask, GCMetaTask.
+// It can handle these tasks: ReplicatePieceTask, SealObjectTask, ReceivePieceTask, GCObjectTask, GCZombiePieceTask, GCMetaTask,
+// ScrubGVGTask.
 type TaskExecutor interface {
 	Modular
 	// AskTask asks the task by remaining limitation from manager module.
@@ -158,6 +163,8 @@ type TaskExecutor interface {
 	HandleGCMetaTask(ctx context.Context, task task.GCMetaTask)
 	// HandleMigrateGVGTask handles the MigrateGVGTask that is asked from manager module
 	HandleMigrateGVGTask(ctx context.Context, gvgTask task.MigrateGVGTask)
+	// HandleScrubGVGTask handles the ScrubGVGTask that is asked from manager module.
+	HandleScrubGVGTask(ctx context.Context, task task.ScrubGVGTask)
 	// ReportTask reports the results or status of running task to manager module.
 	ReportTask(ctx context.Context, task task.Task) error
 }
@@ -221,6 +228,16 @@ type Manager interface {
 	NotifyMigrateSwapOut(ctx context.Context, swapOut *virtualgrouptypes.MsgSwapOut) error
 	// HandleMigrateGVGTask handles MigrateGVGTask, the request from TaskExecutor.
 	HandleMigrateGVGTask(ctx context.Context, task task.MigrateGVGTask) error
+	// ReportSPCallRecords records the results of the calls to the other SPs, the request comes from TaskExecutor.
+	ReportSPCallRecords(ctx context.Context, records []*gfspserver.GfSpSPCallRecord) error
+	// QuerySPHealth queries the health scores of the SPs, all the observed SPs are returned if spEndpoint is empty.
+	QuerySPHealth(ctx context.Context, spEndpoint string) ([]*gfspserver.GfSpSPHealth, error)
+	// HandleScrubGVGTask handles ScrubGVGTask, the request comes from TaskExecutor.
+	HandleScrubGVGTask(ctx context.Context, task task.ScrubGVGTask) error
+	// QueryScrubReport queries the scrub reports of the gvgs, the report of the specified gvg and at most
+	// pieceLimit bad pieces of it are returned if gvgID is not 0.
+	QueryScrubReport(ctx context.Context, gvgID uint32, pieceLimit uint32) ([]*gfspserver.GfSpScrubGVGReport,
+		[]*gfspserver.GfSpScrubPiece, error)
 }
 
 // P2P is an abstract interface to the to do replicate piece approvals between SPs.
@@ -320,6 +337,17 @@ type Uploader interface {
 	// PostResumableUploadObject is called after HandleResumableUploadObjectTask, it can recycle
 	// resources, statistics and other operations.
 	PostResumableUploadObject(ctx context.Context, task task.ResumableUploadObjectTask)
+	// HandleUploadObjectPart handles one part of the multipart upload, stages the part data into
+	// piece store and returns the etag of the part.
+	HandleUploadObjectPart(ctx context.Context, task task.ResumableUploadObjectTask, partNumber uint32, stream io.Reader) (string, error)
+	// ListMultipartUploadParts lists the staged parts of the multipart upload by object id.
+	ListMultipartUploadParts(ctx context.Context, objectID uint64) ([]*spdb.MultipartUploadPart, error)
+	// HandleCompleteMultipartUpload checks the parts to complete, and assembles the staged parts into
+	// segment pieces in the background, the task is reported to Manager to replicate the object after
+	// the parts are assembled.
+	HandleCompleteMultipartUpload(ctx context.Context, task task.ResumableUploadObjectTask, parts []*spdb.MultipartUploadPart) error
+	// HandleAbortMultipartUpload discards the staged parts of the multipart upload.
+	HandleAbortMultipartUpload(ctx context.Context, task task.ResumableUploadObjectTask) error
 
 	// QueryTasks queries upload object tasks that running on uploading by task sub-key.
 	QueryTasks(ctx context.Context, subKey task.TKey) ([]task.Task, error)
@@ -96,6 +96,18 @@ func (*NullModular) HandleResumableUploadObjectTask(ctx context.Context, task ta
 }
 func (*NullModular) PostResumableUploadObject(ctx context.Context, task task.ResumableUploadObjectTask) {
 }
+func (*NullModular) HandleUploadObjectPart(context.Context, task.ResumableUploadObjectTask, uint32, io.Reader) (string, error) {
+	return "", ErrNilModular
+}
+func (*NullModular) ListMultipartUploadParts(context.Context, uint64) ([]*corespdb.MultipartUploadPart, error) {
+	return nil, ErrNilModular
+}
+func (*NullModular) HandleCompleteMultipartUpload(context.Context, task.ResumableUploadObjectTask, []*corespdb.MultipartUploadPart) error {
+	return ErrNilModular
+}
+func (*NullModular) HandleAbortMultipartUpload(context.Context, task.ResumableUploadObjectTask) error {
+	return ErrNilModular
+}
 
 func (*NullModular) HandleUploadObjectTask(ctx context.Context, task task.UploadObjectTask, stream io.Reader) error {
 	return nil
@@ -138,6 +150,19 @@ func (*NullModular) HandleGCMetaTask(context.Context, task.GCMetaTask) error { r
 func (*NullModular) HandleMigrateGVGTask(ctx context.Context, gvgTask task.MigrateGVGTask) error {
 	return ErrNilModular
 }
+func (*NullModular) ReportSPCallRecords(context.Context, []*gfspserver.GfSpSPCallRecord) error {
+	return ErrNilModular
+}
+func (*NullModular) QuerySPHealth(context.Context, string) ([]*gfspserver.GfSpSPHealth, error) {
+	return nil, ErrNilModular
+}
+func (*NullModular) HandleScrubGVGTask(context.Context, task.ScrubGVGTask) error {
+	return ErrNilModular
+}
+func (*NullModular) QueryScrubReport(context.Context, uint32, uint32) ([]*gfspserver.GfSpScrubGVGReport,
+	[]*gfspserver.GfSpScrubPiece, error) {
+	return nil, nil, ErrNilModular
+}
 func (*NullModular) HandleDownloadObjectTask(context.Context, task.DownloadObjectTask) error {
 	return ErrNilModular
 }
@@ -184,6 +209,9 @@ func (*NilModular) PreDownloadObject(context.Context, task.DownloadObjectTask) e
 func (*NilModular) HandleDownloadObjectTask(context.Context, task.DownloadObjectTask) ([]byte, error) {
 	return nil, ErrNilModular
 }
+func (*NilModular) HandleDownloadObjectTaskStream(context.Context, task.DownloadObjectTask) (io.ReadCloser, error) {
+	return nil, ErrNilModular
+}
 func (*NilModular) PostDownloadObject(context.Context, task.DownloadObjectTask) {}
 
 func (*NilModular) PreDownloadPiece(context.Context, task.DownloadPieceTask) error {
@@ -213,6 +241,7 @@ func (*NilModular) HandleReplicatePieceApproval(context.Context, task.ApprovalRe
 	return nil, ErrNilModular
 }
 func (*NilModular) HandleMigrateGVGTask(ctx context.Context, gvgTask task.MigrateGVGTask) {}
+func (*NilModular) HandleScrubGVGTask(context.Context, task.ScrubGVGTask)                 {}
 func (*NilModular) HandleQueryBootstrap(context.Context) ([]string, error)                { return nil, ErrNilModular }
 func (*NilModular) SignCreateBucketApproval(context.Context, *storagetypes.MsgCreateBucket) ([]byte, error) {
 	return nil, ErrNilModular
@@ -2,6 +2,7 @@ package piecestore
 
 import (
 	"context"
+	"time"
 )
 
 // PieceOp is a helper interface for piece key operator and piece size calculate.
@@ -13,6 +14,15 @@ type PieceOp interface {
 	// ChallengePieceKey returns the  piece key used as the key of challenge piece key.
 	// if replicateIdx < 0 , returns the SegmentPieceKey, otherwise returns the ECPieceKey.
 	ChallengePieceKey(objectID uint64, segmentIdx uint32, redundancyIdx int32) string
+	// MultipartPieceKey returns the piece key used to stage the chunk of the part of multipart upload,
+	// the part is split into chunks by max segment size, and re-segmented when the upload completes.
+	// Every upload of the part stages its chunks by its own upload id, so the concurrent uploads of
+	// the same part do not overwrite the chunks of each other.
+	MultipartPieceKey(objectID uint64, partNumber uint32, uploadID string, chunkIdx uint32) string
+	// MultipartPieceKeyPrefix returns the common prefix of the multipart piece keys of the object.
+	MultipartPieceKeyPrefix(objectID uint64) string
+	// IsMultipartPieceKey returns whether the piece key is a multipart piece key.
+	IsMultipartPieceKey(pieceKey string) bool
 	// MaxSegmentPieceSize returns the object max segment piece size by object payload size and
 	// max segment size that comes from storage params.
 	MaxSegmentPieceSize(payloadSize uint64, maxSegmentSize uint64) int64
@@ -29,6 +39,8 @@ type PieceOp interface {
 	ParseSegmentIdx(segmentKey string) (uint32, error)
 	// ParseChallengeIdx returns the segment index and EC piece index  according to the challenge piece key
 	ParseChallengeIdx(challengeKey string) (uint32, int32, error)
+	// ParseObjectID returns the object id according to the segment, ec or multipart piece key
+	ParseObjectID(pieceKey string) (uint64, error)
 }
 
 // PieceStore is an abstract interface to piece store that store the object payload data.
@@ -42,4 +54,14 @@ type PieceStore interface {
 	// DeletePiece deletes the piece data from piece store, it can delete
 	// segment or ec piece data.
 	DeletePiece(ctx context.Context, key string) error
+	// ListPieces returns the pieces whose key has the prefix and is after the marker,
+	// the pieces are sorted by key and the number of pieces is not more than limit.
+	ListPieces(ctx context.Context, prefix, marker string, limit int64) ([]*PieceInfo, error)
+}
+
+// PieceInfo is the meta info of the piece that is stored in piece store.
+type PieceInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
 }
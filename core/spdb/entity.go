@@ -36,6 +36,154 @@ type GCObjectMeta struct {
 	LastDeletedObjectID uint64
 }
 
+// GCMetaProgress defines the gc meta progress info.
+type GCMetaProgress struct {
+	CurrentIdx   uint64
+	LastObjectID uint64
+}
+
+// GCZombieProgress defines the gc zombie piece progress, the next task scans the pieces after the last piece key.
+type GCZombieProgress struct {
+	LastPieceKey string
+}
+
+// ScrubProgress defines the progress and the result of the current round of scrubbing the pieces
+// that are stored by the SP for the global virtual group.
+type ScrubProgress struct {
+	GlobalVirtualGroupID  uint32
+	RedundancyIndex       int32
+	LastObjectID          uint64
+	ScrubbedObjectCount   uint64
+	ScrubbedPieceCount    uint64
+	CorruptedPieceCount   uint64
+	MissingPieceCount     uint64
+	Finished              bool
+	StartTimestampSecond  int64
+	UpdateTimestampSecond int64
+}
+
+// ScrubPieceState defines the state of the bad piece found by scrubbing.
+type ScrubPieceState string
+
+const (
+	// ScrubPieceCorrupted means the checksum of the piece data does not match the integrity meta.
+	ScrubPieceCorrupted ScrubPieceState = "corrupted"
+	// ScrubPieceMissing means the piece data is not found.
+	ScrubPieceMissing ScrubPieceState = "missing"
+	// ScrubPieceNoIntegrity means the integrity meta of the piece is not found, the piece can not be verified
+	// and is not recovered because the recovered piece can not be verified either.
+	ScrubPieceNoIntegrity ScrubPieceState = "no_integrity"
+)
+
+// ScrubPiece defines a bad piece found by scrubbing.
+type ScrubPiece struct {
+	GlobalVirtualGroupID  uint32
+	ObjectID              uint64
+	SegmentIndex          uint32
+	RedundancyIndex       int32
+	PieceKey              string
+	State                 ScrubPieceState
+	Recovering            bool // whether a recovery piece task has been created for the piece
+	DetectTimestampSecond int64
+}
+
+// MultipartUploadPart defines the uploaded part info of the multipart upload.
+type MultipartUploadPart struct {
+	ObjectID              uint64
+	PartNumber            uint32
+	UploadID              string // the upload whose staged chunks are the part data
+	Size                  uint64
+	ETag                  string
+	UpdateTimestampSecond int64
+}
+
+// MultipartUpload defines the multipart upload which has staged parts, the update time is the time of
+// the latest uploaded part.
+type MultipartUpload struct {
+	ObjectID              uint64
+	UpdateTimestampSecond int64
+}
+
+// TaskQueueMeta defines the task persisted by the task queue, the task data is the encoded task,
+// the retry, priority and logs are also recorded for querying. The lease timestamp is not zero if
+// the task has been dispatched.
+type TaskQueueMeta struct {
+	QueueName             string
+	TaskKey               string
+	TaskType              int32
+	Priority              uint32
+	Retry                 int64
+	MaxRetry              int64
+	Logs                  string
+	TaskData              []byte
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64
+	LeaseTimestampSecond  int64
+}
+
+// S3AccessKey defines the access key of the s3 compatible api which is mapped to the greenfield account.
+type S3AccessKey struct {
+	AccessKeyID           string
+	SecretAccessKey       string
+	AccountAddress        string
+	CreateTimestampSecond int64
+}
+
+// BucketCORS defines the cross-origin resource sharing policy of the bucket which overrides the gateway default.
+type BucketCORS struct {
+	BucketName            string
+	AllowedOrigins        []string
+	AllowedMethods        []string
+	AllowedHeaders        []string
+	ExposedHeaders        []string
+	AllowCredentials      bool
+	MaxAgeSec             int64
+	UpdateTimestampSecond int64
+}
+
+// RateLimitCounter defines the request counter of a rate limit window which is shared by the gateway replicas.
+type RateLimitCounter struct {
+	LimiterKey            string
+	Count                 int64
+	ExpireTimestampSecond int64
+}
+
+// ObjectEventType defines the type of the object lifecycle events which are notified to the subscriptions.
+type ObjectEventType string
+
+const (
+	// ObjectUploadedEvent is emitted when the payload of the object is uploaded to the primary sp.
+	ObjectUploadedEvent ObjectEventType = "ObjectUploaded"
+	// ObjectReplicatedEvent is emitted when the pieces of the object are replicated to the secondary sps.
+	ObjectReplicatedEvent ObjectEventType = "ObjectReplicated"
+	// ObjectSealedEvent is emitted when the object is sealed on greenfield.
+	ObjectSealedEvent ObjectEventType = "ObjectSealed"
+	// ObjectDeletedEvent is emitted when the pieces of the deleted object are collected by the gc.
+	ObjectDeletedEvent ObjectEventType = "ObjectDeleted"
+)
+
+// EventSubscription defines a webhook of the bucket which is notified of the object events.
+type EventSubscription struct {
+	BucketName     string
+	SubscriptionID string // unique in the bucket
+	Endpoint       string // the url which the notifications are posted to
+	Secret         string // the key of signing the notifications by hmac-sha256
+	EventTypes     []ObjectEventType
+}
+
+// EventDelivery defines a notification of an object event to a subscription.
+type EventDelivery struct {
+	DeliveryID                 uint64
+	BucketName                 string
+	SubscriptionID             string
+	EventType                  ObjectEventType
+	Payload                    string // the json body of the notification
+	Attempts                   int
+	NextAttemptTimestampSecond int64
+	LastError                  string
+	CreateTimestampSecond      int64
+}
+
 // IntegrityMeta defines the payload integrity hash and piece checksum with objectID.
 type IntegrityMeta struct {
 	ObjectID          uint64
@@ -84,6 +232,109 @@ type TrafficTimeRange struct {
 	LimitNum         int // is unlimited if LimitNum <= 0.
 }
 
+// TotalQuotaSize returns the total read quota of the bucket, including the free and the charged quota.
+func (t *BucketTraffic) TotalQuotaSize() uint64 {
+	return t.FreeQuotaSize + t.ChargedQuotaSize
+}
+
+// RemainingQuotaSize returns the read quota of the bucket which has not been consumed.
+func (t *BucketTraffic) RemainingQuotaSize() uint64 {
+	if t.ReadConsumedSize >= t.TotalQuotaSize() {
+		return 0
+	}
+	return t.TotalQuotaSize() - t.ReadConsumedSize
+}
+
+// ConsumedPercent returns the consumed percent of the read quota, it is 100 if the bucket has no quota.
+func (t *BucketTraffic) ConsumedPercent() uint32 {
+	total := t.TotalQuotaSize()
+	if total == 0 || t.ReadConsumedSize >= total {
+		return 100
+	}
+	return uint32(float64(t.ReadConsumedSize) / float64(total) * 100)
+}
+
+// ProjectQuotaExhaustion projects when the read quota of the bucket is exhausted if the bucket keeps being read
+// as it was in the recent window, returns false if the bucket is not read in the window or the read quota is
+// not exhausted before it is reset at the start of the next month.
+func (t *BucketTraffic) ProjectQuotaExhaustion(recentReadSize uint64, window time.Duration, now time.Time) (time.Time, bool) {
+	remaining := t.RemainingQuotaSize()
+	if remaining == 0 {
+		return now, true
+	}
+	if recentReadSize == 0 || window <= 0 {
+		return time.Time{}, false
+	}
+	seconds := float64(remaining) / float64(recentReadSize) * window.Seconds()
+	resetTime := ReadQuotaResetTime(now)
+	if seconds >= resetTime.Sub(now).Seconds() {
+		return time.Time{}, false
+	}
+	return now.Add(time.Duration(seconds * float64(time.Second))), true
+}
+
+// ReadQuotaResetTime returns when the monthly read quota is reset after now, it is the start of the next
+// month in UTC.
+func ReadQuotaResetTime(now time.Time) time.Time {
+	year, month, _ := now.UTC().Date()
+	return time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ReadQuotaProjectionStart returns the start of the recent window whose read rate is used to project the
+// exhaustion, the window does not reach back before the start of the month, the reads of the previous month
+// consumed the read quota before the reset.
+func ReadQuotaProjectionStart(now time.Time) time.Time {
+	start := now.Add(-ReadQuotaProjectionWindow)
+	if monthStart := ReadQuotaResetTime(now).AddDate(0, -1, 0); start.Before(monthStart) {
+		return monthStart
+	}
+	return start
+}
+
+// ReadUsageDimension defines by what the read records are rolled up.
+type ReadUsageDimension string
+
+const (
+	ReadUsageByBucket ReadUsageDimension = "bucket"
+	ReadUsageByObject ReadUsageDimension = "object"
+	ReadUsageByUser   ReadUsageDimension = "user"
+)
+
+const (
+	// ReadUsageHourUs defines the hourly granularity of the read usage in microsecond, the read records are
+	// rolled up by hour.
+	ReadUsageHourUs int64 = 3600 * 1000 * 1000
+	// ReadUsageDayUs defines the daily granularity of the read usage in microsecond.
+	ReadUsageDayUs = 24 * ReadUsageHourUs
+	// ReadQuotaProjectionWindow defines the recent window whose read rate is used to project when the read
+	// quota of a bucket is exhausted.
+	ReadQuotaProjectionWindow = 24 * time.Hour
+)
+
+// ReadUsage defines the read size and the read count of a bucket, an object or a user in a period.
+type ReadUsage struct {
+	Dimension              ReadUsageDimension
+	UsageKey               string // the bucket id, the object id or the user address
+	BucketID               uint64 // 0 if the usage of a user is summed up across the buckets
+	Name                   string // the bucket name or the object name, empty for the user dimension
+	PeriodStartTimestampUs int64  // the start of the hour or the day, 0 for the totals of the time range
+	ReadSize               uint64
+	ReadCount              uint64
+}
+
+// ReadUsageQuery is used by query the read usage in [StartTimestampUs, EndTimestampUs), the time range is
+// extended to the whole hours.
+type ReadUsageQuery struct {
+	Dimension ReadUsageDimension
+	BucketID  uint64 // 0 means all the buckets
+	// PeriodUs is ReadUsageHourUs or ReadUsageDayUs to return the usage of every period, or 0 to return
+	// the totals of the time range ordered by the read size, which are the top consumers.
+	PeriodUs         int64
+	StartTimestampUs int64
+	EndTimestampUs   int64
+	LimitNum         int // is unlimited if LimitNum <= 0.
+}
+
 type OffChainAuthKey struct {
 	UserAddress string
 	Domain      string
@@ -111,6 +362,14 @@ type MigrateGVGUnitMeta struct {
 	MigrateStatus        int // scheduler assign unit status.
 }
 
+// The migrate status of the gvg unit, the gvg unit is migrated by
+// WaitForMigrate(created)->Migrating(schedule success)->Migrated(executor report success).
+const (
+	MigrateStatusWaitForMigrate = 0
+	MigrateStatusMigrating      = 1
+	MigrateStatusMigrated       = 2
+)
+
 // SwapOutMeta is used to record swap out meta.
 type SwapOutMeta struct {
 	SwapOutKey    string // as primary key
@@ -0,0 +1,101 @@
+package spdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketTraffic_ConsumedPercent(t *testing.T) {
+	testCases := []struct {
+		name          string
+		traffic       *BucketTraffic
+		wantedPercent uint32
+	}{
+		{"no quota", &BucketTraffic{}, 100},
+		{"not consumed", &BucketTraffic{FreeQuotaSize: 100, ChargedQuotaSize: 100}, 0},
+		{"free and charged quota", &BucketTraffic{ReadConsumedSize: 150, FreeQuotaSize: 100, ChargedQuotaSize: 100}, 75},
+		{"rounded down", &BucketTraffic{ReadConsumedSize: 2, FreeQuotaSize: 3}, 66},
+		{"exhausted", &BucketTraffic{ReadConsumedSize: 100, FreeQuotaSize: 100}, 100},
+		{"over consumed", &BucketTraffic{ReadConsumedSize: 300, ChargedQuotaSize: 100}, 100},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantedPercent, tt.traffic.ConsumedPercent())
+		})
+	}
+}
+
+func TestBucketTraffic_ProjectQuotaExhaustion(t *testing.T) {
+	now := time.Date(2023, 6, 10, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		traffic        *BucketTraffic
+		recentReadSize uint64
+		window         time.Duration
+		now            time.Time
+		wantedTime     time.Time
+		wantedOk       bool
+	}{
+		{"exhausted", &BucketTraffic{ReadConsumedSize: 100, FreeQuotaSize: 100}, 0, 24 * time.Hour, now, now, true},
+		{"not read", &BucketTraffic{FreeQuotaSize: 100}, 0, 24 * time.Hour, now, time.Time{}, false},
+		{"no window", &BucketTraffic{FreeQuotaSize: 100}, 10, 0, now, time.Time{}, false},
+		{"exhausted in days", &BucketTraffic{ReadConsumedSize: 20, FreeQuotaSize: 50, ChargedQuotaSize: 50}, 40,
+			24 * time.Hour, now, now.Add(48 * time.Hour), true},
+		{"short window", &BucketTraffic{ReadConsumedSize: 90, ChargedQuotaSize: 100}, 5, time.Hour, now,
+			now.Add(2 * time.Hour), true},
+		{"exhausted after the monthly reset", &BucketTraffic{ReadConsumedSize: 20, FreeQuotaSize: 100}, 1,
+			24 * time.Hour, now, time.Time{}, false},
+		{"exhausted at the monthly reset", &BucketTraffic{FreeQuotaSize: 24}, 1, time.Hour,
+			time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC), time.Time{}, false},
+		{"exhausted before the monthly reset", &BucketTraffic{FreeQuotaSize: 23}, 1, time.Hour,
+			time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC), time.Date(2023, 6, 30, 23, 0, 0, 0, time.UTC), true},
+		{"huge remaining quota", &BucketTraffic{FreeQuotaSize: 1 << 63}, 1, 24 * time.Hour, now, time.Time{}, false},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			exhaustion, ok := tt.traffic.ProjectQuotaExhaustion(tt.recentReadSize, tt.window, tt.now)
+			assert.Equal(t, tt.wantedOk, ok)
+			assert.True(t, tt.wantedTime.Equal(exhaustion), "wanted %s, got %s", tt.wantedTime, exhaustion)
+		})
+	}
+}
+
+func TestReadQuotaResetTime(t *testing.T) {
+	testCases := []struct {
+		name       string
+		now        time.Time
+		wantedTime time.Time
+	}{
+		{"middle of month", time.Date(2023, 6, 10, 12, 0, 0, 0, time.UTC), time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"start of month", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"end of year", time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"local time zone", time.Date(2023, 7, 1, 2, 0, 0, 0, time.FixedZone("UTC+8", 8*3600)),
+			time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantedTime, ReadQuotaResetTime(tt.now))
+		})
+	}
+}
+
+func TestReadQuotaProjectionStart(t *testing.T) {
+	testCases := []struct {
+		name       string
+		now        time.Time
+		wantedTime time.Time
+	}{
+		{"whole window", time.Date(2023, 6, 10, 12, 0, 0, 0, time.UTC), time.Date(2023, 6, 9, 12, 0, 0, 0, time.UTC)},
+		{"window at the month start", time.Date(2023, 6, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"window cut by the month start", time.Date(2023, 6, 1, 6, 0, 0, 0, time.UTC),
+			time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.wantedTime.Equal(ReadQuotaProjectionStart(tt.now)))
+		})
+	}
+}
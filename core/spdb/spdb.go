@@ -26,6 +26,12 @@ type UploadObjectProgressDB interface {
 	GetUploadMetasToSeal(limit int, timeout int64) ([]*UploadObjectMeta, error)
 	// InsertPutEvent inserts a new upload event progress.
 	InsertPutEvent(task coretask.Task) error
+	// DeleteExpiredUploadProgress deletes at most limit upload object progresses in the terminal states
+	// whose update time is before the expiredTimestampSecond, returns the number of deleted progresses.
+	DeleteExpiredUploadProgress(expiredTimestampSecond int64, limit int) (int64, error)
+	// DeleteExpiredPutEvent deletes at most limit upload event logs of every event table whose
+	// update time is before the expiredTime, returns the number of deleted event logs.
+	DeleteExpiredPutEvent(expiredTime time.Time, limit int) (int64, error)
 }
 
 // GCObjectProgressDB interface which records gc object related progress.
@@ -41,6 +47,114 @@ type GCObjectProgressDB interface {
 	GetGCMetasToGC(limit int) ([]*GCObjectMeta, error)
 }
 
+// GCMetaProgressDB interface which records gc meta related progress.
+type GCMetaProgressDB interface {
+	// UpdateGCMetaProgress includes insert and update.
+	UpdateGCMetaProgress(gcMeta *GCMetaProgress) error
+	// QueryGCMetaProgress returns the gc meta progress which is called at startup,
+	// returns (nil, nil) if there is no progress.
+	QueryGCMetaProgress() (*GCMetaProgress, error)
+	// UpdateGCZombieProgress includes insert and update.
+	UpdateGCZombieProgress(progress *GCZombieProgress) error
+	// QueryGCZombieProgress returns the gc zombie piece progress, returns (nil, nil) if there is no progress.
+	QueryGCZombieProgress() (*GCZombieProgress, error)
+}
+
+// ScrubDB interface which records the progress of scrubbing the pieces by global virtual group and
+// the bad pieces found by scrubbing.
+type ScrubDB interface {
+	// UpdateScrubProgress includes insert and update.
+	UpdateScrubProgress(progress *ScrubProgress) error
+	// QueryScrubProgress returns the scrub progress of the gvg, returns (nil, nil) if there is no progress.
+	QueryScrubProgress(gvgID uint32) (*ScrubProgress, error)
+	// ListScrubProgresses returns the scrub progresses of all the gvgs in ascending order of gvg id.
+	ListScrubProgresses() ([]*ScrubProgress, error)
+	// UpdateScrubPiece includes insert and update, the piece found again overwrites the old one.
+	UpdateScrubPiece(piece *ScrubPiece) error
+	// ListScrubPieces returns at most limit bad pieces of the gvg, the latest found first.
+	ListScrubPieces(gvgID uint32, limit int) ([]*ScrubPiece, error)
+	// DeleteScrubPieces deletes all the bad pieces of the gvg, it is called before a new round starts.
+	DeleteScrubPieces(gvgID uint32) error
+}
+
+// MultipartUploadDB interface which records the uploaded parts of multipart upload.
+type MultipartUploadDB interface {
+	// UpdateMultipartUploadPart includes insert and update, the part uploaded again overwrites the old one.
+	UpdateMultipartUploadPart(part *MultipartUploadPart) error
+	// ListMultipartUploadParts returns the uploaded parts of the object in ascending order of part number.
+	ListMultipartUploadParts(objectID uint64) ([]*MultipartUploadPart, error)
+	// DeleteMultipartUploadParts deletes all the uploaded parts records of the object.
+	DeleteMultipartUploadParts(objectID uint64) error
+	// ListMultipartUploads returns at most limit multipart uploads whose object id is greater than
+	// startAfterObjectID in ascending order of object id.
+	ListMultipartUploads(startAfterObjectID uint64, limit int) ([]*MultipartUpload, error)
+}
+
+// TaskQueueDB interface which persists the tasks of the task queue.
+type TaskQueueDB interface {
+	// UpdateQueueTask includes insert and update.
+	UpdateQueueTask(meta *TaskQueueMeta) error
+	// LeaseQueueTask records that the task is popped from the queue to dispatch, the leased task
+	// is kept until it is deleted.
+	LeaseQueueTask(queueName string, taskKey string, leaseTimestampSecond int64) error
+	// DeleteQueueTask deletes the task from the queue.
+	DeleteQueueTask(queueName string, taskKey string) error
+	// ListQueueTasks returns all the tasks of the queue which is called at startup.
+	ListQueueTasks(queueName string) ([]*TaskQueueMeta, error)
+}
+
+// S3AccessKeyDB interface which records the access keys of the s3 compatible api.
+type S3AccessKeyDB interface {
+	// InsertS3AccessKey inserts a new access key.
+	InsertS3AccessKey(key *S3AccessKey) error
+	// GetS3AccessKey returns the access key by the access key id.
+	GetS3AccessKey(accessKeyID string) (*S3AccessKey, error)
+	// DeleteS3AccessKey deletes the access key by the access key id.
+	DeleteS3AccessKey(accessKeyID string) error
+}
+
+// BucketCORSDB interface which records the cors policies of the buckets.
+type BucketCORSDB interface {
+	// UpdateBucketCORS includes insert and update, the new policy overwrites the old one.
+	UpdateBucketCORS(cors *BucketCORS) error
+	// GetBucketCORS returns the cors policy of the bucket, returns nil if the bucket has no policy.
+	GetBucketCORS(bucketName string) (*BucketCORS, error)
+	// DeleteBucketCORS deletes the cors policy of the bucket.
+	DeleteBucketCORS(bucketName string) error
+}
+
+// EventNotificationDB interface which records the event subscriptions of the buckets, the notifications
+// waiting to be delivered and the dead notifications which exceed the max delivery attempts.
+type EventNotificationDB interface {
+	// UpdateBucketEventSubscriptions replaces the event subscriptions of the bucket.
+	UpdateBucketEventSubscriptions(bucketName string, subscriptions []*EventSubscription) error
+	// GetBucketEventSubscriptions returns the event subscriptions of the bucket.
+	GetBucketEventSubscriptions(bucketName string) ([]*EventSubscription, error)
+	// DeleteBucketEventSubscriptions deletes the event subscriptions of the bucket.
+	DeleteBucketEventSubscriptions(bucketName string) error
+	// InsertEventDeliveries inserts the notifications waiting to be delivered.
+	InsertEventDeliveries(deliveries []*EventDelivery) error
+	// GetDueEventDeliveries returns at most limit notifications whose next attempt is not after the timestamp.
+	GetDueEventDeliveries(timestampSecond int64, limit int) ([]*EventDelivery, error)
+	// UpdateEventDeliveryAttempt updates the attempts, the next attempt time and the last error of the notification.
+	UpdateEventDeliveryAttempt(delivery *EventDelivery) error
+	// DeleteEventDelivery deletes the notification which is delivered or dropped.
+	DeleteEventDelivery(deliveryID uint64) error
+	// MoveEventDeliveryToDeadLetter moves the notification which exceeds the max attempts to the dead letters.
+	MoveEventDeliveryToDeadLetter(delivery *EventDelivery) error
+	// ListDeadLetterEventDeliveries returns at most limit dead notifications of the bucket, the latest first.
+	ListDeadLetterEventDeliveries(bucketName string, limit int) ([]*EventDelivery, error)
+}
+
+// RateLimitDB interface which records the rate limit counters shared by the gateway replicas.
+type RateLimitDB interface {
+	// IncreaseRateLimitCounters adds the counts to the counters, the counters are created if they do not
+	// exist, and returns the updated counts of the counters keyed by the limiter key.
+	IncreaseRateLimitCounters(counters []*RateLimitCounter) (map[string]int64, error)
+	// DeleteExpiredRateLimitCounters deletes the counters which expire before the timestamp.
+	DeleteExpiredRateLimitCounters(timestampSecond int64) error
+}
+
 // SignatureDB abstract object integrity interface.
 type SignatureDB interface {
 	/*
@@ -65,6 +179,12 @@ type SignatureDB interface {
 	GetAllReplicatePieceChecksum(objectID uint64, redundancyIdx int32, pieceCount uint32) ([][]byte, error)
 	// DeleteAllReplicatePieceChecksum deletes all piece hashes.
 	DeleteAllReplicatePieceChecksum(objectID uint64, redundancyIdx int32, pieceCount uint32) error
+	// ListReplicatePieceChecksumObjectIDs lists at most limit distinct object ids in ascending
+	// order that are greater than startAfter and still have piece hashes.
+	ListReplicatePieceChecksumObjectIDs(startAfter uint64, limit int) ([]uint64, error)
+	// DeleteReplicatePieceChecksumByObjectIDs deletes all piece hashes of the objects, returns
+	// the number of deleted piece hashes.
+	DeleteReplicatePieceChecksumByObjectIDs(objectIDs []uint64) (int64, error)
 }
 
 // TrafficDB defines a series of traffic interfaces.
@@ -86,6 +206,22 @@ type TrafficDB interface {
 	GetObjectReadRecord(objectID uint64, timeRange *TrafficTimeRange) ([]*ReadRecord, error)
 	// GetUserReadRecord return user record list by time range.
 	GetUserReadRecord(userAddress string, timeRange *TrafficTimeRange) ([]*ReadRecord, error)
+	// DeleteExpiredReadRecord deletes at most limit read records whose read time is before
+	// the expiredTimestampUs, returns the number of deleted records.
+	DeleteExpiredReadRecord(expiredTimestampUs int64, limit int) (int64, error)
+	// RollupReadRecord rolls up the read records of the hours in [startTimestampUs, endTimestampUs) by
+	// bucket, object and user, the rollups of the hours are recomputed if they already exist.
+	RollupReadRecord(startTimestampUs, endTimestampUs int64) error
+	// GetReadUsage returns the read usage from the rollups of the read records.
+	GetReadUsage(query *ReadUsageQuery) ([]*ReadUsage, error)
+	// DeleteExpiredReadUsage deletes the rollups of the hours before the expiredTimestampUs.
+	DeleteExpiredReadUsage(expiredTimestampUs int64) error
+	// GetReadQuotaAlertPercent returns the highest threshold percent of the read quota which has been
+	// notified for the bucket, returns 0 if it has not been notified.
+	GetReadQuotaAlertPercent(bucketID uint64) (uint32, error)
+	// UpdateReadQuotaAlertPercent updates the highest threshold percent of the read quota which has been
+	// notified for the bucket.
+	UpdateReadQuotaAlertPercent(bucketID uint64, percent uint32) error
 }
 
 // SPInfoDB defines a series of sp interfaces.
@@ -152,11 +288,29 @@ type MigrateDB interface {
 	QueryMigrateGVGUnit(migrateKey string) (*MigrateGVGUnitMeta, error)
 	// ListMigrateGVGUnitsByBucketID is used to load at dest sp startup(bucket migrate).
 	ListMigrateGVGUnitsByBucketID(bucketID uint64) ([]*MigrateGVGUnitMeta, error)
+	// ListMigrateGVGUnits returns all the gvg migrate units, is used to protect the migrating pieces from gc.
+	ListMigrateGVGUnits() ([]*MigrateGVGUnitMeta, error)
+
+	// DeleteMigratedBucketGVGUnits deletes the gvg units of at most limit buckets whose units have
+	// all been migrated before the expiredTimestampSecond, returns the number of deleted units.
+	DeleteMigratedBucketGVGUnits(expiredTimestampSecond int64, limit int) (int64, error)
+	// DeleteCompletedDestSPSwapOutUnits deletes at most limit dest sp swap out units and their gvg
+	// units which have all been migrated before the expiredTimestampSecond, returns the number of
+	// deleted swap out units.
+	DeleteCompletedDestSPSwapOutUnits(expiredTimestampSecond int64, limit int) (int64, error)
 }
 
 type SPDB interface {
 	UploadObjectProgressDB
 	GCObjectProgressDB
+	GCMetaProgressDB
+	ScrubDB
+	MultipartUploadDB
+	TaskQueueDB
+	S3AccessKeyDB
+	BucketCORSDB
+	RateLimitDB
+	EventNotificationDB
 	SignatureDB
 	TrafficDB
 	SPInfoDB
@@ -37,6 +37,36 @@ func (m *MockUploadObjectProgressDB) EXPECT() *MockUploadObjectProgressDBMockRec
 	return m.recorder
 }
 
+// DeleteExpiredPutEvent mocks base method.
+func (m *MockUploadObjectProgressDB) DeleteExpiredPutEvent(expiredTime time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredPutEvent", expiredTime, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredPutEvent indicates an expected call of DeleteExpiredPutEvent.
+func (mr *MockUploadObjectProgressDBMockRecorder) DeleteExpiredPutEvent(expiredTime, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredPutEvent", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).DeleteExpiredPutEvent), expiredTime, limit)
+}
+
+// DeleteExpiredUploadProgress mocks base method.
+func (m *MockUploadObjectProgressDB) DeleteExpiredUploadProgress(expiredTimestampSecond int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredUploadProgress", expiredTimestampSecond, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredUploadProgress indicates an expected call of DeleteExpiredUploadProgress.
+func (mr *MockUploadObjectProgressDBMockRecorder) DeleteExpiredUploadProgress(expiredTimestampSecond, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredUploadProgress", reflect.TypeOf((*MockUploadObjectProgressDB)(nil).DeleteExpiredUploadProgress), expiredTimestampSecond, limit)
+}
+
 // DeleteUploadProgress mocks base method.
 func (m *MockUploadObjectProgressDB) DeleteUploadProgress(objectID uint64) error {
 	m.ctrl.T.Helper()
@@ -219,6 +249,694 @@ func (mr *MockGCObjectProgressDBMockRecorder) UpdateGCObjectProgress(gcMeta inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGCObjectProgress", reflect.TypeOf((*MockGCObjectProgressDB)(nil).UpdateGCObjectProgress), gcMeta)
 }
 
+// MockGCMetaProgressDB is a mock of GCMetaProgressDB interface.
+type MockGCMetaProgressDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockGCMetaProgressDBMockRecorder
+}
+
+// MockGCMetaProgressDBMockRecorder is the mock recorder for MockGCMetaProgressDB.
+type MockGCMetaProgressDBMockRecorder struct {
+	mock *MockGCMetaProgressDB
+}
+
+// NewMockGCMetaProgressDB creates a new mock instance.
+func NewMockGCMetaProgressDB(ctrl *gomock.Controller) *MockGCMetaProgressDB {
+	mock := &MockGCMetaProgressDB{ctrl: ctrl}
+	mock.recorder = &MockGCMetaProgressDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGCMetaProgressDB) EXPECT() *MockGCMetaProgressDBMockRecorder {
+	return m.recorder
+}
+
+// QueryGCMetaProgress mocks base method.
+func (m *MockGCMetaProgressDB) QueryGCMetaProgress() (*GCMetaProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryGCMetaProgress")
+	ret0, _ := ret[0].(*GCMetaProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryGCMetaProgress indicates an expected call of QueryGCMetaProgress.
+func (mr *MockGCMetaProgressDBMockRecorder) QueryGCMetaProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryGCMetaProgress", reflect.TypeOf((*MockGCMetaProgressDB)(nil).QueryGCMetaProgress))
+}
+
+// QueryGCZombieProgress mocks base method.
+func (m *MockGCMetaProgressDB) QueryGCZombieProgress() (*GCZombieProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryGCZombieProgress")
+	ret0, _ := ret[0].(*GCZombieProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryGCZombieProgress indicates an expected call of QueryGCZombieProgress.
+func (mr *MockGCMetaProgressDBMockRecorder) QueryGCZombieProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryGCZombieProgress", reflect.TypeOf((*MockGCMetaProgressDB)(nil).QueryGCZombieProgress))
+}
+
+// UpdateGCMetaProgress mocks base method.
+func (m *MockGCMetaProgressDB) UpdateGCMetaProgress(gcMeta *GCMetaProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGCMetaProgress", gcMeta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGCMetaProgress indicates an expected call of UpdateGCMetaProgress.
+func (mr *MockGCMetaProgressDBMockRecorder) UpdateGCMetaProgress(gcMeta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGCMetaProgress", reflect.TypeOf((*MockGCMetaProgressDB)(nil).UpdateGCMetaProgress), gcMeta)
+}
+
+// UpdateGCZombieProgress mocks base method.
+func (m *MockGCMetaProgressDB) UpdateGCZombieProgress(progress *GCZombieProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGCZombieProgress", progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGCZombieProgress indicates an expected call of UpdateGCZombieProgress.
+func (mr *MockGCMetaProgressDBMockRecorder) UpdateGCZombieProgress(progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGCZombieProgress", reflect.TypeOf((*MockGCMetaProgressDB)(nil).UpdateGCZombieProgress), progress)
+}
+
+// MockScrubDB is a mock of ScrubDB interface.
+type MockScrubDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockScrubDBMockRecorder
+}
+
+// MockScrubDBMockRecorder is the mock recorder for MockScrubDB.
+type MockScrubDBMockRecorder struct {
+	mock *MockScrubDB
+}
+
+// NewMockScrubDB creates a new mock instance.
+func NewMockScrubDB(ctrl *gomock.Controller) *MockScrubDB {
+	mock := &MockScrubDB{ctrl: ctrl}
+	mock.recorder = &MockScrubDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScrubDB) EXPECT() *MockScrubDBMockRecorder {
+	return m.recorder
+}
+
+// DeleteScrubPieces mocks base method.
+func (m *MockScrubDB) DeleteScrubPieces(gvgID uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScrubPieces", gvgID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScrubPieces indicates an expected call of DeleteScrubPieces.
+func (mr *MockScrubDBMockRecorder) DeleteScrubPieces(gvgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScrubPieces", reflect.TypeOf((*MockScrubDB)(nil).DeleteScrubPieces), gvgID)
+}
+
+// ListScrubPieces mocks base method.
+func (m *MockScrubDB) ListScrubPieces(gvgID uint32, limit int) ([]*ScrubPiece, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScrubPieces", gvgID, limit)
+	ret0, _ := ret[0].([]*ScrubPiece)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScrubPieces indicates an expected call of ListScrubPieces.
+func (mr *MockScrubDBMockRecorder) ListScrubPieces(gvgID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScrubPieces", reflect.TypeOf((*MockScrubDB)(nil).ListScrubPieces), gvgID, limit)
+}
+
+// ListScrubProgresses mocks base method.
+func (m *MockScrubDB) ListScrubProgresses() ([]*ScrubProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScrubProgresses")
+	ret0, _ := ret[0].([]*ScrubProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScrubProgresses indicates an expected call of ListScrubProgresses.
+func (mr *MockScrubDBMockRecorder) ListScrubProgresses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScrubProgresses", reflect.TypeOf((*MockScrubDB)(nil).ListScrubProgresses))
+}
+
+// QueryScrubProgress mocks base method.
+func (m *MockScrubDB) QueryScrubProgress(gvgID uint32) (*ScrubProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryScrubProgress", gvgID)
+	ret0, _ := ret[0].(*ScrubProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryScrubProgress indicates an expected call of QueryScrubProgress.
+func (mr *MockScrubDBMockRecorder) QueryScrubProgress(gvgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryScrubProgress", reflect.TypeOf((*MockScrubDB)(nil).QueryScrubProgress), gvgID)
+}
+
+// UpdateScrubPiece mocks base method.
+func (m *MockScrubDB) UpdateScrubPiece(piece *ScrubPiece) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScrubPiece", piece)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScrubPiece indicates an expected call of UpdateScrubPiece.
+func (mr *MockScrubDBMockRecorder) UpdateScrubPiece(piece interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScrubPiece", reflect.TypeOf((*MockScrubDB)(nil).UpdateScrubPiece), piece)
+}
+
+// UpdateScrubProgress mocks base method.
+func (m *MockScrubDB) UpdateScrubProgress(progress *ScrubProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScrubProgress", progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScrubProgress indicates an expected call of UpdateScrubProgress.
+func (mr *MockScrubDBMockRecorder) UpdateScrubProgress(progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScrubProgress", reflect.TypeOf((*MockScrubDB)(nil).UpdateScrubProgress), progress)
+}
+
+// MockMultipartUploadDB is a mock of MultipartUploadDB interface.
+type MockMultipartUploadDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockMultipartUploadDBMockRecorder
+}
+
+// MockMultipartUploadDBMockRecorder is the mock recorder for MockMultipartUploadDB.
+type MockMultipartUploadDBMockRecorder struct {
+	mock *MockMultipartUploadDB
+}
+
+// NewMockMultipartUploadDB creates a new mock instance.
+func NewMockMultipartUploadDB(ctrl *gomock.Controller) *MockMultipartUploadDB {
+	mock := &MockMultipartUploadDB{ctrl: ctrl}
+	mock.recorder = &MockMultipartUploadDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMultipartUploadDB) EXPECT() *MockMultipartUploadDBMockRecorder {
+	return m.recorder
+}
+
+// DeleteMultipartUploadParts mocks base method.
+func (m *MockMultipartUploadDB) DeleteMultipartUploadParts(objectID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMultipartUploadParts", objectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMultipartUploadParts indicates an expected call of DeleteMultipartUploadParts.
+func (mr *MockMultipartUploadDBMockRecorder) DeleteMultipartUploadParts(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMultipartUploadParts", reflect.TypeOf((*MockMultipartUploadDB)(nil).DeleteMultipartUploadParts), objectID)
+}
+
+// ListMultipartUploadParts mocks base method.
+func (m *MockMultipartUploadDB) ListMultipartUploadParts(objectID uint64) ([]*MultipartUploadPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMultipartUploadParts", objectID)
+	ret0, _ := ret[0].([]*MultipartUploadPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMultipartUploadParts indicates an expected call of ListMultipartUploadParts.
+func (mr *MockMultipartUploadDBMockRecorder) ListMultipartUploadParts(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMultipartUploadParts", reflect.TypeOf((*MockMultipartUploadDB)(nil).ListMultipartUploadParts), objectID)
+}
+
+// ListMultipartUploads mocks base method.
+func (m *MockMultipartUploadDB) ListMultipartUploads(startAfterObjectID uint64, limit int) ([]*MultipartUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMultipartUploads", startAfterObjectID, limit)
+	ret0, _ := ret[0].([]*MultipartUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMultipartUploads indicates an expected call of ListMultipartUploads.
+func (mr *MockMultipartUploadDBMockRecorder) ListMultipartUploads(startAfterObjectID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMultipartUploads", reflect.TypeOf((*MockMultipartUploadDB)(nil).ListMultipartUploads), startAfterObjectID, limit)
+}
+
+// UpdateMultipartUploadPart mocks base method.
+func (m *MockMultipartUploadDB) UpdateMultipartUploadPart(part *MultipartUploadPart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMultipartUploadPart", part)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMultipartUploadPart indicates an expected call of UpdateMultipartUploadPart.
+func (mr *MockMultipartUploadDBMockRecorder) UpdateMultipartUploadPart(part interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMultipartUploadPart", reflect.TypeOf((*MockMultipartUploadDB)(nil).UpdateMultipartUploadPart), part)
+}
+
+// MockTaskQueueDB is a mock of TaskQueueDB interface.
+type MockTaskQueueDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskQueueDBMockRecorder
+}
+
+// MockTaskQueueDBMockRecorder is the mock recorder for MockTaskQueueDB.
+type MockTaskQueueDBMockRecorder struct {
+	mock *MockTaskQueueDB
+}
+
+// NewMockTaskQueueDB creates a new mock instance.
+func NewMockTaskQueueDB(ctrl *gomock.Controller) *MockTaskQueueDB {
+	mock := &MockTaskQueueDB{ctrl: ctrl}
+	mock.recorder = &MockTaskQueueDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskQueueDB) EXPECT() *MockTaskQueueDBMockRecorder {
+	return m.recorder
+}
+
+// DeleteQueueTask mocks base method.
+func (m *MockTaskQueueDB) DeleteQueueTask(queueName, taskKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQueueTask", queueName, taskKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteQueueTask indicates an expected call of DeleteQueueTask.
+func (mr *MockTaskQueueDBMockRecorder) DeleteQueueTask(queueName, taskKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQueueTask", reflect.TypeOf((*MockTaskQueueDB)(nil).DeleteQueueTask), queueName, taskKey)
+}
+
+// LeaseQueueTask mocks base method.
+func (m *MockTaskQueueDB) LeaseQueueTask(queueName, taskKey string, leaseTimestampSecond int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaseQueueTask", queueName, taskKey, leaseTimestampSecond)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeaseQueueTask indicates an expected call of LeaseQueueTask.
+func (mr *MockTaskQueueDBMockRecorder) LeaseQueueTask(queueName, taskKey, leaseTimestampSecond interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaseQueueTask", reflect.TypeOf((*MockTaskQueueDB)(nil).LeaseQueueTask), queueName, taskKey, leaseTimestampSecond)
+}
+
+// ListQueueTasks mocks base method.
+func (m *MockTaskQueueDB) ListQueueTasks(queueName string) ([]*TaskQueueMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQueueTasks", queueName)
+	ret0, _ := ret[0].([]*TaskQueueMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQueueTasks indicates an expected call of ListQueueTasks.
+func (mr *MockTaskQueueDBMockRecorder) ListQueueTasks(queueName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQueueTasks", reflect.TypeOf((*MockTaskQueueDB)(nil).ListQueueTasks), queueName)
+}
+
+// UpdateQueueTask mocks base method.
+func (m *MockTaskQueueDB) UpdateQueueTask(meta *TaskQueueMeta) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQueueTask", meta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateQueueTask indicates an expected call of UpdateQueueTask.
+func (mr *MockTaskQueueDBMockRecorder) UpdateQueueTask(meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQueueTask", reflect.TypeOf((*MockTaskQueueDB)(nil).UpdateQueueTask), meta)
+}
+
+// MockS3AccessKeyDB is a mock of S3AccessKeyDB interface.
+type MockS3AccessKeyDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockS3AccessKeyDBMockRecorder
+}
+
+// MockS3AccessKeyDBMockRecorder is the mock recorder for MockS3AccessKeyDB.
+type MockS3AccessKeyDBMockRecorder struct {
+	mock *MockS3AccessKeyDB
+}
+
+// NewMockS3AccessKeyDB creates a new mock instance.
+func NewMockS3AccessKeyDB(ctrl *gomock.Controller) *MockS3AccessKeyDB {
+	mock := &MockS3AccessKeyDB{ctrl: ctrl}
+	mock.recorder = &MockS3AccessKeyDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockS3AccessKeyDB) EXPECT() *MockS3AccessKeyDBMockRecorder {
+	return m.recorder
+}
+
+// DeleteS3AccessKey mocks base method.
+func (m *MockS3AccessKeyDB) DeleteS3AccessKey(accessKeyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteS3AccessKey", accessKeyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteS3AccessKey indicates an expected call of DeleteS3AccessKey.
+func (mr *MockS3AccessKeyDBMockRecorder) DeleteS3AccessKey(accessKeyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteS3AccessKey", reflect.TypeOf((*MockS3AccessKeyDB)(nil).DeleteS3AccessKey), accessKeyID)
+}
+
+// GetS3AccessKey mocks base method.
+func (m *MockS3AccessKeyDB) GetS3AccessKey(accessKeyID string) (*S3AccessKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetS3AccessKey", accessKeyID)
+	ret0, _ := ret[0].(*S3AccessKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetS3AccessKey indicates an expected call of GetS3AccessKey.
+func (mr *MockS3AccessKeyDBMockRecorder) GetS3AccessKey(accessKeyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetS3AccessKey", reflect.TypeOf((*MockS3AccessKeyDB)(nil).GetS3AccessKey), accessKeyID)
+}
+
+// InsertS3AccessKey mocks base method.
+func (m *MockS3AccessKeyDB) InsertS3AccessKey(key *S3AccessKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertS3AccessKey", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertS3AccessKey indicates an expected call of InsertS3AccessKey.
+func (mr *MockS3AccessKeyDBMockRecorder) InsertS3AccessKey(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertS3AccessKey", reflect.TypeOf((*MockS3AccessKeyDB)(nil).InsertS3AccessKey), key)
+}
+
+// MockBucketCORSDB is a mock of BucketCORSDB interface.
+type MockBucketCORSDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockBucketCORSDBMockRecorder
+}
+
+// MockBucketCORSDBMockRecorder is the mock recorder for MockBucketCORSDB.
+type MockBucketCORSDBMockRecorder struct {
+	mock *MockBucketCORSDB
+}
+
+// NewMockBucketCORSDB creates a new mock instance.
+func NewMockBucketCORSDB(ctrl *gomock.Controller) *MockBucketCORSDB {
+	mock := &MockBucketCORSDB{ctrl: ctrl}
+	mock.recorder = &MockBucketCORSDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBucketCORSDB) EXPECT() *MockBucketCORSDBMockRecorder {
+	return m.recorder
+}
+
+// DeleteBucketCORS mocks base method.
+func (m *MockBucketCORSDB) DeleteBucketCORS(bucketName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucketCORS", bucketName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBucketCORS indicates an expected call of DeleteBucketCORS.
+func (mr *MockBucketCORSDBMockRecorder) DeleteBucketCORS(bucketName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucketCORS", reflect.TypeOf((*MockBucketCORSDB)(nil).DeleteBucketCORS), bucketName)
+}
+
+// GetBucketCORS mocks base method.
+func (m *MockBucketCORSDB) GetBucketCORS(bucketName string) (*BucketCORS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketCORS", bucketName)
+	ret0, _ := ret[0].(*BucketCORS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketCORS indicates an expected call of GetBucketCORS.
+func (mr *MockBucketCORSDBMockRecorder) GetBucketCORS(bucketName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketCORS", reflect.TypeOf((*MockBucketCORSDB)(nil).GetBucketCORS), bucketName)
+}
+
+// UpdateBucketCORS mocks base method.
+func (m *MockBucketCORSDB) UpdateBucketCORS(cors *BucketCORS) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBucketCORS", cors)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBucketCORS indicates an expected call of UpdateBucketCORS.
+func (mr *MockBucketCORSDBMockRecorder) UpdateBucketCORS(cors interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBucketCORS", reflect.TypeOf((*MockBucketCORSDB)(nil).UpdateBucketCORS), cors)
+}
+
+// MockEventNotificationDB is a mock of EventNotificationDB interface.
+type MockEventNotificationDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventNotificationDBMockRecorder
+}
+
+// MockEventNotificationDBMockRecorder is the mock recorder for MockEventNotificationDB.
+type MockEventNotificationDBMockRecorder struct {
+	mock *MockEventNotificationDB
+}
+
+// NewMockEventNotificationDB creates a new mock instance.
+func NewMockEventNotificationDB(ctrl *gomock.Controller) *MockEventNotificationDB {
+	mock := &MockEventNotificationDB{ctrl: ctrl}
+	mock.recorder = &MockEventNotificationDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventNotificationDB) EXPECT() *MockEventNotificationDBMockRecorder {
+	return m.recorder
+}
+
+// DeleteBucketEventSubscriptions mocks base method.
+func (m *MockEventNotificationDB) DeleteBucketEventSubscriptions(bucketName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucketEventSubscriptions", bucketName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBucketEventSubscriptions indicates an expected call of DeleteBucketEventSubscriptions.
+func (mr *MockEventNotificationDBMockRecorder) DeleteBucketEventSubscriptions(bucketName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucketEventSubscriptions", reflect.TypeOf((*MockEventNotificationDB)(nil).DeleteBucketEventSubscriptions), bucketName)
+}
+
+// DeleteEventDelivery mocks base method.
+func (m *MockEventNotificationDB) DeleteEventDelivery(deliveryID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventDelivery", deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEventDelivery indicates an expected call of DeleteEventDelivery.
+func (mr *MockEventNotificationDBMockRecorder) DeleteEventDelivery(deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventDelivery", reflect.TypeOf((*MockEventNotificationDB)(nil).DeleteEventDelivery), deliveryID)
+}
+
+// GetBucketEventSubscriptions mocks base method.
+func (m *MockEventNotificationDB) GetBucketEventSubscriptions(bucketName string) ([]*EventSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketEventSubscriptions", bucketName)
+	ret0, _ := ret[0].([]*EventSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketEventSubscriptions indicates an expected call of GetBucketEventSubscriptions.
+func (mr *MockEventNotificationDBMockRecorder) GetBucketEventSubscriptions(bucketName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketEventSubscriptions", reflect.TypeOf((*MockEventNotificationDB)(nil).GetBucketEventSubscriptions), bucketName)
+}
+
+// GetDueEventDeliveries mocks base method.
+func (m *MockEventNotificationDB) GetDueEventDeliveries(timestampSecond int64, limit int) ([]*EventDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueEventDeliveries", timestampSecond, limit)
+	ret0, _ := ret[0].([]*EventDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueEventDeliveries indicates an expected call of GetDueEventDeliveries.
+func (mr *MockEventNotificationDBMockRecorder) GetDueEventDeliveries(timestampSecond, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueEventDeliveries", reflect.TypeOf((*MockEventNotificationDB)(nil).GetDueEventDeliveries), timestampSecond, limit)
+}
+
+// InsertEventDeliveries mocks base method.
+func (m *MockEventNotificationDB) InsertEventDeliveries(deliveries []*EventDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEventDeliveries", deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertEventDeliveries indicates an expected call of InsertEventDeliveries.
+func (mr *MockEventNotificationDBMockRecorder) InsertEventDeliveries(deliveries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEventDeliveries", reflect.TypeOf((*MockEventNotificationDB)(nil).InsertEventDeliveries), deliveries)
+}
+
+// ListDeadLetterEventDeliveries mocks base method.
+func (m *MockEventNotificationDB) ListDeadLetterEventDeliveries(bucketName string, limit int) ([]*EventDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetterEventDeliveries", bucketName, limit)
+	ret0, _ := ret[0].([]*EventDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetterEventDeliveries indicates an expected call of ListDeadLetterEventDeliveries.
+func (mr *MockEventNotificationDBMockRecorder) ListDeadLetterEventDeliveries(bucketName, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetterEventDeliveries", reflect.TypeOf((*MockEventNotificationDB)(nil).ListDeadLetterEventDeliveries), bucketName, limit)
+}
+
+// MoveEventDeliveryToDeadLetter mocks base method.
+func (m *MockEventNotificationDB) MoveEventDeliveryToDeadLetter(delivery *EventDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveEventDeliveryToDeadLetter", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveEventDeliveryToDeadLetter indicates an expected call of MoveEventDeliveryToDeadLetter.
+func (mr *MockEventNotificationDBMockRecorder) MoveEventDeliveryToDeadLetter(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveEventDeliveryToDeadLetter", reflect.TypeOf((*MockEventNotificationDB)(nil).MoveEventDeliveryToDeadLetter), delivery)
+}
+
+// UpdateBucketEventSubscriptions mocks base method.
+func (m *MockEventNotificationDB) UpdateBucketEventSubscriptions(bucketName string, subscriptions []*EventSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBucketEventSubscriptions", bucketName, subscriptions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBucketEventSubscriptions indicates an expected call of UpdateBucketEventSubscriptions.
+func (mr *MockEventNotificationDBMockRecorder) UpdateBucketEventSubscriptions(bucketName, subscriptions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBucketEventSubscriptions", reflect.TypeOf((*MockEventNotificationDB)(nil).UpdateBucketEventSubscriptions), bucketName, subscriptions)
+}
+
+// UpdateEventDeliveryAttempt mocks base method.
+func (m *MockEventNotificationDB) UpdateEventDeliveryAttempt(delivery *EventDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventDeliveryAttempt", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEventDeliveryAttempt indicates an expected call of UpdateEventDeliveryAttempt.
+func (mr *MockEventNotificationDBMockRecorder) UpdateEventDeliveryAttempt(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventDeliveryAttempt", reflect.TypeOf((*MockEventNotificationDB)(nil).UpdateEventDeliveryAttempt), delivery)
+}
+
+// MockRateLimitDB is a mock of RateLimitDB interface.
+type MockRateLimitDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitDBMockRecorder
+}
+
+// MockRateLimitDBMockRecorder is the mock recorder for MockRateLimitDB.
+type MockRateLimitDBMockRecorder struct {
+	mock *MockRateLimitDB
+}
+
+// NewMockRateLimitDB creates a new mock instance.
+func NewMockRateLimitDB(ctrl *gomock.Controller) *MockRateLimitDB {
+	mock := &MockRateLimitDB{ctrl: ctrl}
+	mock.recorder = &MockRateLimitDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitDB) EXPECT() *MockRateLimitDBMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredRateLimitCounters mocks base method.
+func (m *MockRateLimitDB) DeleteExpiredRateLimitCounters(timestampSecond int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRateLimitCounters", timestampSecond)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredRateLimitCounters indicates an expected call of DeleteExpiredRateLimitCounters.
+func (mr *MockRateLimitDBMockRecorder) DeleteExpiredRateLimitCounters(timestampSecond interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRateLimitCounters", reflect.TypeOf((*MockRateLimitDB)(nil).DeleteExpiredRateLimitCounters), timestampSecond)
+}
+
+// IncreaseRateLimitCounters mocks base method.
+func (m *MockRateLimitDB) IncreaseRateLimitCounters(counters []*RateLimitCounter) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseRateLimitCounters", counters)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncreaseRateLimitCounters indicates an expected call of IncreaseRateLimitCounters.
+func (mr *MockRateLimitDBMockRecorder) IncreaseRateLimitCounters(counters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseRateLimitCounters", reflect.TypeOf((*MockRateLimitDB)(nil).IncreaseRateLimitCounters), counters)
+}
+
 // MockSignatureDB is a mock of SignatureDB interface.
 type MockSignatureDB struct {
 	ctrl     *gomock.Controller
@@ -270,6 +988,21 @@ func (mr *MockSignatureDBMockRecorder) DeleteObjectIntegrity(objectID, redundanc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjectIntegrity", reflect.TypeOf((*MockSignatureDB)(nil).DeleteObjectIntegrity), objectID, redundancyIndex)
 }
 
+// DeleteReplicatePieceChecksumByObjectIDs mocks base method.
+func (m *MockSignatureDB) DeleteReplicatePieceChecksumByObjectIDs(objectIDs []uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReplicatePieceChecksumByObjectIDs", objectIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteReplicatePieceChecksumByObjectIDs indicates an expected call of DeleteReplicatePieceChecksumByObjectIDs.
+func (mr *MockSignatureDBMockRecorder) DeleteReplicatePieceChecksumByObjectIDs(objectIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicatePieceChecksumByObjectIDs", reflect.TypeOf((*MockSignatureDB)(nil).DeleteReplicatePieceChecksumByObjectIDs), objectIDs)
+}
+
 // GetAllReplicatePieceChecksum mocks base method.
 func (m *MockSignatureDB) GetAllReplicatePieceChecksum(objectID uint64, redundancyIdx int32, pieceCount uint32) ([][]byte, error) {
 	m.ctrl.T.Helper()
@@ -300,6 +1033,21 @@ func (mr *MockSignatureDBMockRecorder) GetObjectIntegrity(objectID, redundancyIn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectIntegrity", reflect.TypeOf((*MockSignatureDB)(nil).GetObjectIntegrity), objectID, redundancyIndex)
 }
 
+// ListReplicatePieceChecksumObjectIDs mocks base method.
+func (m *MockSignatureDB) ListReplicatePieceChecksumObjectIDs(startAfter uint64, limit int) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReplicatePieceChecksumObjectIDs", startAfter, limit)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReplicatePieceChecksumObjectIDs indicates an expected call of ListReplicatePieceChecksumObjectIDs.
+func (mr *MockSignatureDBMockRecorder) ListReplicatePieceChecksumObjectIDs(startAfter, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReplicatePieceChecksumObjectIDs", reflect.TypeOf((*MockSignatureDB)(nil).ListReplicatePieceChecksumObjectIDs), startAfter, limit)
+}
+
 // SetObjectIntegrity mocks base method.
 func (m *MockSignatureDB) SetObjectIntegrity(integrity *IntegrityMeta) error {
 	m.ctrl.T.Helper()
@@ -328,32 +1076,32 @@ func (mr *MockSignatureDBMockRecorder) SetReplicatePieceChecksum(objectID, segme
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReplicatePieceChecksum", reflect.TypeOf((*MockSignatureDB)(nil).SetReplicatePieceChecksum), objectID, segmentIdx, redundancyIdx, checksum)
 }
 
-// UpdatePieceChecksum mocks base method.
-func (m *MockSignatureDB) UpdatePieceChecksum(objectID uint64, redundancyIndex int32, checksum []byte) error {
+// UpdateIntegrityChecksum mocks base method.
+func (m *MockSignatureDB) UpdateIntegrityChecksum(integrity *IntegrityMeta) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePieceChecksum", objectID, redundancyIndex, checksum)
+	ret := m.ctrl.Call(m, "UpdateIntegrityChecksum", integrity)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdatePieceChecksum indicates an expected call of UpdatePieceChecksum.
-func (mr *MockSignatureDBMockRecorder) UpdatePieceChecksum(objectID, redundancyIndex, checksum interface{}) *gomock.Call {
+// UpdateIntegrityChecksum indicates an expected call of UpdateIntegrityChecksum.
+func (mr *MockSignatureDBMockRecorder) UpdateIntegrityChecksum(integrity interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePieceChecksum", reflect.TypeOf((*MockSignatureDB)(nil).UpdatePieceChecksum), objectID, redundancyIndex, checksum)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIntegrityChecksum", reflect.TypeOf((*MockSignatureDB)(nil).UpdateIntegrityChecksum), integrity)
 }
 
-// UpdateIntegrityChecksum mocks base method.
-func (m *MockSignatureDB) UpdateIntegrityChecksum(integrity *IntegrityMeta) error {
+// UpdatePieceChecksum mocks base method.
+func (m *MockSignatureDB) UpdatePieceChecksum(objectID uint64, redundancyIndex int32, checksum []byte) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateIntegrityChecksum", integrity)
+	ret := m.ctrl.Call(m, "UpdatePieceChecksum", objectID, redundancyIndex, checksum)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateIntegrityChecksum indicates an expected call of UpdateIntegrityChecksum.
-func (mr *MockSignatureDBMockRecorder) UpdateIntegrityChecksum(integrity interface{}) *gomock.Call {
+// UpdatePieceChecksum indicates an expected call of UpdatePieceChecksum.
+func (mr *MockSignatureDBMockRecorder) UpdatePieceChecksum(objectID, redundancyIndex, checksum interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIntegrityChecksum", reflect.TypeOf((*MockSignatureDB)(nil).UpdateIntegrityChecksum), integrity)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePieceChecksum", reflect.TypeOf((*MockSignatureDB)(nil).UpdatePieceChecksum), objectID, redundancyIndex, checksum)
 }
 
 // MockTrafficDB is a mock of TrafficDB interface.
@@ -393,6 +1141,35 @@ func (mr *MockTrafficDBMockRecorder) CheckQuotaAndAddReadRecord(record, quota in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckQuotaAndAddReadRecord", reflect.TypeOf((*MockTrafficDB)(nil).CheckQuotaAndAddReadRecord), record, quota)
 }
 
+// DeleteExpiredReadRecord mocks base method.
+func (m *MockTrafficDB) DeleteExpiredReadRecord(expiredTimestampUs int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredReadRecord", expiredTimestampUs, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredReadRecord indicates an expected call of DeleteExpiredReadRecord.
+func (mr *MockTrafficDBMockRecorder) DeleteExpiredReadRecord(expiredTimestampUs, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredReadRecord", reflect.TypeOf((*MockTrafficDB)(nil).DeleteExpiredReadRecord), expiredTimestampUs, limit)
+}
+
+// DeleteExpiredReadUsage mocks base method.
+func (m *MockTrafficDB) DeleteExpiredReadUsage(expiredTimestampUs int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredReadUsage", expiredTimestampUs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredReadUsage indicates an expected call of DeleteExpiredReadUsage.
+func (mr *MockTrafficDBMockRecorder) DeleteExpiredReadUsage(expiredTimestampUs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredReadUsage", reflect.TypeOf((*MockTrafficDB)(nil).DeleteExpiredReadUsage), expiredTimestampUs)
+}
+
 // GetBucketReadRecord mocks base method.
 func (m *MockTrafficDB) GetBucketReadRecord(bucketID uint64, timeRange *TrafficTimeRange) ([]*ReadRecord, error) {
 	m.ctrl.T.Helper()
@@ -438,6 +1215,21 @@ func (mr *MockTrafficDBMockRecorder) GetObjectReadRecord(objectID, timeRange int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectReadRecord", reflect.TypeOf((*MockTrafficDB)(nil).GetObjectReadRecord), objectID, timeRange)
 }
 
+// GetReadQuotaAlertPercent mocks base method.
+func (m *MockTrafficDB) GetReadQuotaAlertPercent(bucketID uint64) (uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadQuotaAlertPercent", bucketID)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReadQuotaAlertPercent indicates an expected call of GetReadQuotaAlertPercent.
+func (mr *MockTrafficDBMockRecorder) GetReadQuotaAlertPercent(bucketID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadQuotaAlertPercent", reflect.TypeOf((*MockTrafficDB)(nil).GetReadQuotaAlertPercent), bucketID)
+}
+
 // GetReadRecord mocks base method.
 func (m *MockTrafficDB) GetReadRecord(timeRange *TrafficTimeRange) ([]*ReadRecord, error) {
 	m.ctrl.T.Helper()
@@ -453,6 +1245,21 @@ func (mr *MockTrafficDBMockRecorder) GetReadRecord(timeRange interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadRecord", reflect.TypeOf((*MockTrafficDB)(nil).GetReadRecord), timeRange)
 }
 
+// GetReadUsage mocks base method.
+func (m *MockTrafficDB) GetReadUsage(query *ReadUsageQuery) ([]*ReadUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadUsage", query)
+	ret0, _ := ret[0].([]*ReadUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReadUsage indicates an expected call of GetReadUsage.
+func (mr *MockTrafficDBMockRecorder) GetReadUsage(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadUsage", reflect.TypeOf((*MockTrafficDB)(nil).GetReadUsage), query)
+}
+
 // GetUserReadRecord mocks base method.
 func (m *MockTrafficDB) GetUserReadRecord(userAddress string, timeRange *TrafficTimeRange) ([]*ReadRecord, error) {
 	m.ctrl.T.Helper()
@@ -482,6 +1289,34 @@ func (mr *MockTrafficDBMockRecorder) InitBucketTraffic(bucketID, bucketName, quo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitBucketTraffic", reflect.TypeOf((*MockTrafficDB)(nil).InitBucketTraffic), bucketID, bucketName, quota)
 }
 
+// RollupReadRecord mocks base method.
+func (m *MockTrafficDB) RollupReadRecord(startTimestampUs, endTimestampUs int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupReadRecord", startTimestampUs, endTimestampUs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupReadRecord indicates an expected call of RollupReadRecord.
+func (mr *MockTrafficDBMockRecorder) RollupReadRecord(startTimestampUs, endTimestampUs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupReadRecord", reflect.TypeOf((*MockTrafficDB)(nil).RollupReadRecord), startTimestampUs, endTimestampUs)
+}
+
+// UpdateReadQuotaAlertPercent mocks base method.
+func (m *MockTrafficDB) UpdateReadQuotaAlertPercent(bucketID uint64, percent uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReadQuotaAlertPercent", bucketID, percent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReadQuotaAlertPercent indicates an expected call of UpdateReadQuotaAlertPercent.
+func (mr *MockTrafficDBMockRecorder) UpdateReadQuotaAlertPercent(bucketID, percent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReadQuotaAlertPercent", reflect.TypeOf((*MockTrafficDB)(nil).UpdateReadQuotaAlertPercent), bucketID, percent)
+}
+
 // MockSPInfoDB is a mock of SPInfoDB interface.
 type MockSPInfoDB struct {
 	ctrl     *gomock.Controller
@@ -705,6 +1540,21 @@ func (m *MockMigrateDB) EXPECT() *MockMigrateDBMockRecorder {
 	return m.recorder
 }
 
+// DeleteCompletedDestSPSwapOutUnits mocks base method.
+func (m *MockMigrateDB) DeleteCompletedDestSPSwapOutUnits(expiredTimestampSecond int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompletedDestSPSwapOutUnits", expiredTimestampSecond, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCompletedDestSPSwapOutUnits indicates an expected call of DeleteCompletedDestSPSwapOutUnits.
+func (mr *MockMigrateDBMockRecorder) DeleteCompletedDestSPSwapOutUnits(expiredTimestampSecond, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompletedDestSPSwapOutUnits", reflect.TypeOf((*MockMigrateDB)(nil).DeleteCompletedDestSPSwapOutUnits), expiredTimestampSecond, limit)
+}
+
 // DeleteMigrateGVGUnit mocks base method.
 func (m *MockMigrateDB) DeleteMigrateGVGUnit(meta *MigrateGVGUnitMeta) error {
 	m.ctrl.T.Helper()
@@ -719,6 +1569,21 @@ func (mr *MockMigrateDBMockRecorder) DeleteMigrateGVGUnit(meta interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMigrateGVGUnit", reflect.TypeOf((*MockMigrateDB)(nil).DeleteMigrateGVGUnit), meta)
 }
 
+// DeleteMigratedBucketGVGUnits mocks base method.
+func (m *MockMigrateDB) DeleteMigratedBucketGVGUnits(expiredTimestampSecond int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMigratedBucketGVGUnits", expiredTimestampSecond, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMigratedBucketGVGUnits indicates an expected call of DeleteMigratedBucketGVGUnits.
+func (mr *MockMigrateDBMockRecorder) DeleteMigratedBucketGVGUnits(expiredTimestampSecond, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMigratedBucketGVGUnits", reflect.TypeOf((*MockMigrateDB)(nil).DeleteMigratedBucketGVGUnits), expiredTimestampSecond, limit)
+}
+
 // InsertMigrateGVGUnit mocks base method.
 func (m *MockMigrateDB) InsertMigrateGVGUnit(meta *MigrateGVGUnitMeta) error {
 	m.ctrl.T.Helper()
@@ -762,6 +1627,21 @@ func (mr *MockMigrateDBMockRecorder) ListDestSPSwapOutUnits() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDestSPSwapOutUnits", reflect.TypeOf((*MockMigrateDB)(nil).ListDestSPSwapOutUnits))
 }
 
+// ListMigrateGVGUnits mocks base method.
+func (m *MockMigrateDB) ListMigrateGVGUnits() ([]*MigrateGVGUnitMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMigrateGVGUnits")
+	ret0, _ := ret[0].([]*MigrateGVGUnitMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMigrateGVGUnits indicates an expected call of ListMigrateGVGUnits.
+func (mr *MockMigrateDBMockRecorder) ListMigrateGVGUnits() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMigrateGVGUnits", reflect.TypeOf((*MockMigrateDB)(nil).ListMigrateGVGUnits))
+}
+
 // ListMigrateGVGUnitsByBucketID mocks base method.
 func (m *MockMigrateDB) ListMigrateGVGUnitsByBucketID(bucketID uint64) ([]*MigrateGVGUnitMeta, error) {
 	m.ctrl.T.Helper()
@@ -987,6 +1867,136 @@ func (mr *MockSPDBMockRecorder) DeleteAllReplicatePieceChecksum(objectID, redund
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllReplicatePieceChecksum", reflect.TypeOf((*MockSPDB)(nil).DeleteAllReplicatePieceChecksum), objectID, redundancyIdx, pieceCount)
 }
 
+// DeleteBucketCORS mocks base method.
+func (m *MockSPDB) DeleteBucketCORS(bucketName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucketCORS", bucketName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBucketCORS indicates an expected call of DeleteBucketCORS.
+func (mr *MockSPDBMockRecorder) DeleteBucketCORS(bucketName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucketCORS", reflect.TypeOf((*MockSPDB)(nil).DeleteBucketCORS), bucketName)
+}
+
+// DeleteBucketEventSubscriptions mocks base method.
+func (m *MockSPDB) DeleteBucketEventSubscriptions(bucketName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBucketEventSubscriptions", bucketName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBucketEventSubscriptions indicates an expected call of DeleteBucketEventSubscriptions.
+func (mr *MockSPDBMockRecorder) DeleteBucketEventSubscriptions(bucketName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBucketEventSubscriptions", reflect.TypeOf((*MockSPDB)(nil).DeleteBucketEventSubscriptions), bucketName)
+}
+
+// DeleteCompletedDestSPSwapOutUnits mocks base method.
+func (m *MockSPDB) DeleteCompletedDestSPSwapOutUnits(expiredTimestampSecond int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompletedDestSPSwapOutUnits", expiredTimestampSecond, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCompletedDestSPSwapOutUnits indicates an expected call of DeleteCompletedDestSPSwapOutUnits.
+func (mr *MockSPDBMockRecorder) DeleteCompletedDestSPSwapOutUnits(expiredTimestampSecond, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompletedDestSPSwapOutUnits", reflect.TypeOf((*MockSPDB)(nil).DeleteCompletedDestSPSwapOutUnits), expiredTimestampSecond, limit)
+}
+
+// DeleteEventDelivery mocks base method.
+func (m *MockSPDB) DeleteEventDelivery(deliveryID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventDelivery", deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEventDelivery indicates an expected call of DeleteEventDelivery.
+func (mr *MockSPDBMockRecorder) DeleteEventDelivery(deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventDelivery", reflect.TypeOf((*MockSPDB)(nil).DeleteEventDelivery), deliveryID)
+}
+
+// DeleteExpiredPutEvent mocks base method.
+func (m *MockSPDB) DeleteExpiredPutEvent(expiredTime time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredPutEvent", expiredTime, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredPutEvent indicates an expected call of DeleteExpiredPutEvent.
+func (mr *MockSPDBMockRecorder) DeleteExpiredPutEvent(expiredTime, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredPutEvent", reflect.TypeOf((*MockSPDB)(nil).DeleteExpiredPutEvent), expiredTime, limit)
+}
+
+// DeleteExpiredRateLimitCounters mocks base method.
+func (m *MockSPDB) DeleteExpiredRateLimitCounters(timestampSecond int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRateLimitCounters", timestampSecond)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredRateLimitCounters indicates an expected call of DeleteExpiredRateLimitCounters.
+func (mr *MockSPDBMockRecorder) DeleteExpiredRateLimitCounters(timestampSecond interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRateLimitCounters", reflect.TypeOf((*MockSPDB)(nil).DeleteExpiredRateLimitCounters), timestampSecond)
+}
+
+// DeleteExpiredReadRecord mocks base method.
+func (m *MockSPDB) DeleteExpiredReadRecord(expiredTimestampUs int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredReadRecord", expiredTimestampUs, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredReadRecord indicates an expected call of DeleteExpiredReadRecord.
+func (mr *MockSPDBMockRecorder) DeleteExpiredReadRecord(expiredTimestampUs, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredReadRecord", reflect.TypeOf((*MockSPDB)(nil).DeleteExpiredReadRecord), expiredTimestampUs, limit)
+}
+
+// DeleteExpiredReadUsage mocks base method.
+func (m *MockSPDB) DeleteExpiredReadUsage(expiredTimestampUs int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredReadUsage", expiredTimestampUs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredReadUsage indicates an expected call of DeleteExpiredReadUsage.
+func (mr *MockSPDBMockRecorder) DeleteExpiredReadUsage(expiredTimestampUs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredReadUsage", reflect.TypeOf((*MockSPDB)(nil).DeleteExpiredReadUsage), expiredTimestampUs)
+}
+
+// DeleteExpiredUploadProgress mocks base method.
+func (m *MockSPDB) DeleteExpiredUploadProgress(expiredTimestampSecond int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredUploadProgress", expiredTimestampSecond, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredUploadProgress indicates an expected call of DeleteExpiredUploadProgress.
+func (mr *MockSPDBMockRecorder) DeleteExpiredUploadProgress(expiredTimestampSecond, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredUploadProgress", reflect.TypeOf((*MockSPDB)(nil).DeleteExpiredUploadProgress), expiredTimestampSecond, limit)
+}
+
 // DeleteGCObjectProgress mocks base method.
 func (m *MockSPDB) DeleteGCObjectProgress(taskKey string) error {
 	m.ctrl.T.Helper()
@@ -1015,6 +2025,35 @@ func (mr *MockSPDBMockRecorder) DeleteMigrateGVGUnit(meta interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMigrateGVGUnit", reflect.TypeOf((*MockSPDB)(nil).DeleteMigrateGVGUnit), meta)
 }
 
+// DeleteMigratedBucketGVGUnits mocks base method.
+func (m *MockSPDB) DeleteMigratedBucketGVGUnits(expiredTimestampSecond int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMigratedBucketGVGUnits", expiredTimestampSecond, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMigratedBucketGVGUnits indicates an expected call of DeleteMigratedBucketGVGUnits.
+func (mr *MockSPDBMockRecorder) DeleteMigratedBucketGVGUnits(expiredTimestampSecond, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMigratedBucketGVGUnits", reflect.TypeOf((*MockSPDB)(nil).DeleteMigratedBucketGVGUnits), expiredTimestampSecond, limit)
+}
+
+// DeleteMultipartUploadParts mocks base method.
+func (m *MockSPDB) DeleteMultipartUploadParts(objectID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMultipartUploadParts", objectID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMultipartUploadParts indicates an expected call of DeleteMultipartUploadParts.
+func (mr *MockSPDBMockRecorder) DeleteMultipartUploadParts(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMultipartUploadParts", reflect.TypeOf((*MockSPDB)(nil).DeleteMultipartUploadParts), objectID)
+}
+
 // DeleteObjectIntegrity mocks base method.
 func (m *MockSPDB) DeleteObjectIntegrity(objectID uint64, redundancyIndex int32) error {
 	m.ctrl.T.Helper()
@@ -1029,6 +2068,63 @@ func (mr *MockSPDBMockRecorder) DeleteObjectIntegrity(objectID, redundancyIndex
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteObjectIntegrity", reflect.TypeOf((*MockSPDB)(nil).DeleteObjectIntegrity), objectID, redundancyIndex)
 }
 
+// DeleteQueueTask mocks base method.
+func (m *MockSPDB) DeleteQueueTask(queueName, taskKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQueueTask", queueName, taskKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteQueueTask indicates an expected call of DeleteQueueTask.
+func (mr *MockSPDBMockRecorder) DeleteQueueTask(queueName, taskKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQueueTask", reflect.TypeOf((*MockSPDB)(nil).DeleteQueueTask), queueName, taskKey)
+}
+
+// DeleteReplicatePieceChecksumByObjectIDs mocks base method.
+func (m *MockSPDB) DeleteReplicatePieceChecksumByObjectIDs(objectIDs []uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReplicatePieceChecksumByObjectIDs", objectIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteReplicatePieceChecksumByObjectIDs indicates an expected call of DeleteReplicatePieceChecksumByObjectIDs.
+func (mr *MockSPDBMockRecorder) DeleteReplicatePieceChecksumByObjectIDs(objectIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicatePieceChecksumByObjectIDs", reflect.TypeOf((*MockSPDB)(nil).DeleteReplicatePieceChecksumByObjectIDs), objectIDs)
+}
+
+// DeleteS3AccessKey mocks base method.
+func (m *MockSPDB) DeleteS3AccessKey(accessKeyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteS3AccessKey", accessKeyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteS3AccessKey indicates an expected call of DeleteS3AccessKey.
+func (mr *MockSPDBMockRecorder) DeleteS3AccessKey(accessKeyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteS3AccessKey", reflect.TypeOf((*MockSPDB)(nil).DeleteS3AccessKey), accessKeyID)
+}
+
+// DeleteScrubPieces mocks base method.
+func (m *MockSPDB) DeleteScrubPieces(gvgID uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScrubPieces", gvgID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScrubPieces indicates an expected call of DeleteScrubPieces.
+func (mr *MockSPDBMockRecorder) DeleteScrubPieces(gvgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScrubPieces", reflect.TypeOf((*MockSPDB)(nil).DeleteScrubPieces), gvgID)
+}
+
 // DeleteUploadProgress mocks base method.
 func (m *MockSPDB) DeleteUploadProgress(objectID uint64) error {
 	m.ctrl.T.Helper()
@@ -1096,19 +2192,49 @@ func (mr *MockSPDBMockRecorder) GetAllReplicatePieceChecksum(objectID, redundanc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllReplicatePieceChecksum", reflect.TypeOf((*MockSPDB)(nil).GetAllReplicatePieceChecksum), objectID, redundancyIdx, pieceCount)
 }
 
-// GetAuthKey mocks base method.
-func (m *MockSPDB) GetAuthKey(userAddress, domain string) (*OffChainAuthKey, error) {
+// GetAuthKey mocks base method.
+func (m *MockSPDB) GetAuthKey(userAddress, domain string) (*OffChainAuthKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthKey", userAddress, domain)
+	ret0, _ := ret[0].(*OffChainAuthKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthKey indicates an expected call of GetAuthKey.
+func (mr *MockSPDBMockRecorder) GetAuthKey(userAddress, domain interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthKey", reflect.TypeOf((*MockSPDB)(nil).GetAuthKey), userAddress, domain)
+}
+
+// GetBucketCORS mocks base method.
+func (m *MockSPDB) GetBucketCORS(bucketName string) (*BucketCORS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketCORS", bucketName)
+	ret0, _ := ret[0].(*BucketCORS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketCORS indicates an expected call of GetBucketCORS.
+func (mr *MockSPDBMockRecorder) GetBucketCORS(bucketName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketCORS", reflect.TypeOf((*MockSPDB)(nil).GetBucketCORS), bucketName)
+}
+
+// GetBucketEventSubscriptions mocks base method.
+func (m *MockSPDB) GetBucketEventSubscriptions(bucketName string) ([]*EventSubscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAuthKey", userAddress, domain)
-	ret0, _ := ret[0].(*OffChainAuthKey)
+	ret := m.ctrl.Call(m, "GetBucketEventSubscriptions", bucketName)
+	ret0, _ := ret[0].([]*EventSubscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAuthKey indicates an expected call of GetAuthKey.
-func (mr *MockSPDBMockRecorder) GetAuthKey(userAddress, domain interface{}) *gomock.Call {
+// GetBucketEventSubscriptions indicates an expected call of GetBucketEventSubscriptions.
+func (mr *MockSPDBMockRecorder) GetBucketEventSubscriptions(bucketName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthKey", reflect.TypeOf((*MockSPDB)(nil).GetAuthKey), userAddress, domain)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketEventSubscriptions", reflect.TypeOf((*MockSPDB)(nil).GetBucketEventSubscriptions), bucketName)
 }
 
 // GetBucketReadRecord mocks base method.
@@ -1141,6 +2267,21 @@ func (mr *MockSPDBMockRecorder) GetBucketTraffic(bucketID interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketTraffic", reflect.TypeOf((*MockSPDB)(nil).GetBucketTraffic), bucketID)
 }
 
+// GetDueEventDeliveries mocks base method.
+func (m *MockSPDB) GetDueEventDeliveries(timestampSecond int64, limit int) ([]*EventDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueEventDeliveries", timestampSecond, limit)
+	ret0, _ := ret[0].([]*EventDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueEventDeliveries indicates an expected call of GetDueEventDeliveries.
+func (mr *MockSPDBMockRecorder) GetDueEventDeliveries(timestampSecond, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueEventDeliveries", reflect.TypeOf((*MockSPDB)(nil).GetDueEventDeliveries), timestampSecond, limit)
+}
+
 // GetGCMetasToGC mocks base method.
 func (m *MockSPDB) GetGCMetasToGC(limit int) ([]*GCObjectMeta, error) {
 	m.ctrl.T.Helper()
@@ -1201,6 +2342,21 @@ func (mr *MockSPDBMockRecorder) GetOwnSpInfo() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwnSpInfo", reflect.TypeOf((*MockSPDB)(nil).GetOwnSpInfo))
 }
 
+// GetReadQuotaAlertPercent mocks base method.
+func (m *MockSPDB) GetReadQuotaAlertPercent(bucketID uint64) (uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadQuotaAlertPercent", bucketID)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReadQuotaAlertPercent indicates an expected call of GetReadQuotaAlertPercent.
+func (mr *MockSPDBMockRecorder) GetReadQuotaAlertPercent(bucketID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadQuotaAlertPercent", reflect.TypeOf((*MockSPDB)(nil).GetReadQuotaAlertPercent), bucketID)
+}
+
 // GetReadRecord mocks base method.
 func (m *MockSPDB) GetReadRecord(timeRange *TrafficTimeRange) ([]*ReadRecord, error) {
 	m.ctrl.T.Helper()
@@ -1216,6 +2372,36 @@ func (mr *MockSPDBMockRecorder) GetReadRecord(timeRange interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadRecord", reflect.TypeOf((*MockSPDB)(nil).GetReadRecord), timeRange)
 }
 
+// GetReadUsage mocks base method.
+func (m *MockSPDB) GetReadUsage(query *ReadUsageQuery) ([]*ReadUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadUsage", query)
+	ret0, _ := ret[0].([]*ReadUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReadUsage indicates an expected call of GetReadUsage.
+func (mr *MockSPDBMockRecorder) GetReadUsage(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadUsage", reflect.TypeOf((*MockSPDB)(nil).GetReadUsage), query)
+}
+
+// GetS3AccessKey mocks base method.
+func (m *MockSPDB) GetS3AccessKey(accessKeyID string) (*S3AccessKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetS3AccessKey", accessKeyID)
+	ret0, _ := ret[0].(*S3AccessKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetS3AccessKey indicates an expected call of GetS3AccessKey.
+func (mr *MockSPDBMockRecorder) GetS3AccessKey(accessKeyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetS3AccessKey", reflect.TypeOf((*MockSPDB)(nil).GetS3AccessKey), accessKeyID)
+}
+
 // GetSpByAddress mocks base method.
 func (m *MockSPDB) GetSpByAddress(address string, addressType SpAddressType) (*types0.StorageProvider, error) {
 	m.ctrl.T.Helper()
@@ -1307,6 +2493,21 @@ func (mr *MockSPDBMockRecorder) GetUserReadRecord(userAddress, timeRange interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReadRecord", reflect.TypeOf((*MockSPDB)(nil).GetUserReadRecord), userAddress, timeRange)
 }
 
+// IncreaseRateLimitCounters mocks base method.
+func (m *MockSPDB) IncreaseRateLimitCounters(counters []*RateLimitCounter) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseRateLimitCounters", counters)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncreaseRateLimitCounters indicates an expected call of IncreaseRateLimitCounters.
+func (mr *MockSPDBMockRecorder) IncreaseRateLimitCounters(counters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseRateLimitCounters", reflect.TypeOf((*MockSPDB)(nil).IncreaseRateLimitCounters), counters)
+}
+
 // InitBucketTraffic mocks base method.
 func (m *MockSPDB) InitBucketTraffic(bucketID uint64, bucketName string, quota *BucketQuota) error {
 	m.ctrl.T.Helper()
@@ -1335,6 +2536,20 @@ func (mr *MockSPDBMockRecorder) InsertAuthKey(newRecord interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuthKey", reflect.TypeOf((*MockSPDB)(nil).InsertAuthKey), newRecord)
 }
 
+// InsertEventDeliveries mocks base method.
+func (m *MockSPDB) InsertEventDeliveries(deliveries []*EventDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEventDeliveries", deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertEventDeliveries indicates an expected call of InsertEventDeliveries.
+func (mr *MockSPDBMockRecorder) InsertEventDeliveries(deliveries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEventDeliveries", reflect.TypeOf((*MockSPDB)(nil).InsertEventDeliveries), deliveries)
+}
+
 // InsertGCObjectProgress mocks base method.
 func (m *MockSPDB) InsertGCObjectProgress(taskKey string, gcMeta *GCObjectMeta) error {
 	m.ctrl.T.Helper()
@@ -1377,6 +2592,20 @@ func (mr *MockSPDBMockRecorder) InsertPutEvent(task interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPutEvent", reflect.TypeOf((*MockSPDB)(nil).InsertPutEvent), task)
 }
 
+// InsertS3AccessKey mocks base method.
+func (m *MockSPDB) InsertS3AccessKey(key *S3AccessKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertS3AccessKey", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertS3AccessKey indicates an expected call of InsertS3AccessKey.
+func (mr *MockSPDBMockRecorder) InsertS3AccessKey(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertS3AccessKey", reflect.TypeOf((*MockSPDB)(nil).InsertS3AccessKey), key)
+}
+
 // InsertSwapOutUnit mocks base method.
 func (m *MockSPDB) InsertSwapOutUnit(meta *SwapOutMeta) error {
 	m.ctrl.T.Helper()
@@ -1405,6 +2634,35 @@ func (mr *MockSPDBMockRecorder) InsertUploadProgress(objectID interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUploadProgress", reflect.TypeOf((*MockSPDB)(nil).InsertUploadProgress), objectID)
 }
 
+// LeaseQueueTask mocks base method.
+func (m *MockSPDB) LeaseQueueTask(queueName, taskKey string, leaseTimestampSecond int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaseQueueTask", queueName, taskKey, leaseTimestampSecond)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeaseQueueTask indicates an expected call of LeaseQueueTask.
+func (mr *MockSPDBMockRecorder) LeaseQueueTask(queueName, taskKey, leaseTimestampSecond interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaseQueueTask", reflect.TypeOf((*MockSPDB)(nil).LeaseQueueTask), queueName, taskKey, leaseTimestampSecond)
+}
+
+// ListDeadLetterEventDeliveries mocks base method.
+func (m *MockSPDB) ListDeadLetterEventDeliveries(bucketName string, limit int) ([]*EventDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetterEventDeliveries", bucketName, limit)
+	ret0, _ := ret[0].([]*EventDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetterEventDeliveries indicates an expected call of ListDeadLetterEventDeliveries.
+func (mr *MockSPDBMockRecorder) ListDeadLetterEventDeliveries(bucketName, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetterEventDeliveries", reflect.TypeOf((*MockSPDB)(nil).ListDeadLetterEventDeliveries), bucketName, limit)
+}
+
 // ListDestSPSwapOutUnits mocks base method.
 func (m *MockSPDB) ListDestSPSwapOutUnits() ([]*SwapOutMeta, error) {
 	m.ctrl.T.Helper()
@@ -1420,6 +2678,21 @@ func (mr *MockSPDBMockRecorder) ListDestSPSwapOutUnits() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDestSPSwapOutUnits", reflect.TypeOf((*MockSPDB)(nil).ListDestSPSwapOutUnits))
 }
 
+// ListMigrateGVGUnits mocks base method.
+func (m *MockSPDB) ListMigrateGVGUnits() ([]*MigrateGVGUnitMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMigrateGVGUnits")
+	ret0, _ := ret[0].([]*MigrateGVGUnitMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMigrateGVGUnits indicates an expected call of ListMigrateGVGUnits.
+func (mr *MockSPDBMockRecorder) ListMigrateGVGUnits() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMigrateGVGUnits", reflect.TypeOf((*MockSPDB)(nil).ListMigrateGVGUnits))
+}
+
 // ListMigrateGVGUnitsByBucketID mocks base method.
 func (m *MockSPDB) ListMigrateGVGUnitsByBucketID(bucketID uint64) ([]*MigrateGVGUnitMeta, error) {
 	m.ctrl.T.Helper()
@@ -1435,6 +2708,110 @@ func (mr *MockSPDBMockRecorder) ListMigrateGVGUnitsByBucketID(bucketID interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMigrateGVGUnitsByBucketID", reflect.TypeOf((*MockSPDB)(nil).ListMigrateGVGUnitsByBucketID), bucketID)
 }
 
+// ListMultipartUploadParts mocks base method.
+func (m *MockSPDB) ListMultipartUploadParts(objectID uint64) ([]*MultipartUploadPart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMultipartUploadParts", objectID)
+	ret0, _ := ret[0].([]*MultipartUploadPart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMultipartUploadParts indicates an expected call of ListMultipartUploadParts.
+func (mr *MockSPDBMockRecorder) ListMultipartUploadParts(objectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMultipartUploadParts", reflect.TypeOf((*MockSPDB)(nil).ListMultipartUploadParts), objectID)
+}
+
+// ListMultipartUploads mocks base method.
+func (m *MockSPDB) ListMultipartUploads(startAfterObjectID uint64, limit int) ([]*MultipartUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMultipartUploads", startAfterObjectID, limit)
+	ret0, _ := ret[0].([]*MultipartUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMultipartUploads indicates an expected call of ListMultipartUploads.
+func (mr *MockSPDBMockRecorder) ListMultipartUploads(startAfterObjectID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMultipartUploads", reflect.TypeOf((*MockSPDB)(nil).ListMultipartUploads), startAfterObjectID, limit)
+}
+
+// ListQueueTasks mocks base method.
+func (m *MockSPDB) ListQueueTasks(queueName string) ([]*TaskQueueMeta, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQueueTasks", queueName)
+	ret0, _ := ret[0].([]*TaskQueueMeta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQueueTasks indicates an expected call of ListQueueTasks.
+func (mr *MockSPDBMockRecorder) ListQueueTasks(queueName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQueueTasks", reflect.TypeOf((*MockSPDB)(nil).ListQueueTasks), queueName)
+}
+
+// ListReplicatePieceChecksumObjectIDs mocks base method.
+func (m *MockSPDB) ListReplicatePieceChecksumObjectIDs(startAfter uint64, limit int) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReplicatePieceChecksumObjectIDs", startAfter, limit)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReplicatePieceChecksumObjectIDs indicates an expected call of ListReplicatePieceChecksumObjectIDs.
+func (mr *MockSPDBMockRecorder) ListReplicatePieceChecksumObjectIDs(startAfter, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReplicatePieceChecksumObjectIDs", reflect.TypeOf((*MockSPDB)(nil).ListReplicatePieceChecksumObjectIDs), startAfter, limit)
+}
+
+// ListScrubPieces mocks base method.
+func (m *MockSPDB) ListScrubPieces(gvgID uint32, limit int) ([]*ScrubPiece, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScrubPieces", gvgID, limit)
+	ret0, _ := ret[0].([]*ScrubPiece)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScrubPieces indicates an expected call of ListScrubPieces.
+func (mr *MockSPDBMockRecorder) ListScrubPieces(gvgID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScrubPieces", reflect.TypeOf((*MockSPDB)(nil).ListScrubPieces), gvgID, limit)
+}
+
+// ListScrubProgresses mocks base method.
+func (m *MockSPDB) ListScrubProgresses() ([]*ScrubProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScrubProgresses")
+	ret0, _ := ret[0].([]*ScrubProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScrubProgresses indicates an expected call of ListScrubProgresses.
+func (mr *MockSPDBMockRecorder) ListScrubProgresses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScrubProgresses", reflect.TypeOf((*MockSPDB)(nil).ListScrubProgresses))
+}
+
+// MoveEventDeliveryToDeadLetter mocks base method.
+func (m *MockSPDB) MoveEventDeliveryToDeadLetter(delivery *EventDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveEventDeliveryToDeadLetter", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveEventDeliveryToDeadLetter indicates an expected call of MoveEventDeliveryToDeadLetter.
+func (mr *MockSPDBMockRecorder) MoveEventDeliveryToDeadLetter(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveEventDeliveryToDeadLetter", reflect.TypeOf((*MockSPDB)(nil).MoveEventDeliveryToDeadLetter), delivery)
+}
+
 // QueryBucketMigrateSubscribeProgress mocks base method.
 func (m *MockSPDB) QueryBucketMigrateSubscribeProgress() (uint64, error) {
 	m.ctrl.T.Helper()
@@ -1450,6 +2827,36 @@ func (mr *MockSPDBMockRecorder) QueryBucketMigrateSubscribeProgress() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBucketMigrateSubscribeProgress", reflect.TypeOf((*MockSPDB)(nil).QueryBucketMigrateSubscribeProgress))
 }
 
+// QueryGCMetaProgress mocks base method.
+func (m *MockSPDB) QueryGCMetaProgress() (*GCMetaProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryGCMetaProgress")
+	ret0, _ := ret[0].(*GCMetaProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryGCMetaProgress indicates an expected call of QueryGCMetaProgress.
+func (mr *MockSPDBMockRecorder) QueryGCMetaProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryGCMetaProgress", reflect.TypeOf((*MockSPDB)(nil).QueryGCMetaProgress))
+}
+
+// QueryGCZombieProgress mocks base method.
+func (m *MockSPDB) QueryGCZombieProgress() (*GCZombieProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryGCZombieProgress")
+	ret0, _ := ret[0].(*GCZombieProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryGCZombieProgress indicates an expected call of QueryGCZombieProgress.
+func (mr *MockSPDBMockRecorder) QueryGCZombieProgress() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryGCZombieProgress", reflect.TypeOf((*MockSPDB)(nil).QueryGCZombieProgress))
+}
+
 // QueryMigrateGVGUnit mocks base method.
 func (m *MockSPDB) QueryMigrateGVGUnit(migrateKey string) (*MigrateGVGUnitMeta, error) {
 	m.ctrl.T.Helper()
@@ -1480,6 +2887,21 @@ func (mr *MockSPDBMockRecorder) QuerySPExitSubscribeProgress() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySPExitSubscribeProgress", reflect.TypeOf((*MockSPDB)(nil).QuerySPExitSubscribeProgress))
 }
 
+// QueryScrubProgress mocks base method.
+func (m *MockSPDB) QueryScrubProgress(gvgID uint32) (*ScrubProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryScrubProgress", gvgID)
+	ret0, _ := ret[0].(*ScrubProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryScrubProgress indicates an expected call of QueryScrubProgress.
+func (mr *MockSPDBMockRecorder) QueryScrubProgress(gvgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryScrubProgress", reflect.TypeOf((*MockSPDB)(nil).QueryScrubProgress), gvgID)
+}
+
 // QuerySwapOutSubscribeProgress mocks base method.
 func (m *MockSPDB) QuerySwapOutSubscribeProgress() (uint64, error) {
 	m.ctrl.T.Helper()
@@ -1510,6 +2932,20 @@ func (mr *MockSPDBMockRecorder) QuerySwapOutUnitInSrcSP(swapOutKey interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySwapOutUnitInSrcSP", reflect.TypeOf((*MockSPDB)(nil).QuerySwapOutUnitInSrcSP), swapOutKey)
 }
 
+// RollupReadRecord mocks base method.
+func (m *MockSPDB) RollupReadRecord(startTimestampUs, endTimestampUs int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupReadRecord", startTimestampUs, endTimestampUs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupReadRecord indicates an expected call of RollupReadRecord.
+func (mr *MockSPDBMockRecorder) RollupReadRecord(startTimestampUs, endTimestampUs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupReadRecord", reflect.TypeOf((*MockSPDB)(nil).RollupReadRecord), startTimestampUs, endTimestampUs)
+}
+
 // SetObjectIntegrity mocks base method.
 func (m *MockSPDB) SetObjectIntegrity(integrity *IntegrityMeta) error {
 	m.ctrl.T.Helper()
@@ -1580,6 +3016,34 @@ func (mr *MockSPDBMockRecorder) UpdateAuthKey(userAddress, domain, oldNonce, new
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthKey", reflect.TypeOf((*MockSPDB)(nil).UpdateAuthKey), userAddress, domain, oldNonce, newNonce, newPublicKey, newExpiryDate)
 }
 
+// UpdateBucketCORS mocks base method.
+func (m *MockSPDB) UpdateBucketCORS(cors *BucketCORS) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBucketCORS", cors)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBucketCORS indicates an expected call of UpdateBucketCORS.
+func (mr *MockSPDBMockRecorder) UpdateBucketCORS(cors interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBucketCORS", reflect.TypeOf((*MockSPDB)(nil).UpdateBucketCORS), cors)
+}
+
+// UpdateBucketEventSubscriptions mocks base method.
+func (m *MockSPDB) UpdateBucketEventSubscriptions(bucketName string, subscriptions []*EventSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBucketEventSubscriptions", bucketName, subscriptions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBucketEventSubscriptions indicates an expected call of UpdateBucketEventSubscriptions.
+func (mr *MockSPDBMockRecorder) UpdateBucketEventSubscriptions(bucketName, subscriptions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBucketEventSubscriptions", reflect.TypeOf((*MockSPDB)(nil).UpdateBucketEventSubscriptions), bucketName, subscriptions)
+}
+
 // UpdateBucketMigrateSubscribeProgress mocks base method.
 func (m *MockSPDB) UpdateBucketMigrateSubscribeProgress(blockHeight uint64) error {
 	m.ctrl.T.Helper()
@@ -1594,6 +3058,34 @@ func (mr *MockSPDBMockRecorder) UpdateBucketMigrateSubscribeProgress(blockHeight
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBucketMigrateSubscribeProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateBucketMigrateSubscribeProgress), blockHeight)
 }
 
+// UpdateEventDeliveryAttempt mocks base method.
+func (m *MockSPDB) UpdateEventDeliveryAttempt(delivery *EventDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEventDeliveryAttempt", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEventDeliveryAttempt indicates an expected call of UpdateEventDeliveryAttempt.
+func (mr *MockSPDBMockRecorder) UpdateEventDeliveryAttempt(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEventDeliveryAttempt", reflect.TypeOf((*MockSPDB)(nil).UpdateEventDeliveryAttempt), delivery)
+}
+
+// UpdateGCMetaProgress mocks base method.
+func (m *MockSPDB) UpdateGCMetaProgress(gcMeta *GCMetaProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGCMetaProgress", gcMeta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGCMetaProgress indicates an expected call of UpdateGCMetaProgress.
+func (mr *MockSPDBMockRecorder) UpdateGCMetaProgress(gcMeta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGCMetaProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateGCMetaProgress), gcMeta)
+}
+
 // UpdateGCObjectProgress mocks base method.
 func (m *MockSPDB) UpdateGCObjectProgress(gcMeta *GCObjectMeta) error {
 	m.ctrl.T.Helper()
@@ -1608,6 +3100,20 @@ func (mr *MockSPDBMockRecorder) UpdateGCObjectProgress(gcMeta interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGCObjectProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateGCObjectProgress), gcMeta)
 }
 
+// UpdateGCZombieProgress mocks base method.
+func (m *MockSPDB) UpdateGCZombieProgress(progress *GCZombieProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGCZombieProgress", progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGCZombieProgress indicates an expected call of UpdateGCZombieProgress.
+func (mr *MockSPDBMockRecorder) UpdateGCZombieProgress(progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGCZombieProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateGCZombieProgress), progress)
+}
+
 // UpdateIntegrityChecksum mocks base method.
 func (m *MockSPDB) UpdateIntegrityChecksum(integrity *IntegrityMeta) error {
 	m.ctrl.T.Helper()
@@ -1650,6 +3156,20 @@ func (mr *MockSPDBMockRecorder) UpdateMigrateGVGUnitStatus(migrateKey, migrateSt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMigrateGVGUnitStatus", reflect.TypeOf((*MockSPDB)(nil).UpdateMigrateGVGUnitStatus), migrateKey, migrateStatus)
 }
 
+// UpdateMultipartUploadPart mocks base method.
+func (m *MockSPDB) UpdateMultipartUploadPart(part *MultipartUploadPart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMultipartUploadPart", part)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMultipartUploadPart indicates an expected call of UpdateMultipartUploadPart.
+func (mr *MockSPDBMockRecorder) UpdateMultipartUploadPart(part interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMultipartUploadPart", reflect.TypeOf((*MockSPDB)(nil).UpdateMultipartUploadPart), part)
+}
+
 // UpdatePieceChecksum mocks base method.
 func (m *MockSPDB) UpdatePieceChecksum(objectID uint64, redundancyIndex int32, checksum []byte) error {
 	m.ctrl.T.Helper()
@@ -1664,6 +3184,34 @@ func (mr *MockSPDBMockRecorder) UpdatePieceChecksum(objectID, redundancyIndex, c
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePieceChecksum", reflect.TypeOf((*MockSPDB)(nil).UpdatePieceChecksum), objectID, redundancyIndex, checksum)
 }
 
+// UpdateQueueTask mocks base method.
+func (m *MockSPDB) UpdateQueueTask(meta *TaskQueueMeta) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQueueTask", meta)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateQueueTask indicates an expected call of UpdateQueueTask.
+func (mr *MockSPDBMockRecorder) UpdateQueueTask(meta interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQueueTask", reflect.TypeOf((*MockSPDB)(nil).UpdateQueueTask), meta)
+}
+
+// UpdateReadQuotaAlertPercent mocks base method.
+func (m *MockSPDB) UpdateReadQuotaAlertPercent(bucketID uint64, percent uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReadQuotaAlertPercent", bucketID, percent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReadQuotaAlertPercent indicates an expected call of UpdateReadQuotaAlertPercent.
+func (mr *MockSPDBMockRecorder) UpdateReadQuotaAlertPercent(bucketID, percent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReadQuotaAlertPercent", reflect.TypeOf((*MockSPDB)(nil).UpdateReadQuotaAlertPercent), bucketID, percent)
+}
+
 // UpdateSPExitSubscribeProgress mocks base method.
 func (m *MockSPDB) UpdateSPExitSubscribeProgress(blockHeight uint64) error {
 	m.ctrl.T.Helper()
@@ -1678,6 +3226,34 @@ func (mr *MockSPDBMockRecorder) UpdateSPExitSubscribeProgress(blockHeight interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSPExitSubscribeProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateSPExitSubscribeProgress), blockHeight)
 }
 
+// UpdateScrubPiece mocks base method.
+func (m *MockSPDB) UpdateScrubPiece(piece *ScrubPiece) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScrubPiece", piece)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScrubPiece indicates an expected call of UpdateScrubPiece.
+func (mr *MockSPDBMockRecorder) UpdateScrubPiece(piece interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScrubPiece", reflect.TypeOf((*MockSPDB)(nil).UpdateScrubPiece), piece)
+}
+
+// UpdateScrubProgress mocks base method.
+func (m *MockSPDB) UpdateScrubProgress(progress *ScrubProgress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScrubProgress", progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateScrubProgress indicates an expected call of UpdateScrubProgress.
+func (mr *MockSPDBMockRecorder) UpdateScrubProgress(progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScrubProgress", reflect.TypeOf((*MockSPDB)(nil).UpdateScrubProgress), progress)
+}
+
 // UpdateSwapOutSubscribeProgress mocks base method.
 func (m *MockSPDB) UpdateSwapOutSubscribeProgress(blockHeight uint64) error {
 	m.ctrl.T.Helper()
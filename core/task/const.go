@@ -52,6 +52,8 @@ const (
 	TypeTaskMigrateGVG
 	// TypeTaskMigratePiece defines the type of migrating piece task.
 	TypeTaskMigratePiece
+	// TypeTaskScrubGVG defines the type of scrubbing the pieces of gvg task.
+	TypeTaskScrubGVG
 )
 
 var TypeTaskMap = map[TType]string{
@@ -71,6 +73,7 @@ var TypeTaskMap = map[TType]string{
 	TypeTaskGCMeta:                 "GCMetaTask",
 	TypeTaskRecoverPiece:           "RecoverPieceTask",
 	TypeTaskMigrateGVG:             "MigrateGVGTask",
+	TypeTaskScrubGVG:               "ScrubGVGTask",
 }
 
 func TaskTypeName(taskType TType) string {
@@ -24,6 +24,7 @@ var _ GCZombiePieceTask = (*NullTask)(nil)
 var _ GCMetaTask = (*NullTask)(nil)
 var _ RecoveryPieceTask = (*NullTask)(nil)
 var _ MigrateGVGTask = (*NullTask)(nil)
+var _ ScrubGVGTask = (*NullTask)(nil)
 
 type NullTask struct{}
 
@@ -60,8 +61,16 @@ func (*NullTask) GetObjectInfo() *storagetypes.ObjectInfo
 func (*NullTask) SetObjectInfo(*storagetypes.ObjectInfo)                                        {}
 func (*NullTask) GetStorageParams() *storagetypes.Params                                        { return nil }
 func (*NullTask) SetStorageParams(*storagetypes.Params)                                         {}
+func (*NullTask) InitGCZombiePieceTask(TPriority, string, uint64, int64)                        {}
+func (*NullTask) GetStartAfter() string                                                         { return "" }
+func (*NullTask) GetLimit() uint64                                                              { return 0 }
+func (*NullTask) GetLastPieceKey() string                                                       { return "" }
+func (*NullTask) SetLastPieceKey(string)                                                        {}
 func (*NullTask) GetGCZombiePieceStatus() (uint64, uint64)                                      { return 0, 0 }
 func (*NullTask) SetGCZombiePieceStatus(uint64, uint64)                                         {}
+func (*NullTask) InitGCMetaTask(TPriority, uint64, uint64, int64)                               {}
+func (*NullTask) GetLastObjectId() uint64                                                       { return 0 }
+func (*NullTask) SetLastObjectId(uint64)                                                        {}
 func (*NullTask) GetGCMetaStatus() (uint64, uint64)                                             { return 0, 0 }
 func (*NullTask) SetGCMetaStatus(uint64, uint64)                                                {}
 func (*NullTask) InitApprovalCreateBucketTask(string, *storagetypes.MsgCreateBucket, TPriority) {}
@@ -162,3 +171,10 @@ func (*NullTask) GetLastMigratedObjectID() uint64                   { return 0 }
 func (*NullTask) SetLastMigratedObjectID(uint64)                    {}
 func (*NullTask) GetFinished() bool                                 { return false }
 func (*NullTask) SetFinished(bool)                                  {}
+
+func (*NullTask) InitScrubGVGTask(TPriority, uint32, int32, uint64, int64) {}
+func (*NullTask) GetGvgId() uint32                                         { return 0 }
+func (*NullTask) GetLastScrubbedObjectId() uint64                          { return 0 }
+func (*NullTask) SetLastScrubbedObjectId(uint64)                           {}
+func (*NullTask) GetScrubStatus() (uint64, uint64, uint64, uint64)         { return 0, 0, 0, 0 }
+func (*NullTask) SetScrubStatus(uint64, uint64, uint64, uint64)            {}
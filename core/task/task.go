@@ -488,6 +488,22 @@ type GCObjectTask interface {
 // the piece data meta is not on chain but the pieces has been store in piece store.
 type GCZombiePieceTask interface {
 	GCTask
+	// InitGCZombiePieceTask inits InitGCZombiePieceTask, the task scans at most limit
+	// pieces from piece store whose key is after startAfter.
+	InitGCZombiePieceTask(priority TPriority, startAfter string, limit uint64, timeout int64)
+	// GetStartAfter returns the piece key that the task starts to scan after.
+	GetStartAfter() string
+	// GetLimit returns the max number of pieces to scan in the task.
+	GetLimit() uint64
+	// GetLastPieceKey returns the last scanned piece key, empty if the task reaches the
+	// end of piece store.
+	GetLastPieceKey() string
+	// SetLastPieceKey sets the last scanned piece key.
+	SetLastPieceKey(string)
+	// GetFinished returns whether the task has scanned all the pieces.
+	GetFinished() bool
+	// SetFinished sets the task has scanned all the pieces.
+	SetFinished(bool)
 	// GetGCZombiePieceStatus returns the status of collecting zombie pieces, returns
 	// the last deleted object id and the number that has been deleted.
 	GetGCZombiePieceStatus() (uint64, uint64)
@@ -500,11 +516,22 @@ type GCZombiePieceTask interface {
 // meta store space by deleting the expired data.
 type GCMetaTask interface {
 	GCTask
-	// GetGCMetaStatus returns the status of collecting metadata, returns the last
-	// deleted object id and the number that has been deleted.
+	// InitGCMetaTask inits InitGCMetaTask, the task starts to collect the metadata from
+	// the currentIdx unit and the object id after lastObjectID.
+	InitGCMetaTask(priority TPriority, currentIdx uint64, lastObjectID uint64, timeout int64)
+	// GetLastObjectId returns the last checked object id of the current collecting unit.
+	GetLastObjectId() uint64
+	// SetLastObjectId sets the last checked object id of the current collecting unit.
+	SetLastObjectId(uint64)
+	// GetFinished returns whether the task has collected all the metadata units.
+	GetFinished() bool
+	// SetFinished sets the task has collected all the metadata units.
+	SetFinished(bool)
+	// GetGCMetaStatus returns the status of collecting metadata, returns the current
+	// collecting unit index and the number that has been deleted.
 	GetGCMetaStatus() (uint64, uint64)
-	// SetGCMetaStatus sets the status of collecting metadata, parma stands the last
-	// deleted object id and the number that has been deleted.
+	// SetGCMetaStatus sets the status of collecting metadata, parma stands the current
+	// collecting unit index and the number that has been deleted.
 	SetGCMetaStatus(uint64, uint64)
 }
 
@@ -567,3 +594,32 @@ type MigrateGVGTask interface {
 	// SetFinished sets the migrated gvg task status when finished
 	SetFinished(bool)
 }
+
+// ScrubGVGTask is an abstract interface to record the information for scrubbing the pieces
+// that are stored by the SP for the global virtual group, the pieces are read again from
+// the piece store and checked against the piece checksums of the integrity meta.
+type ScrubGVGTask interface {
+	Task
+	// InitScrubGVGTask inits the ScrubGVGTask, the task scrubs the pieces of the redundancyIdx
+	// in the gvg from the object after lastScrubbedObjectID, redundancyIdx is -1 if the SP is
+	// the primary SP of the gvg.
+	InitScrubGVGTask(priority TPriority, gvgID uint32, redundancyIdx int32, lastScrubbedObjectID uint64, timeout int64)
+	// GetGvgId returns the id of the scrubbed gvg.
+	GetGvgId() uint32
+	// GetRedundancyIdx returns the redundancy index of the SP in the gvg.
+	GetRedundancyIdx() int32
+	// GetLastScrubbedObjectId returns the last scrubbed object id.
+	GetLastScrubbedObjectId() uint64
+	// SetLastScrubbedObjectId sets the last scrubbed object id.
+	SetLastScrubbedObjectId(uint64)
+	// GetFinished returns whether the task has scrubbed all the objects of the gvg.
+	GetFinished() bool
+	// SetFinished sets the task has scrubbed all the objects of the gvg.
+	SetFinished(bool)
+	// GetScrubStatus returns the status of scrubbing, returns the number of scrubbed objects,
+	// scrubbed pieces, corrupted pieces and missing pieces.
+	GetScrubStatus() (uint64, uint64, uint64, uint64)
+	// SetScrubStatus sets the status of scrubbing, params stand the number of scrubbed objects,
+	// scrubbed pieces, corrupted pieces and missing pieces.
+	SetScrubStatus(uint64, uint64, uint64, uint64)
+}
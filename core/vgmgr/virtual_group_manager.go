@@ -27,6 +27,35 @@ type VirtualGroupFamilyMeta struct {
 	GVGMap                   map[uint32]*GlobalVirtualGroupMeta
 }
 
+// PickVirtualGroupFamilyRequest defines the bucket which the virtual group family is picked for.
+type PickVirtualGroupFamilyRequest struct {
+	BucketName string
+	Owner      string
+}
+
+// VirtualGroupStats provides the statistics observed by sp, which are used by the pick policies.
+type VirtualGroupStats interface {
+	// FamilyBucketCount returns the number of the buckets in the virtual group family.
+	FamilyBucketCount(vgfID uint32) uint64
+	// SPHealthScore returns the health score in [0, 1] of the secondary sp which is scored by its replicate
+	// failure rate and slow call rate, ok is false if no replicate to the sp is observed.
+	SPHealthScore(spID uint32) (score float64, ok bool)
+}
+
+// VirtualGroupPickPolicy is used to pick a virtual group family for creating bucket and a global virtual group
+// for replicating object. The candidates have enough free staking storage size, the policy returns an error if
+// none of them is suitable, then a new global virtual group is created by primary SP.
+type VirtualGroupPickPolicy interface {
+	// Name returns the name of the policy.
+	Name() string
+	// PickVirtualGroupFamily picks a virtual group family from the candidates for the bucket.
+	PickVirtualGroupFamily(req *PickVirtualGroupFamilyRequest, candidates []*VirtualGroupFamilyMeta,
+		stats VirtualGroupStats) (*VirtualGroupFamilyMeta, error)
+	// PickGlobalVirtualGroup picks a global virtual group from the candidates of the virtual group family.
+	PickGlobalVirtualGroup(vgf *VirtualGroupFamilyMeta, candidates []*GlobalVirtualGroupMeta,
+		stats VirtualGroupStats) (*GlobalVirtualGroupMeta, error)
+}
+
 // SPPickFilter is used to check sp pick condition.
 type SPPickFilter interface {
 	// Check returns true when match pick request condition.
@@ -53,7 +82,7 @@ type GenerateGVGSecondarySPsPolicy interface {
 type VirtualGroupManager interface {
 	// PickVirtualGroupFamily pick a virtual group family(If failed to pick,
 	// new VGF will be automatically created on the chain) in get create bucket approval workflow.
-	PickVirtualGroupFamily() (*VirtualGroupFamilyMeta, error)
+	PickVirtualGroupFamily(req *PickVirtualGroupFamilyRequest) (*VirtualGroupFamilyMeta, error)
 	// PickGlobalVirtualGroup picks a global virtual group(If failed to pick,
 	// new GVG will be created by primary SP) in replicate/seal object workflow.
 	PickGlobalVirtualGroup(vgfID uint32) (*GlobalVirtualGroupMeta, error)
@@ -72,4 +101,5 @@ type VirtualGroupManager interface {
 }
 
 // NewVirtualGroupManager is the virtual group manager init api.
-type NewVirtualGroupManager = func(selfOperatorAddress string, chainClient consensus.Consensus) (VirtualGroupManager, error)
+type NewVirtualGroupManager = func(selfOperatorAddress string, chainClient consensus.Consensus,
+	policy VirtualGroupPickPolicy, stats VirtualGroupStats) (VirtualGroupManager, error)
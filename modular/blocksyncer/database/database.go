@@ -76,6 +76,31 @@ func (db *DB) AutoMigrate(ctx context.Context, tables []schema.Tabler) error {
 	return nil
 }
 
+// CreateIndexes creates the named indexes defined by the model if they do not exist, the indexes of the sharded
+// tables are created on every shard.
+func (db *DB) CreateIndexes(ctx context.Context, t schema.Tabler, names ...string) error {
+	tableNames := []string{t.TableName()}
+	if t.TableName() == bsdb.PrefixTreeTableName || t.TableName() == bsdb.ObjectTableName {
+		tableNames = tableNames[:0]
+		for i := 0; i < bsdb.ObjectsNumberOfShards; i++ {
+			tableNames = append(tableNames, fmt.Sprintf(t.TableName()+"_%02d", i))
+		}
+	}
+	for _, tableName := range tableNames {
+		m := db.Db.WithContext(ctx).Table(tableName).Migrator()
+		for _, name := range names {
+			if m.HasIndex(t, name) {
+				continue
+			}
+			if err := m.CreateIndex(t, name); err != nil {
+				log.Errorw("create index failed", "table", tableName, "index", name, "err", err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (db *DB) PrepareTables(ctx context.Context, tables []schema.Tabler) error {
 	q := db.Db.WithContext(ctx)
 	m := db.Db.Migrator()
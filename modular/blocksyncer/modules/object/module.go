@@ -38,10 +38,32 @@ func (m *Module) Name() string {
 
 // PrepareTables implements
 func (m *Module) PrepareTables() error {
-	return m.db.PrepareTables(context.TODO(), []schema.Tabler{&models.Object{}})
+	if err := m.db.PrepareTables(context.TODO(), []schema.Tabler{&models.Object{}}); err != nil {
+		return err
+	}
+	return m.db.CreateIndexes(context.TODO(), &objectSortIndexes{}, objectSortIndexNames...)
 }
 
 // AutoMigrate implements
 func (m *Module) AutoMigrate() error {
-	return m.db.AutoMigrate(context.TODO(), []schema.Tabler{&models.Object{}, &models.Object{}})
+	if err := m.db.AutoMigrate(context.TODO(), []schema.Tabler{&models.Object{}, &models.Object{}}); err != nil {
+		return err
+	}
+	return m.db.CreateIndexes(context.TODO(), &objectSortIndexes{}, objectSortIndexNames...)
+}
+
+// objectSortIndexNames defines the indexes to list the objects of a bucket sorted by the payload size or
+// the create time.
+var objectSortIndexNames = []string{"idx_bucket_name_payload_size", "idx_bucket_name_create_time"}
+
+// objectSortIndexes defines the sort indexes of the object table, it is only used to create the indexes,
+// the columns are defined by models.Object.
+type objectSortIndexes struct {
+	BucketName  string `gorm:"column:bucket_name;index:idx_bucket_name_payload_size,priority:1;index:idx_bucket_name_create_time,priority:1"`
+	PayloadSize uint64 `gorm:"column:payload_size;index:idx_bucket_name_payload_size,priority:2"`
+	CreateTime  int64  `gorm:"column:create_time;index:idx_bucket_name_create_time,priority:2"`
+}
+
+func (*objectSortIndexes) TableName() string {
+	return (&models.Object{}).TableName()
 }
@@ -3,7 +3,9 @@ package downloader
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -123,26 +125,131 @@ func (d *DownloadModular) HandleDownloadObjectTask(ctx context.Context, download
 		log.CtxErrorw(ctx, "failed to generate piece info to download", "error", err)
 		return nil, err
 	}
-	var data []byte
+	var data, piece []byte
 	for _, pInfo := range pieceInfos {
-		key := cacheKey(pInfo.SegmentPieceKey, int64(pInfo.Offset), int64(pInfo.Length))
-		pieceData, has := d.pieceCache.Get(key)
-		if has {
-			data = append(data, pieceData.([]byte)...)
-			continue
-		}
-		piece, err := d.baseApp.PieceStore().GetPiece(ctx, pInfo.SegmentPieceKey,
-			int64(pInfo.Offset), int64(pInfo.Length))
-		if err != nil {
-			log.CtxErrorw(ctx, "failed to get piece data from piece store", "error", err)
-			return nil, ErrPieceStore
+		if piece, err = d.getSegmentPiece(ctx, pInfo); err != nil {
+			return nil, err
 		}
-		d.pieceCache.Add(key, piece)
 		data = append(data, piece...)
 	}
 	return data, nil
 }
 
+func (d *DownloadModular) HandleDownloadObjectTaskStream(ctx context.Context, downloadObjectTask task.DownloadObjectTask) (
+	io.ReadCloser, error) {
+	var err error
+	defer func() {
+		if err != nil {
+			downloadObjectTask.SetError(err)
+			atomic.AddInt64(&d.downloading, -1)
+		}
+		log.CtxDebugw(ctx, downloadObjectTask.Info())
+	}()
+	if atomic.AddInt64(&d.downloading, 1) >= atomic.LoadInt64(&d.downloadParallel) {
+		err = ErrExceedRequest
+		return nil, err
+	}
+
+	pieceInfos, err := SplitToSegmentPieceInfos(downloadObjectTask, d.baseApp.PieceOp())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to generate piece info to download", "error", err)
+		return nil, err
+	}
+	return &segmentPieceReader{
+		pieceInfos: pieceInfos,
+		fetch: func(pInfo *SegmentPieceInfo) ([]byte, error) {
+			piece, fetchErr := d.getSegmentPiece(ctx, pInfo)
+			if fetchErr != nil {
+				downloadObjectTask.SetError(fetchErr)
+			}
+			return piece, fetchErr
+		},
+		release: func() {
+			atomic.AddInt64(&d.downloading, -1)
+		},
+	}, nil
+}
+
+// getSegmentPiece returns the segment piece data of the piece info, the piece cache is
+// checked first, and the piece store is only accessed if cache misses.
+func (d *DownloadModular) getSegmentPiece(ctx context.Context, pInfo *SegmentPieceInfo) ([]byte, error) {
+	key := cacheKey(pInfo.SegmentPieceKey, int64(pInfo.Offset), int64(pInfo.Length))
+	if pieceData, has := d.pieceCache.Get(key); has {
+		return pieceData.([]byte), nil
+	}
+	piece, err := d.baseApp.PieceStore().GetPiece(ctx, pInfo.SegmentPieceKey,
+		int64(pInfo.Offset), int64(pInfo.Length))
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get piece data from piece store", "error", err)
+		return nil, ErrPieceStore
+	}
+	d.pieceCache.Add(key, piece)
+	return piece, nil
+}
+
+// segmentPieceReader reads the segment pieces of the object in order, only the current
+// segment piece is held in memory, the next one is fetched when it is drained.
+type segmentPieceReader struct {
+	pieceInfos []*SegmentPieceInfo
+	index      int
+	data       []byte
+	fetch      func(*SegmentPieceInfo) ([]byte, error)
+	release    func()
+	closeOnce  sync.Once
+}
+
+func (r *segmentPieceReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if r.index >= len(r.pieceInfos) {
+			return 0, io.EOF
+		}
+		data, err := r.fetch(r.pieceInfos[r.index])
+		if err != nil {
+			return 0, err
+		}
+		r.index++
+		r.data = data
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// WriteTo writes the remaining segment pieces to w directly, it avoids the extra copy
+// buffer when the reader is consumed by io.Copy.
+func (r *segmentPieceReader) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for {
+		if len(r.data) == 0 {
+			if r.index >= len(r.pieceInfos) {
+				return total, nil
+			}
+			data, err := r.fetch(r.pieceInfos[r.index])
+			if err != nil {
+				return total, err
+			}
+			r.index++
+			r.data = data
+		}
+		n, err := w.Write(r.data)
+		total += int64(n)
+		r.data = r.data[n:]
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+func (r *segmentPieceReader) Close() error {
+	r.closeOnce.Do(func() {
+		r.data = nil
+		if r.release != nil {
+			r.release()
+		}
+	})
+	return nil
+}
+
 type SegmentPieceInfo struct {
 	SegmentPieceKey string
 	Offset          uint64
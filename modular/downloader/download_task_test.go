@@ -1,7 +1,10 @@
 package downloader
 
 import (
+	"bytes"
+	"io"
 	"testing"
+	"testing/iotest"
 
 	sdkmath "cosmossdk.io/math"
 	"github.com/stretchr/testify/assert"
@@ -125,3 +128,48 @@ func TestSplitToSegmentPieceInfos(t *testing.T) {
 		})
 	}
 }
+
+func TestSegmentPieceReader(t *testing.T) {
+	pieces := map[string][]byte{
+		"p0": []byte("hello "),
+		"p1": []byte("greenfield"),
+	}
+	newReader := func(fetchErr error) (*segmentPieceReader, *int) {
+		var released int
+		return &segmentPieceReader{
+			pieceInfos: []*SegmentPieceInfo{{SegmentPieceKey: "p0"}, {SegmentPieceKey: "p1"}},
+			fetch: func(pInfo *SegmentPieceInfo) ([]byte, error) {
+				if fetchErr != nil && pInfo.SegmentPieceKey == "p1" {
+					return nil, fetchErr
+				}
+				return pieces[pInfo.SegmentPieceKey], nil
+			},
+			release: func() { released++ },
+		}, &released
+	}
+
+	t.Run("read with small buffer", func(t *testing.T) {
+		reader, released := newReader(nil)
+		data, err := io.ReadAll(iotest.OneByteReader(reader))
+		require.NoError(t, err)
+		assert.Equal(t, "hello greenfield", string(data))
+		require.NoError(t, reader.Close())
+		require.NoError(t, reader.Close())
+		assert.Equal(t, 1, *released)
+	})
+	t.Run("write to", func(t *testing.T) {
+		reader, _ := newReader(nil)
+		buf := &bytes.Buffer{}
+		n, err := io.Copy(buf, reader)
+		require.NoError(t, err)
+		assert.Equal(t, int64(16), n)
+		assert.Equal(t, "hello greenfield", buf.String())
+	})
+	t.Run("fetch piece failed", func(t *testing.T) {
+		reader, _ := newReader(ErrPieceStore)
+		buf := &bytes.Buffer{}
+		n, err := io.Copy(buf, reader)
+		assert.Equal(t, ErrPieceStore, err)
+		assert.Equal(t, int64(6), n)
+	})
+}
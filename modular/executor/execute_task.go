@@ -15,8 +15,10 @@ import (
 
 	"github.com/bnb-chain/greenfield-common/go/hash"
 	"github.com/bnb-chain/greenfield-common/go/redundancy"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsphealth"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	corepiecestore "github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
 	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
 	"github.com/bnb-chain/greenfield-storage-provider/modular/manager"
@@ -25,6 +27,7 @@ import (
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
 	"github.com/bnb-chain/greenfield-storage-provider/util"
 	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	virtualgrouptypes "github.com/bnb-chain/greenfield/x/virtualgroup/types"
 )
 
 var (
@@ -327,17 +330,544 @@ func (e *ExecuteModular) HandleGCObjectTask(ctx context.Context, task coretask.G
 			return
 		}
 		log.CtxDebugw(ctx, "succeed to gc an object", "object_info", objectInfo, "deleted_at_block_id", currentGCBlockID)
+		if notifyErr := manager.NotifyObjectEvent(e.baseApp.GfSpDB(), spdb.ObjectDeletedEvent, objectInfo); notifyErr != nil {
+			log.CtxErrorw(ctx, "failed to notify object deleted event", "object_info", objectInfo, "error", notifyErr)
+		}
 		gcObjectNumber++
 	}
 	isSucceed = true
 }
 
 func (e *ExecuteModular) HandleGCZombiePieceTask(ctx context.Context, task coretask.GCZombiePieceTask) {
-	log.CtxWarn(ctx, "gc zombie piece future support")
+	var (
+		err            error
+		spID           uint32
+		pieces         []*corepiecestore.PieceInfo
+		marker         string
+		batchNumber    int64
+		scanNumber     uint64
+		deleteNumber   uint64
+		lastObjectID   uint64
+		taskIsCanceled bool
+	)
+
+	reportProgress := func() bool {
+		reportErr := e.ReportTask(ctx, task)
+		log.CtxDebugw(ctx, "gc zombie piece task report progress", "task_info", task.Info(), "error", reportErr)
+		return errors.Is(reportErr, manager.ErrCanceledTask)
+	}
+
+	defer func() {
+		if err != nil {
+			task.SetError(err)
+		} else if !taskIsCanceled {
+			// the task will be reported by the ask task loop after it is finished
+			task.SetFinished(true)
+		}
+		log.CtxDebugw(ctx, "gc zombie piece task", "task_info", task.Info(), "scan_number", scanNumber,
+			"task_is_canceled", taskIsCanceled, "error", err)
+	}()
+
+	if spID, err = e.getSPID(); err != nil {
+		log.CtxErrorw(ctx, "failed to get sp id", "error", err)
+		return
+	}
+	marker = task.GetLastPieceKey()
+	if marker == "" {
+		marker = task.GetStartAfter()
+	}
+	for scanNumber < task.GetLimit() {
+		batchNumber = e.gcZombiePieceBatchNumber
+		if remaining := task.GetLimit() - scanNumber; remaining < uint64(batchNumber) {
+			batchNumber = int64(remaining)
+		}
+		if pieces, err = e.baseApp.PieceStore().ListPieces(ctx, "", marker, batchNumber); err != nil {
+			log.CtxErrorw(ctx, "failed to list pieces", "marker", marker, "error", err)
+			return
+		}
+		if len(pieces) == 0 {
+			// reach the end of piece store, the next task starts from the beginning
+			task.SetLastPieceKey("")
+			return
+		}
+		if lastObjectID, deleteNumber, err = e.gcZombiePieces(ctx, spID, pieces); err != nil {
+			return
+		}
+		marker = pieces[len(pieces)-1].Key
+		scanNumber += uint64(len(pieces))
+		_, deleteCount := task.GetGCZombiePieceStatus()
+		task.SetGCZombiePieceStatus(lastObjectID, deleteCount+deleteNumber)
+		task.SetLastPieceKey(marker)
+		if int64(len(pieces)) < batchNumber {
+			task.SetLastPieceKey("")
+			return
+		}
+		if taskIsCanceled = reportProgress(); taskIsCanceled {
+			log.CtxErrorw(ctx, "gc zombie piece task has been canceled", "task_info", task.Info())
+			return
+		}
+	}
+}
+
+// gcZombiePieces checks the pieces against the object meta and deletes the zombie pieces, a piece is
+// zombie if its object is not existed or deleted, or the sp is no longer responsible for the piece.
+// The pieces of the migrating buckets and the swapping out virtual groups are kept, the sp may be the
+// destination of the migration before the chain state is final. It returns the last checked object id and the number of deleted pieces.
+func (e *ExecuteModular) gcZombiePieces(ctx context.Context, spID uint32, pieces []*corepiecestore.PieceInfo) (
+	uint64, uint64, error) {
+	var (
+		lastObjectID uint64
+		deleteNumber uint64
+		safeTime     = time.Now().Add(-time.Duration(e.gcZombiePieceSafeTime) * time.Second)
+		objectIDs    = make([]uint64, 0, len(pieces))
+		objectIDMap  = make(map[string]uint64, len(pieces))
+		objectIDSet  = make(map[uint64]struct{}, len(pieces))
+		bucketMap    = make(map[string]*storagetypes.BucketInfo)
+		gvgMap       = make(map[zombiePieceGVGKey]*virtualgrouptypes.GlobalVirtualGroup)
+	)
+	for _, piece := range pieces {
+		objectID, err := e.baseApp.PieceOp().ParseObjectID(piece.Key)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to parse object id, skip the piece", "piece_key", piece.Key, "error", err)
+			continue
+		}
+		if piece.ModTime.After(safeTime) {
+			log.CtxDebugw(ctx, "skip the piece in the safe time", "piece_key", piece.Key, "mod_time", piece.ModTime)
+			continue
+		}
+		if _, ok := objectIDSet[objectID]; !ok {
+			objectIDSet[objectID] = struct{}{}
+			objectIDs = append(objectIDs, objectID)
+		}
+		objectIDMap[piece.Key] = objectID
+	}
+	if len(objectIDs) == 0 {
+		return lastObjectID, deleteNumber, nil
+	}
+	guard, err := e.loadZombiePieceGuard()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to load the migrating virtual groups", "error", err)
+		return lastObjectID, deleteNumber, err
+	}
+	objects, err := e.baseApp.GfSpClient().ListObjectsByObjectID(ctx, objectIDs, true)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list objects by object ids", "error", err)
+		return lastObjectID, deleteNumber, err
+	}
+
+	for _, piece := range pieces {
+		objectID, ok := objectIDMap[piece.Key]
+		if !ok {
+			continue
+		}
+		lastObjectID = objectID
+		if e.baseApp.PieceOp().IsMultipartPieceKey(piece.Key) {
+			// the staged chunk of the multipart upload is only needed while the object is uploading, the
+			// chunks of the uploading object are collected by gc meta task after they are expired.
+			object, ok := objects[objectID]
+			if ok && !object.GetRemoved() && object.GetObjectInfo() != nil &&
+				object.GetObjectInfo().GetObjectStatus() == storagetypes.OBJECT_STATUS_CREATED {
+				continue
+			}
+			if err = e.baseApp.PieceStore().DeletePiece(ctx, piece.Key); err != nil {
+				log.CtxErrorw(ctx, "failed to delete zombie multipart piece", "piece_key", piece.Key, "error", err)
+				continue
+			}
+			metrics.GCZombiePieceCounter.WithLabelValues(e.Name()).Inc()
+			log.CtxDebugw(ctx, "succeed to delete zombie multipart piece", "piece_key", piece.Key, "size", piece.Size)
+			deleteNumber++
+			continue
+		}
+		segmentIdx, redundancyIdx, err := e.baseApp.PieceOp().ParseChallengeIdx(piece.Key)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to parse piece key, skip the piece", "piece_key", piece.Key, "error", err)
+			continue
+		}
+		object, ok := objects[objectID]
+		if ok && !object.GetRemoved() && object.GetObjectInfo() != nil {
+			objectInfo := object.GetObjectInfo()
+			if objectInfo.GetObjectStatus() != storagetypes.OBJECT_STATUS_SEALED {
+				// the created object may be uploading, and the discontinued object will be
+				// collected by gc object task.
+				continue
+			}
+			bucketInfo, hasBucket := bucketMap[objectInfo.GetBucketName()]
+			if !hasBucket {
+				bucket, bucketErr := e.baseApp.GfSpClient().GetBucketByBucketName(ctx, objectInfo.GetBucketName(), true)
+				if bucketErr != nil || bucket == nil || bucket.GetBucketInfo() == nil {
+					log.CtxErrorw(ctx, "failed to get bucket by bucket name, skip the piece", "piece_key",
+						piece.Key, "bucket_name", objectInfo.GetBucketName(), "error", bucketErr)
+					continue
+				}
+				bucketInfo = bucket.GetBucketInfo()
+				bucketMap[objectInfo.GetBucketName()] = bucketInfo
+			}
+			bucketID := bucketInfo.Id.Uint64()
+			if bucketInfo.GetBucketStatus() == storagetypes.BUCKET_STATUS_MIGRATING || guard.isBucketMigrating(bucketID) {
+				log.CtxDebugw(ctx, "skip the piece of the migrating bucket", "piece_key", piece.Key,
+					"bucket_id", bucketID)
+				continue
+			}
+			gvgKey := zombiePieceGVGKey{bucketID: bucketID, lvgID: objectInfo.GetLocalVirtualGroupId()}
+			gvg, hasGVG := gvgMap[gvgKey]
+			if !hasGVG {
+				var gvgErr error
+				if gvg, gvgErr = e.baseApp.GfSpClient().GetGlobalVirtualGroup(ctx, bucketID,
+					objectInfo.GetLocalVirtualGroupId()); gvgErr != nil || gvg == nil {
+					log.CtxErrorw(ctx, "failed to get global virtual group, skip the piece", "piece_key", piece.Key,
+						"object_info", objectInfo, "error", gvgErr)
+					continue
+				}
+				gvgMap[gvgKey] = gvg
+			}
+			if guard.isGVGMigrating(gvg) {
+				log.CtxDebugw(ctx, "skip the piece of the migrating global virtual group", "piece_key", piece.Key,
+					"gvg_id", gvg.GetId(), "family_id", gvg.GetFamilyId())
+				continue
+			}
+			if e.isPieceServedBySP(spID, gvg, objectInfo.GetRedundancyType(), redundancyIdx) {
+				if _, integrityErr := e.baseApp.GfSpDB().GetObjectIntegrity(objectID, redundancyIdx); integrityErr != nil {
+					// the piece is in service but the integrity meta is missing, keep the piece
+					// and let the recovery workflow to repair it.
+					log.CtxWarnw(ctx, "the integrity meta of served piece is missing", "piece_key", piece.Key,
+						"redundancy_idx", redundancyIdx, "error", integrityErr)
+				}
+				continue
+			}
+		}
+		if err = e.baseApp.PieceStore().DeletePiece(ctx, piece.Key); err != nil {
+			log.CtxErrorw(ctx, "failed to delete zombie piece", "piece_key", piece.Key, "error", err)
+			continue
+		}
+		// ignore this delete api error, the integrity meta may have been deleted with other pieces.
+		deleteErr := e.baseApp.GfSpDB().DeleteObjectIntegrity(objectID, redundancyIdx)
+		metrics.GCZombiePieceCounter.WithLabelValues(e.Name()).Inc()
+		log.CtxDebugw(ctx, "succeed to delete zombie piece", "piece_key", piece.Key, "segment_idx", segmentIdx,
+			"size", piece.Size, "delete_integrity_error", deleteErr)
+		deleteNumber++
+	}
+	return lastObjectID, deleteNumber, nil
+}
+
+// zombiePieceGVGKey is the key of the global virtual group cached in a batch of the zombie pieces.
+type zombiePieceGVGKey struct {
+	bucketID uint64
+	lvgID    uint32
+}
+
+// zombiePieceGuard records the buckets and the virtual groups which are being migrated or swapped out,
+// their pieces are not collected until the migrations are completed.
+type zombiePieceGuard struct {
+	bucketIDs map[uint64]struct{}
+	gvgIDs    map[uint32]struct{}
+	familyIDs map[uint32]struct{}
+}
+
+// loadZombiePieceGuard loads the migrate gvg units and the swap out units recorded in the sp db.
+func (e *ExecuteModular) loadZombiePieceGuard() (*zombiePieceGuard, error) {
+	guard := &zombiePieceGuard{
+		bucketIDs: make(map[uint64]struct{}),
+		gvgIDs:    make(map[uint32]struct{}),
+		familyIDs: make(map[uint32]struct{}),
+	}
+	units, err := e.baseApp.GfSpDB().ListMigrateGVGUnits()
+	if err != nil {
+		return nil, err
+	}
+	for _, unit := range units {
+		if unit.BucketID != 0 {
+			guard.bucketIDs[unit.BucketID] = struct{}{}
+			continue
+		}
+		guard.gvgIDs[unit.GlobalVirtualGroupID] = struct{}{}
+	}
+	swapOuts, err := e.baseApp.GfSpDB().ListDestSPSwapOutUnits()
+	if err != nil {
+		return nil, err
+	}
+	for _, swapOut := range swapOuts {
+		if swapOut.SwapOutMsg == nil {
+			continue
+		}
+		if swapOut.SwapOutMsg.GetGlobalVirtualGroupFamilyId() != 0 {
+			guard.familyIDs[swapOut.SwapOutMsg.GetGlobalVirtualGroupFamilyId()] = struct{}{}
+		}
+		for _, gvgID := range swapOut.SwapOutMsg.GetGlobalVirtualGroupIds() {
+			guard.gvgIDs[gvgID] = struct{}{}
+		}
+	}
+	return guard, nil
 }
 
+func (g *zombiePieceGuard) isBucketMigrating(bucketID uint64) bool {
+	_, ok := g.bucketIDs[bucketID]
+	return ok
+}
+
+func (g *zombiePieceGuard) isGVGMigrating(gvg *virtualgrouptypes.GlobalVirtualGroup) bool {
+	if _, ok := g.gvgIDs[gvg.GetId()]; ok {
+		return true
+	}
+	_, ok := g.familyIDs[gvg.GetFamilyId()]
+	return ok
+}
+
+// isPieceServedBySP returns whether the piece of the global virtual group should be stored by the sp,
+// the primary sp and the secondary sps of replica type store segment pieces, the secondary sps of ec
+// type store ec pieces according to their index in the global virtual group.
+func (e *ExecuteModular) isPieceServedBySP(spID uint32, gvg *virtualgrouptypes.GlobalVirtualGroup,
+	redundancyType storagetypes.RedundancyType, redundancyIdx int32) bool {
+	if redundancyIdx < 0 {
+		if gvg.GetPrimarySpId() == spID {
+			return true
+		}
+		if redundancyType != storagetypes.REDUNDANCY_REPLICA_TYPE {
+			return false
+		}
+		for _, secondarySPID := range gvg.GetSecondarySpIds() {
+			if secondarySPID == spID {
+				return true
+			}
+		}
+		return false
+	}
+	if int(redundancyIdx) >= len(gvg.GetSecondarySpIds()) {
+		return false
+	}
+	return gvg.GetSecondarySpIds()[redundancyIdx] == spID
+}
+
+// The gc meta task collects the sp db tables unit by unit in the following order, the current
+// unit index is recorded in the task to resume the progress.
+const (
+	// GCMetaUploadProgressUnit collects the expired upload object progresses.
+	GCMetaUploadProgressUnit uint64 = iota
+	// GCMetaUploadEventUnit collects the expired upload event logs.
+	GCMetaUploadEventUnit
+	// GCMetaPieceChecksumUnit collects the replicate piece checksums of the sealed or deleted objects.
+	GCMetaPieceChecksumUnit
+	// GCMetaReadRecordUnit collects the expired read records.
+	GCMetaReadRecordUnit
+	// GCMetaMigrateGVGUnit collects the finished bucket migrate gvg units.
+	GCMetaMigrateGVGUnit
+	// GCMetaSwapOutUnit collects the finished swap out units.
+	GCMetaSwapOutUnit
+	// GCMetaMultipartUploadUnit collects the staged parts of the expired or aborted multipart uploads.
+	GCMetaMultipartUploadUnit
+	// GCMetaUnitNumber defines the number of the gc meta units.
+	GCMetaUnitNumber
+)
+
+// HandleGCMetaTask collects the sp db tables which only grow, every unit is collected in batches and
+// the progress is reported to the manager after every batch.
 func (e *ExecuteModular) HandleGCMetaTask(ctx context.Context, task coretask.GCMetaTask) {
-	log.CtxWarn(ctx, "gc meta future support")
+	var (
+		err            error
+		currentIdx     uint64
+		deleteCount    uint64
+		deleteNumber   int64
+		unitFinished   bool
+		taskIsCanceled bool
+	)
+
+	reportProgress := func() bool {
+		reportErr := e.ReportTask(ctx, task)
+		log.CtxDebugw(ctx, "gc meta task report progress", "task_info", task.Info(), "error", reportErr)
+		return errors.Is(reportErr, manager.ErrCanceledTask)
+	}
+
+	defer func() {
+		if err != nil {
+			task.SetError(err)
+		} else if !taskIsCanceled {
+			// the task will be reported by the ask task loop after it is finished
+			task.SetFinished(true)
+		}
+		log.CtxDebugw(ctx, "gc meta task", "task_info", task.Info(), "task_is_canceled", taskIsCanceled, "error", err)
+	}()
+
+	currentIdx, deleteCount = task.GetGCMetaStatus()
+	for currentIdx < GCMetaUnitNumber {
+		if deleteNumber, unitFinished, err = e.gcMetaUnit(ctx, task, currentIdx); err != nil {
+			log.CtxErrorw(ctx, "failed to gc meta", "unit_idx", currentIdx, "task_info", task.Info(), "error", err)
+			return
+		}
+		deleteCount += uint64(deleteNumber)
+		metrics.GCMetaCounter.WithLabelValues(e.Name()).Add(float64(deleteNumber))
+		if unitFinished {
+			// the next unit starts from the beginning
+			currentIdx++
+			task.SetLastObjectId(0)
+		}
+		task.SetGCMetaStatus(currentIdx, deleteCount)
+		if currentIdx == GCMetaUnitNumber {
+			return
+		}
+		if taskIsCanceled = reportProgress(); taskIsCanceled {
+			log.CtxErrorw(ctx, "gc meta task has been canceled", "task_info", task.Info())
+			return
+		}
+	}
+}
+
+// gcMetaUnit deletes a batch of the expired rows in the unit, returns the number of deleted rows and
+// whether the unit has been collected.
+func (e *ExecuteModular) gcMetaUnit(ctx context.Context, task coretask.GCMetaTask, unitIdx uint64) (int64, bool, error) {
+	var (
+		err          error
+		deleteNumber int64
+		now          = time.Now()
+		batchNumber  = int(e.gcMetaBatchNumber)
+	)
+	switch unitIdx {
+	case GCMetaUploadProgressUnit:
+		expired := now.Unix() - e.gcMetaUploadProgressRetention
+		if deleteNumber, err = e.baseApp.GfSpDB().DeleteExpiredUploadProgress(expired, batchNumber); err != nil {
+			return 0, false, ErrGfSpDB
+		}
+		return deleteNumber, deleteNumber < int64(batchNumber), nil
+	case GCMetaUploadEventUnit:
+		expired := now.Add(-time.Duration(e.gcMetaUploadEventRetention) * time.Second)
+		if deleteNumber, err = e.baseApp.GfSpDB().DeleteExpiredPutEvent(expired, batchNumber); err != nil {
+			return 0, false, ErrGfSpDB
+		}
+		return deleteNumber, deleteNumber < int64(batchNumber), nil
+	case GCMetaPieceChecksumUnit:
+		return e.gcReplicatePieceChecksum(ctx, task, batchNumber)
+	case GCMetaReadRecordUnit:
+		expired := now.Add(-time.Duration(e.gcMetaReadRecordRetention) * time.Second).UnixMicro()
+		if deleteNumber, err = e.baseApp.GfSpDB().DeleteExpiredReadRecord(expired, batchNumber); err != nil {
+			return 0, false, ErrGfSpDB
+		}
+		return deleteNumber, deleteNumber < int64(batchNumber), nil
+	case GCMetaMigrateGVGUnit:
+		expired := now.Unix() - e.gcMetaMigrateRetention
+		if deleteNumber, err = e.baseApp.GfSpDB().DeleteMigratedBucketGVGUnits(expired, batchNumber); err != nil {
+			return 0, false, ErrGfSpDB
+		}
+		// the limit is the number of buckets rather than the deleted units
+		return deleteNumber, deleteNumber == 0, nil
+	case GCMetaSwapOutUnit:
+		expired := now.Unix() - e.gcMetaMigrateRetention
+		if deleteNumber, err = e.baseApp.GfSpDB().DeleteCompletedDestSPSwapOutUnits(expired, batchNumber); err != nil {
+			return 0, false, ErrGfSpDB
+		}
+		return deleteNumber, deleteNumber < int64(batchNumber), nil
+	case GCMetaMultipartUploadUnit:
+		return e.gcMultipartUpload(ctx, task, batchNumber)
+	default:
+		log.CtxErrorw(ctx, "unknown gc meta unit, skip it", "unit_idx", unitIdx)
+		return 0, true, nil
+	}
+}
+
+// gcReplicatePieceChecksum deletes the replicate piece checksums of the objects which have been sealed,
+// discontinued or deleted, the piece checksums are temporary and should be deleted after replicating.
+func (e *ExecuteModular) gcReplicatePieceChecksum(ctx context.Context, task coretask.GCMetaTask, batchNumber int) (
+	int64, bool, error) {
+	objectIDs, err := e.baseApp.GfSpDB().ListReplicatePieceChecksumObjectIDs(task.GetLastObjectId(), batchNumber)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list replicate piece checksum object ids", "error", err)
+		return 0, false, ErrGfSpDB
+	}
+	if len(objectIDs) == 0 {
+		return 0, true, nil
+	}
+	objects, err := e.baseApp.GfSpClient().ListObjectsByObjectID(ctx, objectIDs, true)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list objects by object ids", "error", err)
+		return 0, false, err
+	}
+	deleteObjectIDs := make([]uint64, 0, len(objectIDs))
+	for _, objectID := range objectIDs {
+		object, ok := objects[objectID]
+		if !ok || object == nil {
+			// the object may not be synced to metadata service yet, check it in the next round.
+			continue
+		}
+		if object.GetRemoved() || object.GetObjectInfo() == nil ||
+			object.GetObjectInfo().GetObjectStatus() != storagetypes.OBJECT_STATUS_CREATED {
+			deleteObjectIDs = append(deleteObjectIDs, objectID)
+		}
+	}
+	deleteNumber, err := e.baseApp.GfSpDB().DeleteReplicatePieceChecksumByObjectIDs(deleteObjectIDs)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to delete replicate piece checksum", "error", err)
+		return 0, false, ErrGfSpDB
+	}
+	task.SetLastObjectId(objectIDs[len(objectIDs)-1])
+	return deleteNumber, len(objectIDs) < batchNumber, nil
+}
+
+// gcMultipartUpload deletes the staged parts of the multipart uploads whose objects have been sealed,
+// discontinued or deleted, and of the uploads which have no part uploaded within the retention time.
+func (e *ExecuteModular) gcMultipartUpload(ctx context.Context, task coretask.GCMetaTask, batchNumber int) (
+	int64, bool, error) {
+	uploads, err := e.baseApp.GfSpDB().ListMultipartUploads(task.GetLastObjectId(), batchNumber)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list multipart uploads", "error", err)
+		return 0, false, ErrGfSpDB
+	}
+	if len(uploads) == 0 {
+		return 0, true, nil
+	}
+	objectIDs := make([]uint64, 0, len(uploads))
+	for _, upload := range uploads {
+		objectIDs = append(objectIDs, upload.ObjectID)
+	}
+	objects, err := e.baseApp.GfSpClient().ListObjectsByObjectID(ctx, objectIDs, true)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list objects by object ids", "error", err)
+		return 0, false, err
+	}
+	var (
+		deleteNumber int64
+		expired      = time.Now().Unix() - e.gcMetaMultipartRetention
+	)
+	for _, upload := range uploads {
+		object, ok := objects[upload.ObjectID]
+		if ok && object != nil && !object.GetRemoved() && object.GetObjectInfo() != nil &&
+			object.GetObjectInfo().GetObjectStatus() == storagetypes.OBJECT_STATUS_CREATED &&
+			upload.UpdateTimestampSecond > expired {
+			// the object is still uploading
+			continue
+		}
+		if err = e.deleteMultipartUpload(ctx, upload.ObjectID); err != nil {
+			return deleteNumber, false, err
+		}
+		log.CtxDebugw(ctx, "succeed to delete multipart upload", "object_id", upload.ObjectID,
+			"update_time", upload.UpdateTimestampSecond)
+		deleteNumber++
+	}
+	task.SetLastObjectId(uploads[len(uploads)-1].ObjectID)
+	return deleteNumber, len(uploads) < batchNumber, nil
+}
+
+// deleteMultipartUpload deletes the staged chunks and the part records of the multipart upload, the records
+// are deleted after the chunks, so the failed deletion is retried by the next gc meta task.
+func (e *ExecuteModular) deleteMultipartUpload(ctx context.Context, objectID uint64) error {
+	prefix := e.baseApp.PieceOp().MultipartPieceKeyPrefix(objectID)
+	for {
+		pieces, err := e.baseApp.PieceStore().ListPieces(ctx, prefix, "", e.gcZombiePieceBatchNumber)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to list multipart pieces", "prefix", prefix, "error", err)
+			return ErrPieceStore
+		}
+		for _, piece := range pieces {
+			if err = e.baseApp.PieceStore().DeletePiece(ctx, piece.Key); err != nil {
+				log.CtxErrorw(ctx, "failed to delete multipart piece", "piece_key", piece.Key, "error", err)
+				return ErrPieceStore
+			}
+		}
+		if int64(len(pieces)) < e.gcZombiePieceBatchNumber {
+			break
+		}
+	}
+	if err := e.baseApp.GfSpDB().DeleteMultipartUploadParts(objectID); err != nil {
+		log.CtxErrorw(ctx, "failed to delete multipart upload parts", "object_id", objectID, "error", err)
+		return ErrGfSpDB
+	}
+	return nil
 }
 
 // HandleRecoverPieceTask handle the recovery piece task, it will send request to other SPs to get piece data to recovery,
@@ -632,6 +1162,10 @@ func (e *ExecuteModular) doRecoveryPiece(ctx context.Context, rTask coretask.Rec
 	}
 	rTask.SetSignature(signature)
 	// recovery primary sp segment or secondary piece
+	startTime := time.Now()
+	defer func() {
+		e.spCallRecorder.RecordCall(endpoint, gfsphealth.RecoveryCallType, startTime, err)
+	}()
 	respBody, err := e.baseApp.GfSpClient().GetPieceFromECChunks(ctx, endpoint, rTask)
 	if err != nil {
 		log.CtxErrorw(ctx, "failed to recovery piece", "objectID", rTask.GetObjectInfo().Id,
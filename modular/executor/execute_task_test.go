@@ -0,0 +1,306 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsppieceop"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	metadatatypes "github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+	virtualgrouptypes "github.com/bnb-chain/greenfield/x/virtualgroup/types"
+)
+
+// gcTestMetadata serves the objects by object ids, the buckets by bucket names and the global virtual groups
+// by bucket ids and local virtual group ids.
+type gcTestMetadata struct {
+	metadatatypes.UnimplementedGfSpMetadataServiceServer
+
+	objects  map[uint64]*metadatatypes.Object
+	buckets  map[string]*storagetypes.BucketInfo
+	gvgs     map[zombiePieceGVGKey]*virtualgrouptypes.GlobalVirtualGroup
+	gvgCalls int32
+}
+
+func (s *gcTestMetadata) GfSpListObjectsByObjectID(_ context.Context, req *metadatatypes.GfSpListObjectsByObjectIDRequest) (
+	*metadatatypes.GfSpListObjectsByObjectIDResponse, error) {
+	resp := &metadatatypes.GfSpListObjectsByObjectIDResponse{Objects: make(map[uint64]*metadatatypes.Object)}
+	for _, objectID := range req.GetObjectIds() {
+		if object, ok := s.objects[objectID]; ok {
+			resp.Objects[objectID] = object
+		}
+	}
+	return resp, nil
+}
+
+func (s *gcTestMetadata) GfSpGetBucketByBucketName(_ context.Context, req *metadatatypes.GfSpGetBucketByBucketNameRequest) (
+	*metadatatypes.GfSpGetBucketByBucketNameResponse, error) {
+	bucketInfo, ok := s.buckets[req.GetBucketName()]
+	if !ok {
+		return nil, errors.New("bucket not found")
+	}
+	return &metadatatypes.GfSpGetBucketByBucketNameResponse{Bucket: &metadatatypes.Bucket{BucketInfo: bucketInfo}}, nil
+}
+
+func (s *gcTestMetadata) GfSpGetGlobalVirtualGroup(_ context.Context, req *metadatatypes.GfSpGetGlobalVirtualGroupRequest) (
+	*metadatatypes.GfSpGetGlobalVirtualGroupResponse, error) {
+	atomic.AddInt32(&s.gvgCalls, 1)
+	gvg, ok := s.gvgs[zombiePieceGVGKey{bucketID: req.GetBucketId(), lvgID: req.GetLvgId()}]
+	if !ok {
+		return nil, errors.New("global virtual group not found")
+	}
+	return &metadatatypes.GfSpGetGlobalVirtualGroupResponse{Gvg: gvg}, nil
+}
+
+// gcTestPieceStore stores the pieces in memory.
+type gcTestPieceStore struct {
+	piecestore.PieceStore
+
+	mux    sync.Mutex
+	pieces map[string]time.Time
+}
+
+func (s *gcTestPieceStore) DeletePiece(_ context.Context, key string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.pieces, key)
+	return nil
+}
+
+func (s *gcTestPieceStore) ListPieces(_ context.Context, prefix, marker string, limit int64) ([]*piecestore.PieceInfo, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var keys []string
+	for key := range s.pieces {
+		if strings.HasPrefix(key, prefix) && key > marker {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if int64(len(keys)) > limit {
+		keys = keys[:limit]
+	}
+	pieces := make([]*piecestore.PieceInfo, 0, len(keys))
+	for _, key := range keys {
+		pieces = append(pieces, &piecestore.PieceInfo{Key: key, ModTime: s.pieces[key]})
+	}
+	return pieces, nil
+}
+
+func (s *gcTestPieceStore) keys() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	keys := make([]string, 0, len(s.pieces))
+	for key := range s.pieces {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setupGCTest serves the object 1 and 2 which are uploading, the sealed object 3 and the removed object 4,
+// the object 5 is not found.
+func setupGCTest(t *testing.T, db corespdb.SPDB, store piecestore.PieceStore) *ExecuteModular {
+	newObject := func(id uint64, status storagetypes.ObjectStatus) *metadatatypes.Object {
+		return &metadatatypes.Object{ObjectInfo: &storagetypes.ObjectInfo{Id: sdkmath.NewUint(id), ObjectStatus: status}}
+	}
+	removed := newObject(4, storagetypes.OBJECT_STATUS_CREATED)
+	removed.Removed = true
+	return setupGCTestWithMetadata(t, db, store, &gcTestMetadata{objects: map[uint64]*metadatatypes.Object{
+		1: newObject(1, storagetypes.OBJECT_STATUS_CREATED),
+		2: newObject(2, storagetypes.OBJECT_STATUS_CREATED),
+		3: newObject(3, storagetypes.OBJECT_STATUS_SEALED),
+		4: removed,
+	}})
+}
+
+// setupGCTestWithMetadata sets up the executor with the metadata served by the given metadata server.
+func setupGCTestWithMetadata(t *testing.T, db corespdb.SPDB, store piecestore.PieceStore,
+	metadata *gcTestMetadata) *ExecuteModular {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	metadatatypes.RegisterGfSpMetadataServiceServer(grpcServer, metadata)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	cfg := &gfspconfig.GfSpConfig{GRPCAddress: listener.Addr().String()}
+	baseApp, err := gfspapp.NewGfSpBaseApp(cfg, gfspconfig.CustomizeGfSpDB(db), gfspconfig.CustomizePieceStore(store))
+	require.NoError(t, err)
+	return &ExecuteModular{baseApp: baseApp, gcZombiePieceBatchNumber: 2, gcMetaMultipartRetention: 3600}
+}
+
+func TestExecuteModular_GCMultipartUpload(t *testing.T) {
+	pieceOp := &gfsppieceop.GfSpPieceOp{}
+	store := &gcTestPieceStore{pieces: make(map[string]time.Time)}
+	for objectID := uint64(1); objectID <= 5; objectID++ {
+		for chunkIdx := uint32(0); chunkIdx < 3; chunkIdx++ {
+			store.pieces[pieceOp.MultipartPieceKey(objectID, 1, "u1", chunkIdx)] = time.Now()
+		}
+	}
+	store.pieces[pieceOp.SegmentPieceKey(2, 0)] = time.Now()
+	now := time.Now().Unix()
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	db.EXPECT().ListMultipartUploads(uint64(0), 10).Return([]*corespdb.MultipartUpload{
+		{ObjectID: 1, UpdateTimestampSecond: now},
+		{ObjectID: 2, UpdateTimestampSecond: now - 7200},
+		{ObjectID: 3, UpdateTimestampSecond: now},
+		{ObjectID: 4, UpdateTimestampSecond: now},
+		{ObjectID: 5, UpdateTimestampSecond: now},
+	}, nil)
+	for _, objectID := range []uint64{2, 3, 4, 5} {
+		db.EXPECT().DeleteMultipartUploadParts(objectID).Return(nil)
+	}
+	executor := setupGCTest(t, db, store)
+
+	task := &gfsptask.GfSpGCMetaTask{}
+	task.InitGCMetaTask(0, GCMetaMultipartUploadUnit, 0, 0)
+	deleteNumber, finished, err := executor.gcMultipartUpload(context.Background(), task, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), deleteNumber)
+	assert.True(t, finished)
+	assert.Equal(t, uint64(5), task.GetLastObjectId())
+	// only the chunks of the uploading object within the retention time are kept
+	assert.Equal(t, []string{pieceOp.MultipartPieceKey(1, 1, "u1", 0), pieceOp.MultipartPieceKey(1, 1, "u1", 1),
+		pieceOp.MultipartPieceKey(1, 1, "u1", 2), pieceOp.SegmentPieceKey(2, 0)}, store.keys())
+}
+
+func TestExecuteModular_GCZombieMultipartPieces(t *testing.T) {
+	pieceOp := &gfsppieceop.GfSpPieceOp{}
+	store := &gcTestPieceStore{pieces: make(map[string]time.Time)}
+	var pieces []*piecestore.PieceInfo
+	for objectID := uint64(1); objectID <= 5; objectID++ {
+		key := pieceOp.MultipartPieceKey(objectID, 1, "u1", 0)
+		store.pieces[key] = time.Now().Add(-time.Hour)
+		pieces = append(pieces, &piecestore.PieceInfo{Key: key, ModTime: store.pieces[key]})
+	}
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	db.EXPECT().ListMigrateGVGUnits().Return(nil, nil)
+	db.EXPECT().ListDestSPSwapOutUnits().Return(nil, nil)
+	executor := setupGCTest(t, db, store)
+
+	lastObjectID, deleteNumber, err := executor.gcZombiePieces(context.Background(), 1, pieces)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), lastObjectID)
+	assert.Equal(t, uint64(3), deleteNumber)
+	// the chunks of the uploading objects are kept
+	assert.Equal(t, []string{pieceOp.MultipartPieceKey(1, 1, "u1", 0), pieceOp.MultipartPieceKey(2, 1, "u1", 0)}, store.keys())
+}
+
+func TestExecuteModular_GCZombiePieces(t *testing.T) {
+	newBucket := func(id uint64, status storagetypes.BucketStatus) *storagetypes.BucketInfo {
+		return &storagetypes.BucketInfo{Id: sdkmath.NewUint(id), BucketStatus: status}
+	}
+	newObject := func(id uint64, bucketName string, lvgID uint32) *metadatatypes.Object {
+		return &metadatatypes.Object{ObjectInfo: &storagetypes.ObjectInfo{Id: sdkmath.NewUint(id), BucketName: bucketName,
+			LocalVirtualGroupId: lvgID, ObjectStatus: storagetypes.OBJECT_STATUS_SEALED}}
+	}
+	newGVG := func(id, familyID, primarySPID uint32) *virtualgrouptypes.GlobalVirtualGroup {
+		return &virtualgrouptypes.GlobalVirtualGroup{Id: id, FamilyId: familyID, PrimarySpId: primarySPID,
+			SecondarySpIds: []uint32{3, 4}}
+	}
+	// the sp 1 is the primary sp of the gvg 12 only, the bucket b2 is migrating on chain, the bucket b3 is
+	// migrating to the sp, the gvg 13 and the family 24 are swapped out to the sp, the gvg 15 is migrated
+	// to the sp by the sp exit.
+	metadata := &gcTestMetadata{
+		objects: map[uint64]*metadatatypes.Object{
+			10: newObject(10, "b1", 1),
+			11: newObject(11, "b1", 1),
+			12: newObject(12, "b1", 2),
+			13: newObject(13, "b2", 1),
+			14: newObject(14, "b3", 1),
+			15: newObject(15, "b4", 1),
+			16: newObject(16, "b4", 2),
+			17: newObject(17, "b4", 3),
+		},
+		buckets: map[string]*storagetypes.BucketInfo{
+			"b1": newBucket(1, storagetypes.BUCKET_STATUS_CREATED),
+			"b2": newBucket(2, storagetypes.BUCKET_STATUS_MIGRATING),
+			"b3": newBucket(3, storagetypes.BUCKET_STATUS_CREATED),
+			"b4": newBucket(4, storagetypes.BUCKET_STATUS_CREATED),
+		},
+		gvgs: map[zombiePieceGVGKey]*virtualgrouptypes.GlobalVirtualGroup{
+			{bucketID: 1, lvgID: 1}: newGVG(11, 21, 2),
+			{bucketID: 1, lvgID: 2}: newGVG(12, 22, 1),
+			{bucketID: 3, lvgID: 1}: newGVG(11, 21, 2),
+			{bucketID: 4, lvgID: 1}: newGVG(13, 23, 2),
+			{bucketID: 4, lvgID: 2}: newGVG(14, 24, 2),
+			{bucketID: 4, lvgID: 3}: newGVG(15, 25, 2),
+		},
+	}
+	pieceOp := &gfsppieceop.GfSpPieceOp{}
+	store := &gcTestPieceStore{pieces: make(map[string]time.Time)}
+	var pieces []*piecestore.PieceInfo
+	addPiece := func(key string) {
+		store.pieces[key] = time.Now().Add(-time.Hour)
+		pieces = append(pieces, &piecestore.PieceInfo{Key: key, ModTime: store.pieces[key]})
+	}
+	for objectID := uint64(10); objectID <= 17; objectID++ {
+		addPiece(pieceOp.SegmentPieceKey(objectID, 0))
+	}
+	addPiece(pieceOp.SegmentPieceKey(11, 1))
+
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	db.EXPECT().ListMigrateGVGUnits().Return([]*corespdb.MigrateGVGUnitMeta{
+		{GlobalVirtualGroupID: 11, BucketID: 3, DestSPID: 1},
+		{GlobalVirtualGroupID: 15, VirtualGroupFamilyID: 25, DestSPID: 1},
+	}, nil)
+	db.EXPECT().ListDestSPSwapOutUnits().Return([]*corespdb.SwapOutMeta{
+		{IsDestSP: true, SwapOutMsg: &virtualgrouptypes.MsgSwapOut{GlobalVirtualGroupIds: []uint32{13}, SuccessorSpId: 1}},
+		{IsDestSP: true, SwapOutMsg: &virtualgrouptypes.MsgSwapOut{GlobalVirtualGroupFamilyId: 24, SuccessorSpId: 1}},
+	}, nil)
+	db.EXPECT().GetObjectIntegrity(uint64(12), int32(-1)).Return(&corespdb.IntegrityMeta{}, nil)
+	db.EXPECT().DeleteObjectIntegrity(uint64(10), int32(-1)).Return(nil)
+	db.EXPECT().DeleteObjectIntegrity(uint64(11), int32(-1)).Return(nil).Times(2)
+	executor := setupGCTestWithMetadata(t, db, store, metadata)
+
+	_, deleteNumber, err := executor.gcZombiePieces(context.Background(), 1, pieces)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), deleteNumber)
+	// only the pieces of the gvg 11 which is not migrating are collected
+	var wantedKeys []string
+	for objectID := uint64(12); objectID <= 17; objectID++ {
+		wantedKeys = append(wantedKeys, pieceOp.SegmentPieceKey(objectID, 0))
+	}
+	sort.Strings(wantedKeys)
+	assert.Equal(t, wantedKeys, store.keys())
+	// the gvg is fetched once for every local virtual group, the migrating buckets are skipped before fetching
+	assert.Equal(t, int32(5), atomic.LoadInt32(&metadata.gvgCalls))
+}
+
+func TestExecuteModular_GCZombiePiecesGuardError(t *testing.T) {
+	pieceOp := &gfsppieceop.GfSpPieceOp{}
+	store := &gcTestPieceStore{pieces: make(map[string]time.Time)}
+	key := pieceOp.SegmentPieceKey(5, 0)
+	store.pieces[key] = time.Now().Add(-time.Hour)
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	db.EXPECT().ListMigrateGVGUnits().Return(nil, errors.New("mock error"))
+	executor := setupGCTest(t, db, store)
+
+	_, deleteNumber, err := executor.gcZombiePieces(context.Background(), 1,
+		[]*piecestore.PieceInfo{{Key: key, ModTime: store.pieces[key]}})
+	assert.Error(t, err)
+	assert.Equal(t, uint64(0), deleteNumber)
+	assert.Equal(t, []string{key}, store.keys())
+}
@@ -7,7 +7,10 @@ import (
 	"sync/atomic"
 	"time"
 
+	"golang.org/x/time/rate"
+
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsphealth"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
@@ -35,6 +38,22 @@ type ExecuteModular struct {
 	listenSealRetryTimeout  int
 	maxListenSealRetry      int
 
+	gcZombiePieceBatchNumber int64
+	gcZombiePieceSafeTime    int64
+
+	gcMetaBatchNumber             int64
+	gcMetaUploadProgressRetention int64
+	gcMetaUploadEventRetention    int64
+	gcMetaReadRecordRetention     int64
+	gcMetaMigrateRetention        int64
+	gcMetaMultipartRetention      int64
+
+	spCallReportInterval int
+	spCallRecorder       *gfsphealth.CallRecorder
+
+	scrubObjectBatchNumber uint32
+	scrubLimiter           *rate.Limiter
+
 	statisticsOutputInterval   int
 	doingReplicatePieceTaskCnt int64
 	doingSpSealObjectTaskCnt   int64
@@ -44,6 +63,7 @@ type ExecuteModular struct {
 	doingGCGCMetaTaskCnt       int64
 	doingRecoveryPieceTaskCnt  int64
 	doingMigrationGVGTaskCnt   int64
+	doingScrubGVGTaskCnt       int64
 
 	spID uint32
 }
@@ -81,16 +101,32 @@ func (e *ExecuteModular) eventLoop(ctx context.Context) {
 	}
 
 	statisticsTicker := time.NewTicker(time.Duration(e.statisticsOutputInterval) * time.Second)
+	spCallReportTicker := time.NewTicker(time.Duration(e.spCallReportInterval) * time.Second)
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-statisticsTicker.C:
 			log.CtxInfo(ctx, e.Statistics())
+		case <-spCallReportTicker.C:
+			e.reportSPCallRecords(ctx)
 		}
 	}
 }
 
+// reportSPCallRecords reports the results of the calls to the other sps to manager, the records are put back
+// and reported next time if failed to report.
+func (e *ExecuteModular) reportSPCallRecords(ctx context.Context) {
+	records := e.spCallRecorder.TakePending()
+	if len(records) == 0 {
+		return
+	}
+	if err := e.baseApp.GfSpClient().ReportSPCallRecords(ctx, records); err != nil {
+		log.CtxErrorw(ctx, "failed to report sp call records", "record_number", len(records), "error", err)
+		e.spCallRecorder.Restore(records)
+	}
+}
+
 func (e *ExecuteModular) omitError(err error) bool {
 	switch realErr := err.(type) {
 	case *gfsperrors.GfSpError:
@@ -204,10 +240,24 @@ func (e *ExecuteModular) AskTask(ctx context.Context) error {
 		atomic.AddInt64(&e.doingGCZombiePieceTaskCnt, 1)
 		defer atomic.AddInt64(&e.doingGCZombiePieceTaskCnt, -1)
 		e.HandleGCZombiePieceTask(ctx, t)
+		if t.Error() != nil {
+			metrics.ReqCounter.WithLabelValues(ExeutorFailureGCZombieTask).Inc()
+			metrics.ReqTime.WithLabelValues(ExeutorFailureGCZombieTask).Observe(time.Since(startTime).Seconds())
+		} else {
+			metrics.ReqCounter.WithLabelValues(ExeutorSuccessGCZombieTask).Inc()
+			metrics.ReqTime.WithLabelValues(ExeutorSuccessGCZombieTask).Observe(time.Since(startTime).Seconds())
+		}
 	case *gfsptask.GfSpGCMetaTask:
 		atomic.AddInt64(&e.doingGCGCMetaTaskCnt, 1)
 		defer atomic.AddInt64(&e.doingGCGCMetaTaskCnt, -1)
 		e.HandleGCMetaTask(ctx, t)
+		if t.Error() != nil {
+			metrics.ReqCounter.WithLabelValues(ExeutorFailureGCMetaTask).Inc()
+			metrics.ReqTime.WithLabelValues(ExeutorFailureGCMetaTask).Observe(time.Since(startTime).Seconds())
+		} else {
+			metrics.ReqCounter.WithLabelValues(ExeutorSuccessGCMetaTask).Inc()
+			metrics.ReqTime.WithLabelValues(ExeutorSuccessGCMetaTask).Observe(time.Since(startTime).Seconds())
+		}
 	case *gfsptask.GfSpRecoverPieceTask:
 		atomic.AddInt64(&e.doingRecoveryPieceTaskCnt, 1)
 		defer atomic.AddInt64(&e.doingRecoveryPieceTaskCnt, 1)
@@ -223,6 +273,17 @@ func (e *ExecuteModular) AskTask(ctx context.Context) error {
 		atomic.AddInt64(&e.doingMigrationGVGTaskCnt, 1)
 		defer atomic.AddInt64(&e.doingMigrationGVGTaskCnt, -1)
 		e.HandleMigrateGVGTask(ctx, t)
+	case *gfsptask.GfSpScrubGVGTask:
+		atomic.AddInt64(&e.doingScrubGVGTaskCnt, 1)
+		defer atomic.AddInt64(&e.doingScrubGVGTaskCnt, -1)
+		e.HandleScrubGVGTask(ctx, t)
+		if t.Error() != nil {
+			metrics.ReqCounter.WithLabelValues(ExeutorFailureScrubGVGTask).Inc()
+			metrics.ReqTime.WithLabelValues(ExeutorFailureScrubGVGTask).Observe(time.Since(startTime).Seconds())
+		} else {
+			metrics.ReqCounter.WithLabelValues(ExeutorSuccessScrubGVGTask).Inc()
+			metrics.ReqTime.WithLabelValues(ExeutorSuccessScrubGVGTask).Observe(time.Since(startTime).Seconds())
+		}
 	default:
 		log.CtxError(ctx, "unsupported task type")
 	}
@@ -277,7 +338,7 @@ func (e *ExecuteModular) ReleaseResource(
 
 func (e *ExecuteModular) Statistics() string {
 	return fmt.Sprintf(
-		"maxAsk[%d], asking[%d], replicate[%d], seal[%d], receive[%d], gcObject[%d], gcZombie[%d], gcMeta[%d], migrateGVG[%d]",
+		"maxAsk[%d], asking[%d], replicate[%d], seal[%d], receive[%d], gcObject[%d], gcZombie[%d], gcMeta[%d], migrateGVG[%d], scrubGVG[%d]",
 		&e.maxExecuteNum, atomic.LoadInt64(&e.executingNum),
 		atomic.LoadInt64(&e.doingReplicatePieceTaskCnt),
 		atomic.LoadInt64(&e.doingSpSealObjectTaskCnt),
@@ -285,7 +346,8 @@ func (e *ExecuteModular) Statistics() string {
 		atomic.LoadInt64(&e.doingGCObjectTaskCnt),
 		atomic.LoadInt64(&e.doingGCZombiePieceTaskCnt),
 		atomic.LoadInt64(&e.doingGCGCMetaTaskCnt),
-		atomic.LoadInt64(&e.doingMigrationGVGTaskCnt))
+		atomic.LoadInt64(&e.doingMigrationGVGTaskCnt),
+		atomic.LoadInt64(&e.doingScrubGVGTaskCnt))
 }
 
 func (e *ExecuteModular) getSPID() (uint32, error) {
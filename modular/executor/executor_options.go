@@ -1,8 +1,11 @@
 package executor
 
 import (
+	"golang.org/x/time/rate"
+
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsphealth"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
 )
 
@@ -31,6 +34,40 @@ const (
 	// DefaultExecutorMaxListenSealRetry defines the default max retry number for listening
 	// object.
 	DefaultExecutorMaxListenSealRetry int = 3
+	// DefaultExecutorGCZombiePieceBatchNumber defines the default number of pieces that
+	// are listed from piece store and checked in one batch by gc zombie piece task.
+	DefaultExecutorGCZombiePieceBatchNumber int64 = 100
+	// DefaultExecutorGCZombiePieceSafeTime defines the default safe time in seconds, the
+	// pieces that are written within the safe time are skipped by gc zombie piece task,
+	// because the objects of these pieces may be uploading or replicating.
+	DefaultExecutorGCZombiePieceSafeTime int64 = 24 * 60 * 60
+	// DefaultExecutorGCMetaBatchNumber defines the default number of rows that are deleted
+	// from sp db in one batch by gc meta task.
+	DefaultExecutorGCMetaBatchNumber int64 = 100
+	// DefaultExecutorGCMetaUploadProgressRetention defines the default retention time in
+	// seconds of the upload object progress.
+	DefaultExecutorGCMetaUploadProgressRetention int64 = 7 * 24 * 60 * 60
+	// DefaultExecutorGCMetaUploadEventRetention defines the default retention time in
+	// seconds of the upload event logs.
+	DefaultExecutorGCMetaUploadEventRetention int64 = 7 * 24 * 60 * 60
+	// DefaultExecutorGCMetaReadRecordRetention defines the default retention time in
+	// seconds of the read records.
+	DefaultExecutorGCMetaReadRecordRetention int64 = 30 * 24 * 60 * 60
+	// DefaultExecutorGCMetaMigrateRetention defines the default retention time in seconds
+	// of the finished migrate gvg units and swap out units.
+	DefaultExecutorGCMetaMigrateRetention int64 = 7 * 24 * 60 * 60
+	// DefaultExecutorGCMetaMultipartUploadRetention defines the default retention time in seconds
+	// of the multipart uploads which are not completed.
+	DefaultExecutorGCMetaMultipartUploadRetention int64 = 7 * 24 * 60 * 60
+	// DefaultExecutorSPCallReportInterval defines the default interval in seconds of reporting
+	// the results of the calls to the other sps to manager.
+	DefaultExecutorSPCallReportInterval int = 10
+	// DefaultExecutorScrubObjectBatchNumber defines the default number of objects that are
+	// listed and scrubbed in one batch by scrub gvg task.
+	DefaultExecutorScrubObjectBatchNumber uint32 = 100
+	// DefaultExecutorScrubRateLimit defines the default max bytes per second of the piece
+	// data that is read by scrubbing.
+	DefaultExecutorScrubRateLimit int = 10 * 1024 * 1024
 	// DefaultStatisticsOutputInterval defines the default interval for output statistics info,
 	// it is used to log and debug.
 	DefaultStatisticsOutputInterval int = 60
@@ -53,6 +90,12 @@ const (
 	ExeutorFailureReceiveTask    = "executor_receive_task_failure"
 	ExeutorSuccessRecoveryTask   = "executor_recovery_task_success"
 	ExeutorFailureRecoveryTask   = "executor_recovery_task_failure"
+	ExeutorSuccessGCZombieTask   = "executor_gc_zombie_task_success"
+	ExeutorFailureGCZombieTask   = "executor_gc_zombie_task_failure"
+	ExeutorSuccessGCMetaTask     = "executor_gc_meta_task_success"
+	ExeutorFailureGCMetaTask     = "executor_gc_meta_task_failure"
+	ExeutorSuccessScrubGVGTask   = "executor_scrub_gvg_task_success"
+	ExeutorFailureScrubGVGTask   = "executor_scrub_gvg_task_failure"
 
 	ExeutorSuccessReportTask = "executor_report_task_to_manager_success"
 	ExeutorFailureReportTask = "executor_report_task_to_manager_failure"
@@ -107,6 +150,51 @@ func DefaultExecutorOptions(executor *ExecuteModular, cfg *gfspconfig.GfSpConfig
 		cfg.Executor.MaxListenSealRetry = DefaultExecutorMaxListenSealRetry
 	}
 	executor.maxListenSealRetry = cfg.Executor.MaxListenSealRetry
+	if cfg.Executor.GCZombiePieceBatchNumber == 0 {
+		cfg.Executor.GCZombiePieceBatchNumber = DefaultExecutorGCZombiePieceBatchNumber
+	}
+	executor.gcZombiePieceBatchNumber = cfg.Executor.GCZombiePieceBatchNumber
+	if cfg.Executor.GCZombiePieceSafeTime == 0 {
+		cfg.Executor.GCZombiePieceSafeTime = DefaultExecutorGCZombiePieceSafeTime
+	}
+	executor.gcZombiePieceSafeTime = cfg.Executor.GCZombiePieceSafeTime
+	if cfg.Executor.GCMetaBatchNumber == 0 {
+		cfg.Executor.GCMetaBatchNumber = DefaultExecutorGCMetaBatchNumber
+	}
+	executor.gcMetaBatchNumber = cfg.Executor.GCMetaBatchNumber
+	if cfg.Executor.GCMetaUploadProgressRetention == 0 {
+		cfg.Executor.GCMetaUploadProgressRetention = DefaultExecutorGCMetaUploadProgressRetention
+	}
+	executor.gcMetaUploadProgressRetention = cfg.Executor.GCMetaUploadProgressRetention
+	if cfg.Executor.GCMetaUploadEventRetention == 0 {
+		cfg.Executor.GCMetaUploadEventRetention = DefaultExecutorGCMetaUploadEventRetention
+	}
+	executor.gcMetaUploadEventRetention = cfg.Executor.GCMetaUploadEventRetention
+	if cfg.Executor.GCMetaReadRecordRetention == 0 {
+		cfg.Executor.GCMetaReadRecordRetention = DefaultExecutorGCMetaReadRecordRetention
+	}
+	executor.gcMetaReadRecordRetention = cfg.Executor.GCMetaReadRecordRetention
+	if cfg.Executor.GCMetaMigrateRetention == 0 {
+		cfg.Executor.GCMetaMigrateRetention = DefaultExecutorGCMetaMigrateRetention
+	}
+	executor.gcMetaMigrateRetention = cfg.Executor.GCMetaMigrateRetention
+	if cfg.Executor.GCMetaMultipartUploadRetention == 0 {
+		cfg.Executor.GCMetaMultipartUploadRetention = DefaultExecutorGCMetaMultipartUploadRetention
+	}
+	executor.gcMetaMultipartRetention = cfg.Executor.GCMetaMultipartUploadRetention
+	if cfg.Executor.SPCallReportInterval == 0 {
+		cfg.Executor.SPCallReportInterval = DefaultExecutorSPCallReportInterval
+	}
+	executor.spCallReportInterval = cfg.Executor.SPCallReportInterval
+	executor.spCallRecorder = gfsphealth.NewCallRecorder()
+	if cfg.Executor.ScrubObjectBatchNumber == 0 {
+		cfg.Executor.ScrubObjectBatchNumber = DefaultExecutorScrubObjectBatchNumber
+	}
+	executor.scrubObjectBatchNumber = cfg.Executor.ScrubObjectBatchNumber
+	if cfg.Executor.ScrubRateLimit == 0 {
+		cfg.Executor.ScrubRateLimit = DefaultExecutorScrubRateLimit
+	}
+	executor.scrubLimiter = rate.NewLimiter(rate.Limit(cfg.Executor.ScrubRateLimit), cfg.Executor.ScrubRateLimit)
 	executor.statisticsOutputInterval = DefaultStatisticsOutputInterval
 	return nil
 }
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/bnb-chain/greenfield-common/go/hash"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsphealth"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
@@ -169,7 +171,10 @@ func (e *ExecuteModular) doBucketMigrationReplicatePiece(ctx context.Context, gv
 		return err
 	}
 	receive.SetSignature(signature)
-	if err = e.baseApp.GfSpClient().ReplicatePieceToSecondary(ctx, destSPEndpoint, receive, data); err != nil {
+	startTime := time.Now()
+	err = e.baseApp.GfSpClient().ReplicatePieceToSecondary(ctx, destSPEndpoint, receive, data)
+	e.spCallRecorder.RecordCall(destSPEndpoint, gfsphealth.ReplicateCallType, startTime, err)
+	if err != nil {
 		log.CtxErrorw(ctx, "failed to replicate piece", "segment_piece_index", segmentIdx,
 			"redundancy_index", redundancyIdx, "error", err)
 	}
@@ -187,7 +192,9 @@ func (e *ExecuteModular) doneBucketMigrationReplicatePiece(ctx context.Context,
 	}
 	receive.SetSignature(taskSignature)
 	receive.SetBucketMigration(true)
+	startTime := time.Now()
 	_, err = e.baseApp.GfSpClient().DoneReplicatePieceToSecondary(ctx, destSPEndpoint, receive)
+	e.spCallRecorder.RecordCall(destSPEndpoint, gfsphealth.ReceiveCallType, startTime, err)
 	if err != nil {
 		log.CtxErrorw(ctx, "failed to done replicate piece", "dest_sp_endpoint", destSPEndpoint,
 			"segment_idx", segmentIdx, "error", err)
@@ -0,0 +1,235 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-common/go/hash"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/modular/manager"
+	metadatatypes "github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	"github.com/bnb-chain/greenfield-storage-provider/store/piecestore/storage"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	// scrubRecoveryTimeout defines the timeout in seconds of the recovery piece task created by scrubbing.
+	scrubRecoveryTimeout = 50
+	// scrubRecoveryMaxRetry defines the max retry number of the recovery piece task created by scrubbing.
+	scrubRecoveryMaxRetry = 3
+)
+
+// HandleScrubGVGTask reads the pieces of the sealed objects in the gvg which are stored by the sp, and checks
+// them against the piece checksums of the integrity meta. The bad pieces are recorded in sp db, and the recovery
+// piece tasks are created for the bad pieces of ec objects. The progress is reported to manager every batch of
+// objects, so the scrubbing can be resumed from the last scrubbed object.
+func (e *ExecuteModular) HandleScrubGVGTask(ctx context.Context, task coretask.ScrubGVGTask) {
+	var (
+		err            error
+		objectList     []*metadatatypes.ObjectDetails
+		lastObjectID   = task.GetLastScrubbedObjectId()
+		taskIsCanceled bool
+	)
+
+	reportProgress := func() bool {
+		reportErr := e.ReportTask(ctx, task)
+		log.CtxDebugw(ctx, "scrub gvg task report progress", "task_info", task.Info(), "error", reportErr)
+		return errors.Is(reportErr, manager.ErrCanceledTask)
+	}
+
+	defer func() {
+		if err != nil {
+			task.SetError(err)
+		}
+		log.CtxDebugw(ctx, "scrub gvg task", "task_info", task.Info(), "task_is_canceled", taskIsCanceled,
+			"error", err)
+	}()
+
+	for {
+		if objectList, err = e.baseApp.GfSpClient().ListObjectsInGVG(ctx, task.GetGvgId(), lastObjectID,
+			e.scrubObjectBatchNumber); err != nil {
+			log.CtxErrorw(ctx, "failed to list objects in gvg", "gvg_id", task.GetGvgId(),
+				"start_after", lastObjectID, "error", err)
+			return
+		}
+		for _, object := range objectList {
+			objectInfo := object.GetObject().GetObjectInfo()
+			if objectInfo == nil {
+				continue
+			}
+			lastObjectID = objectInfo.Id.Uint64()
+			if object.GetObject().GetRemoved() || objectInfo.GetObjectStatus() != storagetypes.OBJECT_STATUS_SEALED {
+				continue
+			}
+			if err = e.scrubObject(ctx, task, objectInfo); err != nil {
+				return
+			}
+		}
+		task.SetLastScrubbedObjectId(lastObjectID)
+		if len(objectList) < int(e.scrubObjectBatchNumber) {
+			// the task will be reported by the ask task loop after it is finished
+			task.SetFinished(true)
+			return
+		}
+		if taskIsCanceled = reportProgress(); taskIsCanceled {
+			log.CtxErrorw(ctx, "scrub gvg task has been canceled", "task_info", task.Info())
+			return
+		}
+	}
+}
+
+// scrubObject checks all the pieces of the object which are stored by the sp, only the failures of querying
+// the storage params, waiting the rate limiter and writing sp db are returned, the bad pieces are recorded and
+// counted into the task.
+func (e *ExecuteModular) scrubObject(ctx context.Context, task coretask.ScrubGVGTask,
+	objectInfo *storagetypes.ObjectInfo) error {
+	params, err := e.baseApp.Consensus().QueryStorageParamsByTimestamp(ctx, objectInfo.GetCreateAt())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to query storage params", "object_id", objectInfo.Id.Uint64(), "error", err)
+		return err
+	}
+	var (
+		objectID       = objectInfo.Id.Uint64()
+		redundancyIdx  = task.GetRedundancyIdx()
+		maxSegmentSize = params.VersionedParams.GetMaxSegmentSize()
+		segmentCount   = e.baseApp.PieceOp().SegmentPieceCount(objectInfo.GetPayloadSize(), maxSegmentSize)
+		isECPiece      = redundancyIdx >= 0 && objectInfo.GetRedundancyType() == storagetypes.REDUNDANCY_EC_TYPE
+		checksums      [][]byte
+	)
+	integrity, err := e.baseApp.GfSpDB().GetObjectIntegrity(objectID, redundancyIdx)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.CtxErrorw(ctx, "failed to get object integrity", "object_id", objectID,
+			"redundancy_idx", redundancyIdx, "error", err)
+		return err
+	}
+	if integrity != nil {
+		checksums = integrity.PieceChecksumList
+	}
+
+	objectNumber, pieceNumber, corruptedNumber, missingNumber := task.GetScrubStatus()
+	defer func() {
+		task.SetScrubStatus(objectNumber, pieceNumber, corruptedNumber, missingNumber)
+	}()
+	for segmentIdx := uint32(0); segmentIdx < segmentCount; segmentIdx++ {
+		pieceKey := e.baseApp.PieceOp().SegmentPieceKey(objectID, segmentIdx)
+		pieceSize := e.baseApp.PieceOp().SegmentPieceSize(objectInfo.GetPayloadSize(), segmentIdx, maxSegmentSize)
+		if isECPiece {
+			pieceKey = e.baseApp.PieceOp().ECPieceKey(objectID, segmentIdx, uint32(redundancyIdx))
+			pieceSize = e.baseApp.PieceOp().ECPieceSize(objectInfo.GetPayloadSize(), segmentIdx, maxSegmentSize,
+				params.VersionedParams.GetRedundantDataChunkNum())
+		}
+		pieceNumber++
+		state := corespdb.ScrubPieceNoIntegrity
+		if int(segmentIdx) < len(checksums) {
+			if state, err = e.scrubPiece(ctx, pieceKey, pieceSize, checksums[segmentIdx]); err != nil {
+				log.CtxErrorw(ctx, "failed to scrub piece", "piece_key", pieceKey, "error", err)
+				return err
+			}
+		}
+		if state == "" {
+			metrics.ScrubPieceCounter.WithLabelValues("scrubbed").Inc()
+			continue
+		}
+		recovering := false
+		switch state {
+		case corespdb.ScrubPieceCorrupted:
+			corruptedNumber++
+			recovering = e.recoverScrubPiece(ctx, objectInfo, params, segmentIdx, redundancyIdx)
+		case corespdb.ScrubPieceMissing:
+			missingNumber++
+			recovering = e.recoverScrubPiece(ctx, objectInfo, params, segmentIdx, redundancyIdx)
+		}
+		metrics.ScrubPieceCounter.WithLabelValues(string(state)).Inc()
+		scrubPiece := &corespdb.ScrubPiece{
+			GlobalVirtualGroupID:  task.GetGvgId(),
+			ObjectID:              objectID,
+			SegmentIndex:          segmentIdx,
+			RedundancyIndex:       redundancyIdx,
+			PieceKey:              pieceKey,
+			State:                 state,
+			Recovering:            recovering,
+			DetectTimestampSecond: time.Now().Unix(),
+		}
+		if err = e.baseApp.GfSpDB().UpdateScrubPiece(scrubPiece); err != nil {
+			log.CtxErrorw(ctx, "failed to record scrub piece", "piece_key", pieceKey, "error", err)
+			return err
+		}
+		log.CtxWarnw(ctx, "found bad piece by scrubbing", "piece_key", pieceKey, "state", state,
+			"recovering", scrubPiece.Recovering)
+	}
+	objectNumber++
+	return nil
+}
+
+// scrubPiece waits the rate limiter for the piece size before reading the piece, and verifies the piece data
+// against the checksum, returns the empty state if the piece is healthy. Only the piece that does not exist in
+// the piece store is missing, the other failures of reading the piece, e.g. the store is unavailable, and the
+// failure of waiting the rate limiter are returned, so the task is retried instead of recording the piece.
+func (e *ExecuteModular) scrubPiece(ctx context.Context, pieceKey string, pieceSize int64,
+	checksum []byte) (corespdb.ScrubPieceState, error) {
+	if err := e.waitScrubLimiter(ctx, int(pieceSize)); err != nil {
+		return "", err
+	}
+	data, err := e.baseApp.PieceStore().GetPiece(ctx, pieceKey, 0, -1)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get piece data", "piece_key", pieceKey, "error", err)
+		if storage.IsNoSuchObject(err) {
+			return corespdb.ScrubPieceMissing, nil
+		}
+		return "", err
+	}
+	metrics.ScrubBytesCounter.WithLabelValues(e.Name()).Add(float64(len(data)))
+	if !bytes.Equal(hash.GenerateChecksum(data), checksum) {
+		return corespdb.ScrubPieceCorrupted, nil
+	}
+	return "", nil
+}
+
+// waitScrubLimiter blocks until the limiter permits reading n bytes, the bytes are acquired in burst sized
+// chunks because the limiter rejects to wait for more than the burst at once.
+func (e *ExecuteModular) waitScrubLimiter(ctx context.Context, n int) error {
+	burst := e.scrubLimiter.Burst()
+	for n > 0 {
+		chunk := n
+		if chunk > burst {
+			chunk = burst
+		}
+		if err := e.scrubLimiter.WaitN(ctx, chunk); err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+// recoverScrubPiece asks manager to recover the bad piece, returns whether the recovery piece task is created
+// or is existed. Only the pieces of ec objects can be recovered from the other sps, the recovery piece task does
+// not support the replica objects, so their bad pieces are only recorded with recovering false and counted as
+// unrecoverable, they are listed by the query.scrub.report command to be repaired manually.
+func (e *ExecuteModular) recoverScrubPiece(ctx context.Context, objectInfo *storagetypes.ObjectInfo,
+	params *storagetypes.Params, segmentIdx uint32, redundancyIdx int32) bool {
+	if objectInfo.GetRedundancyType() != storagetypes.REDUNDANCY_EC_TYPE {
+		metrics.ScrubPieceCounter.WithLabelValues("unrecoverable").Inc()
+		log.CtxWarnw(ctx, "bad piece of replica object can not be recovered automatically", "object_id",
+			objectInfo.Id.Uint64(), "segment_idx", segmentIdx, "redundancy_idx", redundancyIdx)
+		return false
+	}
+	recoveryTask := &gfsptask.GfSpRecoverPieceTask{}
+	recoveryTask.InitRecoverPieceTask(objectInfo, params, coretask.DefaultSmallerPriority, segmentIdx,
+		redundancyIdx, params.VersionedParams.GetMaxSegmentSize(), scrubRecoveryTimeout, scrubRecoveryMaxRetry)
+	// the piece may be recovering by the task that is created last round
+	if err := e.baseApp.GfSpClient().ReportTask(ctx, recoveryTask); err != nil && !errors.Is(err, manager.ErrRepeatedTask) {
+		log.CtxErrorw(ctx, "failed to create recovery piece task for bad piece", "object_id",
+			objectInfo.Id.Uint64(), "segment_idx", segmentIdx, "redundancy_idx", redundancyIdx, "error", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,265 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-common/go/hash"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsppieceop"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/consensus"
+	"github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	metadatatypes "github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	"github.com/bnb-chain/greenfield-storage-provider/store/piecestore/storage"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const scrubTestMaxSegmentSize = 16
+
+// scrubTestServer serves the objects of the gvg as metadata, and records the reported tasks as manager.
+type scrubTestServer struct {
+	metadatatypes.UnimplementedGfSpMetadataServiceServer
+	gfspserver.UnimplementedGfSpManageServiceServer
+
+	objects []*metadatatypes.ObjectDetails
+
+	mux          sync.Mutex
+	recoverTasks []*gfsptask.GfSpRecoverPieceTask
+}
+
+func (s *scrubTestServer) GfSpListObjectsInGVG(_ context.Context, req *metadatatypes.GfSpListObjectsInGVGRequest) (
+	*metadatatypes.GfSpListObjectsInGVGResponse, error) {
+	resp := &metadatatypes.GfSpListObjectsInGVGResponse{}
+	for _, object := range s.objects {
+		if object.GetObject().GetObjectInfo().Id.Uint64() > req.GetStartAfter() &&
+			uint32(len(resp.Objects)) < req.GetLimit() {
+			resp.Objects = append(resp.Objects, object)
+		}
+	}
+	return resp, nil
+}
+
+func (s *scrubTestServer) GfSpReportTask(_ context.Context, req *gfspserver.GfSpReportTaskRequest) (
+	*gfspserver.GfSpReportTaskResponse, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if t := req.GetRecoverPieceTask(); t != nil {
+		s.recoverTasks = append(s.recoverTasks, t)
+	}
+	return &gfspserver.GfSpReportTaskResponse{}, nil
+}
+
+type scrubTestConsensus struct {
+	consensus.NullConsensus
+}
+
+func (*scrubTestConsensus) QueryStorageParamsByTimestamp(context.Context, int64) (*storagetypes.Params, error) {
+	return &storagetypes.Params{VersionedParams: storagetypes.VersionedParams{
+		MaxSegmentSize:          scrubTestMaxSegmentSize,
+		RedundantDataChunkNum:   4,
+		RedundantParityChunkNum: 2,
+	}}, nil
+}
+
+// scrubTestPieceStore stores the pieces in memory, and records the read pieces.
+type scrubTestPieceStore struct {
+	piecestore.PieceStore
+
+	mux    sync.Mutex
+	pieces map[string][]byte
+	getErr error
+	reads  []string
+}
+
+func (s *scrubTestPieceStore) GetPiece(_ context.Context, key string, _, _ int64) ([]byte, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.reads = append(s.reads, key)
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+	data, ok := s.pieces[key]
+	if !ok {
+		return nil, storage.ErrNoSuchObject
+	}
+	return data, nil
+}
+
+func setupScrubTest(t *testing.T, db corespdb.SPDB, store piecestore.PieceStore, objects []*metadatatypes.ObjectDetails,
+	limiter *rate.Limiter) (*ExecuteModular, *scrubTestServer) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &scrubTestServer{objects: objects}
+	grpcServer := grpc.NewServer()
+	metadatatypes.RegisterGfSpMetadataServiceServer(grpcServer, server)
+	gfspserver.RegisterGfSpManageServiceServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	cfg := &gfspconfig.GfSpConfig{GRPCAddress: listener.Addr().String()}
+	baseApp, err := gfspapp.NewGfSpBaseApp(cfg, gfspconfig.CustomizeGfSpDB(db), gfspconfig.CustomizePieceStore(store),
+		gfspconfig.CustomizeConsensus(&scrubTestConsensus{}))
+	require.NoError(t, err)
+	return &ExecuteModular{baseApp: baseApp, scrubObjectBatchNumber: 10, scrubLimiter: limiter}, server
+}
+
+func newScrubTestObject(id uint64, payloadSize uint64, status storagetypes.ObjectStatus) *metadatatypes.ObjectDetails {
+	return &metadatatypes.ObjectDetails{Object: &metadatatypes.Object{ObjectInfo: &storagetypes.ObjectInfo{
+		Id:             sdkmath.NewUint(id),
+		PayloadSize:    payloadSize,
+		ObjectStatus:   status,
+		RedundancyType: storagetypes.REDUNDANCY_EC_TYPE,
+	}}}
+}
+
+func TestExecuteModular_HandleScrubGVGTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	pieceOp := &gfsppieceop.GfSpPieceOp{}
+	store := &scrubTestPieceStore{pieces: make(map[string][]byte)}
+
+	// the object 1 is healthy, the object 2 has a corrupted piece and a missing piece, the integrity meta of the
+	// object 3 is missing, the object 4 is not sealed, the object 5 is removed
+	healthy, corrupted := []byte("0123456789abcdef"), []byte("x")
+	store.pieces[pieceOp.ECPieceKey(1, 0, 0)] = healthy
+	store.pieces[pieceOp.ECPieceKey(2, 0, 0)] = corrupted
+	store.pieces[pieceOp.ECPieceKey(3, 0, 0)] = healthy
+	db.EXPECT().GetObjectIntegrity(uint64(1), int32(0)).Return(&corespdb.IntegrityMeta{
+		PieceChecksumList: [][]byte{hash.GenerateChecksum(healthy)}}, nil)
+	db.EXPECT().GetObjectIntegrity(uint64(2), int32(0)).Return(&corespdb.IntegrityMeta{
+		PieceChecksumList: [][]byte{hash.GenerateChecksum(healthy), hash.GenerateChecksum(healthy)}}, nil)
+	db.EXPECT().GetObjectIntegrity(uint64(3), int32(0)).Return(nil, gorm.ErrRecordNotFound)
+	var scrubPieces []*corespdb.ScrubPiece
+	db.EXPECT().UpdateScrubPiece(gomock.Any()).DoAndReturn(func(piece *corespdb.ScrubPiece) error {
+		scrubPieces = append(scrubPieces, piece)
+		return nil
+	}).Times(4)
+
+	removed := newScrubTestObject(5, 16, storagetypes.OBJECT_STATUS_SEALED)
+	removed.Object.Removed = true
+	objects := []*metadatatypes.ObjectDetails{
+		newScrubTestObject(1, 16, storagetypes.OBJECT_STATUS_SEALED),
+		newScrubTestObject(2, 32, storagetypes.OBJECT_STATUS_SEALED),
+		newScrubTestObject(3, 32, storagetypes.OBJECT_STATUS_SEALED),
+		newScrubTestObject(4, 16, storagetypes.OBJECT_STATUS_CREATED),
+		removed,
+	}
+	executor, server := setupScrubTest(t, db, store, objects, rate.NewLimiter(rate.Inf, 1024))
+
+	task := &gfsptask.GfSpScrubGVGTask{}
+	task.InitScrubGVGTask(0, 1, 0, 0, 10)
+	executor.HandleScrubGVGTask(context.Background(), task)
+	require.NoError(t, task.Error())
+	assert.True(t, task.GetFinished())
+	assert.Equal(t, uint64(5), task.GetLastScrubbedObjectId())
+	objectNumber, pieceNumber, corruptedNumber, missingNumber := task.GetScrubStatus()
+	assert.Equal(t, uint64(3), objectNumber)
+	assert.Equal(t, uint64(5), pieceNumber)
+	assert.Equal(t, uint64(1), corruptedNumber)
+	assert.Equal(t, uint64(1), missingNumber)
+
+	states := make(map[string]corespdb.ScrubPieceState)
+	for _, piece := range scrubPieces {
+		states[piece.PieceKey] = piece.State
+		// only the corrupted and missing pieces are recovered
+		assert.Equal(t, piece.State != corespdb.ScrubPieceNoIntegrity, piece.Recovering)
+	}
+	assert.Equal(t, map[string]corespdb.ScrubPieceState{
+		pieceOp.ECPieceKey(2, 0, 0): corespdb.ScrubPieceCorrupted,
+		pieceOp.ECPieceKey(2, 1, 0): corespdb.ScrubPieceMissing,
+		pieceOp.ECPieceKey(3, 0, 0): corespdb.ScrubPieceNoIntegrity,
+		pieceOp.ECPieceKey(3, 1, 0): corespdb.ScrubPieceNoIntegrity,
+	}, states)
+	require.Len(t, server.recoverTasks, 2)
+	for _, recoverTask := range server.recoverTasks {
+		assert.Equal(t, uint64(2), recoverTask.GetObjectInfo().Id.Uint64())
+	}
+	// the pieces without the integrity meta are not read
+	assert.NotContains(t, store.reads, pieceOp.ECPieceKey(3, 0, 0))
+}
+
+func TestExecuteModular_HandleScrubGVGTaskLimiterFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	store := &scrubTestPieceStore{pieces: make(map[string][]byte)}
+	db.EXPECT().GetObjectIntegrity(uint64(1), int32(0)).Return(&corespdb.IntegrityMeta{
+		PieceChecksumList: [][]byte{[]byte("checksum")}}, nil)
+	objects := []*metadatatypes.ObjectDetails{newScrubTestObject(1, 16, storagetypes.OBJECT_STATUS_SEALED)}
+	// reading the piece needs more time than the deadline of the task
+	executor, _ := setupScrubTest(t, db, store, objects, rate.NewLimiter(rate.Limit(0.001), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	task := &gfsptask.GfSpScrubGVGTask{}
+	task.InitScrubGVGTask(0, 1, 0, 0, 10)
+	executor.HandleScrubGVGTask(ctx, task)
+	assert.Error(t, task.Error())
+	assert.False(t, task.GetFinished())
+	assert.Empty(t, store.reads)
+}
+
+func TestExecuteModular_HandleScrubGVGTaskStoreFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	// the piece store is unavailable, the piece is not recorded as missing
+	store := &scrubTestPieceStore{pieces: make(map[string][]byte), getErr: errors.New("connection refused")}
+	db.EXPECT().GetObjectIntegrity(uint64(1), int32(0)).Return(&corespdb.IntegrityMeta{
+		PieceChecksumList: [][]byte{[]byte("checksum")}}, nil)
+	db.EXPECT().UpdateScrubPiece(gomock.Any()).Times(0)
+	objects := []*metadatatypes.ObjectDetails{newScrubTestObject(1, 16, storagetypes.OBJECT_STATUS_SEALED)}
+	executor, server := setupScrubTest(t, db, store, objects, rate.NewLimiter(rate.Inf, 1024))
+
+	task := &gfsptask.GfSpScrubGVGTask{}
+	task.InitScrubGVGTask(0, 1, 0, 0, 10)
+	executor.HandleScrubGVGTask(context.Background(), task)
+	assert.Error(t, task.Error())
+	assert.False(t, task.GetFinished())
+	_, _, _, missingNumber := task.GetScrubStatus()
+	assert.Zero(t, missingNumber)
+	assert.Empty(t, server.recoverTasks)
+}
+
+func TestExecuteModular_HandleScrubGVGTaskReplicaObject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	store := &scrubTestPieceStore{pieces: make(map[string][]byte)}
+	pieceOp := &gfsppieceop.GfSpPieceOp{}
+	db.EXPECT().GetObjectIntegrity(uint64(1), int32(-1)).Return(&corespdb.IntegrityMeta{
+		PieceChecksumList: [][]byte{[]byte("checksum")}}, nil)
+	var scrubPiece *corespdb.ScrubPiece
+	db.EXPECT().UpdateScrubPiece(gomock.Any()).DoAndReturn(func(piece *corespdb.ScrubPiece) error {
+		scrubPiece = piece
+		return nil
+	})
+	object := newScrubTestObject(1, 16, storagetypes.OBJECT_STATUS_SEALED)
+	object.Object.ObjectInfo.RedundancyType = storagetypes.REDUNDANCY_REPLICA_TYPE
+	executor, server := setupScrubTest(t, db, store, []*metadatatypes.ObjectDetails{object},
+		rate.NewLimiter(rate.Inf, 1024))
+
+	task := &gfsptask.GfSpScrubGVGTask{}
+	task.InitScrubGVGTask(0, 1, -1, 0, 10)
+	executor.HandleScrubGVGTask(context.Background(), task)
+	require.NoError(t, task.Error())
+	// the missing piece of the replica object is recorded, but it is not recovered
+	require.NotNil(t, scrubPiece)
+	assert.Equal(t, pieceOp.SegmentPieceKey(1, 0), scrubPiece.PieceKey)
+	assert.Equal(t, corespdb.ScrubPieceMissing, scrubPiece.State)
+	assert.False(t, scrubPiece.Recovering)
+	assert.Empty(t, server.recoverTasks)
+}
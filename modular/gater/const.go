@@ -16,12 +16,24 @@ const (
 	ContentTypeJSONHeaderValue = "application/json"
 	// ContentTypeXMLHeaderValue is used to indicate xml
 	ContentTypeXMLHeaderValue = "application/xml"
+	// ETagHeader is used to indicate the entity tag of the uploaded part or object
+	ETagHeader = "ETag"
 	// ContentDispositionHeader is used to indicate the media disposition of the resource
 	ContentDispositionHeader = "Content-Disposition"
 	// ContentDispositionAttachmentValue is used to indicate attachment
 	ContentDispositionAttachmentValue = "attachment"
 	// ContentDispositionInlineValue is used to indicate inline
 	ContentDispositionInlineValue = "inline"
+	// LastModifiedHeader is used to indicate the time when the object is created
+	LastModifiedHeader = "Last-Modified"
+	// IfMatchHeader makes the request conditional on the entity tag of the object matches
+	IfMatchHeader = "If-Match"
+	// IfNoneMatchHeader makes the request conditional on the entity tag of the object mismatches
+	IfNoneMatchHeader = "If-None-Match"
+	// IfModifiedSinceHeader makes the request conditional on the object is modified after the date
+	IfModifiedSinceHeader = "If-Modified-Since"
+	// IfUnmodifiedSinceHeader makes the request conditional on the object is not modified after the date
+	IfUnmodifiedSinceHeader = "If-Unmodified-Since"
 
 	// SignAlgorithm uses secp256k1 with the ECDSA algorithm
 	SignAlgorithm = "ECDSA-secp256k1"
@@ -47,14 +59,31 @@ const (
 	ResumableUploadComplete = "complete"
 	ResumableUploadOffset   = "offset"
 	GetSecondaryPieceData   = "get-piece"
+	// MultipartUploadsQuery defines initiate multipart upload query, which is used to route request
+	MultipartUploadsQuery = "uploads"
+	// MultipartUploadIDQuery defines the upload id of the multipart upload, which is used to route request
+	MultipartUploadIDQuery = "uploadId"
+	// MultipartPartNumberQuery defines the part number of the multipart upload, which is used to route request
+	MultipartPartNumberQuery = "partNumber"
 	// GetBucketReadQuotaQuery defines bucket read quota query, which is used to route request
 	GetBucketReadQuotaQuery = "read-quota"
 	// GetBucketReadQuotaMonthQuery defines bucket read quota query month
 	GetBucketReadQuotaMonthQuery = "year-month"
+	// BucketCORSQuery defines bucket cors query, which is used to route the bucket cors policy requests
+	BucketCORSQuery = "cors"
+	// BucketNotificationQuery defines bucket notification query, which is used to route the bucket webhook requests
+	BucketNotificationQuery = "notification"
 	// ListBucketReadRecordQuery defines list bucket read record query, which is used to route request
 	ListBucketReadRecordQuery = "list-read-record"
 	// ListBucketReadRecordMaxRecordsQuery defines list read record max num
 	ListBucketReadRecordMaxRecordsQuery = "max-records"
+	// ReadUsageQuery defines get bucket read usage query, which is used to route request
+	ReadUsageQuery = "read-usage"
+	// ReadUsageGranularityQuery defines the granularity of the read usage, hour or day returns the usage of
+	// every period, empty returns the top consumers of the time range
+	ReadUsageGranularityQuery = "granularity"
+	// ReadUsageDimensionQuery defines the dimension of the read usage, bucket, object or user
+	ReadUsageDimensionQuery = "dimension"
 	// ListObjectsMaxKeysQuery defines the maximum number of keys returned to the response
 	ListObjectsMaxKeysQuery = "max-keys"
 	// ListObjectsStartAfterQuery defines where you want to start listing from
@@ -73,6 +102,28 @@ const (
 	ListObjectsPrefixQuery = "prefix"
 	// ListObjectsIncludeRemovedQuery defines whether include removed objects
 	ListObjectsIncludeRemovedQuery = "include-removed"
+	// ListObjectsContentTypeQuery limits the response to the objects of the content type
+	ListObjectsContentTypeQuery = "content-type"
+	// ListObjectsMinSizeQuery limits the response to the objects whose payload size is not less than it
+	ListObjectsMinSizeQuery = "min-size"
+	// ListObjectsMaxSizeQuery limits the response to the objects whose payload size is not greater than it
+	ListObjectsMaxSizeQuery = "max-size"
+	// ListObjectsCreateTimeStartQuery limits the response to the objects created not before the unix timestamp
+	ListObjectsCreateTimeStartQuery = "create-time-start"
+	// ListObjectsCreateTimeEndQuery limits the response to the objects created not after the unix timestamp
+	ListObjectsCreateTimeEndQuery = "create-time-end"
+	// ListObjectsOwnerQuery limits the response to the objects owned by the account address
+	ListObjectsOwnerQuery = "owner"
+	// ListObjectsVisibilityQuery limits the response to the objects of the visibility, e.g. VISIBILITY_TYPE_PUBLIC_READ
+	ListObjectsVisibilityQuery = "visibility"
+	// ListObjectsStatusQuery limits the response to the objects of the status, e.g. OBJECT_STATUS_SEALED
+	ListObjectsStatusQuery = "object-status"
+	// ListObjectsSortByQuery defines the order of the objects, name, size or create_time
+	ListObjectsSortByQuery = "sort-by"
+	// ListObjectsSortOrderQuery defines the direction of the order, asc or desc
+	ListObjectsSortOrderQuery = "sort-order"
+	// ListObjectsIncludeStatsQuery defines whether include the number and the total payload size of the objects
+	ListObjectsIncludeStatsQuery = "include-stats"
 	// GetBucketMetaQuery defines get bucket metadata query, which is used to route request
 	GetBucketMetaQuery = "bucket-meta"
 	// GetObjectMetaQuery defines get object metadata query, which is used to route request
@@ -190,6 +241,8 @@ const (
 	GnfdReplicatePieceApprovalHeader = "X-Gnfd-Replicate-Piece-Approval-Msg"
 	// GnfdObjectIDHeader defines object id
 	GnfdObjectIDHeader = "X-Gnfd-Object-ID"
+	// GnfdObjectStatusHeader defines the object status on chain, e.g. OBJECT_STATUS_SEALED
+	GnfdObjectStatusHeader = "X-Gnfd-Object-Status"
 	// GnfdPieceIndexHeader defines piece idx, which is used by challenge
 	GnfdPieceIndexHeader = "X-Gnfd-Piece-Index"
 	// GnfdRedundancyIndexHeader defines redundancy idx, which is used by challenge and receiver
@@ -210,6 +263,24 @@ const (
 	GnfdIntegrityHashSignatureHeader = "X-Gnfd-Integrity-Hash-Signature"
 	// GnfdUserAddressHeader defines the user address
 	GnfdUserAddressHeader = "X-Gnfd-User-Address"
+	// GnfdPresignAlgorithmQuery defines the sign algorithm of the presigned url
+	GnfdPresignAlgorithmQuery = "X-Gnfd-Algorithm"
+	// GnfdPresignCredentialQuery defines the account who signs the presigned url
+	GnfdPresignCredentialQuery = "X-Gnfd-Credential"
+	// GnfdPresignDateQuery defines the time when the presigned url is signed, formatted in PresignDateFormat
+	GnfdPresignDateQuery = "X-Gnfd-Date"
+	// GnfdPresignExpiresQuery defines the seconds the presigned url is valid for since GnfdPresignDateQuery
+	GnfdPresignExpiresQuery = "X-Gnfd-Expires"
+	// GnfdPresignSignatureQuery defines the hex encoded signature of the presigned url
+	GnfdPresignSignatureQuery = "X-Gnfd-Signature"
+	// PresignDateFormat defines the ISO 8601 basic format of GnfdPresignDateQuery, e.g. 20230420T083412Z
+	PresignDateFormat = "20060102T150405Z"
+	// MaxPresignExpiresInSec defines the max valid period of the presigned url
+	MaxPresignExpiresInSec = 3600 * 24 * 7
+	// MaxPresignClockSkewInSec defines the allowed clock skew between the signer and the sp
+	MaxPresignClockSkewInSec = 60 * 15
+	// MaxRangeNumber defines the max number of the ranges in the Range header of the get object request
+	MaxRangeNumber = 64
 	// GnfdResponseXMLVersion defines the response xml version
 	GnfdResponseXMLVersion = "1.0"
 
@@ -279,4 +350,6 @@ const (
 	GatewayFailurePutObject        = "gateway_put_object_failure"
 	GatewaySuccessGetObject        = "gateway_get_object_success"
 	GatewayFailureGetObject        = "gateway_get_object_failure"
+	GatewaySuccessHeadObject       = "gateway_head_object_success"
+	GatewayFailureHeadObject       = "gateway_head_object_failure"
 )
@@ -0,0 +1,267 @@
+package gater
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
+	"github.com/bnb-chain/greenfield/types/s3util"
+)
+
+const (
+	// bucketCORSCacheSize defines the max number of the bucket cors policies cached by the gateway
+	bucketCORSCacheSize = 10000
+	// bucketCORSCacheTTL defines how long the cached bucket cors policy is valid, the policy updated
+	// by the other gateway instances takes effect after it
+	bucketCORSCacheTTL = time.Minute
+	// maxBucketCORSBodySize defines the max size of the put bucket cors request body
+	maxBucketCORSBodySize = 64 * 1024
+	// maxBucketCORSListLength defines the max number of the items in every list of the bucket cors policy
+	maxBucketCORSListLength = 100
+)
+
+// corsAllowedMethods are the methods which can be allowed by the cors policy.
+var corsAllowedMethods = []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPost, http.MethodDelete}
+
+// bucketCORSCacheEntry caches the cors policy of the bucket, the nil policy means the bucket has no override.
+type bucketCORSCacheEntry struct {
+	cfg      *localhttp.CORSConfig
+	expireAt time.Time
+}
+
+// CORSConfiguration is the body of the bucket cors request and response.
+type CORSConfiguration struct {
+	XMLName          xml.Name `xml:"CORSConfiguration"`
+	AllowedOrigins   []string `xml:"AllowedOrigin"`
+	AllowedMethods   []string `xml:"AllowedMethod"`
+	AllowedHeaders   []string `xml:"AllowedHeader"`
+	ExposedHeaders   []string `xml:"ExposeHeader"`
+	AllowCredentials bool     `xml:"AllowCredentials"`
+	MaxAgeSeconds    int64    `xml:"MaxAgeSeconds"`
+}
+
+func (c *CORSConfiguration) validate() error {
+	if len(c.AllowedOrigins) == 0 || c.MaxAgeSeconds < 0 {
+		return ErrInvalidCORSConfiguration
+	}
+	for _, list := range [][]string{c.AllowedOrigins, c.AllowedMethods, c.AllowedHeaders, c.ExposedHeaders} {
+		if len(list) > maxBucketCORSListLength {
+			return ErrInvalidCORSConfiguration
+		}
+		for _, item := range list {
+			// the items are joined by comma in db
+			if item == "" || strings.ContainsAny(item, ", ") {
+				return ErrInvalidCORSConfiguration
+			}
+		}
+	}
+	for _, origin := range c.AllowedOrigins {
+		if strings.Count(origin, "*") > 1 || (c.AllowCredentials && origin == "*") {
+			return ErrInvalidCORSConfiguration
+		}
+	}
+	for _, method := range c.AllowedMethods {
+		allowed := false
+		for _, m := range corsAllowedMethods {
+			if method == m {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return ErrInvalidCORSConfiguration
+		}
+	}
+	return nil
+}
+
+// corsBucketName returns the bucket name of the request, the bucket is parsed from the host for the virtual
+// hosted style requests, and from the path for the path style and the universal endpoint requests.
+func (g *GateModular) corsBucketName(r *http.Request) string {
+	if host := strings.ToLower(r.Host); strings.HasSuffix(host, "."+g.domain) {
+		return strings.TrimSuffix(host, "."+g.domain)
+	}
+	segments := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 3)
+	if len(segments) > 1 && (segments[0] == "download" || segments[0] == "view") {
+		return segments[1]
+	}
+	return segments[0]
+}
+
+// bucketCORSPolicy returns the cors policy of the requested bucket, returns nil if the bucket has no
+// policy, then the gateway default policy is used.
+func (g *GateModular) bucketCORSPolicy(r *http.Request) *localhttp.CORSConfig {
+	bucketName := g.corsBucketName(r)
+	if s3util.CheckValidBucketName(bucketName) != nil {
+		return nil
+	}
+	if entry, ok := g.corsCache.Get(bucketName); ok && time.Now().Before(entry.(*bucketCORSCacheEntry).expireAt) {
+		return entry.(*bucketCORSCacheEntry).cfg
+	}
+	cors, err := g.baseApp.GfSpDB().GetBucketCORS(bucketName)
+	if err != nil {
+		// the failure is not cached, the default policy is used until the db recovers
+		log.Errorw("failed to get bucket cors", "bucket_name", bucketName, "error", err)
+		return nil
+	}
+	var cfg *localhttp.CORSConfig
+	if cors != nil {
+		cfg = &localhttp.CORSConfig{
+			AllowedOrigins:   cors.AllowedOrigins,
+			AllowedMethods:   cors.AllowedMethods,
+			AllowedHeaders:   cors.AllowedHeaders,
+			ExposedHeaders:   cors.ExposedHeaders,
+			AllowCredentials: cors.AllowCredentials,
+			MaxAgeSec:        cors.MaxAgeSec,
+		}
+		// the unspecified lists inherit the gateway default policy
+		if len(cfg.AllowedMethods) == 0 {
+			cfg.AllowedMethods = g.defaultCORS.AllowedMethods
+		}
+		if len(cfg.AllowedHeaders) == 0 {
+			cfg.AllowedHeaders = g.defaultCORS.AllowedHeaders
+		}
+		if len(cfg.ExposedHeaders) == 0 {
+			cfg.ExposedHeaders = g.defaultCORS.ExposedHeaders
+		}
+		if err = cfg.Validate(); err != nil {
+			// the policy saved before the validation is tightened is ignored
+			log.Errorw("invalid bucket cors", "bucket_name", bucketName, "error", err)
+			cfg = nil
+		}
+	}
+	g.corsCache.Add(bucketName, &bucketCORSCacheEntry{cfg: cfg, expireAt: time.Now().Add(bucketCORSCacheTTL)})
+	return cfg
+}
+
+// corsPreflightHandler handles the cors preflight request of all the routes.
+func (g *GateModular) corsPreflightHandler(w http.ResponseWriter, r *http.Request) {
+	g.cors.Preflight(w, r)
+}
+
+// checkBucketOwner verifies the request is sent by the owner of the bucket.
+func (g *GateModular) checkBucketOwner(reqCtx *RequestContext) error {
+	bucketInfo, err := g.baseApp.Consensus().QueryBucketInfo(reqCtx.Context(), reqCtx.bucketName)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket info from consensus", "error", err)
+		return ErrConsensus
+	}
+	if bucketInfo.GetOwner() != reqCtx.Account() {
+		log.CtxErrorw(reqCtx.Context(), "no permission to operate, the account is not the bucket owner",
+			"owner", bucketInfo.GetOwner())
+		return ErrNoPermission
+	}
+	return nil
+}
+
+// bucketCORSHandler handles the get, put and delete bucket cors requests, only the bucket owner can manage the policy.
+func (g *GateModular) bucketCORSHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		reqCtx *RequestContext
+		body   []byte
+	)
+	startTime := time.Now()
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(startTime).Seconds())
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if err = s3util.CheckValidBucketName(reqCtx.bucketName); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to check bucket name", "bucket_name", reqCtx.bucketName, "error", err)
+		return
+	}
+	if err = g.checkBucketOwner(reqCtx); err != nil {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var cors *spdb.BucketCORS
+		if cors, err = g.baseApp.GfSpDB().GetBucketCORS(reqCtx.bucketName); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to get bucket cors", "error", err)
+			err = ErrSPDB
+			return
+		}
+		if cors == nil {
+			err = ErrNoSuchCORSConfiguration
+			return
+		}
+		xmlBody, marshalErr := xml.Marshal(&CORSConfiguration{
+			AllowedOrigins:   cors.AllowedOrigins,
+			AllowedMethods:   cors.AllowedMethods,
+			AllowedHeaders:   cors.AllowedHeaders,
+			ExposedHeaders:   cors.ExposedHeaders,
+			AllowCredentials: cors.AllowCredentials,
+			MaxAgeSeconds:    cors.MaxAgeSec,
+		})
+		if marshalErr != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to marshal xml", "error", marshalErr)
+			err = ErrEncodeResponse
+			return
+		}
+		w.Header().Set(ContentTypeHeader, ContentTypeXMLHeaderValue)
+		if _, err = w.Write(xmlBody); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to write the response", "error", err)
+		}
+	case http.MethodPut:
+		if body, err = io.ReadAll(io.LimitReader(r.Body, maxBucketCORSBodySize+1)); err != nil ||
+			len(body) > maxBucketCORSBodySize {
+			log.CtxErrorw(reqCtx.Context(), "failed to read the bucket cors body", "error", err)
+			err = ErrInvalidCORSConfiguration
+			return
+		}
+		cfg := &CORSConfiguration{}
+		if err = xml.Unmarshal(body, cfg); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to unmarshal the bucket cors body", "error", err)
+			err = ErrInvalidCORSConfiguration
+			return
+		}
+		if err = cfg.validate(); err != nil {
+			return
+		}
+		if err = g.baseApp.GfSpDB().UpdateBucketCORS(&spdb.BucketCORS{
+			BucketName:       reqCtx.bucketName,
+			AllowedOrigins:   cfg.AllowedOrigins,
+			AllowedMethods:   cfg.AllowedMethods,
+			AllowedHeaders:   cfg.AllowedHeaders,
+			ExposedHeaders:   cfg.ExposedHeaders,
+			AllowCredentials: cfg.AllowCredentials,
+			MaxAgeSec:        cfg.MaxAgeSeconds,
+		}); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to update bucket cors", "error", err)
+			err = ErrSPDB
+			return
+		}
+		g.corsCache.Remove(reqCtx.bucketName)
+	case http.MethodDelete:
+		if err = g.baseApp.GfSpDB().DeleteBucketCORS(reqCtx.bucketName); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to delete bucket cors", "error", err)
+			err = ErrSPDB
+			return
+		}
+		g.corsCache.Remove(reqCtx.bucketName)
+	}
+}
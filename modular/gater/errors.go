@@ -51,6 +51,38 @@ var (
 	ErrMigrateApproval        = gfsperrors.Register(module.GateModularName, http.StatusInternalServerError, 50033, "server slipped away, try again later")
 	ErrNotifySwapOut          = gfsperrors.Register(module.GateModularName, http.StatusInternalServerError, 50034, "server slipped away, try again later")
 	ErrInvalidRedundancyIndex = gfsperrors.Register(module.GateModularName, http.StatusInternalServerError, 50035, "invalid redundancy index")
+	ErrInvalidUploadID        = gfsperrors.Register(module.GateModularName, http.StatusNotFound, 50036, "the specified multipart upload does not exist")
+	ErrInvalidPartNumber      = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50037, "invalid part number")
+	ErrMalformedXML           = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50038, "the xml you provided was not well-formed")
+	ErrPresignedURLFormat     = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50039, "presigned url query params format error")
+	ErrPresignedURLExpired    = gfsperrors.Register(module.GateModularName, http.StatusForbidden, 50040, "presigned url is expired or not yet valid")
+	ErrPresignedURLNotAllowed = gfsperrors.Register(module.GateModularName, http.StatusForbidden, 50041, "presigned url is only allowed to get or put object")
+
+	ErrS3AccessDenied                   = gfsperrors.Register(module.GateModularName, http.StatusForbidden, 50042, "Access Denied")
+	ErrS3InvalidAccessKeyID             = gfsperrors.Register(module.GateModularName, http.StatusForbidden, 50043, "The AWS access key Id you provided does not exist in our records.")
+	ErrS3SignatureDoesNotMatch          = gfsperrors.Register(module.GateModularName, http.StatusForbidden, 50044, "The request signature we calculated does not match the signature you provided.")
+	ErrS3SignatureVersionNotSupported   = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50045, "The authorization mechanism you have provided is not supported. Please use AWS4-HMAC-SHA256.")
+	ErrS3AuthorizationMalformed         = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50046, "The authorization header is malformed.")
+	ErrS3AuthorizationQueryMalformed    = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50047, "Error parsing the X-Amz-Credential parameter.")
+	ErrS3RequestTimeTooSkewed           = gfsperrors.Register(module.GateModularName, http.StatusForbidden, 50048, "The difference between the request time and the server's time is too large.")
+	ErrS3ExpiredPresignRequest          = gfsperrors.Register(module.GateModularName, http.StatusForbidden, 50049, "Request has expired.")
+	ErrS3ContentSHA256Mismatch          = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50050, "The provided 'x-amz-content-sha256' header does not match what was computed.")
+	ErrS3IncompleteBody                 = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50051, "You did not provide the number of bytes specified by the Content-Length HTTP header.")
+	ErrS3InvalidRequest                 = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50052, "Invalid Request.")
+	ErrS3NoSuchKey                      = gfsperrors.Register(module.GateModularName, http.StatusNotFound, 50053, "The specified key does not exist.")
+	ErrS3NoSuchBucket                   = gfsperrors.Register(module.GateModularName, http.StatusNotFound, 50054, "The specified bucket does not exist.")
+	ErrS3InvalidObjectState             = gfsperrors.Register(module.GateModularName, http.StatusForbidden, 50055, "The operation is not valid for the current state of the object.")
+	ErrS3NotImplemented                 = gfsperrors.Register(module.GateModularName, http.StatusNotImplemented, 50056, "A header or query you provided implies functionality that is not implemented.")
+	ErrS3InvalidRange                   = gfsperrors.Register(module.GateModularName, http.StatusRequestedRangeNotSatisfiable, 50057, "The requested range is not satisfiable.")
+	ErrS3InternalError                  = gfsperrors.Register(module.GateModularName, http.StatusInternalServerError, 50058, "We encountered an internal error. Please try again.")
+	ErrPreconditionFailed               = gfsperrors.Register(module.GateModularName, http.StatusPreconditionFailed, 50059, "at least one of the pre-conditions you specified did not hold")
+	ErrRangeNotSatisfiable              = gfsperrors.Register(module.GateModularName, http.StatusRequestedRangeNotSatisfiable, 50060, "none of the ranges is satisfiable")
+	ErrInvalidCORSConfiguration         = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50061, "the cors configuration you provided is invalid")
+	ErrNoSuchCORSConfiguration          = gfsperrors.Register(module.GateModularName, http.StatusNotFound, 50062, "the cors configuration does not exist")
+	ErrSPDB                             = gfsperrors.Register(module.GateModularName, http.StatusInternalServerError, 50063, "server slipped away, try again later")
+	ErrTooManyRequests                  = gfsperrors.Register(module.GateModularName, http.StatusTooManyRequests, 50064, "too many requests, try again later")
+	ErrInvalidNotificationConfiguration = gfsperrors.Register(module.GateModularName, http.StatusBadRequest, 50065, "the notification configuration is invalid")
+	ErrNoSuchNotificationConfiguration  = gfsperrors.Register(module.GateModularName, http.StatusNotFound, 50066, "the notification configuration does not exist")
 )
 
 func MakeErrorResponse(w http.ResponseWriter, err error) {
@@ -5,12 +5,14 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	lru "github.com/hashicorp/golang-lru"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
 )
 
 var _ module.Modular = &GateModular{}
@@ -23,6 +25,17 @@ type GateModular struct {
 	scope       rcmgr.ResourceScope
 	httpServer  *http.Server
 
+	// s3HTTPAddress is the listen address of the s3 compatible api, it is disabled if empty
+	s3HTTPAddress string
+	s3DomainName  string
+	s3Region      string
+	s3HttpServer  *http.Server
+
+	// defaultCORS is the gateway cors policy, it is overridden by the bucket cors policy stored in sp db
+	defaultCORS localhttp.CORSConfig
+	cors        *localhttp.CORS
+	corsCache   *lru.Cache
+
 	maxListReadQuota int64
 	maxPayloadSize   uint64
 
@@ -40,6 +53,9 @@ func (g *GateModular) Start(ctx context.Context) error {
 	}
 	g.scope = scope
 	go g.server(ctx)
+	if g.s3HTTPAddress != "" {
+		go g.s3Server(ctx)
+	}
 	return nil
 }
 
@@ -60,9 +76,29 @@ func (g *GateModular) server(ctx context.Context) {
 	}
 }
 
+func (g *GateModular) s3Server(ctx context.Context) {
+	router := mux.NewRouter().SkipClean(true)
+	if g.baseApp.EnableMetrics() {
+		router.Use(metrics.DefaultHTTPServerMetrics.InstrumentationHandler)
+	}
+	g.registerS3Handler(router)
+	server := &http.Server{
+		Addr:    g.s3HTTPAddress,
+		Handler: router,
+	}
+	g.s3HttpServer = server
+	if err := server.ListenAndServe(); err != nil {
+		log.Errorw("failed to listen s3 api", "error", err)
+		return
+	}
+}
+
 func (g *GateModular) Stop(ctx context.Context) error {
 	g.scope.Release()
 	g.httpServer.Shutdown(ctx)
+	if g.s3HttpServer != nil {
+		g.s3HttpServer.Shutdown(ctx)
+	}
 	return nil
 }
 
@@ -1,9 +1,14 @@
 package gater
 
 import (
+	"net/http"
+
+	lru "github.com/hashicorp/golang-lru"
+
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
 )
@@ -12,6 +17,17 @@ const (
 	DefaultGatewayDomainName = "localhost:9133"
 	DefaultMaxListReadQuota  = 100
 	DefaultMaxPayloadSize    = 2 * 1024 * 1024 * 1024
+	DefaultS3Region          = "us-east-1"
+)
+
+var (
+	// DefaultCORSAllowedMethods defines the default methods allowed by the cors policy
+	DefaultCORSAllowedMethods = []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPost, http.MethodDelete}
+	// DefaultCORSAllowedHeaders defines the default request headers allowed by the cors policy
+	DefaultCORSAllowedHeaders = []string{"*"}
+	// DefaultCORSExposedHeaders defines the default response headers exposed to the browser scripts
+	DefaultCORSExposedHeaders = []string{ETagHeader, ContentLengthHeader, ContentRangeHeader, LastModifiedHeader,
+		GnfdRequestIDHeader}
 )
 
 func NewGateModular(app *gfspapp.GfSpBaseApp, cfg *gfspconfig.GfSpConfig) (coremodule.Modular, error) {
@@ -35,12 +51,42 @@ func DefaultGaterOptions(gater *GateModular, cfg *gfspconfig.GfSpConfig) error {
 	if cfg.Bucket.MaxPayloadSize == 0 {
 		cfg.Bucket.MaxPayloadSize = DefaultMaxPayloadSize
 	}
+	if cfg.Gateway.S3Region == "" {
+		cfg.Gateway.S3Region = DefaultS3Region
+	}
 	gater.maxPayloadSize = cfg.Bucket.MaxPayloadSize
 	gater.env = cfg.Env
 	gater.domain = cfg.Gateway.DomainName
 	gater.httpAddress = cfg.Gateway.HTTPAddress
+	gater.s3HTTPAddress = cfg.Gateway.S3HTTPAddress
+	gater.s3DomainName = cfg.Gateway.S3DomainName
+	gater.s3Region = cfg.Gateway.S3Region
 	gater.maxListReadQuota = cfg.Bucket.MaxListReadQuotaNumber
+	if len(cfg.Gateway.CORS.AllowedMethods) == 0 {
+		cfg.Gateway.CORS.AllowedMethods = DefaultCORSAllowedMethods
+	}
+	if len(cfg.Gateway.CORS.AllowedHeaders) == 0 {
+		cfg.Gateway.CORS.AllowedHeaders = DefaultCORSAllowedHeaders
+	}
+	if len(cfg.Gateway.CORS.ExposedHeaders) == 0 {
+		cfg.Gateway.CORS.ExposedHeaders = DefaultCORSExposedHeaders
+	}
+	if err := cfg.Gateway.CORS.Validate(); err != nil {
+		log.Errorw("failed to validate the gateway cors config", "error", err)
+		return err
+	}
+	gater.defaultCORS = cfg.Gateway.CORS
+	gater.cors = localhttp.NewCORS(gater.defaultCORS, gater.bucketCORSPolicy)
+	corsCache, err := lru.New(bucketCORSCacheSize)
+	if err != nil {
+		log.Errorw("failed to new bucket cors cache", "error", err)
+		return err
+	}
+	gater.corsCache = corsCache
 	rateCfg := makeAPIRateLimitCfg(cfg.APIRateLimiter)
+	if rateCfg.SharedStore.On && gater.baseApp.GfSpDB() != nil {
+		rateCfg.SharedCounterStore = &rateLimitCounterStore{db: gater.baseApp.GfSpDB()}
+	}
 	if err := localhttp.NewAPILimiter(rateCfg); err != nil {
 		log.Errorw("failed to new api limiter", "err", err)
 		return err
@@ -71,9 +117,33 @@ func makeAPIRateLimitCfg(cfg localhttp.RateLimiterConfig) *localhttp.APILimiterC
 		}
 	}
 	return &localhttp.APILimiterConfig{
-		PathPattern: defaultMap,
-		HostPattern: patternMap,
-		APILimits:   apiLimitsMap,
-		IPLimitCfg:  cfg.IPLimitCfg,
+		PathPattern:     defaultMap,
+		HostPattern:     patternMap,
+		APILimits:       apiLimitsMap,
+		IPLimitCfg:      cfg.IPLimitCfg,
+		AccountLimitCfg: cfg.AccountLimitCfg,
+		BucketLimitCfg:  cfg.BucketLimitCfg,
+		SharedStore:     cfg.SharedStore,
+	}
+}
+
+// rateLimitCounterStore shares the rate limit counters of the gateway replicas through the sp db.
+type rateLimitCounterStore struct {
+	db spdb.RateLimitDB
+}
+
+func (s *rateLimitCounterStore) IncreaseCounters(counters []*localhttp.SharedCounter) (map[string]int64, error) {
+	dbCounters := make([]*spdb.RateLimitCounter, 0, len(counters))
+	for _, c := range counters {
+		dbCounters = append(dbCounters, &spdb.RateLimitCounter{
+			LimiterKey:            c.Key,
+			Count:                 c.Count,
+			ExpireTimestampSecond: c.ExpireTimestampSecond,
+		})
 	}
+	return s.db.IncreaseRateLimitCounters(dbCounters)
+}
+
+func (s *rateLimitCounterStore) DeleteExpiredCounters(timestampSecond int64) error {
+	return s.db.DeleteExpiredRateLimitCounters(timestampSecond)
 }
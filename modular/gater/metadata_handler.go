@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -232,43 +233,25 @@ func (g *GateModular) listObjectsByBucketNameHandler(w http.ResponseWriter, r *h
 		continuationToken = requestStartAfter
 	}
 
-	objects,
-		keyCount,
-		maxKeys,
-		isTruncated,
-		nextContinuationToken,
-		name,
-		prefix,
-		delimiter,
-		commonPrefixes,
-		continuationToken,
-		err :=
-		g.baseApp.GfSpClient().ListObjectsByBucketName(
-			reqCtx.Context(),
-			requestBucketName,
-			"",
-			maxKeys,
-			requestStartAfter,
-			continuationToken,
-			requestDelimiter,
-			requestPrefix,
-			includedRemoved)
-	if err != nil {
-		log.Errorf("failed to list objects by bucket name", "error", err)
+	listRequest := &types.GfSpListObjectsByBucketNameRequest{
+		BucketName:        requestBucketName,
+		MaxKeys:           maxKeys,
+		StartAfter:        requestStartAfter,
+		ContinuationToken: continuationToken,
+		Delimiter:         requestDelimiter,
+		Prefix:            requestPrefix,
+		IncludeRemoved:    includedRemoved,
+	}
+	if err = parseListObjectsFilter(queryParams, listRequest); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to parse list objects filters", "error", err)
+		err = ErrInvalidQuery
 		return
 	}
 
-	grpcResponse := &types.GfSpListObjectsByBucketNameResponse{
-		Objects:               objects,
-		KeyCount:              keyCount,
-		MaxKeys:               maxKeys,
-		IsTruncated:           isTruncated,
-		NextContinuationToken: nextContinuationToken,
-		Name:                  name,
-		Prefix:                prefix,
-		Delimiter:             delimiter,
-		CommonPrefixes:        commonPrefixes,
-		ContinuationToken:     continuationToken,
+	grpcResponse, err := g.baseApp.GfSpClient().ListObjectsByBucketNameWithFilter(reqCtx.Context(), listRequest)
+	if err != nil {
+		log.Errorf("failed to list objects by bucket name", "error", err)
+		return
 	}
 
 	m := jsonpb.Marshaler{EmitDefaults: true, OrigName: true, EnumsAsInts: true}
@@ -281,6 +264,73 @@ func (g *GateModular) listObjectsByBucketNameHandler(w http.ResponseWriter, r *h
 	w.Write(b.Bytes())
 }
 
+// parseListObjectsFilter parses the filters, the order and the stats option of the list objects request, the
+// objects grouped by the delimiter can not be filtered or sorted.
+func parseListObjectsFilter(queryParams url.Values, req *types.GfSpListObjectsByBucketNameRequest) error {
+	var err error
+	req.ContentType = queryParams.Get(ListObjectsContentTypeQuery)
+	if minSize := queryParams.Get(ListObjectsMinSizeQuery); minSize != "" {
+		if req.MinPayloadSize, err = util.StringToUint64(minSize); err != nil {
+			return err
+		}
+	}
+	if maxSize := queryParams.Get(ListObjectsMaxSizeQuery); maxSize != "" {
+		if req.MaxPayloadSize, err = util.StringToUint64(maxSize); err != nil {
+			return err
+		}
+		if req.MaxPayloadSize < req.MinPayloadSize {
+			return fmt.Errorf("max size %d is less than min size %d", req.MaxPayloadSize, req.MinPayloadSize)
+		}
+	}
+	if createTimeStart := queryParams.Get(ListObjectsCreateTimeStartQuery); createTimeStart != "" {
+		if req.CreateTimeStart, err = util.StringToInt64(createTimeStart); err != nil || req.CreateTimeStart < 0 {
+			return fmt.Errorf("invalid create time start: %s", createTimeStart)
+		}
+	}
+	if createTimeEnd := queryParams.Get(ListObjectsCreateTimeEndQuery); createTimeEnd != "" {
+		if req.CreateTimeEnd, err = util.StringToInt64(createTimeEnd); err != nil || req.CreateTimeEnd < req.CreateTimeStart {
+			return fmt.Errorf("invalid create time end: %s", createTimeEnd)
+		}
+	}
+	if req.Owner = queryParams.Get(ListObjectsOwnerQuery); req.Owner != "" && !common.IsHexAddress(req.Owner) {
+		return fmt.Errorf("invalid owner: %s", req.Owner)
+	}
+	if req.Visibility = queryParams.Get(ListObjectsVisibilityQuery); req.Visibility != "" {
+		if _, ok := storage_types.VisibilityType_value[req.Visibility]; !ok {
+			return fmt.Errorf("invalid visibility: %s", req.Visibility)
+		}
+	}
+	if req.ObjectStatus = queryParams.Get(ListObjectsStatusQuery); req.ObjectStatus != "" {
+		if _, ok := storage_types.ObjectStatus_value[req.ObjectStatus]; !ok {
+			return fmt.Errorf("invalid object status: %s", req.ObjectStatus)
+		}
+	}
+	switch sortBy := bsdb.ObjectSortType(queryParams.Get(ListObjectsSortByQuery)); sortBy {
+	case "", bsdb.ObjectSortByName, bsdb.ObjectSortBySize, bsdb.ObjectSortByCreateTime:
+		req.SortBy = string(sortBy)
+	default:
+		return fmt.Errorf("invalid sort by: %s", sortBy)
+	}
+	switch sortOrder := queryParams.Get(ListObjectsSortOrderQuery); sortOrder {
+	case "", "asc":
+	case "desc":
+		req.SortDesc = true
+	default:
+		return fmt.Errorf("invalid sort order: %s", sortOrder)
+	}
+	if includeStats := queryParams.Get(ListObjectsIncludeStatsQuery); includeStats != "" {
+		if req.IncludeStats, err = strconv.ParseBool(includeStats); err != nil {
+			return err
+		}
+	}
+	if req.Delimiter != "" && (req.ContentType != "" || req.MinPayloadSize != 0 || req.MaxPayloadSize != 0 ||
+		req.CreateTimeStart != 0 || req.CreateTimeEnd != 0 || req.Owner != "" || req.Visibility != "" ||
+		req.ObjectStatus != "" || (req.SortBy != "" && req.SortBy != string(bsdb.ObjectSortByName)) || req.SortDesc) {
+		return fmt.Errorf("the filters and the order are not supported with the delimiter")
+	}
+	return nil
+}
+
 // getObjectMetaHandler handle get object metadata request
 func (g *GateModular) getObjectMetaHandler(w http.ResponseWriter, r *http.Request) {
 	var (
@@ -0,0 +1,66 @@
+package gater
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+)
+
+func TestParseListObjectsFilter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		delimiter string
+		wanted    *types.GfSpListObjectsByBucketNameRequest
+		wantedErr bool
+	}{
+		{"no filter", "", "", &types.GfSpListObjectsByBucketNameRequest{}, false},
+		{
+			name: "all filters",
+			query: "content-type=image/png&min-size=10&max-size=100&create-time-start=1&create-time-end=2" +
+				"&owner=0x0000000000000000000000000000000000000001&visibility=VISIBILITY_TYPE_PRIVATE" +
+				"&object-status=OBJECT_STATUS_SEALED&sort-by=size&sort-order=desc&include-stats=true",
+			wanted: &types.GfSpListObjectsByBucketNameRequest{
+				ContentType:     "image/png",
+				MinPayloadSize:  10,
+				MaxPayloadSize:  100,
+				CreateTimeStart: 1,
+				CreateTimeEnd:   2,
+				Owner:           "0x0000000000000000000000000000000000000001",
+				Visibility:      "VISIBILITY_TYPE_PRIVATE",
+				ObjectStatus:    "OBJECT_STATUS_SEALED",
+				SortBy:          "size",
+				SortDesc:        true,
+				IncludeStats:    true,
+			},
+		},
+		{"stats with delimiter", "include-stats=true&sort-by=name", "/",
+			&types.GfSpListObjectsByBucketNameRequest{Delimiter: "/", SortBy: "name", IncludeStats: true}, false},
+		{"invalid size range", "min-size=100&max-size=10", "", nil, true},
+		{"invalid create time range", "create-time-start=2&create-time-end=1", "", nil, true},
+		{"invalid owner", "owner=0x01", "", nil, true},
+		{"invalid visibility", "visibility=public", "", nil, true},
+		{"invalid object status", "object-status=sealed", "", nil, true},
+		{"invalid sort by", "sort-by=owner", "", nil, true},
+		{"invalid sort order", "sort-order=up", "", nil, true},
+		{"filter with delimiter", "content-type=image/png", "/", nil, true},
+		{"sort with delimiter", "sort-by=create_time", "/", nil, true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			queryParams, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			req := &types.GfSpListObjectsByBucketNameRequest{Delimiter: tt.delimiter}
+			err = parseListObjectsFilter(queryParams, req)
+			if tt.wantedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wanted, req)
+		})
+	}
+}
@@ -0,0 +1,387 @@
+package gater
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/modular/uploader"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	"github.com/bnb-chain/greenfield-storage-provider/util"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+// maxCompleteMultipartUploadBodySize defines the max size of the complete multipart upload request body,
+// it is enough for 10000 parts.
+const maxCompleteMultipartUploadBodySize = 2 * 1024 * 1024
+
+// multipartUploadTask verifies the permission and the upload id of the multipart upload request, then
+// builds the resumable upload task that carries the object info to uploader.
+func (g *GateModular) multipartUploadTask(reqCtx *RequestContext, authOpType coremodule.AuthOpType,
+	checkUploadID bool, complete bool) (*gfsptask.GfSpResumableUploadObjectTask, error) {
+	authenticated, err := g.baseApp.GfSpClient().VerifyAuthentication(reqCtx.Context(),
+		authOpType, reqCtx.Account(), reqCtx.bucketName, reqCtx.objectName)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to verify authorize", "error", err)
+		return nil, err
+	}
+	if !authenticated {
+		log.CtxErrorw(reqCtx.Context(), "no permission to operate")
+		return nil, ErrNoPermission
+	}
+	bucketInfo, objectInfo, err := g.baseApp.Consensus().QueryBucketInfoAndObjectInfo(reqCtx.Context(),
+		reqCtx.bucketName, reqCtx.objectName)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get object info from consensus", "error", err)
+		return nil, ErrConsensus
+	}
+	if checkUploadID {
+		// the object id is used as the upload id, the object can be uploaded only once
+		if reqCtx.request.URL.Query().Get(MultipartUploadIDQuery) != objectInfo.Id.String() {
+			log.CtxErrorw(reqCtx.Context(), "failed to match upload id", "object_id", objectInfo.Id.String())
+			return nil, ErrInvalidUploadID
+		}
+	}
+	if objectInfo.GetObjectStatus() != storagetypes.OBJECT_STATUS_CREATED {
+		log.CtxErrorw(reqCtx.Context(), "object is not in created status", "status", objectInfo.GetObjectStatus())
+		return nil, ErrInvalidUploadID
+	}
+	params, err := g.baseApp.Consensus().QueryStorageParams(reqCtx.Context())
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get storage params from consensus", "error", err)
+		return nil, ErrConsensus
+	}
+	// the multipart upload utilizes the on-chain MaxPayloadSize as the maximum file size, same as resumable upload
+	if objectInfo.GetPayloadSize() == 0 || objectInfo.GetPayloadSize() > params.GetMaxPayloadSize() {
+		log.CtxErrorw(reqCtx.Context(), "failed to multipart upload due to invalid payload size")
+		return nil, ErrInvalidPayloadSize
+	}
+	task := &gfsptask.GfSpResumableUploadObjectTask{}
+	task.InitResumableUploadObjectTask(bucketInfo.GetGlobalVirtualGroupFamilyId(), objectInfo, params,
+		g.baseApp.TaskTimeout(task, objectInfo.GetPayloadSize()), complete, 0)
+	task.SetCreateTime(time.Now().Unix())
+	return task, nil
+}
+
+// writeXMLResponse marshals the xml info and writes it to the response body.
+func writeXMLResponse(w http.ResponseWriter, xmlInfo interface{}) error {
+	xmlBody, err := xml.Marshal(xmlInfo)
+	if err != nil {
+		log.Errorw("failed to marshal xml", "error", err)
+		return ErrEncodeResponse
+	}
+	w.Header().Set(ContentTypeHeader, ContentTypeXMLHeaderValue)
+	if _, err = w.Write(xmlBody); err != nil {
+		log.Errorw("failed to write body", "error", err)
+		return ErrEncodeResponse
+	}
+	return nil
+}
+
+// createMultipartUploadHandler handles the create multipart upload request, the object id is returned as the upload id.
+func (g *GateModular) createMultipartUploadHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		reqCtx *RequestContext
+		task   *gfsptask.GfSpResumableUploadObjectTask
+	)
+	startTime := time.Now()
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(startTime).Seconds())
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if task, err = g.multipartUploadTask(reqCtx, coremodule.AuthOpTypePutObject, false, false); err != nil {
+		return
+	}
+	var xmlInfo = struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+	}{
+		Bucket:   reqCtx.bucketName,
+		Key:      reqCtx.objectName,
+		UploadID: task.GetObjectInfo().Id.String(),
+	}
+	if err = writeXMLResponse(w, &xmlInfo); err != nil {
+		return
+	}
+	log.CtxDebugw(reqCtx.Context(), "succeed to create multipart upload", "xml_info", xmlInfo)
+}
+
+// uploadPartHandler handles the upload part request, the etag of the part is returned in the header.
+func (g *GateModular) uploadPartHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err        error
+		reqCtx     *RequestContext
+		task       *gfsptask.GfSpResumableUploadObjectTask
+		partNumber uint64
+		etag       string
+	)
+	startTime := time.Now()
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(startTime).Seconds())
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	partNumber, err = util.StringToUint64(reqCtx.request.URL.Query().Get(MultipartPartNumberQuery))
+	if err != nil || partNumber < uploader.MinPartNumber || partNumber > uploader.MaxPartNumber {
+		log.CtxErrorw(reqCtx.Context(), "failed to parse part number", "error", err)
+		err = ErrInvalidPartNumber
+		return
+	}
+	if task, err = g.multipartUploadTask(reqCtx, coremodule.AuthOpTypePutObject, true, false); err != nil {
+		return
+	}
+	ctx := log.WithValue(reqCtx.Context(), log.CtxKeyTask, task.Key().String())
+	etag, err = g.baseApp.GfSpClient().UploadObjectPart(ctx, task, uint32(partNumber), r.Body)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to upload part data", "part_number", partNumber, "error", err)
+		return
+	}
+	w.Header().Set(ETagHeader, "\""+etag+"\"")
+	log.CtxDebugw(ctx, "succeed to upload part data", "part_number", partNumber, "etag", etag)
+}
+
+// listPartsHandler handles the list parts request.
+func (g *GateModular) listPartsHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		reqCtx *RequestContext
+		task   *gfsptask.GfSpResumableUploadObjectTask
+		parts  []*gfspserver.GfSpMultipartUploadPart
+	)
+	startTime := time.Now()
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(startTime).Seconds())
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if task, err = g.multipartUploadTask(reqCtx, coremodule.AuthOpTypeGetUploadingState, true, false); err != nil {
+		return
+	}
+	if parts, err = g.baseApp.GfSpClient().ListMultipartUploadParts(reqCtx.Context(),
+		task.GetObjectInfo().Id.Uint64()); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to list parts", "error", err)
+		return
+	}
+
+	type Part struct {
+		PartNumber   uint32 `xml:"PartNumber"`
+		ETag         string `xml:"ETag"`
+		Size         uint64 `xml:"Size"`
+		LastModified string `xml:"LastModified"`
+	}
+	var xmlInfo = struct {
+		XMLName  xml.Name `xml:"ListPartsResult"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+		Parts    []Part   `xml:"Part"`
+	}{
+		Bucket:   reqCtx.bucketName,
+		Key:      reqCtx.objectName,
+		UploadID: task.GetObjectInfo().Id.String(),
+		Parts:    make([]Part, 0, len(parts)),
+	}
+	for _, part := range parts {
+		xmlInfo.Parts = append(xmlInfo.Parts, Part{
+			PartNumber:   part.GetPartNumber(),
+			ETag:         "\"" + part.GetEtag() + "\"",
+			Size:         part.GetSize_(),
+			LastModified: time.Unix(part.GetUpdateTimestampSecond(), 0).UTC().Format(time.RFC3339),
+		})
+	}
+	if err = writeXMLResponse(w, &xmlInfo); err != nil {
+		return
+	}
+	log.CtxDebugw(reqCtx.Context(), "succeed to list parts", "part_count", len(parts))
+}
+
+// completeMultipartUploadHandler handles the complete multipart upload request, the parts are assembled into
+// the object and the object is replicated to secondary SPs as the normal upload.
+func (g *GateModular) completeMultipartUploadHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		reqCtx *RequestContext
+		task   *gfsptask.GfSpResumableUploadObjectTask
+		body   []byte
+	)
+	startTime := time.Now()
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(startTime).Seconds())
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if task, err = g.multipartUploadTask(reqCtx, coremodule.AuthOpTypePutObject, true, true); err != nil {
+		return
+	}
+
+	var completeInfo struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []struct {
+			PartNumber uint32 `xml:"PartNumber"`
+			ETag       string `xml:"ETag"`
+		} `xml:"Part"`
+	}
+	if body, err = io.ReadAll(io.LimitReader(r.Body, maxCompleteMultipartUploadBodySize)); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to read complete multipart upload body", "error", err)
+		err = ErrExceptionStream
+		return
+	}
+	if err = xml.Unmarshal(body, &completeInfo); err != nil || len(completeInfo.Parts) == 0 {
+		log.CtxErrorw(reqCtx.Context(), "failed to unmarshal complete multipart upload body", "error", err)
+		err = ErrMalformedXML
+		return
+	}
+	var (
+		parts   = make([]*gfspserver.GfSpMultipartUploadPart, 0, len(completeInfo.Parts))
+		md5Hash = md5.New()
+	)
+	for _, part := range completeInfo.Parts {
+		etag := strings.Trim(part.ETag, "\"")
+		etagBytes, decodeErr := hex.DecodeString(etag)
+		if decodeErr != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to decode part etag", "etag", part.ETag, "error", decodeErr)
+			err = ErrMalformedXML
+			return
+		}
+		md5Hash.Write(etagBytes)
+		parts = append(parts, &gfspserver.GfSpMultipartUploadPart{
+			PartNumber: part.PartNumber,
+			Etag:       etag,
+		})
+	}
+
+	ctx := log.WithValue(reqCtx.Context(), log.CtxKeyTask, task.Key().String())
+	if err = g.baseApp.GfSpClient().CompleteMultipartUpload(ctx, task, parts); err != nil {
+		log.CtxErrorw(ctx, "failed to complete multipart upload", "error", err)
+		return
+	}
+	var xmlInfo = struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Bucket  string   `xml:"Bucket"`
+		Key     string   `xml:"Key"`
+		ETag    string   `xml:"ETag"`
+	}{
+		Bucket: reqCtx.bucketName,
+		Key:    reqCtx.objectName,
+		// the same as s3, the etag of multipart object is the md5 of the part etags with the part count
+		ETag: fmt.Sprintf("\"%s-%d\"", hex.EncodeToString(md5Hash.Sum(nil)), len(parts)),
+	}
+	if err = writeXMLResponse(w, &xmlInfo); err != nil {
+		return
+	}
+	log.CtxDebugw(ctx, "succeed to complete multipart upload", "xml_info", xmlInfo)
+}
+
+// abortMultipartUploadHandler handles the abort multipart upload request, the staged parts are discarded.
+func (g *GateModular) abortMultipartUploadHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		reqCtx *RequestContext
+		task   *gfsptask.GfSpResumableUploadObjectTask
+	)
+	startTime := time.Now()
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(startTime).Seconds())
+		} else {
+			reqCtx.SetHttpCode(http.StatusNoContent)
+			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if task, err = g.multipartUploadTask(reqCtx, coremodule.AuthOpTypePutObject, true, false); err != nil {
+		return
+	}
+	ctx := log.WithValue(reqCtx.Context(), log.CtxKeyTask, task.Key().String())
+	if err = g.baseApp.GfSpClient().AbortMultipartUpload(ctx, task); err != nil {
+		log.CtxErrorw(ctx, "failed to abort multipart upload", "error", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	log.CtxDebugw(ctx, "succeed to abort multipart upload")
+}
@@ -0,0 +1,209 @@
+package gater
+
+import (
+	"encoding/xml"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/modular/manager"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	"github.com/bnb-chain/greenfield/types/s3util"
+)
+
+const (
+	// maxBucketNotificationBodySize defines the max size of the put bucket notification request body
+	maxBucketNotificationBodySize = 64 * 1024
+	// maxBucketWebhookNumber defines the max number of the webhooks of a bucket
+	maxBucketWebhookNumber = 10
+	// maxWebhookIDLength defines the max length of the webhook id
+	maxWebhookIDLength = 64
+	// maxWebhookEndpointLength defines the max length of the webhook endpoint
+	maxWebhookEndpointLength = 1024
+	// maxWebhookSecretLength defines the max length of the webhook secret
+	maxWebhookSecretLength = 256
+)
+
+// objectEventTypes are the object events which can be subscribed.
+var objectEventTypes = []spdb.ObjectEventType{spdb.ObjectUploadedEvent, spdb.ObjectReplicatedEvent,
+	spdb.ObjectSealedEvent, spdb.ObjectDeletedEvent}
+
+// WebhookConfiguration is a webhook which is notified of the object events of the bucket, the notifications
+// are signed by the secret, which is not returned by the get bucket notification request.
+type WebhookConfiguration struct {
+	ID       string   `xml:"Id"`
+	Endpoint string   `xml:"Endpoint"`
+	Secret   string   `xml:"Secret,omitempty"`
+	Events   []string `xml:"Event"`
+}
+
+// NotificationConfiguration is the body of the bucket notification request and response.
+type NotificationConfiguration struct {
+	XMLName  xml.Name               `xml:"NotificationConfiguration"`
+	Webhooks []WebhookConfiguration `xml:"WebhookConfiguration"`
+}
+
+func (c *NotificationConfiguration) validate() error {
+	if len(c.Webhooks) == 0 || len(c.Webhooks) > maxBucketWebhookNumber {
+		return ErrInvalidNotificationConfiguration
+	}
+	ids := make(map[string]struct{}, len(c.Webhooks))
+	for _, webhook := range c.Webhooks {
+		if webhook.ID == "" || len(webhook.ID) > maxWebhookIDLength {
+			return ErrInvalidNotificationConfiguration
+		}
+		if _, ok := ids[webhook.ID]; ok {
+			return ErrInvalidNotificationConfiguration
+		}
+		ids[webhook.ID] = struct{}{}
+		if len(webhook.Endpoint) > maxWebhookEndpointLength {
+			return ErrInvalidNotificationConfiguration
+		}
+		endpoint, err := url.Parse(webhook.Endpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Hostname() == "" {
+			return ErrInvalidNotificationConfiguration
+		}
+		// the resolved address is checked again when the notification is delivered
+		if strings.EqualFold(endpoint.Hostname(), "localhost") {
+			return ErrInvalidNotificationConfiguration
+		}
+		if ip := net.ParseIP(endpoint.Hostname()); ip != nil && !manager.IsPublicEventIP(ip) {
+			return ErrInvalidNotificationConfiguration
+		}
+		if webhook.Secret == "" || len(webhook.Secret) > maxWebhookSecretLength {
+			return ErrInvalidNotificationConfiguration
+		}
+		if len(webhook.Events) == 0 {
+			return ErrInvalidNotificationConfiguration
+		}
+		for _, event := range webhook.Events {
+			valid := false
+			for _, t := range objectEventTypes {
+				if spdb.ObjectEventType(event) == t {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return ErrInvalidNotificationConfiguration
+			}
+		}
+	}
+	return nil
+}
+
+// bucketNotificationHandler handles the get, put and delete bucket notification requests, only the bucket owner
+// can manage the webhooks.
+func (g *GateModular) bucketNotificationHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		reqCtx *RequestContext
+		body   []byte
+	)
+	startTime := time.Now()
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(startTime).Seconds())
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	if err = s3util.CheckValidBucketName(reqCtx.bucketName); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to check bucket name", "bucket_name", reqCtx.bucketName, "error", err)
+		return
+	}
+	if err = g.checkBucketOwner(reqCtx); err != nil {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var subscriptions []*spdb.EventSubscription
+		if subscriptions, err = g.baseApp.GfSpDB().GetBucketEventSubscriptions(reqCtx.bucketName); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to get bucket event subscriptions", "error", err)
+			err = ErrSPDB
+			return
+		}
+		if len(subscriptions) == 0 {
+			err = ErrNoSuchNotificationConfiguration
+			return
+		}
+		cfg := &NotificationConfiguration{}
+		for _, subscription := range subscriptions {
+			webhook := WebhookConfiguration{ID: subscription.SubscriptionID, Endpoint: subscription.Endpoint}
+			for _, eventType := range subscription.EventTypes {
+				webhook.Events = append(webhook.Events, string(eventType))
+			}
+			cfg.Webhooks = append(cfg.Webhooks, webhook)
+		}
+		xmlBody, marshalErr := xml.Marshal(cfg)
+		if marshalErr != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to marshal xml", "error", marshalErr)
+			err = ErrEncodeResponse
+			return
+		}
+		w.Header().Set(ContentTypeHeader, ContentTypeXMLHeaderValue)
+		if _, err = w.Write(xmlBody); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to write the response", "error", err)
+		}
+	case http.MethodPut:
+		if body, err = io.ReadAll(io.LimitReader(r.Body, maxBucketNotificationBodySize+1)); err != nil ||
+			len(body) > maxBucketNotificationBodySize {
+			log.CtxErrorw(reqCtx.Context(), "failed to read the bucket notification body", "error", err)
+			err = ErrInvalidNotificationConfiguration
+			return
+		}
+		cfg := &NotificationConfiguration{}
+		if err = xml.Unmarshal(body, cfg); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to unmarshal the bucket notification body", "error", err)
+			err = ErrInvalidNotificationConfiguration
+			return
+		}
+		if err = cfg.validate(); err != nil {
+			return
+		}
+		subscriptions := make([]*spdb.EventSubscription, 0, len(cfg.Webhooks))
+		for _, webhook := range cfg.Webhooks {
+			subscription := &spdb.EventSubscription{
+				BucketName:     reqCtx.bucketName,
+				SubscriptionID: webhook.ID,
+				Endpoint:       strings.TrimSpace(webhook.Endpoint),
+				Secret:         webhook.Secret,
+			}
+			for _, event := range webhook.Events {
+				subscription.EventTypes = append(subscription.EventTypes, spdb.ObjectEventType(event))
+			}
+			subscriptions = append(subscriptions, subscription)
+		}
+		if err = g.baseApp.GfSpDB().UpdateBucketEventSubscriptions(reqCtx.bucketName, subscriptions); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to update bucket event subscriptions", "error", err)
+			err = ErrSPDB
+			return
+		}
+	case http.MethodDelete:
+		if err = g.baseApp.GfSpDB().DeleteBucketEventSubscriptions(reqCtx.bucketName); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to delete bucket event subscriptions", "error", err)
+			err = ErrSPDB
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package gater
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationConfigurationValidate(t *testing.T) {
+	webhook := func(endpoint string) WebhookConfiguration {
+		return WebhookConfiguration{ID: "a", Endpoint: endpoint, Secret: "secret", Events: []string{"ObjectSealed"}}
+	}
+	testCases := []struct {
+		name      string
+		webhooks  []WebhookConfiguration
+		wantedErr error
+	}{
+		{"public domain", []WebhookConfiguration{webhook("https://example.com/hook")}, nil},
+		{"public ip", []WebhookConfiguration{webhook("http://8.8.8.8:8080/hook")}, nil},
+		{"no webhook", nil, ErrInvalidNotificationConfiguration},
+		{"duplicated id", []WebhookConfiguration{webhook("https://a.com"), webhook("https://b.com")},
+			ErrInvalidNotificationConfiguration},
+		{"invalid scheme", []WebhookConfiguration{webhook("ftp://example.com")}, ErrInvalidNotificationConfiguration},
+		{"no host", []WebhookConfiguration{webhook("https://:8080/hook")}, ErrInvalidNotificationConfiguration},
+		{"localhost", []WebhookConfiguration{webhook("http://LocalHost:8080/hook")}, ErrInvalidNotificationConfiguration},
+		{"loopback", []WebhookConfiguration{webhook("http://127.0.0.1/hook")}, ErrInvalidNotificationConfiguration},
+		{"loopback ipv6", []WebhookConfiguration{webhook("http://[::1]/hook")}, ErrInvalidNotificationConfiguration},
+		{"private", []WebhookConfiguration{webhook("http://10.1.2.3/hook")}, ErrInvalidNotificationConfiguration},
+		{"metadata service", []WebhookConfiguration{webhook("http://169.254.169.254/latest")},
+			ErrInvalidNotificationConfiguration},
+		{"unknown event", []WebhookConfiguration{{ID: "a", Endpoint: "https://a.com", Secret: "secret",
+			Events: []string{"ObjectRead"}}}, ErrInvalidNotificationConfiguration},
+		{"no secret", []WebhookConfiguration{{ID: "a", Endpoint: "https://a.com", Events: []string{"ObjectSealed"}}},
+			ErrInvalidNotificationConfiguration},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &NotificationConfiguration{Webhooks: tt.webhooks}
+			assert.Equal(t, tt.wantedErr, cfg.validate())
+		})
+	}
+}
@@ -2,11 +2,17 @@ package gater
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -116,37 +122,252 @@ func (g *GateModular) putObjectHandler(w http.ResponseWriter, r *http.Request) {
 	log.CtxDebug(ctx, "succeed to upload payload data")
 }
 
-func parseRange(rangeStr string) (bool, int64, int64) {
-	if rangeStr == "" {
-		return false, -1, -1
-	}
-	rangeStr = strings.ToLower(rangeStr)
-	rangeStr = strings.ReplaceAll(rangeStr, " ", "")
+// byteRange is a satisfiable byte range of the object, both the start and the end are inclusive.
+type byteRange struct {
+	start int64
+	end   int64
+}
+
+func (r byteRange) length() int64 {
+	return r.end - r.start + 1
+}
+
+// contentRange returns the Content-Range header value of the range in the object with the size.
+func (r byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.end, size)
+}
+
+// parseRange parses the Range header as RFC 7233 for the object with the size, the byte range set can contain
+// multiple ranges, e.g. bytes=0-99,200-,-500, which are the first 100 bytes, the bytes from offset 200 to the
+// end and the last 500 bytes. It returns false if the header is absent or the range unit is not bytes, then the
+// whole object should be returned. The unsatisfiable ranges are skipped, ErrInvalidRange is returned if the
+// header is malformed, and ErrRangeNotSatisfiable is returned if none of the ranges is satisfiable. The
+// overlapping and adjacent ranges are coalesced as RFC 7233 section 4.1 allows, and the whole object is
+// returned if the ranges sum up to more than the object, so a small object can not be read many times over.
+func parseRange(rangeStr string, size int64) (bool, []byteRange, error) {
+	rangeStr = strings.ToLower(strings.ReplaceAll(rangeStr, " ", ""))
 	if !strings.HasPrefix(rangeStr, "bytes=") {
-		return false, -1, -1
+		return false, nil, nil
+	}
+	specs := strings.Split(rangeStr[len("bytes="):], ",")
+	if len(specs) > MaxRangeNumber {
+		return true, nil, ErrInvalidRange
 	}
-	rangeStr = rangeStr[len("bytes="):]
-	if strings.HasSuffix(rangeStr, "-") {
-		rangeStr = rangeStr[:len(rangeStr)-1]
-		rangeStart, err := util.StringToUint64(rangeStr)
+	var ranges []byteRange
+	for _, spec := range specs {
+		if spec == "" {
+			// the empty list elements are allowed by RFC 7230 section 7
+			continue
+		}
+		startStr, endStr, ok := strings.Cut(spec, "-")
+		if !ok {
+			return true, nil, ErrInvalidRange
+		}
+		if startStr == "" {
+			// the suffix range contains the last n bytes of the object
+			n, err := strconv.ParseUint(endStr, 10, 63)
+			if err != nil {
+				return true, nil, ErrInvalidRange
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			if int64(n) > size {
+				n = uint64(size)
+			}
+			ranges = append(ranges, byteRange{start: size - int64(n), end: size - 1})
+			continue
+		}
+		start, err := strconv.ParseUint(startStr, 10, 63)
 		if err != nil {
-			return false, -1, -1
+			return true, nil, ErrInvalidRange
+		}
+		end := uint64(size - 1)
+		if endStr != "" {
+			if end, err = strconv.ParseUint(endStr, 10, 63); err != nil || end < start {
+				return true, nil, ErrInvalidRange
+			}
+		}
+		if int64(start) >= size {
+			continue
+		}
+		if int64(end) >= size {
+			end = uint64(size - 1)
+		}
+		ranges = append(ranges, byteRange{start: int64(start), end: int64(end)})
+	}
+	if len(ranges) == 0 {
+		return true, nil, ErrRangeNotSatisfiable
+	}
+	var total int64
+	for _, r := range ranges {
+		total += r.length()
+	}
+	if total > size {
+		return false, nil, nil
+	}
+	return true, coalesceRanges(ranges), nil
+}
+
+// coalesceRanges merges the overlapping and adjacent ranges, the ranges are returned in the requested order
+// if none of them is merged, otherwise the merged ranges are returned in the ascending order.
+func coalesceRanges(ranges []byteRange) []byteRange {
+	sorted := make([]byteRange, len(ranges))
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start < sorted[j].start })
+	merged := sorted[:1]
+	for _, r := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if r.start > last.end+1 {
+			merged = append(merged, r)
+			continue
+		}
+		if r.end > last.end {
+			last.end = r.end
 		}
-		return true, int64(rangeStart), -1
 	}
-	pair := strings.Split(rangeStr, "-")
-	if len(pair) == 2 {
-		rangeStart, err := util.StringToUint64(pair[0])
+	if len(merged) == len(ranges) {
+		return ranges
+	}
+	return merged
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+func rangePartHeader(r byteRange, contentType string, size int64) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		ContentTypeHeader:  {contentType},
+		ContentRangeHeader: {r.contentRange(size)},
+	}
+}
+
+// multipartRangesSize returns the content length of the multipart/byteranges response of the ranges.
+func multipartRangesSize(ranges []byteRange, boundary, contentType string, size int64) int64 {
+	var w countingWriter
+	mw := multipart.NewWriter(&w)
+	_ = mw.SetBoundary(boundary)
+	for _, r := range ranges {
+		_, _ = mw.CreatePart(rangePartHeader(r, contentType, size))
+		w += countingWriter(r.length())
+	}
+	_ = mw.Close()
+	return int64(w)
+}
+
+// writeObjectRanges writes the object data to the response, the whole object is written if it is not a range
+// request, a single range is written as the 206 response body, and multiple ranges are written in the
+// multipart/byteranges format. Every range is read by its own download task, so only the segment pieces
+// covering the range are read. The first range is opened before the response header is written, so the
+// failure of the download can still be responded as the error. It returns the size of the written data.
+func writeObjectRanges(w http.ResponseWriter, isRange bool, ranges []byteRange, size int64, contentType string,
+	open func(r byteRange) (io.ReadCloser, error)) (int64, error) {
+	reader, err := open(ranges[0])
+	if err != nil {
+		return 0, err
+	}
+	if !isRange || len(ranges) == 1 {
+		defer reader.Close()
+		w.Header().Set(ContentTypeHeader, contentType)
+		w.Header().Set(ContentLengthHeader, strconv.FormatInt(ranges[0].length(), 10))
+		if isRange {
+			w.Header().Set(ContentRangeHeader, ranges[0].contentRange(size))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		return io.Copy(w, reader)
+	}
+
+	var written int64
+	mw := multipart.NewWriter(w)
+	w.Header().Set(ContentTypeHeader, "multipart/byteranges; boundary="+mw.Boundary())
+	w.Header().Set(ContentLengthHeader, strconv.FormatInt(multipartRangesSize(ranges, mw.Boundary(), contentType, size), 10))
+	w.WriteHeader(http.StatusPartialContent)
+	for i, r := range ranges {
+		if i > 0 {
+			if reader, err = open(r); err != nil {
+				return written, err
+			}
+		}
+		part, err := mw.CreatePart(rangePartHeader(r, contentType, size))
 		if err != nil {
-			return false, -1, -1
+			reader.Close()
+			return written, err
 		}
-		rangeEnd, err := util.StringToUint64(pair[1])
+		n, err := io.Copy(part, reader)
+		reader.Close()
+		written += n
 		if err != nil {
-			return false, -1, -1
+			return written, err
+		}
+	}
+	return written, mw.Close()
+}
+
+// objectETag returns the entity tag of the object, it is derived from the integrity checksum of the
+// object, so the tag keeps unchanged as long as the object is not recreated with the other payload.
+func objectETag(objectInfo *storagetypes.ObjectInfo) string {
+	if len(objectInfo.GetChecksums()) == 0 {
+		return `""`
+	}
+	return `"` + hex.EncodeToString(objectInfo.GetChecksums()[0]) + `"`
+}
+
+// setObjectCacheHeaders sets the headers which are used by the caches to validate the object.
+func setObjectCacheHeaders(w http.ResponseWriter, objectInfo *storagetypes.ObjectInfo) {
+	w.Header().Set(ETagHeader, objectETag(objectInfo))
+	w.Header().Set(LastModifiedHeader, time.Unix(objectInfo.GetCreateAt(), 0).UTC().Format(http.TimeFormat))
+}
+
+// matchETag reports whether the etag matches any entity tag in the list of If-Match or If-None-Match header,
+// the weak comparison is used for If-None-Match as RFC 7232.
+func matchETag(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = tag[len("W/"):]
+		}
+		if tag == etag {
+			return true
 		}
-		return true, int64(rangeStart), int64(rangeEnd)
 	}
-	return false, -1, -1
+	return false
+}
+
+// checkObjectPreconditions evaluates the conditional headers of the get and head object request in the order
+// of RFC 7232 section 6, returns true if the object is not modified and the 304 response should be sent, or
+// returns ErrPreconditionFailed if If-Match or If-Unmodified-Since does not hold.
+func checkObjectPreconditions(r *http.Request, objectInfo *storagetypes.ObjectInfo) (bool, error) {
+	etag := objectETag(objectInfo)
+	lastModified := time.Unix(objectInfo.GetCreateAt(), 0)
+	if ifMatch := r.Header.Get(IfMatchHeader); ifMatch != "" {
+		if !matchETag(ifMatch, etag, false) {
+			return false, ErrPreconditionFailed
+		}
+	} else if ifUnmodifiedSince := r.Header.Get(IfUnmodifiedSinceHeader); ifUnmodifiedSince != "" {
+		if t, err := http.ParseTime(ifUnmodifiedSince); err == nil && lastModified.After(t) {
+			return false, ErrPreconditionFailed
+		}
+	}
+	if ifNoneMatch := r.Header.Get(IfNoneMatchHeader); ifNoneMatch != "" {
+		return matchETag(ifNoneMatch, etag, true), nil
+	}
+	if ifModifiedSince := r.Header.Get(IfModifiedSinceHeader); ifModifiedSince != "" {
+		if t, err := http.ParseTime(ifModifiedSince); err == nil && !lastModified.After(t) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // resumablePutObjectHandler handles the resumable put object
@@ -370,10 +591,10 @@ func (g *GateModular) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 		objectInfo    *storagetypes.ObjectInfo
 		bucketInfo    *storagetypes.BucketInfo
 		params        *storagetypes.Params
-		lowOffset     int64
-		highOffset    int64
-		pieceInfos    []*downloader.SegmentPieceInfo
-		pieceData     []byte
+		isRange       bool
+		ranges        []byteRange
+		writtenSize   int64
+		notModified   bool
 	)
 	getObjectStartTime := time.Now()
 	defer func() {
@@ -381,14 +602,25 @@ func (g *GateModular) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			log.CtxDebugw(reqCtx.Context(), "get object error")
 			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
-			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
-			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			if writtenSize == 0 {
+				reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+				MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			} else {
+				// the object data has been partially sent, the response can only be aborted
+				reqCtx.SetHttpCode(http.StatusOK)
+			}
 			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
 			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(getObjectStartTime).Seconds())
 			metrics.ReqCounter.WithLabelValues(GatewayFailureGetObject).Inc()
 			metrics.ReqTime.WithLabelValues(GatewayFailureGetObject).Observe(time.Since(getObjectStartTime).Seconds())
 		} else {
-			reqCtx.SetHttpCode(http.StatusOK)
+			if notModified {
+				reqCtx.SetHttpCode(http.StatusNotModified)
+			} else if isRange {
+				reqCtx.SetHttpCode(http.StatusPartialContent)
+			} else {
+				reqCtx.SetHttpCode(http.StatusOK)
+			}
 			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
 			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(getObjectStartTime).Seconds())
 			metrics.ReqCounter.WithLabelValues(GatewaySuccessGetObject).Inc()
@@ -457,6 +689,15 @@ func (g *GateModular) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 		err = ErrConsensus
 		return
 	}
+	if notModified, err = checkObjectPreconditions(r, objectInfo); err != nil {
+		log.CtxDebugw(reqCtx.Context(), "the preconditions of get object do not hold")
+		return
+	}
+	if notModified {
+		setObjectCacheHeaders(w, objectInfo)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 
 	getBucketTime := time.Now()
 	bucketInfo, err = g.baseApp.Consensus().QueryBucketInfo(reqCtx.Context(), objectInfo.GetBucketName())
@@ -476,62 +717,126 @@ func (g *GateModular) getObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	isRange, rangeStart, rangeEnd := parseRange(reqCtx.request.Header.Get(RangeHeader))
-	if isRange && (rangeEnd < 0 || rangeEnd >= int64(objectInfo.GetPayloadSize())) {
-		rangeEnd = int64(objectInfo.GetPayloadSize()) - 1
-	}
-	if isRange && (rangeStart < 0 || rangeEnd < 0 || rangeStart > rangeEnd) {
-		err = ErrInvalidRange
+	size := int64(objectInfo.GetPayloadSize())
+	if isRange, ranges, err = parseRange(r.Header.Get(RangeHeader), size); err != nil {
+		if err == ErrRangeNotSatisfiable {
+			w.Header().Set(ContentRangeHeader, fmt.Sprintf("bytes */%d", size))
+		}
 		return
 	}
-
-	if isRange {
-		lowOffset = rangeStart
-		highOffset = rangeEnd
-	} else {
-		lowOffset = 0
-		highOffset = int64(objectInfo.GetPayloadSize()) - 1
+	if !isRange {
+		ranges = []byteRange{{start: 0, end: size - 1}}
 	}
 
-	task := &gfsptask.GfSpDownloadObjectTask{}
-	task.InitDownloadObjectTask(objectInfo, bucketInfo, params, g.baseApp.TaskPriority(task), reqCtx.Account(),
-		lowOffset, highOffset, g.baseApp.TaskTimeout(task, uint64(highOffset-lowOffset+1)), g.baseApp.TaskMaxRetry(task))
-	if pieceInfos, err = downloader.SplitToSegmentPieceInfos(task, g.baseApp.PieceOp()); err != nil {
-		log.CtxErrorw(reqCtx.Context(), "failed to download object", "error", err)
+	setObjectCacheHeaders(w, objectInfo)
+	getDataTime := time.Now()
+	writtenSize, err = writeObjectRanges(w, isRange, ranges, size, objectInfo.GetContentType(),
+		func(ra byteRange) (io.ReadCloser, error) {
+			task := &gfsptask.GfSpDownloadObjectTask{}
+			task.InitDownloadObjectTask(objectInfo, bucketInfo, params, g.baseApp.TaskPriority(task), reqCtx.Account(),
+				ra.start, ra.end, g.baseApp.TaskTimeout(task, uint64(ra.length())), g.baseApp.TaskMaxRetry(task))
+			openTime := time.Now()
+			reader, openErr := g.baseApp.GfSpClient().GetObjectStream(reqCtx.Context(), task)
+			metrics.PerfGetObjectTimeHistogram.WithLabelValues("get_object_segment_data_time").Observe(time.Since(openTime).Seconds())
+			if openErr != nil {
+				log.CtxErrorw(reqCtx.Context(), "failed to download object", "low", ra.start, "high", ra.end, "error", openErr)
+			}
+			return reader, openErr
+		})
+	metrics.PerfGetObjectTimeHistogram.WithLabelValues("get_object_write_time").Observe(time.Since(getDataTime).Seconds())
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to write object data", "written_size", writtenSize, "error", err)
 		return
 	}
-	w.Header().Set(ContentTypeHeader, objectInfo.GetContentType())
-	if isRange {
-		w.Header().Set(ContentRangeHeader, "bytes "+util.Uint64ToString(uint64(lowOffset))+
-			"-"+util.Uint64ToString(uint64(highOffset)))
-	} else {
-		w.Header().Set(ContentLengthHeader, util.Uint64ToString(objectInfo.GetPayloadSize()))
-	}
+	metrics.ReqPieceSize.WithLabelValues(GatewayGetObjectSize).Observe(float64(writtenSize))
+	metrics.PerfGetObjectTimeHistogram.WithLabelValues("get_object_get_data_time").Observe(time.Since(getDataTime).Seconds())
+}
 
-	getDataTime := time.Now()
-	for idx, pInfo := range pieceInfos {
-		enableCheck := false
-		if idx == 0 { // only check in first piece
-			enableCheck = true
-		}
-		pieceTask := &gfsptask.GfSpDownloadPieceTask{}
-		pieceTask.InitDownloadPieceTask(objectInfo, bucketInfo, params, g.baseApp.TaskPriority(task),
-			enableCheck, reqCtx.Account(), uint64(highOffset-lowOffset+1), pInfo.SegmentPieceKey, pInfo.Offset,
-			pInfo.Length, g.baseApp.TaskTimeout(task, uint64(pieceTask.GetSize())), g.baseApp.TaskMaxRetry(task))
-		getSegmentTime := time.Now()
-		pieceData, err = g.baseApp.GfSpClient().GetPiece(reqCtx.Context(), pieceTask)
-		metrics.PerfGetObjectTimeHistogram.WithLabelValues("get_object_segment_data_time").Observe(time.Since(getSegmentTime).Seconds())
+// headObjectHandler handles the head object request, it returns the size, content type, entity tag and the
+// status of the object without reading the piece store, so the unsealed object can be queried as well.
+func (g *GateModular) headObjectHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err         error
+		reqCtxErr   error
+		reqCtx      *RequestContext
+		permission  *permissiontypes.Effect
+		objectInfo  *storagetypes.ObjectInfo
+		notModified bool
+	)
+	startTime := time.Now()
+	defer func() {
+		reqCtx.Cancel()
 		if err != nil {
-			log.CtxErrorw(reqCtx.Context(), "failed to download piece", "error", err)
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(startTime).Seconds())
+			metrics.ReqCounter.WithLabelValues(GatewayFailureHeadObject).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayFailureHeadObject).Observe(time.Since(startTime).Seconds())
+		} else {
+			if notModified {
+				reqCtx.SetHttpCode(http.StatusNotModified)
+			} else {
+				reqCtx.SetHttpCode(http.StatusOK)
+			}
+			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
+			metrics.ReqCounter.WithLabelValues(GatewaySuccessHeadObject).Inc()
+			metrics.ReqTime.WithLabelValues(GatewaySuccessHeadObject).Observe(time.Since(startTime).Seconds())
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, reqCtxErr = NewRequestContext(r, g)
+	// the public object can be queried by the anonymous users.
+	if permission, err = g.baseApp.GfSpClient().VerifyPermission(reqCtx.Context(), sdk.AccAddress{}.String(),
+		reqCtx.bucketName, reqCtx.objectName, permissiontypes.ACTION_GET_OBJECT); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to verify permission for head public object", "error", err)
+		err = ErrConsensus
+		return
+	}
+	if *permission != permissiontypes.EFFECT_ALLOW {
+		if reqCtxErr != nil {
+			err = reqCtxErr
+			log.CtxErrorw(reqCtx.Context(), "no permission to operate, object is not public", "error", err)
+			return
+		}
+		if permission, err = g.baseApp.GfSpClient().VerifyPermission(reqCtx.Context(), reqCtx.Account(),
+			reqCtx.bucketName, reqCtx.objectName, permissiontypes.ACTION_GET_OBJECT); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to verify permission for head object", "error", err)
+			err = ErrConsensus
+			return
+		}
+		if *permission != permissiontypes.EFFECT_ALLOW {
+			log.CtxErrorw(reqCtx.Context(), "no permission to operate")
+			err = ErrNoPermission
 			return
 		}
+	}
 
-		writeTime := time.Now()
-		w.Write(pieceData)
-		metrics.PerfGetObjectTimeHistogram.WithLabelValues("get_object_write_time").Observe(time.Since(writeTime).Seconds())
+	if objectInfo, err = g.baseApp.Consensus().QueryObjectInfo(reqCtx.Context(), reqCtx.bucketName, reqCtx.objectName); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get object info from consensus", "error", err)
+		if strings.Contains(err.Error(), "No such object") {
+			err = ErrNoSuchObject
+			return
+		}
+		err = ErrConsensus
+		return
 	}
-	metrics.ReqPieceSize.WithLabelValues(GatewayGetObjectSize).Observe(float64(highOffset - lowOffset + 1))
-	metrics.PerfGetObjectTimeHistogram.WithLabelValues("get_object_get_data_time").Observe(time.Since(getDataTime).Seconds())
+	if notModified, err = checkObjectPreconditions(r, objectInfo); err != nil {
+		return
+	}
+	setObjectCacheHeaders(w, objectInfo)
+	if notModified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set(ContentTypeHeader, objectInfo.GetContentType())
+	w.Header().Set(ContentLengthHeader, util.Uint64ToString(objectInfo.GetPayloadSize()))
+	w.Header().Set(GnfdObjectIDHeader, objectInfo.Id.String())
+	w.Header().Set(GnfdObjectStatusHeader, objectInfo.GetObjectStatus().String())
+	w.WriteHeader(http.StatusOK)
 }
 
 // queryUploadProgressHandler handles the query uploaded object progress request.
@@ -633,14 +938,14 @@ func (g *GateModular) getObjectByUniversalEndpointHandler(w http.ResponseWriter,
 		reqCtx               *RequestContext
 		authenticated        bool
 		isRange              bool
-		rangeStart           int64
-		rangeEnd             int64
+		ranges               []byteRange
 		redirectURL          string
 		params               *storagetypes.Params
 		escapedObjectName    string
 		isRequestFromBrowser bool
 		spEndpoint           string
 		getEndpointErr       error
+		notModified          bool
 	)
 	startTime := time.Now()
 	defer func() {
@@ -671,6 +976,10 @@ func (g *GateModular) getObjectByUniversalEndpointHandler(w http.ResponseWriter,
 				metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
 			}
 
+		} else if notModified {
+			reqCtx.SetHttpCode(http.StatusNotModified)
+		} else if isRange {
+			reqCtx.SetHttpCode(http.StatusPartialContent)
 		} else {
 			reqCtx.SetHttpCode(http.StatusOK)
 		}
@@ -848,6 +1157,15 @@ func (g *GateModular) getObjectByUniversalEndpointHandler(w http.ResponseWriter,
 
 	}
 
+	if notModified, err = checkObjectPreconditions(r, getObjectInfoRes.GetObjectInfo()); err != nil {
+		return
+	}
+	if notModified {
+		setObjectCacheHeaders(w, getObjectInfoRes.GetObjectInfo())
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	params, err = g.baseApp.Consensus().QueryStorageParamsByTimestamp(
 		reqCtx.Context(), getObjectInfoRes.GetObjectInfo().GetCreateAt())
 	if err != nil {
@@ -856,39 +1174,43 @@ func (g *GateModular) getObjectByUniversalEndpointHandler(w http.ResponseWriter,
 		return
 	}
 
-	var low int64
-	var high int64
-	if isRange {
-		low = rangeStart
-		high = rangeEnd
-	} else {
-		low = 0
-		high = int64(getObjectInfoRes.GetObjectInfo().GetPayloadSize()) - 1
-	}
-
-	task := &gfsptask.GfSpDownloadObjectTask{}
-	task.InitDownloadObjectTask(getObjectInfoRes.GetObjectInfo(), getBucketInfoRes.GetBucketInfo(), params, g.baseApp.TaskPriority(task), reqCtx.Account(),
-		low, high, g.baseApp.TaskTimeout(task, uint64(high-low+1)), g.baseApp.TaskMaxRetry(task))
-	data, getObjectErr := g.baseApp.GfSpClient().GetObject(reqCtx.Context(), task)
-	if getObjectErr != nil {
-		err = getObjectErr
-		log.CtxErrorw(reqCtx.Context(), "failed to download object", "error", err)
+	objectInfo := getObjectInfoRes.GetObjectInfo()
+	size := int64(objectInfo.GetPayloadSize())
+	if isRange, ranges, err = parseRange(r.Header.Get(RangeHeader), size); err != nil {
+		if err == ErrRangeNotSatisfiable {
+			w.Header().Set(ContentRangeHeader, fmt.Sprintf("bytes */%d", size))
+		}
 		return
 	}
+	if !isRange {
+		ranges = []byteRange{{start: 0, end: size - 1}}
+	}
 
 	if isDownload {
 		w.Header().Set(ContentDispositionHeader, ContentDispositionAttachmentValue+"; filename=\""+escapedObjectName+"\"")
 	} else {
 		w.Header().Set(ContentDispositionHeader, ContentDispositionInlineValue)
 	}
-	w.Header().Set(ContentTypeHeader, getObjectInfoRes.GetObjectInfo().GetContentType())
-	if isRange {
-		w.Header().Set(ContentRangeHeader, "bytes "+util.Uint64ToString(uint64(low))+
-			"-"+util.Uint64ToString(uint64(high)))
-	} else {
-		w.Header().Set(ContentLengthHeader, util.Uint64ToString(getObjectInfoRes.GetObjectInfo().GetPayloadSize()))
+	setObjectCacheHeaders(w, objectInfo)
+	writtenSize, err := writeObjectRanges(w, isRange, ranges, size, objectInfo.GetContentType(),
+		func(ra byteRange) (io.ReadCloser, error) {
+			task := &gfsptask.GfSpDownloadObjectTask{}
+			task.InitDownloadObjectTask(objectInfo, getBucketInfoRes.GetBucketInfo(), params, g.baseApp.TaskPriority(task), reqCtx.Account(),
+				ra.start, ra.end, g.baseApp.TaskTimeout(task, uint64(ra.length())), g.baseApp.TaskMaxRetry(task))
+			return g.baseApp.GfSpClient().GetObjectStream(reqCtx.Context(), task)
+		})
+	if err != nil && writtenSize == 0 {
+		log.CtxErrorw(reqCtx.Context(), "failed to download object", "error", err)
+		return
+	}
+	if err != nil {
+		// the object data has been partially sent, the response can only be aborted
+		log.CtxErrorw(reqCtx.Context(), "failed to write object data for universal endpoint",
+			"written_size", writtenSize, "error", err)
+		reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+		err = nil
+		return
 	}
-	w.Write(data)
 	log.CtxDebugw(reqCtx.Context(), "succeed to download object for universal endpoint")
 }
 
@@ -0,0 +1,169 @@
+package gater
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func TestCheckObjectPreconditions(t *testing.T) {
+	createAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	objectInfo := &storagetypes.ObjectInfo{CreateAt: createAt.Unix(), Checksums: [][]byte{{0xab, 0xcd}}}
+	etag := objectETag(objectInfo)
+	assert.Equal(t, `"abcd"`, etag)
+	before := createAt.Add(-time.Hour).Format(http.TimeFormat)
+	after := createAt.Add(time.Hour).Format(http.TimeFormat)
+
+	testCases := []struct {
+		name            string
+		headers         map[string]string
+		wantNotModified bool
+		wantedErr       error
+	}{
+		{"no condition", nil, false, nil},
+		{"if-none-match hit", map[string]string{IfNoneMatchHeader: etag}, true, nil},
+		{"if-none-match weak hit", map[string]string{IfNoneMatchHeader: `"other", W/` + etag}, true, nil},
+		{"if-none-match miss", map[string]string{IfNoneMatchHeader: `"other"`}, false, nil},
+		{"if-none-match takes precedence", map[string]string{IfNoneMatchHeader: `"other"`, IfModifiedSinceHeader: after}, false, nil},
+		{"if-modified-since not modified", map[string]string{IfModifiedSinceHeader: after}, true, nil},
+		{"if-modified-since modified", map[string]string{IfModifiedSinceHeader: before}, false, nil},
+		{"if-modified-since invalid", map[string]string{IfModifiedSinceHeader: "yesterday"}, false, nil},
+		{"if-match hit", map[string]string{IfMatchHeader: etag}, false, nil},
+		{"if-match any", map[string]string{IfMatchHeader: "*"}, false, nil},
+		{"if-match miss", map[string]string{IfMatchHeader: `"other"`}, false, ErrPreconditionFailed},
+		{"if-match weak", map[string]string{IfMatchHeader: "W/" + etag}, false, ErrPreconditionFailed},
+		{"if-unmodified-since hold", map[string]string{IfUnmodifiedSinceHeader: after}, false, nil},
+		{"if-unmodified-since fail", map[string]string{IfUnmodifiedSinceHeader: before}, false, ErrPreconditionFailed},
+		{"if-match takes precedence", map[string]string{IfMatchHeader: etag, IfUnmodifiedSinceHeader: before}, false, nil},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, scheme+bucketName+"."+testDomain+"/"+objectName, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			notModified, err := checkObjectPreconditions(req, objectInfo)
+			assert.Equal(t, tt.wantedErr, err)
+			assert.Equal(t, tt.wantNotModified, notModified)
+		})
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rangeStr      string
+		size          int64
+		wantedIsRange bool
+		wantedRanges  []byteRange
+		wantedErr     error
+	}{
+		{"no range", "", 100, false, nil, nil},
+		{"other unit", "items=0-1", 100, false, nil, nil},
+		{"single range", "bytes=0-9", 100, true, []byteRange{{0, 9}}, nil},
+		{"open-ended range", "bytes=90-", 100, true, []byteRange{{90, 99}}, nil},
+		{"suffix range", "bytes=-10", 100, true, []byteRange{{90, 99}}, nil},
+		{"suffix range larger than object", "bytes=-500", 100, true, []byteRange{{0, 99}}, nil},
+		{"end exceeds object", "Bytes = 50-500", 100, true, []byteRange{{50, 99}}, nil},
+		{"multiple ranges", "bytes=0-0,10-19,-1", 100, true, []byteRange{{0, 0}, {10, 19}, {99, 99}}, nil},
+		{"unsatisfiable range skipped", "bytes=200-300,0-1", 100, true, []byteRange{{0, 1}}, nil},
+		{"empty list element", "bytes=0-1,,5-6", 100, true, []byteRange{{0, 1}, {5, 6}}, nil},
+		{"unordered ranges kept", "bytes=50-59,0-9", 100, true, []byteRange{{50, 59}, {0, 9}}, nil},
+		{"adjacent ranges merged", "bytes=0-1,2-3", 100, true, []byteRange{{0, 3}}, nil},
+		{"overlapping ranges merged", "bytes=50-59,0-9,5-14,-45", 100, true, []byteRange{{0, 14}, {50, 99}}, nil},
+		{"duplicated ranges merged", "bytes=0-9,0-9,0-9", 100, true, []byteRange{{0, 9}}, nil},
+		{"ranges exceed object", "bytes=0-59,40-99", 100, false, nil, nil},
+		{"repeated ranges exceed object", "bytes=" + strings.Repeat("0-9,", 10) + "0-9", 100, false, nil, nil},
+		{"none satisfiable", "bytes=100-", 100, true, nil, ErrRangeNotSatisfiable},
+		{"zero suffix", "bytes=-0", 100, true, nil, ErrRangeNotSatisfiable},
+		{"empty object", "bytes=0-1", 0, true, nil, ErrRangeNotSatisfiable},
+		{"start after end", "bytes=10-5", 100, true, nil, ErrInvalidRange},
+		{"negative start", "bytes=--5", 100, true, nil, ErrInvalidRange},
+		{"signed number", "bytes=+1-5", 100, true, nil, ErrInvalidRange},
+		{"no dash", "bytes=10", 100, true, nil, ErrInvalidRange},
+		{"not number", "bytes=a-b", 100, true, nil, ErrInvalidRange},
+		{"too many ranges", "bytes=" + strings.Repeat("0-0,", MaxRangeNumber) + "0-0", 100, true, nil, ErrInvalidRange},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			isRange, ranges, err := parseRange(tt.rangeStr, tt.size)
+			assert.Equal(t, tt.wantedErr, err)
+			assert.Equal(t, tt.wantedIsRange, isRange)
+			assert.Equal(t, tt.wantedRanges, ranges)
+		})
+	}
+}
+
+func TestWriteObjectRanges(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+	size := int64(len(data))
+	var opened []byteRange
+	open := func(r byteRange) (io.ReadCloser, error) {
+		opened = append(opened, r)
+		return io.NopCloser(bytes.NewReader(data[r.start : r.end+1])), nil
+	}
+
+	// the whole object
+	w := httptest.NewRecorder()
+	n, err := writeObjectRanges(w, false, []byteRange{{0, size - 1}}, size, "text/plain", open)
+	require.NoError(t, err)
+	assert.Equal(t, size, n)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(data), w.Body.String())
+	assert.Equal(t, "20", w.Header().Get(ContentLengthHeader))
+
+	// single range
+	w = httptest.NewRecorder()
+	_, err = writeObjectRanges(w, true, []byteRange{{16, 19}}, size, "text/plain", open)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "ghij", w.Body.String())
+	assert.Equal(t, "bytes 16-19/20", w.Header().Get(ContentRangeHeader))
+
+	// multiple ranges, every range is read by its own download
+	opened = nil
+	ranges := []byteRange{{0, 1}, {10, 12}, {19, 19}}
+	w = httptest.NewRecorder()
+	n, err = writeObjectRanges(w, true, ranges, size, "text/plain", open)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, ranges, opened)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get(ContentLengthHeader))
+	mediaType, params, err := mime.ParseMediaType(w.Header().Get(ContentTypeHeader))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/byteranges", mediaType)
+	mr := multipart.NewReader(w.Body, params["boundary"])
+	for _, r := range ranges {
+		part, err := mr.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain", part.Header.Get(ContentTypeHeader))
+		assert.Equal(t, r.contentRange(size), part.Header.Get(ContentRangeHeader))
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		assert.Equal(t, data[r.start:r.end+1], body)
+	}
+	_, err = mr.NextPart()
+	assert.Equal(t, io.EOF, err)
+
+	// the failure of the first range is returned before the response is written
+	w = httptest.NewRecorder()
+	n, err = writeObjectRanges(w, true, ranges, size, "text/plain", func(byteRange) (io.ReadCloser, error) {
+		return nil, ErrConsensus
+	})
+	assert.Equal(t, ErrConsensus, err)
+	assert.Equal(t, int64(0), n)
+	assert.Empty(t, w.Header().Get(ContentTypeHeader))
+}
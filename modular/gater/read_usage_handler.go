@@ -0,0 +1,251 @@
+package gater
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	"github.com/bnb-chain/greenfield-storage-provider/util"
+)
+
+const (
+	// readUsageGranularityHour returns the read usage of every hour
+	readUsageGranularityHour = "hour"
+	// readUsageGranularityDay returns the read usage of every day
+	readUsageGranularityDay = "day"
+	// defaultReadUsageTimeRange defines the default time range of the read usage if it is not specified
+	defaultReadUsageTimeRange = 24 * time.Hour
+)
+
+// ReadUsage is the read usage of a bucket, an object or a user in the get bucket read usage response.
+type ReadUsage struct {
+	Key                    string `xml:"Key"` // the bucket id, the object id or the user address
+	Name                   string `xml:"Name,omitempty"`
+	PeriodStartTimestampUs int64  `xml:"PeriodStartTimestampUs,omitempty"`
+	ReadSize               uint64 `xml:"ReadSize"`
+	ReadCount              uint64 `xml:"ReadCount"`
+}
+
+// GetBucketReadUsageResult is the response of the get bucket read usage request.
+type GetBucketReadUsageResult struct {
+	XMLName          xml.Name `xml:"GetBucketReadUsageResult"`
+	Version          string   `xml:"version,attr"`
+	BucketName       string   `xml:"BucketName"`
+	BucketID         string   `xml:"BucketID"`
+	Dimension        string   `xml:"Dimension"`
+	Granularity      string   `xml:"Granularity,omitempty"`
+	StartTimestampUs int64    `xml:"StartTimestampUs"`
+	EndTimestampUs   int64    `xml:"EndTimestampUs"`
+	// ReadQuotaSize and SPFreeReadQuotaSize are the charged and the free read quota of the bucket
+	ReadQuotaSize       uint64 `xml:"ReadQuotaSize"`
+	SPFreeReadQuotaSize uint64 `xml:"SPFreeReadQuotaSize"`
+	ReadConsumedSize    uint64 `xml:"ReadConsumedSize"`
+	ConsumedPercent     uint32 `xml:"ConsumedPercent"`
+	// ProjectedExhaustionTimestamp is the projected unix timestamp when the read quota is exhausted by the read
+	// rate of the last 24 hours, 0 means the bucket is not read recently.
+	ProjectedExhaustionTimestamp int64       `xml:"ProjectedExhaustionTimestamp"`
+	ReadUsages                   []ReadUsage `xml:"ReadUsage"`
+}
+
+// getBucketReadUsageHandler handles the get bucket read usage request, which returns the hourly or daily read
+// usage of the bucket, its objects or its users, or the top consumers of the time range if the granularity is
+// not specified, together with the read quota of the bucket.
+func (g *GateModular) getBucketReadUsageHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err           error
+		reqCtx        *RequestContext
+		authenticated bool
+		traffic       *spdb.BucketTraffic
+		usages        []*spdb.ReadUsage
+	)
+	startTime := time.Now()
+	defer func() {
+		reqCtx.Cancel()
+		if err != nil {
+			reqCtx.SetError(gfsperrors.MakeGfSpError(err))
+			reqCtx.SetHttpCode(int(gfsperrors.MakeGfSpError(err).GetHttpStatusCode()))
+			MakeErrorResponse(w, gfsperrors.MakeGfSpError(err))
+			metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(startTime).Seconds())
+		} else {
+			reqCtx.SetHttpCode(http.StatusOK)
+			metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+			metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(startTime).Seconds())
+		}
+		log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+	}()
+
+	reqCtx, err = NewRequestContext(r, g)
+	if err != nil {
+		return
+	}
+	// the read usage is as sensitive as the read records
+	authenticated, err = g.baseApp.GfSpClient().VerifyAuthentication(reqCtx.Context(),
+		coremodule.AuthOpTypeListBucketReadRecord, reqCtx.Account(), reqCtx.bucketName, "")
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to verify authentication", "error", err)
+		return
+	}
+	if !authenticated {
+		log.CtxErrorw(reqCtx.Context(), "no permission to operate")
+		err = ErrNoPermission
+		return
+	}
+	bucketInfo, err := g.baseApp.Consensus().QueryBucketInfo(reqCtx.Context(), reqCtx.bucketName)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket info from consensus", "error", err)
+		err = ErrConsensus
+		return
+	}
+
+	queryParams := reqCtx.request.URL.Query()
+	query := &spdb.ReadUsageQuery{
+		Dimension: spdb.ReadUsageDimension(queryParams.Get(ReadUsageDimensionQuery)),
+		BucketID:  bucketInfo.Id.Uint64(),
+	}
+	switch query.Dimension {
+	case "":
+		query.Dimension = spdb.ReadUsageByBucket
+	case spdb.ReadUsageByBucket, spdb.ReadUsageByObject, spdb.ReadUsageByUser:
+	default:
+		log.CtxErrorw(reqCtx.Context(), "failed to parse dimension query", "dimension", query.Dimension)
+		err = ErrInvalidQuery
+		return
+	}
+	granularity := queryParams.Get(ReadUsageGranularityQuery)
+	switch granularity {
+	case readUsageGranularityHour:
+		query.PeriodUs = spdb.ReadUsageHourUs
+	case readUsageGranularityDay:
+		query.PeriodUs = spdb.ReadUsageDayUs
+	case "":
+	default:
+		log.CtxErrorw(reqCtx.Context(), "failed to parse granularity query", "granularity", granularity)
+		err = ErrInvalidQuery
+		return
+	}
+	query.EndTimestampUs = startTime.UnixMicro()
+	if endTs := queryParams.Get(EndTimestampUs); endTs != "" {
+		if query.EndTimestampUs, err = util.StringToInt64(endTs); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to parse end_ts query", "error", err)
+			err = ErrInvalidQuery
+			return
+		}
+	}
+	query.StartTimestampUs = query.EndTimestampUs - defaultReadUsageTimeRange.Microseconds()
+	if startTs := queryParams.Get(StartTimestampUs); startTs != "" {
+		if query.StartTimestampUs, err = util.StringToInt64(startTs); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to parse start_ts query", "error", err)
+			err = ErrInvalidQuery
+			return
+		}
+	}
+	if query.StartTimestampUs >= query.EndTimestampUs {
+		log.CtxErrorw(reqCtx.Context(), "the start timestamp should be less than the end timestamp")
+		err = ErrInvalidQuery
+		return
+	}
+	query.LimitNum = int(g.maxListReadQuota)
+	if maxRecords := queryParams.Get(ListBucketReadRecordMaxRecordsQuery); maxRecords != "" {
+		var limit int64
+		if limit, err = util.StringToInt64(maxRecords); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to parse max record num query", "error", err)
+			err = ErrInvalidQuery
+			return
+		}
+		if limit > 0 && limit < g.maxListReadQuota {
+			query.LimitNum = int(limit)
+		}
+	}
+
+	traffic, err = g.baseApp.GfSpDB().GetBucketTraffic(bucketInfo.Id.Uint64())
+	// if the traffic table has not been created and initialized yet, return the chain info
+	if errors.Is(err, gorm.ErrRecordNotFound) || traffic == nil {
+		traffic = &spdb.BucketTraffic{
+			BucketID:         bucketInfo.Id.Uint64(),
+			BucketName:       bucketInfo.GetBucketName(),
+			ChargedQuotaSize: bucketInfo.GetChargedReadQuota(),
+		}
+		if traffic.FreeQuotaSize, err = g.baseApp.Consensus().QuerySPFreeQuota(reqCtx.Context(),
+			g.baseApp.OperatorAddress()); err != nil {
+			log.CtxErrorw(reqCtx.Context(), "failed to get sp free quota from consensus", "error", err)
+			err = ErrConsensus
+			return
+		}
+	} else if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket traffic", "error", err)
+		err = ErrSPDB
+		return
+	}
+	if usages, err = g.baseApp.GfSpDB().GetReadUsage(query); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get read usage", "error", err)
+		err = ErrSPDB
+		return
+	}
+	windowStart := spdb.ReadQuotaProjectionStart(startTime)
+	recent, err := g.baseApp.GfSpDB().GetReadUsage(&spdb.ReadUsageQuery{
+		Dimension:        spdb.ReadUsageByBucket,
+		BucketID:         bucketInfo.Id.Uint64(),
+		StartTimestampUs: windowStart.UnixMicro(),
+		EndTimestampUs:   startTime.UnixMicro(),
+	})
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get recent read usage", "error", err)
+		err = ErrSPDB
+		return
+	}
+
+	result := &GetBucketReadUsageResult{
+		Version:             GnfdResponseXMLVersion,
+		BucketName:          bucketInfo.GetBucketName(),
+		BucketID:            util.Uint64ToString(bucketInfo.Id.Uint64()),
+		Dimension:           string(query.Dimension),
+		Granularity:         granularity,
+		StartTimestampUs:    query.StartTimestampUs,
+		EndTimestampUs:      query.EndTimestampUs,
+		ReadQuotaSize:       traffic.ChargedQuotaSize,
+		SPFreeReadQuotaSize: traffic.FreeQuotaSize,
+		ReadConsumedSize:    traffic.ReadConsumedSize,
+		ConsumedPercent:     traffic.ConsumedPercent(),
+		ReadUsages:          make([]ReadUsage, 0, len(usages)),
+	}
+	var recentReadSize uint64
+	if len(recent) > 0 {
+		recentReadSize = recent[0].ReadSize
+	}
+	if exhaustion, ok := traffic.ProjectQuotaExhaustion(recentReadSize, startTime.Sub(windowStart),
+		startTime); ok {
+		result.ProjectedExhaustionTimestamp = exhaustion.Unix()
+	}
+	for _, usage := range usages {
+		result.ReadUsages = append(result.ReadUsages, ReadUsage{
+			Key:                    usage.UsageKey,
+			Name:                   usage.Name,
+			PeriodStartTimestampUs: usage.PeriodStartTimestampUs,
+			ReadSize:               usage.ReadSize,
+			ReadCount:              usage.ReadCount,
+		})
+	}
+	xmlBody, err := xml.Marshal(result)
+	if err != nil {
+		log.Errorw("failed to marshal xml", "error", err)
+		err = ErrEncodeResponse
+		return
+	}
+	w.Header().Set(ContentTypeHeader, ContentTypeXMLHeaderValue)
+	if _, err = w.Write(xmlBody); err != nil {
+		log.Errorw("failed to write body", "error", err)
+		err = ErrEncodeResponse
+		return
+	}
+	log.CtxDebugw(reqCtx.Context(), "succeed to get bucket read usage", "dimension", query.Dimension,
+		"granularity", granularity, "usage_number", len(usages))
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 
 	commonhttp "github.com/bnb-chain/greenfield-common/go/http"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
 )
 
 // RequestContext generates from http request, it records the common info
@@ -67,6 +70,10 @@ func NewRequestContext(r *http.Request, g *GateModular) (*RequestContext, error)
 		return reqCtx, err
 	}
 	reqCtx.account = account
+	if !localhttp.LimitAccount(r, account) {
+		log.CtxErrorw(reqCtx.Context(), "failed to pass the account rate limiter", "account", account)
+		return reqCtx, ErrTooManyRequests
+	}
 	return reqCtx, nil
 }
 
@@ -135,6 +142,13 @@ func signaturePrefix(version, algorithm string) string {
 
 func (r *RequestContext) VerifySignature() (string, error) {
 	requestSignature := r.request.Header.Get(GnfdAuthorizationHeader)
+	if requestSignature == "" && r.request.URL.Query().Get(GnfdPresignSignatureQuery) != "" {
+		accAddress, err := r.verifyPresignedURL(time.Now())
+		if err != nil {
+			return "", err
+		}
+		return accAddress.String(), nil
+	}
 	v1SignaturePrefix := signaturePrefix(SignTypeV1, SignAlgorithm)
 	if strings.HasPrefix(requestSignature, v1SignaturePrefix) {
 		accAddress, err := r.verifySignatureV1(requestSignature[len(v1SignaturePrefix):])
@@ -204,6 +218,96 @@ func (r *RequestContext) verifySignatureV1(requestSignature string) (sdk.AccAddr
 	return addr, nil
 }
 
+// verifyPresignedURL used to verify the presigned url whose signature is carried in query params, return
+// (address, nil) if check succeed. The presigned url is scoped to the bucket, object and http method, and
+// is only allowed to get or put object.
+func (r *RequestContext) verifyPresignedURL(now time.Time) (sdk.AccAddress, error) {
+	if r.routerName != getObjectRouterName && r.routerName != putObjectRouterName {
+		log.CtxErrorw(r.ctx, "failed to verify presigned url due to unsupported router", "router", r.routerName)
+		return nil, ErrPresignedURLNotAllowed
+	}
+	query := r.request.URL.Query()
+	if query.Get(GnfdPresignAlgorithmQuery) != SignAlgorithm {
+		return nil, ErrUnsupportedSignType
+	}
+	account, err := sdk.AccAddressFromHexUnsafe(query.Get(GnfdPresignCredentialQuery))
+	if err != nil {
+		log.CtxErrorw(r.ctx, "failed to parse presigned url credential", "error", err)
+		return nil, ErrPresignedURLFormat
+	}
+	date, err := time.Parse(PresignDateFormat, query.Get(GnfdPresignDateQuery))
+	if err != nil {
+		log.CtxErrorw(r.ctx, "failed to parse presigned url date", "error", err)
+		return nil, ErrPresignedURLFormat
+	}
+	expires, err := strconv.ParseInt(query.Get(GnfdPresignExpiresQuery), 10, 64)
+	if err != nil || expires <= 0 || expires > MaxPresignExpiresInSec {
+		log.CtxErrorw(r.ctx, "failed to parse presigned url expires", "expires", query.Get(GnfdPresignExpiresQuery))
+		return nil, ErrPresignedURLFormat
+	}
+	signature, err := hex.DecodeString(query.Get(GnfdPresignSignatureQuery))
+	if err != nil || len(signature) != 65 {
+		log.CtxErrorw(r.ctx, "failed to decode presigned url signature")
+		return nil, ErrPresignedURLFormat
+	}
+	if now.Before(date.Add(-MaxPresignClockSkewInSec*time.Second)) || now.After(date.Add(time.Duration(expires)*time.Second)) {
+		log.CtxErrorw(r.ctx, "presigned url is expired or not yet valid", "date", date, "expires", expires)
+		return nil, ErrPresignedURLExpired
+	}
+
+	msgToSign := GetPresignedMsgToSign(r.request.Method, r.bucketName, r.objectName, account, date, expires)
+	addr, pk, err := RecoverAddr(msgToSign, signature)
+	if err != nil {
+		log.CtxErrorw(r.ctx, "failed to recover address")
+		return nil, ErrRequestConsistent
+	}
+	if !secp256k1.VerifySignature(pk.Bytes(), msgToSign, signature[:len(signature)-1]) || !addr.Equals(account) {
+		log.CtxErrorw(r.ctx, "failed to verify presigned url signature")
+		return nil, ErrRequestConsistent
+	}
+	return addr, nil
+}
+
+// GetPresignedMsgToSign returns the hash of the presigned url content which the account signs against.
+func GetPresignedMsgToSign(method, bucketName, objectName string, account sdk.AccAddress, date time.Time, expires int64) []byte {
+	content := strings.Join([]string{
+		SignAlgorithm,
+		method,
+		bucketName,
+		objectName,
+		account.String(),
+		date.UTC().Format(PresignDateFormat),
+		strconv.FormatInt(expires, 10),
+	}, "\n")
+	return crypto.Keccak256([]byte(content))
+}
+
+// PresignURL mints the presigned url from the object url, e.g. https://bucket.sp.com/object, the url is valid
+// for the http method and expires seconds, sign is used to sign the msg by the private key of the account.
+func PresignURL(objectURL, method, bucketName, objectName string, account sdk.AccAddress, expires int64,
+	sign func(msg []byte) ([]byte, error)) (string, error) {
+	if expires <= 0 || expires > MaxPresignExpiresInSec {
+		return "", ErrPresignedURLFormat
+	}
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", err
+	}
+	date := time.Now().UTC()
+	signature, err := sign(GetPresignedMsgToSign(method, bucketName, objectName, account, date, expires))
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(GnfdPresignAlgorithmQuery, SignAlgorithm)
+	query.Set(GnfdPresignCredentialQuery, account.String())
+	query.Set(GnfdPresignDateQuery, date.Format(PresignDateFormat))
+	query.Set(GnfdPresignExpiresQuery, strconv.FormatInt(expires, 10))
+	query.Set(GnfdPresignSignatureQuery, hex.EncodeToString(signature))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
 // RecoverAddr recovers the sender address from msg and signature
 // TODO: move it to greenfield-common
 func RecoverAddr(msg []byte, sig []byte) (sdk.AccAddress, ethsecp256k1.PubKey, error) {
@@ -0,0 +1,86 @@
+package gater
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPresignedRequestContext(t *testing.T, method, presignedURL string) *RequestContext {
+	req := httptest.NewRequest(method, presignedURL, nil)
+	var match mux.RouteMatch
+	require.True(t, setupRouter(t).Match(req, &match))
+	return &RequestContext{
+		g:          gw,
+		ctx:        context.Background(),
+		request:    req,
+		routerName: match.Route.GetName(),
+		bucketName: match.Vars["bucket"],
+		objectName: match.Vars["object"],
+		vars:       match.Vars,
+	}
+}
+
+func TestVerifyPresignedURL(t *testing.T) {
+	key, err := ethcrypto.GenerateKey()
+	require.NoError(t, err)
+	account := sdk.AccAddress(ethcrypto.PubkeyToAddress(key.PublicKey).Bytes())
+	sign := func(msg []byte) ([]byte, error) { return ethcrypto.Sign(msg, key) }
+	objectURL := scheme + bucketName + "." + testDomain + "/" + objectName
+
+	getURL, err := PresignURL(objectURL, http.MethodGet, bucketName, objectName, account, 3600, sign)
+	require.NoError(t, err)
+	putURL, err := PresignURL(objectURL, http.MethodPut, bucketName, objectName, account, 3600, sign)
+	require.NoError(t, err)
+	otherURL, err := PresignURL(scheme+bucketName+"."+testDomain+"/other", http.MethodGet, bucketName, objectName, account, 3600, sign)
+	require.NoError(t, err)
+	_, err = PresignURL(objectURL, http.MethodGet, bucketName, objectName, account, MaxPresignExpiresInSec+1, sign)
+	assert.Equal(t, ErrPresignedURLFormat, err)
+
+	tamper := func(rawURL, key, value string) string {
+		u, _ := url.Parse(rawURL)
+		query := u.Query()
+		query.Set(key, value)
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+	now := time.Now()
+	testCases := []struct {
+		name      string
+		method    string
+		url       string
+		now       time.Time
+		wantedErr error
+	}{
+		{"get object", http.MethodGet, getURL, now, nil},
+		{"put object", http.MethodPut, putURL, now, nil},
+		{"mismatched method", http.MethodPut, getURL, now, ErrRequestConsistent},
+		{"mismatched object", http.MethodGet, otherURL, now, ErrRequestConsistent},
+		{"expired", http.MethodGet, getURL, now.Add(2 * time.Hour), ErrPresignedURLExpired},
+		{"not yet valid", http.MethodGet, getURL, now.Add(-time.Hour), ErrPresignedURLExpired},
+		{"extended expires", http.MethodGet, tamper(getURL, GnfdPresignExpiresQuery, "7200"), now, ErrRequestConsistent},
+		{"other credential", http.MethodGet, tamper(getURL, GnfdPresignCredentialQuery, sdk.AccAddress(make([]byte, 20)).String()), now, ErrRequestConsistent},
+		{"unsupported algorithm", http.MethodGet, tamper(getURL, GnfdPresignAlgorithmQuery, SignAlgorithmEddsa), now, ErrUnsupportedSignType},
+		{"invalid signature", http.MethodGet, tamper(getURL, GnfdPresignSignatureQuery, "00"), now, ErrPresignedURLFormat},
+		{"not object router", http.MethodGet, tamper(getURL, GetObjectMetaQuery, ""), now, ErrPresignedURLNotAllowed},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			reqCtx := setupPresignedRequestContext(t, tt.method, tt.url)
+			addr, err := reqCtx.verifyPresignedURL(tt.now)
+			assert.Equal(t, tt.wantedErr, err)
+			if tt.wantedErr == nil {
+				assert.Equal(t, account, addr)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ const (
 	resumablePutObjectRouterName                   = "ResumablePutObject"
 	queryResumeOffsetName                          = "QueryResumeOffsetName"
 	getObjectRouterName                            = "GetObject"
+	headObjectRouterName                           = "HeadObject"
 	getChallengeInfoRouterName                     = "GetChallengeInfo"
 	replicateObjectPieceRouterName                 = "ReplicateObjectPiece"
 	getUserBucketsRouterName                       = "GetUserBuckets"
@@ -61,6 +62,19 @@ const (
 	listSpExitEventsRouterName                     = "ListSpExitEvents"
 	verifyPermissionByIDRouterName                 = "VerifyPermissionByID"
 	getSPInfoRouterName                            = "GetSPInfo"
+	createMultipartUploadRouterName                = "CreateMultipartUpload"
+	uploadPartRouterName                           = "UploadPart"
+	listPartsRouterName                            = "ListParts"
+	completeMultipartUploadRouterName              = "CompleteMultipartUpload"
+	abortMultipartUploadRouterName                 = "AbortMultipartUpload"
+	corsPreflightRouterName                        = "CORSPreflight"
+	putBucketCORSRouterName                        = "PutBucketCORS"
+	getBucketCORSRouterName                        = "GetBucketCORS"
+	deleteBucketCORSRouterName                     = "DeleteBucketCORS"
+	getBucketReadUsageRouterName                   = "GetBucketReadUsage"
+	putBucketNotificationRouterName                = "PutBucketNotification"
+	getBucketNotificationRouterName                = "GetBucketNotification"
+	deleteBucketNotificationRouterName             = "DeleteBucketNotification"
 )
 
 const (
@@ -79,6 +93,9 @@ func (g *GateModular) notFoundHandler(w http.ResponseWriter, r *http.Request) {
 
 // RegisterHandler registers the handlers to the gateway router.
 func (g *GateModular) RegisterHandler(router *mux.Router) {
+	// cors preflight router, all the paths are matched
+	router.Methods(http.MethodOptions).Name(corsPreflightRouterName).HandlerFunc(g.corsPreflightHandler)
+
 	// off-chain-auth router
 	router.Path(AuthRequestNoncePath).
 		Name(requestNonceName).
@@ -133,6 +150,23 @@ func (g *GateModular) RegisterHandler(router *mux.Router) {
 		r.NewRoute().Name(resumablePutObjectRouterName).Methods(http.MethodPost).Path("/{object:.+}").HandlerFunc(g.resumablePutObjectHandler).Queries(
 			"offset", "{offset}",
 			"complete", "{complete}")
+		// Create Multipart Upload
+		r.NewRoute().Name(createMultipartUploadRouterName).Methods(http.MethodPost).Path("/{object:.+}").HandlerFunc(g.createMultipartUploadHandler).Queries(
+			MultipartUploadsQuery, "")
+		// Upload Part
+		r.NewRoute().Name(uploadPartRouterName).Methods(http.MethodPut).Path("/{object:.+}").HandlerFunc(g.uploadPartHandler).Queries(
+			MultipartPartNumberQuery, "{part_number}",
+			MultipartUploadIDQuery, "{upload_id}")
+		// List Parts
+		r.NewRoute().Name(listPartsRouterName).Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(g.listPartsHandler).Queries(
+			MultipartUploadIDQuery, "{upload_id}")
+		// Complete Multipart Upload
+		r.NewRoute().Name(completeMultipartUploadRouterName).Methods(http.MethodPost).Path("/{object:.+}").HandlerFunc(g.completeMultipartUploadHandler).Queries(
+			MultipartUploadIDQuery, "{upload_id}")
+		// Abort Multipart Upload
+		r.NewRoute().Name(abortMultipartUploadRouterName).Methods(http.MethodDelete).Path("/{object:.+}").HandlerFunc(g.abortMultipartUploadHandler).Queries(
+			MultipartUploadIDQuery, "{upload_id}")
+
 		// Put Object
 		r.NewRoute().Name(putObjectRouterName).Methods(http.MethodPut).Path("/{object:.+}").HandlerFunc(g.putObjectHandler)
 
@@ -144,6 +178,24 @@ func (g *GateModular) RegisterHandler(router *mux.Router) {
 		r.NewRoute().Name(queryUploadProgressRouterName).Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(g.queryUploadProgressHandler).Queries(
 			UploadProgressQuery, "")
 
+		// Put Bucket CORS
+		r.NewRoute().Name(putBucketCORSRouterName).Methods(http.MethodPut).Queries(BucketCORSQuery, "").HandlerFunc(g.bucketCORSHandler)
+
+		// Get Bucket CORS
+		r.NewRoute().Name(getBucketCORSRouterName).Methods(http.MethodGet).Queries(BucketCORSQuery, "").HandlerFunc(g.bucketCORSHandler)
+
+		// Delete Bucket CORS
+		r.NewRoute().Name(deleteBucketCORSRouterName).Methods(http.MethodDelete).Queries(BucketCORSQuery, "").HandlerFunc(g.bucketCORSHandler)
+
+		// Put Bucket Notification
+		r.NewRoute().Name(putBucketNotificationRouterName).Methods(http.MethodPut).Queries(BucketNotificationQuery, "").HandlerFunc(g.bucketNotificationHandler)
+
+		// Get Bucket Notification
+		r.NewRoute().Name(getBucketNotificationRouterName).Methods(http.MethodGet).Queries(BucketNotificationQuery, "").HandlerFunc(g.bucketNotificationHandler)
+
+		// Delete Bucket Notification
+		r.NewRoute().Name(deleteBucketNotificationRouterName).Methods(http.MethodDelete).Queries(BucketNotificationQuery, "").HandlerFunc(g.bucketNotificationHandler)
+
 		// Get Bucket Meta
 		r.NewRoute().Name(getBucketMetaRouterName).Methods(http.MethodGet).Queries(GetBucketMetaQuery, "").HandlerFunc(g.getBucketMetaHandler)
 
@@ -154,6 +206,9 @@ func (g *GateModular) RegisterHandler(router *mux.Router) {
 		// Get Object
 		r.NewRoute().Name(getObjectRouterName).Methods(http.MethodGet).Path("/{object:.+}").HandlerFunc(g.getObjectHandler)
 
+		// Head Object
+		r.NewRoute().Name(headObjectRouterName).Methods(http.MethodHead).Path("/{object:.+}").HandlerFunc(g.headObjectHandler)
+
 		// Get Bucket Read Quota
 		r.NewRoute().Name(getBucketReadQuotaRouterName).Methods(http.MethodGet).HandlerFunc(g.getBucketReadQuotaHandler).Queries(
 			GetBucketReadQuotaQuery, "",
@@ -167,6 +222,9 @@ func (g *GateModular) RegisterHandler(router *mux.Router) {
 			r.NewRoute().Name(getPaymentByBucketNameRouterName).Methods(http.MethodGet).Queries(GetPaymentByBucketNameQuery, "").HandlerFunc(g.getPaymentByBucketNameHandler)
 		}
 
+		// Get Bucket Read Usage
+		r.NewRoute().Name(getBucketReadUsageRouterName).Methods(http.MethodGet).Queries(ReadUsageQuery, "").HandlerFunc(g.getBucketReadUsageHandler)
+
 		// List Bucket Read Record
 		r.NewRoute().Name(listBucketReadRecordRouterName).Methods(http.MethodGet).HandlerFunc(g.listBucketReadRecordHandler).Queries(
 			ListBucketReadRecordQuery, "",
@@ -270,5 +328,9 @@ func (g *GateModular) RegisterHandler(router *mux.Router) {
 	http.Handle("/", router)
 
 	router.NotFoundHandler = http.HandlerFunc(g.notFoundHandler)
+	// the limiter runs before the bucket cors policy lookup, the responses rejected by the limiter only carry
+	// the default cors headers, so the browsers can read them
+	router.Use(g.cors.DefaultHandler)
 	router.Use(localhttp.Limit)
+	router.Use(g.cors.Handler)
 }
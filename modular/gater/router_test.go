@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -23,10 +24,18 @@ var (
 	objectName = "test-object-name"
 )
 
+var (
+	testRouter     *mux.Router
+	testRouterOnce sync.Once
+)
+
 func setupRouter(t *testing.T) *mux.Router {
-	gwRouter := mux.NewRouter().SkipClean(true)
-	gw.RegisterHandler(gwRouter)
-	return gwRouter
+	// the router is registered to the default serve mux which panics on the second registration
+	testRouterOnce.Do(func() {
+		testRouter = mux.NewRouter().SkipClean(true)
+		gw.RegisterHandler(testRouter)
+	})
+	return testRouter
 }
 
 func TestRouters(t *testing.T) {
@@ -119,6 +128,54 @@ func TestRouters(t *testing.T) {
 			shouldMatch:      true,
 			wantedRouterName: resumablePutObjectRouterName,
 		},
+		{
+			name:             "Create multipart upload router, virtual host style",
+			router:           gwRouter,
+			method:           http.MethodPost,
+			url:              scheme + bucketName + "." + testDomain + "/" + objectName + "?" + MultipartUploadsQuery,
+			shouldMatch:      true,
+			wantedRouterName: createMultipartUploadRouterName,
+		},
+		{
+			name:             "Create multipart upload router, path style",
+			router:           gwRouter,
+			method:           http.MethodPost,
+			url:              scheme + testDomain + "/" + bucketName + "/" + objectName + "?" + MultipartUploadsQuery,
+			shouldMatch:      true,
+			wantedRouterName: createMultipartUploadRouterName,
+		},
+		{
+			name:             "Upload part router, path style",
+			router:           gwRouter,
+			method:           http.MethodPut,
+			url:              scheme + testDomain + "/" + bucketName + "/" + objectName + "?" + MultipartPartNumberQuery + "=1&" + MultipartUploadIDQuery + "=1",
+			shouldMatch:      true,
+			wantedRouterName: uploadPartRouterName,
+		},
+		{
+			name:             "List parts router, path style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + testDomain + "/" + bucketName + "/" + objectName + "?" + MultipartUploadIDQuery + "=1",
+			shouldMatch:      true,
+			wantedRouterName: listPartsRouterName,
+		},
+		{
+			name:             "Complete multipart upload router, path style",
+			router:           gwRouter,
+			method:           http.MethodPost,
+			url:              scheme + testDomain + "/" + bucketName + "/" + objectName + "?" + MultipartUploadIDQuery + "=1",
+			shouldMatch:      true,
+			wantedRouterName: completeMultipartUploadRouterName,
+		},
+		{
+			name:             "Abort multipart upload router, virtual host style",
+			router:           gwRouter,
+			method:           http.MethodDelete,
+			url:              scheme + bucketName + "." + testDomain + "/" + objectName + "?" + MultipartUploadIDQuery + "=1",
+			shouldMatch:      true,
+			wantedRouterName: abortMultipartUploadRouterName,
+		},
 		{
 			name:             "QueryUploadOffset router, virtual host style",
 			router:           gwRouter,
@@ -168,6 +225,94 @@ func TestRouters(t *testing.T) {
 			shouldMatch:      true,
 			wantedRouterName: getObjectRouterName,
 		},
+		{
+			name:             "Head object router, virtual host style",
+			router:           gwRouter,
+			method:           http.MethodHead,
+			url:              scheme + bucketName + "." + testDomain + "/" + objectName,
+			shouldMatch:      true,
+			wantedRouterName: headObjectRouterName,
+		},
+		{
+			name:             "Head object router, path style",
+			router:           gwRouter,
+			method:           http.MethodHead,
+			url:              scheme + testDomain + "/" + bucketName + "/" + objectName,
+			shouldMatch:      true,
+			wantedRouterName: headObjectRouterName,
+		},
+		{
+			name:             "CORS preflight router",
+			router:           gwRouter,
+			method:           http.MethodOptions,
+			url:              scheme + testDomain + "/" + bucketName + "/" + objectName,
+			shouldMatch:      true,
+			wantedRouterName: corsPreflightRouterName,
+		},
+		{
+			name:             "Put bucket cors router, virtual host style",
+			router:           gwRouter,
+			method:           http.MethodPut,
+			url:              scheme + bucketName + "." + testDomain + "/?" + BucketCORSQuery,
+			shouldMatch:      true,
+			wantedRouterName: putBucketCORSRouterName,
+		},
+		{
+			name:             "Get bucket cors router, path style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + testDomain + "/" + bucketName + "?" + BucketCORSQuery,
+			shouldMatch:      true,
+			wantedRouterName: getBucketCORSRouterName,
+		},
+		{
+			name:             "Delete bucket cors router, path style",
+			router:           gwRouter,
+			method:           http.MethodDelete,
+			url:              scheme + testDomain + "/" + bucketName + "?" + BucketCORSQuery,
+			shouldMatch:      true,
+			wantedRouterName: deleteBucketCORSRouterName,
+		},
+		{
+			name:             "Put bucket notification router, virtual host style",
+			router:           gwRouter,
+			method:           http.MethodPut,
+			url:              scheme + bucketName + "." + testDomain + "/?" + BucketNotificationQuery,
+			shouldMatch:      true,
+			wantedRouterName: putBucketNotificationRouterName,
+		},
+		{
+			name:             "Get bucket notification router, path style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + testDomain + "/" + bucketName + "?" + BucketNotificationQuery,
+			shouldMatch:      true,
+			wantedRouterName: getBucketNotificationRouterName,
+		},
+		{
+			name:             "Delete bucket notification router, path style",
+			router:           gwRouter,
+			method:           http.MethodDelete,
+			url:              scheme + testDomain + "/" + bucketName + "?" + BucketNotificationQuery,
+			shouldMatch:      true,
+			wantedRouterName: deleteBucketNotificationRouterName,
+		},
+		{
+			name:             "Get bucket read usage router, path style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + testDomain + "/" + bucketName + "?" + ReadUsageQuery + "&" + ReadUsageGranularityQuery + "=day",
+			shouldMatch:      true,
+			wantedRouterName: getBucketReadUsageRouterName,
+		},
+		{
+			name:             "Get bucket read usage router, virtual host style",
+			router:           gwRouter,
+			method:           http.MethodGet,
+			url:              scheme + bucketName + "." + testDomain + "/?" + ReadUsageQuery,
+			shouldMatch:      true,
+			wantedRouterName: getBucketReadUsageRouterName,
+		},
 		{
 			name:             "Get bucket read quota router, virtual host style",
 			router:           gwRouter,
@@ -0,0 +1,421 @@
+package gater
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// s3SignV4Algorithm defines the algorithm of the aws signature version 4
+	s3SignV4Algorithm = "AWS4-HMAC-SHA256"
+	// s3StreamingPayloadAlgorithm defines the algorithm of the chunk signatures of the streaming payload
+	s3StreamingPayloadAlgorithm = "AWS4-HMAC-SHA256-PAYLOAD"
+	// s3ServiceName defines the service name in the credential scope
+	s3ServiceName = "s3"
+	// s3ScopeTerminator defines the terminator of the credential scope
+	s3ScopeTerminator = "aws4_request"
+	// s3DateFormat defines the format of the x-amz-date
+	s3DateFormat = "20060102T150405Z"
+	// s3ScopeDateFormat defines the format of the date in the credential scope
+	s3ScopeDateFormat = "20060102"
+	// s3MaxClockSkew defines the allowed clock skew between the client and the sp
+	s3MaxClockSkew = 15 * time.Minute
+	// s3MaxPresignExpires defines the max valid period of the presigned url
+	s3MaxPresignExpires = 7 * 24 * time.Hour
+
+	// s3UnsignedPayload defines the payload is not signed
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	// s3StreamingPayload defines the payload is signed by chunks in aws-chunked encoding
+	s3StreamingPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	// s3StreamingPayloadPrefix defines the prefix of the other streaming payloads, e.g. the payloads with the
+	// trailing checksums and the payloads signed by ecdsa, which are not supported
+	s3StreamingPayloadPrefix = "STREAMING-"
+	// s3EmptySHA256 defines the hex encoded sha256 of the empty string
+	s3EmptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	S3AmzDateHeader                 = "X-Amz-Date"
+	S3AmzContentSHA256Header        = "X-Amz-Content-Sha256"
+	S3AmzDecodedContentLengthHeader = "X-Amz-Decoded-Content-Length"
+	S3AmzAlgorithmQuery             = "X-Amz-Algorithm"
+	S3AmzCredentialQuery            = "X-Amz-Credential"
+	S3AmzDateQuery                  = "X-Amz-Date"
+	S3AmzExpiresQuery               = "X-Amz-Expires"
+	S3AmzSignedHeadersQuery         = "X-Amz-SignedHeaders"
+	S3AmzSignatureQuery             = "X-Amz-Signature"
+)
+
+// s3Credential is the parsed signature v4 of the request.
+type s3Credential struct {
+	accessKeyID   string
+	scopeDate     string
+	region        string
+	date          time.Time
+	signedHeaders []string
+	signature     string
+	payloadHash   string
+	presigned     bool
+	expires       time.Duration
+}
+
+func (c *s3Credential) scope() string {
+	return strings.Join([]string{c.scopeDate, c.region, s3ServiceName, s3ScopeTerminator}, "/")
+}
+
+// s3KeyLookup returns the secret access key and the greenfield account of the access key id.
+type s3KeyLookup func(accessKeyID string) (secretAccessKey string, account string, err error)
+
+// verifyS3SignatureV4 verifies the aws signature version 4 of the request which is carried in the
+// Authorization header or the presigned url query params, returns the greenfield account of the access key.
+// The request body is replaced by the reader which verifies the payload hash or the chunk signatures.
+func verifyS3SignatureV4(r *http.Request, region string, now time.Time, lookup s3KeyLookup) (string, error) {
+	cred, err := parseS3Credential(r)
+	if err != nil {
+		return "", err
+	}
+	if cred.scopeDate != cred.date.Format(s3ScopeDateFormat) {
+		return "", ErrS3AuthorizationMalformed
+	}
+	if region != "" && cred.region != region {
+		return "", ErrS3AuthorizationMalformed
+	}
+	if cred.presigned {
+		if now.Before(cred.date.Add(-s3MaxClockSkew)) {
+			return "", ErrS3RequestTimeTooSkewed
+		}
+		if now.After(cred.date.Add(cred.expires)) {
+			return "", ErrS3ExpiredPresignRequest
+		}
+	} else if now.Sub(cred.date) > s3MaxClockSkew || cred.date.Sub(now) > s3MaxClockSkew {
+		return "", ErrS3RequestTimeTooSkewed
+	}
+
+	secretAccessKey, account, err := lookup(cred.accessKeyID)
+	if err != nil {
+		return "", err
+	}
+	signingKey := s3SigningKey(secretAccessKey, cred.scopeDate, cred.region)
+	stringToSign := s3StringToSign(cred, s3CanonicalRequest(r, cred))
+	signature := hex.EncodeToString(s3HMAC(signingKey, []byte(stringToSign)))
+	if !hmac.Equal([]byte(signature), []byte(cred.signature)) {
+		return "", ErrS3SignatureDoesNotMatch
+	}
+
+	switch cred.payloadHash {
+	case s3UnsignedPayload:
+	case s3StreamingPayload:
+		r.Body = io.NopCloser(newS3ChunkedReader(r.Body, signingKey, cred, signature))
+		if decoded := r.Header.Get(S3AmzDecodedContentLengthHeader); decoded != "" {
+			if r.ContentLength, err = strconv.ParseInt(decoded, 10, 64); err != nil {
+				return "", ErrS3InvalidRequest
+			}
+		} else {
+			r.ContentLength = -1
+		}
+	default:
+		r.Body = io.NopCloser(newS3PayloadHashReader(r.Body, strings.ToLower(cred.payloadHash)))
+	}
+	return account, nil
+}
+
+// checkS3PayloadHash checks the x-amz-content-sha256 before the payload is read, it should be the hex encoded
+// sha256 of the payload, the unsigned payload or the supported streaming payload.
+func checkS3PayloadHash(payloadHash string) error {
+	switch {
+	case payloadHash == s3UnsignedPayload || payloadHash == s3StreamingPayload:
+		return nil
+	case strings.HasPrefix(payloadHash, s3StreamingPayloadPrefix):
+		return ErrS3NotImplemented
+	}
+	if decoded, err := hex.DecodeString(payloadHash); err != nil || len(decoded) != sha256.Size {
+		return ErrS3InvalidRequest
+	}
+	return nil
+}
+
+// parseS3Credential parses the signature v4 from the Authorization header, e.g.
+// AWS4-HMAC-SHA256 Credential=AKID/20230101/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-date, Signature=xxx
+// or from the presigned url query params.
+func parseS3Credential(r *http.Request) (*s3Credential, error) {
+	var (
+		cred       = &s3Credential{}
+		credential string
+		signed     string
+		date       string
+		err        error
+	)
+	if auth := r.Header.Get(GnfdAuthorizationHeader); auth != "" {
+		if !strings.HasPrefix(auth, s3SignV4Algorithm+" ") {
+			return nil, ErrS3SignatureVersionNotSupported
+		}
+		for _, item := range strings.Split(strings.TrimPrefix(auth, s3SignV4Algorithm+" "), ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+			if !ok {
+				return nil, ErrS3AuthorizationMalformed
+			}
+			switch key {
+			case "Credential":
+				credential = value
+			case "SignedHeaders":
+				signed = value
+			case "Signature":
+				cred.signature = value
+			default:
+				return nil, ErrS3AuthorizationMalformed
+			}
+		}
+		date = r.Header.Get(S3AmzDateHeader)
+		cred.payloadHash = r.Header.Get(S3AmzContentSHA256Header)
+		if cred.payloadHash == "" {
+			return nil, ErrS3InvalidRequest
+		}
+	} else {
+		query := r.URL.Query()
+		if query.Get(S3AmzAlgorithmQuery) == "" {
+			return nil, ErrS3AccessDenied
+		}
+		if query.Get(S3AmzAlgorithmQuery) != s3SignV4Algorithm {
+			return nil, ErrS3SignatureVersionNotSupported
+		}
+		cred.presigned = true
+		credential = query.Get(S3AmzCredentialQuery)
+		signed = query.Get(S3AmzSignedHeadersQuery)
+		cred.signature = query.Get(S3AmzSignatureQuery)
+		date = query.Get(S3AmzDateQuery)
+		expires, err := strconv.ParseInt(query.Get(S3AmzExpiresQuery), 10, 64)
+		if err != nil || expires <= 0 || time.Duration(expires)*time.Second > s3MaxPresignExpires {
+			return nil, ErrS3AuthorizationQueryMalformed
+		}
+		cred.expires = time.Duration(expires) * time.Second
+		cred.payloadHash = s3UnsignedPayload
+		if hash := r.Header.Get(S3AmzContentSHA256Header); hash != "" {
+			cred.payloadHash = hash
+		}
+	}
+
+	scope := strings.Split(credential, "/")
+	if len(scope) != 5 || scope[0] == "" || scope[3] != s3ServiceName || scope[4] != s3ScopeTerminator {
+		return nil, ErrS3AuthorizationMalformed
+	}
+	cred.accessKeyID, cred.scopeDate, cred.region = scope[0], scope[1], scope[2]
+	if signed == "" || cred.signature == "" {
+		return nil, ErrS3AuthorizationMalformed
+	}
+	cred.signedHeaders = strings.Split(signed, ";")
+	hasHost := false
+	for _, header := range cred.signedHeaders {
+		hasHost = hasHost || header == "host"
+	}
+	if !hasHost {
+		return nil, ErrS3AuthorizationMalformed
+	}
+	if cred.date, err = time.Parse(s3DateFormat, date); err != nil {
+		return nil, ErrS3AuthorizationMalformed
+	}
+	if err = checkS3PayloadHash(cred.payloadHash); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// s3CanonicalRequest returns the canonical request of the signature v4, the query params are sorted by
+// the encoded keys and then the encoded values.
+func s3CanonicalRequest(r *http.Request, cred *s3Credential) string {
+	var params [][2]string
+	for key, values := range r.URL.Query() {
+		if cred.presigned && key == S3AmzSignatureQuery {
+			continue
+		}
+		for _, value := range values {
+			params = append(params, [2]string{s3URIEncode(key, true), s3URIEncode(value, true)})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i][0] != params[j][0] {
+			return params[i][0] < params[j][0]
+		}
+		return params[i][1] < params[j][1]
+	})
+	query := make([]string, 0, len(params))
+	for _, param := range params {
+		query = append(query, param[0]+"="+param[1])
+	}
+
+	var headers strings.Builder
+	for _, name := range cred.signedHeaders {
+		var value string
+		switch name {
+		case "host":
+			value = r.Host
+		case "content-length":
+			value = r.Header.Get(ContentLengthHeader)
+			if value == "" && r.ContentLength >= 0 {
+				value = strconv.FormatInt(r.ContentLength, 10)
+			}
+		default:
+			var values []string
+			for _, v := range r.Header.Values(name) {
+				values = append(values, strings.Join(strings.Fields(v), " "))
+			}
+			value = strings.Join(values, ",")
+		}
+		headers.WriteString(name + ":" + value + "\n")
+	}
+
+	payloadHash := cred.payloadHash
+	if cred.presigned && r.Header.Get(S3AmzContentSHA256Header) == "" {
+		payloadHash = s3UnsignedPayload
+	}
+	return strings.Join([]string{
+		r.Method,
+		s3URIEncode(r.URL.Path, false),
+		strings.Join(query, "&"),
+		headers.String(),
+		strings.Join(cred.signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+func s3StringToSign(cred *s3Credential, canonicalRequest string) string {
+	return strings.Join([]string{
+		s3SignV4Algorithm,
+		cred.date.Format(s3DateFormat),
+		cred.scope(),
+		s3SHA256Hex([]byte(canonicalRequest)),
+	}, "\n")
+}
+
+func s3SigningKey(secretAccessKey, scopeDate, region string) []byte {
+	key := s3HMAC([]byte("AWS4"+secretAccessKey), []byte(scopeDate))
+	key = s3HMAC(key, []byte(region))
+	key = s3HMAC(key, []byte(s3ServiceName))
+	return s3HMAC(key, []byte(s3ScopeTerminator))
+}
+
+func s3HMAC(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func s3SHA256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// s3URIEncode encodes the string as the signature v4 requires, every byte except the unreserved
+// characters is percent encoded, and the slash is kept if it is not a query component.
+func s3URIEncode(s string, encodeSlash bool) string {
+	const hexChars = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' && !encodeSlash {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexChars[c>>4])
+		b.WriteByte(hexChars[c&15])
+	}
+	return b.String()
+}
+
+// s3PayloadHashReader verifies the sha256 of the payload when the payload is read to the end.
+type s3PayloadHashReader struct {
+	reader   io.Reader
+	hash     hash.Hash
+	expected string
+}
+
+func newS3PayloadHashReader(reader io.Reader, expected string) *s3PayloadHashReader {
+	return &s3PayloadHashReader{reader: reader, hash: sha256.New(), expected: expected}
+}
+
+func (p *s3PayloadHashReader) Read(buf []byte) (int, error) {
+	n, err := p.reader.Read(buf)
+	p.hash.Write(buf[:n])
+	if errors.Is(err, io.EOF) && hex.EncodeToString(p.hash.Sum(nil)) != p.expected {
+		return n, ErrS3ContentSHA256Mismatch
+	}
+	return n, err
+}
+
+// s3ChunkedReader decodes the aws-chunked encoding payload and verifies the chunk signatures, every
+// chunk is formatted as hex(size);chunk-signature=signature\r\ndata\r\n, and ends with a zero size chunk.
+type s3ChunkedReader struct {
+	reader     *bufio.Reader
+	signingKey []byte
+	cred       *s3Credential
+	prevSig    string
+	chunk      []byte
+	done       bool
+	err        error
+}
+
+func newS3ChunkedReader(reader io.Reader, signingKey []byte, cred *s3Credential, seedSignature string) *s3ChunkedReader {
+	return &s3ChunkedReader{reader: bufio.NewReader(reader), signingKey: signingKey, cred: cred, prevSig: seedSignature}
+}
+
+func (c *s3ChunkedReader) Read(buf []byte) (int, error) {
+	for len(c.chunk) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		if c.done {
+			return 0, io.EOF
+		}
+		c.err = c.readChunk()
+	}
+	n := copy(buf, c.chunk)
+	c.chunk = c.chunk[n:]
+	return n, nil
+}
+
+func (c *s3ChunkedReader) readChunk() error {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return ErrS3IncompleteBody
+	}
+	sizeStr, sigStr, ok := strings.Cut(strings.TrimSuffix(line, "\r\n"), ";chunk-signature=")
+	if !ok {
+		return ErrS3IncompleteBody
+	}
+	size, err := strconv.ParseInt(sizeStr, 16, 64)
+	if err != nil || size < 0 || size > 16*1024*1024 {
+		return ErrS3IncompleteBody
+	}
+	chunk := make([]byte, size+2)
+	if _, err = io.ReadFull(c.reader, chunk); err != nil || !bytes.HasSuffix(chunk, []byte("\r\n")) {
+		return ErrS3IncompleteBody
+	}
+	chunk = chunk[:size]
+
+	stringToSign := strings.Join([]string{
+		s3StreamingPayloadAlgorithm,
+		c.cred.date.Format(s3DateFormat),
+		c.cred.scope(),
+		c.prevSig,
+		s3EmptySHA256,
+		s3SHA256Hex(chunk),
+	}, "\n")
+	signature := hex.EncodeToString(s3HMAC(c.signingKey, []byte(stringToSign)))
+	if !hmac.Equal([]byte(signature), []byte(sigStr)) {
+		return ErrS3SignatureDoesNotMatch
+	}
+	c.prevSig = signature
+	c.chunk = chunk
+	c.done = size == 0
+	return nil
+}
@@ -0,0 +1,663 @@
+package gater
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
+	"github.com/bnb-chain/greenfield-storage-provider/util"
+	"github.com/bnb-chain/greenfield/types/s3util"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	s3ListBucketsRouterName   = "S3ListBuckets"
+	s3HeadBucketRouterName    = "S3HeadBucket"
+	s3GetBucketLocationName   = "S3GetBucketLocation"
+	s3ListObjectsV2RouterName = "S3ListObjectsV2"
+	s3GetObjectRouterName     = "S3GetObject"
+	s3HeadObjectRouterName    = "S3HeadObject"
+	s3PutObjectRouterName     = "S3PutObject"
+	s3NotImplementedName      = "S3NotImplemented"
+)
+
+const (
+	// S3XMLNamespace defines the xml namespace of the s3 api responses
+	S3XMLNamespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+	// S3ListTypeQuery defines the version of the list objects api, only 2 is supported
+	S3ListTypeQuery = "list-type"
+	// S3EncodingTypeQuery defines the encoding type of the object keys in the list objects response
+	S3EncodingTypeQuery = "encoding-type"
+	// S3LocationQuery defines the get bucket location query
+	S3LocationQuery = "location"
+	// S3DefaultMaxKeys defines the default and max number of the keys returned by list objects
+	S3DefaultMaxKeys = 1000
+	// S3StorageClassStandard defines the storage class of all the objects
+	S3StorageClassStandard = "STANDARD"
+	// S3AcceptRangesHeader defines the object supports range requests
+	S3AcceptRangesHeader = "Accept-Ranges"
+	// s3TimeFormat defines the ISO 8601 time format of the s3 api responses
+	s3TimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// s3UnsupportedSubResources are the sub resources of the bucket and object which are not implemented.
+var s3UnsupportedSubResources = []string{"acl", "cors", "delete", "lifecycle", "policy", "tagging",
+	"uploadId", "uploads", "versioning", "versions", "website"}
+
+// s3ErrorCodes maps the gateway errors to the error codes of the s3 api.
+var s3ErrorCodes = map[int32]string{
+	ErrS3AccessDenied.GetInnerCode():                 "AccessDenied",
+	ErrNoPermission.GetInnerCode():                   "AccessDenied",
+	ErrS3InvalidAccessKeyID.GetInnerCode():           "InvalidAccessKeyId",
+	ErrS3SignatureDoesNotMatch.GetInnerCode():        "SignatureDoesNotMatch",
+	ErrS3SignatureVersionNotSupported.GetInnerCode(): "InvalidRequest",
+	ErrS3AuthorizationMalformed.GetInnerCode():       "AuthorizationHeaderMalformed",
+	ErrS3AuthorizationQueryMalformed.GetInnerCode():  "AuthorizationQueryParametersError",
+	ErrS3RequestTimeTooSkewed.GetInnerCode():         "RequestTimeTooSkewed",
+	ErrS3ExpiredPresignRequest.GetInnerCode():        "AccessDenied",
+	ErrS3ContentSHA256Mismatch.GetInnerCode():        "XAmzContentSHA256Mismatch",
+	ErrS3IncompleteBody.GetInnerCode():               "IncompleteBody",
+	ErrS3InvalidRequest.GetInnerCode():               "InvalidRequest",
+	ErrS3NoSuchKey.GetInnerCode():                    "NoSuchKey",
+	ErrNoSuchObject.GetInnerCode():                   "NoSuchKey",
+	ErrS3NoSuchBucket.GetInnerCode():                 "NoSuchBucket",
+	ErrS3InvalidObjectState.GetInnerCode():           "InvalidObjectState",
+	ErrS3NotImplemented.GetInnerCode():               "NotImplemented",
+	ErrS3InvalidRange.GetInnerCode():                 "InvalidRange",
+	ErrRangeNotSatisfiable.GetInnerCode():            "InvalidRange",
+	ErrS3InternalError.GetInnerCode():                "InternalError",
+	ErrTooManyRequests.GetInnerCode():                "SlowDown",
+	ErrPreconditionFailed.GetInnerCode():             "PreconditionFailed",
+	ErrInvalidPayloadSize.GetInnerCode():             "EntityTooLarge",
+	ErrInvalidQuery.GetInnerCode():                   "InvalidArgument",
+}
+
+// registerS3Handler registers the s3 compatible api, the bucket is parsed from the host for the virtual
+// hosted style requests if the s3 domain name is configured, otherwise from the path.
+func (g *GateModular) registerS3Handler(r *mux.Router) {
+	if g.s3DomainName != "" {
+		hostBucketRouter := r.Host("{bucket:.+}." + g.s3DomainName).Subrouter()
+		g.registerS3BucketHandler(hostBucketRouter, "/")
+		g.registerS3ObjectHandler(hostBucketRouter, "/{object:.+}")
+	}
+	r.Path("/").Name(s3ListBucketsRouterName).Methods(http.MethodGet).HandlerFunc(g.s3ListBucketsHandler)
+	for _, path := range []string{"/{bucket:[^/]+}", "/{bucket:[^/]+}/"} {
+		g.registerS3BucketHandler(r, path)
+	}
+	g.registerS3ObjectHandler(r, "/{bucket:[^/]+}/{object:.+}")
+	r.NotFoundHandler = http.HandlerFunc(g.s3NotImplementedHandler)
+	r.MethodNotAllowedHandler = http.HandlerFunc(g.s3NotImplementedHandler)
+	r.Use(localhttp.Limit)
+}
+
+func (g *GateModular) registerS3BucketHandler(r *mux.Router, path string) {
+	for _, sub := range s3UnsupportedSubResources {
+		r.Path(path).Name(s3NotImplementedName).Queries(sub, "").HandlerFunc(g.s3NotImplementedHandler)
+	}
+	r.Path(path).Name(s3GetBucketLocationName).Methods(http.MethodGet).Queries(S3LocationQuery, "").HandlerFunc(g.s3GetBucketLocationHandler)
+	r.Path(path).Name(s3ListObjectsV2RouterName).Methods(http.MethodGet).HandlerFunc(g.s3ListObjectsV2Handler)
+	r.Path(path).Name(s3HeadBucketRouterName).Methods(http.MethodHead).HandlerFunc(g.s3HeadBucketHandler)
+}
+
+func (g *GateModular) registerS3ObjectHandler(r *mux.Router, path string) {
+	for _, sub := range s3UnsupportedSubResources {
+		r.Path(path).Name(s3NotImplementedName).Queries(sub, "").HandlerFunc(g.s3NotImplementedHandler)
+	}
+	r.Path(path).Name(s3GetObjectRouterName).Methods(http.MethodGet).HandlerFunc(g.s3GetObjectHandler)
+	r.Path(path).Name(s3HeadObjectRouterName).Methods(http.MethodHead).HandlerFunc(g.s3GetObjectHandler)
+	r.Path(path).Name(s3PutObjectRouterName).Methods(http.MethodPut).HandlerFunc(g.s3PutObjectHandler)
+}
+
+// newS3RequestContext returns an instance of RequestContext, and verifies the signature v4 of the s3 api,
+// returns the instance regardless of the success or failure of the verification.
+func (g *GateModular) newS3RequestContext(r *http.Request) (*RequestContext, error) {
+	vars := mux.Vars(r)
+	routerName := ""
+	if mux.CurrentRoute(r) != nil {
+		routerName = mux.CurrentRoute(r).GetName()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	reqCtx := &RequestContext{
+		g:          g,
+		ctx:        ctx,
+		cancel:     cancel,
+		request:    r,
+		routerName: routerName,
+		bucketName: vars["bucket"],
+		objectName: vars["object"],
+		vars:       vars,
+		startTime:  time.Now(),
+	}
+	account, err := verifyS3SignatureV4(r, g.s3Region, time.Now(), g.getS3AccessKey)
+	if err != nil {
+		return reqCtx, err
+	}
+	reqCtx.account = account
+	if !localhttp.LimitAccount(r, account) {
+		log.CtxErrorw(reqCtx.Context(), "failed to pass the account rate limiter", "account", account)
+		return reqCtx, ErrTooManyRequests
+	}
+	return reqCtx, nil
+}
+
+// getS3AccessKey returns the secret access key and the greenfield account of the access key id from sp db.
+func (g *GateModular) getS3AccessKey(accessKeyID string) (string, string, error) {
+	key, err := g.baseApp.GfSpDB().GetS3AccessKey(accessKeyID)
+	if err != nil {
+		log.Errorw("failed to get s3 access key", "access_key_id", accessKeyID, "error", err)
+		return "", "", ErrS3InternalError
+	}
+	if key == nil {
+		return "", "", ErrS3InvalidAccessKeyID
+	}
+	return key.SecretAccessKey, key.AccountAddress, nil
+}
+
+// s3RequestDone writes the error response in the s3 xml error format, and records the result of the request.
+func s3RequestDone(w http.ResponseWriter, reqCtx *RequestContext, err error) {
+	reqCtx.Cancel()
+	if err != nil {
+		gfspErr := gfsperrors.MakeGfSpError(err)
+		reqCtx.SetError(gfspErr)
+		reqCtx.SetHttpCode(int(gfspErr.GetHttpStatusCode()))
+		MakeS3ErrorResponse(w, reqCtx.request, gfspErr)
+		metrics.ReqCounter.WithLabelValues(GatewayTotalFailure).Inc()
+		metrics.ReqTime.WithLabelValues(GatewayTotalFailure).Observe(time.Since(reqCtx.startTime).Seconds())
+	} else {
+		if reqCtx.httpCode == 0 {
+			reqCtx.SetHttpCode(http.StatusOK)
+		}
+		metrics.ReqCounter.WithLabelValues(GatewayTotalSuccess).Inc()
+		metrics.ReqTime.WithLabelValues(GatewayTotalSuccess).Observe(time.Since(reqCtx.startTime).Seconds())
+	}
+	log.CtxDebugw(reqCtx.Context(), reqCtx.String())
+}
+
+// MakeS3ErrorResponse writes the error response in the s3 xml error format.
+func MakeS3ErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	gfspErr := gfsperrors.MakeGfSpError(err)
+	code, ok := s3ErrorCodes[gfspErr.GetInnerCode()]
+	if !ok {
+		switch status := gfspErr.GetHttpStatusCode(); {
+		case status == http.StatusNotFound:
+			code = "NoSuchKey"
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			code = "AccessDenied"
+		case status >= http.StatusInternalServerError:
+			code = "InternalError"
+		default:
+			code = "InvalidRequest"
+		}
+	}
+	w.Header().Set(ContentTypeHeader, ContentTypeXMLHeaderValue)
+	w.WriteHeader(int(gfspErr.GetHttpStatusCode()))
+	if r.Method == http.MethodHead {
+		return
+	}
+	var xmlInfo = struct {
+		XMLName   xml.Name `xml:"Error"`
+		Code      string   `xml:"Code"`
+		Message   string   `xml:"Message"`
+		Resource  string   `xml:"Resource"`
+		RequestID string   `xml:"RequestId"`
+	}{
+		Code:      code,
+		Message:   gfspErr.GetDescription(),
+		Resource:  r.URL.Path,
+		RequestID: r.Header.Get(GnfdRequestIDHeader),
+	}
+	xmlBody, err := xml.Marshal(&xmlInfo)
+	if err != nil {
+		log.Errorw("failed to marshal s3 error response", "error", gfspErr.String())
+		return
+	}
+	if _, err = w.Write(append([]byte(xml.Header), xmlBody...)); err != nil {
+		log.Errorw("failed to write s3 error response", "error", gfspErr.String())
+	}
+}
+
+func writeS3XMLResponse(w http.ResponseWriter, v interface{}) error {
+	xmlBody, err := xml.Marshal(v)
+	if err != nil {
+		return ErrEncodeResponse
+	}
+	w.Header().Set(ContentTypeHeader, ContentTypeXMLHeaderValue)
+	if _, err = w.Write(append([]byte(xml.Header), xmlBody...)); err != nil {
+		log.Errorw("failed to write s3 response", "error", err)
+	}
+	return nil
+}
+
+// s3NotImplementedHandler handles the s3 api which is not implemented by the facade.
+func (g *GateModular) s3NotImplementedHandler(w http.ResponseWriter, r *http.Request) {
+	reqCtx := &RequestContext{ctx: context.Background(), cancel: func() {}, request: r, startTime: time.Now()}
+	s3RequestDone(w, reqCtx, ErrS3NotImplemented)
+}
+
+// s3ListBucketsHandler handles the s3 list buckets request, it returns the buckets owned by the account.
+func (g *GateModular) s3ListBucketsHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		reqCtx *RequestContext
+	)
+	defer func() { s3RequestDone(w, reqCtx, err) }()
+
+	if reqCtx, err = g.newS3RequestContext(r); err != nil {
+		return
+	}
+	buckets, err := g.baseApp.GfSpClient().GetUserBuckets(reqCtx.Context(), reqCtx.Account(), false)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get user buckets", "error", err)
+		return
+	}
+
+	type s3Bucket struct {
+		Name         string `xml:"Name"`
+		CreationDate string `xml:"CreationDate"`
+	}
+	var result = struct {
+		XMLName xml.Name   `xml:"ListAllMyBucketsResult"`
+		Xmlns   string     `xml:"xmlns,attr"`
+		OwnerID string     `xml:"Owner>ID"`
+		Owner   string     `xml:"Owner>DisplayName"`
+		Buckets []s3Bucket `xml:"Buckets>Bucket"`
+	}{
+		Xmlns:   S3XMLNamespace,
+		OwnerID: reqCtx.Account(),
+		Owner:   reqCtx.Account(),
+	}
+	for _, bucket := range buckets {
+		if bucket.GetRemoved() || bucket.GetBucketInfo() == nil {
+			continue
+		}
+		result.Buckets = append(result.Buckets, s3Bucket{
+			Name:         bucket.GetBucketInfo().GetBucketName(),
+			CreationDate: time.Unix(bucket.GetBucketInfo().GetCreateAt(), 0).UTC().Format(s3TimeFormat),
+		})
+	}
+	err = writeS3XMLResponse(w, &result)
+}
+
+// s3HeadBucketHandler handles the s3 head bucket request.
+func (g *GateModular) s3HeadBucketHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		reqCtx *RequestContext
+	)
+	defer func() { s3RequestDone(w, reqCtx, err) }()
+
+	if reqCtx, err = g.newS3RequestContext(r); err != nil {
+		return
+	}
+	if _, err = g.queryS3BucketInfo(reqCtx); err != nil {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// s3GetBucketLocationHandler handles the s3 get bucket location request, it returns the configured region.
+func (g *GateModular) s3GetBucketLocationHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err    error
+		reqCtx *RequestContext
+	)
+	defer func() { s3RequestDone(w, reqCtx, err) }()
+
+	if reqCtx, err = g.newS3RequestContext(r); err != nil {
+		return
+	}
+	if _, err = g.queryS3BucketInfo(reqCtx); err != nil {
+		return
+	}
+	var result = struct {
+		XMLName  xml.Name `xml:"LocationConstraint"`
+		Xmlns    string   `xml:"xmlns,attr"`
+		Location string   `xml:",chardata"`
+	}{
+		Xmlns:    S3XMLNamespace,
+		Location: g.s3Region,
+	}
+	err = writeS3XMLResponse(w, &result)
+}
+
+func (g *GateModular) queryS3BucketInfo(reqCtx *RequestContext) (*storagetypes.BucketInfo, error) {
+	bucketInfo, err := g.baseApp.Consensus().QueryBucketInfo(reqCtx.Context(), reqCtx.bucketName)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket info from consensus", "error", err)
+		if strings.Contains(err.Error(), "No such bucket") {
+			return nil, ErrS3NoSuchBucket
+		}
+		return nil, ErrConsensus
+	}
+	return bucketInfo, nil
+}
+
+func (g *GateModular) queryS3BucketAndObjectInfo(reqCtx *RequestContext) (
+	*storagetypes.BucketInfo, *storagetypes.ObjectInfo, error) {
+	bucketInfo, objectInfo, err := g.baseApp.Consensus().QueryBucketInfoAndObjectInfo(reqCtx.Context(),
+		reqCtx.bucketName, reqCtx.objectName)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get bucket and object info from consensus", "error", err)
+		if strings.Contains(err.Error(), "No such bucket") {
+			return nil, nil, ErrS3NoSuchBucket
+		}
+		if strings.Contains(err.Error(), "No such object") {
+			return nil, nil, ErrS3NoSuchKey
+		}
+		return nil, nil, ErrConsensus
+	}
+	return bucketInfo, objectInfo, nil
+}
+
+// s3ListObjectsV2Handler handles the s3 list objects v2 request, only the sealed objects are listed.
+func (g *GateModular) s3ListObjectsV2Handler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err               error
+		reqCtx            *RequestContext
+		maxKeys           uint64 = S3DefaultMaxKeys
+		continuationToken string
+	)
+	defer func() { s3RequestDone(w, reqCtx, err) }()
+
+	if reqCtx, err = g.newS3RequestContext(r); err != nil {
+		return
+	}
+	query := r.URL.Query()
+	if query.Get(S3ListTypeQuery) != "2" {
+		err = ErrS3NotImplemented
+		return
+	}
+	prefix := query.Get(ListObjectsPrefixQuery)
+	delimiter := query.Get(ListObjectsDelimiterQuery)
+	startAfter := query.Get(ListObjectsStartAfterQuery)
+	requestContinuationToken := query.Get(ListObjectsContinuationTokenQuery)
+	encodingType := query.Get(S3EncodingTypeQuery)
+	if delimiter != "" && delimiter != "/" {
+		err = ErrS3NotImplemented
+		return
+	}
+	if encodingType != "" && encodingType != "url" {
+		err = ErrInvalidQuery
+		return
+	}
+	if err = s3util.CheckValidBucketName(reqCtx.bucketName); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to check bucket name", "bucket_name", reqCtx.bucketName, "error", err)
+		err = ErrS3NoSuchBucket
+		return
+	}
+	if query.Has(ListObjectsMaxKeysQuery) {
+		if maxKeys, err = strconv.ParseUint(query.Get(ListObjectsMaxKeysQuery), 10, 64); err != nil {
+			err = ErrInvalidQuery
+			return
+		}
+		if maxKeys > S3DefaultMaxKeys {
+			maxKeys = S3DefaultMaxKeys
+		}
+	}
+	if requestContinuationToken != "" {
+		decoded, decodeErr := base64.StdEncoding.DecodeString(requestContinuationToken)
+		if decodeErr != nil || !strings.HasPrefix(string(decoded), prefix) {
+			log.CtxErrorw(reqCtx.Context(), "failed to check continuation token", "continuation_token", requestContinuationToken)
+			err = ErrInvalidQuery
+			return
+		}
+		continuationToken = string(decoded)
+	} else {
+		continuationToken = startAfter
+	}
+	if _, err = g.queryS3BucketInfo(reqCtx); err != nil {
+		return
+	}
+
+	type s3Object struct {
+		Key          string `xml:"Key"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         uint64 `xml:"Size"`
+		StorageClass string `xml:"StorageClass"`
+	}
+	type s3CommonPrefix struct {
+		Prefix string `xml:"Prefix"`
+	}
+	encode := func(s string) string {
+		if encodingType == "url" {
+			return url.QueryEscape(s)
+		}
+		return s
+	}
+	var result = struct {
+		XMLName               xml.Name         `xml:"ListBucketResult"`
+		Xmlns                 string           `xml:"xmlns,attr"`
+		Name                  string           `xml:"Name"`
+		Prefix                string           `xml:"Prefix"`
+		Delimiter             string           `xml:"Delimiter,omitempty"`
+		StartAfter            string           `xml:"StartAfter,omitempty"`
+		ContinuationToken     string           `xml:"ContinuationToken,omitempty"`
+		NextContinuationToken string           `xml:"NextContinuationToken,omitempty"`
+		EncodingType          string           `xml:"EncodingType,omitempty"`
+		MaxKeys               uint64           `xml:"MaxKeys"`
+		KeyCount              int              `xml:"KeyCount"`
+		IsTruncated           bool             `xml:"IsTruncated"`
+		Contents              []s3Object       `xml:"Contents"`
+		CommonPrefixes        []s3CommonPrefix `xml:"CommonPrefixes"`
+	}{
+		Xmlns:             S3XMLNamespace,
+		Name:              reqCtx.bucketName,
+		Prefix:            encode(prefix),
+		Delimiter:         encode(delimiter),
+		StartAfter:        encode(startAfter),
+		ContinuationToken: requestContinuationToken,
+		EncodingType:      encodingType,
+		MaxKeys:           maxKeys,
+	}
+	if maxKeys == 0 {
+		err = writeS3XMLResponse(w, &result)
+		return
+	}
+
+	objects, _, _, isTruncated, nextContinuationToken, _, _, _, commonPrefixes, _, err :=
+		g.baseApp.GfSpClient().ListObjectsByBucketName(reqCtx.Context(), reqCtx.bucketName, "", maxKeys,
+			startAfter, continuationToken, delimiter, prefix, false)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to list objects by bucket name", "error", err)
+		return
+	}
+	for _, object := range objects {
+		objectInfo := object.GetObjectInfo()
+		if object.GetRemoved() || objectInfo == nil || objectInfo.GetObjectStatus() != storagetypes.OBJECT_STATUS_SEALED {
+			continue
+		}
+		result.Contents = append(result.Contents, s3Object{
+			Key:          encode(objectInfo.GetObjectName()),
+			LastModified: time.Unix(objectInfo.GetCreateAt(), 0).UTC().Format(s3TimeFormat),
+			ETag:         objectETag(objectInfo),
+			Size:         objectInfo.GetPayloadSize(),
+			StorageClass: S3StorageClassStandard,
+		})
+	}
+	for _, commonPrefix := range commonPrefixes {
+		result.CommonPrefixes = append(result.CommonPrefixes, s3CommonPrefix{Prefix: encode(commonPrefix)})
+	}
+	result.IsTruncated = isTruncated
+	if isTruncated {
+		result.NextContinuationToken = nextContinuationToken
+	}
+	result.KeyCount = len(result.Contents) + len(result.CommonPrefixes)
+	err = writeS3XMLResponse(w, &result)
+}
+
+// s3GetObjectHandler handles the s3 get object and head object request.
+func (g *GateModular) s3GetObjectHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err           error
+		reqCtx        *RequestContext
+		authenticated bool
+		writtenSize   int64
+	)
+	defer func() {
+		if err != nil && writtenSize > 0 {
+			// the object data has been partially sent, the response can only be aborted
+			log.CtxErrorw(reqCtx.Context(), "failed to write object data", "written_size", writtenSize, "error", err)
+			err = nil
+		}
+		s3RequestDone(w, reqCtx, err)
+	}()
+
+	if reqCtx, err = g.newS3RequestContext(r); err != nil {
+		return
+	}
+	if authenticated, err = g.baseApp.GfSpClient().VerifyAuthentication(reqCtx.Context(),
+		coremodule.AuthOpTypeGetObject, reqCtx.Account(), reqCtx.bucketName, reqCtx.objectName); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to verify authentication", "error", err)
+		return
+	}
+	if !authenticated {
+		err = ErrNoPermission
+		return
+	}
+	bucketInfo, objectInfo, err := g.queryS3BucketAndObjectInfo(reqCtx)
+	if err != nil {
+		return
+	}
+	if objectInfo.GetObjectStatus() != storagetypes.OBJECT_STATUS_SEALED {
+		log.CtxErrorw(reqCtx.Context(), "failed to get object due to object is not sealed", "status", objectInfo.GetObjectStatus())
+		err = ErrS3NoSuchKey
+		return
+	}
+
+	notModified, err := checkObjectPreconditions(r, objectInfo)
+	if err != nil {
+		return
+	}
+	if notModified {
+		setObjectCacheHeaders(w, objectInfo)
+		reqCtx.SetHttpCode(http.StatusNotModified)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	size := int64(objectInfo.GetPayloadSize())
+	lowOffset, highOffset := int64(0), size-1
+	// the malformed range is ignored, and the multiple ranges are not supported by s3, the whole object is returned
+	isRange, ranges, rangeErr := parseRange(r.Header.Get(RangeHeader), size)
+	if rangeErr == ErrRangeNotSatisfiable {
+		err = ErrS3InvalidRange
+		return
+	}
+	isRange = isRange && rangeErr == nil && len(ranges) == 1
+	if isRange {
+		lowOffset, highOffset = ranges[0].start, ranges[0].end
+	}
+
+	w.Header().Set(ContentTypeHeader, objectInfo.GetContentType())
+	setObjectCacheHeaders(w, objectInfo)
+	w.Header().Set(S3AcceptRangesHeader, "bytes")
+	w.Header().Set(ContentLengthHeader, strconv.FormatInt(highOffset-lowOffset+1, 10))
+	if isRange {
+		w.Header().Set(ContentRangeHeader, ranges[0].contentRange(size))
+		reqCtx.SetHttpCode(http.StatusPartialContent)
+	} else {
+		reqCtx.SetHttpCode(http.StatusOK)
+	}
+	if r.Method == http.MethodHead || size == 0 {
+		w.WriteHeader(reqCtx.httpCode)
+		return
+	}
+
+	params, err := g.baseApp.Consensus().QueryStorageParamsByTimestamp(reqCtx.Context(), objectInfo.GetCreateAt())
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get storage params from consensus", "error", err)
+		err = ErrConsensus
+		return
+	}
+	task := &gfsptask.GfSpDownloadObjectTask{}
+	task.InitDownloadObjectTask(objectInfo, bucketInfo, params, g.baseApp.TaskPriority(task), reqCtx.Account(),
+		lowOffset, highOffset, g.baseApp.TaskTimeout(task, uint64(highOffset-lowOffset+1)), g.baseApp.TaskMaxRetry(task))
+	reader, err := g.baseApp.GfSpClient().GetObjectStream(reqCtx.Context(), task)
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to download object", "error", err)
+		return
+	}
+	defer reader.Close()
+	w.WriteHeader(reqCtx.httpCode)
+	writtenSize, err = io.Copy(w, reader)
+	if err == nil {
+		metrics.ReqPieceSize.WithLabelValues(GatewayGetObjectSize).Observe(float64(writtenSize))
+	}
+}
+
+// s3PutObjectHandler handles the s3 put object request, the object must be created on chain beforehand.
+func (g *GateModular) s3PutObjectHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		err           error
+		reqCtx        *RequestContext
+		authenticated bool
+	)
+	defer func() { s3RequestDone(w, reqCtx, err) }()
+
+	if reqCtx, err = g.newS3RequestContext(r); err != nil {
+		return
+	}
+	if authenticated, err = g.baseApp.GfSpClient().VerifyAuthentication(reqCtx.Context(),
+		coremodule.AuthOpTypePutObject, reqCtx.Account(), reqCtx.bucketName, reqCtx.objectName); err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to verify authentication", "error", err)
+		return
+	}
+	if !authenticated {
+		err = ErrNoPermission
+		return
+	}
+	bucketInfo, objectInfo, err := g.queryS3BucketAndObjectInfo(reqCtx)
+	if err != nil {
+		return
+	}
+	if objectInfo.GetObjectStatus() != storagetypes.OBJECT_STATUS_CREATED {
+		log.CtxErrorw(reqCtx.Context(), "failed to put object due to object is not created", "status", objectInfo.GetObjectStatus())
+		err = ErrS3InvalidObjectState
+		return
+	}
+	if objectInfo.GetPayloadSize() == 0 || objectInfo.GetPayloadSize() > g.maxPayloadSize {
+		err = ErrInvalidPayloadSize
+		return
+	}
+	if r.ContentLength >= 0 && uint64(r.ContentLength) != objectInfo.GetPayloadSize() {
+		log.CtxErrorw(reqCtx.Context(), "failed to put object due to content length mismatch",
+			"content_length", r.ContentLength, "payload_size", objectInfo.GetPayloadSize())
+		err = ErrS3IncompleteBody
+		return
+	}
+	params, err := g.baseApp.Consensus().QueryStorageParamsByTimestamp(reqCtx.Context(), objectInfo.GetCreateAt())
+	if err != nil {
+		log.CtxErrorw(reqCtx.Context(), "failed to get storage params from consensus", "error", err)
+		err = ErrConsensus
+		return
+	}
+
+	task := &gfsptask.GfSpUploadObjectTask{}
+	task.InitUploadObjectTask(bucketInfo.GetGlobalVirtualGroupFamilyId(), objectInfo, params,
+		g.baseApp.TaskTimeout(task, objectInfo.GetPayloadSize()))
+	task.SetCreateTime(reqCtx.startTime.Unix())
+	task.AppendLog("gateway-create-s3-upload-task")
+	ctx := log.WithValue(reqCtx.Context(), log.CtxKeyTask, task.Key().String())
+	if err = g.baseApp.GfSpClient().UploadObject(ctx, task, r.Body); err != nil {
+		log.CtxErrorw(ctx, "failed to upload payload data", "error", err)
+		return
+	}
+	w.Header().Set(ETagHeader, objectETag(objectInfo))
+	w.WriteHeader(http.StatusOK)
+	log.CtxDebugw(ctx, "succeed to upload payload data by s3 api", "size", util.Uint64ToString(objectInfo.GetPayloadSize()))
+}
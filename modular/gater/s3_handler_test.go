@@ -0,0 +1,325 @@
+package gater
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/core/consensus"
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	metadatatypes "github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	localhttp "github.com/bnb-chain/greenfield-storage-provider/pkg/middleware/http"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	s3TestBucket  = "s3-bucket"
+	s3TestObject  = "dir/s3-object"
+	s3TestPayload = "0123456789"
+)
+
+// s3TestConsensus serves the bucket s3-bucket with the sealed object dir/s3-object and the created
+// object dir/created.
+type s3TestConsensus struct {
+	consensus.Consensus
+}
+
+func (c *s3TestConsensus) QueryBucketInfo(_ context.Context, bucket string) (*storagetypes.BucketInfo, error) {
+	if bucket != s3TestBucket {
+		return nil, errors.New("No such bucket")
+	}
+	return &storagetypes.BucketInfo{BucketName: bucket, Id: sdkmath.NewUint(1)}, nil
+}
+
+func (c *s3TestConsensus) QueryBucketInfoAndObjectInfo(ctx context.Context, bucket, object string) (
+	*storagetypes.BucketInfo, *storagetypes.ObjectInfo, error) {
+	bucketInfo, err := c.QueryBucketInfo(ctx, bucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	objectInfo := &storagetypes.ObjectInfo{BucketName: bucket, ObjectName: object, Id: sdkmath.NewUint(2),
+		PayloadSize: uint64(len(s3TestPayload)), ContentType: "text/plain", Checksums: [][]byte{{0xab, 0xcd}}}
+	switch object {
+	case s3TestObject:
+		objectInfo.ObjectStatus = storagetypes.OBJECT_STATUS_SEALED
+	case "dir/created":
+		objectInfo.ObjectStatus = storagetypes.OBJECT_STATUS_CREATED
+	default:
+		return nil, nil, errors.New("No such object")
+	}
+	return bucketInfo, objectInfo, nil
+}
+
+func (c *s3TestConsensus) QueryStorageParamsByTimestamp(context.Context, int64) (*storagetypes.Params, error) {
+	return &storagetypes.Params{}, nil
+}
+
+// s3TestBackend allows all the authentications, serves the object data of the download tasks, records the
+// uploaded payload and lists the objects.
+type s3TestBackend struct {
+	gfspserver.UnimplementedGfSpAuthenticationServiceServer
+	gfspserver.UnimplementedGfSpUploadServiceServer
+	gfspserver.UnimplementedGfSpDownloadServiceServer
+	metadatatypes.UnimplementedGfSpMetadataServiceServer
+
+	mux      sync.Mutex
+	uploaded []byte
+}
+
+func (b *s3TestBackend) GfSpVerifyAuthentication(context.Context, *gfspserver.GfSpAuthenticationRequest) (
+	*gfspserver.GfSpAuthenticationResponse, error) {
+	return &gfspserver.GfSpAuthenticationResponse{Allowed: true}, nil
+}
+
+func (b *s3TestBackend) GfSpUploadObject(stream gfspserver.GfSpUploadService_GfSpUploadObjectServer) error {
+	var payload []byte
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		payload = append(payload, req.GetPayload()...)
+	}
+	b.mux.Lock()
+	b.uploaded = payload
+	b.mux.Unlock()
+	return stream.SendAndClose(&gfspserver.GfSpUploadObjectResponse{})
+}
+
+func (b *s3TestBackend) GfSpDownloadObjectStream(req *gfspserver.GfSpDownloadObjectRequest,
+	stream gfspserver.GfSpDownloadService_GfSpDownloadObjectStreamServer) error {
+	task := req.GetDownloadObjectTask()
+	return stream.Send(&gfspserver.GfSpDownloadObjectResponse{
+		Data: []byte(s3TestPayload[task.GetLow() : task.GetHigh()+1])})
+}
+
+func (b *s3TestBackend) GfSpListObjectsByBucketName(_ context.Context, req *metadatatypes.GfSpListObjectsByBucketNameRequest) (
+	*metadatatypes.GfSpListObjectsByBucketNameResponse, error) {
+	newObject := func(name string, status storagetypes.ObjectStatus) *metadatatypes.Object {
+		return &metadatatypes.Object{ObjectInfo: &storagetypes.ObjectInfo{ObjectName: name, ObjectStatus: status,
+			PayloadSize: 10, Checksums: [][]byte{{0xab, 0xcd}}}}
+	}
+	return &metadatatypes.GfSpListObjectsByBucketNameResponse{
+		Objects: []*metadatatypes.Object{
+			newObject("dir/a b", storagetypes.OBJECT_STATUS_SEALED),
+			newObject("dir/created", storagetypes.OBJECT_STATUS_CREATED),
+		},
+		CommonPrefixes:        []string{"dir/sub/"},
+		IsTruncated:           true,
+		NextContinuationToken: "next",
+	}, nil
+}
+
+func (b *s3TestBackend) uploadedPayload() []byte {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.uploaded
+}
+
+// setupS3HandlerTest returns the s3 router of the gateway which is backed by the test backend, the access
+// key of the aws documentation examples is mapped to the test account.
+func setupS3HandlerTest(t *testing.T) (*mux.Router, *s3TestBackend) {
+	backend := &s3TestBackend{}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	gfspserver.RegisterGfSpAuthenticationServiceServer(grpcServer, backend)
+	gfspserver.RegisterGfSpUploadServiceServer(grpcServer, backend)
+	gfspserver.RegisterGfSpDownloadServiceServer(grpcServer, backend)
+	metadatatypes.RegisterGfSpMetadataServiceServer(grpcServer, backend)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	db.EXPECT().GetS3AccessKey(gomock.Any()).DoAndReturn(func(accessKeyID string) (*corespdb.S3AccessKey, error) {
+		if accessKeyID != s3TestAccessKeyID {
+			return nil, nil
+		}
+		return &corespdb.S3AccessKey{AccessKeyID: accessKeyID, SecretAccessKey: s3TestSecretAccessKey,
+			AccountAddress: s3TestAccount}, nil
+	}).AnyTimes()
+	baseApp, err := gfspapp.NewGfSpBaseApp(&gfspconfig.GfSpConfig{GRPCAddress: listener.Addr().String()},
+		gfspconfig.CustomizeGfSpDB(db), gfspconfig.CustomizeConsensus(&s3TestConsensus{}))
+	require.NoError(t, err)
+	require.NoError(t, localhttp.NewAPILimiter(&localhttp.APILimiterConfig{}))
+
+	g := &GateModular{baseApp: baseApp, s3Region: DefaultS3Region, maxPayloadSize: 1024}
+	router := mux.NewRouter().SkipClean(true)
+	g.registerS3Handler(router)
+	return router, backend
+}
+
+// newS3TestRequest returns the request signed by the signature v4 in the Authorization header, the payload
+// hash is the sha256 of the body if it is empty.
+func newS3TestRequest(method, target, body, payloadHash string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	now := time.Now().UTC()
+	if payloadHash == "" {
+		payloadHash = s3SHA256Hex([]byte(body))
+	}
+	req.Header.Set(S3AmzContentSHA256Header, payloadHash)
+	req.Header.Set(S3AmzDateHeader, now.Format(s3DateFormat))
+	cred := &s3Credential{accessKeyID: s3TestAccessKeyID, scopeDate: now.Format(s3ScopeDateFormat),
+		region: DefaultS3Region, date: now, payloadHash: payloadHash,
+		signedHeaders: []string{"host", "x-amz-content-sha256", "x-amz-date"}}
+	signingKey := s3SigningKey(s3TestSecretAccessKey, cred.scopeDate, cred.region)
+	signature := fmt.Sprintf("%x", s3HMAC(signingKey, []byte(s3StringToSign(cred, s3CanonicalRequest(req, cred)))))
+	req.Header.Set(GnfdAuthorizationHeader, fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		s3TestAccessKeyID, cred.scope(), strings.Join(cred.signedHeaders, ";"), signature))
+	return req
+}
+
+// s3TestErrorCode returns the code of the s3 error response.
+func s3TestErrorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	var resp struct {
+		Code string `xml:"Code"`
+	}
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Code
+}
+
+func TestS3Handler_Errors(t *testing.T) {
+	router, backend := setupS3HandlerTest(t)
+	objectURL := "http://s3.test/" + s3TestBucket + "/" + s3TestObject
+	unknownKey := newS3TestRequest(http.MethodGet, objectURL, "", "")
+	unknownKey.Header.Set(GnfdAuthorizationHeader, strings.Replace(unknownKey.Header.Get(GnfdAuthorizationHeader),
+		s3TestAccessKeyID, "AKIDUNKNOWN", 1))
+	testCases := []struct {
+		name       string
+		req        *http.Request
+		wantedCode int
+		wantedErr  string
+	}{
+		{"anonymous", httptest.NewRequest(http.MethodGet, objectURL, nil), http.StatusForbidden, "AccessDenied"},
+		{"unknown access key", unknownKey, http.StatusForbidden, "InvalidAccessKeyId"},
+		{"bad signature", func() *http.Request {
+			req := newS3TestRequest(http.MethodGet, objectURL, "", "")
+			req.Header.Set(S3AmzDateHeader, time.Now().UTC().Add(time.Second).Format(s3DateFormat))
+			return req
+		}(), http.StatusForbidden, "SignatureDoesNotMatch"},
+		{"unsupported sub resource", newS3TestRequest(http.MethodGet, objectURL+"?acl", "", ""),
+			http.StatusNotImplemented, "NotImplemented"},
+		{"unsupported method", newS3TestRequest(http.MethodPost, objectURL, "", ""),
+			http.StatusNotImplemented, "NotImplemented"},
+		{"trailer payload", newS3TestRequest(http.MethodPut, "http://s3.test/"+s3TestBucket+"/dir/created",
+			s3TestPayload, "STREAMING-UNSIGNED-PAYLOAD-TRAILER"), http.StatusNotImplemented, "NotImplemented"},
+		{"invalid payload hash", newS3TestRequest(http.MethodPut, "http://s3.test/"+s3TestBucket+"/dir/created",
+			s3TestPayload, "UNKNOWN"), http.StatusBadRequest, "InvalidRequest"},
+		{"no such bucket", newS3TestRequest(http.MethodGet, "http://s3.test/other-bucket?list-type=2", "", ""),
+			http.StatusNotFound, "NoSuchBucket"},
+		{"no such key", newS3TestRequest(http.MethodGet, "http://s3.test/"+s3TestBucket+"/dir/other", "", ""),
+			http.StatusNotFound, "NoSuchKey"},
+		{"list objects v1", newS3TestRequest(http.MethodGet, "http://s3.test/"+s3TestBucket, "", ""),
+			http.StatusNotImplemented, "NotImplemented"},
+		{"invalid max keys", newS3TestRequest(http.MethodGet, "http://s3.test/"+s3TestBucket+"?list-type=2&max-keys=x",
+			"", ""), http.StatusBadRequest, "InvalidArgument"},
+		{"get created object", newS3TestRequest(http.MethodGet, "http://s3.test/"+s3TestBucket+"/dir/created", "", ""),
+			http.StatusNotFound, "NoSuchKey"},
+		{"put sealed object", newS3TestRequest(http.MethodPut, objectURL, s3TestPayload, ""),
+			http.StatusForbidden, "InvalidObjectState"},
+		{"put incomplete body", newS3TestRequest(http.MethodPut, "http://s3.test/"+s3TestBucket+"/dir/created",
+			s3TestPayload[:5], ""), http.StatusBadRequest, "IncompleteBody"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, tt.req)
+			assert.Equal(t, tt.wantedCode, w.Code)
+			assert.Equal(t, tt.wantedErr, s3TestErrorCode(t, w))
+		})
+	}
+	assert.Nil(t, backend.uploadedPayload())
+}
+
+func TestS3Handler_GetObject(t *testing.T) {
+	router, _ := setupS3HandlerTest(t)
+	objectURL := "http://s3.test/" + s3TestBucket + "/" + s3TestObject
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newS3TestRequest(http.MethodGet, objectURL, "", ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, s3TestPayload, w.Body.String())
+	assert.Equal(t, `"abcd"`, w.Header().Get(ETagHeader))
+	assert.Equal(t, "text/plain", w.Header().Get(ContentTypeHeader))
+
+	req := newS3TestRequest(http.MethodGet, objectURL, "", "")
+	req.Header.Set(RangeHeader, "bytes=2-5")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "2345", w.Body.String())
+	assert.Equal(t, "bytes 2-5/10", w.Header().Get(ContentRangeHeader))
+
+	req = newS3TestRequest(http.MethodGet, objectURL, "", "")
+	req.Header.Set(RangeHeader, "bytes=20-")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	assert.Equal(t, "InvalidRange", s3TestErrorCode(t, w))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, newS3TestRequest(http.MethodHead, objectURL, "", ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "10", w.Header().Get(ContentLengthHeader))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestS3Handler_PutObject(t *testing.T) {
+	router, backend := setupS3HandlerTest(t)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newS3TestRequest(http.MethodPut, "http://s3.test/"+s3TestBucket+"/dir/created", s3TestPayload, ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"abcd"`, w.Header().Get(ETagHeader))
+	assert.Equal(t, []byte(s3TestPayload), backend.uploadedPayload())
+}
+
+func TestS3Handler_ListObjectsV2(t *testing.T) {
+	router, _ := setupS3HandlerTest(t)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, newS3TestRequest(http.MethodGet,
+		"http://s3.test/"+s3TestBucket+"?list-type=2&prefix=dir%2F&delimiter=%2F&encoding-type=url", "", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	var result struct {
+		Name                  string   `xml:"Name"`
+		Prefix                string   `xml:"Prefix"`
+		KeyCount              int      `xml:"KeyCount"`
+		IsTruncated           bool     `xml:"IsTruncated"`
+		NextContinuationToken string   `xml:"NextContinuationToken"`
+		Keys                  []string `xml:"Contents>Key"`
+		CommonPrefixes        []string `xml:"CommonPrefixes>Prefix"`
+	}
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, s3TestBucket, result.Name)
+	assert.Equal(t, "dir%2F", result.Prefix)
+	// only the sealed objects are listed
+	assert.Equal(t, []string{"dir%2Fa+b"}, result.Keys)
+	assert.Equal(t, []string{"dir%2Fsub%2F"}, result.CommonPrefixes)
+	assert.Equal(t, 2, result.KeyCount)
+	assert.True(t, result.IsTruncated)
+	assert.Equal(t, "next", result.NextContinuationToken)
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte(xml.Header)))
+}
@@ -401,7 +401,7 @@ func calculateStakingSizeStrategy(manager *ManageModular) (denom string, amount
 		return "", sdkmath.ZeroInt(), err
 	}
 
-	gvgMeta, err := manager.virtualGroupManager.GenerateGlobalVirtualGroupMeta(NewGenerateGVGSecondarySPsPolicyByPrefer(params, manager.gvgPreferSPList))
+	gvgMeta, err := manager.virtualGroupManager.GenerateGlobalVirtualGroupMeta(NewGenerateGVGSecondarySPsPolicyByPrefer(params, manager.gvgPreferSPList, manager.isSPHealthy))
 	if err != nil {
 		return "", sdkmath.ZeroInt(), err
 	}
@@ -0,0 +1,319 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	// EventIDHeader defines the header of the notification id, the receivers can deduplicate the notifications
+	// by it since a notification may be delivered more than once.
+	EventIDHeader = "X-Gnfd-Event-Id"
+	// EventTypeHeader defines the header of the object event type.
+	EventTypeHeader = "X-Gnfd-Event-Type"
+	// EventTimestampHeader defines the header of the unix timestamp when the notification is signed.
+	EventTimestampHeader = "X-Gnfd-Event-Timestamp"
+	// EventSignatureHeader defines the header of the notification signature, it is the hex encoded
+	// hmac-sha256 of "{timestamp}.{body}" by the secret of the subscription.
+	EventSignatureHeader = "X-Gnfd-Event-Signature"
+
+	// eventDeliveryBatchSize defines the max number of the notifications delivered in a round
+	eventDeliveryBatchSize = 100
+	// eventDeliveryTimeout defines the timeout of posting a notification
+	eventDeliveryTimeout = 10 * time.Second
+	// eventDeliveryBaseBackoff defines the backoff after the first failed attempt, it is doubled by every attempt
+	eventDeliveryBaseBackoff = 5 * time.Second
+)
+
+var (
+	// ErrEventEndpointNotPublic is returned if the webhook endpoint is resolved to a non-public address.
+	ErrEventEndpointNotPublic = errors.New("event endpoint is not a public address")
+	// ErrEventRedirect is returned if the webhook endpoint redirects the notification.
+	ErrEventRedirect = errors.New("event endpoint redirect is not allowed")
+)
+
+// nonPublicEventNets are the blocks of the IANA IPv4 and IPv6 special-purpose address registries that are not
+// globally reachable, besides the loopback, private, link-local and multicast blocks checked by net.IP. The
+// IPv6 blocks that embed or translate to the IPv4 addresses are refused as well, since the embedded address
+// can be internal.
+var nonPublicEventNets = mustParseEventCIDRs(
+	"0.0.0.0/8",       // this network
+	"100.64.0.0/10",   // shared address space
+	"192.0.0.0/24",    // ietf protocol assignments
+	"192.0.2.0/24",    // documentation (test-net-1)
+	"192.88.99.0/24",  // deprecated 6to4 relay anycast
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // documentation (test-net-2)
+	"203.0.113.0/24",  // documentation (test-net-3)
+	"240.0.0.0/4",     // reserved and limited broadcast
+	"64:ff9b::/96",    // ipv4-ipv6 translation
+	"64:ff9b:1::/48",  // local-use ipv4-ipv6 translation
+	"100::/64",        // discard-only
+	"2001::/23",       // ietf protocol assignments
+	"2001:db8::/32",   // documentation
+	"2002::/16",       // 6to4
+	"3fff::/20",       // documentation
+	"5f00::/16",       // segment routing sids
+)
+
+func mustParseEventCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
+
+// IsPublicEventIP returns whether the webhook endpoint can be notified at the ip, the addresses that are not
+// globally reachable are refused to keep the webhooks off the internal network.
+func IsPublicEventIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, ipNet := range nonPublicEventNets {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// newEventClient returns the http client of delivering the notifications, the address is checked after the
+// endpoint is resolved, so the endpoint can not reach the internal network by the dns, and the redirects are
+// refused since they are not checked before following.
+func newEventClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: eventDeliveryTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublicEventIP(ip) {
+				return fmt.Errorf("%w: %s", ErrEventEndpointNotPublic, host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: eventDeliveryTimeout,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return ErrEventRedirect
+		},
+	}
+}
+
+// ObjectEvent is the json body of the object event notification.
+type ObjectEvent struct {
+	EventType      spdb.ObjectEventType `json:"event_type"`
+	EventTime      int64                `json:"event_time"`
+	SubscriptionID string               `json:"subscription_id"`
+	BucketName     string               `json:"bucket_name"`
+	ObjectName     string               `json:"object_name"`
+	ObjectID       string               `json:"object_id"`
+	Owner          string               `json:"owner"`
+	PayloadSize    uint64               `json:"payload_size"`
+	ContentType    string               `json:"content_type"`
+}
+
+// NotifyObjectEvent records the notifications of the object event for the subscriptions of the bucket, the
+// notifications are delivered by the manager asynchronously.
+func NotifyObjectEvent(db spdb.EventNotificationDB, eventType spdb.ObjectEventType, objectInfo *storagetypes.ObjectInfo) error {
+	if objectInfo == nil {
+		return nil
+	}
+	subscriptions, err := db.GetBucketEventSubscriptions(objectInfo.GetBucketName())
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+	now := time.Now().Unix()
+	var deliveries []*spdb.EventDelivery
+	for _, subscription := range subscriptions {
+		if !subscribesEvent(subscription, eventType) {
+			continue
+		}
+		payload, err := json.Marshal(&ObjectEvent{
+			EventType:      eventType,
+			EventTime:      now,
+			SubscriptionID: subscription.SubscriptionID,
+			BucketName:     objectInfo.GetBucketName(),
+			ObjectName:     objectInfo.GetObjectName(),
+			ObjectID:       objectInfo.Id.String(),
+			Owner:          objectInfo.GetOwner(),
+			PayloadSize:    objectInfo.GetPayloadSize(),
+			ContentType:    objectInfo.GetContentType(),
+		})
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, &spdb.EventDelivery{
+			BucketName:                 subscription.BucketName,
+			SubscriptionID:             subscription.SubscriptionID,
+			EventType:                  eventType,
+			Payload:                    string(payload),
+			NextAttemptTimestampSecond: now,
+			CreateTimestampSecond:      now,
+		})
+	}
+	return db.InsertEventDeliveries(deliveries)
+}
+
+func subscribesEvent(subscription *spdb.EventSubscription, eventType spdb.ObjectEventType) bool {
+	for _, t := range subscription.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyObjectEvent records the notifications of the object event, the errors are only logged since the
+// notifications should not affect the upload workflow.
+func (m *ManageModular) notifyObjectEvent(ctx context.Context, eventType spdb.ObjectEventType, objectInfo *storagetypes.ObjectInfo) {
+	if err := NotifyObjectEvent(m.baseApp.GfSpDB(), eventType, objectInfo); err != nil {
+		log.CtxErrorw(ctx, "failed to notify object event", "event_type", eventType,
+			"object_info", objectInfo, "error", err)
+	}
+}
+
+// SignObjectEvent returns the signature of the notification body by the secret of the subscription.
+func SignObjectEvent(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliverEvents delivers the due notifications, the failed ones are retried with the exponential backoff
+// and moved to the dead letters after the max attempts.
+func (m *ManageModular) deliverEvents(ctx context.Context) {
+	defer m.eventDeliveryRunning.Store(false)
+	db := m.baseApp.GfSpDB()
+	deliveries, err := db.GetDueEventDeliveries(time.Now().Unix(), eventDeliveryBatchSize)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get due event deliveries", "error", err)
+		return
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+	subscriptions := make(map[string]*spdb.EventSubscription)
+	for _, delivery := range deliveries {
+		if _, ok := subscriptions[delivery.BucketName+"/"+delivery.SubscriptionID]; ok {
+			continue
+		}
+		bucketSubscriptions, err := db.GetBucketEventSubscriptions(delivery.BucketName)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to get bucket event subscriptions", "bucket_name", delivery.BucketName,
+				"error", err)
+			return
+		}
+		for _, subscription := range bucketSubscriptions {
+			subscriptions[subscription.BucketName+"/"+subscription.SubscriptionID] = subscription
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		subscription := subscriptions[delivery.BucketName+"/"+delivery.SubscriptionID]
+		if subscription == nil {
+			// the subscription is deleted, the notification is dropped
+			if err = db.DeleteEventDelivery(delivery.DeliveryID); err != nil {
+				log.CtxErrorw(ctx, "failed to delete event delivery", "delivery_id", delivery.DeliveryID, "error", err)
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(delivery *spdb.EventDelivery) {
+			defer wg.Done()
+			m.deliverEvent(ctx, subscription, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+func (m *ManageModular) deliverEvent(ctx context.Context, subscription *spdb.EventSubscription, delivery *spdb.EventDelivery) {
+	var (
+		db  = m.baseApp.GfSpDB()
+		err = m.postEvent(ctx, subscription, delivery)
+	)
+	if err == nil {
+		if err = db.DeleteEventDelivery(delivery.DeliveryID); err != nil {
+			log.CtxErrorw(ctx, "failed to delete delivered event", "delivery_id", delivery.DeliveryID, "error", err)
+		}
+		return
+	}
+	delivery.Attempts++
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= m.eventDeliveryMaxAttempts {
+		log.CtxWarnw(ctx, "event delivery exceeds the max attempts", "bucket_name", delivery.BucketName,
+			"subscription_id", delivery.SubscriptionID, "delivery_id", delivery.DeliveryID, "error", err)
+		if err = db.MoveEventDeliveryToDeadLetter(delivery); err != nil {
+			log.CtxErrorw(ctx, "failed to move event delivery to dead letter", "delivery_id", delivery.DeliveryID,
+				"error", err)
+		}
+		return
+	}
+	backoff := eventDeliveryBaseBackoff << (delivery.Attempts - 1)
+	if backoff <= 0 || backoff > m.eventDeliveryMaxBackoff {
+		backoff = m.eventDeliveryMaxBackoff
+	}
+	delivery.NextAttemptTimestampSecond = time.Now().Add(backoff).Unix()
+	if err = db.UpdateEventDeliveryAttempt(delivery); err != nil {
+		log.CtxErrorw(ctx, "failed to update event delivery attempt", "delivery_id", delivery.DeliveryID, "error", err)
+	}
+}
+
+func (m *ManageModular) postEvent(ctx context.Context, subscription *spdb.EventSubscription, delivery *spdb.EventDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, eventDeliveryTimeout)
+	defer cancel()
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, strconv.FormatUint(delivery.DeliveryID, 10))
+	req.Header.Set(EventTypeHeader, string(delivery.EventType))
+	req.Header.Set(EventTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(EventSignatureHeader, SignObjectEvent(subscription.Secret, timestamp, body))
+	resp, err := m.eventClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
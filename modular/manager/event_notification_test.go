@@ -0,0 +1,284 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const eventTestBucketName = "event-bucket"
+
+func setupEventTest(t *testing.T, db spdb.SPDB, client *http.Client) *ManageModular {
+	baseApp, err := gfspapp.NewGfSpBaseApp(&gfspconfig.GfSpConfig{}, gfspconfig.CustomizeGfSpDB(db))
+	require.NoError(t, err)
+	return &ManageModular{
+		baseApp:                  baseApp,
+		eventDeliveryMaxAttempts: 3,
+		eventDeliveryMaxBackoff:  time.Minute,
+		eventClient:              client,
+	}
+}
+
+func TestNotifyObjectEvent(t *testing.T) {
+	objectInfo := &storagetypes.ObjectInfo{BucketName: eventTestBucketName, ObjectName: "object", Id: sdkmath.NewUint(10),
+		Owner: "owner", PayloadSize: 100, ContentType: "text/plain"}
+	cases := []struct {
+		name          string
+		subscriptions []*spdb.EventSubscription
+		expected      []string
+	}{
+		{
+			name: "no subscription",
+		},
+		{
+			name: "only the subscriptions of the event",
+			subscriptions: []*spdb.EventSubscription{
+				{BucketName: eventTestBucketName, SubscriptionID: "a",
+					EventTypes: []spdb.ObjectEventType{spdb.ObjectUploadedEvent, spdb.ObjectSealedEvent}},
+				{BucketName: eventTestBucketName, SubscriptionID: "b",
+					EventTypes: []spdb.ObjectEventType{spdb.ObjectDeletedEvent}},
+				{BucketName: eventTestBucketName, SubscriptionID: "c",
+					EventTypes: []spdb.ObjectEventType{spdb.ObjectSealedEvent}},
+			},
+			expected: []string{"a", "c"},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := spdb.NewMockSPDB(ctrl)
+			db.EXPECT().GetBucketEventSubscriptions(eventTestBucketName).Return(tt.subscriptions, nil)
+			var deliveries []*spdb.EventDelivery
+			if len(tt.subscriptions) > 0 {
+				db.EXPECT().InsertEventDeliveries(gomock.Any()).DoAndReturn(func(d []*spdb.EventDelivery) error {
+					deliveries = d
+					return nil
+				})
+			}
+			require.NoError(t, NotifyObjectEvent(db, spdb.ObjectSealedEvent, objectInfo))
+
+			require.Len(t, deliveries, len(tt.expected))
+			for i, delivery := range deliveries {
+				assert.Equal(t, tt.expected[i], delivery.SubscriptionID)
+				assert.Equal(t, spdb.ObjectSealedEvent, delivery.EventType)
+				assert.Equal(t, delivery.CreateTimestampSecond, delivery.NextAttemptTimestampSecond)
+				event := &ObjectEvent{}
+				require.NoError(t, json.Unmarshal([]byte(delivery.Payload), event))
+				assert.Equal(t, &ObjectEvent{EventType: spdb.ObjectSealedEvent, EventTime: delivery.CreateTimestampSecond,
+					SubscriptionID: tt.expected[i], BucketName: eventTestBucketName, ObjectName: "object", ObjectID: "10",
+					Owner: "owner", PayloadSize: 100, ContentType: "text/plain"}, event)
+			}
+		})
+	}
+}
+
+func TestNotifyObjectEventDBError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := spdb.NewMockSPDB(ctrl)
+	db.EXPECT().GetBucketEventSubscriptions(eventTestBucketName).Return(nil, errors.New("mock error"))
+	assert.Error(t, NotifyObjectEvent(db, spdb.ObjectSealedEvent, &storagetypes.ObjectInfo{BucketName: eventTestBucketName}))
+	assert.NoError(t, NotifyObjectEvent(db, spdb.ObjectSealedEvent, nil))
+}
+
+func TestSignObjectEvent(t *testing.T) {
+	// echo -n '100.{"a":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "d11157d820d2450c9feff3143012eb2ce5d901e1ede45be0ad2ab7ba6b31706c",
+		SignObjectEvent("secret", 100, []byte(`{"a":1}`)))
+	assert.NotEqual(t, SignObjectEvent("secret", 100, []byte("body")), SignObjectEvent("other", 100, []byte("body")))
+	assert.NotEqual(t, SignObjectEvent("secret", 100, []byte("body")), SignObjectEvent("secret", 101, []byte("body")))
+}
+
+func TestManageModular_DeliverEvent(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+	subscription := &spdb.EventSubscription{BucketName: eventTestBucketName, SubscriptionID: "a",
+		Endpoint: server.URL, Secret: "secret"}
+	newDelivery := func(attempts int) *spdb.EventDelivery {
+		return &spdb.EventDelivery{DeliveryID: 1, BucketName: eventTestBucketName, SubscriptionID: "a",
+			EventType: spdb.ObjectSealedEvent, Payload: `{"a":1}`, Attempts: attempts}
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		db := spdb.NewMockSPDB(ctrl)
+		db.EXPECT().DeleteEventDelivery(uint64(1)).Return(nil)
+		m := setupEventTest(t, db, server.Client())
+		statusCode = http.StatusOK
+		m.deliverEvent(context.Background(), subscription, newDelivery(0))
+
+		require.NotNil(t, received)
+		assert.Equal(t, `{"a":1}`, string(receivedBody))
+		assert.Equal(t, "1", received.Header.Get(EventIDHeader))
+		assert.Equal(t, string(spdb.ObjectSealedEvent), received.Header.Get(EventTypeHeader))
+		timestamp, err := strconv.ParseInt(received.Header.Get(EventTimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, SignObjectEvent("secret", timestamp, receivedBody), received.Header.Get(EventSignatureHeader))
+	})
+
+	cases := []struct {
+		name            string
+		attempts        int
+		expectedBackoff time.Duration
+	}{
+		{name: "first failure", attempts: 0, expectedBackoff: eventDeliveryBaseBackoff},
+		{name: "backoff doubled", attempts: 1, expectedBackoff: 2 * eventDeliveryBaseBackoff},
+		{name: "backoff capped", attempts: 100, expectedBackoff: time.Minute},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := spdb.NewMockSPDB(ctrl)
+			var updated *spdb.EventDelivery
+			db.EXPECT().UpdateEventDeliveryAttempt(gomock.Any()).DoAndReturn(func(d *spdb.EventDelivery) error {
+				updated = d
+				return nil
+			})
+			m := setupEventTest(t, db, server.Client())
+			m.eventDeliveryMaxAttempts = 1000
+			statusCode = http.StatusInternalServerError
+			now := time.Now()
+			m.deliverEvent(context.Background(), subscription, newDelivery(tt.attempts))
+
+			require.NotNil(t, updated)
+			assert.Equal(t, tt.attempts+1, updated.Attempts)
+			assert.Contains(t, updated.LastError, "500")
+			assert.InDelta(t, now.Add(tt.expectedBackoff).Unix(), updated.NextAttemptTimestampSecond, 1)
+		})
+	}
+
+	t.Run("dead letter", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		db := spdb.NewMockSPDB(ctrl)
+		var dead *spdb.EventDelivery
+		db.EXPECT().MoveEventDeliveryToDeadLetter(gomock.Any()).DoAndReturn(func(d *spdb.EventDelivery) error {
+			dead = d
+			return nil
+		})
+		m := setupEventTest(t, db, server.Client())
+		statusCode = http.StatusNotFound
+		m.deliverEvent(context.Background(), subscription, newDelivery(2))
+
+		require.NotNil(t, dead)
+		assert.Equal(t, 3, dead.Attempts)
+		assert.Contains(t, dead.LastError, "404")
+	})
+}
+
+func TestManageModular_DeliverEvents(t *testing.T) {
+	delivered := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- r.Header.Get(EventIDHeader)
+	}))
+	defer server.Close()
+	ctrl := gomock.NewController(t)
+	db := spdb.NewMockSPDB(ctrl)
+	db.EXPECT().GetDueEventDeliveries(gomock.Any(), eventDeliveryBatchSize).Return([]*spdb.EventDelivery{
+		{DeliveryID: 1, BucketName: eventTestBucketName, SubscriptionID: "a"},
+		{DeliveryID: 2, BucketName: eventTestBucketName, SubscriptionID: "deleted"},
+	}, nil)
+	db.EXPECT().GetBucketEventSubscriptions(eventTestBucketName).Return([]*spdb.EventSubscription{
+		{BucketName: eventTestBucketName, SubscriptionID: "a", Endpoint: server.URL, Secret: "secret"},
+	}, nil).Times(2)
+	// the delivered notification and the notification of the deleted subscription are both deleted
+	db.EXPECT().DeleteEventDelivery(uint64(1)).Return(nil)
+	db.EXPECT().DeleteEventDelivery(uint64(2)).Return(nil)
+	m := setupEventTest(t, db, server.Client())
+	m.eventDeliveryRunning.Store(true)
+	m.deliverEvents(context.Background())
+
+	assert.False(t, m.eventDeliveryRunning.Load())
+	require.Len(t, delivered, 1)
+	assert.Equal(t, "1", <-delivered)
+}
+
+func TestIsPublicEventIP(t *testing.T) {
+	cases := []struct {
+		ip       string
+		expected bool
+	}{
+		{"8.8.8.8", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"0.1.2.3", false},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"100.128.0.1", true},
+		{"192.0.0.8", false},
+		{"192.0.2.1", false},
+		{"192.88.99.1", false},
+		{"198.18.0.1", false},
+		{"198.19.255.254", false},
+		{"198.20.0.1", true},
+		{"198.51.100.1", false},
+		{"203.0.113.1", false},
+		{"240.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:100.64.0.1", false},
+		{"64:ff9b::a00:1", false},
+		{"64:ff9b:1::1", false},
+		{"100::1", false},
+		{"2001::1", false},
+		{"2001:db8::1", false},
+		{"2002:a00:1::1", false},
+		{"3fff::1", false},
+		{"5f00::1", false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsPublicEventIP(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestEventClient(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+	client := newEventClient()
+
+	// the loopback address is refused before connecting
+	_, err := client.Get(server.URL)
+	assert.ErrorIs(t, err, ErrEventEndpointNotPublic)
+	assert.False(t, requested)
+
+	// the redirects are not followed
+	req, err := http.NewRequest(http.MethodGet, "https://8.8.8.8/", nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, client.CheckRedirect(req, nil), ErrEventRedirect)
+}
@@ -33,6 +33,8 @@ var (
 	ErrCanceledTask         = gfsperrors.Register(module.ManageModularName, http.StatusBadRequest, 60004, "task canceled")
 	ErrFutureSupport        = gfsperrors.Register(module.ManageModularName, http.StatusNotFound, 60005, "future support")
 	ErrNotifyMigrateSwapOut = gfsperrors.Register(module.ManageModularName, http.StatusNotAcceptable, 60006, "failed to notify swap out start")
+	ErrNoSPHealth           = gfsperrors.Register(module.ManageModularName, http.StatusNotFound, 60007, "no call to the sp is observed")
+	ErrNoScrubReport        = gfsperrors.Register(module.ManageModularName, http.StatusNotFound, 60008, "no scrub report of the gvg")
 	ErrGfSpDB               = gfsperrors.Register(module.ManageModularName, http.StatusInternalServerError, 65201, "server slipped away, try again later")
 )
 
@@ -72,6 +74,12 @@ func (m *ManageModular) DispatchTask(ctx context.Context, limit rcmgr.Limit) (ta
 			"task_limit", task.EstimateLimit().String())
 		backupTasks = append(backupTasks, task)
 	}
+	task = m.scrubGVGQueue.PopByLimit(limit)
+	if task != nil {
+		log.CtxDebugw(ctx, "add scrub gvg task to backup set", "task_key", task.Key().String(),
+			"task_limit", task.EstimateLimit().String())
+		backupTasks = append(backupTasks, task)
+	}
 	task = m.receiveQueue.PopByLimit(limit)
 	if task != nil {
 		log.CtxDebugw(ctx, "add confirm receive piece to backup set", "task_key", task.Key().String(),
@@ -118,6 +126,9 @@ func (m *ManageModular) DispatchTask(ctx context.Context, limit rcmgr.Limit) (ta
 			case *gfsptask.GfSpGCMetaTask:
 				err := m.gcMetaQueue.Push(t)
 				log.Errorw("failed to retry push gc meta task to queue after dispatching", "error", err)
+			case *gfsptask.GfSpScrubGVGTask:
+				err := m.scrubGVGQueue.Push(t)
+				log.Errorw("failed to retry push scrub gvg task to queue after dispatching", "error", err)
 			case *gfsptask.GfSpRecoverPieceTask:
 				err := m.recoveryQueue.Push(t)
 				log.Errorw("failed to retry push recovery task to queue after dispatching", "error", err)
@@ -224,6 +235,7 @@ func (m *ManageModular) HandleDoneUploadObjectTask(ctx context.Context, task tas
 		}
 		log.Debugw("succeed to done upload object and waiting for scheduling to replicate piece", "task_info", task.Info())
 	}()
+	go m.notifyObjectEvent(ctx, spdb.ObjectUploadedEvent, task.GetObjectInfo())
 	return nil
 }
 
@@ -325,6 +337,7 @@ func (m *ManageModular) HandleDoneResumableUploadObjectTask(ctx context.Context,
 		log.CtxDebugw(ctx, "succeed to done upload object and waiting for scheduling to replicate piece")
 		return nil
 	}()
+	go m.notifyObjectEvent(ctx, spdb.ObjectUploadedEvent, task.GetObjectInfo())
 	return nil
 }
 
@@ -353,6 +366,8 @@ func (m *ManageModular) HandleReplicatePieceTask(ctx context.Context, task task.
 	if task.GetSealed() {
 		task.AppendLog(fmt.Sprintf("manager-handle-succeed-replicate-task-retry:%d", task.GetRetry()))
 		go func() {
+			m.notifyObjectEvent(ctx, spdb.ObjectReplicatedEvent, task.GetObjectInfo())
+			m.notifyObjectEvent(ctx, spdb.ObjectSealedEvent, task.GetObjectInfo())
 			_ = m.baseApp.GfSpDB().InsertPutEvent(task)
 			log.Debugw("replicate piece object task has combined seal object task", "task_info", task.Info())
 			if err := m.baseApp.GfSpDB().UpdateUploadProgress(&spdb.UploadObjectMeta{
@@ -402,6 +417,7 @@ func (m *ManageModular) HandleReplicatePieceTask(ctx context.Context, task task.
 		}
 		log.Debugw("succeed to done replicate piece and waiting for scheduling to seal object", "task_info", task.Info())
 	}()
+	go m.notifyObjectEvent(ctx, spdb.ObjectReplicatedEvent, task.GetObjectInfo())
 	return nil
 }
 
@@ -476,6 +492,7 @@ func (m *ManageModular) HandleSealObjectTask(ctx context.Context, task task.Seal
 		// TODO: delete this upload db record?
 		log.Debugw("succeed to seal object on chain", "task_info", task.Info())
 	}()
+	go m.notifyObjectEvent(ctx, spdb.ObjectSealedEvent, task.GetObjectInfo())
 	return nil
 }
 
@@ -606,12 +623,112 @@ func (m *ManageModular) HandleGCObjectTask(ctx context.Context, gcTask task.GCOb
 	return nil
 }
 
-func (m *ManageModular) HandleGCZombiePieceTask(ctx context.Context, task task.GCZombiePieceTask) error {
-	return ErrFutureSupport
+func (m *ManageModular) HandleGCZombiePieceTask(ctx context.Context, gcTask task.GCZombiePieceTask) error {
+	if gcTask == nil {
+		log.CtxErrorw(ctx, "failed to handle gc zombie piece due to task pointer dangling")
+		return ErrDanglingTask
+	}
+	if gcTask.GetFinished() || gcTask.Error() != nil {
+		m.gcZombieQueue.PopByKey(gcTask.Key())
+		if gcTask.GetFinished() || gcTask.GetLastPieceKey() != "" {
+			m.updateGCZombieProgress(ctx, gcTask.GetLastPieceKey())
+		}
+		log.CtxInfow(ctx, "finish the gc zombie piece task", "task_info", gcTask.Info(), "error", gcTask.Error())
+		return nil
+	}
+	gcTask.SetUpdateTime(time.Now().Unix())
+	oldTask := m.gcZombieQueue.PopByKey(gcTask.Key())
+	if oldTask != nil && oldTask.(task.GCZombiePieceTask).GetLastPieceKey() > gcTask.GetLastPieceKey() {
+		log.CtxErrorw(ctx, "the reported gc zombie piece task is expired", "report_info", gcTask.Info(),
+			"current_info", oldTask.Info())
+		return ErrCanceledTask
+	}
+	// push the task to queue to record the running task, it will not be dispatched again
+	// because the retry is not zero.
+	err := m.gcZombieQueue.Push(gcTask)
+	m.updateGCZombieProgress(ctx, gcTask.GetLastPieceKey())
+	log.CtxInfow(ctx, "update the gc zombie piece task progress", "from", oldTask, "to", gcTask, "error", err)
+	return nil
 }
 
-func (m *ManageModular) HandleGCMetaTask(ctx context.Context, task task.GCMetaTask) error {
-	return ErrFutureSupport
+// updateGCZombieProgress records the last scanned piece key in memory and sp db, the next gc zombie piece
+// task resumes from it even if the manager restarts.
+func (m *ManageModular) updateGCZombieProgress(ctx context.Context, lastPieceKey string) {
+	m.setGCZombiePieceMarker(lastPieceKey)
+	err := m.baseApp.GfSpDB().UpdateGCZombieProgress(&spdb.GCZombieProgress{LastPieceKey: lastPieceKey})
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to update the gc zombie piece progress", "last_piece_key", lastPieceKey,
+			"error", err)
+	}
+}
+
+func (m *ManageModular) HandleGCMetaTask(ctx context.Context, gcTask task.GCMetaTask) error {
+	if gcTask == nil {
+		log.CtxErrorw(ctx, "failed to handle gc meta due to task pointer dangling")
+		return ErrDanglingTask
+	}
+	if gcTask.GetFinished() || gcTask.Error() != nil {
+		m.gcMetaQueue.PopByKey(gcTask.Key())
+		if gcTask.GetFinished() {
+			// the next task starts to collect the meta from the beginning
+			err := m.baseApp.GfSpDB().UpdateGCMetaProgress(&spdb.GCMetaProgress{})
+			log.CtxInfow(ctx, "reset the gc meta task progress", "error", err)
+		}
+		log.CtxInfow(ctx, "finish the gc meta task", "task_info", gcTask.Info(), "error", gcTask.Error())
+		return nil
+	}
+	gcTask.SetUpdateTime(time.Now().Unix())
+	oldTask := m.gcMetaQueue.PopByKey(gcTask.Key())
+	if oldTask != nil {
+		oldIdx, _ := oldTask.(task.GCMetaTask).GetGCMetaStatus()
+		currentIdx, _ := gcTask.GetGCMetaStatus()
+		if oldIdx > currentIdx || (oldIdx == currentIdx &&
+			oldTask.(task.GCMetaTask).GetLastObjectId() > gcTask.GetLastObjectId()) {
+			log.CtxErrorw(ctx, "the reported gc meta task is expired", "report_info", gcTask.Info(),
+				"current_info", oldTask.Info())
+			return ErrCanceledTask
+		}
+	}
+	// push the task to queue to record the running task, it will not be dispatched again
+	// because the retry is not zero.
+	err := m.gcMetaQueue.Push(gcTask)
+	log.CtxInfow(ctx, "push gc meta task to queue again", "from", oldTask, "to", gcTask, "error", err)
+	currentIdx, _ := gcTask.GetGCMetaStatus()
+	err = m.baseApp.GfSpDB().UpdateGCMetaProgress(&spdb.GCMetaProgress{
+		CurrentIdx:   currentIdx,
+		LastObjectID: gcTask.GetLastObjectId(),
+	})
+	log.CtxInfow(ctx, "update the gc meta task progress", "from", oldTask, "to", gcTask, "error", err)
+	return nil
+}
+
+func (m *ManageModular) HandleScrubGVGTask(ctx context.Context, scrubTask task.ScrubGVGTask) error {
+	if scrubTask == nil {
+		log.CtxErrorw(ctx, "failed to handle scrub gvg due to task pointer dangling")
+		return ErrDanglingTask
+	}
+	if scrubTask.GetFinished() || scrubTask.Error() != nil {
+		m.scrubGVGQueue.PopByKey(scrubTask.Key())
+		// the failed task is resumed from the recorded progress by the next task
+		err := m.updateScrubProgress(scrubTask)
+		log.CtxInfow(ctx, "finish the scrub gvg task", "task_info", scrubTask.Info(),
+			"task_error", scrubTask.Error(), "error", err)
+		return nil
+	}
+	scrubTask.SetUpdateTime(time.Now().Unix())
+	oldTask := m.scrubGVGQueue.PopByKey(scrubTask.Key())
+	if oldTask != nil && oldTask.(task.ScrubGVGTask).GetLastScrubbedObjectId() > scrubTask.GetLastScrubbedObjectId() {
+		log.CtxErrorw(ctx, "the reported scrub gvg task is expired", "report_info", scrubTask.Info(),
+			"current_info", oldTask.Info())
+		return ErrCanceledTask
+	}
+	// push the task to queue to record the running task, it will not be dispatched again
+	// because the retry is not zero.
+	err := m.scrubGVGQueue.Push(scrubTask)
+	log.CtxInfow(ctx, "push scrub gvg task to queue again", "from", oldTask, "to", scrubTask, "error", err)
+	err = m.updateScrubProgress(scrubTask)
+	log.CtxInfow(ctx, "update the scrub gvg task progress", "task_info", scrubTask.Info(), "error", err)
+	return nil
 }
 
 func (m *ManageModular) HandleDownloadObjectTask(ctx context.Context, task task.DownloadObjectTask) error {
@@ -696,6 +813,7 @@ func (m *ManageModular) QueryTasks(ctx context.Context, subKey task.TKey) ([]tas
 	gcObjectTasks, _ := taskqueue.ScanTQueueWithLimitBySubKey(m.gcObjectQueue, subKey)
 	gcZombieTasks, _ := taskqueue.ScanTQueueWithLimitBySubKey(m.gcZombieQueue, subKey)
 	gcMetaTasks, _ := taskqueue.ScanTQueueWithLimitBySubKey(m.gcMetaQueue, subKey)
+	scrubGVGTasks, _ := taskqueue.ScanTQueueWithLimitBySubKey(m.scrubGVGQueue, subKey)
 	downloadTasks, _ := taskqueue.ScanTQueueBySubKey(m.downloadQueue, subKey)
 	challengeTasks, _ := taskqueue.ScanTQueueBySubKey(m.challengeQueue, subKey)
 	recoveryTasks, _ := taskqueue.ScanTQueueWithLimitBySubKey(m.recoveryQueue, subKey)
@@ -709,6 +827,7 @@ func (m *ManageModular) QueryTasks(ctx context.Context, subKey task.TKey) ([]tas
 	tasks = append(tasks, gcObjectTasks...)
 	tasks = append(tasks, gcZombieTasks...)
 	tasks = append(tasks, gcMetaTasks...)
+	tasks = append(tasks, scrubGVGTasks...)
 	tasks = append(tasks, downloadTasks...)
 	tasks = append(tasks, challengeTasks...)
 	tasks = append(tasks, recoveryTasks...)
@@ -736,6 +855,93 @@ func (m *ManageModular) QuerySpExit(ctx context.Context) (res *gfspserver.GfSpQu
 	return res, err
 }
 
+// ReportSPCallRecords records the results of the calls to the other sps which are reported by executor.
+func (m *ManageModular) ReportSPCallRecords(ctx context.Context, records []*gfspserver.GfSpSPCallRecord) error {
+	for _, record := range records {
+		m.spHealth.Record(record)
+	}
+	return nil
+}
+
+// QuerySPHealth returns the healths of the observed sps, the unhealthiest is the first.
+func (m *ManageModular) QuerySPHealth(ctx context.Context, spEndpoint string) ([]*gfspserver.GfSpSPHealth, error) {
+	if spEndpoint == "" {
+		return m.spHealth.Healths(), nil
+	}
+	health, ok := m.spHealth.Health(spEndpoint)
+	if !ok {
+		return nil, ErrNoSPHealth
+	}
+	return []*gfspserver.GfSpSPHealth{health}, nil
+}
+
+// QueryScrubReport returns the scrub reports of all the gvgs if gvgID is 0, otherwise returns the report of the
+// gvg and at most pieceLimit bad pieces found in the current round.
+func (m *ManageModular) QueryScrubReport(ctx context.Context, gvgID uint32, pieceLimit uint32) (
+	[]*gfspserver.GfSpScrubGVGReport, []*gfspserver.GfSpScrubPiece, error) {
+	if gvgID == 0 {
+		progresses, err := m.baseApp.GfSpDB().ListScrubProgresses()
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to list scrub progresses", "error", err)
+			return nil, nil, err
+		}
+		reports := make([]*gfspserver.GfSpScrubGVGReport, 0, len(progresses))
+		for _, progress := range progresses {
+			reports = append(reports, toScrubGVGReport(progress))
+		}
+		return reports, nil, nil
+	}
+	progress, err := m.baseApp.GfSpDB().QueryScrubProgress(gvgID)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to query scrub progress", "gvg_id", gvgID, "error", err)
+		return nil, nil, err
+	}
+	if progress == nil {
+		return nil, nil, ErrNoScrubReport
+	}
+	if pieceLimit == 0 {
+		pieceLimit = DefaultQueryScrubPieceLimit
+	}
+	scrubPieces, err := m.baseApp.GfSpDB().ListScrubPieces(gvgID, int(pieceLimit))
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list scrub pieces", "gvg_id", gvgID, "error", err)
+		return nil, nil, err
+	}
+	pieces := make([]*gfspserver.GfSpScrubPiece, 0, len(scrubPieces))
+	for _, piece := range scrubPieces {
+		pieces = append(pieces, &gfspserver.GfSpScrubPiece{
+			GvgId:              piece.GlobalVirtualGroupID,
+			ObjectId:           piece.ObjectID,
+			SegmentIdx:         piece.SegmentIndex,
+			RedundancyIdx:      piece.RedundancyIndex,
+			PieceKey:           piece.PieceKey,
+			State:              string(piece.State),
+			Recovering:         piece.Recovering,
+			DetectTimestampSec: piece.DetectTimestampSecond,
+		})
+	}
+	return []*gfspserver.GfSpScrubGVGReport{toScrubGVGReport(progress)}, pieces, nil
+}
+
+// isSPHealthy returns false if the calls to the sp keep failing or timing out, the sp which is not found is
+// regarded as healthy and is left to the caller to check.
+func (m *ManageModular) isSPHealthy(spID uint32) bool {
+	endpoint, err := m.querySPEndpoint(spID)
+	if err != nil {
+		return true
+	}
+	return m.spHealth.IsHealthy(endpoint)
+}
+
+// querySPEndpoint returns the endpoint of the sp by which the sp health is tracked.
+func (m *ManageModular) querySPEndpoint(spID uint32) (string, error) {
+	sp, err := m.virtualGroupManager.QuerySPByID(spID)
+	if err != nil {
+		return "", err
+	}
+	return sp.GetEndpoint(), nil
+}
+
 // PickVirtualGroupFamily is used to pick a suitable vgf for creating bucket.
 func (m *ManageModular) PickVirtualGroupFamily(ctx context.Context, task task.ApprovalCreateBucketTask) (uint32, error) {
 	var (
@@ -743,19 +949,23 @@ func (m *ManageModular) PickVirtualGroupFamily(ctx context.Context, task task.Ap
 		vgf *vgmgr.VirtualGroupFamilyMeta
 	)
 
-	if vgf, err = m.virtualGroupManager.PickVirtualGroupFamily(); err != nil {
-		// create a new gvg, and retry pick.
-		if err = m.createGlobalVirtualGroup(0, nil); err != nil {
+	req := &vgmgr.PickVirtualGroupFamilyRequest{
+		BucketName: task.GetCreateBucketInfo().GetBucketName(),
+		Owner:      task.GetCreateBucketInfo().GetCreator(),
+	}
+	if vgf, err = m.virtualGroupManager.PickVirtualGroupFamily(req); err != nil {
+		// create a new gvg, and retry pick, the gvg is created in the dedicated family of the owner if it has.
+		if err = m.createGlobalVirtualGroup(m.dedicatedFamilies[strings.ToLower(req.Owner)], nil); err != nil {
 			log.CtxErrorw(ctx, "failed to create global virtual group", "task_info", task.Info(), "error", err)
 			return 0, err
 		}
 		m.virtualGroupManager.ForceRefreshMeta()
-		if vgf, err = m.virtualGroupManager.PickVirtualGroupFamily(); err != nil {
+		if vgf, err = m.virtualGroupManager.PickVirtualGroupFamily(req); err != nil {
 			log.CtxErrorw(ctx, "failed to pick vgf", "task_info", task.Info(), "error", err)
 			return 0, err
 		}
-		return vgf.ID, nil
 	}
+	m.virtualGroupStats.addFamilyBucket(vgf.ID)
 	return vgf.ID, nil
 }
 
@@ -766,14 +976,22 @@ type GenerateGVGSecondarySPsPolicyByPrefer struct {
 	preferSPIDMap             map[uint32]bool
 	preferSPIDList            []uint32
 	backupSPIDList            []uint32
+	unhealthySPIDList         []uint32
+	isSPHealthy               func(spID uint32) bool
 }
 
-func NewGenerateGVGSecondarySPsPolicyByPrefer(p *storagetypes.Params, preferSPIDList []uint32) *GenerateGVGSecondarySPsPolicyByPrefer {
+// NewGenerateGVGSecondarySPsPolicyByPrefer returns the policy which prefers the sps of the preferSPIDList, the
+// unhealthy sps are only picked if there are not enough healthy sps, isSPHealthy can be nil if all the sps are
+// regarded as healthy.
+func NewGenerateGVGSecondarySPsPolicyByPrefer(p *storagetypes.Params, preferSPIDList []uint32,
+	isSPHealthy func(spID uint32) bool) *GenerateGVGSecondarySPsPolicyByPrefer {
 	policy := &GenerateGVGSecondarySPsPolicyByPrefer{
 		expectedSecondarySPNumber: int(p.GetRedundantDataChunkNum() + p.GetRedundantParityChunkNum()),
 		preferSPIDMap:             make(map[uint32]bool),
 		preferSPIDList:            make([]uint32, 0),
 		backupSPIDList:            make([]uint32, 0),
+		unhealthySPIDList:         make([]uint32, 0),
+		isSPHealthy:               isSPHealthy,
 	}
 	for _, spID := range preferSPIDList {
 		policy.preferSPIDMap[spID] = true
@@ -782,6 +1000,10 @@ func NewGenerateGVGSecondarySPsPolicyByPrefer(p *storagetypes.Params, preferSPID
 }
 
 func (p *GenerateGVGSecondarySPsPolicyByPrefer) AddCandidateSP(spID uint32) {
+	if p.isSPHealthy != nil && !p.isSPHealthy(spID) {
+		p.unhealthySPIDList = append(p.unhealthySPIDList, spID)
+		return
+	}
 	if _, found := p.preferSPIDMap[spID]; found {
 		p.preferSPIDList = append(p.preferSPIDList, spID)
 	} else {
@@ -790,12 +1012,13 @@ func (p *GenerateGVGSecondarySPsPolicyByPrefer) AddCandidateSP(spID uint32) {
 
 }
 func (p *GenerateGVGSecondarySPsPolicyByPrefer) GenerateGVGSecondarySPs() ([]uint32, error) {
-	if p.expectedSecondarySPNumber > len(p.preferSPIDList)+len(p.backupSPIDList) {
+	if p.expectedSecondarySPNumber > len(p.preferSPIDList)+len(p.backupSPIDList)+len(p.unhealthySPIDList) {
 		return nil, fmt.Errorf("no enough sp")
 	}
 	resultSPList := make([]uint32, 0)
 	resultSPList = append(resultSPList, p.preferSPIDList...)
 	resultSPList = append(resultSPList, p.backupSPIDList...)
+	resultSPList = append(resultSPList, p.unhealthySPIDList...)
 	return resultSPList[0:p.expectedSecondarySPNumber], nil
 }
 
@@ -806,7 +1029,7 @@ func (m *ManageModular) createGlobalVirtualGroup(vgfID uint32, params *storagety
 			return err
 		}
 	}
-	gvgMeta, err := m.virtualGroupManager.GenerateGlobalVirtualGroupMeta(NewGenerateGVGSecondarySPsPolicyByPrefer(params, m.gvgPreferSPList))
+	gvgMeta, err := m.virtualGroupManager.GenerateGlobalVirtualGroupMeta(NewGenerateGVGSecondarySPsPolicyByPrefer(params, m.gvgPreferSPList, m.isSPHealthy))
 	if err != nil {
 		return err
 	}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsphealth"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
 	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
@@ -57,6 +61,7 @@ type ManageModular struct {
 	gcObjectQueue        taskqueue.TQueueOnStrategyWithLimit
 	gcZombieQueue        taskqueue.TQueueOnStrategyWithLimit
 	gcMetaQueue          taskqueue.TQueueOnStrategyWithLimit
+	scrubGVGQueue        taskqueue.TQueueOnStrategyWithLimit
 	downloadQueue        taskqueue.TQueueOnStrategy
 	challengeQueue       taskqueue.TQueueOnStrategy
 	recoveryQueue        taskqueue.TQueueOnStrategyWithLimit
@@ -69,6 +74,18 @@ type ManageModular struct {
 	gcObjectBlockInterval uint64
 	gcSafeBlockDistance   uint64
 
+	gcZombiePieceTimeInterval int
+	gcZombiePieceLimit        uint64
+	gcZombiePieceMarker       string
+	gcZombiePieceMux          sync.Mutex
+
+	gcMetaTimeInterval int
+
+	scrubGVGTimeInterval int
+	scrubRoundInterval   int64
+	scrubGVGEnabled      bool
+	scrubScheduleRunning atomic.Bool
+
 	syncConsensusInfoInterval uint64
 	statisticsOutputInterval  int
 
@@ -89,6 +106,23 @@ type ManageModular struct {
 	loadSealTimeout      int64
 
 	gvgPreferSPList []uint32
+	spHealth        *gfsphealth.Tracker
+
+	dedicatedFamilies        map[string]uint32
+	virtualGroupStats        *virtualGroupStats
+	virtualGroupStatsRunning atomic.Bool
+
+	readUsageRollupInterval  int
+	readUsageRetentionDays   int
+	readQuotaAlertThresholds []uint32
+	readQuotaAlertWebhook    string
+	readUsageRunning         atomic.Bool
+
+	eventDeliveryInterval    int
+	eventDeliveryMaxAttempts int
+	eventDeliveryMaxBackoff  time.Duration
+	eventDeliveryRunning     atomic.Bool
+	eventClient              *http.Client
 }
 
 func (m *ManageModular) Name() string {
@@ -106,6 +140,12 @@ func (m *ManageModular) Start(ctx context.Context) error {
 	m.receiveQueue.SetFilterTaskStrategy(m.FilterReceiveTask)
 	m.gcObjectQueue.SetRetireTaskStrategy(m.ResetGCObjectTask)
 	m.gcObjectQueue.SetFilterTaskStrategy(m.FilterGCTask)
+	m.gcZombieQueue.SetRetireTaskStrategy(m.GCZombiePieceQueue)
+	m.gcZombieQueue.SetFilterTaskStrategy(m.FilterGCTask)
+	m.gcMetaQueue.SetRetireTaskStrategy(m.GCMetaQueue)
+	m.gcMetaQueue.SetFilterTaskStrategy(m.FilterGCTask)
+	m.scrubGVGQueue.SetRetireTaskStrategy(m.ScrubGVGQueue)
+	m.scrubGVGQueue.SetFilterTaskStrategy(m.FilterGCTask)
 	m.downloadQueue.SetRetireTaskStrategy(m.GCCacheQueue)
 	m.challengeQueue.SetRetireTaskStrategy(m.GCCacheQueue)
 	m.recoveryQueue.SetRetireTaskStrategy(m.GCRecoverQueue)
@@ -153,6 +193,12 @@ func (m *ManageModular) eventLoop(ctx context.Context) {
 	syncConsensusInfoTicker := time.NewTicker(time.Duration(m.syncConsensusInfoInterval) * time.Second)
 	statisticsTicker := time.NewTicker(time.Duration(m.statisticsOutputInterval) * time.Second)
 	discontinueBucketTicker := time.NewTicker(time.Duration(m.discontinueBucketTimeInterval) * time.Second)
+	gcZombiePieceTicker := time.NewTicker(time.Duration(m.gcZombiePieceTimeInterval) * time.Second)
+	gcMetaTicker := time.NewTicker(time.Duration(m.gcMetaTimeInterval) * time.Second)
+	scrubGVGTicker := time.NewTicker(time.Duration(m.scrubGVGTimeInterval) * time.Second)
+	readUsageTicker := time.NewTicker(time.Duration(m.readUsageRollupInterval) * time.Second)
+	eventDeliveryTicker := time.NewTicker(time.Duration(m.eventDeliveryInterval) * time.Millisecond)
+	vgfBucketNumberTicker := time.NewTicker(time.Duration(DefaultVGFBucketNumberRefreshIntervalSec) * time.Second)
 	for {
 		select {
 		case <-ctx.Done():
@@ -193,12 +239,78 @@ func (m *ManageModular) eventLoop(ctx context.Context) {
 				}
 			}
 			log.CtxErrorw(ctx, "generate a gc object task", "task_info", task.Info(), "error", err)
+		case <-gcZombiePieceTicker.C:
+			if m.gcZombieQueue.Len() > 0 {
+				log.CtxDebugw(ctx, "gc zombie piece task is running and try again later")
+				continue
+			}
+			// resume the progress of the last gc zombie piece task, it is recorded in sp db.
+			progress, err := m.baseApp.GfSpDB().QueryGCZombieProgress()
+			if err != nil {
+				log.CtxErrorw(ctx, "failed to query gc zombie piece progress and try again later", "error", err)
+				continue
+			}
+			if progress != nil {
+				m.setGCZombiePieceMarker(progress.LastPieceKey)
+			}
+			task := &gfsptask.GfSpGCZombiePieceTask{}
+			task.InitGCZombiePieceTask(m.baseApp.TaskPriority(task), m.getGCZombiePieceMarker(),
+				m.gcZombiePieceLimit, m.baseApp.TaskTimeout(task, 0))
+			err = m.gcZombieQueue.Push(task)
+			log.CtxErrorw(ctx, "generate a gc zombie piece task", "task_info", task.Info(), "error", err)
+		case <-gcMetaTicker.C:
+			if m.gcMetaQueue.Len() > 0 {
+				log.CtxDebugw(ctx, "gc meta task is running and try again later")
+				continue
+			}
+			// resume the progress of the last gc meta task, it is recorded in sp db.
+			progress, err := m.baseApp.GfSpDB().QueryGCMetaProgress()
+			if err != nil {
+				log.CtxErrorw(ctx, "failed to query gc meta progress and try again later", "error", err)
+				continue
+			}
+			if progress == nil {
+				progress = &spdb.GCMetaProgress{}
+			}
+			task := &gfsptask.GfSpGCMetaTask{}
+			task.InitGCMetaTask(m.baseApp.TaskPriority(task), progress.CurrentIdx, progress.LastObjectID,
+				m.baseApp.TaskTimeout(task, 0))
+			err = m.gcMetaQueue.Push(task)
+			log.CtxErrorw(ctx, "generate a gc meta task", "task_info", task.Info(), "error", err)
+		case <-scrubGVGTicker.C:
+			if !m.scrubGVGEnabled {
+				continue
+			}
+			if m.scrubGVGQueue.Len() > 0 {
+				log.CtxDebugw(ctx, "scrub gvg task is running and try again later")
+				continue
+			}
+			if !m.scrubScheduleRunning.CompareAndSwap(false, true) {
+				continue
+			}
+			go m.scheduleScrubGVG(ctx)
 		case <-discontinueBucketTicker.C:
 			if !m.discontinueBucketEnabled {
 				continue
 			}
 			go m.discontinueBuckets(ctx)
 			log.Infow("finished to discontinue buckets", "time", time.Now())
+		case <-readUsageTicker.C:
+			if !m.readUsageRunning.CompareAndSwap(false, true) {
+				log.CtxDebugw(ctx, "read usage rollup is running and try again later")
+				continue
+			}
+			go m.rollupReadUsage(ctx)
+		case <-eventDeliveryTicker.C:
+			if !m.eventDeliveryRunning.CompareAndSwap(false, true) {
+				continue
+			}
+			go m.deliverEvents(ctx)
+		case <-vgfBucketNumberTicker.C:
+			if !m.virtualGroupStatsRunning.CompareAndSwap(false, true) {
+				continue
+			}
+			go m.refreshFamilyBucketNumbers(ctx)
 		}
 	}
 }
@@ -304,6 +416,10 @@ func (m *ManageModular) LoadTaskFromDB() error {
 		replicateTask.SetSecondaryAddresses(meta.SecondaryEndpoints)
 		replicateTask.SetSecondarySignatures(meta.SecondarySignatures)
 		replicateTask.GlobalVirtualGroupId = meta.GlobalVirtualGroupID
+		if m.TaskUploading(context.Background(), replicateTask) {
+			// the task has been loaded by the persistent task queue
+			continue
+		}
 		pushErr := m.replicateQueue.Push(replicateTask)
 		if pushErr != nil {
 			log.Errorw("failed to push replicate piece task to queue", "object_info", objectInfo, "error", pushErr)
@@ -335,6 +451,9 @@ func (m *ManageModular) LoadTaskFromDB() error {
 		sealTask := &gfsptask.GfSpSealObjectTask{}
 		sealTask.InitSealObjectTask(meta.GlobalVirtualGroupID, objectInfo, storageParams, m.baseApp.TaskPriority(sealTask),
 			meta.SecondaryEndpoints, meta.SecondarySignatures, m.baseApp.TaskTimeout(sealTask, 0), m.baseApp.TaskMaxRetry(sealTask))
+		if m.TaskUploading(context.Background(), sealTask) {
+			continue
+		}
 		pushErr := m.sealQueue.Push(sealTask)
 		if pushErr != nil {
 			log.Errorw("failed to push seal object task to queue", "object_info", objectInfo, "error", pushErr)
@@ -348,16 +467,26 @@ func (m *ManageModular) LoadTaskFromDB() error {
 		log.Errorw("failed to load gc task from sp db", "error", err)
 		return err
 	}
+	// the gc object task key contains the create time, so the tasks loaded by the persistent task queue
+	// are deduplicated by the block range instead of the task key.
+	queuedGCRanges := make(map[[2]uint64]bool)
+	m.gcObjectQueue.ScanTask(func(queued task.Task) {
+		if gcTask, ok := queued.(task.GCObjectTask); ok {
+			queuedGCRanges[[2]uint64{gcTask.GetStartBlockNumber(), gcTask.GetEndBlockNumber()}] = true
+		}
+	})
 	for _, meta := range gcObjectMetas {
-		gcObjectTask := &gfsptask.GfSpGCObjectTask{}
-		gcObjectTask.InitGCObjectTask(m.baseApp.TaskPriority(gcObjectTask), meta.StartBlockHeight, meta.EndBlockHeight, m.baseApp.TaskTimeout(gcObjectTask, 0))
-		gcObjectTask.SetGCObjectProgress(meta.CurrentBlockHeight, meta.LastDeletedObjectID)
-		pushErr := m.gcObjectQueue.Push(gcObjectTask)
-		if pushErr != nil {
-			log.Errorw("failed to push gc object task to queue", "gc_object_task_meta", meta, "error", pushErr)
-			continue
+		if !queuedGCRanges[[2]uint64{meta.StartBlockHeight, meta.EndBlockHeight}] {
+			gcObjectTask := &gfsptask.GfSpGCObjectTask{}
+			gcObjectTask.InitGCObjectTask(m.baseApp.TaskPriority(gcObjectTask), meta.StartBlockHeight, meta.EndBlockHeight, m.baseApp.TaskTimeout(gcObjectTask, 0))
+			gcObjectTask.SetGCObjectProgress(meta.CurrentBlockHeight, meta.LastDeletedObjectID)
+			pushErr := m.gcObjectQueue.Push(gcObjectTask)
+			if pushErr != nil {
+				log.Errorw("failed to push gc object task to queue", "gc_object_task_meta", meta, "error", pushErr)
+				continue
+			}
+			generateGCOjectTaskCounter++
 		}
-		generateGCOjectTaskCounter++
 		if meta.EndBlockHeight >= m.gcBlockHeight {
 			m.gcBlockHeight = meta.EndBlockHeight + 1
 		}
@@ -493,6 +622,36 @@ func (m *ManageModular) ResetGCObjectTask(qTask task.Task) bool {
 	return false
 }
 
+func (m *ManageModular) GCZombiePieceQueue(qTask task.Task) bool {
+	// the progress of gc zombie piece task has been recorded by the marker, the next
+	// task will continue to scan the piece store from it.
+	return qTask.ExceedTimeout()
+}
+
+func (m *ManageModular) GCMetaQueue(qTask task.Task) bool {
+	// the progress of gc meta task has been recorded in sp db, the next task will
+	// continue to collect the meta from it.
+	return qTask.ExceedTimeout()
+}
+
+func (m *ManageModular) ScrubGVGQueue(qTask task.Task) bool {
+	// the progress of scrub gvg task has been recorded in sp db, the next task will
+	// resume scrubbing the gvg from it.
+	return qTask.ExceedTimeout()
+}
+
+func (m *ManageModular) getGCZombiePieceMarker() string {
+	m.gcZombiePieceMux.Lock()
+	defer m.gcZombiePieceMux.Unlock()
+	return m.gcZombiePieceMarker
+}
+
+func (m *ManageModular) setGCZombiePieceMarker(marker string) {
+	m.gcZombiePieceMux.Lock()
+	defer m.gcZombiePieceMux.Unlock()
+	m.gcZombiePieceMarker = marker
+}
+
 func (m *ManageModular) GCCacheQueue(qTask task.Task) bool {
 	return true
 }
@@ -604,9 +763,9 @@ func (m *ManageModular) RejectUnSealObject(ctx context.Context, object *storaget
 
 func (m *ManageModular) Statistics() string {
 	return fmt.Sprintf(
-		"upload[%d], replicate[%d], seal[%d], receive[%d], recovery[%d] gcObject[%d], gcZombie[%d], gcMeta[%d], download[%d], challenge[%d], migrateGVG[%d], gcBlockHeight[%d], gcSafeDistance[%d]",
+		"upload[%d], replicate[%d], seal[%d], receive[%d], recovery[%d] gcObject[%d], gcZombie[%d], gcMeta[%d], scrubGVG[%d], download[%d], challenge[%d], migrateGVG[%d], gcBlockHeight[%d], gcSafeDistance[%d], gcZombieMarker[%s]",
 		m.uploadQueue.Len(), m.replicateQueue.Len(), m.sealQueue.Len(),
 		m.receiveQueue.Len(), m.recoveryQueue.Len(), m.gcObjectQueue.Len(), m.gcZombieQueue.Len(),
-		m.gcMetaQueue.Len(), m.downloadQueue.Len(), m.challengeQueue.Len(), m.migrateGVGQueue.Len(),
-		m.gcBlockHeight, m.gcSafeBlockDistance)
+		m.gcMetaQueue.Len(), m.scrubGVGQueue.Len(), m.downloadQueue.Len(), m.challengeQueue.Len(), m.migrateGVGQueue.Len(),
+		m.gcBlockHeight, m.gcSafeBlockDistance, m.getGCZombiePieceMarker())
 }
@@ -1,8 +1,14 @@
 package manager
 
 import (
+	"fmt"
+	"sort"
+	"time"
+
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsphealth"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspvgmgr"
 	coremodule "github.com/bnb-chain/greenfield-storage-provider/core/module"
 )
 
@@ -52,6 +58,25 @@ const (
 	// DefaultGlobalGcObjectSafeBlockDistance defines the default distance form current block
 	// height to gc the deleted object.
 	DefaultGlobalGcObjectSafeBlockDistance uint64 = 1000
+	// DefaultGlobalBatchGcZombiePieceInterval defines the default interval for generating
+	// gc zombie piece task.
+	DefaultGlobalBatchGcZombiePieceInterval int = 30 * 60
+	// DefaultGlobalGcZombiePieceLimit defines the default max number of pieces to scan in
+	// a gc zombie piece task.
+	DefaultGlobalGcZombiePieceLimit uint64 = 10000
+	// DefaultGlobalBatchGcMetaTimeInterval defines the default interval for generating
+	// gc meta task.
+	DefaultGlobalBatchGcMetaTimeInterval int = 60 * 60
+	// DefaultGlobalScrubGVGParallel defines the default max parallel scrub gvg in SP system.
+	DefaultGlobalScrubGVGParallel int = 1
+	// DefaultScrubGVGIntervalSec defines the default interval for generating scrub gvg task.
+	DefaultScrubGVGIntervalSec int = 60
+	// DefaultScrubRoundIntervalSec defines the default min interval between two rounds of scrubbing
+	// the same gvg.
+	DefaultScrubRoundIntervalSec int64 = 7 * 24 * 60 * 60
+	// DefaultQueryScrubPieceLimit defines the default max number of the bad pieces returned by querying
+	// the scrub report of a gvg.
+	DefaultQueryScrubPieceLimit uint32 = 100
 	// DefaultGlobalSyncConsensusInfoInterval defines the default interval for sync the sp
 	// info list to sp db.
 	DefaultGlobalSyncConsensusInfoInterval uint64 = 600
@@ -78,6 +103,21 @@ const (
 	DefaultSubscribeBucketMigrateEventIntervalMillisecond = 100
 	// DefaultSubscribeSwapOutEventIntervalMillisecond define the default time interval to subscribe gvg swap out event from metadata.
 	DefaultSubscribeSwapOutEventIntervalMillisecond = 100
+	// DefaultReadUsageRollupIntervalSec defines the default interval of rolling up the read records.
+	DefaultReadUsageRollupIntervalSec = 5 * 60
+	// DefaultReadUsageRetentionDays defines the default days of keeping the rollups of the read records.
+	DefaultReadUsageRetentionDays = 90
+	// DefaultEventDeliveryIntervalMillisecond defines the default interval of delivering the object event
+	// notifications.
+	DefaultEventDeliveryIntervalMillisecond = 1000
+	// DefaultEventDeliveryMaxAttempts defines the default max attempts of delivering a notification.
+	DefaultEventDeliveryMaxAttempts = 10
+	// DefaultEventDeliveryMaxBackoffSec defines the default max interval between the attempts of delivering
+	// a notification.
+	DefaultEventDeliveryMaxBackoffSec = 60 * 60
+	// DefaultVGFBucketNumberRefreshIntervalSec defines the default interval of refreshing the bucket numbers
+	// of the vgfs, which are used by the virtual group pick policies.
+	DefaultVGFBucketNumberRefreshIntervalSec = 60
 )
 
 const (
@@ -152,6 +192,15 @@ func DefaultManagerOptions(manager *ManageModular, cfg *gfspconfig.GfSpConfig) (
 	if cfg.Parallel.GlobalGcObjectSafeBlockDistance == 0 {
 		cfg.Parallel.GlobalGcObjectSafeBlockDistance = DefaultGlobalGcObjectSafeBlockDistance
 	}
+	if cfg.Parallel.GlobalBatchGcZombiePieceInterval == 0 {
+		cfg.Parallel.GlobalBatchGcZombiePieceInterval = DefaultGlobalBatchGcZombiePieceInterval
+	}
+	if cfg.Parallel.GlobalGcZombiePieceLimit == 0 {
+		cfg.Parallel.GlobalGcZombiePieceLimit = DefaultGlobalGcZombiePieceLimit
+	}
+	if cfg.Parallel.GlobalBatchGcMetaTimeInterval == 0 {
+		cfg.Parallel.GlobalBatchGcMetaTimeInterval = DefaultGlobalBatchGcMetaTimeInterval
+	}
 	if cfg.Parallel.GlobalSyncConsensusInfoInterval == 0 {
 		cfg.Parallel.GlobalSyncConsensusInfoInterval = DefaultGlobalSyncConsensusInfoInterval
 	}
@@ -181,6 +230,9 @@ func DefaultManagerOptions(manager *ManageModular, cfg *gfspconfig.GfSpConfig) (
 	manager.gcObjectTimeInterval = cfg.Parallel.GlobalBatchGcObjectTimeInterval
 	manager.gcObjectBlockInterval = cfg.Parallel.GlobalGcObjectBlockInterval
 	manager.gcSafeBlockDistance = cfg.Parallel.GlobalGcObjectSafeBlockDistance
+	manager.gcZombiePieceTimeInterval = cfg.Parallel.GlobalBatchGcZombiePieceInterval
+	manager.gcZombiePieceLimit = cfg.Parallel.GlobalGcZombiePieceLimit
+	manager.gcMetaTimeInterval = cfg.Parallel.GlobalBatchGcMetaTimeInterval
 	manager.syncConsensusInfoInterval = cfg.Parallel.GlobalSyncConsensusInfoInterval
 	manager.discontinueBucketEnabled = cfg.Parallel.DiscontinueBucketEnabled
 	manager.discontinueBucketTimeInterval = cfg.Parallel.DiscontinueBucketTimeInterval
@@ -207,12 +259,45 @@ func DefaultManagerOptions(manager *ManageModular, cfg *gfspconfig.GfSpConfig) (
 		manager.Name()+"-migrate-gvg", cfg.Parallel.GlobalMigrateGVGParallel)
 	manager.gcMetaQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
 		manager.Name()+"-gc-meta", cfg.Parallel.GlobalGCMetaParallel)
+	manager.scrubGVGQueue = cfg.Customize.NewStrategyTQueueWithLimitFunc(
+		manager.Name()+"-scrub-gvg", DefaultGlobalScrubGVGParallel)
 	manager.downloadQueue = cfg.Customize.NewStrategyTQueueFunc(
 		manager.Name()+"-cache-download-object", cfg.Parallel.GlobalDownloadObjectTaskCacheSize)
 	manager.challengeQueue = cfg.Customize.NewStrategyTQueueFunc(
 		manager.Name()+"-cache-challenge-piece", cfg.Parallel.GlobalChallengePieceTaskCacheSize)
 
-	if manager.virtualGroupManager, err = cfg.Customize.NewVirtualGroupManagerFunc(manager.baseApp.OperatorAddress(), manager.baseApp.Consensus()); err != nil {
+	dedicatedFamilies, err := gfspvgmgr.ParseDedicatedFamilies(cfg.Manager.DedicatedVGFAccounts)
+	if err != nil {
+		return err
+	}
+	pickPolicy, err := gfspvgmgr.NewVirtualGroupPickPolicy(&gfspvgmgr.PickPolicyConfig{
+		Name:              cfg.Manager.VirtualGroupPickPolicy,
+		DedicatedFamilies: dedicatedFamilies,
+		MaxBucketNumber:   cfg.Manager.MaxBucketNumberPerVGF,
+	})
+	if err != nil {
+		return err
+	}
+	if pickPolicy.Name() == gfspvgmgr.DedicatedFamilyPolicyName {
+		manager.dedicatedFamilies = dedicatedFamilies
+	}
+	if cfg.Manager.SPHealthMinCallNumber == 0 {
+		cfg.Manager.SPHealthMinCallNumber = gfsphealth.DefaultMinCallNumber
+	}
+	if cfg.Manager.SPUnhealthyScore == 0 {
+		cfg.Manager.SPUnhealthyScore = gfsphealth.DefaultUnhealthyScore
+	}
+	if cfg.Manager.SPUnhealthyScore < 0 || cfg.Manager.SPUnhealthyScore >= 1 {
+		return fmt.Errorf("invalid sp unhealthy score: %v, it should be in (0, 1)", cfg.Manager.SPUnhealthyScore)
+	}
+	if cfg.Manager.SPSlowCallLatencySec == 0 {
+		cfg.Manager.SPSlowCallLatencySec = int64(gfsphealth.DefaultSlowCallLatency / time.Second)
+	}
+	manager.spHealth = gfsphealth.NewTracker(cfg.Manager.SPHealthMinCallNumber, cfg.Manager.SPUnhealthyScore,
+		time.Duration(cfg.Manager.SPSlowCallLatencySec)*time.Second)
+	manager.virtualGroupStats = newVirtualGroupStats(manager.spHealth, manager.querySPEndpoint)
+	if manager.virtualGroupManager, err = cfg.Customize.NewVirtualGroupManagerFunc(manager.baseApp.OperatorAddress(),
+		manager.baseApp.Consensus(), pickPolicy, manager.virtualGroupStats); err != nil {
 		return err
 	}
 	if cfg.Manager.SubscribeSPExitEventIntervalSec == 0 {
@@ -226,5 +311,48 @@ func DefaultManagerOptions(manager *ManageModular, cfg *gfspconfig.GfSpConfig) (
 	}
 	manager.gvgPreferSPList = cfg.Manager.GVGPreferSPList
 
+	if cfg.Manager.ReadUsageRollupIntervalSec == 0 {
+		cfg.Manager.ReadUsageRollupIntervalSec = DefaultReadUsageRollupIntervalSec
+	}
+	if cfg.Manager.ReadUsageRetentionDays == 0 {
+		cfg.Manager.ReadUsageRetentionDays = DefaultReadUsageRetentionDays
+	}
+	manager.readUsageRollupInterval = cfg.Manager.ReadUsageRollupIntervalSec
+	manager.readUsageRetentionDays = cfg.Manager.ReadUsageRetentionDays
+	manager.readQuotaAlertWebhook = cfg.Manager.ReadQuotaAlertWebhook
+	for _, threshold := range cfg.Manager.ReadQuotaAlertThresholds {
+		if threshold == 0 || threshold > 100 {
+			return fmt.Errorf("invalid read quota alert threshold: %d, it should be in (0, 100]", threshold)
+		}
+		manager.readQuotaAlertThresholds = append(manager.readQuotaAlertThresholds, threshold)
+	}
+	sort.Slice(manager.readQuotaAlertThresholds, func(i, j int) bool {
+		return manager.readQuotaAlertThresholds[i] < manager.readQuotaAlertThresholds[j]
+	})
+
+	if cfg.Manager.EventDeliveryIntervalMillisecond == 0 {
+		cfg.Manager.EventDeliveryIntervalMillisecond = DefaultEventDeliveryIntervalMillisecond
+	}
+	if cfg.Manager.EventDeliveryMaxAttempts == 0 {
+		cfg.Manager.EventDeliveryMaxAttempts = DefaultEventDeliveryMaxAttempts
+	}
+	if cfg.Manager.EventDeliveryMaxBackoffSec == 0 {
+		cfg.Manager.EventDeliveryMaxBackoffSec = DefaultEventDeliveryMaxBackoffSec
+	}
+	manager.eventDeliveryInterval = cfg.Manager.EventDeliveryIntervalMillisecond
+	manager.eventDeliveryMaxAttempts = cfg.Manager.EventDeliveryMaxAttempts
+	manager.eventDeliveryMaxBackoff = time.Duration(cfg.Manager.EventDeliveryMaxBackoffSec) * time.Second
+	manager.eventClient = newEventClient()
+
+	if cfg.Manager.ScrubGVGIntervalSec == 0 {
+		cfg.Manager.ScrubGVGIntervalSec = DefaultScrubGVGIntervalSec
+	}
+	if cfg.Manager.ScrubRoundIntervalSec == 0 {
+		cfg.Manager.ScrubRoundIntervalSec = DefaultScrubRoundIntervalSec
+	}
+	manager.scrubGVGEnabled = cfg.Manager.EnableScrubGVG
+	manager.scrubGVGTimeInterval = cfg.Manager.ScrubGVGIntervalSec
+	manager.scrubRoundInterval = cfg.Manager.ScrubRoundIntervalSec
+
 	return nil
 }
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptqueue"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/core/task"
+)
+
+func TestManageModular_LoadTaskFromDBSkipQueuedGCObjectTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := spdb.NewMockSPDB(ctrl)
+	db.EXPECT().GetUploadMetasToReplicate(gomock.Any(), gomock.Any()).Return(nil, nil)
+	db.EXPECT().GetUploadMetasToSeal(gomock.Any(), gomock.Any()).Return(nil, nil)
+	db.EXPECT().GetGCMetasToGC(gomock.Any()).Return([]*spdb.GCObjectMeta{
+		{StartBlockHeight: 1, EndBlockHeight: 10, CurrentBlockHeight: 5},
+		{StartBlockHeight: 11, EndBlockHeight: 20},
+	}, nil)
+	baseApp, err := gfspapp.NewGfSpBaseApp(&gfspconfig.GfSpConfig{}, gfspconfig.CustomizeGfSpDB(db))
+	require.NoError(t, err)
+	m := &ManageModular{
+		baseApp:              baseApp,
+		enableLoadTask:       true,
+		uploadQueue:          gfsptqueue.NewGfSpTQueue("upload_test", 10),
+		resumableUploadQueue: gfsptqueue.NewGfSpTQueue("resumable_upload_test", 10),
+		replicateQueue:       gfsptqueue.NewGfSpTQueueWithLimit("replicate_test", 10),
+		sealQueue:            gfsptqueue.NewGfSpTQueueWithLimit("seal_test", 10),
+		gcObjectQueue:        gfsptqueue.NewGfSpTQueueWithLimit("gc_object_test", 10),
+	}
+
+	// the task of the first block range has been loaded by the persistent task queue with the other create time
+	loaded := &gfsptask.GfSpGCObjectTask{}
+	loaded.InitGCObjectTask(0, 1, 10, 0)
+	loaded.SetCreateTime(1)
+	require.NoError(t, m.gcObjectQueue.Push(loaded))
+
+	require.NoError(t, m.LoadTaskFromDB())
+	var ranges [][2]uint64
+	m.gcObjectQueue.ScanTask(func(queued task.Task) {
+		gcTask := queued.(task.GCObjectTask)
+		ranges = append(ranges, [2]uint64{gcTask.GetStartBlockNumber(), gcTask.GetEndBlockNumber()})
+	})
+	assert.ElementsMatch(t, [][2]uint64{{1, 10}, {11, 20}}, ranges)
+	assert.Equal(t, uint64(21), m.gcBlockHeight)
+}
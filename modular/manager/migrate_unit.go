@@ -3,6 +3,7 @@ package manager
 import (
 	"fmt"
 
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	sptypes "github.com/bnb-chain/greenfield/x/sp/types"
 	virtualgrouptypes "github.com/bnb-chain/greenfield/x/virtualgroup/types"
 )
@@ -11,9 +12,9 @@ type MigrateStatus int32
 
 // migrate: WaitForMigrate(created)->Migrating(schedule success)->Migrated(executor report success).
 var (
-	WaitForMigrate MigrateStatus = 0
-	Migrating      MigrateStatus = 1
-	Migrated       MigrateStatus = 2
+	WaitForMigrate MigrateStatus = spdb.MigrateStatusWaitForMigrate
+	Migrating      MigrateStatus = spdb.MigrateStatusMigrating
+	Migrated       MigrateStatus = spdb.MigrateStatusMigrated
 )
 
 type basicGVGMigrateExecuteUnit struct {
@@ -0,0 +1,160 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+// readQuotaAlertTimeout defines the timeout of posting a read quota alert to the webhook.
+const readQuotaAlertTimeout = 10 * time.Second
+
+// ReadQuotaAlert is the json body posted to the read quota alert webhook when a bucket crosses a threshold.
+type ReadQuotaAlert struct {
+	BucketID         uint64 `json:"bucket_id"`
+	BucketName       string `json:"bucket_name"`
+	ThresholdPercent uint32 `json:"threshold_percent"`
+	ConsumedPercent  uint32 `json:"consumed_percent"`
+	ReadConsumedSize uint64 `json:"read_consumed_size"`
+	FreeQuotaSize    uint64 `json:"free_quota_size"`
+	ChargedQuotaSize uint64 `json:"charged_quota_size"`
+	// ProjectedExhaustionTimestamp is the projected unix timestamp when the read quota is exhausted by the
+	// read rate of the last 24 hours, 0 means the bucket is not read recently or the read quota is not
+	// exhausted before the monthly reset.
+	ProjectedExhaustionTimestamp int64 `json:"projected_exhaustion_timestamp"`
+	Timestamp                    int64 `json:"timestamp"`
+}
+
+// rollupReadUsage rolls up the read records since the last rollup, and notifies the buckets which cross the
+// thresholds of the read quota.
+func (m *ManageModular) rollupReadUsage(ctx context.Context) {
+	defer m.readUsageRunning.Store(false)
+	var (
+		now   = time.Now()
+		nowUs = now.UnixMicro()
+		// the read records of the previous hour may be added after the last rollup, so it is rolled up again
+		startUs = nowUs - int64(m.readUsageRollupInterval)*int64(time.Second/time.Microsecond) -
+			spdb.ReadUsageHourUs
+		db = m.baseApp.GfSpDB()
+	)
+	startUs -= startUs % spdb.ReadUsageHourUs
+	if err := db.RollupReadRecord(startUs, nowUs); err != nil {
+		log.CtxErrorw(ctx, "failed to rollup read record", "start_timestamp_us", startUs, "error", err)
+		return
+	}
+	expiredUs := now.AddDate(0, 0, -m.readUsageRetentionDays).UnixMicro()
+	if err := db.DeleteExpiredReadUsage(expiredUs); err != nil {
+		log.CtxErrorw(ctx, "failed to delete expired read usage", "error", err)
+	}
+	if m.readQuotaAlertWebhook == "" || len(m.readQuotaAlertThresholds) == 0 {
+		return
+	}
+
+	// only the buckets which are read since the last rollup may cross the thresholds
+	usages, err := db.GetReadUsage(&spdb.ReadUsageQuery{
+		Dimension:        spdb.ReadUsageByBucket,
+		StartTimestampUs: startUs,
+		EndTimestampUs:   nowUs,
+	})
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get read usage of buckets", "error", err)
+		return
+	}
+	for _, usage := range usages {
+		if err = m.checkReadQuota(ctx, usage.BucketID, now); err != nil {
+			log.CtxErrorw(ctx, "failed to check read quota of bucket", "bucket_id", usage.BucketID, "error", err)
+		}
+	}
+}
+
+// checkReadQuota posts an alert if the bucket crosses a higher threshold than the notified one. The notified
+// threshold is lowered if the consumed percent drops, e.g. the charged quota is increased, then the bucket
+// is notified again when it crosses the threshold.
+func (m *ManageModular) checkReadQuota(ctx context.Context, bucketID uint64, now time.Time) error {
+	db := m.baseApp.GfSpDB()
+	traffic, err := db.GetBucketTraffic(bucketID)
+	if err != nil {
+		return err
+	}
+	notified, err := db.GetReadQuotaAlertPercent(bucketID)
+	if err != nil {
+		return err
+	}
+	consumedPercent := traffic.ConsumedPercent()
+	var crossed uint32
+	for _, threshold := range m.readQuotaAlertThresholds {
+		if consumedPercent >= threshold {
+			crossed = threshold
+		}
+	}
+	if crossed == notified {
+		return nil
+	}
+	if crossed > notified {
+		alert := &ReadQuotaAlert{
+			BucketID:         traffic.BucketID,
+			BucketName:       traffic.BucketName,
+			ThresholdPercent: crossed,
+			ConsumedPercent:  consumedPercent,
+			ReadConsumedSize: traffic.ReadConsumedSize,
+			FreeQuotaSize:    traffic.FreeQuotaSize,
+			ChargedQuotaSize: traffic.ChargedQuotaSize,
+			Timestamp:        now.Unix(),
+		}
+		if exhaustion, ok := m.projectReadQuotaExhaustion(traffic, now); ok {
+			alert.ProjectedExhaustionTimestamp = exhaustion.Unix()
+		}
+		// the notified threshold is not updated if the alert fails, so it is posted again at the next rollup
+		if err = m.postReadQuotaAlert(ctx, alert); err != nil {
+			return err
+		}
+		log.CtxInfow(ctx, "succeed to post read quota alert", "alert", alert)
+	}
+	return db.UpdateReadQuotaAlertPercent(bucketID, crossed)
+}
+
+// projectReadQuotaExhaustion projects when the read quota of the bucket is exhausted by the read rate of the
+// recent window.
+func (m *ManageModular) projectReadQuotaExhaustion(traffic *spdb.BucketTraffic, now time.Time) (time.Time, bool) {
+	windowStart := spdb.ReadQuotaProjectionStart(now)
+	window := now.Sub(windowStart)
+	usages, err := m.baseApp.GfSpDB().GetReadUsage(&spdb.ReadUsageQuery{
+		Dimension:        spdb.ReadUsageByBucket,
+		BucketID:         traffic.BucketID,
+		StartTimestampUs: windowStart.UnixMicro(),
+		EndTimestampUs:   now.UnixMicro(),
+	})
+	if err != nil || len(usages) == 0 {
+		return traffic.ProjectQuotaExhaustion(0, window, now)
+	}
+	return traffic.ProjectQuotaExhaustion(usages[0].ReadSize, window, now)
+}
+
+func (m *ManageModular) postReadQuotaAlert(ctx context.Context, alert *ReadQuotaAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, readQuotaAlertTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.readQuotaAlertWebhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to post read quota alert, status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+func TestCheckReadQuota(t *testing.T) {
+	now := time.Date(2023, 6, 25, 12, 0, 0, 0, time.UTC)
+	monthStart := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		now            time.Time
+		consumedSize   uint64
+		recentReadSize uint64
+		windowStart    time.Time
+		notified       uint32
+		webhookStatus  int
+		wantedAlert    *ReadQuotaAlert
+		wantedNotified *uint32
+		wantedErr      bool
+	}{
+		{name: "below the thresholds", consumedSize: 10},
+		{name: "cross the first threshold", consumedSize: 60, recentReadSize: 20, windowStart: now.Add(-24 * time.Hour),
+			webhookStatus: http.StatusOK, wantedAlert: &ReadQuotaAlert{ThresholdPercent: 50, ConsumedPercent: 60,
+				ReadConsumedSize: 60, ProjectedExhaustionTimestamp: now.Add(48 * time.Hour).Unix()},
+			wantedNotified: newUint32(50)},
+		{name: "window starts at the month start", now: monthStart.Add(6 * time.Hour), consumedSize: 60,
+			recentReadSize: 10, windowStart: monthStart, webhookStatus: http.StatusOK,
+			wantedAlert: &ReadQuotaAlert{ThresholdPercent: 50, ConsumedPercent: 60, ReadConsumedSize: 60,
+				ProjectedExhaustionTimestamp: monthStart.Add(30 * time.Hour).Unix()},
+			wantedNotified: newUint32(50)},
+		{name: "exhausted after the monthly reset", consumedSize: 85, recentReadSize: 1, notified: 50,
+			webhookStatus: http.StatusOK, wantedAlert: &ReadQuotaAlert{ThresholdPercent: 80, ConsumedPercent: 85,
+				ReadConsumedSize: 85}, wantedNotified: newUint32(80)},
+		{name: "exhausted", consumedSize: 100, notified: 80, webhookStatus: http.StatusOK,
+			wantedAlert: &ReadQuotaAlert{ThresholdPercent: 100, ConsumedPercent: 100, ReadConsumedSize: 100,
+				ProjectedExhaustionTimestamp: now.Unix()},
+			wantedNotified: newUint32(100)},
+		{name: "threshold notified", consumedSize: 70, notified: 50},
+		{name: "consumed percent drops", consumedSize: 10, notified: 80, wantedNotified: newUint32(0)},
+		{name: "webhook fails", consumedSize: 60, webhookStatus: http.StatusInternalServerError,
+			wantedAlert: &ReadQuotaAlert{ThresholdPercent: 50, ConsumedPercent: 60, ReadConsumedSize: 60},
+			wantedErr:   true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.now.IsZero() {
+				tt.now = now
+			}
+			var alert *ReadQuotaAlert
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				alert = &ReadQuotaAlert{}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(alert))
+				w.WriteHeader(tt.webhookStatus)
+			}))
+			defer server.Close()
+
+			ctrl := gomock.NewController(t)
+			db := spdb.NewMockSPDB(ctrl)
+			db.EXPECT().GetBucketTraffic(uint64(1)).Return(&spdb.BucketTraffic{BucketID: 1, BucketName: "bucket",
+				ReadConsumedSize: tt.consumedSize, FreeQuotaSize: 40, ChargedQuotaSize: 60}, nil)
+			db.EXPECT().GetReadQuotaAlertPercent(uint64(1)).Return(tt.notified, nil)
+			db.EXPECT().GetReadUsage(gomock.Any()).DoAndReturn(func(query *spdb.ReadUsageQuery) ([]*spdb.ReadUsage, error) {
+				if !tt.windowStart.IsZero() {
+					assert.Equal(t, tt.windowStart.UnixMicro(), query.StartTimestampUs)
+				}
+				return []*spdb.ReadUsage{{ReadSize: tt.recentReadSize}}, nil
+			}).AnyTimes()
+			if tt.wantedNotified != nil {
+				db.EXPECT().UpdateReadQuotaAlertPercent(uint64(1), *tt.wantedNotified).Return(nil)
+			}
+			baseApp, err := gfspapp.NewGfSpBaseApp(&gfspconfig.GfSpConfig{}, gfspconfig.CustomizeGfSpDB(db))
+			require.NoError(t, err)
+			m := &ManageModular{
+				baseApp:                  baseApp,
+				readQuotaAlertThresholds: []uint32{50, 80, 100},
+				readQuotaAlertWebhook:    server.URL,
+			}
+
+			err = m.checkReadQuota(context.Background(), 1, tt.now)
+			assert.Equal(t, tt.wantedErr, err != nil)
+			if tt.wantedAlert != nil {
+				tt.wantedAlert.BucketID = 1
+				tt.wantedAlert.BucketName = "bucket"
+				tt.wantedAlert.FreeQuotaSize = 40
+				tt.wantedAlert.ChargedQuotaSize = 60
+				tt.wantedAlert.Timestamp = tt.now.Unix()
+			}
+			assert.Equal(t, tt.wantedAlert, alert)
+		})
+	}
+}
+
+func TestCheckReadQuota_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	db := spdb.NewMockSPDB(ctrl)
+	db.EXPECT().GetBucketTraffic(uint64(1)).Return(nil, errors.New("mock error"))
+	baseApp, err := gfspapp.NewGfSpBaseApp(&gfspconfig.GfSpConfig{}, gfspconfig.CustomizeGfSpDB(db))
+	require.NoError(t, err)
+	m := &ManageModular{baseApp: baseApp, readQuotaAlertThresholds: []uint32{50}}
+	assert.Error(t, m.checkReadQuota(context.Background(), 1, time.Now()))
+}
+
+func newUint32(v uint32) *uint32 {
+	return &v
+}
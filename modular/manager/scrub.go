@@ -0,0 +1,157 @@
+package manager
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+// scheduleScrubGVG picks the gvg to scrub and generates the scrub gvg task, the unfinished round is resumed
+// first, then the gvgs that have never been scrubbed, then the gvg whose last round is the oldest.
+func (m *ManageModular) scheduleScrubGVG(ctx context.Context) {
+	defer m.scrubScheduleRunning.Store(false)
+	progress, err := m.pickScrubGVG(ctx)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to pick gvg to scrub and try again later", "error", err)
+		return
+	}
+	if progress == nil {
+		log.CtxDebugw(ctx, "no gvg needs to scrub and try again later")
+		return
+	}
+	if progress.Finished || progress.StartTimestampSecond == 0 {
+		// start a new round, the bad pieces found by the last round are dropped
+		if err = m.baseApp.GfSpDB().DeleteScrubPieces(progress.GlobalVirtualGroupID); err != nil {
+			log.CtxErrorw(ctx, "failed to delete scrub pieces of the last round", "gvg_id",
+				progress.GlobalVirtualGroupID, "error", err)
+			return
+		}
+		progress = &spdb.ScrubProgress{
+			GlobalVirtualGroupID: progress.GlobalVirtualGroupID,
+			RedundancyIndex:      progress.RedundancyIndex,
+			StartTimestampSecond: time.Now().Unix(),
+		}
+		if err = m.baseApp.GfSpDB().UpdateScrubProgress(progress); err != nil {
+			log.CtxErrorw(ctx, "failed to init scrub progress", "gvg_id", progress.GlobalVirtualGroupID, "error", err)
+			return
+		}
+	}
+	scrubTask := &gfsptask.GfSpScrubGVGTask{}
+	scrubTask.InitScrubGVGTask(m.baseApp.TaskPriority(scrubTask), progress.GlobalVirtualGroupID,
+		progress.RedundancyIndex, progress.LastObjectID, m.baseApp.TaskTimeout(scrubTask, 0))
+	scrubTask.SetScrubStatus(progress.ScrubbedObjectCount, progress.ScrubbedPieceCount,
+		progress.CorruptedPieceCount, progress.MissingPieceCount)
+	err = m.scrubGVGQueue.Push(scrubTask)
+	log.CtxInfow(ctx, "generate a scrub gvg task", "task_info", scrubTask.Info(), "error", err)
+}
+
+// pickScrubGVG returns the progress of the gvg to scrub, returns nil if all the gvgs are scrubbed within the
+// round interval. The gvgs include the ones whose primary sp is the sp and the ones whose secondary sps
+// contain the sp.
+func (m *ManageModular) pickScrubGVG(ctx context.Context) (*spdb.ScrubProgress, error) {
+	spID, err := m.getSPID()
+	if err != nil {
+		return nil, err
+	}
+	families, err := m.baseApp.GfSpClient().ListVirtualGroupFamiliesSpID(ctx, spID)
+	if err != nil {
+		return nil, err
+	}
+	secondaryGVGs, err := m.baseApp.GfSpClient().ListGlobalVirtualGroupsBySecondarySP(ctx, spID)
+	if err != nil {
+		return nil, err
+	}
+	progresses, err := m.baseApp.GfSpDB().ListScrubProgresses()
+	if err != nil {
+		return nil, err
+	}
+
+	redundancyIndexes := make(map[uint32]int32)
+	for _, family := range families {
+		for _, gvgID := range family.GetGlobalVirtualGroupIds() {
+			redundancyIndexes[gvgID] = -1
+		}
+	}
+	for _, gvg := range secondaryGVGs {
+		for idx, secondarySPID := range gvg.GetSecondarySpIds() {
+			if secondarySPID == spID {
+				redundancyIndexes[gvg.GetId()] = int32(idx)
+			}
+		}
+	}
+	progressMap := make(map[uint32]*spdb.ScrubProgress, len(progresses))
+	for _, progress := range progresses {
+		progressMap[progress.GlobalVirtualGroupID] = progress
+	}
+	gvgIDs := make([]uint32, 0, len(redundancyIndexes))
+	for gvgID := range redundancyIndexes {
+		gvgIDs = append(gvgIDs, gvgID)
+	}
+	sort.Slice(gvgIDs, func(i, j int) bool { return gvgIDs[i] < gvgIDs[j] })
+
+	var (
+		picked  *spdb.ScrubProgress
+		expired = time.Now().Unix() - m.scrubRoundInterval
+	)
+	for _, gvgID := range gvgIDs {
+		redundancyIdx := redundancyIndexes[gvgID]
+		progress, ok := progressMap[gvgID]
+		if !ok || progress.RedundancyIndex != redundancyIdx {
+			// never scrubbed, or the sp serves the gvg in a different role since the last round
+			if picked == nil || picked.StartTimestampSecond != 0 {
+				picked = &spdb.ScrubProgress{GlobalVirtualGroupID: gvgID, RedundancyIndex: redundancyIdx}
+			}
+			continue
+		}
+		if !progress.Finished {
+			return progress, nil
+		}
+		if progress.UpdateTimestampSecond > expired {
+			continue
+		}
+		if picked == nil || (picked.StartTimestampSecond != 0 &&
+			progress.UpdateTimestampSecond < picked.UpdateTimestampSecond) {
+			picked = progress
+		}
+	}
+	return picked, nil
+}
+
+// updateScrubProgress records the progress of the scrub gvg task, which is resumed by the next task if the
+// task is failed or timeout.
+func (m *ManageModular) updateScrubProgress(scrubTask task.ScrubGVGTask) error {
+	progress, err := m.baseApp.GfSpDB().QueryScrubProgress(scrubTask.GetGvgId())
+	if err != nil {
+		return err
+	}
+	if progress == nil {
+		progress = &spdb.ScrubProgress{GlobalVirtualGroupID: scrubTask.GetGvgId()}
+	}
+	progress.RedundancyIndex = scrubTask.GetRedundancyIdx()
+	progress.LastObjectID = scrubTask.GetLastScrubbedObjectId()
+	progress.ScrubbedObjectCount, progress.ScrubbedPieceCount, progress.CorruptedPieceCount,
+		progress.MissingPieceCount = scrubTask.GetScrubStatus()
+	progress.Finished = scrubTask.GetFinished()
+	return m.baseApp.GfSpDB().UpdateScrubProgress(progress)
+}
+
+func toScrubGVGReport(progress *spdb.ScrubProgress) *gfspserver.GfSpScrubGVGReport {
+	return &gfspserver.GfSpScrubGVGReport{
+		GvgId:                progress.GlobalVirtualGroupID,
+		RedundancyIdx:        progress.RedundancyIndex,
+		LastScrubbedObjectId: progress.LastObjectID,
+		ScrubbedObjectCount:  progress.ScrubbedObjectCount,
+		ScrubbedPieceCount:   progress.ScrubbedPieceCount,
+		CorruptedPieceCount:  progress.CorruptedPieceCount,
+		MissingPieceCount:    progress.MissingPieceCount,
+		Finished:             progress.Finished,
+		StartTimestampSec:    progress.StartTimestampSecond,
+		UpdateTimestampSec:   progress.UpdateTimestampSecond,
+	}
+}
@@ -0,0 +1,208 @@
+package manager
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptqueue"
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/core/task"
+	metadatatypes "github.com/bnb-chain/greenfield-storage-provider/modular/metadata/types"
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+	"github.com/bnb-chain/greenfield-storage-provider/store/sqldb"
+	virtualgrouptypes "github.com/bnb-chain/greenfield/x/virtualgroup/types"
+)
+
+const scrubTestSPID = 1
+
+// scrubTestMetadata serves the gvgs whose primary sp is the sp by families, and the gvgs whose secondary sps
+// contain the sp.
+type scrubTestMetadata struct {
+	metadatatypes.UnimplementedGfSpMetadataServiceServer
+
+	families      []*virtualgrouptypes.GlobalVirtualGroupFamily
+	secondaryGVGs []*virtualgrouptypes.GlobalVirtualGroup
+}
+
+func (s *scrubTestMetadata) GfSpListVirtualGroupFamiliesBySpID(context.Context,
+	*metadatatypes.GfSpListVirtualGroupFamiliesBySpIDRequest) (*metadatatypes.GfSpListVirtualGroupFamiliesBySpIDResponse, error) {
+	return &metadatatypes.GfSpListVirtualGroupFamiliesBySpIDResponse{GlobalVirtualGroupFamilies: s.families}, nil
+}
+
+func (s *scrubTestMetadata) GfSpListGlobalVirtualGroupsBySecondarySP(context.Context,
+	*metadatatypes.GfSpListGlobalVirtualGroupsBySecondarySPRequest) (*metadatatypes.GfSpListGlobalVirtualGroupsBySecondarySPResponse, error) {
+	return &metadatatypes.GfSpListGlobalVirtualGroupsBySecondarySPResponse{Groups: s.secondaryGVGs}, nil
+}
+
+// setupScrubTest serves the gvgs 1, 2 and 3 as the primary sp, and the gvg 4 as the second secondary sp.
+func setupScrubTest(t *testing.T, db spdb.SPDB) *ManageModular {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	metadatatypes.RegisterGfSpMetadataServiceServer(grpcServer, &scrubTestMetadata{
+		families: []*virtualgrouptypes.GlobalVirtualGroupFamily{
+			{Id: 1, PrimarySpId: scrubTestSPID, GlobalVirtualGroupIds: []uint32{3, 1}},
+			{Id: 2, PrimarySpId: scrubTestSPID, GlobalVirtualGroupIds: []uint32{2}},
+		},
+		secondaryGVGs: []*virtualgrouptypes.GlobalVirtualGroup{
+			{Id: 4, PrimarySpId: 2, SecondarySpIds: []uint32{3, scrubTestSPID, 4}},
+		},
+	})
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	cfg := &gfspconfig.GfSpConfig{GRPCAddress: listener.Addr().String()}
+	baseApp, err := gfspapp.NewGfSpBaseApp(cfg, gfspconfig.CustomizeGfSpDB(db))
+	require.NoError(t, err)
+	return &ManageModular{
+		baseApp:            baseApp,
+		spID:               scrubTestSPID,
+		scrubRoundInterval: 3600,
+		scrubGVGQueue:      gfsptqueue.NewGfSpTQueueWithLimit("scrub_gvg_test", 10),
+	}
+}
+
+// queuedScrubTask returns the only scrub gvg task in the queue.
+func queuedScrubTask(t *testing.T, m *ManageModular) task.ScrubGVGTask {
+	var tasks []task.Task
+	m.scrubGVGQueue.ScanTask(func(queued task.Task) { tasks = append(tasks, queued) })
+	require.Len(t, tasks, 1)
+	return tasks[0].(task.ScrubGVGTask)
+}
+
+func TestManageModular_PickScrubGVG(t *testing.T) {
+	now := time.Now().Unix()
+	finished := func(gvgID uint32, redundancyIdx int32, updateTime int64) *spdb.ScrubProgress {
+		return &spdb.ScrubProgress{GlobalVirtualGroupID: gvgID, RedundancyIndex: redundancyIdx, Finished: true,
+			StartTimestampSecond: updateTime - 60, UpdateTimestampSecond: updateTime}
+	}
+	cases := []struct {
+		name       string
+		progresses []*spdb.ScrubProgress
+		expected   *spdb.ScrubProgress
+	}{
+		{
+			name:       "never scrubbed gvg with the smallest id",
+			progresses: nil,
+			expected:   &spdb.ScrubProgress{GlobalVirtualGroupID: 1, RedundancyIndex: -1},
+		},
+		{
+			name: "unfinished round first",
+			progresses: []*spdb.ScrubProgress{
+				finished(1, -1, now-7200),
+				{GlobalVirtualGroupID: 3, RedundancyIndex: -1, LastObjectID: 100, StartTimestampSecond: now},
+			},
+			expected: &spdb.ScrubProgress{GlobalVirtualGroupID: 3, RedundancyIndex: -1, LastObjectID: 100,
+				StartTimestampSecond: now},
+		},
+		{
+			name: "never scrubbed gvg before expired round",
+			progresses: []*spdb.ScrubProgress{
+				finished(1, -1, now-7200),
+				finished(3, -1, now-7200),
+				finished(4, 1, now-7200),
+			},
+			expected: &spdb.ScrubProgress{GlobalVirtualGroupID: 2, RedundancyIndex: -1},
+		},
+		{
+			name: "oldest expired round",
+			progresses: []*spdb.ScrubProgress{
+				finished(1, -1, now-7200),
+				finished(2, -1, now-60),
+				finished(3, -1, now-9000),
+				finished(4, 1, now-8000),
+			},
+			expected: finished(3, -1, now-9000),
+		},
+		{
+			name: "secondary gvg whose role is changed",
+			progresses: []*spdb.ScrubProgress{
+				finished(1, -1, now-60),
+				finished(2, -1, now-60),
+				finished(3, -1, now-60),
+				finished(4, -1, now-60),
+			},
+			expected: &spdb.ScrubProgress{GlobalVirtualGroupID: 4, RedundancyIndex: 1},
+		},
+		{
+			name: "all gvgs scrubbed within round interval",
+			progresses: []*spdb.ScrubProgress{
+				finished(1, -1, now-60),
+				finished(2, -1, now-60),
+				finished(3, -1, now-60),
+				finished(4, 1, now-60),
+			},
+			expected: nil,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			db := spdb.NewMockSPDB(ctrl)
+			db.EXPECT().ListScrubProgresses().Return(tt.progresses, nil)
+			m := setupScrubTest(t, db)
+
+			progress, err := m.pickScrubGVG(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, progress)
+		})
+	}
+}
+
+func TestManageModular_UpdateScrubProgressResume(t *testing.T) {
+	t.Setenv(sqldb.SpDBDriver, dialect.SQLiteDriver)
+	t.Setenv(sqldb.SpDBDataBase, filepath.Join(t.TempDir(), "sp.db"))
+	db, err := sqldb.NewSpDB(&config.SQLDBConfig{})
+	require.NoError(t, err)
+	m := setupScrubTest(t, db)
+	ctx := context.Background()
+
+	// the first round starts from the gvg 1
+	m.scheduleScrubGVG(ctx)
+	scrubTask := queuedScrubTask(t, m)
+	assert.Equal(t, uint32(1), scrubTask.GetGvgId())
+	assert.Equal(t, uint64(0), scrubTask.GetLastScrubbedObjectId())
+
+	// the task fails after scrubbing some objects, the next task resumes from the last scrubbed object
+	scrubTask.SetLastScrubbedObjectId(100)
+	scrubTask.SetScrubStatus(10, 20, 1, 2)
+	require.NoError(t, m.updateScrubProgress(scrubTask))
+	m.scrubGVGQueue.PopByKey(scrubTask.Key())
+	m.scheduleScrubGVG(ctx)
+	resumed := queuedScrubTask(t, m)
+	assert.Equal(t, uint32(1), resumed.GetGvgId())
+	assert.Equal(t, int32(-1), resumed.GetRedundancyIdx())
+	assert.Equal(t, uint64(100), resumed.GetLastScrubbedObjectId())
+	objectNumber, pieceNumber, corruptedNumber, missingNumber := resumed.GetScrubStatus()
+	assert.Equal(t, []uint64{10, 20, 1, 2}, []uint64{objectNumber, pieceNumber, corruptedNumber, missingNumber})
+
+	// the bad pieces of the finished round are kept until the next round of the gvg starts
+	require.NoError(t, db.UpdateScrubPiece(&spdb.ScrubPiece{GlobalVirtualGroupID: 1, PieceKey: "s100_s0",
+		State: spdb.ScrubPieceCorrupted}))
+	resumed.SetFinished(true)
+	require.NoError(t, m.updateScrubProgress(resumed))
+	m.scrubGVGQueue.PopByKey(resumed.Key())
+	progress, err := db.QueryScrubProgress(1)
+	require.NoError(t, err)
+	assert.True(t, progress.Finished)
+	pieces, err := db.ListScrubPieces(1, 10)
+	require.NoError(t, err)
+	assert.Len(t, pieces, 1)
+
+	// the next round starts from the never scrubbed gvg
+	m.scheduleScrubGVG(ctx)
+	next := queuedScrubTask(t, m)
+	assert.Equal(t, uint32(2), next.GetGvgId())
+	assert.Equal(t, uint64(0), next.GetLastScrubbedObjectId())
+}
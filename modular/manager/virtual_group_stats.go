@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsphealth"
+	"github.com/bnb-chain/greenfield-storage-provider/core/vgmgr"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+var _ vgmgr.VirtualGroupStats = &virtualGroupStats{}
+
+// virtualGroupStats implements the vgmgr.VirtualGroupStats by the bucket numbers of the vgfs which are refreshed
+// from the metadata service and the sp healths which are tracked by the calls reported by executor.
+type virtualGroupStats struct {
+	mux           sync.RWMutex
+	bucketNumbers map[uint32]uint64
+	spHealth      *gfsphealth.Tracker
+	spEndpoint    func(spID uint32) (string, error)
+}
+
+func newVirtualGroupStats(spHealth *gfsphealth.Tracker, spEndpoint func(spID uint32) (string, error)) *virtualGroupStats {
+	return &virtualGroupStats{
+		bucketNumbers: make(map[uint32]uint64),
+		spHealth:      spHealth,
+		spEndpoint:    spEndpoint,
+	}
+}
+
+// FamilyBucketCount returns the number of the buckets in the vgf.
+func (s *virtualGroupStats) FamilyBucketCount(vgfID uint32) uint64 {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.bucketNumbers[vgfID]
+}
+
+// SPHealthScore returns the health score of the secondary sp which is scored by the calls measured by executor.
+func (s *virtualGroupStats) SPHealthScore(spID uint32) (float64, bool) {
+	endpoint, err := s.spEndpoint(spID)
+	if err != nil {
+		return 0, false
+	}
+	if _, ok := s.spHealth.Health(endpoint); !ok {
+		return 0, false
+	}
+	return s.spHealth.Score(endpoint), true
+}
+
+// addFamilyBucket counts the bucket which the vgf is picked for until the bucket numbers are refreshed.
+func (s *virtualGroupStats) addFamilyBucket(vgfID uint32) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.bucketNumbers[vgfID]++
+}
+
+func (s *virtualGroupStats) setFamilyBucketNumbers(bucketNumbers map[uint32]int64) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.bucketNumbers = make(map[uint32]uint64, len(bucketNumbers))
+	for vgfID, number := range bucketNumbers {
+		s.bucketNumbers[vgfID] = uint64(number)
+	}
+}
+
+// refreshFamilyBucketNumbers refreshes the bucket numbers of the vgfs of the sp from the metadata service.
+func (m *ManageModular) refreshFamilyBucketNumbers(ctx context.Context) {
+	defer m.virtualGroupStatsRunning.Store(false)
+	spID, err := m.getSPID()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to get sp id", "error", err)
+		return
+	}
+	families, err := m.baseApp.Consensus().ListVirtualGroupFamilies(ctx, spID)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list virtual group families", "error", err)
+		return
+	}
+	vgfIDs := make([]uint32, 0, len(families))
+	for _, family := range families {
+		vgfIDs = append(vgfIDs, family.GetId())
+	}
+	if len(vgfIDs) == 0 {
+		return
+	}
+	bucketNumbers, err := m.baseApp.GfSpClient().CountBucketsByVgfID(ctx, vgfIDs)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to count buckets by vgf ids", "error", err)
+		return
+	}
+	m.virtualGroupStats.setFamilyBucketNumbers(bucketNumbers)
+	log.CtxDebugw(ctx, "succeed to refresh the bucket numbers of the vgfs", "vgf_number", len(vgfIDs))
+}
@@ -177,6 +177,21 @@ func (r *MetadataModular) GfSpGetUserBucketsCount(ctx context.Context, req *type
 	return resp, nil
 }
 
+// GfSpCountBucketsByVgfID counts the buckets which are not removed by vgf ids
+func (r *MetadataModular) GfSpCountBucketsByVgfID(ctx context.Context, req *types.GfSpCountBucketsByVgfIDRequest) (resp *types.GfSpCountBucketsByVgfIDResponse, err error) {
+	ctx = log.Context(ctx, req)
+
+	counts, err := r.baseApp.GfBsDB().CountBucketsByVgfID(req.GetVgfIds())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to count buckets by vgf ids", "error", err)
+		return
+	}
+
+	resp = &types.GfSpCountBucketsByVgfIDResponse{Counts: counts}
+	log.CtxInfow(ctx, "succeed to count buckets by vgf ids")
+	return resp, nil
+}
+
 // GfSpListExpiredBucketsBySp list expired bucket by sp
 func (r *MetadataModular) GfSpListExpiredBucketsBySp(ctx context.Context, req *types.GfSpListExpiredBucketsBySpRequest) (resp *types.GfSpListExpiredBucketsBySpResponse, err error) {
 	ctx = log.Context(ctx, req)
@@ -3,6 +3,7 @@ package metadata
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"sync"
 
 	"cosmossdk.io/math"
@@ -26,6 +27,7 @@ func (r *MetadataModular) GfSpListObjectsByBucketName(ctx context.Context, req *
 		maxKeys               uint64
 		commonPrefixes        []string
 		res                   []*types.Object
+		stats                 *model.ObjectsStats
 	)
 
 	maxKeys = req.MaxKeys
@@ -40,12 +42,52 @@ func (r *MetadataModular) GfSpListObjectsByBucketName(ctx context.Context, req *
 	}
 
 	ctx = log.Context(ctx, req)
-	results, err = r.baseApp.GfBsDB().ListObjectsByBucketName(req.BucketName, req.ContinuationToken, req.Prefix, req.Delimiter, int(maxKeys), req.IncludeRemoved)
+	filter := &model.ListObjectsFilter{
+		ContentType:     req.ContentType,
+		MinPayloadSize:  req.MinPayloadSize,
+		MaxPayloadSize:  req.MaxPayloadSize,
+		CreateTimeStart: req.CreateTimeStart,
+		CreateTimeEnd:   req.CreateTimeEnd,
+		Visibility:      req.Visibility,
+		ObjectStatus:    req.ObjectStatus,
+		SortBy:          model.ObjectSortType(req.SortBy),
+		SortDesc:        req.SortDesc,
+	}
+	if req.Owner != "" {
+		if !common.IsHexAddress(req.Owner) {
+			log.CtxErrorw(ctx, "failed to check owner", "owner", req.Owner)
+			return nil, ErrInvalidParams
+		}
+		filter.Owner = common.HexToAddress(req.Owner)
+	}
+	switch filter.SortBy {
+	case "", model.ObjectSortByName, model.ObjectSortBySize, model.ObjectSortByCreateTime:
+	default:
+		log.CtxErrorw(ctx, "failed to check sort by", "sort_by", req.SortBy)
+		return nil, ErrInvalidParams
+	}
+	// the objects grouped by the delimiter can not be filtered or sorted
+	if req.Delimiter != "" && filter.IsFiltered() {
+		log.CtxErrorw(ctx, "failed to list objects with both the delimiter and the filters")
+		return nil, ErrInvalidParams
+	}
+
+	results, err = r.baseApp.GfBsDB().ListObjectsByBucketName(req.BucketName, req.ContinuationToken, req.Prefix, req.Delimiter, int(maxKeys), req.IncludeRemoved, filter)
 	if err != nil {
 		log.CtxErrorw(ctx, "failed to list objects by bucket name", "error", err)
+		if errors.Is(err, model.ErrInvalidContinuationToken) {
+			return nil, ErrInvalidParams
+		}
 		return
 	}
 
+	if req.IncludeStats {
+		if stats, err = r.baseApp.GfBsDB().GetObjectsStatsByBucketName(req.BucketName, req.Prefix, req.IncludeRemoved, filter); err != nil {
+			log.CtxErrorw(ctx, "failed to get objects stats by bucket name", "error", err)
+			return
+		}
+	}
+
 	keyCount = uint64(len(results))
 	// if keyCount is equal to req.MaxKeys+1 which means that we additionally return NextContinuationToken, and it is not counted in the keyCount
 	// isTruncated set to false if all the results were returned, set to true if more keys are available to return
@@ -106,6 +148,10 @@ func (r *MetadataModular) GfSpListObjectsByBucketName(ctx context.Context, req *
 		CommonPrefixes:        commonPrefixes,
 		ContinuationToken:     base64.StdEncoding.EncodeToString([]byte(req.ContinuationToken)),
 	}
+	if stats != nil {
+		resp.TotalCount = stats.TotalCount
+		resp.TotalPayloadSize = stats.TotalPayloadSize
+	}
 	log.CtxInfo(ctx, "succeed to list objects by bucket name")
 	return resp, nil
 }
@@ -194,31 +194,65 @@ func (n *Node) GetSecondaryReplicatePieceApproval(
 		return
 	}
 	task.SetAskSignature(signature)
-	n.broadcast(ctx, GetApprovalRequest, task.(*gfsptask.GfSpReplicatePieceApprovalTask))
+	// the approvals of the unhealthy sps are only accepted if there are not enough approvals of the healthy sps
+	unhealthySPs := n.unhealthySPEndpoints(ctx)
+	var unhealthyAccept []coretask.ApprovalReplicatePieceTask
+	defer func() {
+		for i := 0; len(accept) < expectedAccept && i < len(unhealthyAccept); i++ {
+			accept = append(accept, unhealthyAccept[i])
+		}
+	}()
+	sent := n.broadcast(ctx, GetApprovalRequest, task.(*gfsptask.GfSpReplicatePieceApprovalTask))
 	approvalCtx, cancelFunc := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
 	defer cancelFunc()
+	var (
+		responded  int
+		deferredCh <-chan time.Time
+	)
 	for {
 		select {
 		case approval := <-approvalCh:
+			responded++
 			current, innerErr := n.baseApp.Consensus().CurrentHeight(approvalCtx)
-			if innerErr == nil {
-				if approval.GetExpiredHeight() < current {
-					log.CtxWarnw(ctx, "discard expired approval", "sp", approval.GetApprovedSpApprovalAddress(),
-						"object_id", approval.GetObjectInfo().Id.Uint64(), "current_height", current,
-						"expire_height", approval.GetExpiredHeight())
-					continue
-				}
+			if innerErr != nil {
+				log.CtxWarnw(ctx, "failed to get current height", "error", innerErr)
+			}
+			if innerErr == nil && approval.GetExpiredHeight() < current {
+				log.CtxWarnw(ctx, "discard expired approval", "sp", approval.GetApprovedSpApprovalAddress(),
+					"object_id", approval.GetObjectInfo().Id.Uint64(), "current_height", current,
+					"expire_height", approval.GetExpiredHeight())
+			} else if unhealthySPs[approval.GetApprovedSpEndpoint()] {
+				log.CtxDebugw(ctx, "defer the approval of unhealthy sp", "sp", approval.GetApprovedSpEndpoint())
+				unhealthyAccept = append(unhealthyAccept, approval)
 			} else {
-				log.CtxWarnw(ctx, "failed to get current height", "error", err)
+				log.CtxDebugw(ctx, "append replicate approval",
+					"approval_op_address", approval.GetStorageParams())
+				accept = append(accept, approval)
+				if len(accept) >= expectedAccept {
+					log.CtxErrorw(ctx, "succeed to get sufficient approvals",
+						"expect", expectedAccept, "accepted", len(accept))
+					return
+				}
+			}
+			if len(accept)+len(unhealthyAccept) < expectedAccept {
+				continue
 			}
-			log.CtxDebugw(ctx, "append replicate approval",
-				"approval_op_address", approval.GetStorageParams())
-			accept = append(accept, approval)
-			if len(accept) >= expectedAccept {
-				log.CtxErrorw(ctx, "succeed to get sufficient approvals",
-					"expect", expectedAccept, "accepted", len(accept))
+			// the approvals are sufficient with the deferred ones, it is not worth waiting for the approvals of the
+			// healthy sps if all the peers have responded or for a long time
+			if responded >= sent {
+				log.CtxWarnw(ctx, "succeed to get sufficient approvals with the approvals of unhealthy sps",
+					"expect", expectedAccept, "accepted", len(accept), "deferred", len(unhealthyAccept))
 				return
 			}
+			if deferredCh == nil {
+				deferredTimer := time.NewTimer(DefaultDeferredApprovalWaitTime)
+				defer deferredTimer.Stop()
+				deferredCh = deferredTimer.C
+			}
+		case <-deferredCh:
+			log.CtxWarnw(ctx, "stop waiting for the approvals of healthy sps", "expect", expectedAccept,
+				"accepted", len(accept), "deferred", len(unhealthyAccept))
+			return
 		case <-approvalCtx.Done():
 			log.CtxWarnw(ctx, "failed to get sufficient approvals",
 				"expect", expectedAccept, "accepted", len(accept))
@@ -227,6 +261,23 @@ func (n *Node) GetSecondaryReplicatePieceApproval(
 	}
 }
 
+// unhealthySPEndpoints returns the endpoints of the sps which are scored as unhealthy by manager, all the sps
+// are regarded as healthy if failed to query manager.
+func (n *Node) unhealthySPEndpoints(ctx context.Context) map[string]bool {
+	unhealthySPs := make(map[string]bool)
+	healths, err := n.baseApp.GfSpClient().QuerySPHealth(ctx, "")
+	if err != nil {
+		log.CtxWarnw(ctx, "failed to query sp health", "error", err)
+		return unhealthySPs
+	}
+	for _, health := range healths {
+		if !health.GetHealthy() {
+			unhealthySPs[health.GetSpEndpoint()] = true
+		}
+	}
+	return unhealthySPs
+}
+
 // eventLoop run the background task
 func (n *Node) eventLoop() {
 	ticker := time.NewTicker(time.Duration(n.p2pPingPeriod) * time.Second)
@@ -260,11 +311,12 @@ func (n *Node) eventLoop() {
 	}
 }
 
-// broadcast sends request to all p2p nodes
+// broadcast sends request to all p2p nodes, returns the number of the nodes which the request is sent to
 func (n *Node) broadcast(
 	ctx context.Context,
 	pc protocol.ID,
-	data proto.Message) {
+	data proto.Message) int {
+	sent := 0
 	for _, peerID := range n.node.Peerstore().PeersWithAddrs() {
 		if strings.Compare(n.node.ID().String(), peerID.String()) == 0 {
 			continue
@@ -273,8 +325,11 @@ func (n *Node) broadcast(
 		// for _, addr := range addrs {
 		//	log.CtxErrorw(ctx, "broadcast", "protocol", pc, "peer_addr", addr.String())
 		// }
-		n.sendToPeer(ctx, peerID, pc, data)
+		if n.sendToPeer(ctx, peerID, pc, data) == nil {
+			sent++
+		}
 	}
+	return sent
 }
 
 // sendToPeer sends request to all special p2p node
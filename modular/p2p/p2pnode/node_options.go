@@ -6,6 +6,7 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	"cosmossdk.io/errors"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -30,6 +31,9 @@ const (
 	// MinSecondaryApprovalExpiredHeight defines the min expired height for secondary
 	// approval
 	MinSecondaryApprovalExpiredHeight = 900
+	// DefaultDeferredApprovalWaitTime defines the max time to wait for the approvals of the healthy sps after
+	// the approvals are sufficient with the deferred approvals of the unhealthy sps
+	DefaultDeferredApprovalWaitTime = 2 * time.Second
 )
 
 // MakeMultiaddr new multi addr by address
@@ -0,0 +1,363 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bnb-chain/greenfield-common/go/hash"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
+	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+const (
+	// MinPartNumber defines the min part number of multipart upload.
+	MinPartNumber = 1
+	// MaxPartNumber defines the max part number of multipart upload.
+	MaxPartNumber = 10000
+	// listMultipartPieceLimit defines the number of staged pieces listed in one batch when
+	// deleting the staged parts.
+	listMultipartPieceLimit = 1000
+)
+
+var (
+	ErrInvalidPartNumber = gfsperrors.Register(module.UploadModularName, http.StatusBadRequest, 110006, "invalid part number")
+	ErrInvalidPart       = gfsperrors.Register(module.UploadModularName, http.StatusBadRequest, 110007, "one or more of the specified parts could not be found or the etag not match")
+	ErrInvalidPartOrder  = gfsperrors.Register(module.UploadModularName, http.StatusBadRequest, 110008, "the list of parts was not in ascending order")
+	ErrInvalidPartsSize  = gfsperrors.Register(module.UploadModularName, http.StatusBadRequest, 110009, "the total size of parts is not equal to the object payload size")
+)
+
+func (u *UploadModular) HandleUploadObjectPart(ctx context.Context, task coretask.ResumableUploadObjectTask,
+	partNumber uint32, stream io.Reader) (string, error) {
+	if task == nil || task.GetObjectInfo() == nil || task.GetStorageParams() == nil {
+		log.CtxErrorw(ctx, "failed to upload object part, task pointer dangling")
+		return "", ErrDanglingDownloadTask
+	}
+	if task.GetObjectInfo().GetObjectStatus() != storagetypes.OBJECT_STATUS_CREATED {
+		log.CtxErrorw(ctx, "failed to upload object part, object not create")
+		return "", ErrNotCreatedState
+	}
+	if partNumber < MinPartNumber || partNumber > MaxPartNumber {
+		log.CtxErrorw(ctx, "failed to upload object part, invalid part number", "part_number", partNumber)
+		return "", ErrInvalidPartNumber
+	}
+
+	// the chunks of the part are staged by several puts, every upload stages the chunks by its own upload
+	// id, so the concurrent uploads of the same part on any uploader do not mix up the chunks, and the part
+	// record points to the chunks of the last recorded upload. The chunks of the overwritten uploads are
+	// deleted with the other staged chunks when the multipart upload is completed, aborted or expired.
+	uploadID, err := newMultipartUploadID()
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to generate multipart upload id", "error", err)
+		return "", err
+	}
+	var (
+		readN     int
+		partSize  uint64
+		chunkIdx  uint32
+		pieceKeys []string
+		objectID  = task.GetObjectInfo().Id.Uint64()
+		md5Hash   = md5.New()
+		data      = make([]byte, task.GetStorageParams().GetMaxSegmentSize())
+	)
+	defer func() {
+		if err != nil {
+			// the record still points to the chunks of the last successful upload of the part
+			u.deleteSegmentPieces(ctx, pieceKeys)
+		}
+		log.CtxDebugw(ctx, "finish to upload object part", "object_id", objectID, "part_number", partNumber,
+			"upload_id", uploadID, "part_size", partSize, "error", err)
+	}()
+	for {
+		readN, err = StreamReadAt(stream, data)
+		if err != nil && err != io.EOF {
+			log.CtxErrorw(ctx, "stream closed abnormally", "part_number", partNumber, "error", err)
+			err = ErrClosedStream
+			return "", err
+		}
+		eof := err == io.EOF
+		err = nil
+		if readN != 0 {
+			pieceKey := u.baseApp.PieceOp().MultipartPieceKey(objectID, partNumber, uploadID, chunkIdx)
+			pieceKeys = append(pieceKeys, pieceKey)
+			if err = u.baseApp.PieceStore().PutPiece(ctx, pieceKey, data[0:readN]); err != nil {
+				log.CtxErrorw(ctx, "failed to put part chunk to piece store", "piece_key", pieceKey, "error", err)
+				err = ErrPieceStore
+				return "", err
+			}
+			md5Hash.Write(data[0:readN])
+			partSize += uint64(readN)
+			chunkIdx++
+		}
+		if eof {
+			break
+		}
+	}
+	if partSize == 0 {
+		log.CtxErrorw(ctx, "failed to upload object part, empty part", "part_number", partNumber)
+		err = ErrInvalidPart
+		return "", err
+	}
+
+	etag := hex.EncodeToString(md5Hash.Sum(nil))
+	if err = u.baseApp.GfSpDB().UpdateMultipartUploadPart(&corespdb.MultipartUploadPart{
+		ObjectID:   objectID,
+		PartNumber: partNumber,
+		UploadID:   uploadID,
+		Size:       partSize,
+		ETag:       etag,
+	}); err != nil {
+		log.CtxErrorw(ctx, "failed to record multipart upload part", "error", err)
+		err = ErrGfSpDB
+		return "", err
+	}
+	return etag, nil
+}
+
+func (u *UploadModular) ListMultipartUploadParts(ctx context.Context, objectID uint64) (
+	[]*corespdb.MultipartUploadPart, error) {
+	parts, err := u.baseApp.GfSpDB().ListMultipartUploadParts(objectID)
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to list multipart upload parts", "object_id", objectID, "error", err)
+		return nil, ErrGfSpDB
+	}
+	return parts, nil
+}
+
+// HandleCompleteMultipartUpload checks the parts to complete against the staged parts, and assembles the
+// staged parts into the segment pieces by a background task, since re-segmenting the whole object takes
+// much longer than the rpc deadline. The object is being assembled while the task is in the resumable upload
+// queue, the result is reported to manager by the task, which replicates the object or records the error.
+func (u *UploadModular) HandleCompleteMultipartUpload(ctx context.Context, task coretask.ResumableUploadObjectTask,
+	parts []*corespdb.MultipartUploadPart) error {
+	stagedParts, err := u.ListMultipartUploadParts(ctx, task.GetObjectInfo().Id.Uint64())
+	if err != nil {
+		return err
+	}
+	if parts, err = checkCompleteParts(parts, stagedParts, task.GetObjectInfo().GetPayloadSize()); err != nil {
+		log.CtxErrorw(ctx, "failed to check the parts of multipart upload", "error", err)
+		return err
+	}
+	span, err := u.ReserveResource(ctx, task.EstimateLimit().ScopeStat())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to reserve resource", "error", err)
+		return gfspapp.ErrUploadExhaustResource
+	}
+	if err = u.resumeableUploadQueue.Push(task); err != nil {
+		span.Done()
+		log.CtxErrorw(ctx, "failed to push upload queue", "error", err)
+		return err
+	}
+	go func() {
+		defer span.Done()
+		u.assembleMultipartUpload(task, parts)
+	}()
+	return nil
+}
+
+// assembleMultipartUpload re-segments the checked parts into the segment pieces, and reports the task to
+// manager after it finishes.
+func (u *UploadModular) assembleMultipartUpload(task coretask.ResumableUploadObjectTask,
+	parts []*corespdb.MultipartUploadPart) {
+	ctx := log.WithValue(context.Background(), log.CtxKeyTask, task.Key().String())
+	if task.GetTimeout() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(task.GetTimeout())*time.Second)
+		defer cancel()
+	}
+	defer u.resumeableUploadQueue.PopByKey(task.Key())
+
+	var (
+		err       error
+		integrity []byte
+		checksums [][]byte
+		pieceKeys []string
+		objectID  = task.GetObjectInfo().Id.Uint64()
+	)
+	defer func() {
+		if err != nil {
+			task.SetError(err)
+			u.deleteSegmentPieces(ctx, pieceKeys)
+		}
+		log.CtxDebugw(ctx, "finish to complete multipart upload", "info", task.Info(), "error", err)
+		// report the task to manager to replicate the object or record the error
+		if reportErr := u.baseApp.GfSpClient().ReportTask(ctx, task); reportErr != nil {
+			log.CtxErrorw(ctx, "failed to report complete multipart upload task", "error", reportErr)
+		}
+	}()
+
+	segmentSize := u.baseApp.PieceOp().MaxSegmentPieceSize(
+		task.GetObjectInfo().GetPayloadSize(), task.GetStorageParams().GetMaxSegmentSize())
+	segmentCount := u.baseApp.PieceOp().SegmentPieceCount(
+		task.GetObjectInfo().GetPayloadSize(), task.GetStorageParams().GetMaxSegmentSize())
+	window, release := u.reserveSegmentWindow(ctx, segmentSize, segmentCount)
+	defer release()
+
+	// the parts may not be aligned to the max segment size, re-segment them by reading the
+	// staged chunks in part order.
+	reader := &multipartReader{
+		parts: parts,
+		getChunk: func(part *corespdb.MultipartUploadPart, chunkIdx uint32) ([]byte, error) {
+			return u.baseApp.PieceStore().GetPiece(ctx,
+				u.baseApp.PieceOp().MultipartPieceKey(objectID, part.PartNumber, part.UploadID, chunkIdx), 0, -1)
+		},
+	}
+	if checksums, pieceKeys, _, err = u.putSegmentPieces(ctx, task, segmentSize, window, reader); err != nil {
+		return
+	}
+	integrity = hash.GenerateIntegrityHash(checksums)
+	if !bytes.Equal(integrity, task.GetObjectInfo().GetChecksums()[0]) {
+		log.CtxErrorw(ctx, "failed to complete multipart upload due to check integrity hash not consistent",
+			"actual_integrity", hex.EncodeToString(integrity),
+			"expected_integrity", hex.EncodeToString(task.GetObjectInfo().GetChecksums()[0]))
+		err = ErrInvalidIntegrity
+		return
+	}
+	// the integrity meta may be left by the previous resumable upload, overwrite it
+	_ = u.baseApp.GfSpDB().DeleteObjectIntegrity(objectID, primarySPRedundancyIdx)
+	if err = u.baseApp.GfSpDB().SetObjectIntegrity(&corespdb.IntegrityMeta{
+		ObjectID:          objectID,
+		RedundancyIndex:   primarySPRedundancyIdx,
+		PieceChecksumList: checksums,
+		IntegrityChecksum: integrity,
+	}); err != nil {
+		log.CtxErrorw(ctx, "failed to write integrity hash to db", "error", err)
+		err = ErrGfSpDB
+		return
+	}
+	if abortErr := u.abortMultipartUpload(ctx, objectID); abortErr != nil {
+		log.CtxWarnw(ctx, "failed to clean the staged parts after completing multipart upload", "error", abortErr)
+	}
+	log.CtxDebugw(ctx, "succeed to complete multipart upload")
+}
+
+func (u *UploadModular) HandleAbortMultipartUpload(ctx context.Context, task coretask.ResumableUploadObjectTask) error {
+	if task == nil || task.GetObjectInfo() == nil {
+		log.CtxErrorw(ctx, "failed to abort multipart upload, task pointer dangling")
+		return ErrDanglingDownloadTask
+	}
+	if u.resumeableUploadQueue.Has(task.Key()) {
+		log.CtxErrorw(ctx, "failed to abort multipart upload, the upload is completing")
+		return ErrRepeatedTask
+	}
+	return u.abortMultipartUpload(ctx, task.GetObjectInfo().Id.Uint64())
+}
+
+// abortMultipartUpload deletes all the staged chunks and the part records of the object.
+func (u *UploadModular) abortMultipartUpload(ctx context.Context, objectID uint64) error {
+	prefix := u.baseApp.PieceOp().MultipartPieceKeyPrefix(objectID)
+	for {
+		pieces, err := u.baseApp.PieceStore().ListPieces(ctx, prefix, "", listMultipartPieceLimit)
+		if err != nil {
+			log.CtxErrorw(ctx, "failed to list staged parts", "prefix", prefix, "error", err)
+			return ErrPieceStore
+		}
+		for _, piece := range pieces {
+			if err = u.baseApp.PieceStore().DeletePiece(ctx, piece.Key); err != nil {
+				log.CtxErrorw(ctx, "failed to delete staged part chunk", "piece_key", piece.Key, "error", err)
+				return ErrPieceStore
+			}
+		}
+		if len(pieces) < listMultipartPieceLimit {
+			break
+		}
+	}
+	if err := u.baseApp.GfSpDB().DeleteMultipartUploadParts(objectID); err != nil {
+		log.CtxErrorw(ctx, "failed to delete multipart upload parts", "object_id", objectID, "error", err)
+		return ErrGfSpDB
+	}
+	return nil
+}
+
+// checkCompleteParts checks the parts to complete against the staged parts, the parts must be in
+// ascending order of part number, and the etags must match the staged ones. It returns the staged
+// parts to assemble the object.
+func checkCompleteParts(parts, stagedParts []*corespdb.MultipartUploadPart, payloadSize uint64) (
+	[]*corespdb.MultipartUploadPart, error) {
+	if len(parts) == 0 {
+		return nil, ErrInvalidPart
+	}
+	staged := make(map[uint32]*corespdb.MultipartUploadPart, len(stagedParts))
+	for _, part := range stagedParts {
+		staged[part.PartNumber] = part
+	}
+	var (
+		totalSize      uint64
+		lastPartNumber uint32
+		assembleParts  = make([]*corespdb.MultipartUploadPart, 0, len(parts))
+	)
+	for _, part := range parts {
+		if part.PartNumber <= lastPartNumber {
+			return nil, ErrInvalidPartOrder
+		}
+		lastPartNumber = part.PartNumber
+		stagedPart, ok := staged[part.PartNumber]
+		if !ok || stagedPart.ETag == "" || stagedPart.ETag != part.ETag {
+			return nil, ErrInvalidPart
+		}
+		totalSize += stagedPart.Size
+		assembleParts = append(assembleParts, stagedPart)
+	}
+	if totalSize != payloadSize {
+		return nil, ErrInvalidPartsSize
+	}
+	return assembleParts, nil
+}
+
+// multipartReader reads the staged chunks of the parts in order, only the current chunk
+// is held in memory.
+type multipartReader struct {
+	parts    []*corespdb.MultipartUploadPart
+	partIdx  int
+	chunkIdx uint32
+	readSize uint64
+	data     []byte
+	getChunk func(part *corespdb.MultipartUploadPart, chunkIdx uint32) ([]byte, error)
+}
+
+func (r *multipartReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if r.partIdx >= len(r.parts) {
+			return 0, io.EOF
+		}
+		part := r.parts[r.partIdx]
+		if r.readSize == part.Size {
+			r.partIdx++
+			r.chunkIdx = 0
+			r.readSize = 0
+			continue
+		}
+		data, err := r.getChunk(part, r.chunkIdx)
+		if err != nil {
+			return 0, err
+		}
+		if len(data) == 0 || r.readSize+uint64(len(data)) > part.Size {
+			return 0, ErrInvalidPart
+		}
+		r.chunkIdx++
+		r.readSize += uint64(len(data))
+		r.data = data
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// newMultipartUploadID returns a random id of the upload of a part.
+func newMultipartUploadID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
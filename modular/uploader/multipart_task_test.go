@@ -0,0 +1,349 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+
+	"github.com/bnb-chain/greenfield-common/go/hash"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptqueue"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfspserver"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/piecestore"
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+func TestCheckCompleteParts(t *testing.T) {
+	stagedParts := []*corespdb.MultipartUploadPart{
+		{PartNumber: 1, Size: 10, ETag: "e1"},
+		{PartNumber: 2, Size: 20, ETag: "e2"},
+		{PartNumber: 3, Size: 30, ETag: ""},
+		{PartNumber: 5, Size: 5, ETag: "e5"},
+	}
+	testCases := []struct {
+		name        string
+		parts       []*corespdb.MultipartUploadPart
+		payloadSize uint64
+		wantedParts []uint32
+		wantedErr   error
+	}{
+		{"all parts", []*corespdb.MultipartUploadPart{{PartNumber: 1, ETag: "e1"}, {PartNumber: 2, ETag: "e2"},
+			{PartNumber: 5, ETag: "e5"}}, 35, []uint32{1, 2, 5}, nil},
+		{"part of the staged parts", []*corespdb.MultipartUploadPart{{PartNumber: 2, ETag: "e2"},
+			{PartNumber: 5, ETag: "e5"}}, 25, []uint32{2, 5}, nil},
+		{"no part", nil, 0, nil, ErrInvalidPart},
+		{"descending order", []*corespdb.MultipartUploadPart{{PartNumber: 2, ETag: "e2"},
+			{PartNumber: 1, ETag: "e1"}}, 30, nil, ErrInvalidPartOrder},
+		{"duplicated part", []*corespdb.MultipartUploadPart{{PartNumber: 1, ETag: "e1"},
+			{PartNumber: 1, ETag: "e1"}}, 20, nil, ErrInvalidPartOrder},
+		{"part not staged", []*corespdb.MultipartUploadPart{{PartNumber: 4, ETag: "e4"}}, 0, nil, ErrInvalidPart},
+		{"etag not match", []*corespdb.MultipartUploadPart{{PartNumber: 1, ETag: "e2"}}, 10, nil, ErrInvalidPart},
+		{"invalidated part", []*corespdb.MultipartUploadPart{{PartNumber: 3, ETag: ""}}, 30, nil, ErrInvalidPart},
+		{"size not match", []*corespdb.MultipartUploadPart{{PartNumber: 1, ETag: "e1"}}, 11, nil, ErrInvalidPartsSize},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := checkCompleteParts(tt.parts, stagedParts, tt.payloadSize)
+			assert.Equal(t, tt.wantedErr, err)
+			var partNumbers []uint32
+			for _, part := range parts {
+				partNumbers = append(partNumbers, part.PartNumber)
+				// the staged parts are returned to assemble the object
+				assert.NotZero(t, part.Size)
+			}
+			assert.Equal(t, tt.wantedParts, partNumbers)
+		})
+	}
+}
+
+func TestMultipartReader(t *testing.T) {
+	chunks := map[uint32][]string{
+		1: {"abcd", "ef"},
+		2: {"g"},
+		3: {"hijk", "lmno", "p"},
+	}
+	getChunk := func(part *corespdb.MultipartUploadPart, chunkIdx uint32) ([]byte, error) {
+		if int(chunkIdx) >= len(chunks[part.PartNumber]) {
+			return nil, errors.New("chunk not found")
+		}
+		return []byte(chunks[part.PartNumber][chunkIdx]), nil
+	}
+	testCases := []struct {
+		name       string
+		parts      []*corespdb.MultipartUploadPart
+		wantedData string
+		wantedErr  error
+	}{
+		{"all parts", []*corespdb.MultipartUploadPart{{PartNumber: 1, Size: 6}, {PartNumber: 2, Size: 1},
+			{PartNumber: 3, Size: 9}}, "abcdefghijklmnop", nil},
+		{"skip part", []*corespdb.MultipartUploadPart{{PartNumber: 1, Size: 6}, {PartNumber: 3, Size: 9}},
+			"abcdefhijklmnop", nil},
+		{"no part", nil, "", nil},
+		{"chunk larger than part", []*corespdb.MultipartUploadPart{{PartNumber: 1, Size: 5}}, "abcd", ErrInvalidPart},
+		{"chunk missing", []*corespdb.MultipartUploadPart{{PartNumber: 2, Size: 2}}, "g", errors.New("chunk not found")},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &multipartReader{parts: tt.parts, getChunk: getChunk}
+			// read by a small buffer to read the chunks across the boundaries
+			var (
+				data bytes.Buffer
+				err  error
+				n    int
+				buf  = make([]byte, 3)
+			)
+			for {
+				n, err = reader.Read(buf)
+				data.Write(buf[:n])
+				if err != nil {
+					break
+				}
+			}
+			assert.Equal(t, tt.wantedData, data.String())
+			if tt.wantedErr == nil {
+				assert.Equal(t, io.EOF, err)
+			} else {
+				assert.Equal(t, tt.wantedErr, err)
+			}
+		})
+	}
+}
+
+// multipartTestPieceStore stores the pieces in memory.
+type multipartTestPieceStore struct {
+	piecestore.PieceStore
+
+	mux    sync.Mutex
+	pieces map[string][]byte
+}
+
+func (s *multipartTestPieceStore) PutPiece(_ context.Context, key string, value []byte) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.pieces[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (s *multipartTestPieceStore) GetPiece(_ context.Context, key string, _, _ int64) ([]byte, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	data, ok := s.pieces[key]
+	if !ok {
+		return nil, errors.New("piece not found")
+	}
+	return data, nil
+}
+
+func (s *multipartTestPieceStore) ListPieces(_ context.Context, prefix, _ string, _ int64) ([]*piecestore.PieceInfo, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var pieces []*piecestore.PieceInfo
+	for key, data := range s.pieces {
+		if strings.HasPrefix(key, prefix) {
+			pieces = append(pieces, &piecestore.PieceInfo{Key: key, Size: int64(len(data))})
+		}
+	}
+	return pieces, nil
+}
+
+func (s *multipartTestPieceStore) DeletePiece(_ context.Context, key string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.pieces, key)
+	return nil
+}
+
+// slowReader returns the data chunk by chunk with a delay, so the concurrent uploads interleave.
+type slowReader struct {
+	data      []byte
+	chunkSize int
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(5 * time.Millisecond)
+	if len(p) > r.chunkSize {
+		p = p[:r.chunkSize]
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestUploadModular_HandleUploadObjectPartConcurrently(t *testing.T) {
+	const maxSegmentSize = 4
+	ctrl := gomock.NewController(t)
+	db := corespdb.NewMockSPDB(ctrl)
+	var (
+		mux   sync.Mutex
+		parts []*corespdb.MultipartUploadPart
+	)
+	db.EXPECT().UpdateMultipartUploadPart(gomock.Any()).DoAndReturn(func(part *corespdb.MultipartUploadPart) error {
+		mux.Lock()
+		defer mux.Unlock()
+		parts = append(parts, part)
+		return nil
+	}).Times(2)
+	store := &multipartTestPieceStore{pieces: make(map[string][]byte)}
+	baseApp, err := gfspapp.NewGfSpBaseApp(&gfspconfig.GfSpConfig{}, gfspconfig.CustomizeGfSpDB(db),
+		gfspconfig.CustomizePieceStore(store))
+	require.NoError(t, err)
+	uploader := &UploadModular{baseApp: baseApp}
+
+	task := &gfsptask.GfSpResumableUploadObjectTask{}
+	task.InitResumableUploadObjectTask(0, &storagetypes.ObjectInfo{Id: sdkmath.NewUint(1),
+		ObjectStatus: storagetypes.OBJECT_STATUS_CREATED}, &storagetypes.Params{
+		VersionedParams: storagetypes.VersionedParams{MaxSegmentSize: maxSegmentSize}}, 0, false, 0)
+	contents := []string{strings.Repeat("a", 4*maxSegmentSize), strings.Repeat("b", 4*maxSegmentSize)}
+	var wg sync.WaitGroup
+	for _, content := range contents {
+		wg.Add(1)
+		go func(content string) {
+			defer wg.Done()
+			_, uploadErr := uploader.HandleUploadObjectPart(context.Background(), task, 1,
+				&slowReader{data: []byte(content), chunkSize: maxSegmentSize})
+			assert.NoError(t, uploadErr)
+		}(content)
+	}
+	wg.Wait()
+
+	// every upload stages its chunks by its own upload id, so the last recorded part matches its chunks
+	require.Len(t, parts, 2)
+	assert.NotEqual(t, parts[0].UploadID, parts[1].UploadID)
+	for _, part := range parts {
+		var staged bytes.Buffer
+		for chunkIdx := uint32(0); chunkIdx < 4; chunkIdx++ {
+			staged.Write(store.pieces[baseApp.PieceOp().MultipartPieceKey(1, 1, part.UploadID, chunkIdx)])
+		}
+		assert.Contains(t, contents, staged.String())
+		md5Hash := md5.Sum(staged.Bytes())
+		assert.Equal(t, hex.EncodeToString(md5Hash[:]), part.ETag)
+	}
+}
+
+// multipartTestManager records the reported resumable upload tasks as manager.
+type multipartTestManager struct {
+	gfspserver.UnimplementedGfSpManageServiceServer
+
+	reported chan *gfsptask.GfSpResumableUploadObjectTask
+}
+
+func (m *multipartTestManager) GfSpReportTask(_ context.Context, req *gfspserver.GfSpReportTaskRequest) (
+	*gfspserver.GfSpReportTaskResponse, error) {
+	if t := req.GetResumableUploadObjectTask(); t != nil {
+		m.reported <- t
+	}
+	return &gfspserver.GfSpReportTaskResponse{}, nil
+}
+
+func setupCompleteMultipartTest(t *testing.T, db corespdb.SPDB, store piecestore.PieceStore) (
+	*UploadModular, *multipartTestManager) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	manager := &multipartTestManager{reported: make(chan *gfsptask.GfSpResumableUploadObjectTask, 1)}
+	grpcServer := grpc.NewServer()
+	gfspserver.RegisterGfSpManageServiceServer(grpcServer, manager)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	baseApp, err := gfspapp.NewGfSpBaseApp(&gfspconfig.GfSpConfig{GRPCAddress: listener.Addr().String()},
+		gfspconfig.CustomizeGfSpDB(db), gfspconfig.CustomizePieceStore(store))
+	require.NoError(t, err)
+	scope, err := baseApp.ResourceManager().OpenService(module.UploadModularName)
+	require.NoError(t, err)
+	return &UploadModular{baseApp: baseApp, scope: scope, segmentParallel: 2,
+		resumeableUploadQueue: gfsptqueue.NewGfSpTQueue("test-upload-resumable-object", 1)}, manager
+}
+
+func TestUploadModular_HandleCompleteMultipartUpload(t *testing.T) {
+	const maxSegmentSize = 4
+	// the parts are not aligned to the max segment size, they are re-segmented to "abcd" and "efgh"
+	stagedParts := []*corespdb.MultipartUploadPart{
+		{ObjectID: 1, PartNumber: 1, UploadID: "u1", Size: 3, ETag: "e1"},
+		{ObjectID: 1, PartNumber: 2, UploadID: "u2", Size: 5, ETag: "e2"},
+	}
+	newTask := func() *gfsptask.GfSpResumableUploadObjectTask {
+		task := &gfsptask.GfSpResumableUploadObjectTask{}
+		task.InitResumableUploadObjectTask(0, &storagetypes.ObjectInfo{Id: sdkmath.NewUint(1), PayloadSize: 8,
+			ObjectStatus: storagetypes.OBJECT_STATUS_CREATED, Checksums: [][]byte{hash.GenerateIntegrityHash(
+				[][]byte{hash.GenerateChecksum([]byte("abcd")), hash.GenerateChecksum([]byte("efgh"))})}},
+			&storagetypes.Params{VersionedParams: storagetypes.VersionedParams{MaxSegmentSize: maxSegmentSize}},
+			0, false, 0)
+		return task
+	}
+
+	t.Run("assemble the parts in background", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		db := corespdb.NewMockSPDB(ctrl)
+		db.EXPECT().ListMultipartUploadParts(uint64(1)).Return(stagedParts, nil)
+		db.EXPECT().DeleteObjectIntegrity(uint64(1), int32(primarySPRedundancyIdx)).Return(nil)
+		db.EXPECT().SetObjectIntegrity(gomock.Any()).DoAndReturn(func(meta *corespdb.IntegrityMeta) error {
+			assert.Equal(t, [][]byte{hash.GenerateChecksum([]byte("abcd")), hash.GenerateChecksum([]byte("efgh"))},
+				meta.PieceChecksumList)
+			return nil
+		})
+		db.EXPECT().DeleteMultipartUploadParts(uint64(1)).Return(nil)
+		store := &multipartTestPieceStore{pieces: make(map[string][]byte)}
+		uploader, manager := setupCompleteMultipartTest(t, db, store)
+		pieceOp := uploader.baseApp.PieceOp()
+		store.pieces[pieceOp.MultipartPieceKey(1, 1, "u1", 0)] = []byte("abc")
+		store.pieces[pieceOp.MultipartPieceKey(1, 2, "u2", 0)] = []byte("defg")
+		store.pieces[pieceOp.MultipartPieceKey(1, 2, "u2", 1)] = []byte("h")
+		// the chunks superseded by the later upload of the same part are cleaned too
+		store.pieces[pieceOp.MultipartPieceKey(1, 2, "u0", 0)] = []byte("xxxx")
+
+		task := newTask()
+		err := uploader.HandleCompleteMultipartUpload(context.Background(), task,
+			[]*corespdb.MultipartUploadPart{{PartNumber: 1, ETag: "e1"}, {PartNumber: 2, ETag: "e2"}})
+		require.NoError(t, err)
+		select {
+		case reported := <-manager.reported:
+			assert.Nil(t, reported.Error())
+		case <-time.After(5 * time.Second):
+			t.Fatal("the complete multipart upload task is not reported")
+		}
+		require.Eventually(t, func() bool { return !uploader.resumeableUploadQueue.Has(task.Key()) },
+			time.Second, 10*time.Millisecond)
+		store.mux.Lock()
+		defer store.mux.Unlock()
+		assert.Equal(t, map[string][]byte{
+			pieceOp.SegmentPieceKey(1, 0): []byte("abcd"),
+			pieceOp.SegmentPieceKey(1, 1): []byte("efgh"),
+		}, store.pieces)
+	})
+
+	t.Run("invalid part", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		db := corespdb.NewMockSPDB(ctrl)
+		db.EXPECT().ListMultipartUploadParts(uint64(1)).Return(stagedParts, nil)
+		uploader, manager := setupCompleteMultipartTest(t, db, &multipartTestPieceStore{pieces: make(map[string][]byte)})
+
+		task := newTask()
+		err := uploader.HandleCompleteMultipartUpload(context.Background(), task,
+			[]*corespdb.MultipartUploadPart{{PartNumber: 1, ETag: "e1"}, {PartNumber: 2, ETag: "e3"}})
+		assert.Equal(t, ErrInvalidPart, err)
+		// the parts are checked before the task is started, nothing is queued or reported
+		assert.False(t, uploader.resumeableUploadQueue.Has(task.Key()))
+		assert.Empty(t, manager.reported)
+	})
+}
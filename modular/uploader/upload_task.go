@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/bnb-chain/greenfield-common/go/hash"
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsperrors"
 	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	"github.com/bnb-chain/greenfield-storage-provider/core/rcmgr"
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	coretask "github.com/bnb-chain/greenfield-storage-provider/core/task"
 	"github.com/bnb-chain/greenfield-storage-provider/core/taskqueue"
@@ -30,6 +32,9 @@ var (
 	ErrGfSpDB               = gfsperrors.Register(module.UploadModularName, http.StatusInternalServerError, 115001, "server slipped away, try again later")
 )
 
+// deleteSegmentPiecesTimeout defines the max time to delete the segment pieces after the upload fails.
+const deleteSegmentPiecesTimeout = 30 * time.Second
+
 func (u *UploadModular) PreUploadObject(ctx context.Context, uploadObjectTask coretask.UploadObjectTask) error {
 	if uploadObjectTask == nil || uploadObjectTask.GetObjectInfo() == nil || uploadObjectTask.GetStorageParams() == nil {
 		log.CtxErrorw(ctx, "failed to pre upload object, task pointer dangling")
@@ -64,23 +69,19 @@ func (u *UploadModular) HandleUploadObjectTask(ctx context.Context, uploadObject
 	}
 	defer u.uploadQueue.PopByKey(uploadObjectTask.Key())
 
-	segmentSize := u.baseApp.PieceOp().MaxSegmentPieceSize(
-		uploadObjectTask.GetObjectInfo().GetPayloadSize(),
-		uploadObjectTask.GetStorageParams().GetMaxSegmentSize())
 	var (
 		err       error
-		segIdx    uint32 = 0
-		pieceKey  string
 		integrity []byte
 		checksums [][]byte
-		readN     int
+		pieceKeys []string
 		readSize  int
-		data      = make([]byte, segmentSize)
+		objectID  = uploadObjectTask.GetObjectInfo().Id.Uint64()
 	)
 	metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_begin_from_task_create").Observe(time.Since(time.Unix(uploadObjectTask.GetCreateTime(), 0)).Seconds())
 	defer func() {
 		if err != nil {
 			uploadObjectTask.SetError(err)
+			u.deleteSegmentPieces(ctx, pieceKeys)
 		}
 		log.CtxDebugw(ctx, "finish to read data from stream", "info", uploadObjectTask.Info(),
 			"read_size", readSize, "error", err)
@@ -92,74 +93,175 @@ func (u *UploadModular) HandleUploadObjectTask(ctx context.Context, uploadObject
 			metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_after_report_manager_end").Observe(time.Since(time.Unix(uploadObjectTask.GetCreateTime(), 0)).Seconds())
 		}()
 	}()
+
+	segmentSize := u.baseApp.PieceOp().MaxSegmentPieceSize(
+		uploadObjectTask.GetObjectInfo().GetPayloadSize(),
+		uploadObjectTask.GetStorageParams().GetMaxSegmentSize())
+	segmentCount := u.baseApp.PieceOp().SegmentPieceCount(
+		uploadObjectTask.GetObjectInfo().GetPayloadSize(),
+		uploadObjectTask.GetStorageParams().GetMaxSegmentSize())
+	window, release := u.reserveSegmentWindow(ctx, segmentSize, segmentCount)
+	defer release()
+
+	checksums, pieceKeys, readSize, err = u.putSegmentPieces(ctx, uploadObjectTask, segmentSize, window, stream)
+	if err != nil {
+		return err
+	}
+	integrity = hash.GenerateIntegrityHash(checksums)
+	if !bytes.Equal(integrity, uploadObjectTask.GetObjectInfo().GetChecksums()[0]) {
+		log.CtxErrorw(ctx, "failed to put object due to check integrity hash not consistent",
+			"actual_integrity", hex.EncodeToString(integrity),
+			"expected_integrity", hex.EncodeToString(uploadObjectTask.GetObjectInfo().GetChecksums()[0]))
+		err = ErrInvalidIntegrity
+		return err
+	}
+	integrityMeta := &corespdb.IntegrityMeta{
+		ObjectID:          objectID,
+		RedundancyIndex:   -1,
+		PieceChecksumList: checksums,
+		IntegrityChecksum: integrity,
+	}
+	startUpdateSignature := time.Now()
+	err = u.baseApp.GfSpDB().SetObjectIntegrity(integrityMeta)
+	metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_set_integrity_cost").Observe(time.Since(startUpdateSignature).Seconds())
+	metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_set_integrity_end").Observe(time.Since(time.Unix(uploadObjectTask.GetCreateTime(), 0)).Seconds())
+	if err != nil {
+		log.CtxErrorw(ctx, "failed to write integrity hash to db", "error", err)
+		err = ErrGfSpDB
+		return err
+	}
+	log.CtxDebugw(ctx, "succeed to upload payload to piece store")
+	return nil
+}
+
+// reserveSegmentWindow returns the number of segment pieces that can be put concurrently. The resource
+// estimated by task covers one segment in flight and one being read, the extra in-flight segments are
+// reserved here, if the uploader is busy, it falls back to put the segments one by one.
+func (u *UploadModular) reserveSegmentWindow(ctx context.Context, segmentSize int64, segmentCount uint32) (int, func()) {
+	window := u.segmentParallel
+	if window > int(segmentCount) {
+		window = int(segmentCount)
+	}
+	if window <= 1 {
+		return 1, func() {}
+	}
+	span, err := u.ReserveResource(ctx, &rcmgr.ScopeStat{Memory: int64(window-1) * segmentSize})
+	if err != nil {
+		log.CtxWarnw(ctx, "failed to reserve in-flight segments resource, put segments serially",
+			"window", window, "error", err)
+		return 1, func() {}
+	}
+	return window, func() { u.ReleaseResource(ctx, span) }
+}
+
+// putSegmentPieces reads the payload from stream segment by segment, and puts the segment pieces
+// to piece store concurrently, at most window segment pieces are in flight while the next one is
+// being read. The checksums are returned in segment order. The returned piece keys are the segment
+// pieces that have been put or tried to put, the caller should delete them if the upload fails.
+func (u *UploadModular) putSegmentPieces(ctx context.Context, uploadObjectTask coretask.ObjectTask,
+	segmentSize int64, window int, stream io.Reader) (checksums [][]byte, pieceKeys []string, readSize int, err error) {
+	if window < 1 {
+		window = 1
+	}
+	var (
+		putCtx, cancel = context.WithCancel(ctx)
+		wg             sync.WaitGroup
+		putErr         error
+		putErrOnce     sync.Once
+		// the buffers are allocated lazily, one is used for reading and the others are in flight
+		bufPool  = make(chan []byte, window+1)
+		objectID = uploadObjectTask.GetObjectInfo().Id.Uint64()
+	)
+	defer cancel()
+	for i := 0; i < window+1; i++ {
+		bufPool <- nil
+	}
 	startTime := time.Now()
-	for {
-		if err != nil {
-			return err
+	for segIdx := uint32(0); ; segIdx++ {
+		var data []byte
+		select {
+		case data = <-bufPool:
+		case <-putCtx.Done():
+		}
+		if putCtx.Err() != nil {
+			break
+		}
+		if data == nil {
+			data = make([]byte, segmentSize)
 		}
-		data = data[0:segmentSize]
 		startReadFromGateway := time.Now()
-		readN, err = StreamReadAt(stream, data)
+		readN, readErr := StreamReadAt(stream, data[0:segmentSize])
 		metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_server_read_data_cost").Observe(time.Since(startReadFromGateway).Seconds())
 		metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_server_read_data_end").Observe(time.Since(time.Unix(uploadObjectTask.GetCreateTime(), 0)).Seconds())
 		readSize += readN
-		data = data[0:readN]
-
-		if err == io.EOF {
-			err = nil
-			if readN != 0 { // the last segment piece
-				pieceKey = u.baseApp.PieceOp().SegmentPieceKey(uploadObjectTask.GetObjectInfo().Id.Uint64(), segIdx)
-				checksums = append(checksums, hash.GenerateChecksum(data))
+		if readErr != nil && readErr != io.EOF {
+			log.CtxErrorw(ctx, "stream closed abnormally", "segment_idx", segIdx, "error", readErr)
+			err = ErrClosedStream
+			break
+		}
+		if readN != 0 {
+			pieceKey := u.baseApp.PieceOp().SegmentPieceKey(objectID, segIdx)
+			pieceKeys = append(pieceKeys, pieceKey)
+			checksums = append(checksums, hash.GenerateChecksum(data[0:readN]))
+			wg.Add(1)
+			go func(pieceKey string, data []byte, readN int) {
+				defer wg.Done()
+				defer func() { bufPool <- data }()
 				startPutPiece := time.Now()
-				err = u.baseApp.PieceStore().PutPiece(ctx, pieceKey, data)
+				putPieceErr := u.baseApp.PieceStore().PutPiece(putCtx, pieceKey, data[0:readN])
 				metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_server_put_piece_cost").Observe(time.Since(startPutPiece).Seconds())
 				metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_server_put_piece_end").Observe(time.Since(startTime).Seconds())
-				if err != nil {
-					log.CtxErrorw(ctx, "failed to put segment piece to piece store", "piece_key", pieceKey, "error", err)
-					return ErrPieceStore
+				if putPieceErr != nil {
+					log.CtxErrorw(ctx, "failed to put segment piece to piece store", "piece_key", pieceKey, "error", putPieceErr)
+					putErrOnce.Do(func() {
+						putErr = ErrPieceStore
+						cancel()
+					})
 				}
-			}
-			if !bytes.Equal(hash.GenerateIntegrityHash(checksums), uploadObjectTask.GetObjectInfo().GetChecksums()[0]) {
-				log.CtxErrorw(ctx, "failed to put object due to check integrity hash not consistent",
-					"actual_integrity", hex.EncodeToString(integrity),
-					"expected_integrity", hex.EncodeToString(uploadObjectTask.GetObjectInfo().GetChecksums()[0]))
-				return ErrInvalidIntegrity
-			}
-			integrityMeta := &corespdb.IntegrityMeta{
-				ObjectID:          uploadObjectTask.GetObjectInfo().Id.Uint64(),
-				RedundancyIndex:   -1,
-				PieceChecksumList: checksums,
-				IntegrityChecksum: integrity,
-			}
-			startUpdateSignature := time.Now()
-			err = u.baseApp.GfSpDB().SetObjectIntegrity(integrityMeta)
-			metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_set_integrity_cost").Observe(time.Since(startUpdateSignature).Seconds())
-			metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_set_integrity_end").Observe(time.Since(time.Unix(uploadObjectTask.GetCreateTime(), 0)).Seconds())
-			if err != nil {
-				log.CtxErrorw(ctx, "failed to write integrity hash to db", "error", err)
-				return ErrGfSpDB
-			}
-			log.CtxDebugw(ctx, "succeed to upload payload to piece store")
-			return nil
+			}(pieceKey, data, readN)
 		}
-		if err != nil {
-			log.CtxErrorw(ctx, "stream closed abnormally", "piece_key", pieceKey, "error", err)
-			return ErrClosedStream
+		if readErr == io.EOF {
+			break
 		}
-		pieceKey = u.baseApp.PieceOp().SegmentPieceKey(uploadObjectTask.GetObjectInfo().Id.Uint64(), segIdx)
-		checksums = append(checksums, hash.GenerateChecksum(data))
-		startPutPiece := time.Now()
-		err = u.baseApp.PieceStore().PutPiece(ctx, pieceKey, data)
-		metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_server_put_piece_cost").Observe(time.Since(startPutPiece).Seconds())
-		metrics.PerfPutObjectTime.WithLabelValues("uploader_put_object_server_put_piece_end").Observe(time.Since(time.Unix(uploadObjectTask.GetCreateTime(), 0)).Seconds())
-		if err != nil {
-			log.CtxErrorw(ctx, "failed to put segment piece to piece store", "error", err)
-			return ErrPieceStore
+	}
+	wg.Wait()
+	if err == nil {
+		err = putErr
+	}
+	if err == nil && ctx.Err() != nil {
+		log.CtxErrorw(ctx, "upload object canceled", "error", ctx.Err())
+		err = ErrClosedStream
+	}
+	if err != nil {
+		return nil, pieceKeys, readSize, err
+	}
+	return checksums, pieceKeys, readSize, nil
+}
+
+// deleteSegmentPieces deletes the segment pieces that have been put before the upload fails,
+// the deletion is best-effort, the leftovers are collected by the zombie piece gc. The upload
+// often fails because ctx is canceled, so the deletion is bounded by its own timeout instead
+// of the cancellation of ctx.
+func (u *UploadModular) deleteSegmentPieces(ctx context.Context, pieceKeys []string) {
+	deleteCtx, cancel := context.WithTimeout(uncanceledContext{ctx}, deleteSegmentPiecesTimeout)
+	defer cancel()
+	for _, pieceKey := range pieceKeys {
+		if err := u.baseApp.PieceStore().DeletePiece(deleteCtx, pieceKey); err != nil {
+			log.CtxWarnw(ctx, "failed to delete segment piece after upload failed", "piece_key", pieceKey, "error", err)
 		}
-		segIdx++
 	}
 }
 
+// uncanceledContext keeps the values of the parent context, e.g. the trace id for logging, but
+// is never canceled by the parent.
+type uncanceledContext struct {
+	context.Context
+}
+
+func (uncanceledContext) Deadline() (deadline time.Time, ok bool) { return }
+func (uncanceledContext) Done() <-chan struct{}                   { return nil }
+func (uncanceledContext) Err() error                              { return nil }
+
 func StreamReadAt(stream io.Reader, b []byte) (int, error) {
 	if len(b) == 0 {
 		return 0, fmt.Errorf("failed to read due to invalid args")
@@ -0,0 +1,187 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bnb-chain/greenfield-common/go/hash"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspapp"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
+	"github.com/bnb-chain/greenfield-storage-provider/base/gfsptqueue"
+	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
+	"github.com/bnb-chain/greenfield-storage-provider/core/module"
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	storagetypes "github.com/bnb-chain/greenfield/x/storage/types"
+)
+
+// faultyPieceStore delays the puts of the segment pieces and fails the put of failKey, the pieces
+// are put until their context is canceled.
+type faultyPieceStore struct {
+	multipartTestPieceStore
+
+	delay   func(key string) time.Duration
+	failKey string
+	deleted []string
+}
+
+func (s *faultyPieceStore) PutPiece(ctx context.Context, key string, value []byte) error {
+	if s.delay != nil {
+		select {
+		case <-time.After(s.delay(key)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if key == s.failKey {
+		return errors.New("mock piece store error")
+	}
+	return s.multipartTestPieceStore.PutPiece(ctx, key, value)
+}
+
+func (s *faultyPieceStore) DeletePiece(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mux.Lock()
+	s.deleted = append(s.deleted, key)
+	s.mux.Unlock()
+	return s.multipartTestPieceStore.DeletePiece(ctx, key)
+}
+
+func setupUploadTest(t *testing.T, db corespdb.SPDB, store *faultyPieceStore) *UploadModular {
+	baseApp, err := gfspapp.NewGfSpBaseApp(&gfspconfig.GfSpConfig{}, gfspconfig.CustomizeGfSpDB(db),
+		gfspconfig.CustomizePieceStore(store))
+	require.NoError(t, err)
+	scope, err := baseApp.ResourceManager().OpenService(module.UploadModularName)
+	require.NoError(t, err)
+	return &UploadModular{baseApp: baseApp, scope: scope, segmentParallel: 3,
+		uploadQueue: gfsptqueue.NewGfSpTQueue("test-upload-object", 1)}
+}
+
+func newUploadTestTask(payload string, maxSegmentSize uint64) *gfsptask.GfSpUploadObjectTask {
+	var checksums [][]byte
+	for i := 0; i < len(payload); i += int(maxSegmentSize) {
+		end := i + int(maxSegmentSize)
+		if end > len(payload) {
+			end = len(payload)
+		}
+		checksums = append(checksums, hash.GenerateChecksum([]byte(payload[i:end])))
+	}
+	task := &gfsptask.GfSpUploadObjectTask{}
+	task.InitUploadObjectTask(0, &storagetypes.ObjectInfo{Id: sdkmath.NewUint(1), PayloadSize: uint64(len(payload)),
+		ObjectStatus: storagetypes.OBJECT_STATUS_CREATED, Checksums: [][]byte{hash.GenerateIntegrityHash(checksums)}},
+		&storagetypes.Params{VersionedParams: storagetypes.VersionedParams{MaxSegmentSize: maxSegmentSize}}, 0)
+	return task
+}
+
+func TestUploadModular_PutSegmentPiecesInOrder(t *testing.T) {
+	const maxSegmentSize = 4
+	payload := "aaaabbbbccccddddeeeeff"
+	store := &faultyPieceStore{multipartTestPieceStore: multipartTestPieceStore{pieces: make(map[string][]byte)}}
+	uploader := setupUploadTest(t, corespdb.NewMockSPDB(gomock.NewController(t)), store)
+	pieceOp := uploader.baseApp.PieceOp()
+	// the earlier segments are put slower, so the puts complete in the reverse order
+	store.delay = func(key string) time.Duration {
+		for segIdx := uint32(0); segIdx < 6; segIdx++ {
+			if key == pieceOp.SegmentPieceKey(1, segIdx) {
+				return time.Duration(6-segIdx) * 10 * time.Millisecond
+			}
+		}
+		return 0
+	}
+
+	checksums, pieceKeys, readSize, err := uploader.putSegmentPieces(context.Background(),
+		newUploadTestTask(payload, maxSegmentSize), maxSegmentSize, 3, strings.NewReader(payload))
+	require.NoError(t, err)
+	assert.Equal(t, len(payload), readSize)
+	require.Len(t, checksums, 6)
+	require.Len(t, pieceKeys, 6)
+	for segIdx := 0; segIdx < 6; segIdx++ {
+		end := (segIdx + 1) * maxSegmentSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		segment := payload[segIdx*maxSegmentSize : end]
+		assert.Equal(t, hash.GenerateChecksum([]byte(segment)), checksums[segIdx])
+		assert.Equal(t, pieceOp.SegmentPieceKey(1, uint32(segIdx)), pieceKeys[segIdx])
+		assert.Equal(t, []byte(segment), store.pieces[pieceKeys[segIdx]])
+	}
+}
+
+func TestUploadModular_HandleUploadObjectTaskPartialFailure(t *testing.T) {
+	const maxSegmentSize = 4
+	payload := "aaaabbbbccccddddeeeeffff"
+	store := &faultyPieceStore{multipartTestPieceStore: multipartTestPieceStore{pieces: make(map[string][]byte)}}
+	// no integrity is written if the upload fails
+	uploader := setupUploadTest(t, corespdb.NewMockSPDB(gomock.NewController(t)), store)
+	pieceOp := uploader.baseApp.PieceOp()
+	store.failKey = pieceOp.SegmentPieceKey(1, 2)
+	store.delay = func(key string) time.Duration {
+		if key == store.failKey {
+			return 20 * time.Millisecond
+		}
+		return 0
+	}
+
+	task := newUploadTestTask(payload, maxSegmentSize)
+	err := uploader.HandleUploadObjectTask(context.Background(), task, strings.NewReader(payload))
+	assert.Equal(t, ErrPieceStore, err)
+	assert.Equal(t, ErrPieceStore, task.Error())
+	assert.False(t, uploader.uploadQueue.Has(task.Key()))
+	// the segment pieces put before and after the failure are all deleted
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	assert.Empty(t, store.pieces)
+	assert.Contains(t, store.deleted, pieceOp.SegmentPieceKey(1, 0))
+	assert.Contains(t, store.deleted, pieceOp.SegmentPieceKey(1, 1))
+	assert.Contains(t, store.deleted, pieceOp.SegmentPieceKey(1, 2))
+}
+
+func TestUploadModular_HandleUploadObjectTaskCanceled(t *testing.T) {
+	const maxSegmentSize = 4
+	payload := "aaaabbbbcccc"
+	store := &faultyPieceStore{multipartTestPieceStore: multipartTestPieceStore{pieces: make(map[string][]byte)}}
+	uploader := setupUploadTest(t, corespdb.NewMockSPDB(gomock.NewController(t)), store)
+	ctx, cancel := context.WithCancel(context.Background())
+	// the stream is broken after the first segment, e.g. the client goes away
+	reader := &cancelReader{Reader: bytes.NewReader([]byte(payload)), cancel: cancel, after: maxSegmentSize}
+
+	task := newUploadTestTask(payload, maxSegmentSize)
+	err := uploader.HandleUploadObjectTask(ctx, task, reader)
+	assert.Equal(t, ErrClosedStream, err)
+	// the pieces are deleted even though the upload context has been canceled
+	store.mux.Lock()
+	defer store.mux.Unlock()
+	assert.Empty(t, store.pieces)
+	assert.NotEmpty(t, store.deleted)
+}
+
+// cancelReader cancels the context and fails the read after the given bytes are read.
+type cancelReader struct {
+	*bytes.Reader
+	cancel context.CancelFunc
+	after  int
+	read   int
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	if r.read >= r.after {
+		r.cancel()
+		return 0, context.Canceled
+	}
+	if len(p) > r.after-r.read {
+		p = p[:r.after-r.read]
+	}
+	n, err := r.Reader.Read(p)
+	r.read += n
+	return n, err
+}
@@ -16,6 +16,9 @@ type UploadModular struct {
 	scope                 rcmgr.ResourceScope
 	uploadQueue           taskqueue.TQueueOnStrategy
 	resumeableUploadQueue taskqueue.TQueueOnStrategy
+	// segmentParallel defines the max number of segment pieces of one object
+	// that are put to piece store concurrently.
+	segmentParallel int
 }
 
 func (u *UploadModular) Name() string {
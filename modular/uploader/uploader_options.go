@@ -10,6 +10,9 @@ const (
 	// DefaultUploadObjectParallelPerNode defines the default max parallel of uploading
 	// object per uploader.
 	DefaultUploadObjectParallelPerNode = 10240
+	// DefaultUploadSegmentParallelPerObject defines the default max number of segment
+	// pieces of one object that are put to piece store concurrently.
+	DefaultUploadSegmentParallelPerObject = 4
 )
 
 func NewUploadModular(app *gfspapp.GfSpBaseApp, cfg *gfspconfig.GfSpConfig) (coremodule.Modular, error) {
@@ -24,6 +27,10 @@ func DefaultUploaderOptions(uploader *UploadModular, cfg *gfspconfig.GfSpConfig)
 	if cfg.Parallel.UploadObjectParallelPerNode == 0 {
 		cfg.Parallel.UploadObjectParallelPerNode = DefaultUploadObjectParallelPerNode
 	}
+	if cfg.Parallel.UploadSegmentParallelPerObject == 0 {
+		cfg.Parallel.UploadSegmentParallelPerObject = DefaultUploadSegmentParallelPerObject
+	}
+	uploader.segmentParallel = cfg.Parallel.UploadSegmentParallelPerObject
 	uploader.uploadQueue = cfg.Customize.NewStrategyTQueueFunc(
 		uploader.Name()+"-upload-object", cfg.Parallel.UploadObjectParallelPerNode)
 	uploader.resumeableUploadQueue = cfg.Customize.NewStrategyTQueueFunc(
@@ -42,6 +42,10 @@ var MetricsItems = []prometheus.Collector{
 	ExecutorCounter,
 	ExecutorTime,
 	GCObjectCounter,
+	GCZombiePieceCounter,
+	GCMetaCounter,
+	ScrubPieceCounter,
+	ScrubBytesCounter,
 	MaxTaskNumberGauge,
 	RunningTaskNumberGauge,
 	RemainingMemoryGauge,
@@ -204,6 +208,22 @@ var (
 		Name: "delete_object_number",
 		Help: "Track deleted object number.",
 	}, []string{"delete_object_number"})
+	GCZombiePieceCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "delete_zombie_piece_number",
+		Help: "Track deleted zombie piece number.",
+	}, []string{"delete_zombie_piece_number"})
+	GCMetaCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "delete_meta_number",
+		Help: "Track deleted sp db meta number.",
+	}, []string{"delete_meta_number"})
+	ScrubPieceCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "scrub_piece_number",
+		Help: "Track scrubbed piece number by the result state.",
+	}, []string{"state"})
+	ScrubBytesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "scrub_piece_bytes",
+		Help: "Track the size of the scrubbed piece data.",
+	}, []string{"scrub_piece_bytes"})
 
 	// manager mertics items
 	ManagerCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
@@ -0,0 +1,227 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	corsOriginHeader           = "Origin"
+	corsVaryHeader             = "Vary"
+	corsRequestMethodHeader    = "Access-Control-Request-Method"
+	corsRequestHeadersHeader   = "Access-Control-Request-Headers"
+	corsAllowOriginHeader      = "Access-Control-Allow-Origin"
+	corsAllowMethodsHeader     = "Access-Control-Allow-Methods"
+	corsAllowHeadersHeader     = "Access-Control-Allow-Headers"
+	corsExposeHeadersHeader    = "Access-Control-Expose-Headers"
+	corsAllowCredentialsHeader = "Access-Control-Allow-Credentials"
+	corsMaxAgeHeader           = "Access-Control-Max-Age"
+	corsWildcard               = "*"
+)
+
+// CORSConfig defines the cross-origin resource sharing policy of the browser requests.
+type CORSConfig struct {
+	// AllowedOrigins defines the origins which are allowed, "*" allows all the origins, and one wildcard
+	// can be used in the origin, e.g. https://*.example.com. The cors is disabled if it is empty.
+	AllowedOrigins []string
+	// AllowedMethods defines the methods which are allowed by the preflight request.
+	AllowedMethods []string
+	// AllowedHeaders defines the request headers which are allowed by the preflight request, "*" allows all.
+	AllowedHeaders []string
+	// ExposedHeaders defines the response headers which can be read by the browser scripts.
+	ExposedHeaders []string
+	// AllowCredentials defines whether the requests can carry the cookies and the authorization headers.
+	AllowCredentials bool
+	// MaxAgeSec defines how long the preflight result can be cached by the browsers, 0 means no cache.
+	MaxAgeSec int64
+}
+
+// ErrCORSWildcardCredentials is returned if the credentials are allowed for the wildcard origin.
+var ErrCORSWildcardCredentials = errors.New("the credentials can not be allowed for the wildcard origin")
+
+// Validate checks the cors policy, the credentials can not be allowed for the wildcard origin, otherwise
+// any site can read the responses of the requests carrying the cookies and the authorization headers.
+func (c *CORSConfig) Validate() error {
+	if !c.AllowCredentials {
+		return nil
+	}
+	for _, pattern := range c.AllowedOrigins {
+		if pattern == corsWildcard {
+			return ErrCORSWildcardCredentials
+		}
+	}
+	return nil
+}
+
+func matchOrigin(pattern, origin string) bool {
+	if pattern == corsWildcard || strings.EqualFold(pattern, origin) {
+		return true
+	}
+	prefix, suffix, ok := strings.Cut(strings.ToLower(pattern), corsWildcard)
+	origin = strings.ToLower(origin)
+	return ok && len(origin) >= len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix)
+}
+
+func (c *CORSConfig) allowOrigin(origin string) bool {
+	for _, pattern := range c.AllowedOrigins {
+		if matchOrigin(pattern, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CORSConfig) allowMethod(method string) bool {
+	for _, m := range c.AllowedMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CORSConfig) allowHeaders(headers string) bool {
+	for _, header := range strings.Split(headers, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		allowed := false
+		for _, h := range c.AllowedHeaders {
+			if h == corsWildcard || strings.EqualFold(h, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// CORSPolicyLookup returns the cors policy of the request, e.g. the policy of the requested bucket,
+// the default policy is used if it returns nil.
+type CORSPolicyLookup func(r *http.Request) *CORSConfig
+
+// CORS handles the preflight requests and sets the cors headers of the actual requests.
+type CORS struct {
+	defaultCfg *CORSConfig
+	lookup     CORSPolicyLookup
+}
+
+// NewCORS returns the CORS with the default policy, the lookup can be nil if there are no overrides.
+func NewCORS(cfg CORSConfig, lookup CORSPolicyLookup) *CORS {
+	return &CORS{defaultCfg: &cfg, lookup: lookup}
+}
+
+func (c *CORS) policy(r *http.Request) *CORSConfig {
+	if c.lookup != nil {
+		if cfg := c.lookup(r); cfg != nil {
+			return cfg
+		}
+	}
+	return c.defaultCfg
+}
+
+func setAllowOrigin(w http.ResponseWriter, cfg *CORSConfig, origin string) {
+	if cfg.AllowCredentials {
+		// the wildcard origin is not accepted by the browsers for the credentialed requests
+		w.Header().Set(corsAllowOriginHeader, origin)
+		w.Header().Set(corsAllowCredentialsHeader, "true")
+		return
+	}
+	for _, pattern := range cfg.AllowedOrigins {
+		if pattern == corsWildcard {
+			w.Header().Set(corsAllowOriginHeader, corsWildcard)
+			return
+		}
+	}
+	w.Header().Set(corsAllowOriginHeader, origin)
+}
+
+// setCORSHeaders sets the cors headers of the actual request by the policy, the headers set by the
+// default policy before are replaced.
+func setCORSHeaders(w http.ResponseWriter, cfg *CORSConfig, origin string) {
+	if !containsHeaderValue(w.Header(), corsVaryHeader, corsOriginHeader) {
+		w.Header().Add(corsVaryHeader, corsOriginHeader)
+	}
+	w.Header().Del(corsAllowOriginHeader)
+	w.Header().Del(corsAllowCredentialsHeader)
+	w.Header().Del(corsExposeHeadersHeader)
+	if !cfg.allowOrigin(origin) {
+		return
+	}
+	setAllowOrigin(w, cfg, origin)
+	if len(cfg.ExposedHeaders) > 0 {
+		w.Header().Set(corsExposeHeadersHeader, strings.Join(cfg.ExposedHeaders, ", "))
+	}
+}
+
+func containsHeaderValue(header http.Header, key, value string) bool {
+	for _, v := range header.Values(key) {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isActualCORSRequest(r *http.Request) bool {
+	isPreflight := r.Method == http.MethodOptions && r.Header.Get(corsRequestMethodHeader) != ""
+	return r.Header.Get(corsOriginHeader) != "" && !isPreflight
+}
+
+// DefaultHandler is the http middleware which sets the cors headers of the actual requests by the default
+// policy without looking up the overrides, it is used before the rate limiter, so the browsers can read the
+// rejected responses without the limited requests looking up the policies.
+func (c *CORS) DefaultHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isActualCORSRequest(r) {
+			setCORSHeaders(w, c.defaultCfg, r.Header.Get(corsOriginHeader))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Handler is the http middleware which sets the cors headers of the actual requests, the preflight
+// requests are left to Preflight.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isActualCORSRequest(r) {
+			setCORSHeaders(w, c.policy(r), r.Header.Get(corsOriginHeader))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Preflight handles the preflight request, it responds 403 if the origin, the method or any of
+// the headers of the actual request is not allowed by the policy.
+func (c *CORS) Preflight(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add(corsVaryHeader, corsOriginHeader)
+	w.Header().Add(corsVaryHeader, corsRequestMethodHeader)
+	w.Header().Add(corsVaryHeader, corsRequestHeadersHeader)
+	var (
+		origin         = r.Header.Get(corsOriginHeader)
+		method         = r.Header.Get(corsRequestMethodHeader)
+		requestHeaders = r.Header.Get(corsRequestHeadersHeader)
+		cfg            = c.policy(r)
+	)
+	if origin == "" || method == "" || !cfg.allowOrigin(origin) || !cfg.allowMethod(method) ||
+		!cfg.allowHeaders(requestHeaders) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	setAllowOrigin(w, cfg, origin)
+	w.Header().Set(corsAllowMethodsHeader, strings.Join(cfg.AllowedMethods, ", "))
+	if requestHeaders != "" {
+		w.Header().Set(corsAllowHeadersHeader, requestHeaders)
+	}
+	if cfg.MaxAgeSec > 0 {
+		w.Header().Set(corsMaxAgeHeader, strconv.FormatInt(cfg.MaxAgeSec, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		origin  string
+		wanted  bool
+	}{
+		{"*", "https://example.com", true},
+		{"https://example.com", "https://EXAMPLE.com", true},
+		{"https://*.example.com", "https://app.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "http://app.example.com", false},
+		{"https://example.com", "https://example.org", false},
+	}
+	for _, tt := range testCases {
+		assert.Equal(t, tt.wanted, matchOrigin(tt.pattern, tt.origin), tt.pattern+" "+tt.origin)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	override := &CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{http.MethodGet},
+		AllowedHeaders:   []string{"Authorization"},
+		AllowCredentials: true,
+		MaxAgeSec:        600,
+	}
+	cors := NewCORS(CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPut},
+		AllowedHeaders: []string{"*"},
+	}, func(r *http.Request) *CORSConfig {
+		if r.URL.Path == "/override" {
+			return override
+		}
+		return nil
+	})
+
+	testCases := []struct {
+		name              string
+		path              string
+		origin            string
+		method            string
+		headers           string
+		wantedCode        int
+		wantedOrigin      string
+		wantedMaxAge      string
+		wantedCredentials string
+	}{
+		{"default policy", "/", "https://a.com", http.MethodPut, "X-Custom", http.StatusOK, "*", "", ""},
+		{"default policy, method not allowed", "/", "https://a.com", http.MethodDelete, "", http.StatusForbidden, "", "", ""},
+		{"no origin", "/", "", http.MethodGet, "", http.StatusForbidden, "", "", ""},
+		{"override policy", "/override", "https://app.example.com", http.MethodGet, "authorization", http.StatusOK,
+			"https://app.example.com", "600", "true"},
+		{"override policy, origin not allowed", "/override", "https://a.com", http.MethodGet, "", http.StatusForbidden, "", "", ""},
+		{"override policy, header not allowed", "/override", "https://app.example.com", http.MethodGet, "X-Custom",
+			http.StatusForbidden, "", "", ""},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "http://localhost"+tt.path, nil)
+			if tt.origin != "" {
+				req.Header.Set(corsOriginHeader, tt.origin)
+			}
+			req.Header.Set(corsRequestMethodHeader, tt.method)
+			if tt.headers != "" {
+				req.Header.Set(corsRequestHeadersHeader, tt.headers)
+			}
+			w := httptest.NewRecorder()
+			cors.Preflight(w, req)
+			assert.Equal(t, tt.wantedCode, w.Code)
+			assert.Equal(t, tt.wantedOrigin, w.Header().Get(corsAllowOriginHeader))
+			assert.Equal(t, tt.wantedMaxAge, w.Header().Get(corsMaxAgeHeader))
+			assert.Equal(t, tt.wantedCredentials, w.Header().Get(corsAllowCredentialsHeader))
+		})
+	}
+}
+
+func TestCORSHandler(t *testing.T) {
+	cors := NewCORS(CORSConfig{
+		AllowedOrigins: []string{"https://*.example.com"},
+		ExposedHeaders: []string{"ETag", "Content-Range"},
+	}, nil)
+	handler := cors.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/object", nil)
+	req.Header.Set(corsOriginHeader, "https://app.example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, "https://app.example.com", w.Header().Get(corsAllowOriginHeader))
+	assert.Equal(t, "ETag, Content-Range", w.Header().Get(corsExposeHeadersHeader))
+	assert.Equal(t, corsOriginHeader, w.Header().Get(corsVaryHeader))
+
+	req = httptest.NewRequest(http.MethodGet, "http://localhost/object", nil)
+	req.Header.Set(corsOriginHeader, "https://example.org")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(corsAllowOriginHeader))
+}
+
+func TestCORSDefaultHandler(t *testing.T) {
+	override := &CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}
+	lookups := 0
+	cors := NewCORS(CORSConfig{AllowedOrigins: []string{"*"}}, func(r *http.Request) *CORSConfig {
+		lookups++
+		if r.URL.Path == "/override" {
+			return override
+		}
+		return nil
+	})
+	// the limiter rejects the requests of the /limited path between the default handler and the handler
+	handler := cors.DefaultHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		cors.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(w, r)
+	}))
+
+	testCases := []struct {
+		name              string
+		path              string
+		origin            string
+		wantedCode        int
+		wantedOrigin      string
+		wantedCredentials string
+		wantedLookups     int
+	}{
+		{"limited", "/limited", "https://a.com", http.StatusTooManyRequests, "*", "", 0},
+		{"default policy", "/", "https://a.com", http.StatusOK, "*", "", 1},
+		{"override policy", "/override", "https://app.example.com", http.StatusOK, "https://app.example.com", "true", 1},
+		{"override policy, origin not allowed", "/override", "https://a.com", http.StatusOK, "", "", 1},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			lookups = 0
+			req := httptest.NewRequest(http.MethodGet, "http://localhost"+tt.path, nil)
+			req.Header.Set(corsOriginHeader, tt.origin)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantedCode, w.Code)
+			assert.Equal(t, tt.wantedOrigin, w.Header().Get(corsAllowOriginHeader))
+			assert.Equal(t, tt.wantedCredentials, w.Header().Get(corsAllowCredentialsHeader))
+			assert.Equal(t, []string{corsOriginHeader}, w.Header().Values(corsVaryHeader))
+			assert.Equal(t, tt.wantedLookups, lookups)
+		})
+	}
+}
+
+func TestCORSConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&CORSConfig{AllowedOrigins: []string{"*"}}).Validate())
+	assert.NoError(t, (&CORSConfig{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true}).Validate())
+	assert.ErrorIs(t, (&CORSConfig{AllowedOrigins: []string{"https://example.com", "*"}, AllowCredentials: true}).Validate(),
+		ErrCORSWildcardCredentials)
+}
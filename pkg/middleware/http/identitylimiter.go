@@ -0,0 +1,310 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	slimiter "github.com/ulule/limiter/v3"
+	"golang.org/x/time/rate"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	accountLimiterPrefix = "account_"
+	bucketLimiterPrefix  = "bucket_"
+	// identityLimiterCacheSize defines the max number of the accounts or the buckets whose limiters are
+	// kept in memory, the evicted ones restart with the full token buckets
+	identityLimiterCacheSize = 100000
+	retryAfterHeader         = "Retry-After"
+)
+
+// IdentityLimiterCell defines the request rate and the bandwidth budgets of the specified account or bucket.
+type IdentityLimiterCell struct {
+	Key            string // the account address or the bucket name
+	RateLimit      int    // the number of the requests per RatePeriod, 0 means unlimited
+	RatePeriod     string // e.g. S, M, H
+	BandwidthLimit int    // the bytes per second of the request and the response body, 0 means unlimited
+	BandwidthBurst int    // the bytes which can be transferred at once, defaults to BandwidthLimit
+}
+
+// IdentityLimitConfig defines the limits keyed by the authenticated account or by the bucket name, every
+// account or bucket has its own budgets.
+type IdentityLimitConfig struct {
+	On             bool
+	RateLimit      int
+	RatePeriod     string
+	BandwidthLimit int
+	BandwidthBurst int
+	// Overrides defines the budgets of the specified accounts or buckets instead of the default ones
+	Overrides []IdentityLimiterCell
+}
+
+type identityBudget struct {
+	rate           slimiter.Rate
+	requests       rate.Limit
+	requestsBurst  int
+	bandwidth      rate.Limit
+	bandwidthBurst int
+}
+
+func newIdentityBudget(rateLimit int, ratePeriod string, bandwidthLimit, bandwidthBurst int) (identityBudget, error) {
+	budget := identityBudget{}
+	if rateLimit > 0 {
+		r, err := slimiter.NewRateFromFormatted(fmt.Sprintf("%d-%s", rateLimit, ratePeriod))
+		if err != nil {
+			return budget, err
+		}
+		budget.rate = r
+		// the requests of a whole period can be sent at once, then the tokens are refilled evenly
+		budget.requests = rate.Limit(float64(r.Limit) / r.Period.Seconds())
+		budget.requestsBurst = int(r.Limit)
+	}
+	if bandwidthLimit > 0 {
+		budget.bandwidth = rate.Limit(bandwidthLimit)
+		budget.bandwidthBurst = bandwidthBurst
+		if budget.bandwidthBurst <= 0 {
+			budget.bandwidthBurst = bandwidthLimit
+		}
+	}
+	return budget, nil
+}
+
+// identityLimiters are the token buckets of an account or a bucket, nil means unlimited.
+type identityLimiters struct {
+	requests  *rate.Limiter
+	bandwidth *BandwidthLimiter
+}
+
+// identityLimiter limits the request rate and the bandwidth of every account or bucket, the request rate
+// is limited by the shared store if the counters are shared by the gateway replicas, the bandwidth is
+// always limited locally.
+type identityLimiter struct {
+	prefix        string
+	defaultBudget identityBudget
+	overrides     map[string]identityBudget
+	limiters      *lru.Cache
+	shared        *sharedStore
+}
+
+func newIdentityLimiter(prefix string, cfg IdentityLimitConfig, shared *sharedStore) (*identityLimiter, error) {
+	if !cfg.On {
+		return nil, nil
+	}
+	var err error
+	l := &identityLimiter{prefix: prefix, overrides: make(map[string]identityBudget), shared: shared}
+	if l.defaultBudget, err = newIdentityBudget(cfg.RateLimit, cfg.RatePeriod, cfg.BandwidthLimit,
+		cfg.BandwidthBurst); err != nil {
+		return nil, err
+	}
+	for _, c := range cfg.Overrides {
+		if l.overrides[strings.ToLower(c.Key)], err = newIdentityBudget(c.RateLimit, c.RatePeriod, c.BandwidthLimit,
+			c.BandwidthBurst); err != nil {
+			return nil, err
+		}
+	}
+	if l.limiters, err = lru.New(identityLimiterCacheSize); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *identityLimiter) budget(key string) identityBudget {
+	if budget, ok := l.overrides[key]; ok {
+		return budget
+	}
+	return l.defaultBudget
+}
+
+func (l *identityLimiter) get(key string) *identityLimiters {
+	if limiters, ok := l.limiters.Get(key); ok {
+		return limiters.(*identityLimiters)
+	}
+	budget := l.budget(key)
+	limiters := &identityLimiters{}
+	if budget.requests > 0 {
+		limiters.requests = rate.NewLimiter(budget.requests, budget.requestsBurst)
+	}
+	if budget.bandwidth > 0 {
+		limiters.bandwidth = &BandwidthLimiter{Limiter: rate.NewLimiter(budget.bandwidth, budget.bandwidthBurst)}
+	}
+	if previous, ok, _ := l.limiters.PeekOrAdd(key, limiters); ok {
+		return previous.(*identityLimiters)
+	}
+	return limiters
+}
+
+// allow takes a request token of the key, returns false and the duration to retry after if the request
+// rate of the key is reached.
+func (l *identityLimiter) allow(ctx context.Context, key string) (bool, time.Duration) {
+	if l.shared != nil {
+		budget := l.budget(key)
+		if budget.rate.Limit == 0 {
+			return true, 0
+		}
+		limiterCtx, err := l.shared.Increment(ctx, l.prefix+key, 1, budget.rate)
+		if err == nil {
+			if !limiterCtx.Reached {
+				return true, 0
+			}
+			return false, time.Until(time.Unix(limiterCtx.Reset, 0))
+		}
+		log.CtxDebugw(ctx, "failed to increment shared rate limit counter, fall back to local limiter",
+			"key", l.prefix+key, "error", err)
+	}
+	limiter := l.get(key).requests
+	if limiter == nil {
+		return true, 0
+	}
+	now := time.Now()
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// bandwidthLimiter returns the bandwidth limiter of the key, returns nil if the bandwidth is unlimited.
+func (l *identityLimiter) bandwidthLimiter(key string) *BandwidthLimiter {
+	return l.get(key).bandwidth
+}
+
+// waitN blocks until n bytes are allowed, n can exceed the burst of the limiter.
+func (b *BandwidthLimiter) waitN(ctx context.Context, n int) error {
+	for n > 0 {
+		size := n
+		if burst := b.Limiter.Burst(); size > burst {
+			size = burst
+		}
+		if err := b.Limiter.WaitN(ctx, size); err != nil {
+			return err
+		}
+		n -= size
+	}
+	return nil
+}
+
+type identityContextKey struct{}
+
+// requestIdentity records the bandwidth limiters of the bucket and the account of the request, the account
+// is added after the signature is verified.
+type requestIdentity struct {
+	mu         sync.RWMutex
+	limiters   []*BandwidthLimiter
+	retryAfter time.Duration
+}
+
+func (i *requestIdentity) addLimiter(l *BandwidthLimiter) {
+	if l == nil {
+		return
+	}
+	i.mu.Lock()
+	i.limiters = append(i.limiters, l)
+	i.mu.Unlock()
+}
+
+func (i *requestIdentity) setRetryAfter(retryAfter time.Duration) {
+	i.mu.Lock()
+	i.retryAfter = retryAfter
+	i.mu.Unlock()
+}
+
+func (i *requestIdentity) waitN(ctx context.Context, n int) error {
+	i.mu.RLock()
+	limiters := i.limiters
+	i.mu.RUnlock()
+	for _, l := range limiters {
+		if err := l.waitN(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limitedBody limits the bandwidth of reading the request body.
+type limitedBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	identity *requestIdentity
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := b.identity.waitN(b.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// limitedResponseWriter limits the bandwidth of writing the response body, and sets the Retry-After header
+// if the request is rejected by the account limiter.
+type limitedResponseWriter struct {
+	http.ResponseWriter
+	ctx      context.Context
+	identity *requestIdentity
+}
+
+func (w *limitedResponseWriter) WriteHeader(code int) {
+	w.identity.mu.RLock()
+	retryAfter := w.identity.retryAfter
+	w.identity.mu.RUnlock()
+	if code == http.StatusTooManyRequests && retryAfter > 0 {
+		setRetryAfter(w.ResponseWriter, retryAfter)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *limitedResponseWriter) Write(p []byte) (int, error) {
+	if err := w.identity.waitN(w.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *limitedResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// setRetryAfter sets the Retry-After header in seconds, it is at least one second.
+func setRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set(retryAfterHeader, strconv.FormatInt(seconds, 10))
+}
+
+// LimitAccount applies the request rate and the bandwidth limits of the account to the request, it should be
+// called once the account is authenticated. It returns false if the request rate of the account is reached,
+// then the request should be responded with http.StatusTooManyRequests.
+func LimitAccount(r *http.Request, account string) bool {
+	if limiter == nil || limiter.accountLimiter == nil || account == "" {
+		return true
+	}
+	account = strings.ToLower(account)
+	identity, _ := r.Context().Value(identityContextKey{}).(*requestIdentity)
+	ok, retryAfter := limiter.accountLimiter.allow(r.Context(), account)
+	if !ok {
+		if identity != nil {
+			identity.setRetryAfter(retryAfter)
+		}
+		return false
+	}
+	if identity != nil {
+		identity.addLimiter(limiter.accountLimiter.bandwidthLimiter(account))
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityLimiter(t *testing.T) {
+	require.NoError(t, NewAPILimiter(&APILimiterConfig{
+		AccountLimitCfg: IdentityLimitConfig{
+			On: true, RateLimit: 1, RatePeriod: "M",
+			Overrides: []IdentityLimiterCell{{Key: "0xHeavy", RateLimit: 3, RatePeriod: "M"}},
+		},
+		BucketLimitCfg: IdentityLimitConfig{On: true, RateLimit: 2, RatePeriod: "M", BandwidthLimit: 1024 * 1024},
+	}))
+	defer func() { limiter = nil }()
+
+	router := mux.NewRouter()
+	router.Path("/{bucket}/{object}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !LimitAccount(r, r.Header.Get("X-Account")) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	})
+	router.Use(Limit)
+	do := func(bucket, account string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "http://localhost/"+bucket+"/object", strings.NewReader("payload"))
+		req.Header.Set("X-Account", account)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// the account limit
+	w := do("bucket-a", "0xLight")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "payload", w.Body.String())
+	w = do("bucket-b", "0xlight")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get(retryAfterHeader))
+
+	// the overridden account limit
+	assert.Equal(t, http.StatusOK, do("bucket-c", "0xheavy").Code)
+	assert.Equal(t, http.StatusOK, do("bucket-c", "0xheavy").Code)
+	assert.Equal(t, http.StatusOK, do("bucket-d", "0xheavy").Code)
+	assert.Equal(t, http.StatusTooManyRequests, do("bucket-d", "0xheavy").Code)
+
+	// the bucket limit, bucket-c has been requested twice
+	w = do("bucket-c", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get(retryAfterHeader))
+	assert.Equal(t, http.StatusOK, do("bucket-e", "").Code)
+}
+
+func TestIdentityLimiter_StaleSharedCounters(t *testing.T) {
+	backend := &mockCounterStore{counts: make(map[string]int64), unavailable: true}
+	require.NoError(t, NewAPILimiter(&APILimiterConfig{
+		IPLimitCfg:         IPLimitConfig{On: true, RateLimit: 2, RatePeriod: "M"},
+		AccountLimitCfg:    IdentityLimitConfig{On: true, RateLimit: 1, RatePeriod: "M"},
+		SharedStore:        SharedStoreConfig{On: true, SyncIntervalMillisecond: 3600 * 1000},
+		SharedCounterStore: backend,
+	}))
+	defer func() {
+		limiter.shared.stop()
+		limiter = nil
+	}()
+	limiter.shared.mu.Lock()
+	limiter.shared.lastSynced = time.Now().Add(-sharedStoreStaleSyncs*time.Hour - time.Second)
+	limiter.shared.mu.Unlock()
+
+	// the shared counters are stale, the account and the ip are limited by the local limiters
+	ok, _ := limiter.accountLimiter.allow(context.Background(), "0xa")
+	assert.True(t, ok)
+	ok, retryAfter := limiter.accountLimiter.allow(context.Background(), "0xa")
+	assert.False(t, ok)
+	assert.Positive(t, retryAfter)
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/bucket/object", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	for i := 0; i < 2; i++ {
+		ok, _ = limiter.HTTPAllow(context.Background(), req)
+		assert.True(t, ok)
+	}
+	ok, _ = limiter.HTTPAllow(context.Background(), req)
+	assert.False(t, ok)
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -9,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gorilla/mux"
 	slimiter "github.com/ulule/limiter/v3"
 	smemory "github.com/ulule/limiter/v3/drivers/store/memory"
 
@@ -32,6 +34,12 @@ type RateLimiterConfig struct {
 	PathPattern []RateLimiterCell
 	HostPattern []RateLimiterCell
 	APILimits   []RateLimiterCell
+	// AccountLimitCfg limits the requests by the authenticated account
+	AccountLimitCfg IdentityLimitConfig
+	// BucketLimitCfg limits the requests by the bucket name
+	BucketLimitCfg IdentityLimitConfig
+	// SharedStore defines whether the counters are shared by the gateway replicas
+	SharedStore SharedStoreConfig
 }
 
 type MemoryLimiterConfig struct {
@@ -40,27 +48,53 @@ type MemoryLimiterConfig struct {
 }
 
 type APILimiterConfig struct {
-	IPLimitCfg  IPLimitConfig
-	PathPattern map[string]MemoryLimiterConfig
-	APILimits   map[string]MemoryLimiterConfig // routePrefix-apiName  =>  limit config
-	HostPattern map[string]MemoryLimiterConfig
+	IPLimitCfg      IPLimitConfig
+	PathPattern     map[string]MemoryLimiterConfig
+	APILimits       map[string]MemoryLimiterConfig // routePrefix-apiName  =>  limit config
+	HostPattern     map[string]MemoryLimiterConfig
+	AccountLimitCfg IdentityLimitConfig
+	BucketLimitCfg  IdentityLimitConfig
+	SharedStore     SharedStoreConfig
+	// SharedCounterStore is used to share the counters if SharedStore is on, the counters are local if it is nil
+	SharedCounterStore SharedCounterStore
 }
 
 type apiLimiter struct {
-	store      slimiter.Store
-	limiterMap sync.Map
-	cfg        APILimiterConfig
+	store slimiter.Store
+	// localStore counts the requests locally if the shared counters are stale
+	localStore     slimiter.Store
+	shared         *sharedStore
+	limiterMap     sync.Map
+	cfg            APILimiterConfig
+	accountLimiter *identityLimiter
+	bucketLimiter  *identityLimiter
 }
 
 var limiter *apiLimiter
 
 func NewAPILimiter(cfg *APILimiterConfig) error {
+	if limiter != nil && limiter.shared != nil {
+		limiter.shared.stop()
+	}
 	localStore := smemory.NewStoreWithOptions(slimiter.StoreOptions{
 		Prefix:          "sp_api_rate_limiter",
 		CleanUpInterval: 5 * time.Second,
 	})
+	store := localStore
+	var shared *sharedStore
+	if cfg.SharedStore.On {
+		if cfg.SharedCounterStore == nil {
+			log.Warn("no shared counter store, the rate limit counters are local")
+		} else {
+			shared = newSharedStore(cfg.SharedCounterStore,
+				time.Duration(cfg.SharedStore.SyncIntervalMillisecond)*time.Millisecond)
+			store = shared
+		}
+	}
 	limiter = &apiLimiter{
-		store: localStore,
+		store:      store,
+		localStore: localStore,
+		shared:     shared,
 		cfg: APILimiterConfig{
 			APILimits:   make(map[string]MemoryLimiterConfig),
 			PathPattern: make(map[string]MemoryLimiterConfig),
@@ -72,6 +106,13 @@ func NewAPILimiter(cfg *APILimiterConfig) error {
 	var err error
 	var rate slimiter.Rate
 
+	if limiter.accountLimiter, err = newIdentityLimiter(accountLimiterPrefix, cfg.AccountLimitCfg, shared); err != nil {
+		return err
+	}
+	if limiter.bucketLimiter, err = newIdentityLimiter(bucketLimiterPrefix, cfg.BucketLimitCfg, shared); err != nil {
+		return err
+	}
+
 	for k, v := range cfg.PathPattern {
 		limiter.cfg.PathPattern[strings.ToLower(k)] = v
 	}
@@ -86,7 +127,7 @@ func NewAPILimiter(cfg *APILimiterConfig) error {
 			return err
 		}
 
-		limiter.limiterMap.Store(strings.ToLower(k), slimiter.New(localStore, rate))
+		limiter.limiterMap.Store(strings.ToLower(k), slimiter.New(store, rate))
 	}
 
 	return nil
@@ -125,7 +166,7 @@ func (a *apiLimiter) findLimiter(host, path, key string) *slimiter.Limiter {
 	return nil
 }
 
-func (t *apiLimiter) Allow(ctx context.Context, r *http.Request) bool {
+func (t *apiLimiter) Allow(ctx context.Context, r *http.Request) (bool, time.Duration) {
 	path := strings.ToLower(r.RequestURI)
 	host := r.Host
 	key := host + "-" + path
@@ -133,23 +174,23 @@ func (t *apiLimiter) Allow(ctx context.Context, r *http.Request) bool {
 
 	l := t.findLimiter(host, path, key)
 	if l == nil {
-		return true
+		return true, 0
 	}
 
-	limiterCtx, err := t.store.Increment(ctx, key, 1, l.Rate)
+	limiterCtx, err := t.increment(ctx, key, l.Rate)
 	if err != nil {
-		return true
+		return true, 0
 	}
 
 	if limiterCtx.Reached {
-		return false
+		return false, time.Until(time.Unix(limiterCtx.Reset, 0))
 	}
-	return true
+	return true, 0
 }
 
-func (t *apiLimiter) HTTPAllow(ctx context.Context, r *http.Request) bool {
+func (t *apiLimiter) HTTPAllow(ctx context.Context, r *http.Request) (bool, time.Duration) {
 	if !t.cfg.IPLimitCfg.On {
-		return true
+		return true, 0
 	}
 	ipStr := GetIP(r)
 	key := "ip_" + ipStr
@@ -157,34 +198,66 @@ func (t *apiLimiter) HTTPAllow(ctx context.Context, r *http.Request) bool {
 	rate, err := slimiter.NewRateFromFormatted(fmt.Sprintf("%d-%s", t.cfg.IPLimitCfg.RateLimit, t.cfg.IPLimitCfg.RatePeriod))
 	if err != nil {
 		log.Errorw("failed to new rate from formatted", "err", err)
-		return true
+		return true, 0
 	}
-	limiterCtx, err := t.store.Increment(ctx, key, 1, rate)
+	limiterCtx, err := t.increment(ctx, key, rate)
 	if err != nil {
-		return true
+		return true, 0
 	}
 
 	if limiterCtx.Reached {
-		return false
+		return false, time.Until(time.Unix(limiterCtx.Reset, 0))
 	}
-	return true
+	return true, 0
+}
+
+// increment increments the counter of the key, the local counter is used if the shared counters are stale.
+func (t *apiLimiter) increment(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	limiterCtx, err := t.store.Increment(ctx, key, 1, rate)
+	if errors.Is(err, ErrSharedCountersStale) {
+		log.CtxDebugw(ctx, "shared rate limit counters are stale, fall back to local counter", "key", key)
+		return t.localStore.Increment(ctx, key, 1, rate)
+	}
+	return limiterCtx, err
+}
+
+func tooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	setRetryAfter(w, retryAfter)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
 }
 
 func Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !limiter.Allow(context.Background(), r) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusTooManyRequests)
+		if ok, retryAfter := limiter.Allow(context.Background(), r); !ok {
+			tooManyRequests(w, retryAfter)
 			return
 		}
 
-		if !limiter.HTTPAllow(context.Background(), r) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusTooManyRequests)
+		if ok, retryAfter := limiter.HTTPAllow(context.Background(), r); !ok {
+			tooManyRequests(w, retryAfter)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		if limiter.accountLimiter == nil && limiter.bucketLimiter == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// the bucket is known after routing, the account is added by LimitAccount after the signature is verified
+		identity := &requestIdentity{}
+		if bucket := mux.Vars(r)["bucket"]; bucket != "" && limiter.bucketLimiter != nil {
+			bucket = strings.ToLower(bucket)
+			if ok, retryAfter := limiter.bucketLimiter.allow(r.Context(), bucket); !ok {
+				tooManyRequests(w, retryAfter)
+				return
+			}
+			identity.addLimiter(limiter.bucketLimiter.bandwidthLimiter(bucket))
+		}
+		r = r.WithContext(context.WithValue(r.Context(), identityContextKey{}, identity))
+		if r.Body != nil {
+			r.Body = &limitedBody{ReadCloser: r.Body, ctx: r.Context(), identity: identity}
+		}
+		next.ServeHTTP(&limitedResponseWriter{ResponseWriter: w, ctx: r.Context(), identity: identity}, r)
 	})
 }
 
@@ -0,0 +1,220 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	slimiter "github.com/ulule/limiter/v3"
+	"github.com/ulule/limiter/v3/drivers/store/common"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	// DefaultSharedStoreSyncInterval defines the default interval of syncing the local counters with the shared store
+	DefaultSharedStoreSyncInterval = 500 * time.Millisecond
+	// sharedStorePurgeInterval defines how often the expired counters are deleted from the shared store
+	sharedStorePurgeInterval = time.Minute
+	// sharedStoreStaleSyncs defines the number of the sync intervals without a successful sync after which
+	// the shared counters are stale
+	sharedStoreStaleSyncs = 4
+)
+
+// ErrSharedCountersStale is returned if the counters have not been synced with the shared store for a while, the
+// request is still counted, but the caller should limit it by the local counters until the shared store recovers.
+var ErrSharedCountersStale = errors.New("shared rate limit counters are stale")
+
+// SharedStoreConfig defines how the rate limit counters are shared by the gateway replicas.
+type SharedStoreConfig struct {
+	// On defines whether the counters of the ip, the api and the account limits are shared through the
+	// sp db, otherwise every replica counts the requests locally.
+	On bool
+	// SyncIntervalMillisecond defines how often the local counters are synced with the sp db.
+	SyncIntervalMillisecond int64
+}
+
+// SharedCounter is the request counter of a rate limit window.
+type SharedCounter struct {
+	Key                   string
+	Count                 int64
+	ExpireTimestampSecond int64
+}
+
+// SharedCounterStore persists the rate limit counters shared by the gateway replicas, e.g. the sp db.
+type SharedCounterStore interface {
+	// IncreaseCounters adds the counts to the counters and returns the updated counts keyed by the counter key.
+	IncreaseCounters(counters []*SharedCounter) (map[string]int64, error)
+	// DeleteExpiredCounters deletes the counters which expire before the timestamp.
+	DeleteExpiredCounters(timestampSecond int64) error
+}
+
+// sharedCounter is the local view of a counter in the shared store.
+type sharedCounter struct {
+	synced   int64 // the count of all the replicas at the last sync
+	pending  int64 // the local count which has not been synced
+	expireAt time.Time
+}
+
+// sharedStore implements slimiter.Store with the fixed window counters shared by the gateway replicas.
+// The requests are counted locally and the counts of the keys requested since the last sync are added to
+// the shared store in one batch periodically, the decisions are made by the count of all the replicas at the
+// last sync plus the local count since then. If the shared store is unavailable, the pending counts are kept
+// and retried, and ErrSharedCountersStale is returned once the counters are stale, so the caller falls back
+// to the local limiters until the shared store recovers.
+type sharedStore struct {
+	mu         sync.Mutex
+	counters   map[string]*sharedCounter
+	backend    SharedCounterStore
+	interval   time.Duration
+	available  bool
+	lastSynced time.Time
+	lastPurge  time.Time
+	stopCh     chan struct{}
+}
+
+func newSharedStore(backend SharedCounterStore, interval time.Duration) *sharedStore {
+	if interval <= 0 {
+		interval = DefaultSharedStoreSyncInterval
+	}
+	s := &sharedStore{
+		counters:   make(map[string]*sharedCounter),
+		backend:    backend,
+		interval:   interval,
+		available:  true,
+		lastSynced: time.Now(),
+		stopCh:     make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+func (s *sharedStore) loop() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.sync(time.Now())
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *sharedStore) stop() {
+	close(s.stopCh)
+}
+
+// sync adds the pending counts to the shared store and refreshes the counts of all the replicas, the counters
+// without pending counts are only read.
+func (s *sharedStore) sync(now time.Time) {
+	s.mu.Lock()
+	var (
+		counters = make([]*SharedCounter, 0, len(s.counters))
+		pending  = make(map[string]int64, len(s.counters))
+	)
+	for key, counter := range s.counters {
+		if !now.Before(counter.expireAt) {
+			delete(s.counters, key)
+			continue
+		}
+		counters = append(counters, &SharedCounter{
+			Key:                   key,
+			Count:                 counter.pending,
+			ExpireTimestampSecond: counter.expireAt.Unix(),
+		})
+		pending[key] = counter.pending
+	}
+	s.mu.Unlock()
+
+	var counts map[string]int64
+	if len(counters) > 0 {
+		var err error
+		if counts, err = s.backend.IncreaseCounters(counters); err != nil {
+			s.mu.Lock()
+			if s.available {
+				log.Errorw("failed to sync rate limit counters, fall back to local counters once stale", "error", err)
+			}
+			s.available = false
+			s.mu.Unlock()
+			return
+		}
+	}
+	s.mu.Lock()
+	if !s.available {
+		log.Info("succeed to sync rate limit counters, the shared counters are recovered")
+	}
+	s.available = true
+	s.lastSynced = now
+	for key, count := range pending {
+		if counter, ok := s.counters[key]; ok {
+			// the requests counted during the sync are kept pending
+			counter.pending -= count
+			counter.synced = counts[key]
+		}
+	}
+	s.mu.Unlock()
+
+	if now.Sub(s.lastPurge) >= sharedStorePurgeInterval {
+		s.lastPurge = now
+		if err := s.backend.DeleteExpiredCounters(now.Unix()); err != nil {
+			log.Errorw("failed to delete expired rate limit counters", "error", err)
+		}
+	}
+}
+
+func windowKey(key string, rate slimiter.Rate, now time.Time) (string, time.Time) {
+	start := now.Truncate(rate.Period)
+	return fmt.Sprintf("%s_%d", key, start.Unix()), start.Add(rate.Period)
+}
+
+// count adds the count to the local counter of the key, it returns ErrSharedCountersStale with the limit context
+// of the stale counter if the counters have not been synced for a while.
+func (s *sharedStore) count(key string, count int64, rate slimiter.Rate) (slimiter.Context, error) {
+	now := time.Now()
+	key, expireAt := windowKey(key, rate, now)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	if now.Sub(s.lastSynced) > sharedStoreStaleSyncs*s.interval {
+		err = ErrSharedCountersStale
+	}
+	counter, ok := s.counters[key]
+	if !ok {
+		if count == 0 {
+			return common.GetContextFromState(now, rate, expireAt, 0), err
+		}
+		counter = &sharedCounter{expireAt: expireAt}
+		s.counters[key] = counter
+	}
+	counter.pending += count
+	return common.GetContextFromState(now, rate, expireAt, counter.synced+counter.pending), err
+}
+
+// Get increments the counter of the key and returns the limit context.
+func (s *sharedStore) Get(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	return s.count(key, 1, rate)
+}
+
+// Peek returns the limit context of the key without incrementing the counter.
+func (s *sharedStore) Peek(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	return s.count(key, 0, rate)
+}
+
+// Reset resets the local count of the key, the counts of the other replicas are not affected.
+func (s *sharedStore) Reset(ctx context.Context, key string, rate slimiter.Rate) (slimiter.Context, error) {
+	now := time.Now()
+	key, expireAt := windowKey(key, rate, now)
+	s.mu.Lock()
+	delete(s.counters, key)
+	s.mu.Unlock()
+	return common.GetContextFromState(now, rate, expireAt, 0), nil
+}
+
+// Increment increments the counter of the key by the count and returns the limit context.
+func (s *sharedStore) Increment(ctx context.Context, key string, count int64, rate slimiter.Rate) (slimiter.Context, error) {
+	return s.count(key, count, rate)
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	slimiter "github.com/ulule/limiter/v3"
+)
+
+type mockCounterStore struct {
+	mu          sync.Mutex
+	counts      map[string]int64
+	unavailable bool
+}
+
+func (m *mockCounterStore) IncreaseCounters(counters []*SharedCounter) (map[string]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.unavailable {
+		return nil, errors.New("mock db is unavailable")
+	}
+	counts := make(map[string]int64)
+	for _, c := range counters {
+		m.counts[c.Key] += c.Count
+		counts[c.Key] = m.counts[c.Key]
+	}
+	return counts, nil
+}
+
+func (m *mockCounterStore) DeleteExpiredCounters(timestampSecond int64) error {
+	return nil
+}
+
+func TestSharedStore(t *testing.T) {
+	backend := &mockCounterStore{counts: make(map[string]int64)}
+	// the periodic sync is disabled by the long interval, the test syncs manually
+	replicaA := newSharedStore(backend, time.Hour)
+	replicaB := newSharedStore(backend, time.Hour)
+	defer replicaA.stop()
+	defer replicaB.stop()
+	rate := slimiter.Rate{Period: time.Hour, Limit: 5}
+	ctx := context.Background()
+	key, _ := windowKey("ip_1", rate, time.Now())
+
+	for i := 0; i < 3; i++ {
+		limiterCtx, err := replicaA.Increment(ctx, "ip_1", 1, rate)
+		require.NoError(t, err)
+		assert.False(t, limiterCtx.Reached)
+	}
+	limiterCtx, _ := replicaB.Increment(ctx, "ip_1", 1, rate)
+	assert.Equal(t, int64(4), limiterCtx.Remaining)
+
+	// the replicas share the budget after syncing
+	replicaA.sync(time.Now())
+	replicaB.sync(time.Now())
+	limiterCtx, _ = replicaB.Peek(ctx, "ip_1", rate)
+	assert.Equal(t, int64(1), limiterCtx.Remaining)
+	limiterCtx, _ = replicaB.Increment(ctx, "ip_1", 1, rate)
+	assert.False(t, limiterCtx.Reached)
+	limiterCtx, _ = replicaB.Increment(ctx, "ip_1", 1, rate)
+	assert.True(t, limiterCtx.Reached)
+
+	// the local counts are kept if the shared store is unavailable, and synced after it recovers
+	backend.unavailable = true
+	replicaB.sync(time.Now())
+	limiterCtx, _ = replicaB.Peek(ctx, "ip_1", rate)
+	assert.True(t, limiterCtx.Reached)
+	backend.unavailable = false
+	replicaB.sync(time.Now())
+	replicaA.sync(time.Now())
+	assert.Equal(t, int64(6), backend.counts[key])
+	limiterCtx, _ = replicaA.Peek(ctx, "ip_1", rate)
+	assert.True(t, limiterCtx.Reached)
+}
+
+func TestSharedStore_Stale(t *testing.T) {
+	backend := &mockCounterStore{counts: make(map[string]int64), unavailable: true}
+	store := newSharedStore(backend, time.Hour)
+	defer store.stop()
+	rate := slimiter.Rate{Period: time.Hour, Limit: 5}
+	ctx := context.Background()
+
+	// the failed sync does not make the counters stale at once
+	store.sync(time.Now())
+	limiterCtx, err := store.Increment(ctx, "ip_1", 1, rate)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), limiterCtx.Remaining)
+
+	// the counters are stale if they have not been synced for several intervals, the requests are still counted
+	store.mu.Lock()
+	store.lastSynced = time.Now().Add(-sharedStoreStaleSyncs*time.Hour - time.Second)
+	store.mu.Unlock()
+	limiterCtx, err = store.Increment(ctx, "ip_1", 1, rate)
+	assert.Equal(t, ErrSharedCountersStale, err)
+	assert.Equal(t, int64(3), limiterCtx.Remaining)
+	_, err = store.Peek(ctx, "ip_2", rate)
+	assert.Equal(t, ErrSharedCountersStale, err)
+
+	backend.unavailable = false
+	store.sync(time.Now())
+	_, err = store.Peek(ctx, "ip_1", rate)
+	assert.NoError(t, err)
+	key, _ := windowKey("ip_1", rate, time.Now())
+	assert.Equal(t, int64(2), backend.counts[key])
+}
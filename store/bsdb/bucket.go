@@ -263,3 +263,38 @@ func (b *BsDBImpl) ListBucketsByVgfID(vgfIDs []uint32, startAfter common.Hash, l
 		Find(&buckets).Error
 	return buckets, err
 }
+
+// CountBucketsByVgfID counts the buckets which are not removed by vgf ids
+func (b *BsDBImpl) CountBucketsByVgfID(vgfIDs []uint32) (map[uint32]int64, error) {
+	var (
+		rows []struct {
+			GlobalVirtualGroupFamilyID uint32
+			Count                      int64
+		}
+		err error
+	)
+
+	startTime := time.Now()
+	methodName := currentFunction()
+	defer func() {
+		if err != nil {
+			MetadataDatabaseFailureMetrics(err, startTime, methodName)
+		} else {
+			MetadataDatabaseSuccessMetrics(startTime, methodName)
+		}
+	}()
+
+	err = b.db.Table((&Bucket{}).TableName()).
+		Select("global_virtual_group_family_id, count(1) as count").
+		Where("global_virtual_group_family_id in (?) and removed = false", vgfIDs).
+		Group("global_virtual_group_family_id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uint32]int64, len(rows))
+	for _, row := range rows {
+		counts[row.GlobalVirtualGroupFamilyID] = row.Count
+	}
+	return counts, nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	permtypes "github.com/bnb-chain/greenfield/x/permission/types"
+	"github.com/forbole/juno/v4/common"
 
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
 )
@@ -20,6 +21,39 @@ type ListObjectsResult struct {
 	*Object
 }
 
+// ObjectSortType defines the order of listing objects
+type ObjectSortType string
+
+const (
+	// ObjectSortByName sorts the objects by the object name
+	ObjectSortByName ObjectSortType = "name"
+	// ObjectSortBySize sorts the objects by the payload size and then the object name
+	ObjectSortBySize ObjectSortType = "size"
+	// ObjectSortByCreateTime sorts the objects by the create time and then the object name
+	ObjectSortByCreateTime ObjectSortType = "create_time"
+)
+
+// ListObjectsFilter defines the filters and the order of listing objects, the zero value of a field means
+// the objects are not filtered by it, and the objects are sorted by the object name in ascending order.
+type ListObjectsFilter struct {
+	ContentType     string
+	MinPayloadSize  uint64
+	MaxPayloadSize  uint64
+	CreateTimeStart int64
+	CreateTimeEnd   int64
+	Owner           common.Address
+	Visibility      string
+	ObjectStatus    string
+	SortBy          ObjectSortType
+	SortDesc        bool
+}
+
+// ObjectsStats represents the number and the total payload size of the listed objects.
+type ObjectsStats struct {
+	TotalCount       uint64
+	TotalPayloadSize uint64
+}
+
 // ActionTypeMap db action value is a bitmap, traverse this map to get the corresponding action list
 var ActionTypeMap = map[permtypes.ActionType]int{
 	permtypes.ACTION_TYPE_ALL:            0,
@@ -37,6 +71,8 @@ var ActionTypeMap = map[permtypes.ActionType]int{
 }
 
 const (
+	// BsDBDriver defines env variable name for block syncer db driver, mysql, postgres or sqlite.
+	BsDBDriver = "BS_DB_DRIVER"
 	// BsDBUser defines env variable name for block syncer db username.
 	BsDBUser = "BS_DB_USER"
 	// BsDBPasswd defines env variable name for block syncer db user passwd.
@@ -45,6 +81,8 @@ const (
 	BsDBAddress = "BS_DB_ADDRESS"
 	// BsDBDataBase defines env variable name for block syncer db database.
 	BsDBDataBase = "BS_DB_DATABASE"
+	// BsDBSwitchedDriver defines env variable name for switched block syncer db driver.
+	BsDBSwitchedDriver = "BS_DB_SWITCHED_DRIVER"
 	// BsDBSwitchedUser defines env variable name for switched block syncer db username.
 	BsDBSwitchedUser = "BS_DB_SWITCHED_USER"
 	// BsDBSwitchedPasswd defines env variable name for switched block syncer db user passwd.
@@ -73,12 +111,16 @@ func (a *Uint32Array) Scan(value interface{}) error {
 		return nil
 	}
 
-	bytes, ok := value.([]byte)
-	if !ok {
+	// the text columns are scanned as string by the sqlite driver
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
 		return fmt.Errorf("failed to scan Uint32Array value: %v", value)
 	}
-
-	s := string(bytes)
 	fields := strings.Split(s, ",")
 	result := make([]uint32, len(fields))
 	for i, field := range fields {
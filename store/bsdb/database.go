@@ -32,7 +32,9 @@ type Metadata interface {
 	// GetGroupsByGroupIDAndAccount get groups info by group id list and account id
 	GetGroupsByGroupIDAndAccount(groupIDList []common.Hash, account common.Address, includeRemoved bool) ([]*Group, error)
 	// ListObjectsByBucketName list objects info by a bucket name
-	ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool) ([]*ListObjectsResult, error)
+	ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool, filter *ListObjectsFilter) ([]*ListObjectsResult, error)
+	// GetObjectsStatsByBucketName get the number and the total payload size of the objects by a bucket name
+	GetObjectsStatsByBucketName(bucketName, prefix string, includeRemoved bool, filter *ListObjectsFilter) (*ObjectsStats, error)
 	// ListDeletedObjectsByBlockNumberRange list deleted objects info by a block number range
 	ListDeletedObjectsByBlockNumberRange(startBlockNumber int64, endBlockNumber int64, includePrivate bool) ([]*Object, error)
 	// ListExpiredBucketsBySp list expired buckets by sp
@@ -79,6 +81,8 @@ type Metadata interface {
 	ListLvgByGvgID(gvgIDs []uint32) ([]*LocalVirtualGroup, error)
 	// ListBucketsByVgfID list buckets by vgf ids
 	ListBucketsByVgfID(vgfIDs []uint32, startAfter common.Hash, limit int) ([]*Bucket, error)
+	// CountBucketsByVgfID counts the buckets which are not removed by vgf ids
+	CountBucketsByVgfID(vgfIDs []uint32) (map[uint32]int64, error)
 	// ListObjectsByLVGID list objects by lvg id
 	ListObjectsByLVGID(lvgIDs []uint32, bucketID common.Hash, startAfter common.Hash, limit int, filters ...func(*gorm.DB) *gorm.DB) ([]*Object, *Bucket, error)
 	// GetGvgByBucketAndLvgID get global virtual group by lvg id and bucket id
@@ -35,6 +35,21 @@ func (m *MockMetadata) EXPECT() *MockMetadataMockRecorder {
 	return m.recorder
 }
 
+// CountBucketsByVgfID mocks base method.
+func (m *MockMetadata) CountBucketsByVgfID(vgfIDs []uint32) (map[uint32]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBucketsByVgfID", vgfIDs)
+	ret0, _ := ret[0].(map[uint32]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBucketsByVgfID indicates an expected call of CountBucketsByVgfID.
+func (mr *MockMetadataMockRecorder) CountBucketsByVgfID(vgfIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBucketsByVgfID", reflect.TypeOf((*MockMetadata)(nil).CountBucketsByVgfID), vgfIDs)
+}
+
 // GetBucketByID mocks base method.
 func (m *MockMetadata) GetBucketByID(bucketID int64, includePrivate bool) (*Bucket, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +230,21 @@ func (mr *MockMetadataMockRecorder) GetObjectByName(objectName, bucketName, incl
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectByName", reflect.TypeOf((*MockMetadata)(nil).GetObjectByName), objectName, bucketName, includePrivate)
 }
 
+// GetObjectsStatsByBucketName mocks base method.
+func (m *MockMetadata) GetObjectsStatsByBucketName(bucketName, prefix string, includeRemoved bool, filter *ListObjectsFilter) (*ObjectsStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjectsStatsByBucketName", bucketName, prefix, includeRemoved, filter)
+	ret0, _ := ret[0].(*ObjectsStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectsStatsByBucketName indicates an expected call of GetObjectsStatsByBucketName.
+func (mr *MockMetadataMockRecorder) GetObjectsStatsByBucketName(bucketName, prefix, includeRemoved, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectsStatsByBucketName", reflect.TypeOf((*MockMetadata)(nil).GetObjectsStatsByBucketName), bucketName, prefix, includeRemoved, filter)
+}
+
 // GetPaymentByBucketID mocks base method.
 func (m *MockMetadata) GetPaymentByBucketID(bucketID int64, includePrivate bool) (*StreamRecord, error) {
 	m.ctrl.T.Helper()
@@ -290,6 +320,21 @@ func (mr *MockMetadataMockRecorder) GetPermissionsByResourceAndPrincipleType(res
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissionsByResourceAndPrincipleType", reflect.TypeOf((*MockMetadata)(nil).GetPermissionsByResourceAndPrincipleType), resourceType, principalType, resourceID, includeRemoved)
 }
 
+// GetSPByAddress mocks base method.
+func (m *MockMetadata) GetSPByAddress(operatorAddress common.Address) (*StorageProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSPByAddress", operatorAddress)
+	ret0, _ := ret[0].(*StorageProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSPByAddress indicates an expected call of GetSPByAddress.
+func (mr *MockMetadataMockRecorder) GetSPByAddress(operatorAddress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSPByAddress", reflect.TypeOf((*MockMetadata)(nil).GetSPByAddress), operatorAddress)
+}
+
 // GetStatementsByPolicyID mocks base method.
 func (m *MockMetadata) GetStatementsByPolicyID(policyIDList []common.Hash, includeRemoved bool) ([]*Statement, error) {
 	m.ctrl.T.Helper()
@@ -534,18 +579,18 @@ func (mr *MockMetadataMockRecorder) ListMigrateBucketEvents(blockID, spID interf
 }
 
 // ListObjectsByBucketName mocks base method.
-func (m *MockMetadata) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool) ([]*ListObjectsResult, error) {
+func (m *MockMetadata) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool, filter *ListObjectsFilter) ([]*ListObjectsResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListObjectsByBucketName", bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved)
+	ret := m.ctrl.Call(m, "ListObjectsByBucketName", bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter)
 	ret0, _ := ret[0].([]*ListObjectsResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListObjectsByBucketName indicates an expected call of ListObjectsByBucketName.
-func (mr *MockMetadataMockRecorder) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved interface{}) *gomock.Call {
+func (mr *MockMetadataMockRecorder) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByBucketName", reflect.TypeOf((*MockMetadata)(nil).ListObjectsByBucketName), bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByBucketName", reflect.TypeOf((*MockMetadata)(nil).ListObjectsByBucketName), bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter)
 }
 
 // ListObjectsByGVGAndBucketForGC mocks base method.
@@ -718,6 +763,21 @@ func (m *MockBSDB) EXPECT() *MockBSDBMockRecorder {
 	return m.recorder
 }
 
+// CountBucketsByVgfID mocks base method.
+func (m *MockBSDB) CountBucketsByVgfID(vgfIDs []uint32) (map[uint32]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBucketsByVgfID", vgfIDs)
+	ret0, _ := ret[0].(map[uint32]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBucketsByVgfID indicates an expected call of CountBucketsByVgfID.
+func (mr *MockBSDBMockRecorder) CountBucketsByVgfID(vgfIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBucketsByVgfID", reflect.TypeOf((*MockBSDB)(nil).CountBucketsByVgfID), vgfIDs)
+}
+
 // GetBucketByID mocks base method.
 func (m *MockBSDB) GetBucketByID(bucketID int64, includePrivate bool) (*Bucket, error) {
 	m.ctrl.T.Helper()
@@ -898,6 +958,21 @@ func (mr *MockBSDBMockRecorder) GetObjectByName(objectName, bucketName, includeP
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectByName", reflect.TypeOf((*MockBSDB)(nil).GetObjectByName), objectName, bucketName, includePrivate)
 }
 
+// GetObjectsStatsByBucketName mocks base method.
+func (m *MockBSDB) GetObjectsStatsByBucketName(bucketName, prefix string, includeRemoved bool, filter *ListObjectsFilter) (*ObjectsStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObjectsStatsByBucketName", bucketName, prefix, includeRemoved, filter)
+	ret0, _ := ret[0].(*ObjectsStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectsStatsByBucketName indicates an expected call of GetObjectsStatsByBucketName.
+func (mr *MockBSDBMockRecorder) GetObjectsStatsByBucketName(bucketName, prefix, includeRemoved, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectsStatsByBucketName", reflect.TypeOf((*MockBSDB)(nil).GetObjectsStatsByBucketName), bucketName, prefix, includeRemoved, filter)
+}
+
 // GetPaymentByBucketID mocks base method.
 func (m *MockBSDB) GetPaymentByBucketID(bucketID int64, includePrivate bool) (*StreamRecord, error) {
 	m.ctrl.T.Helper()
@@ -973,6 +1048,21 @@ func (mr *MockBSDBMockRecorder) GetPermissionsByResourceAndPrincipleType(resourc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissionsByResourceAndPrincipleType", reflect.TypeOf((*MockBSDB)(nil).GetPermissionsByResourceAndPrincipleType), resourceType, principalType, resourceID, includeRemoved)
 }
 
+// GetSPByAddress mocks base method.
+func (m *MockBSDB) GetSPByAddress(operatorAddress common.Address) (*StorageProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSPByAddress", operatorAddress)
+	ret0, _ := ret[0].(*StorageProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSPByAddress indicates an expected call of GetSPByAddress.
+func (mr *MockBSDBMockRecorder) GetSPByAddress(operatorAddress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSPByAddress", reflect.TypeOf((*MockBSDB)(nil).GetSPByAddress), operatorAddress)
+}
+
 // GetStatementsByPolicyID mocks base method.
 func (m *MockBSDB) GetStatementsByPolicyID(policyIDList []common.Hash, includeRemoved bool) ([]*Statement, error) {
 	m.ctrl.T.Helper()
@@ -1217,18 +1307,18 @@ func (mr *MockBSDBMockRecorder) ListMigrateBucketEvents(blockID, spID interface{
 }
 
 // ListObjectsByBucketName mocks base method.
-func (m *MockBSDB) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool) ([]*ListObjectsResult, error) {
+func (m *MockBSDB) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool, filter *ListObjectsFilter) ([]*ListObjectsResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListObjectsByBucketName", bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved)
+	ret := m.ctrl.Call(m, "ListObjectsByBucketName", bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter)
 	ret0, _ := ret[0].([]*ListObjectsResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListObjectsByBucketName indicates an expected call of ListObjectsByBucketName.
-func (mr *MockBSDBMockRecorder) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved interface{}) *gomock.Call {
+func (mr *MockBSDBMockRecorder) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByBucketName", reflect.TypeOf((*MockBSDB)(nil).ListObjectsByBucketName), bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsByBucketName", reflect.TypeOf((*MockBSDB)(nil).ListObjectsByBucketName), bucketName, continuationToken, prefix, delimiter, maxKeys, includeRemoved, filter)
 }
 
 // ListObjectsByGVGAndBucketForGC mocks base method.
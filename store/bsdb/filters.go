@@ -70,3 +70,68 @@ func WithLimit(limit int) func(*gorm.DB) *gorm.DB {
 		return db.Limit(limit)
 	}
 }
+
+func ContentTypeFilter(contentType string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("content_type = ?", contentType)
+	}
+}
+
+func MinPayloadSizeFilter(payloadSize uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("payload_size >= ?", payloadSize)
+	}
+}
+
+func MaxPayloadSizeFilter(payloadSize uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("payload_size <= ?", payloadSize)
+	}
+}
+
+func CreateTimeStartFilter(createTime int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("create_time >= ?", createTime)
+	}
+}
+
+func CreateTimeEndFilter(createTime int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("create_time <= ?", createTime)
+	}
+}
+
+func OwnerFilter(owner common.Address) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("owner = ?", owner)
+	}
+}
+
+func VisibilityFilter(visibility string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("visibility = ?", visibility)
+	}
+}
+
+func ObjectStatusFilter(status string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ?", status)
+	}
+}
+
+// SortContinuationFilter continues the objects sorted by the column and the object name from the object of the
+// continuation token whose column value is the value.
+func SortContinuationFilter(column string, value interface{}, continuationToken string, desc bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if desc {
+			return db.Where("("+column+" < ? or ("+column+" = ? and object_name <= ?))", value, value, continuationToken)
+		}
+		return db.Where("("+column+" > ? or ("+column+" = ? and object_name >= ?))", value, value, continuationToken)
+	}
+}
+
+func ReverseContinuationTokenFilter(continuationToken string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("object_name <= ?", continuationToken)
+	}
+}
@@ -2,7 +2,7 @@ package bsdb
 
 import (
 	"errors"
-	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/forbole/juno/v4/common"
@@ -69,9 +69,8 @@ func (b *BsDBImpl) ListGvgByPrimarySpID(spID uint32) ([]*GlobalVirtualGroup, err
 // ListGvgBySecondarySpID list gvg by secondary sp id
 func (b *BsDBImpl) ListGvgBySecondarySpID(spID uint32) ([]*GlobalVirtualGroup, error) {
 	var (
-		gvg   []*GlobalVirtualGroup
-		query string
-		err   error
+		gvg []*GlobalVirtualGroup
+		err error
 	)
 	startTime := time.Now()
 	methodName := currentFunction()
@@ -83,8 +82,9 @@ func (b *BsDBImpl) ListGvgBySecondarySpID(spID uint32) ([]*GlobalVirtualGroup, e
 		}
 	}()
 
-	query = fmt.Sprintf("select * from global_virtual_groups where FIND_IN_SET('%d', secondary_sp_ids) > 0 and removed = false; ", spID)
-	err = b.db.Raw(query).Find(&gvg).Error
+	err = b.db.Table((&GlobalVirtualGroup{}).TableName()).
+		Where(b.dialect.FindInSet("secondary_sp_ids")+" and removed = false", strconv.FormatUint(uint64(spID), 10)).
+		Find(&gvg).Error
 
 	return gvg, err
 }
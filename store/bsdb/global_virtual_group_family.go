@@ -2,7 +2,8 @@ package bsdb
 
 import (
 	"errors"
-	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -69,7 +70,6 @@ func (b *BsDBImpl) GetVirtualGroupFamiliesByVgfID(vgfID uint32) (*GlobalVirtualG
 func (b *BsDBImpl) ListVgfByGvgID(gvgIDs []uint32) ([]*GlobalVirtualGroupFamily, error) {
 	var (
 		families []*GlobalVirtualGroupFamily
-		query    string
 		err      error
 	)
 	startTime := time.Now()
@@ -85,15 +85,15 @@ func (b *BsDBImpl) ListVgfByGvgID(gvgIDs []uint32) ([]*GlobalVirtualGroupFamily,
 	if len(gvgIDs) == 0 {
 		return nil, nil
 	}
-	query = fmt.Sprintf("select * from global_virtual_group_families where (FIND_IN_SET('%d', global_virtual_group_ids) > 0", gvgIDs[0])
-	if len(gvgIDs) > 1 {
-		for _, id := range gvgIDs[1:] {
-			subQuery := fmt.Sprintf(" or FIND_IN_SET('%d', global_virtual_group_ids) > 0", id)
-			query = query + subQuery
-		}
+	conditions := make([]string, 0, len(gvgIDs))
+	args := make([]interface{}, 0, len(gvgIDs))
+	for _, id := range gvgIDs {
+		conditions = append(conditions, b.dialect.FindInSet("global_virtual_group_ids"))
+		args = append(args, strconv.FormatUint(uint64(id), 10))
 	}
-	query = query + ") and removed = false;"
-	err = b.db.Table((&GlobalVirtualGroupFamily{}).TableName()).Raw(query).Find(&families).Error
+	err = b.db.Table((&GlobalVirtualGroupFamily{}).TableName()).
+		Where("("+strings.Join(conditions, " or ")+") and removed = false", args...).
+		Find(&families).Error
 
 	return families, err
 }
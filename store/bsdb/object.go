@@ -1,8 +1,10 @@
 package bsdb
 
 import (
+	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"cosmossdk.io/math"
@@ -11,10 +13,15 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
 )
 
 const ObjectsNumberOfShards = 64
 
+// ErrInvalidContinuationToken is returned if the object of the continuation token is not found when the objects
+// are sorted by the size or the create time.
+var ErrInvalidContinuationToken = errors.New("invalid continuation token")
+
 // ListObjectsByBucketName lists objects information by a bucket name.
 // The function takes the following parameters:
 // - bucketName: The name of the bucket to search for objects.
@@ -22,11 +29,13 @@ const ObjectsNumberOfShards = 64
 // - prefix: A prefix to filter the objects by their object names.
 // - delimiter: A delimiter to group objects that share a common prefix. An empty delimiter means no grouping.
 // - maxKeys: The maximum number of objects to return in the result.
+// - filter: The filters and the order of the objects, it is only supported if the delimiter is empty.
 //
 // The function returns a slice of ListObjectsResult, which contains information about the objects and their types (object or common_prefix).
 // If there is a delimiter specified, the function will group objects that share a common prefix and return them as common_prefix in the result.
 // If the delimiter is empty, the function will return all objects without grouping them by a common prefix.
-func (b *BsDBImpl) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool) ([]*ListObjectsResult, error) {
+func (b *BsDBImpl) ListObjectsByBucketName(bucketName, continuationToken, prefix, delimiter string, maxKeys int, includeRemoved bool,
+	filter *ListObjectsFilter) ([]*ListObjectsResult, error) {
 	var (
 		err     error
 		limit   int
@@ -51,36 +60,173 @@ func (b *BsDBImpl) ListObjectsByBucketName(bucketName, continuationToken, prefix
 	// 2. Find common prefixes based on the delimiter
 	// 3. Limit results
 	if delimiter != "" {
+		if filter.IsFiltered() {
+			err = fmt.Errorf("filters are not supported with the delimiter")
+			return nil, err
+		}
 		results, err = b.ListObjects(bucketName, continuationToken, prefix, maxKeys)
 	} else {
 		// If delimiter is not specified, retrieve objects directly
-
+		sortColumn := filter.sortColumn()
+		if sortColumn == "" {
+			err = fmt.Errorf("unknown sort type: %s", filter.SortBy)
+			return nil, err
+		}
 		if continuationToken != "" {
-			filters = append(filters, ContinuationTokenFilter(continuationToken))
+			var continuationFilter func(*gorm.DB) *gorm.DB
+			if continuationFilter, err = b.continuationFilter(bucketName, continuationToken, sortColumn, filter); err != nil {
+				return nil, err
+			}
+			filters = append(filters, continuationFilter)
 		}
 		if prefix != "" {
 			filters = append(filters, PrefixFilter(prefix))
 		}
+		if !includeRemoved {
+			filters = append(filters, RemovedFilter(false))
+		}
+		filters = append(filters, filter.scopes()...)
 
-		if includeRemoved {
-			err = b.db.Table(GetObjectsTableName(bucketName)).
-				Select("*").
-				Where("bucket_name = ?", bucketName).
-				Scopes(filters...).
-				Limit(limit).
-				Order("object_name asc").
-				Find(&results).Error
+		err = b.db.Table(GetObjectsTableName(bucketName)).
+			Select("*").
+			Where("bucket_name = ?", bucketName).
+			Scopes(filters...).
+			Limit(limit).
+			Order(filter.order(sortColumn)).
+			Find(&results).Error
+	}
+	return results, err
+}
+
+// GetObjectsStatsByBucketName returns the number and the total payload size of the objects which match the prefix
+// and the filter in the bucket.
+func (b *BsDBImpl) GetObjectsStatsByBucketName(bucketName, prefix string, includeRemoved bool, filter *ListObjectsFilter) (*ObjectsStats, error) {
+	var (
+		err     error
+		stats   *ObjectsStats
+		filters []func(*gorm.DB) *gorm.DB
+	)
+	startTime := time.Now()
+	methodName := currentFunction()
+	defer func() {
+		if err != nil {
+			MetadataDatabaseFailureMetrics(err, startTime, methodName)
 		} else {
-			err = b.db.Table(GetObjectsTableName(bucketName)).
-				Select("*").
-				Where("bucket_name = ? and removed = false", bucketName).
-				Scopes(filters...).
-				Limit(limit).
-				Order("object_name asc").
-				Find(&results).Error
+			MetadataDatabaseSuccessMetrics(startTime, methodName)
 		}
+	}()
+
+	if prefix != "" {
+		filters = append(filters, PrefixFilter(prefix))
 	}
-	return results, err
+	if !includeRemoved {
+		filters = append(filters, RemovedFilter(false))
+	}
+	filters = append(filters, filter.scopes()...)
+
+	err = b.db.Table(GetObjectsTableName(bucketName)).
+		Select("count(*) as total_count, coalesce(sum(payload_size), 0) as total_payload_size").
+		Where("bucket_name = ?", bucketName).
+		Scopes(filters...).
+		Take(&stats).Error
+	return stats, err
+}
+
+// continuationFilter continues the sorted objects from the object of the continuation token, the objects sorted
+// by the size or the create time are continued from the sort value of the object.
+func (b *BsDBImpl) continuationFilter(bucketName, continuationToken, sortColumn string, filter *ListObjectsFilter) (func(*gorm.DB) *gorm.DB, error) {
+	desc := filter != nil && filter.SortDesc
+	if sortColumn == "object_name" {
+		if desc {
+			return ReverseContinuationTokenFilter(continuationToken), nil
+		}
+		return ContinuationTokenFilter(continuationToken), nil
+	}
+	var value int64
+	result := b.db.Table(GetObjectsTableName(bucketName)).
+		Select(sortColumn).
+		Where("bucket_name = ? and object_name = ?", bucketName, continuationToken).
+		Order("removed asc").
+		Limit(1).
+		Scan(&value)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidContinuationToken
+	}
+	return SortContinuationFilter(sortColumn, value, continuationToken, desc), nil
+}
+
+// IsFiltered returns whether the objects are filtered or not sorted by the object name in ascending order.
+func (f *ListObjectsFilter) IsFiltered() bool {
+	if f == nil {
+		return false
+	}
+	if f.SortBy != "" && f.SortBy != ObjectSortByName {
+		return true
+	}
+	return *f != ListObjectsFilter{SortBy: f.SortBy}
+}
+
+func (f *ListObjectsFilter) scopes() []func(*gorm.DB) *gorm.DB {
+	var filters []func(*gorm.DB) *gorm.DB
+	if f == nil {
+		return filters
+	}
+	if f.ContentType != "" {
+		filters = append(filters, ContentTypeFilter(f.ContentType))
+	}
+	if f.MinPayloadSize != 0 {
+		filters = append(filters, MinPayloadSizeFilter(f.MinPayloadSize))
+	}
+	if f.MaxPayloadSize != 0 {
+		filters = append(filters, MaxPayloadSizeFilter(f.MaxPayloadSize))
+	}
+	if f.CreateTimeStart != 0 {
+		filters = append(filters, CreateTimeStartFilter(f.CreateTimeStart))
+	}
+	if f.CreateTimeEnd != 0 {
+		filters = append(filters, CreateTimeEndFilter(f.CreateTimeEnd))
+	}
+	if f.Owner != (common.Address{}) {
+		filters = append(filters, OwnerFilter(f.Owner))
+	}
+	if f.Visibility != "" {
+		filters = append(filters, VisibilityFilter(f.Visibility))
+	}
+	if f.ObjectStatus != "" {
+		filters = append(filters, ObjectStatusFilter(f.ObjectStatus))
+	}
+	return filters
+}
+
+// sortColumn returns the column which the objects are sorted by, returns an empty string if the sort type is unknown.
+func (f *ListObjectsFilter) sortColumn() string {
+	if f == nil {
+		return "object_name"
+	}
+	switch f.SortBy {
+	case "", ObjectSortByName:
+		return "object_name"
+	case ObjectSortBySize:
+		return "payload_size"
+	case ObjectSortByCreateTime:
+		return "create_time"
+	default:
+		return ""
+	}
+}
+
+func (f *ListObjectsFilter) order(sortColumn string) string {
+	direction := "asc"
+	if f != nil && f.SortDesc {
+		direction = "desc"
+	}
+	if sortColumn == "object_name" {
+		return "object_name " + direction
+	}
+	return sortColumn + " " + direction + ", object_name " + direction
 }
 
 type ByUpdateAtAndObjectID []*Object
@@ -237,7 +383,7 @@ func GetObjectsShardNumberByBucketName(bucketName string) uint32 {
 }
 
 func GetObjectsTableNameByShardNumber(shard int) string {
-	return fmt.Sprintf("%s_%02d", ObjectTableName, shard)
+	return dialect.ShardTableName(ObjectTableName, shard)
 }
 
 // GetObjectByID get object info by object id
@@ -443,16 +589,17 @@ func (b *BsDBImpl) ListObjectsInGVG(gvgID uint32, startAfter common.Hash, limit
 		tableNameMap[bucket.BucketID] = GetObjectsTableName(bucket.BucketName)
 	}
 
-	query = fmt.Sprintf("select * from ((select * from %s where local_virtual_group_id = %d and bucket_id = %s)", tableNameMap[localGroups[0].BucketID], localGroups[0].LocalVirtualGroupId, localGroups[0].BucketID.String())
-	if len(localGroups) > 1 {
-		for _, group := range localGroups[1:] {
-			subQuery := fmt.Sprintf(" UNION ALL (select * from %s where local_virtual_group_id = %d and bucket_id = %s)", tableNameMap[group.BucketID], group.LocalVirtualGroupId, group.BucketID.String())
-			query = query + subQuery
-		}
+	// the selects of the union are not parenthesized and the values are bound, which are supported by all the sql engines
+	subQueries := make([]string, 0, len(localGroups))
+	args := make([]interface{}, 0, 2*len(localGroups)+2)
+	for _, group := range localGroups {
+		subQueries = append(subQueries, fmt.Sprintf("select * from %s where local_virtual_group_id = ? and bucket_id = ?", tableNameMap[group.BucketID]))
+		args = append(args, group.LocalVirtualGroupId, group.BucketID)
 	}
-
-	query = query + fmt.Sprintf(") as combined where status = 'OBJECT_STATUS_SEALED' and object_id > %s and  removed = false order by object_id limit %d;", startAfter.String(), limit)
-	err = b.db.Table((&Object{}).TableName()).Raw(query).Find(&objects).Error
+	args = append(args, startAfter, limit)
+	query = "select * from (" + strings.Join(subQueries, " UNION ALL ") +
+		") as combined where status = 'OBJECT_STATUS_SEALED' and object_id > ? and removed = false order by object_id limit ?"
+	err = b.db.Table((&Object{}).TableName()).Raw(query, args...).Find(&objects).Error
 
 	return objects, buckets, err
 }
@@ -1,7 +1,6 @@
 package bsdb
 
 import (
-	"fmt"
 	"path/filepath"
 	"strings"
 	"time"
@@ -9,6 +8,8 @@ import (
 	"github.com/forbole/juno/v4/common"
 	"github.com/spaolacci/murmur3"
 	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
 )
 
 const PrefixesNumberOfShards = 64
@@ -155,5 +156,5 @@ func GetPrefixesShardNumberByBucketName(bucketName string) uint32 {
 }
 
 func GetPrefixesTableNameByShardNumber(shard int) string {
-	return fmt.Sprintf("%s_%02d", PrefixTreeTableName, shard)
+	return dialect.ShardTableName(PrefixTreeTableName, shard)
 }
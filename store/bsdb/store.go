@@ -1,25 +1,25 @@
 package bsdb
 
 import (
-	"fmt"
 	syslog "log"
 	"os"
 	"time"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/gfspconfig"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
 )
 
 var _ BSDB = &BsDBImpl{}
 
 // BsDBImpl block syncer database, implements BSDB interface
 type BsDBImpl struct {
-	db *gorm.DB
+	db      *gorm.DB
+	dialect dialect.Dialect
 }
 
 // NewBsDB return a block syncer db instance or a block syncer db backup instance based on the isBackup flag
@@ -30,18 +30,16 @@ func NewBsDB(cfg *gfspconfig.GfSpConfig, isBackup bool) (*BsDBImpl, error) {
 		dbConfig = cfg.BsDBBackup
 	}
 
-	db, err := InitDB(&dbConfig)
+	db, d, err := InitDB(&dbConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	return &BsDBImpl{db: db}, nil
+	return &BsDBImpl{db: db, dialect: d}, nil
 }
 
-// InitDB init a block syncer db instance
-func InitDB(config *config.SQLDBConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.User, config.Passwd, config.Address, config.Database)
+// InitDB init a block syncer db instance by the driver of the config
+func InitDB(config *config.SQLDBConfig) (*gorm.DB, dialect.Dialect, error) {
 	newLogger := logger.New(
 		syslog.New(os.Stdout, "\r\n", syslog.LstdFlags), // io writer
 		logger.Config{
@@ -50,11 +48,11 @@ func InitDB(config *config.SQLDBConfig) (*gorm.DB, error) {
 			Colorful:      true,        // Disable color
 		},
 	)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: newLogger})
+	db, d, err := dialect.Open(config, &gorm.Config{Logger: newLogger})
 	if err != nil {
-		log.Errorw("gorm failed to open db", "error", err)
-		return nil, err
+		log.Errorw("gorm failed to open db", "driver", config.Driver, "error", err)
+		return nil, nil, err
 	}
 
-	return db, nil
+	return db, d, nil
 }
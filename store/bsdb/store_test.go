@@ -0,0 +1,110 @@
+package bsdb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+)
+
+// setupBsDBTest returns a block syncer db on a temporary sqlite file with the tables of the models.
+func setupBsDBTest(t *testing.T, models ...interface{}) *BsDBImpl {
+	db, d, err := InitDB(&config.SQLDBConfig{Driver: dialect.SQLiteDriver, Database: filepath.Join(t.TempDir(), "bs.db")})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(models...))
+	return &BsDBImpl{db: db, dialect: d}
+}
+
+func TestBsDBImpl_ListGvgBySecondarySpID(t *testing.T) {
+	b := setupBsDBTest(t, &GlobalVirtualGroup{})
+	gvgs := []*GlobalVirtualGroup{
+		{ID: 1, GlobalVirtualGroupId: 1, PrimarySpId: 1, SecondarySpIds: Uint32Array{2, 3, 12}},
+		{ID: 2, GlobalVirtualGroupId: 2, PrimarySpId: 1, SecondarySpIds: Uint32Array{12, 13}},
+		{ID: 3, GlobalVirtualGroupId: 3, PrimarySpId: 1, SecondarySpIds: Uint32Array{2}, Removed: true},
+	}
+	require.NoError(t, b.db.Create(gvgs).Error)
+
+	found, err := b.ListGvgBySecondarySpID(2)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, uint32(1), found[0].GlobalVirtualGroupId)
+	assert.Equal(t, Uint32Array{2, 3, 12}, found[0].SecondarySpIds)
+
+	// the id is matched as a whole element of the set
+	found, err = b.ListGvgBySecondarySpID(1)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	found, err = b.ListGvgBySecondarySpID(12)
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+}
+
+func TestBsDBImpl_ListVgfByGvgID(t *testing.T) {
+	b := setupBsDBTest(t, &GlobalVirtualGroupFamily{})
+	families := []*GlobalVirtualGroupFamily{
+		{ID: 1, GlobalVirtualGroupFamilyId: 1, PrimarySpId: 1, GlobalVirtualGroupIds: Uint32Array{1, 2}},
+		{ID: 2, GlobalVirtualGroupFamilyId: 2, PrimarySpId: 1, GlobalVirtualGroupIds: Uint32Array{3, 21}},
+		{ID: 3, GlobalVirtualGroupFamilyId: 3, PrimarySpId: 1, GlobalVirtualGroupIds: Uint32Array{4}, Removed: true},
+	}
+	require.NoError(t, b.db.Create(families).Error)
+
+	found, err := b.ListVgfByGvgID([]uint32{2, 21, 4})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	found, err = b.ListVgfByGvgID([]uint32{5})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	found, err = b.ListVgfByGvgID(nil)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestBsDBImpl_ListObjectsByBucketNameSorted(t *testing.T) {
+	const bucketName = "mock-bucket"
+	b := setupBsDBTest(t)
+	require.NoError(t, b.db.Table(GetObjectsTableName(bucketName)).AutoMigrate(&Object{}))
+	objects := []*Object{
+		{ID: 1, BucketName: bucketName, ObjectName: "a", PayloadSize: 3, CreateTime: 30},
+		{ID: 2, BucketName: bucketName, ObjectName: "b", PayloadSize: 1, CreateTime: 10},
+		{ID: 3, BucketName: bucketName, ObjectName: "c", PayloadSize: 3, CreateTime: 20},
+		{ID: 4, BucketName: bucketName, ObjectName: "d", PayloadSize: 2, CreateTime: 40},
+		{ID: 5, BucketName: bucketName, ObjectName: "e", PayloadSize: 1, CreateTime: 50, Removed: true},
+	}
+	require.NoError(t, b.db.Table(GetObjectsTableName(bucketName)).Create(objects).Error)
+
+	testCases := []struct {
+		name              string
+		continuationToken string
+		filter            *ListObjectsFilter
+		wantedObjects     []string
+		wantedErr         error
+	}{
+		{"name in descending order", "c", &ListObjectsFilter{SortDesc: true}, []string{"c", "b", "a"}, nil},
+		{"size in ascending order", "", &ListObjectsFilter{SortBy: ObjectSortBySize}, []string{"b", "d", "a"}, nil},
+		{"size continued within the same size", "c", &ListObjectsFilter{SortBy: ObjectSortBySize}, []string{"c"}, nil},
+		{"size continued from a larger size", "d", &ListObjectsFilter{SortBy: ObjectSortBySize}, []string{"d", "a", "c"}, nil},
+		{"size in descending order", "a", &ListObjectsFilter{SortBy: ObjectSortBySize, SortDesc: true},
+			[]string{"a", "d", "b"}, nil},
+		{"create time in descending order", "c", &ListObjectsFilter{SortBy: ObjectSortByCreateTime, SortDesc: true},
+			[]string{"c", "b"}, nil},
+		{"continued from the removed object", "e", &ListObjectsFilter{SortBy: ObjectSortByCreateTime, SortDesc: true},
+			[]string{"d", "a", "c"}, nil},
+		{"object of the token not found", "x", &ListObjectsFilter{SortBy: ObjectSortBySize}, nil,
+			ErrInvalidContinuationToken},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := b.ListObjectsByBucketName(bucketName, tt.continuationToken, "", "", 2, false, tt.filter)
+			assert.Equal(t, tt.wantedErr, err)
+			var objectNames []string
+			for _, result := range results {
+				objectNames = append(objectNames, result.ObjectName)
+			}
+			assert.Equal(t, tt.wantedObjects, objectNames)
+		})
+	}
+}
@@ -2,6 +2,9 @@ package config
 
 // SQLDBConfig is sql db config
 type SQLDBConfig struct {
+	// Driver is mysql, postgres or sqlite, the default is mysql. The Database of the sqlite driver is the path of
+	// the database file, and the User, Passwd and Address are ignored.
+	Driver          string
 	User            string
 	Passwd          string
 	Address         string
@@ -0,0 +1,70 @@
+package dialect
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+)
+
+const (
+	// MySQLDriver defines the mysql driver, it is the default driver.
+	MySQLDriver = "mysql"
+	// PostgresDriver defines the postgresql driver.
+	PostgresDriver = "postgres"
+	// SQLiteDriver defines the embedded sqlite driver, the database is the path of the database file, which
+	// needs no database server for the development and the ci.
+	SQLiteDriver = "sqlite"
+)
+
+// Dialect abstracts the differences of the sql engines used by the sp db and the block syncer db.
+type Dialect interface {
+	// Driver returns the name of the driver.
+	Driver() string
+	// Dialector returns the gorm dialector which connects the database of the config.
+	Dialector(cfg *config.SQLDBConfig) gorm.Dialector
+	// IsDuplicateEntry returns whether the error is caused by violating the primary key or an unique index.
+	IsDuplicateEntry(err error) bool
+	// IsTableAlreadyExists returns whether the error is caused by creating an existing table.
+	IsTableAlreadyExists(err error) bool
+	// FindInSet returns the condition that the comma separated list in the column contains the value of
+	// the placeholder of the condition.
+	FindInSet(column string) string
+	// Excluded returns the expression of the value of the column in the row proposed for insertion, which can be
+	// used in the assignments of an upsert.
+	Excluded(column string) string
+}
+
+// New returns the dialect of the driver, an empty driver means the mysql driver.
+func New(driver string) (Dialect, error) {
+	switch driver {
+	case "", MySQLDriver:
+		return mysqlDialect{}, nil
+	case PostgresDriver:
+		return postgresDialect{}, nil
+	case SQLiteDriver:
+		return sqliteDialect{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported sql db driver: %s", driver)
+	}
+}
+
+// Open opens the database of the config by the dialect of the driver of the config.
+func Open(cfg *config.SQLDBConfig, gormConfig *gorm.Config) (*gorm.DB, Dialect, error) {
+	d, err := New(cfg.Driver)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := gorm.Open(d.Dialector(cfg), gormConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, d, nil
+}
+
+// ShardTableName returns the name of the shard of the table, the tables split into shards are named in the same
+// way on all the sql engines.
+func ShardTableName(table string, shard int) string {
+	return fmt.Sprintf("%s_%02d", table, shard)
+}
@@ -0,0 +1,73 @@
+package dialect
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+)
+
+type dialectTestTable struct {
+	ID   uint64 `gorm:"primary_key"`
+	Tags string
+}
+
+func TestNew(t *testing.T) {
+	for driver, wanted := range map[string]string{"": MySQLDriver, MySQLDriver: MySQLDriver,
+		PostgresDriver: PostgresDriver, SQLiteDriver: SQLiteDriver} {
+		d, err := New(driver)
+		require.NoError(t, err)
+		assert.Equal(t, wanted, d.Driver())
+	}
+	_, err := New("oracle")
+	assert.Error(t, err)
+}
+
+func TestSQLiteDialect(t *testing.T) {
+	cfg := &config.SQLDBConfig{Driver: SQLiteDriver, Database: filepath.Join(t.TempDir(), "test.db")}
+	db, d, err := Open(cfg, &gorm.Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.AutoMigrate(&dialectTestTable{}))
+	err = db.Migrator().CreateTable(&dialectTestTable{})
+	assert.True(t, d.IsTableAlreadyExists(err))
+
+	require.NoError(t, db.Create(&dialectTestTable{ID: 1, Tags: "1,12,3"}).Error)
+	err = db.Create(&dialectTestTable{ID: 1}).Error
+	assert.True(t, d.IsDuplicateEntry(err))
+	assert.False(t, d.IsDuplicateEntry(nil))
+
+	var count int64
+	require.NoError(t, db.Model(&dialectTestTable{}).Where(d.FindInSet("tags"), "12").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	require.NoError(t, db.Model(&dialectTestTable{}).Where(d.FindInSet("tags"), "2").Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	// the conflicting row appends the tags of the row proposed for insertion
+	require.NoError(t, db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"tags": gorm.Expr("tags || ',' || " + d.Excluded("tags"))}),
+	}).Create([]*dialectTestTable{{ID: 1, Tags: "4"}, {ID: 2, Tags: "5"}}).Error)
+	var rows []*dialectTestTable
+	require.NoError(t, db.Order("id").Find(&rows).Error)
+	assert.Equal(t, []*dialectTestTable{{ID: 1, Tags: "1,12,3,4"}, {ID: 2, Tags: "5"}}, rows)
+}
+
+func TestDialect_Excluded(t *testing.T) {
+	for driver, wanted := range map[string]string{MySQLDriver: "VALUES(count)", PostgresDriver: "excluded.count",
+		SQLiteDriver: "excluded.count"} {
+		d, err := New(driver)
+		require.NoError(t, err)
+		assert.Equal(t, wanted, d.Excluded("count"))
+	}
+}
+
+func TestShardTableName(t *testing.T) {
+	assert.Equal(t, "objects_05", ShardTableName("objects", 5))
+	assert.Equal(t, "objects_63", ShardTableName("objects", 63))
+}
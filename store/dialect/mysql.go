@@ -0,0 +1,55 @@
+package dialect
+
+import (
+	"errors"
+	"fmt"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+)
+
+const (
+	// mysqlDuplicateEntryCode is the error number of the duplicate entry for a key
+	mysqlDuplicateEntryCode = 1062
+	// mysqlTableExistsCode is the error number of creating an existing table
+	mysqlTableExistsCode = 1050
+)
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Driver() string {
+	return MySQLDriver
+}
+
+func (mysqlDialect) Dialector(cfg *config.SQLDBConfig) gorm.Dialector {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		cfg.User, cfg.Passwd, cfg.Address, cfg.Database)
+	return mysql.Open(dsn)
+}
+
+func (mysqlDialect) IsDuplicateEntry(err error) bool {
+	return mysqlErrorNumber(err) == mysqlDuplicateEntryCode
+}
+
+func (mysqlDialect) IsTableAlreadyExists(err error) bool {
+	return mysqlErrorNumber(err) == mysqlTableExistsCode
+}
+
+func (mysqlDialect) FindInSet(column string) string {
+	return "FIND_IN_SET(?, " + column + ") > 0"
+}
+
+func (mysqlDialect) Excluded(column string) string {
+	return "VALUES(" + column + ")"
+}
+
+func mysqlErrorNumber(err error) uint16 {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return 0
+	}
+	return mysqlErr.Number
+}
@@ -0,0 +1,60 @@
+package dialect
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+)
+
+const (
+	// postgresUniqueViolationCode is the sql state of violating an unique constraint
+	postgresUniqueViolationCode = "23505"
+	// postgresDuplicateTableCode is the sql state of creating an existing table
+	postgresDuplicateTableCode = "42P07"
+)
+
+type postgresDialect struct{}
+
+func (postgresDialect) Driver() string {
+	return PostgresDriver
+}
+
+func (postgresDialect) Dialector(cfg *config.SQLDBConfig) gorm.Dialector {
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Passwd),
+		Host:     cfg.Address,
+		Path:     cfg.Database,
+		RawQuery: "sslmode=prefer",
+	}
+	return postgres.Open(dsn.String())
+}
+
+func (postgresDialect) IsDuplicateEntry(err error) bool {
+	return postgresErrorCode(err) == postgresUniqueViolationCode
+}
+
+func (postgresDialect) IsTableAlreadyExists(err error) bool {
+	return postgresErrorCode(err) == postgresDuplicateTableCode
+}
+
+func (postgresDialect) FindInSet(column string) string {
+	return "? = ANY(string_to_array(" + column + ", ','))"
+}
+
+func (postgresDialect) Excluded(column string) string {
+	return "excluded." + column
+}
+
+func postgresErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+	return pgErr.Code
+}
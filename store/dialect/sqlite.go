@@ -0,0 +1,45 @@
+package dialect
+
+import (
+	"strings"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+)
+
+// sqliteDefaultOptions makes the concurrent writers wait for the lock of the database file instead of failing,
+// it is used if the database has no options.
+const sqliteDefaultOptions = "_busy_timeout=5000&_journal_mode=WAL"
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Driver() string {
+	return SQLiteDriver
+}
+
+func (sqliteDialect) Dialector(cfg *config.SQLDBConfig) gorm.Dialector {
+	dsn := cfg.Database
+	if !strings.Contains(dsn, "?") {
+		dsn += "?" + sqliteDefaultOptions
+	}
+	return sqlite.Open(dsn)
+}
+
+// IsDuplicateEntry matches the error message since the error codes of the sqlite driver are only defined with cgo.
+func (sqliteDialect) IsDuplicateEntry(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+func (sqliteDialect) IsTableAlreadyExists(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "already exists")
+}
+
+func (sqliteDialect) FindInSet(column string) string {
+	return "(',' || " + column + " || ',') LIKE ('%,' || ? || ',%')"
+}
+
+func (sqliteDialect) Excluded(column string) string {
+	return "excluded." + column
+}
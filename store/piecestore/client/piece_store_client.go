@@ -26,6 +26,10 @@ const (
 	PieceStoreSuccessDel = "del_piece_store_success"
 	// PieceStoreFailureDel defines the metrics label of unsuccessfully delete piece data
 	PieceStoreFailureDel = "del_piece_store_failure"
+	// PieceStoreSuccessList defines the metrics label of successfully list piece data
+	PieceStoreSuccessList = "list_piece_store_success"
+	// PieceStoreFailureList defines the metrics label of unsuccessfully list piece data
+	PieceStoreFailureList = "list_piece_store_failure"
 )
 
 // PieceStoreAPI provides an interface to enable mocking the
@@ -130,3 +134,35 @@ func (client *StoreClient) DeletePiece(ctx context.Context, key string) error {
 	err = client.ps.Delete(ctx, key)
 	return err
 }
+
+// ListPieces lists pieces from piece store.
+func (client *StoreClient) ListPieces(ctx context.Context, prefix, marker string, limit int64) (
+	pieces []*corepiecestore.PieceInfo, err error) {
+	startTime := time.Now()
+	defer func() {
+		if err != nil {
+			metrics.PieceStoreCounter.WithLabelValues(PieceStoreFailureList).Inc()
+			metrics.PieceStoreTime.WithLabelValues(PieceStoreFailureList).Observe(
+				time.Since(startTime).Seconds())
+			return
+		}
+		metrics.PieceStoreCounter.WithLabelValues(PieceStoreSuccessList).Inc()
+		metrics.PieceStoreTime.WithLabelValues(PieceStoreSuccessList).Observe(
+			time.Since(startTime).Seconds())
+	}()
+
+	objs, err := client.ps.List(ctx, prefix, marker, limit)
+	if err != nil {
+		log.Errorw("failed to list piece data from piece store", "error", err)
+		return nil, err
+	}
+	pieces = make([]*corepiecestore.PieceInfo, 0, len(objs))
+	for _, obj := range objs {
+		pieces = append(pieces, &corepiecestore.PieceInfo{
+			Key:     obj.Key(),
+			Size:    obj.Size(),
+			ModTime: obj.ModTime(),
+		})
+	}
+	return pieces, nil
+}
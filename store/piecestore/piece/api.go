@@ -2,6 +2,8 @@ package piece
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/bnb-chain/greenfield-storage-provider/store/piecestore/storage"
@@ -30,3 +32,29 @@ func (p *PieceStore) Delete(ctx context.Context, key string) error {
 func (p *PieceStore) GetPieceInfo(ctx context.Context, key string) (storage.Object, error) {
 	return p.storeAPI.HeadObject(ctx, key)
 }
+
+// List returns at most limit pieces whose key has the prefix and is after the marker in PieceStore,
+// it walks the storage by ListAllObjects, and falls back to ListObjects if it is unsupported.
+func (p *PieceStore) List(ctx context.Context, prefix, marker string, limit int64) ([]storage.Object, error) {
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	objCh, err := p.storeAPI.ListAllObjects(listCtx, prefix, marker)
+	if errors.Is(err, storage.ErrUnsupportedMethod) {
+		return p.storeAPI.ListObjects(ctx, prefix, marker, "", limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]storage.Object, 0)
+	for obj := range objCh {
+		// a nil object means that an error occurs when listing objects
+		if obj == nil {
+			return nil, fmt.Errorf("failed to list all objects after %s", marker)
+		}
+		objs = append(objs, obj)
+		if int64(len(objs)) >= limit {
+			break
+		}
+	}
+	return objs, nil
+}
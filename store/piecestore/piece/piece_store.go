@@ -22,7 +22,7 @@ func NewPieceStore(pieceConfig *storage.PieceStoreConfig) (*PieceStore, error) {
 		return nil, err
 	}
 	log.Debugw("piece store is running", "storage type", pieceConfig.Store.Storage,
-		"shards", pieceConfig.Shards)
+		"shards", pieceConfig.Shards, "tier", pieceConfig.Tier.Enable)
 
 	return &PieceStore{blob}, nil
 }
@@ -50,6 +50,14 @@ func checkConfig(cfg *storage.PieceStoreConfig) {
 		cfg.Store.BucketURL = p
 		cfg.Store.BucketURL += "/"
 	}
+	if cfg.Tier.Enable {
+		if cfg.Tier.HotPath == "" || cfg.Tier.HotCapacity <= 0 {
+			log.Panic("HotPath and HotCapacity should be set if tier is enabled")
+		}
+		if cfg.Tier.ColdCapacity < 0 {
+			log.Panic("ColdCapacity should be equal or greater than zero")
+		}
+	}
 }
 
 func overrideConfigFromEnv(cfg *storage.PieceStoreConfig) {
@@ -79,6 +87,12 @@ func createStorage(cfg storage.PieceStoreConfig) (storage.ObjectStorage, error)
 		return nil, err
 	}
 
+	if cfg.Tier.Enable {
+		if object, err = storage.NewTiered(cfg.Tier, object); err != nil {
+			log.Errorw("failed to create tiered storage", "error", err, "hot_path", cfg.Tier.HotPath)
+			return nil, err
+		}
+	}
 	return object, nil
 }
 
@@ -0,0 +1,424 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+var _ ObjectStorage = &azblobStore{}
+
+const (
+	// azblobMaxResults defines the max number of blobs returned by one list request
+	azblobMaxResults = 5000
+	// azblobMaxListMarkers defines the max number of the cached list markers
+	azblobMaxListMarkers = 1024
+)
+
+type azblobStore struct {
+	account   string
+	container string
+	client    *container.Client
+
+	// markers caches the opaque continuation markers of azure blob by the last listed key, so listing
+	// the next page after the key resumes from the marker instead of iterating from the prefix.
+	markerMux sync.Mutex
+	markers   map[azblobListMarkerKey]string
+}
+
+// azblobListMarkerKey is the key of the cached list marker.
+type azblobListMarkerKey struct {
+	prefix    string
+	delimiter string
+	lastKey   string
+}
+
+func newAzblobStore(cfg ObjectStorageConfig) (ObjectStorage, error) {
+	endpoint, account, containerName, err := parseAzblobEndpoint(cfg.BucketURL)
+	if err != nil {
+		log.Errorw("failed to parse azblob endpoint", "error", err)
+		return nil, err
+	}
+	clientOptions := azcore.ClientOptions{
+		Transport: getHTTPClient(cfg.TLSInsecureSkipVerify),
+		// the sdk retries 3 times by default, the negative max retries disables the retry
+		Retry: policy.RetryOptions{MaxRetries: -1, RetryDelay: time.Duration(cfg.MinRetryDelay)},
+	}
+	if cfg.MaxRetries > 0 {
+		clientOptions.Retry.MaxRetries = int32(cfg.MaxRetries)
+	}
+
+	// If IAM type is AKSK, you must provide the account key, the account name is parsed from the bucket url if not provided
+	// If IAM type is SA, the managed identity of the vm or the pod is used, provide the client id if it is user-assigned
+	var client *container.Client
+	switch cfg.IAMType {
+	case AKSKIAMType:
+		key := getSecretKeyFromEnv(AzureAccountName, AzureAccountKey, "")
+		if key.accessKey != "" {
+			account = key.accessKey
+		}
+		if key.secretKey == "" {
+			return nil, fmt.Errorf("failed to read azure account key")
+		}
+		cred, err := container.NewSharedKeyCredential(account, key.secretKey)
+		if err != nil {
+			log.Errorw("failed to decode azure account key", "error", err)
+			return nil, err
+		}
+		if client, err = container.NewClientWithSharedKeyCredential(endpoint, cred,
+			&container.ClientOptions{ClientOptions: clientOptions}); err != nil {
+			log.Errorw("failed to new azblob client", "error", err)
+			return nil, err
+		}
+		log.Debugw("use aksk to access azblob", "account", account)
+	case SAIAMType:
+		options := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
+		if clientID := os.Getenv(AzureClientID); clientID != "" {
+			options.ID = azidentity.ClientID(clientID)
+		}
+		cred, err := azidentity.NewManagedIdentityCredential(options)
+		if err != nil {
+			log.Errorw("failed to new azure managed identity credential", "error", err)
+			return nil, err
+		}
+		if client, err = container.NewClient(endpoint, cred, &container.ClientOptions{ClientOptions: clientOptions}); err != nil {
+			log.Errorw("failed to new azblob client", "error", err)
+			return nil, err
+		}
+		log.Debugw("use managed identity to access azblob", "account", account)
+	default:
+		log.Errorf("unknown IAM type: %s", cfg.IAMType)
+		return nil, fmt.Errorf("unknown IAM type: %s", cfg.IAMType)
+	}
+	log.Infow("new azblob store succeeds", "account", account, "container", containerName)
+	return newAzblobStoreWithClient(account, containerName, client), nil
+}
+
+func newAzblobStoreWithClient(account, containerName string, client *container.Client) *azblobStore {
+	return &azblobStore{
+		account:   account,
+		container: containerName,
+		client:    client,
+		markers:   make(map[azblobListMarkerKey]string),
+	}
+}
+
+// parseAzblobEndpoint parses the bucket url which supports two styles:
+// Azure: https://<account>.blob.core.windows.net/<container>
+// Emulator(e.g. Azurite): http://127.0.0.1:10000/<account>/<container>
+func parseAzblobEndpoint(bucketURL string) (string, string, string, error) {
+	uri, err := url.ParseRequestURI(strings.Trim(bucketURL, "/"))
+	if err != nil {
+		return "", "", "", err
+	}
+	parts := strings.Split(strings.Trim(uri.Path, "/"), "/")
+	var account, containerName string
+	if strings.Contains(uri.Host, ".blob.") {
+		account = strings.Split(uri.Host, ".")[0]
+		containerName = parts[0]
+	} else if len(parts) == 2 {
+		account, containerName = parts[0], parts[1]
+	}
+	if account == "" || containerName == "" {
+		return "", "", "", fmt.Errorf("invalid azblob bucket url: %s", bucketURL)
+	}
+	return fmt.Sprintf("%s://%s%s", uri.Scheme, uri.Host, strings.TrimRight(uri.Path, "/")), account, containerName, nil
+}
+
+func (s *azblobStore) String() string {
+	return fmt.Sprintf("azblob://%s/%s/", s.account, s.container)
+}
+
+func (s *azblobStore) CreateBucket(ctx context.Context) error {
+	_, err := s.client.Create(ctx, nil)
+	if err != nil && bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		log.Errorw("azblob failed to create container", "error", err)
+		return nil
+	}
+	return err
+}
+
+func (s *azblobStore) GetObject(ctx context.Context, key string, offset, limit int64) (io.ReadCloser, error) {
+	options := &blob.DownloadStreamOptions{}
+	if offset > 0 || limit > 0 {
+		// the zero count means the range is to the end of the blob
+		options.Range = blob.HTTPRange{Offset: offset}
+		if limit > 0 {
+			options.Range.Count = limit
+		}
+	}
+	resp, err := s.client.NewBlobClient(key).DownloadStream(ctx, options)
+	if err != nil {
+		if isAzblobNotFound(err) {
+			err = fmt.Errorf("%w: %w", ErrNoSuchObject, err)
+		}
+		log.Errorw("azblob failed to get object", "error", err)
+		return nil, err
+	}
+	if offset == 0 && limit == -1 {
+		return verifyChecksum(resp.Body, azblobMetadata(resp.Metadata, ChecksumAlgo)), nil
+	}
+	return resp.Body, nil
+}
+
+func (s *azblobStore) PutObject(ctx context.Context, key string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	body := bytes.NewReader(data)
+	_, err = s.client.NewBlockBlobClient(key).Upload(ctx, streaming.NopCloser(body), &blockblob.UploadOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: to.Ptr(OctetStream)},
+		Metadata:    map[string]*string{ChecksumAlgo: to.Ptr(generateChecksum(bytes.NewReader(data)))},
+	})
+	return err
+}
+
+func (s *azblobStore) DeleteObject(ctx context.Context, key string) error {
+	_, err := s.client.NewBlobClient(key).Delete(ctx, nil)
+	if err != nil && isAzblobNotFound(err) {
+		log.Errorw("azblob failed to delete object", "error", err)
+		return nil
+	}
+	return err
+}
+
+func (s *azblobStore) HeadBucket(ctx context.Context) error {
+	if _, err := s.client.GetProperties(ctx, nil); err != nil {
+		log.Errorw("azblob failed to head container", "error", err)
+		if isAzblobNotFound(err) {
+			return ErrNoSuchBucket
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *azblobStore) HeadObject(ctx context.Context, key string) (Object, error) {
+	resp, err := s.client.NewBlobClient(key).GetProperties(ctx, nil)
+	if err != nil {
+		if isAzblobNotFound(err) {
+			err = os.ErrNotExist
+		}
+		log.Errorw("azblob failed to head object", "error", err)
+		return nil, err
+	}
+	var (
+		size    int64
+		modTime time.Time
+	)
+	if resp.ContentLength != nil {
+		size = *resp.ContentLength
+	}
+	if resp.LastModified != nil {
+		modTime = *resp.LastModified
+	}
+	return &object{
+		key,
+		size,
+		modTime,
+		strings.HasSuffix(key, "/"),
+	}, nil
+}
+
+// ListObjects lists the objects after the marker. The marker of azure blob is an opaque continuation
+// token, the token responded with the last page is cached by the last listed key, so the next page
+// after the key is listed from the token. The pages are iterated from the prefix only if the token
+// is not cached, e.g. the store is restarted.
+func (s *azblobStore) ListObjects(ctx context.Context, prefix, marker, delimiter string, limit int64) ([]Object, error) {
+	if limit <= 0 || limit > azblobMaxResults {
+		limit = azblobMaxResults
+	}
+	var (
+		objs       []Object
+		nextMarker string
+		cached     bool
+	)
+	if marker != "" {
+		nextMarker, cached = s.popListMarker(azblobListMarkerKey{prefix: prefix, delimiter: delimiter, lastKey: marker})
+	}
+	for {
+		// the service may respond fewer blobs than the max results, request the remaining ones only,
+		// so the next marker of the last page follows the last listed object
+		page, pageMarker, err := s.listBlobs(ctx, prefix, delimiter, nextMarker, int32(limit-int64(len(objs))))
+		if err != nil {
+			log.Errorw("azblob failed to list objects", "error", err)
+			return nil, err
+		}
+		for _, o := range page {
+			if !cached && o.Key() <= marker {
+				continue
+			}
+			objs = append(objs, o)
+		}
+		nextMarker = pageMarker
+		if int64(len(objs)) >= limit || nextMarker == "" {
+			break
+		}
+	}
+	if len(objs) > 0 && nextMarker != "" {
+		s.pushListMarker(azblobListMarkerKey{prefix: prefix, delimiter: delimiter, lastKey: objs[len(objs)-1].Key()},
+			nextMarker)
+	}
+	return objs, nil
+}
+
+// ListAllObjects streams all the objects after the marker, a nil object is sent to the channel if
+// the listing fails halfway.
+func (s *azblobStore) ListAllObjects(ctx context.Context, prefix, marker string) (<-chan Object, error) {
+	var (
+		nextMarker string
+		cached     bool
+	)
+	if marker != "" {
+		nextMarker, cached = s.popListMarker(azblobListMarkerKey{prefix: prefix, lastKey: marker})
+	}
+	page, nextMarker, err := s.listBlobs(ctx, prefix, "", nextMarker, azblobMaxResults)
+	if err != nil {
+		log.Errorw("azblob failed to list all objects", "error", err)
+		return nil, err
+	}
+	objs := make(chan Object, azblobMaxResults)
+	go func() {
+		defer close(objs)
+		for {
+			for _, o := range page {
+				if !cached && o.Key() <= marker {
+					continue
+				}
+				select {
+				case objs <- o:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if nextMarker == "" {
+				return
+			}
+			if page, nextMarker, err = s.listBlobs(ctx, prefix, "", nextMarker, azblobMaxResults); err != nil {
+				log.Errorw("azblob failed to list all objects", "error", err)
+				objs <- nil
+				return
+			}
+		}
+	}()
+	return objs, nil
+}
+
+// listBlobs lists one page of the blobs from the marker, the blob prefixes are listed as the directories
+// if the delimiter is not empty. It returns the sorted objects and the marker of the next page.
+func (s *azblobStore) listBlobs(ctx context.Context, prefix, delimiter, marker string, maxResults int32) (
+	[]Object, string, error) {
+	var (
+		objs       []Object
+		items      []*container.BlobItem
+		nextMarker *string
+	)
+	if delimiter == "" {
+		resp, err := s.client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+			Prefix:     azblobOptionalString(prefix),
+			Marker:     azblobOptionalString(marker),
+			MaxResults: to.Ptr(maxResults),
+		}).NextPage(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		items, nextMarker = resp.Segment.BlobItems, resp.NextMarker
+	} else {
+		resp, err := s.client.NewListBlobsHierarchyPager(delimiter, &container.ListBlobsHierarchyOptions{
+			Prefix:     azblobOptionalString(prefix),
+			Marker:     azblobOptionalString(marker),
+			MaxResults: to.Ptr(maxResults),
+		}).NextPage(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		items, nextMarker = resp.Segment.BlobItems, resp.NextMarker
+		for _, p := range resp.Segment.BlobPrefixes {
+			objs = append(objs, &object{*p.Name, 0, time.Unix(0, 0), true})
+		}
+	}
+	for _, item := range items {
+		var (
+			size    int64
+			modTime time.Time
+		)
+		if item.Properties != nil && item.Properties.ContentLength != nil {
+			size = *item.Properties.ContentLength
+		}
+		if item.Properties != nil && item.Properties.LastModified != nil {
+			modTime = *item.Properties.LastModified
+		}
+		objs = append(objs, &object{*item.Name, size, modTime, strings.HasSuffix(*item.Name, "/")})
+	}
+	// the blobs and the blob prefixes are responded separately
+	sort.Slice(objs, func(i, j int) bool { return objs[i].Key() < objs[j].Key() })
+	if nextMarker == nil {
+		return objs, "", nil
+	}
+	return objs, *nextMarker, nil
+}
+
+// popListMarker returns and removes the cached marker of the next page after the last listed key.
+func (s *azblobStore) popListMarker(key azblobListMarkerKey) (string, bool) {
+	s.markerMux.Lock()
+	defer s.markerMux.Unlock()
+	marker, ok := s.markers[key]
+	delete(s.markers, key)
+	return marker, ok
+}
+
+// pushListMarker caches the marker of the next page after the last listed key, the cache is reset if it
+// is full, since the markers of the abandoned listings are never popped.
+func (s *azblobStore) pushListMarker(key azblobListMarkerKey, marker string) {
+	s.markerMux.Lock()
+	defer s.markerMux.Unlock()
+	if len(s.markers) >= azblobMaxListMarkers {
+		s.markers = make(map[azblobListMarkerKey]string)
+	}
+	s.markers[key] = marker
+}
+
+func azblobOptionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// azblobMetadata returns the metadata value by the case-insensitive name, the names of the responded
+// metadata are canonicalized as http headers.
+func azblobMetadata(metadata map[string]*string, name string) string {
+	for k, v := range metadata {
+		if strings.EqualFold(k, name) && v != nil {
+			return *v
+		}
+	}
+	return ""
+}
+
+func isAzblobNotFound(err error) bool {
+	var respErr *azcore.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,596 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mockAzblobAccount = "devstoreaccount1"
+	mockAzblobToken   = "mockToken"
+)
+
+var mockAzblobKey = []byte("mockAzblobAccountKey")
+
+// fakeAzblobServer is the http fake of azure blob storage in emulator path style, it verifies the
+// signature of requests and pages the list results by pageSize.
+type fakeAzblobServer struct {
+	mu          sync.Mutex
+	container   bool
+	blobs       map[string][]byte
+	metadata    map[string]string
+	pageSize    int
+	failures    int
+	token       string
+	listMarkers []string
+}
+
+func newFakeAzblobServer() *fakeAzblobServer {
+	return &fakeAzblobServer{blobs: map[string][]byte{}, metadata: map[string]string{}, pageSize: azblobMaxResults}
+}
+
+func (f *fakeAzblobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failures > 0 {
+		f.failures--
+		writeFakeAzblobError(w, http.StatusInternalServerError, "InternalError")
+		return
+	}
+	if !f.authorized(r) {
+		writeFakeAzblobError(w, http.StatusForbidden, "AuthenticationFailed")
+		return
+	}
+	containerPath := "/" + mockAzblobAccount + "/" + mockBucket
+	if !strings.HasPrefix(r.URL.Path, containerPath) {
+		writeFakeAzblobError(w, http.StatusNotFound, "ContainerNotFound")
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, containerPath), "/")
+	if name == "" {
+		f.serveContainer(w, r)
+		return
+	}
+	if !f.container {
+		writeFakeAzblobError(w, http.StatusNotFound, "ContainerNotFound")
+		return
+	}
+	data, ok := f.blobs[name]
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.blobs[name] = body
+		f.metadata[name] = r.Header.Get("x-ms-meta-" + ChecksumAlgo)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if !ok {
+			writeFakeAzblobError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		delete(f.blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+	case http.MethodHead, http.MethodGet:
+		if !ok {
+			writeFakeAzblobError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		w.Header().Set("Last-Modified", mockModifiedTime.Format(http.TimeFormat))
+		w.Header().Set("x-ms-meta-"+ChecksumAlgo, f.metadata[name])
+		status := http.StatusOK
+		if rng := r.Header.Get("x-ms-range"); rng != "" {
+			var start, end int
+			bounds := strings.Split(strings.TrimPrefix(rng, "bytes="), "-")
+			start, _ = strconv.Atoi(bounds[0])
+			end = len(data) - 1
+			if bounds[1] != "" {
+				end, _ = strconv.Atoi(bounds[1])
+			}
+			data = data[start : end+1]
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	}
+}
+
+// authorized verifies the shared key signature independently of the sdk, following the azure storage
+// rest api spec, or verifies the bearer token if the token is set.
+func (f *fakeAzblobServer) authorized(r *http.Request) bool {
+	if f.token != "" {
+		return r.Header.Get("Authorization") == "Bearer "+f.token
+	}
+	mac := hmac.New(sha256.New, mockAzblobKey)
+	mac.Write([]byte(azblobSharedKeyStringToSign(mockAzblobAccount, r)))
+	return r.Header.Get("Authorization") ==
+		"SharedKey "+mockAzblobAccount+":"+base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// azblobSharedKeyStringToSign builds the string to sign of the shared key authorization, see
+// https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func azblobSharedKeyStringToSign(account string, r *http.Request) string {
+	h := r.Header
+	contentLength := h.Get("Content-Length")
+	if contentLength == "" && r.ContentLength > 0 {
+		contentLength = strconv.FormatInt(r.ContentLength, 10)
+	}
+	if contentLength == "0" {
+		contentLength = ""
+	}
+	var headers []string
+	for name := range h {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-ms-") {
+			headers = append(headers, lower+":"+strings.TrimSpace(h.Get(name)))
+		}
+	}
+	sort.Strings(headers)
+	resource := "/" + account + r.URL.EscapedPath()
+	query := r.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := query[name]
+		sort.Strings(values)
+		resource += "\n" + strings.ToLower(name) + ":" + strings.Join(values, ",")
+	}
+	return strings.Join(append([]string{
+		r.Method,
+		h.Get("Content-Encoding"),
+		h.Get("Content-Language"),
+		contentLength,
+		h.Get("Content-MD5"),
+		h.Get("Content-Type"),
+		h.Get("Date"),
+		h.Get("If-Modified-Since"),
+		h.Get("If-Match"),
+		h.Get("If-None-Match"),
+		h.Get("If-Unmodified-Since"),
+		h.Get("Range"),
+	}, append(headers, resource)...), "\n")
+}
+
+func (f *fakeAzblobServer) serveContainer(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case query.Get("comp") == "list":
+		if !f.container {
+			writeFakeAzblobError(w, http.StatusNotFound, "ContainerNotFound")
+			return
+		}
+		f.listMarkers = append(f.listMarkers, query.Get("marker"))
+		f.list(w, query.Get("prefix"), query.Get("delimiter"), query.Get("marker"), query.Get("maxresults"))
+	case r.Method == http.MethodPut:
+		if f.container {
+			writeFakeAzblobError(w, http.StatusConflict, "ContainerAlreadyExists")
+			return
+		}
+		f.container = true
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		if !f.container {
+			writeFakeAzblobError(w, http.StatusNotFound, "ContainerNotFound")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+type fakeAzblobBlob struct {
+	Name          string `xml:"Name"`
+	LastModified  string `xml:"Properties>Last-Modified"`
+	ContentLength int    `xml:"Properties>Content-Length"`
+}
+
+type fakeAzblobPrefix struct {
+	Name string `xml:"Name"`
+}
+
+type fakeAzblobListResult struct {
+	XMLName      xml.Name           `xml:"EnumerationResults"`
+	Blobs        []fakeAzblobBlob   `xml:"Blobs>Blob"`
+	BlobPrefixes []fakeAzblobPrefix `xml:"Blobs>BlobPrefix"`
+	NextMarker   string             `xml:"NextMarker"`
+}
+
+// list pages the sorted blobs, the marker is the opaque index of the next blob.
+func (f *fakeAzblobServer) list(w http.ResponseWriter, prefix, delimiter, marker, maxResults string) {
+	names := make([]string, 0, len(f.blobs))
+	for name := range f.blobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	start := 0
+	if marker != "" {
+		start, _ = strconv.Atoi(strings.TrimPrefix(marker, "m"))
+	}
+	max, _ := strconv.Atoi(maxResults)
+	if max > f.pageSize {
+		max = f.pageSize
+	}
+	result := fakeAzblobListResult{}
+	count := 0
+	i := start
+	for i < len(names) && count < max {
+		name := names[i]
+		i++
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		count++
+		if idx := strings.Index(name[len(prefix):], delimiter); delimiter != "" && idx >= 0 {
+			// the blobs in the same prefix are skipped, as azure does
+			p := name[:len(prefix)+idx+len(delimiter)]
+			result.BlobPrefixes = append(result.BlobPrefixes, fakeAzblobPrefix{Name: p})
+			for i < len(names) && strings.HasPrefix(names[i], p) {
+				i++
+			}
+			continue
+		}
+		result.Blobs = append(result.Blobs, fakeAzblobBlob{
+			Name:          name,
+			LastModified:  mockModifiedTime.Format(http.TimeFormat),
+			ContentLength: len(f.blobs[name]),
+		})
+	}
+	if i < len(names) {
+		result.NextMarker = fmt.Sprintf("m%d", i)
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func writeFakeAzblobError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>mock error</Message></Error>", code)
+}
+
+func setupAzblobTest(t *testing.T) (*azblobStore, *fakeAzblobServer) {
+	fake := newFakeAzblobServer()
+	server := httptest.NewTLSServer(fake)
+	t.Cleanup(server.Close)
+	return newAzblobTestStore(t, server, base64.StdEncoding.EncodeToString(mockAzblobKey), 0), fake
+}
+
+func newAzblobTestStore(t *testing.T, server *httptest.Server, key string, maxRetries int32) *azblobStore {
+	cred, err := container.NewSharedKeyCredential(mockAzblobAccount, key)
+	require.NoError(t, err)
+	client, err := container.NewClientWithSharedKeyCredential(server.URL+"/"+mockAzblobAccount+"/"+mockBucket, cred,
+		newAzblobTestClientOptions(server, maxRetries))
+	require.NoError(t, err)
+	return newAzblobStoreWithClient(mockAzblobAccount, mockBucket, client)
+}
+
+func newAzblobTestClientOptions(server *httptest.Server, maxRetries int32) *container.ClientOptions {
+	if maxRetries == 0 {
+		maxRetries = -1
+	}
+	return &container.ClientOptions{ClientOptions: azcore.ClientOptions{
+		Transport: server.Client(),
+		Retry:     policy.RetryOptions{MaxRetries: maxRetries, RetryDelay: time.Millisecond},
+	}}
+}
+
+// TestAzblob_SharedKeyStringToSign verifies the signature verifier of the fake server by the known answer,
+// the request and the string to sign are the example of azure storage rest api doc, the signature is signed
+// by the well-known account key of the azurite emulator.
+func TestAzblob_SharedKeyStringToSign(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet,
+		"https://myaccount.blob.core.windows.net/mycontainer?restype=container&comp=metadata&timeout=20", nil)
+	require.NoError(t, err)
+	req.Header.Set("x-ms-date", "Fri, 26 Jun 2015 23:39:12 GMT")
+	req.Header.Set("x-ms-version", "2015-02-21")
+	stringToSign := azblobSharedKeyStringToSign("myaccount", req)
+	assert.Equal(t, "GET\n\n\n\n\n\n\n\n\n\n\n\nx-ms-date:Fri, 26 Jun 2015 23:39:12 GMT\nx-ms-version:2015-02-21\n"+
+		"/myaccount/mycontainer\ncomp:metadata\nrestype:container\ntimeout:20", stringToSign)
+
+	key, err := base64.StdEncoding.DecodeString(
+		"Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw==")
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+	assert.Equal(t, "1u9lui2jDxj0+fpbHjQ5m5NnastJRSYM+PSmfi8TXx4=", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+func TestAzblob_ParseEndpoint(t *testing.T) {
+	cases := []struct {
+		name              string
+		bucketURL         string
+		wantedEndpoint    string
+		wantedAccount     string
+		wantedContainer   string
+		wantedErrNotEmpty bool
+	}{
+		{
+			name:            "azure style",
+			bucketURL:       "https://myaccount.blob.core.windows.net/mycontainer",
+			wantedEndpoint:  "https://myaccount.blob.core.windows.net/mycontainer",
+			wantedAccount:   "myaccount",
+			wantedContainer: "mycontainer",
+		},
+		{
+			name:            "emulator style",
+			bucketURL:       "http://127.0.0.1:10000/devstoreaccount1/mycontainer/",
+			wantedEndpoint:  "http://127.0.0.1:10000/devstoreaccount1/mycontainer",
+			wantedAccount:   "devstoreaccount1",
+			wantedContainer: "mycontainer",
+		},
+		{
+			name:              "no container",
+			bucketURL:         "https://myaccount.blob.core.windows.net",
+			wantedErrNotEmpty: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, account, container, err := parseAzblobEndpoint(tt.bucketURL)
+			if tt.wantedErrNotEmpty {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantedEndpoint, endpoint)
+			assert.Equal(t, tt.wantedAccount, account)
+			assert.Equal(t, tt.wantedContainer, container)
+		})
+	}
+}
+
+func TestNewAzblobStore(t *testing.T) {
+	t.Setenv(AzureAccountKey, base64.StdEncoding.EncodeToString(mockAzblobKey))
+	store, err := newAzblobStore(ObjectStorageConfig{
+		BucketURL: "https://myaccount.blob.core.windows.net/mycontainer",
+		IAMType:   AKSKIAMType,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "azblob://myaccount/mycontainer/", store.String())
+
+	t.Setenv(AzureClientID, "mockClientID")
+	store, err = newAzblobStore(ObjectStorageConfig{
+		BucketURL: "https://myaccount.blob.core.windows.net/mycontainer",
+		IAMType:   SAIAMType,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "azblob://myaccount/mycontainer/", store.String())
+
+	_, err = newAzblobStore(ObjectStorageConfig{
+		BucketURL: "https://myaccount.blob.core.windows.net/mycontainer",
+		IAMType:   "unknown",
+	})
+	assert.NotNil(t, err)
+}
+
+func TestAzblob_Bucket(t *testing.T) {
+	fake := newFakeAzblobServer()
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+	store := newAzblobTestStore(t, server, base64.StdEncoding.EncodeToString(mockAzblobKey), 0)
+	assert.Equal(t, ErrNoSuchBucket, store.HeadBucket(context.TODO()))
+	assert.Nil(t, store.CreateBucket(context.TODO()))
+	// creating the existed container succeeds
+	assert.Nil(t, store.CreateBucket(context.TODO()))
+	assert.Nil(t, store.HeadBucket(context.TODO()))
+
+	store = newAzblobTestStore(t, server, base64.StdEncoding.EncodeToString([]byte("wrongKey")), 0)
+	err := store.HeadBucket(context.TODO())
+	var respErr *azcore.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	assert.Equal(t, http.StatusForbidden, respErr.StatusCode)
+}
+
+func TestAzblob_Object(t *testing.T) {
+	store, _ := setupAzblobTest(t)
+	require.NoError(t, store.CreateBucket(context.TODO()))
+	key := "mock/object key"
+	require.NoError(t, store.PutObject(context.TODO(), key, strings.NewReader("azblob get")))
+
+	cases := []struct {
+		name   string
+		offset int64
+		limit  int64
+		wanted string
+	}{
+		{name: "full", offset: 0, limit: -1, wanted: "azblob get"},
+		{name: "range", offset: 2, limit: 4, wanted: "blob"},
+		{name: "from offset", offset: 7, limit: 0, wanted: "get"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := store.GetObject(context.TODO(), key, tt.offset, tt.limit)
+			require.NoError(t, err)
+			result, err := io.ReadAll(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wanted, string(result))
+		})
+	}
+
+	obj, err := store.HeadObject(context.TODO(), key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("azblob get")), obj.Size())
+	assert.Equal(t, mockModifiedTime, obj.ModTime().UTC())
+
+	assert.Nil(t, store.DeleteObject(context.TODO(), key))
+	// deleting the non-existed object succeeds
+	assert.Nil(t, store.DeleteObject(context.TODO(), key))
+	_, err = store.HeadObject(context.TODO(), key)
+	assert.Equal(t, os.ErrNotExist, err)
+	_, err = store.GetObject(context.TODO(), key, 0, -1)
+	assert.True(t, IsNoSuchObject(err))
+	var respErr *azcore.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+}
+
+func TestAzblob_GetChecksumMismatch(t *testing.T) {
+	store, fake := setupAzblobTest(t)
+	require.NoError(t, store.CreateBucket(context.TODO()))
+	require.NoError(t, store.PutObject(context.TODO(), mockKey, strings.NewReader("azblob get")))
+	fake.blobs[mockKey] = []byte("corrupted!")
+	data, err := store.GetObject(context.TODO(), mockKey, 0, -1)
+	require.NoError(t, err)
+	_, err = io.ReadAll(data)
+	assert.NotNil(t, err)
+}
+
+func TestAzblob_ListObjects(t *testing.T) {
+	store, fake := setupAzblobTest(t)
+	require.NoError(t, store.CreateBucket(context.TODO()))
+	for _, key := range []string{"a/1", "a/2", "b/1", "b/2", "c"} {
+		require.NoError(t, store.PutObject(context.TODO(), key, strings.NewReader(key)))
+	}
+	fake.pageSize = 2
+
+	cases := []struct {
+		name      string
+		prefix    string
+		marker    string
+		delimiter string
+		limit     int64
+		wanted    []string
+	}{
+		{name: "all", limit: 10, wanted: []string{"a/1", "a/2", "b/1", "b/2", "c"}},
+		{name: "limit", limit: 3, wanted: []string{"a/1", "a/2", "b/1"}},
+		{name: "prefix", prefix: "b/", limit: 10, wanted: []string{"b/1", "b/2"}},
+		{name: "marker", marker: "a/2", limit: 2, wanted: []string{"b/1", "b/2"}},
+		{name: "delimiter", delimiter: "/", limit: 10, wanted: []string{"a/", "b/", "c"}},
+		{name: "delimiter and marker", marker: "a/", delimiter: "/", limit: 10, wanted: []string{"b/", "c"}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			objs, err := store.ListObjects(context.TODO(), tt.prefix, tt.marker, tt.delimiter, tt.limit)
+			require.NoError(t, err)
+			keys := make([]string, 0, len(objs))
+			for _, o := range objs {
+				keys = append(keys, o.Key())
+			}
+			assert.Equal(t, tt.wanted, keys)
+		})
+	}
+}
+
+func TestAzblob_ListAllObjects(t *testing.T) {
+	store, fake := setupAzblobTest(t)
+	require.NoError(t, store.CreateBucket(context.TODO()))
+	var wanted []string
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("mock/%02d", i)
+		require.NoError(t, store.PutObject(context.TODO(), key, strings.NewReader(key)))
+		if i > 3 {
+			wanted = append(wanted, key)
+		}
+	}
+	fake.pageSize = 3
+
+	objs, err := store.ListAllObjects(context.TODO(), "mock/", "mock/03")
+	require.NoError(t, err)
+	var keys []string
+	for o := range objs {
+		require.NotNil(t, o)
+		keys = append(keys, o.Key())
+	}
+	assert.Equal(t, wanted, keys)
+}
+
+func TestAzblob_ListObjectsByMarker(t *testing.T) {
+	store, fake := setupAzblobTest(t)
+	require.NoError(t, store.CreateBucket(context.TODO()))
+	var wanted []string
+	for i := 0; i < 7; i++ {
+		key := fmt.Sprintf("mock/%02d", i)
+		require.NoError(t, store.PutObject(context.TODO(), key, strings.NewReader(key)))
+		wanted = append(wanted, key)
+	}
+	fake.pageSize = 2
+
+	var keys []string
+	marker := ""
+	for {
+		fake.listMarkers = nil
+		objs, err := store.ListObjects(context.TODO(), "mock/", marker, "", 3)
+		require.NoError(t, err)
+		if marker != "" {
+			// the next page is listed from the cached marker of azure blob, not from the prefix
+			assert.NotEmpty(t, fake.listMarkers[0])
+		}
+		for _, o := range objs {
+			keys = append(keys, o.Key())
+		}
+		if len(objs) < 3 {
+			break
+		}
+		marker = objs[len(objs)-1].Key()
+	}
+	assert.Equal(t, wanted, keys)
+	assert.Empty(t, store.markers)
+}
+
+func TestAzblob_Retry(t *testing.T) {
+	fake := newFakeAzblobServer()
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+	store := newAzblobTestStore(t, server, base64.StdEncoding.EncodeToString(mockAzblobKey), 2)
+	fake.failures = 2
+	assert.Nil(t, store.CreateBucket(context.TODO()))
+
+	fake.failures = 3
+	err := store.HeadBucket(context.TODO())
+	var respErr *azcore.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	assert.Equal(t, http.StatusInternalServerError, respErr.StatusCode)
+}
+
+// fakeAzureTokenCredential issues the mock token of managed identity and counts the token requests.
+type fakeAzureTokenCredential struct {
+	requests int
+	scopes   []string
+}
+
+func (c *fakeAzureTokenCredential) GetToken(_ context.Context, options policy.TokenRequestOptions) (
+	azcore.AccessToken, error) {
+	c.requests++
+	c.scopes = options.Scopes
+	return azcore.AccessToken{Token: mockAzblobToken, ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestAzblob_ManagedIdentity(t *testing.T) {
+	fake := newFakeAzblobServer()
+	fake.token = mockAzblobToken
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+	cred := &fakeAzureTokenCredential{}
+	client, err := container.NewClient(server.URL+"/"+mockAzblobAccount+"/"+mockBucket, cred,
+		newAzblobTestClientOptions(server, 0))
+	require.NoError(t, err)
+	store := newAzblobStoreWithClient(mockAzblobAccount, mockBucket, client)
+
+	assert.Nil(t, store.CreateBucket(context.TODO()))
+	assert.Nil(t, store.HeadBucket(context.TODO()))
+	// the token of azure storage is cached before it expires
+	assert.Equal(t, 1, cred.requests)
+	assert.Equal(t, []string{"https://storage.azure.com/.default"}, cred.scopes)
+}
@@ -16,6 +16,10 @@ const (
 	AliyunfsStore = "aliyunfs"
 	// MemoryStore defines storage type for memory
 	MemoryStore = "memory"
+	// AzblobStore defines storage type for azure blob storage
+	AzblobStore = "azblob"
+	// GCSStore defines storage type for google cloud storage
+	GCSStore = "gcs"
 )
 
 // piece store storage config and environment constants
@@ -61,6 +65,20 @@ const (
 	// MinioSessionToken defines env variable name for minio session token
 	MinioSessionToken = "MINIO_SESSION_TOKEN"
 
+	// AzureAccountName defines env variable name for azure storage account name
+	AzureAccountName = "AZURE_STORAGE_ACCOUNT"
+	// AzureAccountKey defines env variable name for azure storage account key
+	AzureAccountKey = "AZURE_STORAGE_KEY"
+	// AzureClientID defines env variable name for the client id of azure user-assigned managed identity
+	AzureClientID = "AZURE_CLIENT_ID"
+
+	// GCSCredentialsFile defines env variable name for the json key file of google service account
+	GCSCredentialsFile = "GOOGLE_APPLICATION_CREDENTIALS"
+	// GCSProjectID defines env variable name for google cloud project id which is used to create bucket
+	GCSProjectID = "GOOGLE_CLOUD_PROJECT"
+	// GCSMetadataHost defines env variable name for the host of gce metadata server
+	GCSMetadataHost = "GCE_METADATA_HOST"
+
 	// B2AccessKey defines env variable name for minio access key
 	B2AccessKey = "B2_ACCESS_KEY"
 	// B2SecretKey defines env variable name for minio secret key
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"errors"
+	"os"
 )
 
 // piece store errors
@@ -18,4 +19,16 @@ var (
 	ErrUnsupportedMethod = errors.New("unsupported method")
 	// ErrNoPermissionAccessBucket defines deny access bucket error
 	ErrNoPermissionAccessBucket = errors.New("deny access bucket")
+	// ErrTierCapacityExceeded defines the error that the cold tier capacity is exceeded
+	ErrTierCapacityExceeded = errors.New("tier capacity exceeded")
+	// ErrInvalidTierConfig defines invalid tier config error
+	ErrInvalidTierConfig = errors.New("invalid tier config")
+	// ErrTierHotPathInUse defines the error that the tier hot path is locked by another process
+	ErrTierHotPathInUse = errors.New("tier hot path is in use by another process")
 )
+
+// IsNoSuchObject returns whether the error is returned by reading the object that does not exist, the
+// file system based stores return os.ErrNotExist and the others return ErrNoSuchObject.
+func IsNoSuchObject(err error) bool {
+	return errors.Is(err, ErrNoSuchObject) || errors.Is(err, os.ErrNotExist)
+}
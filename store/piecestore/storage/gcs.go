@@ -0,0 +1,318 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/googleapis/gax-go/v2"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+var _ ObjectStorage = &gcsStore{}
+
+const (
+	// gcsDefaultEndpoint defines the endpoint of google cloud storage JSON API
+	gcsDefaultEndpoint = "https://storage.googleapis.com"
+	// gcsMaxResults defines the max number of objects returned by one list request
+	gcsMaxResults = 1000
+	// gcsResumableChunkSize defines the chunk size of resumable upload, the objects larger than it are
+	// uploaded by resumable upload, it must be a multiple of 256 KiB
+	gcsResumableChunkSize = 8 * 1024 * 1024
+	// gcsScope defines the oauth2 scope to read and write google cloud storage
+	gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+type gcsStore struct {
+	bucketName    string
+	projectID     string
+	client        *storage.Client
+	maxRetries    int
+	minRetryDelay time.Duration
+}
+
+func newGCSStore(cfg ObjectStorageConfig) (ObjectStorage, error) {
+	endpoint, bucketName, err := parseGCSEndpoint(cfg.BucketURL)
+	if err != nil {
+		log.Errorw("failed to parse gcs endpoint", "error", err)
+		return nil, err
+	}
+	projectID := os.Getenv(GCSProjectID)
+
+	// If IAM type is AKSK, you must provide the service account json key file
+	// If IAM type is SA, the workload identity of the pod(or the service account of the vm) is used, the token
+	// is got from gce metadata server, whose host can be overridden by GCE_METADATA_HOST
+	var tokenSource oauth2.TokenSource
+	switch cfg.IAMType {
+	case AKSKIAMType:
+		keyFile, ok := os.LookupEnv(GCSCredentialsFile)
+		if !ok {
+			return nil, fmt.Errorf("failed to read gcs service account key file")
+		}
+		keyJSON, err := os.ReadFile(keyFile)
+		if err != nil {
+			log.Errorw("failed to read gcs service account key file", "error", err)
+			return nil, err
+		}
+		creds, err := google.CredentialsFromJSON(context.Background(), keyJSON, gcsScope)
+		if err != nil {
+			log.Errorw("failed to load gcs service account key", "error", err)
+			return nil, err
+		}
+		if projectID == "" {
+			projectID = creds.ProjectID
+		}
+		tokenSource = creds.TokenSource
+		log.Debug("use service account key to access gcs")
+	case SAIAMType:
+		tokenSource = google.ComputeTokenSource("", gcsScope)
+		log.Debug("use workload identity to access gcs")
+	default:
+		log.Errorf("unknown IAM type: %s", cfg.IAMType)
+		return nil, fmt.Errorf("unknown IAM type: %s", cfg.IAMType)
+	}
+	client, err := storage.NewClient(context.Background(),
+		option.WithHTTPClient(&http.Client{Transport: &oauth2.Transport{
+			Source: tokenSource,
+			Base:   getHTTPClient(cfg.TLSInsecureSkipVerify).Transport,
+		}}),
+		option.WithEndpoint(endpoint+"/storage/v1/"),
+		storage.WithJSONReads())
+	if err != nil {
+		log.Errorw("failed to new gcs client", "error", err)
+		return nil, err
+	}
+	log.Infow("new gcs store succeeds", "bucket", bucketName)
+	return &gcsStore{
+		bucketName:    bucketName,
+		projectID:     projectID,
+		client:        client,
+		maxRetries:    cfg.MaxRetries,
+		minRetryDelay: time.Duration(cfg.MinRetryDelay),
+	}, nil
+}
+
+// parseGCSEndpoint parses the bucket url which supports two styles:
+// GCS: gs://<bucket> or https://storage.googleapis.com/<bucket>
+// Emulator(e.g. fake-gcs-server): http://127.0.0.1:4443/<bucket>
+func parseGCSEndpoint(bucketURL string) (string, string, error) {
+	uri, err := url.ParseRequestURI(strings.Trim(bucketURL, "/"))
+	if err != nil {
+		return "", "", err
+	}
+	if uri.Scheme == "gs" {
+		if uri.Host == "" {
+			return "", "", fmt.Errorf("invalid gcs bucket url: %s", bucketURL)
+		}
+		return gcsDefaultEndpoint, uri.Host, nil
+	}
+	bucketName := strings.Trim(uri.Path, "/")
+	if bucketName == "" || strings.Contains(bucketName, "/") {
+		return "", "", fmt.Errorf("invalid gcs bucket url: %s", bucketURL)
+	}
+	return fmt.Sprintf("%s://%s", uri.Scheme, uri.Host), bucketName, nil
+}
+
+func (s *gcsStore) String() string {
+	return fmt.Sprintf("gs://%s/", s.bucketName)
+}
+
+// retryOptions returns the retry options of one operation, the transient errors are retried at most
+// maxRetries times, the retry delay starts from minRetryDelay and is doubled for each retry.
+func (s *gcsStore) retryOptions() []storage.RetryOption {
+	delay := s.minRetryDelay
+	if delay <= 0 {
+		delay = defaultHTTPRetryDelay
+	}
+	retries := 0
+	return []storage.RetryOption{
+		storage.WithPolicy(storage.RetryAlways),
+		storage.WithBackoff(gax.Backoff{Initial: delay, Multiplier: 2}),
+		storage.WithErrorFunc(func(err error) bool {
+			if !storage.ShouldRetry(err) || retries >= s.maxRetries {
+				return false
+			}
+			retries++
+			log.Errorw("retryable gcs error", "error", err)
+			return true
+		}),
+	}
+}
+
+func (s *gcsStore) bucket() *storage.BucketHandle {
+	return s.client.Bucket(s.bucketName).Retryer(s.retryOptions()...)
+}
+
+func (s *gcsStore) object(key string) *storage.ObjectHandle {
+	return s.client.Bucket(s.bucketName).Object(key).Retryer(s.retryOptions()...)
+}
+
+func (s *gcsStore) CreateBucket(ctx context.Context) error {
+	if s.projectID == "" {
+		return fmt.Errorf("failed to create gcs bucket without project id")
+	}
+	err := s.bucket().Create(ctx, s.projectID, nil)
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict {
+		log.Errorw("gcs failed to create bucket", "error", err)
+		return nil
+	}
+	return err
+}
+
+// GetObject reads the object from the offset, the crc32c checksum is verified by the sdk if the whole
+// object is read.
+func (s *gcsStore) GetObject(ctx context.Context, key string, offset, limit int64) (io.ReadCloser, error) {
+	length := int64(-1)
+	if limit > 0 {
+		length = limit
+	}
+	reader, err := s.object(key).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			err = fmt.Errorf("%w: %w", ErrNoSuchObject, err)
+		}
+		log.Errorw("gcs failed to get object", "error", err)
+		return nil, err
+	}
+	return reader, nil
+}
+
+// PutObject uploads the object in one request, or by resumable upload if the object is large, the
+// crc32c checksum is verified by gcs.
+func (s *gcsStore) PutObject(ctx context.Context, key string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	w := s.object(key).NewWriter(ctx)
+	w.ContentType = OctetStream
+	w.ChunkSize = gcsResumableChunkSize
+	w.CRC32C = crc32.Checksum(data, crc32c)
+	w.SendCRC32C = true
+	if _, err = w.Write(data); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (s *gcsStore) DeleteObject(ctx context.Context, key string) error {
+	err := s.object(key).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		log.Errorw("gcs failed to delete object", "error", err)
+		return nil
+	}
+	return err
+}
+
+func (s *gcsStore) HeadBucket(ctx context.Context) error {
+	if _, err := s.bucket().Attrs(ctx); err != nil {
+		log.Errorw("gcs failed to head bucket", "error", err)
+		if errors.Is(err, storage.ErrBucketNotExist) {
+			return ErrNoSuchBucket
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *gcsStore) HeadObject(ctx context.Context, key string) (Object, error) {
+	attrs, err := s.object(key).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			err = os.ErrNotExist
+		}
+		log.Errorw("gcs failed to head object", "error", err)
+		return nil, err
+	}
+	return gcsObject(attrs), nil
+}
+
+// ListObjects lists the objects after the marker, gcs starts listing from the marker inclusively, so
+// the marker itself is skipped.
+func (s *gcsStore) ListObjects(ctx context.Context, prefix, marker, delimiter string, limit int64) ([]Object, error) {
+	if limit <= 0 || limit > gcsMaxResults {
+		limit = gcsMaxResults
+	}
+	it := s.listObjects(ctx, prefix, delimiter, marker)
+	it.PageInfo().MaxSize = int(limit)
+	var objs []Object
+	for int64(len(objs)) < limit {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			log.Errorw("gcs failed to list objects", "error", err)
+			return nil, err
+		}
+		if o := gcsObject(attrs); o.Key() > marker {
+			objs = append(objs, o)
+		}
+	}
+	return objs, nil
+}
+
+// ListAllObjects streams all the objects after the marker, a nil object is sent to the channel if
+// the listing fails halfway.
+func (s *gcsStore) ListAllObjects(ctx context.Context, prefix, marker string) (<-chan Object, error) {
+	it := s.listObjects(ctx, prefix, "", marker)
+	it.PageInfo().MaxSize = gcsMaxResults
+	// list the first page synchronously to return the error of listing
+	first, err := it.Next()
+	if err != nil && err != iterator.Done {
+		log.Errorw("gcs failed to list all objects", "error", err)
+		return nil, err
+	}
+	objs := make(chan Object, gcsMaxResults)
+	go func() {
+		defer close(objs)
+		for attrs := first; err != iterator.Done; attrs, err = it.Next() {
+			if err != nil {
+				log.Errorw("gcs failed to list all objects", "error", err)
+				objs <- nil
+				return
+			}
+			o := gcsObject(attrs)
+			if o.Key() <= marker {
+				continue
+			}
+			select {
+			case objs <- o:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return objs, nil
+}
+
+func (s *gcsStore) listObjects(ctx context.Context, prefix, delimiter, startOffset string) *storage.ObjectIterator {
+	query := &storage.Query{Prefix: prefix, Delimiter: delimiter, StartOffset: startOffset}
+	_ = query.SetAttrSelection([]string{"Name", "Size", "Updated"})
+	return s.bucket().Objects(ctx, query)
+}
+
+// gcsObject converts the object attrs to the object, the attrs with the prefix only is the directory
+// listed by the delimiter.
+func gcsObject(attrs *storage.ObjectAttrs) Object {
+	if attrs.Prefix != "" {
+		return &object{attrs.Prefix, 0, time.Unix(0, 0), true}
+	}
+	return &object{attrs.Name, attrs.Size, attrs.Updated, strings.HasSuffix(attrs.Name, "/")}
+}
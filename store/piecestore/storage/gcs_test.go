@@ -0,0 +1,546 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+)
+
+const mockGCSProject = "mockProject"
+
+// fakeGCSServer is the http fake of google cloud storage JSON API, it issues the token for the jwt
+// signed by the service account key, and pages the list results by pageSize.
+type fakeGCSServer struct {
+	mu        sync.Mutex
+	publicKey *rsa.PublicKey
+	tokenURI  string
+	tokens    int
+	buckets   map[string]map[string][]byte
+	sessions  map[string]*fakeGCSSession
+	pageSize  int
+	failures  int
+}
+
+type fakeGCSSession struct {
+	bucket string
+	name   string
+	crc32c string
+	data   []byte
+}
+
+// fakeGCSObject is the object resource of google cloud storage JSON API.
+type fakeGCSObject struct {
+	Bucket  string    `json:"bucket"`
+	Name    string    `json:"name"`
+	Size    string    `json:"size"`
+	Updated time.Time `json:"updated"`
+}
+
+func newFakeGCSServer(publicKey *rsa.PublicKey) *fakeGCSServer {
+	return &fakeGCSServer{
+		publicKey: publicKey,
+		buckets:   map[string]map[string][]byte{},
+		sessions:  map[string]*fakeGCSSession{},
+		pageSize:  gcsMaxResults,
+	}
+}
+
+func (f *fakeGCSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path == "/token" {
+		f.serveToken(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer mockToken" {
+		writeFakeGCSError(w, http.StatusUnauthorized)
+		return
+	}
+	if f.failures > 0 {
+		f.failures--
+		writeFakeGCSError(w, http.StatusServiceUnavailable)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), "/"), "/")
+	switch {
+	case r.URL.Path == "/storage/v1/b" && r.Method == http.MethodPost:
+		var bucket struct {
+			Name string `json:"name"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&bucket)
+		if _, ok := f.buckets[bucket.Name]; ok || r.URL.Query().Get("project") != mockGCSProject {
+			writeFakeGCSError(w, http.StatusConflict)
+			return
+		}
+		f.buckets[bucket.Name] = map[string][]byte{}
+		_ = json.NewEncoder(w).Encode(bucket)
+	case len(parts) == 2 && parts[0] == "session":
+		f.serveResumable(w, r, f.sessions[parts[1]])
+	case len(parts) == 6 && parts[0] == "upload":
+		f.serveUpload(w, r, parts[4])
+	case len(parts) >= 4 && parts[0] == "storage":
+		objects, ok := f.buckets[parts[3]]
+		if !ok {
+			writeFakeGCSError(w, http.StatusNotFound)
+			return
+		}
+		if len(parts) == 4 {
+			_, _ = fmt.Fprintf(w, `{"name":%q}`, parts[3])
+		} else if len(parts) == 5 {
+			f.list(w, r, parts[3], objects)
+		} else {
+			name, _ := url.PathUnescape(parts[5])
+			f.serveObject(w, r, parts[3], objects, name)
+		}
+	default:
+		writeFakeGCSError(w, http.StatusNotFound)
+	}
+}
+
+// serveToken verifies the jwt independently of the sdk, following RFC 7523 and the oauth2 flow of google
+// service account, and issues the token.
+func (f *fakeGCSServer) serveToken(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	parts := strings.Split(r.PostForm.Get("assertion"), ".")
+	if len(parts) != 3 || r.PostForm.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+		writeFakeGCSError(w, http.StatusBadRequest)
+		return
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	var claims struct {
+		Iss   string `json:"iss"`
+		Scope string `json:"scope"`
+		Aud   string `json:"aud"`
+		Iat   int64  `json:"iat"`
+		Exp   int64  `json:"exp"`
+	}
+	headerJSON, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if json.Unmarshal(headerJSON, &header) != nil || json.Unmarshal(claimsJSON, &claims) != nil ||
+		header.Alg != "RS256" || header.Kid != "mockKeyID" || claims.Iss != "mock@mockProject.iam.gserviceaccount.com" ||
+		claims.Scope != gcsScope || claims.Aud != f.tokenURI || claims.Exp <= claims.Iat {
+		writeFakeGCSError(w, http.StatusBadRequest)
+		return
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(f.publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		writeFakeGCSError(w, http.StatusUnauthorized)
+		return
+	}
+	f.tokens++
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprint(w, `{"access_token":"mockToken","expires_in":3600,"token_type":"Bearer"}`)
+}
+
+func (f *fakeGCSServer) serveUpload(w http.ResponseWriter, r *http.Request, bucket string) {
+	objects, ok := f.buckets[bucket]
+	if !ok {
+		writeFakeGCSError(w, http.StatusNotFound)
+		return
+	}
+	var metadata struct {
+		Name   string `json:"name"`
+		CRC32C string `json:"crc32c"`
+	}
+	switch r.URL.Query().Get("uploadType") {
+	case "multipart":
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		part, _ := reader.NextPart()
+		_ = json.NewDecoder(part).Decode(&metadata)
+		part, _ = reader.NextPart()
+		data, _ := io.ReadAll(part)
+		if metadata.CRC32C != fakeGCSChecksum(data) {
+			writeFakeGCSError(w, http.StatusBadRequest)
+			return
+		}
+		objects[metadata.Name] = data
+		f.writeObject(w, bucket, metadata.Name, data)
+	case "resumable":
+		_ = json.NewDecoder(r.Body).Decode(&metadata)
+		id := strconv.Itoa(len(f.sessions))
+		f.sessions[id] = &fakeGCSSession{bucket: bucket, name: metadata.Name, crc32c: metadata.CRC32C}
+		w.Header().Set("Location", "http://"+r.Host+"/session/"+id)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// serveResumable appends the chunk to the session, the incomplete upload is responded by the status
+// override header since the sdk asks not to use 308.
+func (f *fakeGCSServer) serveResumable(w http.ResponseWriter, r *http.Request, session *fakeGCSSession) {
+	var start, end int
+	bounds, total, _ := strings.Cut(strings.TrimPrefix(r.Header.Get("Content-Range"), "bytes "), "/")
+	if bounds != "*" {
+		_, _ = fmt.Sscanf(bounds, "%d-%d", &start, &end)
+		if start != len(session.data) {
+			writeFakeGCSError(w, http.StatusBadRequest)
+			return
+		}
+	}
+	data, _ := io.ReadAll(r.Body)
+	session.data = append(session.data, data...)
+	if total == "*" {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(session.data)-1))
+		w.Header().Set("X-Http-Status-Code-Override", "308")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if total != strconv.Itoa(len(session.data)) || session.crc32c != fakeGCSChecksum(session.data) {
+		writeFakeGCSError(w, http.StatusBadRequest)
+		return
+	}
+	f.buckets[session.bucket][session.name] = session.data
+	f.writeObject(w, session.bucket, session.name, session.data)
+}
+
+func (f *fakeGCSServer) serveObject(w http.ResponseWriter, r *http.Request, bucket string, objects map[string][]byte,
+	name string) {
+	data, ok := objects[name]
+	if !ok {
+		writeFakeGCSError(w, http.StatusNotFound)
+		return
+	}
+	switch {
+	case r.Method == http.MethodDelete:
+		delete(objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Query().Get("alt") == "media":
+		w.Header().Set("x-goog-hash", "crc32c="+fakeGCSChecksum(data))
+		w.Header().Add("x-goog-hash", "md5=mockMD5")
+		w.Header().Set("X-Goog-Generation", "1")
+		status := http.StatusOK
+		if rng := r.Header.Get("Range"); rng != "" {
+			var start, end int
+			bounds := strings.Split(strings.TrimPrefix(rng, "bytes="), "-")
+			start, _ = strconv.Atoi(bounds[0])
+			end = len(data) - 1
+			if bounds[1] != "" {
+				end, _ = strconv.Atoi(bounds[1])
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+			data = data[start : end+1]
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		_, _ = w.Write(data)
+	default:
+		f.writeObject(w, bucket, name, data)
+	}
+}
+
+func (f *fakeGCSServer) writeObject(w http.ResponseWriter, bucket, name string, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(&fakeGCSObject{Bucket: bucket, Name: name, Size: strconv.Itoa(len(data)),
+		Updated: mockModifiedTime})
+}
+
+// list pages the sorted objects from startOffset, the page token is the opaque index of the next object.
+func (f *fakeGCSServer) list(w http.ResponseWriter, r *http.Request, bucket string, objects map[string][]byte) {
+	query := r.URL.Query()
+	prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		if name >= query.Get("startOffset") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	i, _ := strconv.Atoi(query.Get("pageToken"))
+	max, _ := strconv.Atoi(query.Get("maxResults"))
+	if max <= 0 || max > f.pageSize {
+		max = f.pageSize
+	}
+	var result struct {
+		Items         []*fakeGCSObject `json:"items"`
+		Prefixes      []string         `json:"prefixes"`
+		NextPageToken string           `json:"nextPageToken"`
+	}
+	for count := 0; i < len(names) && count < max; {
+		name := names[i]
+		i++
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		count++
+		if idx := strings.Index(name[len(prefix):], delimiter); delimiter != "" && idx >= 0 {
+			p := name[:len(prefix)+idx+len(delimiter)]
+			result.Prefixes = append(result.Prefixes, p)
+			for i < len(names) && strings.HasPrefix(names[i], p) {
+				i++
+			}
+			continue
+		}
+		result.Items = append(result.Items, &fakeGCSObject{Bucket: bucket, Name: name,
+			Size: strconv.Itoa(len(objects[name])), Updated: mockModifiedTime})
+	}
+	if i < len(names) {
+		result.NextPageToken = strconv.Itoa(i)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+func fakeGCSChecksum(data []byte) string {
+	checksum := make([]byte, 4)
+	binary.BigEndian.PutUint32(checksum, crc32.Checksum(data, crc32c))
+	return base64.StdEncoding.EncodeToString(checksum)
+}
+
+func writeFakeGCSError(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, `{"error":{"code":%d,"message":"mock error"}}`, status)
+}
+
+// setupGCSTest starts the fake gcs server and writes the service account key file.
+func setupGCSTest(t *testing.T) (*fakeGCSServer, string, string) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	fake := newFakeGCSServer(&rsaKey.PublicKey)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	fake.tokenURI = server.URL + "/token"
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+	keyJSON, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     mockGCSProject,
+		"private_key_id": "mockKeyID",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})),
+		"client_email":   "mock@mockProject.iam.gserviceaccount.com",
+		"token_uri":      fake.tokenURI,
+	})
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(keyFile, keyJSON, 0600))
+	return fake, server.URL, keyFile
+}
+
+func newTestGCSStore(t *testing.T, endpoint, keyFile string) *gcsStore {
+	t.Setenv(GCSCredentialsFile, keyFile)
+	store, err := newGCSStore(ObjectStorageConfig{BucketURL: endpoint + "/" + mockBucket, IAMType: AKSKIAMType,
+		MinRetryDelay: int64(time.Millisecond)})
+	require.NoError(t, err)
+	return store.(*gcsStore)
+}
+
+func TestGCS_ParseEndpoint(t *testing.T) {
+	cases := []struct {
+		name              string
+		bucketURL         string
+		wantedEndpoint    string
+		wantedBucket      string
+		wantedErrNotEmpty bool
+	}{
+		{name: "gs", bucketURL: "gs://mybucket", wantedEndpoint: gcsDefaultEndpoint, wantedBucket: "mybucket"},
+		{name: "https", bucketURL: "https://storage.googleapis.com/mybucket/", wantedEndpoint: gcsDefaultEndpoint, wantedBucket: "mybucket"},
+		{name: "emulator", bucketURL: "http://127.0.0.1:4443/mybucket", wantedEndpoint: "http://127.0.0.1:4443", wantedBucket: "mybucket"},
+		{name: "no bucket", bucketURL: "https://storage.googleapis.com", wantedErrNotEmpty: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, bucket, err := parseGCSEndpoint(tt.bucketURL)
+			if tt.wantedErrNotEmpty {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantedEndpoint, endpoint)
+			assert.Equal(t, tt.wantedBucket, bucket)
+		})
+	}
+}
+
+func TestGCS_Bucket(t *testing.T) {
+	fake, endpoint, keyFile := setupGCSTest(t)
+	store := newTestGCSStore(t, endpoint, keyFile)
+	assert.Equal(t, "gs://mockBucket/", store.String())
+	assert.Equal(t, ErrNoSuchBucket, store.HeadBucket(context.TODO()))
+	assert.Nil(t, store.CreateBucket(context.TODO()))
+	// creating the existed bucket succeeds
+	assert.Nil(t, store.CreateBucket(context.TODO()))
+	assert.Nil(t, store.HeadBucket(context.TODO()))
+	// the token is cached before it expires
+	assert.Equal(t, 1, fake.tokens)
+}
+
+func TestGCS_Object(t *testing.T) {
+	_, endpoint, keyFile := setupGCSTest(t)
+	store := newTestGCSStore(t, endpoint, keyFile)
+	require.NoError(t, store.CreateBucket(context.TODO()))
+	key := "mock/object key"
+	require.NoError(t, store.PutObject(context.TODO(), key, strings.NewReader("gcs get object")))
+
+	cases := []struct {
+		name   string
+		offset int64
+		limit  int64
+		wanted string
+	}{
+		{name: "full", offset: 0, limit: -1, wanted: "gcs get object"},
+		{name: "range", offset: 4, limit: 3, wanted: "get"},
+		{name: "from offset", offset: 8, limit: 0, wanted: "object"},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := store.GetObject(context.TODO(), key, tt.offset, tt.limit)
+			require.NoError(t, err)
+			result, err := io.ReadAll(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wanted, string(result))
+		})
+	}
+
+	obj, err := store.HeadObject(context.TODO(), key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("gcs get object")), obj.Size())
+	assert.Equal(t, mockModifiedTime, obj.ModTime().UTC())
+
+	assert.Nil(t, store.DeleteObject(context.TODO(), key))
+	// deleting the non-existed object succeeds
+	assert.Nil(t, store.DeleteObject(context.TODO(), key))
+	_, err = store.HeadObject(context.TODO(), key)
+	assert.Equal(t, os.ErrNotExist, err)
+	_, err = store.GetObject(context.TODO(), key, 0, -1)
+	assert.True(t, IsNoSuchObject(err))
+	assert.ErrorIs(t, err, storage.ErrObjectNotExist)
+}
+
+func TestGCS_ResumableUpload(t *testing.T) {
+	fake, endpoint, keyFile := setupGCSTest(t)
+	store := newTestGCSStore(t, endpoint, keyFile)
+	require.NoError(t, store.CreateBucket(context.TODO()))
+
+	data := bytes.Repeat([]byte("resumable"), 2*gcsResumableChunkSize/9)
+	require.NoError(t, store.PutObject(context.TODO(), mockKey, bytes.NewReader(data)))
+	// the object larger than the chunk size is uploaded by chunks in a resumable session
+	assert.Len(t, fake.sessions, 1)
+	reader, err := store.GetObject(context.TODO(), mockKey, 0, -1)
+	require.NoError(t, err)
+	result, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, result)
+}
+
+func TestGCS_ListObjects(t *testing.T) {
+	fake, endpoint, keyFile := setupGCSTest(t)
+	store := newTestGCSStore(t, endpoint, keyFile)
+	require.NoError(t, store.CreateBucket(context.TODO()))
+	for _, key := range []string{"a/1", "a/2", "b/1", "b/2", "c"} {
+		require.NoError(t, store.PutObject(context.TODO(), key, strings.NewReader(key)))
+	}
+	fake.pageSize = 2
+
+	cases := []struct {
+		name      string
+		prefix    string
+		marker    string
+		delimiter string
+		limit     int64
+		wanted    []string
+	}{
+		{name: "all", limit: 10, wanted: []string{"a/1", "a/2", "b/1", "b/2", "c"}},
+		{name: "limit", limit: 3, wanted: []string{"a/1", "a/2", "b/1"}},
+		{name: "prefix", prefix: "b/", limit: 10, wanted: []string{"b/1", "b/2"}},
+		{name: "marker", marker: "a/2", limit: 2, wanted: []string{"b/1", "b/2"}},
+		{name: "delimiter", delimiter: "/", limit: 10, wanted: []string{"a/", "b/", "c"}},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			objs, err := store.ListObjects(context.TODO(), tt.prefix, tt.marker, tt.delimiter, tt.limit)
+			require.NoError(t, err)
+			keys := make([]string, 0, len(objs))
+			for _, o := range objs {
+				keys = append(keys, o.Key())
+			}
+			assert.Equal(t, tt.wanted, keys)
+		})
+	}
+
+	objs, err := store.ListAllObjects(context.TODO(), "", "a/2")
+	require.NoError(t, err)
+	var keys []string
+	for o := range objs {
+		require.NotNil(t, o)
+		keys = append(keys, o.Key())
+	}
+	assert.Equal(t, []string{"b/1", "b/2", "c"}, keys)
+}
+
+func TestGCS_Retry(t *testing.T) {
+	fake, endpoint, keyFile := setupGCSTest(t)
+	store := newTestGCSStore(t, endpoint, keyFile)
+	store.maxRetries = 2
+	require.NoError(t, store.CreateBucket(context.TODO()))
+	fake.failures = 2
+	assert.Nil(t, store.HeadBucket(context.TODO()))
+
+	fake.failures = 3
+	err := store.HeadBucket(context.TODO())
+	var apiErr *googleapi.Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.Code)
+}
+
+func TestGCS_Sharded(t *testing.T) {
+	fake, endpoint, keyFile := setupGCSTest(t)
+	t.Setenv(GCSCredentialsFile, keyFile)
+	store, err := NewSharded(PieceStoreConfig{
+		Shards: 3,
+		Store: ObjectStorageConfig{
+			Storage:   GCSStore,
+			BucketURL: endpoint + "/mockBucket-%d",
+			IAMType:   AKSKIAMType,
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.CreateBucket(context.TODO()))
+	for i := 0; i < 30; i++ {
+		require.NoError(t, store.PutObject(context.TODO(), fmt.Sprintf("mock-%d", i), strings.NewReader("sharded")))
+	}
+	total := 0
+	for i := 0; i < 3; i++ {
+		objects := fake.buckets[fmt.Sprintf("mockBucket-%d", i)]
+		assert.NotEmpty(t, objects)
+		total += len(objects)
+	}
+	assert.Equal(t, 30, total)
+	obj, err := store.HeadObject(context.TODO(), "mock-7")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("sharded")), obj.Size())
+}
@@ -28,6 +28,8 @@ var storageMap = map[string]StorageFn{
 	AliyunfsStore: newAliyunfsStore,
 	DiskFileStore: newDiskFileStore,
 	MemoryStore:   newMemoryStore,
+	AzblobStore:   newAzblobStore,
+	GCSStore:      newGCSStore,
 }
 
 type DefaultObjectStorage struct{}
@@ -61,3 +61,6 @@ func errHasCode(err error, code string) bool {
 	}
 	return false
 }
+
+// defaultHTTPRetryDelay defines the initial retry delay of the storages based on http API if MinRetryDelay is not set
+const defaultHTTPRetryDelay = 30 * time.Millisecond
@@ -91,6 +91,9 @@ func (s *s3Store) GetObject(ctx context.Context, key string, offset, limit int64
 	}
 	resp, err := s.api.GetObjectWithContext(ctx, params)
 	if err != nil {
+		if e, ok := err.(awserr.RequestFailure); ok && e.StatusCode() == http.StatusNotFound {
+			err = fmt.Errorf("%w: %w", ErrNoSuchObject, err)
+		}
 		log.Errorw("S3 failed to get object", "error", err)
 		return nil, err
 	}
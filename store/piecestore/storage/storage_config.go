@@ -4,6 +4,7 @@ package storage
 type PieceStoreConfig struct {
 	Shards int                 // store the blocks into N buckets by hash of key
 	Store  ObjectStorageConfig // config of object storage
+	Tier   TierConfig          // config of the hot local tier in front of object storage
 }
 
 // ObjectStorageConfig object storage config
@@ -15,3 +16,15 @@ type ObjectStorageConfig struct {
 	TLSInsecureSkipVerify bool   // whether skip the certificate verification of HTTPS requests
 	IAMType               string // IAMType is identity and access management type which contains two types: AKSKIAMType/SAIAMType
 }
+
+// TierConfig tiered piece store config, the new and recently read pieces are stored on local disk as
+// the hot tier, and written back to object storage as the cold tier
+type TierConfig struct {
+	Enable           bool   // whether enable the hot local tier
+	HotPath          string // the local directory of the hot tier data and tier state
+	HotCapacity      int64  // the capacity of the hot tier in bytes
+	ColdCapacity     int64  // the capacity of the cold tier in bytes, 0 means unlimited
+	HotMaxAgeSec     int64  // the written back pieces idle longer than this are evicted, 0 means only by capacity
+	FlushIntervalSec int64  // the interval of writing back and evicting the hot tier, default 60s
+	WriteBack        bool   // whether write back the pieces to the cold tier in background, requires the hot path exclusive to one process
+}
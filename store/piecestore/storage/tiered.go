@@ -0,0 +1,762 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+)
+
+const (
+	// tierDataDir defines the directory of hot tier data under the hot path
+	tierDataDir = "data"
+	// tierStateFile defines the file of the persisted tier state under the hot path
+	tierStateFile = "tier_state.json"
+	// tierLockFile defines the file locked by the process which owns the hot path
+	tierLockFile = "tier.lock"
+	// tierListPageSize defines the page size of listing the cold tier
+	tierListPageSize = 1000
+	// tierCountSavePages defines the number of the counted cold tier pages between persisting the tier state
+	tierCountSavePages = 100
+	// defaultTierFlushInterval defines the default interval of flushing and evicting the hot tier
+	defaultTierFlushInterval = 60 * time.Second
+)
+
+// tierEntry is the piece stored in the hot tier, the dirty piece has not been written back to the
+// cold tier yet.
+type tierEntry struct {
+	Key        string `json:"key"`
+	Size       int64  `json:"size"`
+	ColdSize   int64  `json:"cold_size"`
+	AccessTime int64  `json:"access_time"`
+	Dirty      bool   `json:"dirty"`
+	version    uint64
+}
+
+// tierState is the persisted tier state. The pieces existing in the cold tier before the tier is enabled
+// are counted page by page in background, the pieces after the count marker are not counted yet, and the
+// count skip pieces after the marker have been counted by the writes during counting.
+type tierState struct {
+	ColdUsed        int64            `json:"cold_used"`
+	ColdCounting    bool             `json:"cold_counting,omitempty"`
+	ColdCountMarker string           `json:"cold_count_marker,omitempty"`
+	ColdCountSkip   map[string]int64 `json:"cold_count_skip,omitempty"`
+	Entries         []*tierEntry     `json:"entries"`
+}
+
+// tiered stores the new and recently read pieces on local disk as the hot tier, and writes back the
+// pieces to the object storage as the cold tier in background. The pieces are evicted from the hot
+// tier by capacity and LRU age after they are written back.
+//
+// The hot tier is owned by one process, which locks the hot path. The pieces are written through to the
+// cold tier by default, and the other processes sharing the hot path read the cold tier directly. The
+// pieces written back in background are invisible to the other processes before they are flushed, so
+// WriteBack requires the hot path to be exclusive to the process.
+type tiered struct {
+	hot       ObjectStorage
+	cold      ObjectStorage
+	cfg       TierConfig
+	hotRoot   string
+	statePath string
+	lock      *os.File
+
+	mux             sync.Mutex
+	entries         map[string]*tierEntry
+	hotUsed         int64
+	coldUsed        int64
+	coldCounting    bool
+	coldCountMarker string
+	coldCountSkip   map[string]int64
+	version         uint64
+
+	cycleMux sync.Mutex
+	notifyCh chan struct{}
+}
+
+// NewTiered returns the tiered object storage in front of the cold object storage, the tier state is
+// loaded from the hot path and the files in the hot tier not recorded in the state are treated as dirty.
+// The cold object storage is returned if the hot path is locked by another process in the write through
+// mode, and ErrTierHotPathInUse is returned in the write back mode.
+func NewTiered(cfg TierConfig, cold ObjectStorage) (ObjectStorage, error) {
+	t, err := newTiered(cfg, cold)
+	if errors.Is(err, ErrTierHotPathInUse) && !cfg.WriteBack {
+		log.Warnw("tier hot path is in use by another process, read the cold tier directly", "hot_path", cfg.HotPath)
+		return cold, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	go t.loop()
+	go t.countCold(context.Background())
+	log.Infow("new tiered store succeeds", "hot_path", cfg.HotPath, "hot_used", t.hotUsed, "entries", len(t.entries))
+	return t, nil
+}
+
+func newTiered(cfg TierConfig, cold ObjectStorage) (*tiered, error) {
+	if cfg.HotPath == "" || cfg.HotCapacity <= 0 {
+		return nil, ErrInvalidTierConfig
+	}
+	hotRoot := filepath.Join(cfg.HotPath, tierDataDir)
+	hot, err := newDiskFileStore(ObjectStorageConfig{BucketURL: hotRoot + dirSuffix})
+	if err != nil {
+		return nil, err
+	}
+	if err = hot.CreateBucket(context.Background()); err != nil {
+		return nil, err
+	}
+	lock, err := lockTierHotPath(filepath.Join(cfg.HotPath, tierLockFile))
+	if err != nil {
+		log.Errorw("failed to lock tier hot path", "hot_path", cfg.HotPath, "error", err)
+		return nil, err
+	}
+	t := &tiered{
+		hot:           hot,
+		cold:          cold,
+		cfg:           cfg,
+		hotRoot:       hotRoot,
+		statePath:     filepath.Join(cfg.HotPath, tierStateFile),
+		lock:          lock,
+		entries:       make(map[string]*tierEntry),
+		coldCountSkip: make(map[string]int64),
+		notifyCh:      make(chan struct{}, 1),
+	}
+	if err = t.loadState(context.Background()); err != nil {
+		_ = lock.Close()
+		log.Errorw("failed to load tier state", "error", err)
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *tiered) loadState(ctx context.Context) error {
+	state := &tierState{}
+	data, err := os.ReadFile(t.statePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, state); err != nil {
+			return err
+		}
+	}
+	persisted := make(map[string]*tierEntry, len(state.Entries))
+	for _, e := range state.Entries {
+		persisted[e.Key] = e
+	}
+	t.coldUsed = state.ColdUsed
+	t.coldCounting = state.ColdCounting
+	t.coldCountMarker = state.ColdCountMarker
+	for key, size := range state.ColdCountSkip {
+		t.coldCountSkip[key] = size
+	}
+	if os.IsNotExist(err) && t.cfg.ColdCapacity > 0 {
+		// the tier is enabled on an existing store, the pieces in the cold tier are counted in background
+		t.coldCounting = true
+	}
+
+	return filepath.WalkDir(t.hotRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		// the temporary file of the interrupted write
+		if strings.HasPrefix(d.Name(), ".") && strings.Contains(d.Name(), ".tmp") {
+			return os.Remove(path)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		key, err := filepath.Rel(t.hotRoot, path)
+		if err != nil {
+			return err
+		}
+		key = filepath.ToSlash(key)
+		e, ok := persisted[key]
+		if !ok || e.Size != info.Size() {
+			// the piece written after the state is saved, it will be written back to the cold tier
+			var coldSize int64
+			if t.coldUncounted(key) {
+				coldSize = t.coldCountSkip[key]
+				t.coldCountSkip[key] = info.Size()
+			} else if ok {
+				coldSize = e.ColdSize
+			} else if obj, headErr := t.cold.HeadObject(ctx, key); headErr == nil {
+				coldSize = obj.Size()
+			}
+			t.coldUsed += info.Size() - coldSize
+			e = &tierEntry{Key: key, ColdSize: info.Size(), AccessTime: info.ModTime().Unix(), Dirty: true}
+		}
+		e.Size = info.Size()
+		t.entries[key] = e
+		t.hotUsed += e.Size
+		return nil
+	})
+}
+
+// coldUncounted returns whether the piece is after the count marker of the cold tier counting, the
+// caller must hold the lock.
+func (t *tiered) coldUncounted(key string) bool {
+	return t.coldCounting && key > t.coldCountMarker
+}
+
+// countCold counts the pieces existing in the cold tier page by page, the pieces written during counting
+// are skipped. The capacity of the cold tier is checked by the partially counted size before it finishes.
+func (t *tiered) countCold(ctx context.Context) {
+	for pages := 1; ; pages++ {
+		done, err := t.countColdPage(ctx)
+		if err != nil {
+			log.Errorw("failed to count cold tier, retry later", "error", err)
+			time.Sleep(defaultTierFlushInterval)
+			continue
+		}
+		if done || pages%tierCountSavePages == 0 {
+			if err = t.saveState(); err != nil {
+				log.Errorw("failed to save tier state", "error", err)
+			}
+		}
+		if done {
+			return
+		}
+	}
+}
+
+// countColdPage counts one page of the cold tier after the count marker, it returns true if the counting
+// has finished.
+func (t *tiered) countColdPage(ctx context.Context) (bool, error) {
+	t.mux.Lock()
+	counting, marker := t.coldCounting, t.coldCountMarker
+	t.mux.Unlock()
+	if !counting {
+		return true, nil
+	}
+	objs, err := t.listColdPage(ctx, marker)
+	if err != nil {
+		return false, err
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	for _, obj := range objs {
+		if _, ok := t.coldCountSkip[obj.Key()]; ok {
+			delete(t.coldCountSkip, obj.Key())
+			continue
+		}
+		t.coldUsed += obj.Size()
+	}
+	if len(objs) > 0 {
+		t.coldCountMarker = objs[len(objs)-1].Key()
+	}
+	if len(objs) < tierListPageSize {
+		t.coldCounting = false
+		t.coldCountMarker = ""
+		t.coldCountSkip = make(map[string]int64)
+		log.Infow("succeed to count cold tier", "cold_used", t.coldUsed)
+		return true, nil
+	}
+	return false, nil
+}
+
+// listColdPage lists one page of the pieces after the marker from the cold tier.
+func (t *tiered) listColdPage(ctx context.Context, marker string) ([]Object, error) {
+	objs, err := t.cold.ListObjects(ctx, "", marker, "", tierListPageSize)
+	if !errors.Is(err, ErrUnsupportedMethod) {
+		return objs, err
+	}
+	listCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	objCh, err := t.cold.ListAllObjects(listCtx, "", marker)
+	if err != nil {
+		return nil, err
+	}
+	objs = make([]Object, 0, tierListPageSize)
+	for obj := range objCh {
+		// a nil object means that an error occurs when listing objects
+		if obj == nil {
+			return nil, fmt.Errorf("failed to list all objects of %s", t.cold)
+		}
+		objs = append(objs, obj)
+		if len(objs) == tierListPageSize {
+			break
+		}
+	}
+	return objs, nil
+}
+
+func (t *tiered) saveState() error {
+	t.mux.Lock()
+	state := &tierState{
+		ColdUsed:        t.coldUsed,
+		ColdCounting:    t.coldCounting,
+		ColdCountMarker: t.coldCountMarker,
+		ColdCountSkip:   make(map[string]int64, len(t.coldCountSkip)),
+		Entries:         make([]*tierEntry, 0, len(t.entries)),
+	}
+	for key, size := range t.coldCountSkip {
+		state.ColdCountSkip[key] = size
+	}
+	for _, e := range t.entries {
+		entry := *e
+		state.Entries = append(state.Entries, &entry)
+	}
+	t.mux.Unlock()
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := t.statePath + ".tmp"
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, t.statePath)
+}
+
+func (t *tiered) loop() {
+	interval := time.Duration(t.cfg.FlushIntervalSec) * time.Second
+	if interval <= 0 {
+		interval = defaultTierFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-t.notifyCh:
+		}
+		t.cycle(context.Background())
+	}
+}
+
+// cycle writes back the dirty pieces, evicts the pieces from the hot tier and persists the state.
+func (t *tiered) cycle(ctx context.Context) {
+	t.cycleMux.Lock()
+	defer t.cycleMux.Unlock()
+	t.flush(ctx)
+	t.evict(ctx)
+	if err := t.saveState(); err != nil {
+		log.Errorw("failed to save tier state", "error", err)
+	}
+}
+
+func (t *tiered) notify() {
+	select {
+	case t.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// flush writes back the dirty pieces to the cold tier from the least recently accessed.
+func (t *tiered) flush(ctx context.Context) {
+	t.mux.Lock()
+	var dirty []tierEntry
+	for _, e := range t.entries {
+		if e.Dirty {
+			dirty = append(dirty, *e)
+		}
+	}
+	t.mux.Unlock()
+	sort.Slice(dirty, func(i, j int) bool { return dirty[i].AccessTime < dirty[j].AccessTime })
+
+	for _, e := range dirty {
+		if err := t.flushEntry(ctx, e); err != nil {
+			log.Errorw("failed to write back piece to cold tier", "key", e.Key, "error", err)
+		}
+	}
+}
+
+func (t *tiered) flushEntry(ctx context.Context, e tierEntry) error {
+	rc, err := t.hot.GetObject(ctx, e.Key, 0, -1)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil {
+		return err
+	}
+	if err = t.cold.PutObject(ctx, e.Key, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	t.mux.Lock()
+	current, ok := t.entries[e.Key]
+	if ok && current.version == e.version {
+		current.Dirty = false
+	}
+	t.mux.Unlock()
+	if !ok {
+		// the piece is deleted during writing back
+		return t.cold.DeleteObject(ctx, e.Key)
+	}
+	return nil
+}
+
+// evict removes the clean pieces which are idle longer than the max age, and then the least recently
+// accessed clean pieces until the hot tier is within its capacity.
+func (t *tiered) evict(ctx context.Context) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	var clean []*tierEntry
+	for _, e := range t.entries {
+		if !e.Dirty {
+			clean = append(clean, e)
+		}
+	}
+	sort.Slice(clean, func(i, j int) bool { return clean[i].AccessTime < clean[j].AccessTime })
+	evicted := 0
+	expired := time.Now().Unix() - t.cfg.HotMaxAgeSec
+	for _, e := range clean {
+		if t.hotUsed <= t.cfg.HotCapacity && (t.cfg.HotMaxAgeSec <= 0 || e.AccessTime >= expired) {
+			break
+		}
+		if err := t.hot.DeleteObject(ctx, e.Key); err != nil {
+			log.Errorw("failed to evict piece from hot tier", "key", e.Key, "error", err)
+			continue
+		}
+		evicted++
+		t.hotUsed -= e.Size
+		delete(t.entries, e.Key)
+	}
+	if evicted > 0 {
+		log.Debugw("succeed to evict pieces from hot tier", "number", evicted, "hot_used", t.hotUsed)
+	}
+}
+
+// Flush writes back all the dirty pieces to the cold tier and persists the tier state.
+func (t *tiered) Flush(ctx context.Context) {
+	t.cycle(ctx)
+}
+
+func (t *tiered) String() string {
+	return fmt.Sprintf("tiered(%s, %s)", t.hot, t.cold)
+}
+
+func (t *tiered) CreateBucket(ctx context.Context) error {
+	return t.cold.CreateBucket(ctx)
+}
+
+func (t *tiered) HeadBucket(ctx context.Context) error {
+	return t.cold.HeadBucket(ctx)
+}
+
+// GetObject reads the piece from the hot tier first, and promotes the piece read from the cold tier
+// to the hot tier.
+func (t *tiered) GetObject(ctx context.Context, key string, offset, limit int64) (io.ReadCloser, error) {
+	t.mux.Lock()
+	_, ok := t.entries[key]
+	if ok {
+		t.entries[key].AccessTime = time.Now().Unix()
+	}
+	t.mux.Unlock()
+	if ok {
+		rc, err := t.hot.GetObject(ctx, key, offset, limit)
+		if err == nil {
+			return rc, nil
+		}
+		log.Errorw("failed to get piece from hot tier", "key", key, "error", err)
+	}
+
+	rc, err := t.cold.GetObject(ctx, key, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(rc)
+	_ = rc.Close()
+	if err != nil {
+		return nil, err
+	}
+	t.promote(ctx, key, data)
+
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	end := int64(len(data))
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return io.NopCloser(bytes.NewReader(data[offset:end])), nil
+}
+
+func (t *tiered) promote(ctx context.Context, key string, data []byte) {
+	if int64(len(data)) > t.cfg.HotCapacity {
+		return
+	}
+	t.mux.Lock()
+	_, ok := t.entries[key]
+	t.mux.Unlock()
+	if ok {
+		return
+	}
+	// the piece written to the hot tier concurrently is newer than the promoted one
+	if err := t.putHot(ctx, key, data, false, false); err != nil {
+		log.Errorw("failed to promote piece to hot tier", "key", key, "error", err)
+	}
+}
+
+// putHot writes the piece to a temporary file of the hot tier without the lock, and renames it to the
+// piece under the lock, so that the piece is not evicted or deleted concurrently and the slow disk
+// writes do not block the other pieces. The existing piece is kept if overwrite is false.
+func (t *tiered) putHot(ctx context.Context, key string, data []byte, dirty, overwrite bool) error {
+	tmpKey := path.Join(path.Dir(key), "."+path.Base(key)+".tmp"+strconv.Itoa(rand.Int()))
+	if err := t.hot.PutObject(ctx, tmpKey, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	e, ok := t.entries[key]
+	if ok && !overwrite {
+		return os.Remove(t.hotFile(tmpKey))
+	}
+	if err := os.Rename(t.hotFile(tmpKey), t.hotFile(key)); err != nil {
+		_ = os.Remove(t.hotFile(tmpKey))
+		return err
+	}
+	if ok {
+		t.hotUsed -= e.Size
+	}
+	size := int64(len(data))
+	t.version++
+	t.entries[key] = &tierEntry{Key: key, Size: size, ColdSize: size, AccessTime: time.Now().Unix(), Dirty: dirty, version: t.version}
+	t.hotUsed += size
+	if t.hotUsed > t.cfg.HotCapacity {
+		t.notify()
+	}
+	return nil
+}
+
+func (t *tiered) hotFile(key string) string {
+	return filepath.Join(t.hotRoot, filepath.FromSlash(key))
+}
+
+// PutObject writes the piece to the cold tier and caches it in the hot tier. The piece is only written to
+// the hot tier and written back to the cold tier in background if WriteBack is enabled, and the piece larger
+// than the hot tier capacity is only written to the cold tier.
+func (t *tiered) PutObject(ctx context.Context, key string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	size := int64(len(data))
+
+	t.mux.Lock()
+	var prevColdSize int64
+	prevSkipSize, skipped := t.coldCountSkip[key]
+	uncounted := t.coldUncounted(key)
+	if uncounted {
+		// the previous piece in the cold tier is not counted yet, and the piece is skipped by the counting
+		prevColdSize = prevSkipSize
+	} else if e, ok := t.entries[key]; ok {
+		prevColdSize = e.ColdSize
+	}
+	if t.cfg.ColdCapacity > 0 && t.coldUsed+size-prevColdSize > t.cfg.ColdCapacity {
+		t.mux.Unlock()
+		log.Errorw("failed to put piece due to cold tier capacity exceeded", "key", key, "cold_used", t.coldUsed)
+		return ErrTierCapacityExceeded
+	}
+	if uncounted {
+		t.coldCountSkip[key] = size
+	}
+	t.mux.Unlock()
+	// restoreSkip restores the count skip piece if the piece fails to be written
+	restoreSkip := func() {
+		if !uncounted {
+			return
+		}
+		t.mux.Lock()
+		defer t.mux.Unlock()
+		if skipped {
+			t.coldCountSkip[key] = prevSkipSize
+		} else {
+			delete(t.coldCountSkip, key)
+		}
+	}
+
+	if size > t.cfg.HotCapacity || !t.cfg.WriteBack {
+		if err = t.cold.PutObject(ctx, key, bytes.NewReader(data)); err != nil {
+			restoreSkip()
+			return err
+		}
+		t.mux.Lock()
+		t.coldUsed += size - prevColdSize
+		if size <= t.cfg.HotCapacity {
+			t.mux.Unlock()
+			// the written through piece is cached in the hot tier
+			return t.putHot(ctx, key, data, false, true)
+		}
+		defer t.mux.Unlock()
+		if e, ok := t.entries[key]; ok {
+			t.hotUsed -= e.Size
+			delete(t.entries, key)
+			return t.hot.DeleteObject(ctx, key)
+		}
+		return nil
+	}
+
+	if err = t.putHot(ctx, key, data, true, true); err != nil {
+		restoreSkip()
+		return err
+	}
+	t.mux.Lock()
+	t.coldUsed += size - prevColdSize
+	t.mux.Unlock()
+	return nil
+}
+
+func (t *tiered) DeleteObject(ctx context.Context, key string) error {
+	t.mux.Lock()
+	e, ok := t.entries[key]
+	if ok {
+		if err := t.hot.DeleteObject(ctx, key); err != nil {
+			t.mux.Unlock()
+			return err
+		}
+		delete(t.entries, key)
+		t.hotUsed -= e.Size
+	}
+	// the uncounted piece in the cold tier is only counted if it is skipped by the counting
+	skipSize, skipped := t.coldCountSkip[key]
+	uncounted := t.coldUncounted(key)
+	t.mux.Unlock()
+
+	coldSize := int64(0)
+	if uncounted {
+		coldSize = skipSize
+	} else if ok {
+		coldSize = e.ColdSize
+	} else if obj, err := t.cold.HeadObject(ctx, key); err == nil {
+		coldSize = obj.Size()
+	}
+	if err := t.cold.DeleteObject(ctx, key); err != nil {
+		return err
+	}
+	t.mux.Lock()
+	if skipped {
+		delete(t.coldCountSkip, key)
+	}
+	t.coldUsed -= coldSize
+	if t.coldUsed < 0 {
+		t.coldUsed = 0
+	}
+	t.mux.Unlock()
+	return nil
+}
+
+func (t *tiered) HeadObject(ctx context.Context, key string) (Object, error) {
+	t.mux.Lock()
+	_, ok := t.entries[key]
+	t.mux.Unlock()
+	if ok {
+		if obj, err := t.hot.HeadObject(ctx, key); err == nil {
+			return obj, nil
+		}
+	}
+	return t.cold.HeadObject(ctx, key)
+}
+
+// ListObjects merges the dirty pieces of the hot tier into the pieces listed from the cold tier, the
+// delimiter is unsupported.
+func (t *tiered) ListObjects(ctx context.Context, prefix, marker, delimiter string, limit int64) ([]Object, error) {
+	if delimiter != "" {
+		return nil, ErrUnsupportedDelimiter
+	}
+	objs, err := t.cold.ListObjects(ctx, prefix, marker, "", limit)
+	if err != nil {
+		return nil, err
+	}
+	dirty := t.dirtyObjects(prefix, marker)
+	if len(objs) > 0 && int64(len(objs)) >= limit {
+		// the cold tier has more pieces after the last listed one, the dirty pieces after it are listed
+		// in the next page
+		last := objs[len(objs)-1].Key()
+		dirty = dirty[:sort.Search(len(dirty), func(i int) bool { return dirty[i].Key() > last })]
+	}
+	merged := make([]Object, 0, len(objs)+len(dirty))
+	i := 0
+	for _, obj := range objs {
+		for ; i < len(dirty) && dirty[i].Key() < obj.Key(); i++ {
+			merged = append(merged, dirty[i])
+		}
+		if i < len(dirty) && dirty[i].Key() == obj.Key() {
+			obj = dirty[i]
+			i++
+		}
+		merged = append(merged, obj)
+	}
+	merged = append(merged, dirty[i:]...)
+	if int64(len(merged)) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
+
+// ListAllObjects merges the dirty pieces of the hot tier into the pieces listed from the cold tier, which
+// are listed in the ascending order of the keys.
+func (t *tiered) ListAllObjects(ctx context.Context, prefix, marker string) (<-chan Object, error) {
+	coldCh, err := t.cold.ListAllObjects(ctx, prefix, marker)
+	if err != nil {
+		return nil, err
+	}
+	dirty := t.dirtyObjects(prefix, marker)
+	objs := make(chan Object, tierListPageSize)
+	go func() {
+		defer close(objs)
+		send := func(obj Object) bool {
+			select {
+			case objs <- obj:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		i := 0
+		for obj := range coldCh {
+			if obj == nil {
+				send(nil)
+				return
+			}
+			for ; i < len(dirty) && dirty[i].Key() < obj.Key(); i++ {
+				if !send(dirty[i]) {
+					return
+				}
+			}
+			if i < len(dirty) && dirty[i].Key() == obj.Key() {
+				obj = dirty[i]
+				i++
+			}
+			if !send(obj) {
+				return
+			}
+		}
+		for ; i < len(dirty); i++ {
+			if !send(dirty[i]) {
+				return
+			}
+		}
+	}()
+	return objs, nil
+}
+
+// dirtyObjects returns the pieces which have not been written back in the ascending order of the keys.
+func (t *tiered) dirtyObjects(prefix, marker string) []Object {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	var objs []Object
+	for _, e := range t.entries {
+		if e.Dirty && strings.HasPrefix(e.Key, prefix) && e.Key > marker {
+			objs = append(objs, &object{key: e.Key, size: e.Size, modTime: time.Unix(e.AccessTime, 0)})
+		}
+	}
+	sort.Slice(objs, func(i, j int) bool { return objs[i].Key() < objs[j].Key() })
+	return objs
+}
@@ -0,0 +1,313 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTieredTest(t *testing.T, cfg TierConfig, cold ObjectStorage) *tiered {
+	if cfg.HotPath == "" {
+		cfg.HotPath = t.TempDir()
+	}
+	if cfg.HotCapacity == 0 {
+		cfg.HotCapacity = 1024
+	}
+	// flush and evict by the test explicitly without the background loop
+	store, err := newTiered(cfg, cold)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.lock.Close() })
+	return store
+}
+
+func readTiered(t *testing.T, store ObjectStorage, key string, offset, limit int64) string {
+	rc, err := store.GetObject(context.Background(), key, offset, limit)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestTiered_PutAndFlush(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	store := setupTieredTest(t, TierConfig{WriteBack: true}, cold)
+
+	require.NoError(t, store.PutObject(ctx, "a", bytes.NewReader([]byte("hello"))))
+	assert.Equal(t, "hello", readTiered(t, store, "a", 0, -1))
+	assert.Equal(t, "ell", readTiered(t, store, "a", 1, 3))
+	_, err := cold.HeadObject(ctx, "a")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	store.Flush(ctx)
+	assert.Equal(t, "hello", readTiered(t, cold, "a", 0, -1))
+	assert.False(t, store.entries["a"].Dirty)
+
+	obj, err := store.HeadObject(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), obj.Size())
+
+	require.NoError(t, store.DeleteObject(ctx, "a"))
+	_, err = store.HeadObject(ctx, "a")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Equal(t, int64(0), store.hotUsed)
+	assert.Equal(t, int64(0), store.coldUsed)
+}
+
+func TestTiered_ReadThroughAndEvict(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	require.NoError(t, cold.PutObject(ctx, "cold", bytes.NewReader([]byte("0123456789"))))
+	store := setupTieredTest(t, TierConfig{HotCapacity: 16}, cold)
+
+	// the piece read from the cold tier is promoted to the hot tier
+	assert.Equal(t, "2345", readTiered(t, store, "cold", 2, 4))
+	require.Contains(t, store.entries, "cold")
+	assert.False(t, store.entries["cold"].Dirty)
+	assert.Equal(t, int64(10), store.hotUsed)
+
+	// the least recently accessed clean piece is evicted if the hot tier is full
+	store.entries["cold"].AccessTime = time.Now().Add(-time.Hour).Unix()
+	require.NoError(t, store.PutObject(ctx, "new", bytes.NewReader([]byte("abcdefgh"))))
+	store.Flush(ctx)
+	assert.NotContains(t, store.entries, "cold")
+	assert.Contains(t, store.entries, "new")
+	assert.Equal(t, int64(8), store.hotUsed)
+	assert.Equal(t, "0123456789", readTiered(t, store, "cold", 0, -1))
+
+	// the piece larger than the hot tier is written to the cold tier directly
+	large := bytes.Repeat([]byte("x"), 32)
+	require.NoError(t, store.PutObject(ctx, "large", bytes.NewReader(large)))
+	assert.NotContains(t, store.entries, "large")
+	assert.Equal(t, string(large), readTiered(t, cold, "large", 0, -1))
+}
+
+func TestTiered_EvictByAge(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	store := setupTieredTest(t, TierConfig{HotMaxAgeSec: 60}, cold)
+
+	require.NoError(t, store.PutObject(ctx, "a", bytes.NewReader([]byte("aaa"))))
+	require.NoError(t, store.PutObject(ctx, "b", bytes.NewReader([]byte("bbb"))))
+	store.entries["a"].AccessTime = time.Now().Add(-time.Hour).Unix()
+	store.Flush(ctx)
+	assert.NotContains(t, store.entries, "a")
+	assert.Contains(t, store.entries, "b")
+	assert.Equal(t, "aaa", readTiered(t, store, "a", 0, -1))
+}
+
+func TestTiered_ColdCapacity(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	store := setupTieredTest(t, TierConfig{ColdCapacity: 8}, cold)
+
+	require.NoError(t, store.PutObject(ctx, "a", bytes.NewReader([]byte("aaaaa"))))
+	err := store.PutObject(ctx, "b", bytes.NewReader([]byte("bbbbb")))
+	assert.ErrorIs(t, err, ErrTierCapacityExceeded)
+	// overwriting the piece only counts the difference
+	require.NoError(t, store.PutObject(ctx, "a", bytes.NewReader([]byte("aaaaaaa"))))
+	require.NoError(t, store.DeleteObject(ctx, "a"))
+	require.NoError(t, store.PutObject(ctx, "b", bytes.NewReader([]byte("bbbbb"))))
+}
+
+func TestTiered_Restart(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	hotPath := t.TempDir()
+	store := setupTieredTest(t, TierConfig{HotPath: hotPath, ColdCapacity: 100, WriteBack: true}, cold)
+
+	require.NoError(t, store.PutObject(ctx, "clean", bytes.NewReader([]byte("clean"))))
+	store.Flush(ctx)
+	require.NoError(t, store.PutObject(ctx, "dirty", bytes.NewReader([]byte("dirty"))))
+	require.NoError(t, store.saveState())
+	// the piece written after the state is saved and the interrupted write
+	dataDir := filepath.Join(hotPath, tierDataDir)
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "lost"), []byte("lost"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, ".lost.tmp1"), []byte("l"), 0644))
+
+	// the hot path is locked by the running store
+	_, err := newTiered(TierConfig{HotPath: hotPath, HotCapacity: 1024}, cold)
+	assert.ErrorIs(t, err, ErrTierHotPathInUse)
+	require.NoError(t, store.lock.Close())
+
+	restarted := setupTieredTest(t, TierConfig{HotPath: hotPath, ColdCapacity: 100, WriteBack: true}, cold)
+	assert.Len(t, restarted.entries, 3)
+	assert.False(t, restarted.entries["clean"].Dirty)
+	assert.True(t, restarted.entries["dirty"].Dirty)
+	assert.True(t, restarted.entries["lost"].Dirty)
+	assert.Equal(t, int64(14), restarted.hotUsed)
+	assert.Equal(t, int64(14), restarted.coldUsed)
+	_, err = os.Stat(filepath.Join(dataDir, ".lost.tmp1"))
+	assert.True(t, os.IsNotExist(err))
+
+	restarted.Flush(ctx)
+	assert.Equal(t, "dirty", readTiered(t, cold, "dirty", 0, -1))
+	assert.Equal(t, "lost", readTiered(t, cold, "lost", 0, -1))
+
+	objs, err := restarted.ListObjects(ctx, "", "", "", 10)
+	require.NoError(t, err)
+	assert.Len(t, objs, 3)
+}
+
+func TestTiered_ListWithoutFlush(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	require.NoError(t, cold.PutObject(ctx, "p/b", bytes.NewReader([]byte("bb"))))
+	require.NoError(t, cold.PutObject(ctx, "p/d", bytes.NewReader([]byte("dd"))))
+	store := setupTieredTest(t, TierConfig{WriteBack: true}, cold)
+
+	require.NoError(t, store.PutObject(ctx, "p/a", bytes.NewReader([]byte("a"))))
+	require.NoError(t, store.PutObject(ctx, "p/b", bytes.NewReader([]byte("bbbb"))))
+	require.NoError(t, store.PutObject(ctx, "p/c", bytes.NewReader([]byte("c"))))
+	require.NoError(t, store.PutObject(ctx, "p/e", bytes.NewReader([]byte("e"))))
+	require.NoError(t, store.PutObject(ctx, "q", bytes.NewReader([]byte("q"))))
+
+	keys := func(objs []Object) []string {
+		var ks []string
+		for _, obj := range objs {
+			ks = append(ks, obj.Key())
+		}
+		return ks
+	}
+	objs, err := store.ListObjects(ctx, "p/", "", "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p/a", "p/b", "p/c", "p/d", "p/e"}, keys(objs))
+	// the dirty piece overrides the written back one
+	assert.Equal(t, int64(4), objs[1].Size())
+
+	// the dirty pieces after the full cold page are listed in the next page
+	objs, err = store.ListObjects(ctx, "p/", "", "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p/a"}, keys(objs))
+	objs, err = store.ListObjects(ctx, "p/", "p/a", "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p/b", "p/c"}, keys(objs))
+	objs, err = store.ListObjects(ctx, "p/", "p/c", "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p/d", "p/e"}, keys(objs))
+
+	_, err = store.ListObjects(ctx, "p/", "", "/", 10)
+	assert.ErrorIs(t, err, ErrUnsupportedDelimiter)
+	// the pieces are still not written back
+	_, err = cold.HeadObject(ctx, "p/a")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestTiered_WriteThrough(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	store := setupTieredTest(t, TierConfig{}, cold)
+
+	require.NoError(t, store.PutObject(ctx, "a", bytes.NewReader([]byte("hello"))))
+	assert.Equal(t, "hello", readTiered(t, cold, "a", 0, -1))
+	require.Contains(t, store.entries, "a")
+	assert.False(t, store.entries["a"].Dirty)
+	assert.Equal(t, "hello", readTiered(t, store, "a", 0, -1))
+	assert.Equal(t, int64(5), store.hotUsed)
+	assert.Equal(t, int64(5), store.coldUsed)
+}
+
+func TestTiered_ColdUsedOfExistingStore(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	for i := 0; i < tierListPageSize+1; i++ {
+		require.NoError(t, cold.PutObject(ctx, strconv.Itoa(i), bytes.NewReader([]byte("x"))))
+	}
+	hotPath := t.TempDir()
+	dataDir := filepath.Join(hotPath, tierDataDir)
+	require.NoError(t, os.MkdirAll(dataDir, 0755))
+	// the piece in the hot tier without the state is counted by its size in the cold tier
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "0"), []byte("xyz"), 0644))
+
+	store := setupTieredTest(t, TierConfig{HotPath: hotPath, ColdCapacity: 2048, WriteBack: true}, cold)
+	assert.Equal(t, int64(3), store.coldUsed)
+	done, err := store.countColdPage(ctx)
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.True(t, store.coldCounting)
+	store.countCold(ctx)
+	assert.False(t, store.coldCounting)
+	assert.Empty(t, store.coldCountSkip)
+	assert.Equal(t, int64(tierListPageSize+3), store.coldUsed)
+}
+
+func TestTiered_WriteDuringColdCounting(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, cold.PutObject(ctx, key, bytes.NewReader([]byte("x"))))
+	}
+	hotPath := t.TempDir()
+	store := setupTieredTest(t, TierConfig{HotPath: hotPath, ColdCapacity: 100}, cold)
+	require.True(t, store.coldCounting)
+
+	// the uncounted pieces are overwritten, deleted and written, and the counting restarts by the saved state
+	require.NoError(t, store.PutObject(ctx, "b", bytes.NewReader([]byte("bbb"))))
+	require.NoError(t, store.PutObject(ctx, "b", bytes.NewReader([]byte("bbbb"))))
+	require.NoError(t, store.DeleteObject(ctx, "c"))
+	require.NoError(t, store.PutObject(ctx, "e", bytes.NewReader([]byte("ee"))))
+	require.NoError(t, store.DeleteObject(ctx, "e"))
+	require.NoError(t, store.PutObject(ctx, "f", bytes.NewReader([]byte("ff"))))
+	assert.Equal(t, int64(6), store.coldUsed)
+	require.NoError(t, store.saveState())
+	require.NoError(t, store.lock.Close())
+
+	restarted := setupTieredTest(t, TierConfig{HotPath: hotPath, ColdCapacity: 100}, cold)
+	require.True(t, restarted.coldCounting)
+	restarted.countCold(ctx)
+	// a, b, d and f are counted once
+	assert.Equal(t, int64(1+4+1+2), restarted.coldUsed)
+}
+
+func TestTiered_HotPathInUse(t *testing.T) {
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	hotPath := t.TempDir()
+	setupTieredTest(t, TierConfig{HotPath: hotPath}, cold)
+
+	// the other process reads the cold tier directly in the write through mode
+	store, err := NewTiered(TierConfig{HotPath: hotPath, HotCapacity: 1024}, cold)
+	require.NoError(t, err)
+	assert.Equal(t, cold, store)
+	_, err = NewTiered(TierConfig{HotPath: hotPath, HotCapacity: 1024, WriteBack: true}, cold)
+	assert.ErrorIs(t, err, ErrTierHotPathInUse)
+}
+
+func TestTiered_ConcurrentPut(t *testing.T) {
+	ctx := context.Background()
+	cold := &memoryStore{name: mockBucket, objects: make(map[string]*memoryObject)}
+	store := setupTieredTest(t, TierConfig{HotCapacity: 1 << 20}, cold)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := bytes.Repeat([]byte{'a' + byte(i)}, i+1)
+			assert.NoError(t, store.PutObject(ctx, "a", bytes.NewReader(data)))
+			assert.NoError(t, store.PutObject(ctx, strconv.Itoa(i), bytes.NewReader(data)))
+		}(i)
+	}
+	wg.Wait()
+
+	// the hot tier has the last written piece without the temporary files
+	size := store.entries["a"].Size
+	assert.Equal(t, string(bytes.Repeat([]byte{'a' + byte(size-1)}, int(size))), readTiered(t, store, "a", 0, -1))
+	files, err := os.ReadDir(filepath.Join(store.cfg.HotPath, tierDataDir))
+	require.NoError(t, err)
+	assert.Len(t, files, 17)
+	var used int64
+	for _, e := range store.entries {
+		used += e.Size
+	}
+	assert.Equal(t, used, store.hotUsed)
+}
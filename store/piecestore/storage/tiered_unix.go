@@ -0,0 +1,27 @@
+//go:build !windows
+// +build !windows
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockTierHotPath locks the lock file of the hot path exclusively, so that the hot pieces and the tier
+// state are not shared by the processes. The lock is released when the file is closed.
+func lockTierHotPath(lockPath string) (*os.File, error) {
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrTierHotPathInUse
+		}
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,96 @@
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+// corsListSeparator separates the items of the lists in the cors policy, it cannot
+// be contained by the origins, the methods and the header names.
+const corsListSeparator = ","
+
+func splitCORSList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, corsListSeparator)
+}
+
+// UpdateBucketCORS is used to set the cors policy of the bucket, the new policy overwrites the old one.
+func (s *SpDBImpl) UpdateBucketCORS(cors *spdb.BucketCORS) error {
+	var (
+		result      *gorm.DB
+		queryReturn = &BucketCORSTable{}
+		record      = &BucketCORSTable{
+			BucketName:            cors.BucketName,
+			AllowedOrigins:        strings.Join(cors.AllowedOrigins, corsListSeparator),
+			AllowedMethods:        strings.Join(cors.AllowedMethods, corsListSeparator),
+			AllowedHeaders:        strings.Join(cors.AllowedHeaders, corsListSeparator),
+			ExposedHeaders:        strings.Join(cors.ExposedHeaders, corsListSeparator),
+			AllowCredentials:      cors.AllowCredentials,
+			MaxAgeSec:             cors.MaxAgeSec,
+			UpdateTimestampSecond: GetCurrentUnixTime(),
+		}
+	)
+	result = s.db.First(queryReturn, "bucket_name = ?", cors.BucketName)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to query bucket cors table: %s", result.Error)
+	}
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		result = s.db.Create(record)
+		if result.Error != nil || result.RowsAffected != 1 {
+			return fmt.Errorf("failed to insert record in bucket cors table: %s", result.Error)
+		}
+		return nil
+	}
+	result = s.db.Model(&BucketCORSTable{}).Where("bucket_name = ?", cors.BucketName).
+		Updates(map[string]interface{}{
+			"allowed_origins":         record.AllowedOrigins,
+			"allowed_methods":         record.AllowedMethods,
+			"allowed_headers":         record.AllowedHeaders,
+			"exposed_headers":         record.ExposedHeaders,
+			"allow_credentials":       record.AllowCredentials,
+			"max_age_sec":             record.MaxAgeSec,
+			"update_timestamp_second": record.UpdateTimestampSecond,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update record in bucket cors table: %s", result.Error)
+	}
+	return nil
+}
+
+// GetBucketCORS returns the cors policy of the bucket, returns (nil, nil) if it is not found in db.
+func (s *SpDBImpl) GetBucketCORS(bucketName string) (*spdb.BucketCORS, error) {
+	queryReturn := &BucketCORSTable{}
+	result := s.db.First(queryReturn, "bucket_name = ?", bucketName)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query bucket cors table: %s", result.Error)
+	}
+	return &spdb.BucketCORS{
+		BucketName:            queryReturn.BucketName,
+		AllowedOrigins:        splitCORSList(queryReturn.AllowedOrigins),
+		AllowedMethods:        splitCORSList(queryReturn.AllowedMethods),
+		AllowedHeaders:        splitCORSList(queryReturn.AllowedHeaders),
+		ExposedHeaders:        splitCORSList(queryReturn.ExposedHeaders),
+		AllowCredentials:      queryReturn.AllowCredentials,
+		MaxAgeSec:             queryReturn.MaxAgeSec,
+		UpdateTimestampSecond: queryReturn.UpdateTimestampSecond,
+	}, nil
+}
+
+// DeleteBucketCORS deletes the cors policy of the bucket.
+func (s *SpDBImpl) DeleteBucketCORS(bucketName string) error {
+	result := s.db.Where("bucket_name = ?", bucketName).Delete(&BucketCORSTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete record in bucket cors table: %s", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package sqldb
+
+// BucketCORSTable table schema, the lists of the policy are joined by comma.
+type BucketCORSTable struct {
+	BucketName            string `gorm:"primary_key;size:64"`
+	AllowedOrigins        string `gorm:"size:4096"`
+	AllowedMethods        string `gorm:"size:256"`
+	AllowedHeaders        string `gorm:"size:4096"`
+	ExposedHeaders        string `gorm:"size:4096"`
+	AllowCredentials      bool
+	MaxAgeSec             int64
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set BucketCORSTable Schema's table name in database.
+func (BucketCORSTable) TableName() string {
+	return BucketCORSTableName
+}
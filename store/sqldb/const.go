@@ -6,6 +6,14 @@ const (
 	UploadObjectProgressTableName = "upload_object_progress"
 	// GCObjectProgressTableName defines the gc object task table name.
 	GCObjectProgressTableName = "gc_object_progress"
+	// GCMetaProgressTableName defines the gc meta task table name.
+	GCMetaProgressTableName = "gc_meta_progress"
+	// GCZombieProgressTableName defines the gc zombie piece task table name.
+	GCZombieProgressTableName = "gc_zombie_progress"
+	// ScrubProgressTableName defines the scrub progress table name of the global virtual groups.
+	ScrubProgressTableName = "scrub_progress"
+	// ScrubPieceTableName defines the bad pieces found by scrubbing.
+	ScrubPieceTableName = "scrub_piece"
 	// PieceHashTableName defines the piece hash table name.
 	PieceHashTableName = "piece_hash"
 	// IntegrityMetaTableName defines the integrity meta table name.
@@ -18,6 +26,10 @@ const (
 	BucketTrafficTableName = "bucket_traffic"
 	// ReadRecordTableName defines the read record table name.
 	ReadRecordTableName = "read_record"
+	// ReadUsageRollupTableName defines the hourly rollups of the read records by bucket, object and user.
+	ReadUsageRollupTableName = "read_usage_rollup"
+	// ReadQuotaAlertTableName defines the read quota thresholds which have been notified by bucket.
+	ReadQuotaAlertTableName = "read_quota_alert"
 	// ServiceConfigTableName defines the SP configuration table name.
 	ServiceConfigTableName = "service_config"
 	// OffChainAuthKeyTableName defines the off chain auth key table name.
@@ -44,9 +56,22 @@ const (
 	SwapOutTableName = "swap_out_unit"
 	// MigrateGVGTableName defines the progress of subscribe migrate event.
 	MigrateGVGTableName = "migrate_gvg"
-)
-
-// define error name constant.
-const (
-	TableAlreadyExistsErrorPrefix = "Error 1050 (42S01)"
+	// MultipartUploadPartTableName defines the uploaded parts of multipart upload.
+	MultipartUploadPartTableName = "multipart_upload_part"
+	// S3AccessKeyTableName defines the access keys of the s3 compatible api.
+	S3AccessKeyTableName = "s3_access_key"
+	// BucketCORSTableName defines the cors policies of the buckets.
+	BucketCORSTableName = "bucket_cors"
+	// RateLimitCounterTableName defines the rate limit counters shared by the gateway replicas.
+	RateLimitCounterTableName = "rate_limit_counter"
+	// EventSubscriptionTableName defines the event subscriptions of the buckets.
+	EventSubscriptionTableName = "event_subscription"
+	// EventDeliveryTableName defines the event notifications waiting to be delivered.
+	EventDeliveryTableName = "event_delivery"
+	// EventDeadLetterTableName defines the event notifications which exceed the max delivery attempts.
+	EventDeadLetterTableName = "event_dead_letter"
+	// TaskQueueTableName defines the tasks persisted by the task queues.
+	TaskQueueTableName = "task_queue"
+	// SchemaVersionTableName defines the applied schema migrations of the sp db.
+	SchemaVersionTableName = "schema_version"
 )
@@ -0,0 +1,205 @@
+package sqldb
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+// eventTypeSeparator separates the event types of the subscription.
+const eventTypeSeparator = ","
+
+// UpdateBucketEventSubscriptions replaces the event subscriptions of the bucket in a transaction.
+func (s *SpDBImpl) UpdateBucketEventSubscriptions(bucketName string, subscriptions []*spdb.EventSubscription) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("bucket_name = ?", bucketName).Delete(&EventSubscriptionTable{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete records in event subscription table: %s", result.Error)
+		}
+		updateTime := GetCurrentUnixTime()
+		for _, subscription := range subscriptions {
+			eventTypes := make([]string, 0, len(subscription.EventTypes))
+			for _, eventType := range subscription.EventTypes {
+				eventTypes = append(eventTypes, string(eventType))
+			}
+			result = tx.Create(&EventSubscriptionTable{
+				BucketName:            bucketName,
+				SubscriptionID:        subscription.SubscriptionID,
+				Endpoint:              subscription.Endpoint,
+				Secret:                subscription.Secret,
+				EventTypes:            strings.Join(eventTypes, eventTypeSeparator),
+				UpdateTimestampSecond: updateTime,
+			})
+			if result.Error != nil || result.RowsAffected != 1 {
+				return fmt.Errorf("failed to insert record in event subscription table: %s", result.Error)
+			}
+		}
+		return nil
+	})
+}
+
+// GetBucketEventSubscriptions returns the event subscriptions of the bucket, returns an empty list if the
+// bucket has no subscription.
+func (s *SpDBImpl) GetBucketEventSubscriptions(bucketName string) ([]*spdb.EventSubscription, error) {
+	var queryReturns []EventSubscriptionTable
+	result := s.db.Where("bucket_name = ?", bucketName).Order("subscription_id").Find(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query event subscription table: %s", result.Error)
+	}
+	subscriptions := make([]*spdb.EventSubscription, 0, len(queryReturns))
+	for _, record := range queryReturns {
+		subscription := &spdb.EventSubscription{
+			BucketName:     record.BucketName,
+			SubscriptionID: record.SubscriptionID,
+			Endpoint:       record.Endpoint,
+			Secret:         record.Secret,
+		}
+		if record.EventTypes != "" {
+			for _, eventType := range strings.Split(record.EventTypes, eventTypeSeparator) {
+				subscription.EventTypes = append(subscription.EventTypes, spdb.ObjectEventType(eventType))
+			}
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+// DeleteBucketEventSubscriptions deletes the event subscriptions of the bucket.
+func (s *SpDBImpl) DeleteBucketEventSubscriptions(bucketName string) error {
+	result := s.db.Where("bucket_name = ?", bucketName).Delete(&EventSubscriptionTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete records in event subscription table: %s", result.Error)
+	}
+	return nil
+}
+
+// InsertEventDeliveries inserts the notifications waiting to be delivered.
+func (s *SpDBImpl) InsertEventDeliveries(deliveries []*spdb.EventDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	records := make([]*EventDeliveryTable, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		records = append(records, &EventDeliveryTable{
+			BucketName:                 delivery.BucketName,
+			SubscriptionID:             delivery.SubscriptionID,
+			EventType:                  string(delivery.EventType),
+			Payload:                    delivery.Payload,
+			Attempts:                   delivery.Attempts,
+			NextAttemptTimestampSecond: delivery.NextAttemptTimestampSecond,
+			CreateTimestampSecond:      delivery.CreateTimestampSecond,
+		})
+	}
+	if result := s.db.Create(&records); result.Error != nil {
+		return fmt.Errorf("failed to insert records in event delivery table: %s", result.Error)
+	}
+	return nil
+}
+
+// GetDueEventDeliveries returns at most limit notifications whose next attempt is not after the timestamp.
+func (s *SpDBImpl) GetDueEventDeliveries(timestampSecond int64, limit int) ([]*spdb.EventDelivery, error) {
+	var queryReturns []EventDeliveryTable
+	result := s.db.Where("next_attempt_timestamp_second <= ?", timestampSecond).
+		Order("next_attempt_timestamp_second, delivery_id").Limit(limit).Find(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query event delivery table: %s", result.Error)
+	}
+	deliveries := make([]*spdb.EventDelivery, 0, len(queryReturns))
+	for _, record := range queryReturns {
+		deliveries = append(deliveries, &spdb.EventDelivery{
+			DeliveryID:                 record.DeliveryID,
+			BucketName:                 record.BucketName,
+			SubscriptionID:             record.SubscriptionID,
+			EventType:                  spdb.ObjectEventType(record.EventType),
+			Payload:                    record.Payload,
+			Attempts:                   record.Attempts,
+			NextAttemptTimestampSecond: record.NextAttemptTimestampSecond,
+			LastError:                  record.LastError,
+			CreateTimestampSecond:      record.CreateTimestampSecond,
+		})
+	}
+	return deliveries, nil
+}
+
+// UpdateEventDeliveryAttempt updates the attempts, the next attempt time and the last error of the notification.
+func (s *SpDBImpl) UpdateEventDeliveryAttempt(delivery *spdb.EventDelivery) error {
+	result := s.db.Model(&EventDeliveryTable{}).Where("delivery_id = ?", delivery.DeliveryID).
+		Updates(map[string]interface{}{
+			"attempts":                      delivery.Attempts,
+			"next_attempt_timestamp_second": delivery.NextAttemptTimestampSecond,
+			"last_error":                    truncateEventError(delivery.LastError),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update record in event delivery table: %s", result.Error)
+	}
+	return nil
+}
+
+// DeleteEventDelivery deletes the notification which is delivered or dropped.
+func (s *SpDBImpl) DeleteEventDelivery(deliveryID uint64) error {
+	result := s.db.Where("delivery_id = ?", deliveryID).Delete(&EventDeliveryTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete record in event delivery table: %s", result.Error)
+	}
+	return nil
+}
+
+// MoveEventDeliveryToDeadLetter moves the notification to the dead letters in a transaction.
+func (s *SpDBImpl) MoveEventDeliveryToDeadLetter(delivery *spdb.EventDelivery) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Create(&EventDeadLetterTable{
+			DeliveryID:            delivery.DeliveryID,
+			BucketName:            delivery.BucketName,
+			SubscriptionID:        delivery.SubscriptionID,
+			EventType:             string(delivery.EventType),
+			Payload:               delivery.Payload,
+			Attempts:              delivery.Attempts,
+			LastError:             truncateEventError(delivery.LastError),
+			CreateTimestampSecond: delivery.CreateTimestampSecond,
+			DeadTimestampSecond:   GetCurrentUnixTime(),
+		})
+		if result.Error != nil || result.RowsAffected != 1 {
+			return fmt.Errorf("failed to insert record in event dead letter table: %s", result.Error)
+		}
+		result = tx.Where("delivery_id = ?", delivery.DeliveryID).Delete(&EventDeliveryTable{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete record in event delivery table: %s", result.Error)
+		}
+		return nil
+	})
+}
+
+// ListDeadLetterEventDeliveries returns at most limit dead notifications of the bucket, the latest first.
+func (s *SpDBImpl) ListDeadLetterEventDeliveries(bucketName string, limit int) ([]*spdb.EventDelivery, error) {
+	var queryReturns []EventDeadLetterTable
+	result := s.db.Where("bucket_name = ?", bucketName).Order("delivery_id DESC").Limit(limit).Find(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query event dead letter table: %s", result.Error)
+	}
+	deliveries := make([]*spdb.EventDelivery, 0, len(queryReturns))
+	for _, record := range queryReturns {
+		deliveries = append(deliveries, &spdb.EventDelivery{
+			DeliveryID:            record.DeliveryID,
+			BucketName:            record.BucketName,
+			SubscriptionID:        record.SubscriptionID,
+			EventType:             spdb.ObjectEventType(record.EventType),
+			Payload:               record.Payload,
+			Attempts:              record.Attempts,
+			LastError:             record.LastError,
+			CreateTimestampSecond: record.CreateTimestampSecond,
+		})
+	}
+	return deliveries, nil
+}
+
+// truncateEventError truncates the error to the size of the last error column.
+func truncateEventError(err string) string {
+	const maxEventErrorLength = 1024
+	if len(err) > maxEventErrorLength {
+		return err[:maxEventErrorLength]
+	}
+	return err
+}
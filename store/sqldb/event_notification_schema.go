@@ -0,0 +1,52 @@
+package sqldb
+
+// EventSubscriptionTable table schema, the event types are joined by comma.
+type EventSubscriptionTable struct {
+	BucketName            string `gorm:"primary_key;size:64"`
+	SubscriptionID        string `gorm:"primary_key;size:64"`
+	Endpoint              string `gorm:"size:1024"`
+	Secret                string `gorm:"size:256"`
+	EventTypes            string `gorm:"size:256"`
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set EventSubscriptionTable Schema's table name in database.
+func (EventSubscriptionTable) TableName() string {
+	return EventSubscriptionTableName
+}
+
+// EventDeliveryTable table schema, the notifications are delivered by the order of the next attempt time.
+type EventDeliveryTable struct {
+	DeliveryID                 uint64 `gorm:"primary_key;autoIncrement"`
+	BucketName                 string `gorm:"size:64"`
+	SubscriptionID             string `gorm:"size:64"`
+	EventType                  string `gorm:"size:64"`
+	Payload                    string `gorm:"type:text"`
+	Attempts                   int
+	NextAttemptTimestampSecond int64  `gorm:"index:next_attempt_index"`
+	LastError                  string `gorm:"size:1024"`
+	CreateTimestampSecond      int64
+}
+
+// TableName is used to set EventDeliveryTable Schema's table name in database.
+func (EventDeliveryTable) TableName() string {
+	return EventDeliveryTableName
+}
+
+// EventDeadLetterTable table schema, the delivery id is kept from the event delivery table.
+type EventDeadLetterTable struct {
+	DeliveryID            uint64 `gorm:"primary_key;autoIncrement:false"`
+	BucketName            string `gorm:"size:64;index:bucket_to_dead_letter"`
+	SubscriptionID        string `gorm:"size:64"`
+	EventType             string `gorm:"size:64"`
+	Payload               string `gorm:"type:text"`
+	Attempts              int
+	LastError             string `gorm:"size:1024"`
+	CreateTimestampSecond int64
+	DeadTimestampSecond   int64
+}
+
+// TableName is used to set EventDeadLetterTable Schema's table name in database.
+func (EventDeadLetterTable) TableName() string {
+	return EventDeadLetterTableName
+}
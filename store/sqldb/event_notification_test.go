@@ -0,0 +1,139 @@
+package sqldb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+func TestSpDBImpl_BucketEventSubscriptions(t *testing.T) {
+	s := setupSpDBTest(t)
+	subscriptions, err := s.GetBucketEventSubscriptions("bucket")
+	require.NoError(t, err)
+	assert.Empty(t, subscriptions)
+
+	require.NoError(t, s.UpdateBucketEventSubscriptions("bucket", []*spdb.EventSubscription{
+		{SubscriptionID: "b", Endpoint: "https://b.com", Secret: "secret-b",
+			EventTypes: []spdb.ObjectEventType{spdb.ObjectSealedEvent}},
+		{SubscriptionID: "a", Endpoint: "https://a.com", Secret: "secret-a",
+			EventTypes: []spdb.ObjectEventType{spdb.ObjectUploadedEvent, spdb.ObjectDeletedEvent}},
+	}))
+	require.NoError(t, s.UpdateBucketEventSubscriptions("other", []*spdb.EventSubscription{
+		{SubscriptionID: "a", Endpoint: "https://other.com", Secret: "secret",
+			EventTypes: []spdb.ObjectEventType{spdb.ObjectSealedEvent}},
+	}))
+	subscriptions, err = s.GetBucketEventSubscriptions("bucket")
+	require.NoError(t, err)
+	assert.Equal(t, []*spdb.EventSubscription{
+		{BucketName: "bucket", SubscriptionID: "a", Endpoint: "https://a.com", Secret: "secret-a",
+			EventTypes: []spdb.ObjectEventType{spdb.ObjectUploadedEvent, spdb.ObjectDeletedEvent}},
+		{BucketName: "bucket", SubscriptionID: "b", Endpoint: "https://b.com", Secret: "secret-b",
+			EventTypes: []spdb.ObjectEventType{spdb.ObjectSealedEvent}},
+	}, subscriptions)
+
+	// the subscriptions of the bucket are replaced
+	require.NoError(t, s.UpdateBucketEventSubscriptions("bucket", []*spdb.EventSubscription{
+		{SubscriptionID: "c", Endpoint: "https://c.com", Secret: "secret-c",
+			EventTypes: []spdb.ObjectEventType{spdb.ObjectReplicatedEvent}},
+	}))
+	subscriptions, err = s.GetBucketEventSubscriptions("bucket")
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	assert.Equal(t, "c", subscriptions[0].SubscriptionID)
+
+	require.NoError(t, s.DeleteBucketEventSubscriptions("bucket"))
+	subscriptions, err = s.GetBucketEventSubscriptions("bucket")
+	require.NoError(t, err)
+	assert.Empty(t, subscriptions)
+	subscriptions, err = s.GetBucketEventSubscriptions("other")
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+}
+
+func TestSpDBImpl_EventDeliveries(t *testing.T) {
+	s := setupSpDBTest(t)
+	require.NoError(t, s.InsertEventDeliveries(nil))
+	require.NoError(t, s.InsertEventDeliveries([]*spdb.EventDelivery{
+		{BucketName: "bucket", SubscriptionID: "a", EventType: spdb.ObjectSealedEvent, Payload: "1",
+			NextAttemptTimestampSecond: 200, CreateTimestampSecond: 100},
+		{BucketName: "bucket", SubscriptionID: "a", EventType: spdb.ObjectUploadedEvent, Payload: "2",
+			NextAttemptTimestampSecond: 100, CreateTimestampSecond: 100},
+		{BucketName: "bucket", SubscriptionID: "b", EventType: spdb.ObjectSealedEvent, Payload: "3",
+			NextAttemptTimestampSecond: 300, CreateTimestampSecond: 100},
+	}))
+
+	// the earliest due first
+	deliveries, err := s.GetDueEventDeliveries(200, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, "2", deliveries[0].Payload)
+	assert.Equal(t, spdb.ObjectUploadedEvent, deliveries[0].EventType)
+	assert.Equal(t, "1", deliveries[1].Payload)
+	deliveries, err = s.GetDueEventDeliveries(300, 1)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	// the failed notification is retried later
+	failed := deliveries[0]
+	failed.Attempts = 1
+	failed.NextAttemptTimestampSecond = 400
+	failed.LastError = strings.Repeat("e", 2000)
+	require.NoError(t, s.UpdateEventDeliveryAttempt(failed))
+	deliveries, err = s.GetDueEventDeliveries(300, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, "1", deliveries[0].Payload)
+	assert.Equal(t, "3", deliveries[1].Payload)
+	deliveries, err = s.GetDueEventDeliveries(400, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3)
+	assert.Equal(t, 1, deliveries[2].Attempts)
+	assert.Len(t, deliveries[2].LastError, 1024)
+
+	require.NoError(t, s.DeleteEventDelivery(deliveries[0].DeliveryID))
+	deliveries, err = s.GetDueEventDeliveries(400, 10)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+}
+
+func TestSpDBImpl_EventDeadLetters(t *testing.T) {
+	s := setupSpDBTest(t)
+	require.NoError(t, s.InsertEventDeliveries([]*spdb.EventDelivery{
+		{BucketName: "bucket", SubscriptionID: "a", EventType: spdb.ObjectSealedEvent, Payload: "1",
+			NextAttemptTimestampSecond: 100, CreateTimestampSecond: 100},
+		{BucketName: "bucket", SubscriptionID: "a", EventType: spdb.ObjectDeletedEvent, Payload: "2",
+			NextAttemptTimestampSecond: 100, CreateTimestampSecond: 100},
+	}))
+	deliveries, err := s.GetDueEventDeliveries(100, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	for _, delivery := range deliveries {
+		delivery.Attempts = 3
+		delivery.LastError = "unexpected status code: 500"
+		require.NoError(t, s.MoveEventDeliveryToDeadLetter(delivery))
+	}
+
+	// the dead notifications are not delivered again
+	due, err := s.GetDueEventDeliveries(100, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	dead, err := s.ListDeadLetterEventDeliveries("bucket", 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 2)
+	// the latest first
+	assert.Equal(t, &spdb.EventDelivery{DeliveryID: deliveries[1].DeliveryID, BucketName: "bucket", SubscriptionID: "a",
+		EventType: spdb.ObjectDeletedEvent, Payload: "2", Attempts: 3, LastError: "unexpected status code: 500",
+		CreateTimestampSecond: 100}, dead[0])
+	assert.Equal(t, deliveries[0].DeliveryID, dead[1].DeliveryID)
+	dead, err = s.ListDeadLetterEventDeliveries("bucket", 1)
+	require.NoError(t, err)
+	assert.Len(t, dead, 1)
+	dead, err = s.ListDeadLetterEventDeliveries("other", 10)
+	require.NoError(t, err)
+	assert.Empty(t, dead)
+}
@@ -0,0 +1,118 @@
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+// GCMetaProgressKey defines the task name of the gc meta progress, there is only one gc meta
+// task running in SP at the same time.
+const GCMetaProgressKey = "gc_meta_progress"
+
+// UpdateGCMetaProgress is used to update gc meta progress.
+// insert a new one if it is not found in db.
+func (s *SpDBImpl) UpdateGCMetaProgress(gcMeta *spdb.GCMetaProgress) error {
+	var (
+		result       *gorm.DB
+		queryReturn  *GCMetaProgressTable
+		updateRecord *GCMetaProgressTable
+	)
+	queryReturn = &GCMetaProgressTable{}
+	result = s.db.First(queryReturn, "task_name = ?", GCMetaProgressKey)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return result.Error
+	}
+	updateRecord = &GCMetaProgressTable{
+		TaskName:              GCMetaProgressKey,
+		CurrentIdx:            gcMeta.CurrentIdx,
+		LastObjectID:          gcMeta.LastObjectID,
+		UpdateTimestampSecond: GetCurrentUnixTime(),
+	}
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		result = s.db.Create(updateRecord)
+		if result.Error != nil || result.RowsAffected != 1 {
+			return fmt.Errorf("failed to insert record in gc meta progress table: %s", result.Error)
+		}
+		return nil
+	}
+	// use map to update the zero value fields
+	result = s.db.Model(&GCMetaProgressTable{}).Where("task_name = ?", GCMetaProgressKey).
+		Updates(map[string]interface{}{
+			"current_idx":             updateRecord.CurrentIdx,
+			"last_object_id":          updateRecord.LastObjectID,
+			"update_timestamp_second": updateRecord.UpdateTimestampSecond,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update record in gc meta progress table: %s", result.Error)
+	}
+	return nil
+}
+
+// QueryGCMetaProgress returns the gc meta progress, returns (nil, nil) if it is not found in db.
+func (s *SpDBImpl) QueryGCMetaProgress() (*spdb.GCMetaProgress, error) {
+	queryReturn := &GCMetaProgressTable{}
+	result := s.db.First(queryReturn, "task_name = ?", GCMetaProgressKey)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query gc meta progress table: %s", result.Error)
+	}
+	return &spdb.GCMetaProgress{
+		CurrentIdx:   queryReturn.CurrentIdx,
+		LastObjectID: queryReturn.LastObjectID,
+	}, nil
+}
+
+// GCZombieProgressKey defines the task name of the gc zombie piece progress, there is only one gc zombie
+// piece task running in SP at the same time.
+const GCZombieProgressKey = "gc_zombie_progress"
+
+// UpdateGCZombieProgress is used to update gc zombie piece progress.
+// insert a new one if it is not found in db.
+func (s *SpDBImpl) UpdateGCZombieProgress(progress *spdb.GCZombieProgress) error {
+	queryReturn := &GCZombieProgressTable{}
+	result := s.db.First(queryReturn, "task_name = ?", GCZombieProgressKey)
+	if result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return result.Error
+	}
+	updateRecord := &GCZombieProgressTable{
+		TaskName:              GCZombieProgressKey,
+		LastPieceKey:          progress.LastPieceKey,
+		UpdateTimestampSecond: GetCurrentUnixTime(),
+	}
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		result = s.db.Create(updateRecord)
+		if result.Error != nil || result.RowsAffected != 1 {
+			return fmt.Errorf("failed to insert record in gc zombie progress table: %s", result.Error)
+		}
+		return nil
+	}
+	// use map to update the zero value fields
+	result = s.db.Model(&GCZombieProgressTable{}).Where("task_name = ?", GCZombieProgressKey).
+		Updates(map[string]interface{}{
+			"last_piece_key":          updateRecord.LastPieceKey,
+			"update_timestamp_second": updateRecord.UpdateTimestampSecond,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update record in gc zombie progress table: %s", result.Error)
+	}
+	return nil
+}
+
+// QueryGCZombieProgress returns the gc zombie piece progress, returns (nil, nil) if it is not found in db.
+func (s *SpDBImpl) QueryGCZombieProgress() (*spdb.GCZombieProgress, error) {
+	queryReturn := &GCZombieProgressTable{}
+	result := s.db.First(queryReturn, "task_name = ?", GCZombieProgressKey)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query gc zombie progress table: %s", result.Error)
+	}
+	return &spdb.GCZombieProgress{LastPieceKey: queryReturn.LastPieceKey}, nil
+}
@@ -0,0 +1,26 @@
+package sqldb
+
+// GCMetaProgressTable table schema.
+type GCMetaProgressTable struct {
+	TaskName              string `gorm:"primary_key"`
+	CurrentIdx            uint64
+	LastObjectID          uint64
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set GCMetaProgressTable Schema's table name in database.
+func (GCMetaProgressTable) TableName() string {
+	return GCMetaProgressTableName
+}
+
+// GCZombieProgressTable table schema.
+type GCZombieProgressTable struct {
+	TaskName              string `gorm:"primary_key"`
+	LastPieceKey          string `gorm:"size:256"`
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set GCZombieProgressTable Schema's table name in database.
+func (GCZombieProgressTable) TableName() string {
+	return GCZombieProgressTableName
+}
@@ -0,0 +1,51 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+func TestSpDBImpl_GCMetaProgress(t *testing.T) {
+	s := setupSpDBTest(t)
+	progress, err := s.QueryGCMetaProgress()
+	require.NoError(t, err)
+	assert.Nil(t, progress)
+
+	require.NoError(t, s.UpdateGCMetaProgress(&spdb.GCMetaProgress{CurrentIdx: 2, LastObjectID: 100}))
+	progress, err = s.QueryGCMetaProgress()
+	require.NoError(t, err)
+	assert.Equal(t, &spdb.GCMetaProgress{CurrentIdx: 2, LastObjectID: 100}, progress)
+
+	// the zero values reset the progress
+	require.NoError(t, s.UpdateGCMetaProgress(&spdb.GCMetaProgress{}))
+	progress, err = s.QueryGCMetaProgress()
+	require.NoError(t, err)
+	assert.Equal(t, &spdb.GCMetaProgress{}, progress)
+}
+
+func TestSpDBImpl_GCZombieProgress(t *testing.T) {
+	s := setupSpDBTest(t)
+	progress, err := s.QueryGCZombieProgress()
+	require.NoError(t, err)
+	assert.Nil(t, progress)
+
+	require.NoError(t, s.UpdateGCZombieProgress(&spdb.GCZombieProgress{LastPieceKey: "s1_p0"}))
+	progress, err = s.QueryGCZombieProgress()
+	require.NoError(t, err)
+	assert.Equal(t, "s1_p0", progress.LastPieceKey)
+
+	require.NoError(t, s.UpdateGCZombieProgress(&spdb.GCZombieProgress{LastPieceKey: "s2_p0"}))
+	progress, err = s.QueryGCZombieProgress()
+	require.NoError(t, err)
+	assert.Equal(t, "s2_p0", progress.LastPieceKey)
+
+	// the finished task resets the progress to scan from the beginning
+	require.NoError(t, s.UpdateGCZombieProgress(&spdb.GCZombieProgress{}))
+	progress, err = s.QueryGCZombieProgress()
+	require.NoError(t, err)
+	assert.Equal(t, "", progress.LastPieceKey)
+}
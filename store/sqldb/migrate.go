@@ -286,10 +286,11 @@ func (s *SpDBImpl) InsertMigrateGVGUnit(meta *spdb.MigrateGVGUnitMeta) error {
 		BucketID:             meta.BucketID,
 		RedundancyIndex:      meta.RedundancyIndex,
 
-		SrcSPID:              meta.SrcSPID,
-		DestSPID:             meta.DestSPID,
-		LastMigratedObjectID: meta.LastMigratedObjectID,
-		MigrateStatus:        meta.MigrateStatus,
+		SrcSPID:               meta.SrcSPID,
+		DestSPID:              meta.DestSPID,
+		LastMigratedObjectID:  meta.LastMigratedObjectID,
+		MigrateStatus:         meta.MigrateStatus,
+		UpdateTimestampSecond: GetCurrentUnixTime(),
 	}
 	result = s.db.Create(insertMigrateGVG)
 	if result.Error != nil || result.RowsAffected != 1 {
@@ -306,7 +307,8 @@ func (s *SpDBImpl) DeleteMigrateGVGUnit(meta *spdb.MigrateGVGUnitMeta) error {
 
 func (s *SpDBImpl) UpdateMigrateGVGUnitStatus(migrateKey string, migrateStatus int) error {
 	if result := s.db.Model(&MigrateGVGTable{}).Where("migrate_key = ?", migrateKey).Updates(&MigrateGVGTable{
-		MigrateStatus: migrateStatus,
+		MigrateStatus:         migrateStatus,
+		UpdateTimestampSecond: GetCurrentUnixTime(),
 	}); result.Error != nil {
 		return fmt.Errorf("failed to update migrate gvg status: %s", result.Error)
 	}
@@ -315,7 +317,8 @@ func (s *SpDBImpl) UpdateMigrateGVGUnitStatus(migrateKey string, migrateStatus i
 
 func (s *SpDBImpl) UpdateMigrateGVGUnitLastMigrateObjectID(migrateKey string, lastMigratedObjectID uint64) error {
 	if result := s.db.Model(&MigrateGVGTable{}).Where("migrate_key = ?", migrateKey).Updates(&MigrateGVGTable{
-		LastMigratedObjectID: lastMigratedObjectID,
+		LastMigratedObjectID:  lastMigratedObjectID,
+		UpdateTimestampSecond: GetCurrentUnixTime(),
 	}); result.Error != nil {
 		return fmt.Errorf("failed to update migrate gvg progress: %s", result.Error)
 	}
@@ -350,6 +353,20 @@ func (s *SpDBImpl) ListMigrateGVGUnitsByBucketID(bucketID uint64) ([]*spdb.Migra
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to query migrate gvg table: %s", result.Error)
 	}
+	return toMigrateGVGUnitMetas(queryReturns), nil
+}
+
+// ListMigrateGVGUnits returns all the gvg migrate units of the bucket migrations, the swap outs and the sp exits.
+func (s *SpDBImpl) ListMigrateGVGUnits() ([]*spdb.MigrateGVGUnitMeta, error) {
+	var queryReturns []MigrateGVGTable
+	result := s.db.Find(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query migrate gvg table: %s", result.Error)
+	}
+	return toMigrateGVGUnitMetas(queryReturns), nil
+}
+
+func toMigrateGVGUnitMetas(queryReturns []MigrateGVGTable) []*spdb.MigrateGVGUnitMeta {
 	returns := make([]*spdb.MigrateGVGUnitMeta, 0)
 	for _, queryReturn := range queryReturns {
 		returns = append(returns, &spdb.MigrateGVGUnitMeta{
@@ -363,5 +380,58 @@ func (s *SpDBImpl) ListMigrateGVGUnitsByBucketID(bucketID uint64) ([]*spdb.Migra
 			MigrateStatus:        queryReturn.MigrateStatus,
 		})
 	}
-	return returns, nil
+	return returns
+}
+
+// DeleteMigratedBucketGVGUnits deletes the gvg units of the buckets whose units have all been migrated
+// before the expiredTimestampSecond, the number of the buckets is at most limit.
+func (s *SpDBImpl) DeleteMigratedBucketGVGUnits(expiredTimestampSecond int64, limit int) (int64, error) {
+	var bucketIDs []uint64
+	result := s.db.Model(&MigrateGVGTable{}).Select("bucket_id").Where("bucket_id != 0").Group("bucket_id").
+		Having("MIN(migrate_status) = ? and MAX(migrate_status) = ? and MAX(update_timestamp_second) < ?",
+			spdb.MigrateStatusMigrated, spdb.MigrateStatusMigrated, expiredTimestampSecond).
+		Limit(limit).Pluck("bucket_id", &bucketIDs)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to query migrated bucket: %s", result.Error)
+	}
+	if len(bucketIDs) == 0 {
+		return 0, nil
+	}
+	result = s.db.Where("bucket_id IN ?", bucketIDs).Delete(&MigrateGVGTable{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete migrated bucket gvg unit: %s", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteCompletedDestSPSwapOutUnits deletes the dest sp swap out units and their gvg units which have
+// all been migrated before the expiredTimestampSecond, the number of the swap out units is at most limit.
+func (s *SpDBImpl) DeleteCompletedDestSPSwapOutUnits(expiredTimestampSecond int64, limit int) (int64, error) {
+	var swapOutKeys []string
+	result := s.db.Model(&MigrateGVGTable{}).Select("swap_out_key").Where("swap_out_key != ''").Group("swap_out_key").
+		Having("MIN(migrate_status) = ? and MAX(migrate_status) = ? and MAX(update_timestamp_second) < ?",
+			spdb.MigrateStatusMigrated, spdb.MigrateStatusMigrated, expiredTimestampSecond).
+		Limit(limit).Pluck("swap_out_key", &swapOutKeys)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to query completed swap out: %s", result.Error)
+	}
+	if len(swapOutKeys) == 0 {
+		return 0, nil
+	}
+	var deleted int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result = tx.Where("swap_out_key IN ? and is_dest_sp = true", swapOutKeys).Delete(&SwapOutTable{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete completed swap out unit: %s", result.Error)
+		}
+		deleted = result.RowsAffected
+		if result = tx.Where("swap_out_key IN ?", swapOutKeys).Delete(&MigrateGVGTable{}); result.Error != nil {
+			return fmt.Errorf("failed to delete completed swap out gvg unit: %s", result.Error)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
 }
@@ -26,16 +26,17 @@ func (SwapOutTable) TableName() string {
 // MigrateGVGTable table schema.
 // sp exit, bucket migrate
 type MigrateGVGTable struct {
-	MigrateKey           string `gorm:"primary_key"`
-	SwapOutKey           string `gorm:"index:swap_out_index"`
-	GlobalVirtualGroupID uint32 `gorm:"index:gvg_index"`        // is used by sp exit/bucket migrate
-	VirtualGroupFamilyID uint32 `gorm:"index:vgf_index"`        // is used by sp exit
-	BucketID             uint64 `gorm:"index:bucket_index"`     // is used by bucket migrate
-	RedundancyIndex      int32  `gorm:"index:redundancy_index"` // is used by sp exit
-	SrcSPID              uint32
-	DestSPID             uint32
-	LastMigratedObjectID uint64
-	MigrateStatus        int `gorm:"index:migrate_status_index"`
+	MigrateKey            string `gorm:"primary_key"`
+	SwapOutKey            string `gorm:"index:swap_out_index"`
+	GlobalVirtualGroupID  uint32 `gorm:"index:gvg_index"`        // is used by sp exit/bucket migrate
+	VirtualGroupFamilyID  uint32 `gorm:"index:vgf_index"`        // is used by sp exit
+	BucketID              uint64 `gorm:"index"`                  // is used by bucket migrate
+	RedundancyIndex       int32  `gorm:"index:redundancy_index"` // is used by sp exit
+	SrcSPID               uint32
+	DestSPID              uint32
+	LastMigratedObjectID  uint64
+	MigrateStatus         int   `gorm:"index:migrate_status_index"`
+	UpdateTimestampSecond int64 `gorm:"index"`
 }
 
 // TableName is used to set MigrateGVGTable Schema's table name in database.
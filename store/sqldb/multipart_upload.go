@@ -0,0 +1,83 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+// UpdateMultipartUploadPart is used to record the uploaded part, the part which is uploaded
+// again overwrites the old record, the last recorded upload of the part wins.
+func (s *SpDBImpl) UpdateMultipartUploadPart(part *spdb.MultipartUploadPart) error {
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "object_id"}, {Name: "part_number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"upload_id", "size", "etag", "update_timestamp_second"}),
+	}).Create(&MultipartUploadPartTable{
+		ObjectID:              part.ObjectID,
+		PartNumber:            part.PartNumber,
+		UploadID:              part.UploadID,
+		Size:                  part.Size,
+		ETag:                  part.ETag,
+		UpdateTimestampSecond: GetCurrentUnixTime(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to upsert multipart upload part table: %s", result.Error)
+	}
+	return nil
+}
+
+// ListMultipartUploadParts returns the uploaded parts of the object in ascending order of part number.
+func (s *SpDBImpl) ListMultipartUploadParts(objectID uint64) ([]*spdb.MultipartUploadPart, error) {
+	var queryReturns []MultipartUploadPartTable
+	result := s.db.Where("object_id = ?", objectID).Order("part_number asc").Find(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query multipart upload part table: %s", result.Error)
+	}
+	parts := make([]*spdb.MultipartUploadPart, 0, len(queryReturns))
+	for _, part := range queryReturns {
+		parts = append(parts, &spdb.MultipartUploadPart{
+			ObjectID:              part.ObjectID,
+			PartNumber:            part.PartNumber,
+			UploadID:              part.UploadID,
+			Size:                  part.Size,
+			ETag:                  part.ETag,
+			UpdateTimestampSecond: part.UpdateTimestampSecond,
+		})
+	}
+	return parts, nil
+}
+
+// DeleteMultipartUploadParts deletes all the uploaded parts records of the object.
+func (s *SpDBImpl) DeleteMultipartUploadParts(objectID uint64) error {
+	result := s.db.Where("object_id = ?", objectID).Delete(&MultipartUploadPartTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete multipart upload part table: %s", result.Error)
+	}
+	return nil
+}
+
+// ListMultipartUploads returns at most limit multipart uploads whose object id is greater than
+// startAfterObjectID in ascending order of object id.
+func (s *SpDBImpl) ListMultipartUploads(startAfterObjectID uint64, limit int) ([]*spdb.MultipartUpload, error) {
+	var queryReturns []struct {
+		ObjectID              uint64
+		UpdateTimestampSecond int64
+	}
+	result := s.db.Model(&MultipartUploadPartTable{}).
+		Select("object_id, MAX(update_timestamp_second) AS update_timestamp_second").
+		Where("object_id > ?", startAfterObjectID).Group("object_id").Order("object_id asc").Limit(limit).
+		Scan(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query multipart upload part table: %s", result.Error)
+	}
+	uploads := make([]*spdb.MultipartUpload, 0, len(queryReturns))
+	for _, upload := range queryReturns {
+		uploads = append(uploads, &spdb.MultipartUpload{
+			ObjectID:              upload.ObjectID,
+			UpdateTimestampSecond: upload.UpdateTimestampSecond,
+		})
+	}
+	return uploads, nil
+}
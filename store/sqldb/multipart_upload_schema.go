@@ -0,0 +1,16 @@
+package sqldb
+
+// MultipartUploadPartTable table schema.
+type MultipartUploadPartTable struct {
+	ObjectID              uint64 `gorm:"primary_key;autoIncrement:false"`
+	PartNumber            uint32 `gorm:"primary_key;autoIncrement:false"`
+	UploadID              string `gorm:"size:32"`
+	Size                  uint64
+	ETag                  string `gorm:"column:etag"`
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set MultipartUploadPartTable Schema's table name in database.
+func (MultipartUploadPartTable) TableName() string {
+	return MultipartUploadPartTableName
+}
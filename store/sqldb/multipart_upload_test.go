@@ -0,0 +1,69 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+func TestSpDBImpl_MultipartUploadParts(t *testing.T) {
+	s := setupSpDBTest(t)
+	require.NoError(t, s.UpdateMultipartUploadPart(&spdb.MultipartUploadPart{ObjectID: 1, PartNumber: 2, Size: 20, ETag: "e2"}))
+	require.NoError(t, s.UpdateMultipartUploadPart(&spdb.MultipartUploadPart{ObjectID: 1, PartNumber: 1, Size: 10, ETag: "e1"}))
+	// the part uploaded again overwrites the old one
+	require.NoError(t, s.UpdateMultipartUploadPart(&spdb.MultipartUploadPart{ObjectID: 1, PartNumber: 2, UploadID: "u3",
+		Size: 30, ETag: "e3"}))
+	require.NoError(t, s.UpdateMultipartUploadPart(&spdb.MultipartUploadPart{ObjectID: 2, PartNumber: 1, Size: 10, ETag: "e1"}))
+
+	parts, err := s.ListMultipartUploadParts(1)
+	require.NoError(t, err)
+	require.Len(t, parts, 2)
+	assert.Equal(t, uint32(1), parts[0].PartNumber)
+	assert.Equal(t, uint32(2), parts[1].PartNumber)
+	assert.Equal(t, uint64(30), parts[1].Size)
+	assert.Equal(t, "e3", parts[1].ETag)
+	assert.Equal(t, "u3", parts[1].UploadID)
+	assert.NotZero(t, parts[1].UpdateTimestampSecond)
+
+	require.NoError(t, s.DeleteMultipartUploadParts(1))
+	parts, err = s.ListMultipartUploadParts(1)
+	require.NoError(t, err)
+	assert.Empty(t, parts)
+	parts, err = s.ListMultipartUploadParts(2)
+	require.NoError(t, err)
+	assert.Len(t, parts, 1)
+}
+
+func TestSpDBImpl_ListMultipartUploads(t *testing.T) {
+	s := setupSpDBTest(t)
+	uploads, err := s.ListMultipartUploads(0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, uploads)
+
+	for _, part := range []*MultipartUploadPartTable{
+		{ObjectID: 3, PartNumber: 1, UpdateTimestampSecond: 100},
+		{ObjectID: 3, PartNumber: 2, UpdateTimestampSecond: 300},
+		{ObjectID: 1, PartNumber: 1, UpdateTimestampSecond: 200},
+		{ObjectID: 5, PartNumber: 1, UpdateTimestampSecond: 400},
+	} {
+		require.NoError(t, s.db.Create(part).Error)
+	}
+
+	// the update time of the upload is the time of the latest part
+	uploads, err = s.ListMultipartUploads(0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*spdb.MultipartUpload{
+		{ObjectID: 1, UpdateTimestampSecond: 200},
+		{ObjectID: 3, UpdateTimestampSecond: 300},
+		{ObjectID: 5, UpdateTimestampSecond: 400},
+	}, uploads)
+	uploads, err = s.ListMultipartUploads(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []*spdb.MultipartUpload{{ObjectID: 3, UpdateTimestampSecond: 300}}, uploads)
+	uploads, err = s.ListMultipartUploads(5, 10)
+	require.NoError(t, err)
+	assert.Empty(t, uploads)
+}
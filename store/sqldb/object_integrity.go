@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
@@ -57,6 +56,14 @@ const (
 	SPDBSuccessDelAllReplicatePieceChecksum = "del_all_replicate_piece_checksum_success"
 	// SPDBFailureDelAllReplicatePieceChecksum defines the metrics label of unsuccessfully del all replicate piece checksum
 	SPDBFailureDelAllReplicatePieceChecksum = "del_all_replicate_piece_checksum_failure"
+	// SPDBSuccessListReplicatePieceChecksumObjectIDs defines the metrics label of successfully list replicate piece checksum object ids
+	SPDBSuccessListReplicatePieceChecksumObjectIDs = "list_replicate_piece_checksum_object_ids_success"
+	// SPDBFailureListReplicatePieceChecksumObjectIDs defines the metrics label of unsuccessfully list replicate piece checksum object ids
+	SPDBFailureListReplicatePieceChecksumObjectIDs = "list_replicate_piece_checksum_object_ids_failure"
+	// SPDBSuccessDelReplicatePieceChecksumByObjectIDs defines the metrics label of successfully del replicate piece checksum by object ids
+	SPDBSuccessDelReplicatePieceChecksumByObjectIDs = "del_replicate_piece_checksum_by_object_ids_success"
+	// SPDBFailureDelReplicatePieceChecksumByObjectIDs defines the metrics label of unsuccessfully del replicate piece checksum by object ids
+	SPDBFailureDelReplicatePieceChecksumByObjectIDs = "del_replicate_piece_checksum_by_object_ids_failure"
 )
 
 // GetObjectIntegrity returns the integrity hash info
@@ -104,18 +111,6 @@ func (s *SpDBImpl) GetObjectIntegrity(objectID uint64, redundancyIndex int32) (m
 	return meta, nil
 }
 
-func MysqlErrCode(err error) int {
-	mysqlErr, ok := err.(*mysql.MySQLError)
-	if !ok {
-		return 0
-	}
-	return int(mysqlErr.Number)
-}
-
-var (
-	ErrDuplicateEntryCode = 1062
-)
-
 // SetObjectIntegrity puts(overwrites) integrity hash info to db
 func (s *SpDBImpl) SetObjectIntegrity(meta *corespdb.IntegrityMeta) (err error) {
 	startTime := time.Now()
@@ -139,7 +134,7 @@ func (s *SpDBImpl) SetObjectIntegrity(meta *corespdb.IntegrityMeta) (err error)
 	}
 	shardTableName := GetIntegrityMetasTableName(meta.ObjectID)
 	result := s.db.Table(shardTableName).Create(insertIntegrityMetaRecord)
-	if result.Error != nil && MysqlErrCode(result.Error) == ErrDuplicateEntryCode {
+	if result.Error != nil && s.dialect.IsDuplicateEntry(result.Error) {
 		return nil
 	}
 	if result.Error != nil || result.RowsAffected != 1 {
@@ -303,7 +298,7 @@ func (s *SpDBImpl) SetReplicatePieceChecksum(objectID uint64, segmentIdx uint32,
 		PieceChecksum:   hex.EncodeToString(checksum),
 	}
 	result = s.db.Create(insertPieceHash)
-	if result.Error != nil && MysqlErrCode(result.Error) == ErrDuplicateEntryCode {
+	if result.Error != nil && s.dialect.IsDuplicateEntry(result.Error) {
 		return nil
 	}
 	if result.Error != nil || result.RowsAffected != 1 {
@@ -396,3 +391,52 @@ func (s *SpDBImpl) DeleteAllReplicatePieceChecksum(objectID uint64, redundancyId
 	}
 	return nil
 }
+
+// ListReplicatePieceChecksumObjectIDs lists the distinct object ids after startAfter which still have piece checksums.
+func (s *SpDBImpl) ListReplicatePieceChecksumObjectIDs(startAfter uint64, limit int) (objectIDs []uint64, err error) {
+	startTime := time.Now()
+	defer func() {
+		if err != nil {
+			metrics.SPDBCounter.WithLabelValues(SPDBFailureListReplicatePieceChecksumObjectIDs).Inc()
+			metrics.SPDBTime.WithLabelValues(SPDBFailureListReplicatePieceChecksumObjectIDs).Observe(
+				time.Since(startTime).Seconds())
+			return
+		}
+		metrics.SPDBCounter.WithLabelValues(SPDBSuccessListReplicatePieceChecksumObjectIDs).Inc()
+		metrics.SPDBTime.WithLabelValues(SPDBSuccessListReplicatePieceChecksumObjectIDs).Observe(
+			time.Since(startTime).Seconds())
+	}()
+
+	if err = s.db.Model(&PieceHashTable{}).Distinct("object_id").Where("object_id > ?", startAfter).
+		Order("object_id ASC").Limit(limit).Pluck("object_id", &objectIDs).Error; err != nil {
+		err = fmt.Errorf("failed to list piece hash object ids: %s", err)
+		return nil, err
+	}
+	return objectIDs, nil
+}
+
+// DeleteReplicatePieceChecksumByObjectIDs deletes all the piece checksums of the objects.
+func (s *SpDBImpl) DeleteReplicatePieceChecksumByObjectIDs(objectIDs []uint64) (deleted int64, err error) {
+	startTime := time.Now()
+	defer func() {
+		if err != nil {
+			metrics.SPDBCounter.WithLabelValues(SPDBFailureDelReplicatePieceChecksumByObjectIDs).Inc()
+			metrics.SPDBTime.WithLabelValues(SPDBFailureDelReplicatePieceChecksumByObjectIDs).Observe(
+				time.Since(startTime).Seconds())
+			return
+		}
+		metrics.SPDBCounter.WithLabelValues(SPDBSuccessDelReplicatePieceChecksumByObjectIDs).Inc()
+		metrics.SPDBTime.WithLabelValues(SPDBSuccessDelReplicatePieceChecksumByObjectIDs).Observe(
+			time.Since(startTime).Seconds())
+	}()
+
+	if len(objectIDs) == 0 {
+		return 0, nil
+	}
+	result := s.db.Where("object_id IN ?", objectIDs).Delete(&PieceHashTable{})
+	if result.Error != nil {
+		err = fmt.Errorf("failed to delete piece hash record: %s", result.Error)
+		return 0, err
+	}
+	return result.RowsAffected, nil
+}
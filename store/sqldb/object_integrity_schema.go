@@ -1,7 +1,7 @@
 package sqldb
 
 import (
-	"fmt"
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
 )
 
 const (
@@ -45,5 +45,5 @@ func GetIntegrityMetasShardNumberByBucketName(objectID uint64) uint64 {
 }
 
 func GetIntegrityMetasTableNameByShardNumber(shard int) string {
-	return fmt.Sprintf("%s_%02d", IntegrityMetaTableName, shard)
+	return dialect.ShardTableName(IntegrityMetaTableName, shard)
 }
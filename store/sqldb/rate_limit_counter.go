@@ -0,0 +1,76 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+// rateLimitCounterBatchSize defines the max number of the counters upserted by one statement.
+const rateLimitCounterBatchSize = 500
+
+// IncreaseRateLimitCounters adds the counts to the counters in a transaction, and returns the updated counts. The
+// counters with counts are upserted in one batch, the others are only read.
+func (s *SpDBImpl) IncreaseRateLimitCounters(counters []*spdb.RateLimitCounter) (map[string]int64, error) {
+	if len(counters) == 0 {
+		return map[string]int64{}, nil
+	}
+	var (
+		keys      = make([]string, 0, len(counters))
+		increases = make([]*RateLimitCounterTable, 0, len(counters))
+		records   []*RateLimitCounterTable
+	)
+	for _, counter := range counters {
+		keys = append(keys, counter.LimiterKey)
+		if counter.Count == 0 {
+			continue
+		}
+		increases = append(increases, &RateLimitCounterTable{
+			LimiterKey:            counter.LimiterKey,
+			Count:                 counter.Count,
+			ExpireTimestampSecond: counter.ExpireTimestampSecond,
+		})
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// the replicas may create the same counter at the same time, upsert makes the increase atomic
+		for start := 0; start < len(increases); start += rateLimitCounterBatchSize {
+			end := start + rateLimitCounterBatchSize
+			if end > len(increases) {
+				end = len(increases)
+			}
+			result := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "limiter_key"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"count": gorm.Expr(RateLimitCounterTableName + ".count + " + s.dialect.Excluded("count")),
+				}),
+			}).Create(increases[start:end])
+			if result.Error != nil {
+				return fmt.Errorf("failed to upsert records in rate limit counter table: %s", result.Error)
+			}
+		}
+		if result := tx.Where("limiter_key IN ?", keys).Find(&records); result.Error != nil {
+			return fmt.Errorf("failed to query rate limit counter table: %s", result.Error)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(records))
+	for _, record := range records {
+		counts[record.LimiterKey] = record.Count
+	}
+	return counts, nil
+}
+
+// DeleteExpiredRateLimitCounters deletes the counters which expire before the timestamp.
+func (s *SpDBImpl) DeleteExpiredRateLimitCounters(timestampSecond int64) error {
+	result := s.db.Where("expire_timestamp_second < ?", timestampSecond).Delete(&RateLimitCounterTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete records in rate limit counter table: %s", result.Error)
+	}
+	return nil
+}
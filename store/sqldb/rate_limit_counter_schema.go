@@ -0,0 +1,13 @@
+package sqldb
+
+// RateLimitCounterTable table schema, the limiter key contains the start of the window.
+type RateLimitCounterTable struct {
+	LimiterKey            string `gorm:"primary_key;size:256"`
+	Count                 int64
+	ExpireTimestampSecond int64 `gorm:"index:expire_timestamp_index"`
+}
+
+// TableName is used to set RateLimitCounterTable Schema's table name in database.
+func (RateLimitCounterTable) TableName() string {
+	return RateLimitCounterTableName
+}
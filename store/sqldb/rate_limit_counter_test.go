@@ -0,0 +1,36 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+func TestSpDBImpl_IncreaseRateLimitCounters(t *testing.T) {
+	s := setupSpDBTest(t)
+	counts, err := s.IncreaseRateLimitCounters([]*spdb.RateLimitCounter{
+		{LimiterKey: "ip_1_60", Count: 2, ExpireTimestampSecond: 120},
+		{LimiterKey: "ip_2_60", Count: 1, ExpireTimestampSecond: 120},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"ip_1_60": 2, "ip_2_60": 1}, counts)
+
+	// the counts of the other replica are added, and the counter without count is only read
+	counts, err = s.IncreaseRateLimitCounters([]*spdb.RateLimitCounter{
+		{LimiterKey: "ip_1_60", Count: 3, ExpireTimestampSecond: 120},
+		{LimiterKey: "ip_2_60", ExpireTimestampSecond: 120},
+		{LimiterKey: "ip_3_60", ExpireTimestampSecond: 120},
+		{LimiterKey: "account_1_0", Count: 1, ExpireTimestampSecond: 60},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"ip_1_60": 5, "ip_2_60": 1, "account_1_0": 1}, counts)
+
+	require.NoError(t, s.DeleteExpiredRateLimitCounters(100))
+	counts, err = s.IncreaseRateLimitCounters([]*spdb.RateLimitCounter{{LimiterKey: "ip_1_60"},
+		{LimiterKey: "account_1_0"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"ip_1_60": 5}, counts)
+}
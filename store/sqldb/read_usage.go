@@ -0,0 +1,236 @@
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/metrics"
+)
+
+const (
+	// SPDBSuccessRollupReadRecord defines the metrics label of successfully rollup read record
+	SPDBSuccessRollupReadRecord = "rollup_read_record_success"
+	// SPDBFailureRollupReadRecord defines the metrics label of unsuccessfully rollup read record
+	SPDBFailureRollupReadRecord = "rollup_read_record_failure"
+	// SPDBSuccessGetReadUsage defines the metrics label of successfully get read usage
+	SPDBSuccessGetReadUsage = "get_read_usage_success"
+	// SPDBFailureGetReadUsage defines the metrics label of unsuccessfully get read usage
+	SPDBFailureGetReadUsage = "get_read_usage_failure"
+
+	// readUsageRollupBatchSize defines the number of the rollups which are inserted in a batch
+	readUsageRollupBatchSize = 500
+)
+
+// readUsageRow is the aggregation result of the read records or the read usage rollups.
+type readUsageRow struct {
+	BucketID    uint64
+	ObjectID    uint64
+	UserAddress string
+	UsageKey    string
+	Name        string
+	PeriodStart int64
+	ReadSize    uint64
+	ReadCount   uint64
+}
+
+// alignReadUsageRange extends [startTimestampUs, endTimestampUs) to the whole hours.
+func alignReadUsageRange(startTimestampUs, endTimestampUs int64) (int64, int64) {
+	startTimestampUs -= startTimestampUs % corespdb.ReadUsageHourUs
+	if endTimestampUs%corespdb.ReadUsageHourUs != 0 {
+		endTimestampUs += corespdb.ReadUsageHourUs - endTimestampUs%corespdb.ReadUsageHourUs
+	}
+	return startTimestampUs, endTimestampUs
+}
+
+// RollupReadRecord rolls up the read records of the hours in [startTimestampUs, endTimestampUs), the rollups of
+// the hours are deleted and inserted again in a transaction, so rolling up the same hours repeatedly is safe.
+func (s *SpDBImpl) RollupReadRecord(startTimestampUs, endTimestampUs int64) (err error) {
+	startTime := time.Now()
+	defer func() {
+		if err != nil {
+			metrics.SPDBCounter.WithLabelValues(SPDBFailureRollupReadRecord).Inc()
+			metrics.SPDBTime.WithLabelValues(SPDBFailureRollupReadRecord).Observe(
+				time.Since(startTime).Seconds())
+			return
+		}
+		metrics.SPDBCounter.WithLabelValues(SPDBSuccessRollupReadRecord).Inc()
+		metrics.SPDBTime.WithLabelValues(SPDBSuccessRollupReadRecord).Observe(
+			time.Since(startTime).Seconds())
+	}()
+
+	startTimestampUs, endTimestampUs = alignReadUsageRange(startTimestampUs, endTimestampUs)
+	if startTimestampUs >= endTimestampUs {
+		return nil
+	}
+	hour := fmt.Sprintf("read_timestamp_us - read_timestamp_us %% %d AS period_start", corespdb.ReadUsageHourUs)
+	aggregate := func(dimension corespdb.ReadUsageDimension, columns, name string) ([]*ReadUsageRollupTable, error) {
+		var rows []*readUsageRow
+		result := s.db.Model(&ReadRecordTable{}).
+			Select(columns+", "+name+", "+hour+", SUM(read_size) AS read_size, COUNT(*) AS read_count").
+			Where("read_timestamp_us >= ? and read_timestamp_us < ?", startTimestampUs, endTimestampUs).
+			Group(columns + ", period_start").Scan(&rows)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to rollup read record table: %s", result.Error)
+		}
+		rollups := make([]*ReadUsageRollupTable, 0, len(rows))
+		for _, row := range rows {
+			rollup := &ReadUsageRollupTable{
+				Dimension:              string(dimension),
+				BucketID:               row.BucketID,
+				PeriodStartTimestampUs: row.PeriodStart,
+				Name:                   row.Name,
+				ReadSize:               row.ReadSize,
+				ReadCount:              row.ReadCount,
+			}
+			switch dimension {
+			case corespdb.ReadUsageByBucket:
+				rollup.UsageKey = strconv.FormatUint(row.BucketID, 10)
+			case corespdb.ReadUsageByObject:
+				rollup.UsageKey = strconv.FormatUint(row.ObjectID, 10)
+			case corespdb.ReadUsageByUser:
+				rollup.UsageKey = row.UserAddress
+			}
+			rollups = append(rollups, rollup)
+		}
+		return rollups, nil
+	}
+
+	var rollups []*ReadUsageRollupTable
+	for _, d := range []struct {
+		dimension corespdb.ReadUsageDimension
+		columns   string
+		name      string
+	}{
+		{corespdb.ReadUsageByBucket, "bucket_id", "MAX(bucket_name) AS name"},
+		{corespdb.ReadUsageByObject, "bucket_id, object_id", "MAX(object_name) AS name"},
+		{corespdb.ReadUsageByUser, "bucket_id, user_address", "'' AS name"},
+	} {
+		dimensionRollups, aggregateErr := aggregate(d.dimension, d.columns, d.name)
+		if aggregateErr != nil {
+			err = aggregateErr
+			return err
+		}
+		rollups = append(rollups, dimensionRollups...)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("period_start_timestamp_us >= ? and period_start_timestamp_us < ?",
+			startTimestampUs, endTimestampUs).Delete(&ReadUsageRollupTable{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete read usage rollup table: %s", result.Error)
+		}
+		if len(rollups) == 0 {
+			return nil
+		}
+		if result = tx.CreateInBatches(rollups, readUsageRollupBatchSize); result.Error != nil {
+			return fmt.Errorf("failed to insert read usage rollup table: %s", result.Error)
+		}
+		return nil
+	})
+	return err
+}
+
+// GetReadUsage returns the read usage of every hour or day, or the top consumers of the time range.
+func (s *SpDBImpl) GetReadUsage(query *corespdb.ReadUsageQuery) (usages []*corespdb.ReadUsage, err error) {
+	startTime := time.Now()
+	defer func() {
+		if err != nil {
+			metrics.SPDBCounter.WithLabelValues(SPDBFailureGetReadUsage).Inc()
+			metrics.SPDBTime.WithLabelValues(SPDBFailureGetReadUsage).Observe(
+				time.Since(startTime).Seconds())
+			return
+		}
+		metrics.SPDBCounter.WithLabelValues(SPDBSuccessGetReadUsage).Inc()
+		metrics.SPDBTime.WithLabelValues(SPDBSuccessGetReadUsage).Observe(
+			time.Since(startTime).Seconds())
+	}()
+
+	if query.PeriodUs < 0 || query.PeriodUs%corespdb.ReadUsageHourUs != 0 {
+		err = fmt.Errorf("invalid read usage period: %d", query.PeriodUs)
+		return nil, err
+	}
+	startTimestampUs, endTimestampUs := alignReadUsageRange(query.StartTimestampUs, query.EndTimestampUs)
+	db := s.db.Model(&ReadUsageRollupTable{}).
+		Where("dimension = ? and period_start_timestamp_us >= ? and period_start_timestamp_us < ?",
+			string(query.Dimension), startTimestampUs, endTimestampUs)
+	if query.BucketID != 0 {
+		db = db.Where("bucket_id = ?", query.BucketID)
+	}
+	// the usage of a user is summed up across the buckets, the usage keys of the other dimensions belong to a bucket
+	columns := "usage_key, MAX(bucket_id) AS bucket_id, MAX(name) AS name, SUM(read_size) AS read_size, " +
+		"SUM(read_count) AS read_count"
+	if query.PeriodUs > 0 {
+		db = db.Select(columns+", period_start_timestamp_us - period_start_timestamp_us % ? AS period_start",
+			query.PeriodUs).Group("usage_key, period_start").Order("period_start ASC, SUM(read_size) DESC")
+	} else {
+		db = db.Select(columns).Group("usage_key").Order("SUM(read_size) DESC")
+	}
+	if query.LimitNum > 0 {
+		db = db.Limit(query.LimitNum)
+	}
+	var rows []*readUsageRow
+	if result := db.Scan(&rows); result.Error != nil {
+		err = fmt.Errorf("failed to query read usage rollup table: %s", result.Error)
+		return nil, err
+	}
+	for _, row := range rows {
+		usage := &corespdb.ReadUsage{
+			Dimension:              query.Dimension,
+			UsageKey:               row.UsageKey,
+			BucketID:               row.BucketID,
+			Name:                   row.Name,
+			PeriodStartTimestampUs: row.PeriodStart,
+			ReadSize:               row.ReadSize,
+			ReadCount:              row.ReadCount,
+		}
+		if query.Dimension == corespdb.ReadUsageByUser {
+			usage.BucketID = query.BucketID
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// DeleteExpiredReadUsage deletes the rollups of the hours before the expiredTimestampUs.
+func (s *SpDBImpl) DeleteExpiredReadUsage(expiredTimestampUs int64) error {
+	result := s.db.Where("period_start_timestamp_us < ?", expiredTimestampUs).Delete(&ReadUsageRollupTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete expired read usage rollup: %s", result.Error)
+	}
+	return nil
+}
+
+// GetReadQuotaAlertPercent returns the highest threshold percent which has been notified for the bucket.
+func (s *SpDBImpl) GetReadQuotaAlertPercent(bucketID uint64) (uint32, error) {
+	var queryReturn ReadQuotaAlertTable
+	result := s.db.Where("bucket_id = ?", bucketID).First(&queryReturn)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to query read quota alert table: %s", result.Error)
+	}
+	return queryReturn.NotifiedPercent, nil
+}
+
+// UpdateReadQuotaAlertPercent updates the highest threshold percent which has been notified for the bucket.
+func (s *SpDBImpl) UpdateReadQuotaAlertPercent(bucketID uint64, percent uint32) error {
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "bucket_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"notified_percent", "modified_time"}),
+	}).Create(&ReadQuotaAlertTable{
+		BucketID:        bucketID,
+		NotifiedPercent: percent,
+		ModifiedTime:    time.Now(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to upsert read quota alert table: %s", result.Error)
+	}
+	return nil
+}
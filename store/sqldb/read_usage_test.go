@@ -0,0 +1,137 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+const readUsageTestDayUs = 100 * corespdb.ReadUsageDayUs
+
+// setupReadUsageTest inserts the read records of two buckets in the first two hours and the next day, and
+// rolls them up.
+func setupReadUsageTest(t *testing.T) *SpDBImpl {
+	s := setupSpDBTest(t)
+	hour := corespdb.ReadUsageHourUs
+	for _, record := range []*ReadRecordTable{
+		{BucketID: 1, BucketName: "b1", ObjectID: 11, ObjectName: "o11", UserAddress: "u1", ReadSize: 10,
+			ReadTimestampUs: readUsageTestDayUs + 1},
+		{BucketID: 1, BucketName: "b1", ObjectID: 11, ObjectName: "o11", UserAddress: "u2", ReadSize: 20,
+			ReadTimestampUs: readUsageTestDayUs + hour - 1},
+		{BucketID: 1, BucketName: "b1", ObjectID: 12, ObjectName: "o12", UserAddress: "u1", ReadSize: 30,
+			ReadTimestampUs: readUsageTestDayUs + hour},
+		{BucketID: 2, BucketName: "b2", ObjectID: 21, ObjectName: "o21", UserAddress: "u1", ReadSize: 100,
+			ReadTimestampUs: readUsageTestDayUs + hour + 1},
+		{BucketID: 1, BucketName: "b1", ObjectID: 11, ObjectName: "o11", UserAddress: "u2", ReadSize: 5,
+			ReadTimestampUs: readUsageTestDayUs + corespdb.ReadUsageDayUs},
+	} {
+		require.NoError(t, s.db.Create(record).Error)
+	}
+	// the range is extended to the whole hours, and rolling up again does not double the usage
+	require.NoError(t, s.RollupReadRecord(readUsageTestDayUs+1, readUsageTestDayUs+corespdb.ReadUsageDayUs+1))
+	require.NoError(t, s.RollupReadRecord(readUsageTestDayUs, readUsageTestDayUs+2*hour))
+	return s
+}
+
+func TestSpDBImpl_GetReadUsage(t *testing.T) {
+	s := setupReadUsageTest(t)
+	hour := corespdb.ReadUsageHourUs
+	day := corespdb.ReadUsageDayUs
+	testCases := []struct {
+		name         string
+		query        *corespdb.ReadUsageQuery
+		wantedUsages []*corespdb.ReadUsage
+	}{
+		{
+			name: "hourly usage of buckets",
+			query: &corespdb.ReadUsageQuery{Dimension: corespdb.ReadUsageByBucket, PeriodUs: hour,
+				StartTimestampUs: readUsageTestDayUs, EndTimestampUs: readUsageTestDayUs + 2*day},
+			wantedUsages: []*corespdb.ReadUsage{
+				{UsageKey: "1", BucketID: 1, Name: "b1", PeriodStartTimestampUs: readUsageTestDayUs, ReadSize: 30,
+					ReadCount: 2},
+				{UsageKey: "2", BucketID: 2, Name: "b2", PeriodStartTimestampUs: readUsageTestDayUs + hour,
+					ReadSize: 100, ReadCount: 1},
+				{UsageKey: "1", BucketID: 1, Name: "b1", PeriodStartTimestampUs: readUsageTestDayUs + hour,
+					ReadSize: 30, ReadCount: 1},
+				{UsageKey: "1", BucketID: 1, Name: "b1", PeriodStartTimestampUs: readUsageTestDayUs + day,
+					ReadSize: 5, ReadCount: 1},
+			},
+		},
+		{
+			name: "daily usage of a bucket",
+			query: &corespdb.ReadUsageQuery{Dimension: corespdb.ReadUsageByBucket, BucketID: 1, PeriodUs: day,
+				StartTimestampUs: readUsageTestDayUs, EndTimestampUs: readUsageTestDayUs + 2*day},
+			wantedUsages: []*corespdb.ReadUsage{
+				{UsageKey: "1", BucketID: 1, Name: "b1", PeriodStartTimestampUs: readUsageTestDayUs, ReadSize: 60,
+					ReadCount: 3},
+				{UsageKey: "1", BucketID: 1, Name: "b1", PeriodStartTimestampUs: readUsageTestDayUs + day,
+					ReadSize: 5, ReadCount: 1},
+			},
+		},
+		{
+			name: "top objects of a bucket",
+			query: &corespdb.ReadUsageQuery{Dimension: corespdb.ReadUsageByObject, BucketID: 1,
+				StartTimestampUs: readUsageTestDayUs, EndTimestampUs: readUsageTestDayUs + 2*day},
+			wantedUsages: []*corespdb.ReadUsage{
+				{UsageKey: "11", BucketID: 1, Name: "o11", ReadSize: 35, ReadCount: 3},
+				{UsageKey: "12", BucketID: 1, Name: "o12", ReadSize: 30, ReadCount: 1},
+			},
+		},
+		{
+			name: "top user across buckets",
+			query: &corespdb.ReadUsageQuery{Dimension: corespdb.ReadUsageByUser, LimitNum: 1,
+				StartTimestampUs: readUsageTestDayUs, EndTimestampUs: readUsageTestDayUs + 2*day},
+			wantedUsages: []*corespdb.ReadUsage{{UsageKey: "u1", ReadSize: 140, ReadCount: 3}},
+		},
+		{
+			name: "range extended to the whole hours",
+			query: &corespdb.ReadUsageQuery{Dimension: corespdb.ReadUsageByBucket, BucketID: 2,
+				StartTimestampUs: readUsageTestDayUs + hour + 2, EndTimestampUs: readUsageTestDayUs + hour + 3},
+			wantedUsages: []*corespdb.ReadUsage{{UsageKey: "2", BucketID: 2, Name: "b2", ReadSize: 100, ReadCount: 1}},
+		},
+		{
+			name: "no usage",
+			query: &corespdb.ReadUsageQuery{Dimension: corespdb.ReadUsageByBucket,
+				StartTimestampUs: readUsageTestDayUs + 2*day, EndTimestampUs: readUsageTestDayUs + 3*day},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			usages, err := s.GetReadUsage(tt.query)
+			require.NoError(t, err)
+			for _, usage := range tt.wantedUsages {
+				usage.Dimension = tt.query.Dimension
+			}
+			assert.Equal(t, tt.wantedUsages, usages)
+		})
+	}
+
+	_, err := s.GetReadUsage(&corespdb.ReadUsageQuery{Dimension: corespdb.ReadUsageByBucket, PeriodUs: hour + 1})
+	assert.Error(t, err)
+}
+
+func TestSpDBImpl_DeleteExpiredReadUsage(t *testing.T) {
+	s := setupReadUsageTest(t)
+	require.NoError(t, s.DeleteExpiredReadUsage(readUsageTestDayUs+corespdb.ReadUsageDayUs))
+	usages, err := s.GetReadUsage(&corespdb.ReadUsageQuery{Dimension: corespdb.ReadUsageByBucket,
+		StartTimestampUs: 0, EndTimestampUs: readUsageTestDayUs + 2*corespdb.ReadUsageDayUs})
+	require.NoError(t, err)
+	assert.Equal(t, []*corespdb.ReadUsage{{Dimension: corespdb.ReadUsageByBucket, UsageKey: "1", BucketID: 1,
+		Name: "b1", ReadSize: 5, ReadCount: 1}}, usages)
+}
+
+func TestSpDBImpl_ReadQuotaAlertPercent(t *testing.T) {
+	s := setupSpDBTest(t)
+	percent, err := s.GetReadQuotaAlertPercent(1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), percent)
+
+	require.NoError(t, s.UpdateReadQuotaAlertPercent(1, 80))
+	require.NoError(t, s.UpdateReadQuotaAlertPercent(1, 50))
+	percent, err = s.GetReadQuotaAlertPercent(1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(50), percent)
+}
@@ -0,0 +1,51 @@
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+// InsertS3AccessKey inserts a new access key of the s3 compatible api.
+func (s *SpDBImpl) InsertS3AccessKey(key *spdb.S3AccessKey) error {
+	result := s.db.Create(&S3AccessKeyTable{
+		AccessKeyID:           key.AccessKeyID,
+		SecretAccessKey:       key.SecretAccessKey,
+		AccountAddress:        key.AccountAddress,
+		CreateTimestampSecond: GetCurrentUnixTime(),
+	})
+	if result.Error != nil || result.RowsAffected != 1 {
+		return fmt.Errorf("failed to insert record in s3 access key table: %s", result.Error)
+	}
+	return nil
+}
+
+// GetS3AccessKey returns the access key by the access key id, returns (nil, nil) if it is not found in db.
+func (s *SpDBImpl) GetS3AccessKey(accessKeyID string) (*spdb.S3AccessKey, error) {
+	queryReturn := &S3AccessKeyTable{}
+	result := s.db.First(queryReturn, "access_key_id = ?", accessKeyID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query s3 access key table: %s", result.Error)
+	}
+	return &spdb.S3AccessKey{
+		AccessKeyID:           queryReturn.AccessKeyID,
+		SecretAccessKey:       queryReturn.SecretAccessKey,
+		AccountAddress:        queryReturn.AccountAddress,
+		CreateTimestampSecond: queryReturn.CreateTimestampSecond,
+	}, nil
+}
+
+// DeleteS3AccessKey deletes the access key by the access key id.
+func (s *SpDBImpl) DeleteS3AccessKey(accessKeyID string) error {
+	result := s.db.Where("access_key_id = ?", accessKeyID).Delete(&S3AccessKeyTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete record in s3 access key table: %s", result.Error)
+	}
+	return nil
+}
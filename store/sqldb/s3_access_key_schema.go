@@ -0,0 +1,14 @@
+package sqldb
+
+// S3AccessKeyTable table schema.
+type S3AccessKeyTable struct {
+	AccessKeyID           string `gorm:"primary_key;size:64"`
+	SecretAccessKey       string `gorm:"size:128"`
+	AccountAddress        string `gorm:"index:account_address_index;size:64"`
+	CreateTimestampSecond int64
+}
+
+// TableName is used to set S3AccessKeyTable Schema's table name in database.
+func (S3AccessKeyTable) TableName() string {
+	return S3AccessKeyTableName
+}
@@ -0,0 +1,229 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+)
+
+// SchemaMigration is a versioned change of the sp db schema. The migrations are applied in the ascending order
+// of the versions and rolled back in the descending order, a migration and its schema version row are committed
+// in one transaction on postgres and sqlite. MySQL commits every ddl statement implicitly, so a failed migration
+// may be partially applied there, Up and Down must be safe to run again on the partially migrated tables. Up can
+// backfill the data when a column changes its meaning, Down is nil if the migration can not be rolled back.
+type SchemaMigration struct {
+	Version     uint32
+	Description string
+	Up          func(tx *gorm.DB, d dialect.Dialect) error
+	Down        func(tx *gorm.DB, d dialect.Dialect) error
+}
+
+// SchemaMigrationStatus is the status of a schema migration in the sp db.
+type SchemaMigrationStatus struct {
+	Version                uint32
+	Description            string
+	Applied                bool
+	AppliedTimestampSecond int64
+}
+
+// schemaMigrations are the migrations known by the binary, which must be ordered by the consecutive versions
+// starting from 1. A migration creates and alters the tables by its own frozen copies of the table schemas, so
+// a version means the same schema whatever the live table schemas are. New tables and columns are not created
+// unless a migration is appended here, every change of the schema appends its own migration instead of altering
+// the frozen tables of the applied ones.
+var schemaMigrations = []*SchemaMigration{
+	baselineSchemaMigration,
+	scrubSchemaMigration,
+	gcZombieSchemaMigration,
+	taskLeaseSchemaMigration,
+	eventTimestampSchemaMigration,
+	multipartUploadIDSchemaMigration,
+}
+
+// LatestSchemaVersion returns the schema version expected by the binary.
+func LatestSchemaVersion() uint32 {
+	return schemaMigrations[len(schemaMigrations)-1].Version
+}
+
+// SchemaMigrator shows, applies and rolls back the schema migrations of the sp db.
+type SchemaMigrator struct {
+	db      *gorm.DB
+	dialect dialect.Dialect
+}
+
+// NewSchemaMigrator returns a schema migrator of the sp db, which does not check the schema version.
+func NewSchemaMigrator(config *config.SQLDBConfig) (*SchemaMigrator, error) {
+	LoadDBConfigFromEnv(config)
+	OverrideConfigVacancy(config)
+	db, d, err := dialect.Open(config, &gorm.Config{})
+	if err != nil {
+		log.Errorw("gorm failed to open db", "driver", config.Driver, "error", err)
+		return nil, err
+	}
+	return newSchemaMigrator(db, d), nil
+}
+
+func newSchemaMigrator(db *gorm.DB, d dialect.Dialect) *SchemaMigrator {
+	return &SchemaMigrator{db: db, dialect: d}
+}
+
+func (m *SchemaMigrator) prepare() error {
+	for i, migration := range schemaMigrations {
+		if migration.Version != uint32(i+1) {
+			return fmt.Errorf("schema migration versions are not consecutive, expect %d but got %d", i+1, migration.Version)
+		}
+	}
+	if err := m.db.AutoMigrate(&SchemaVersionTable{}); err != nil && !m.dialect.IsTableAlreadyExists(err) {
+		return fmt.Errorf("failed to create schema version table: %s", err)
+	}
+	return nil
+}
+
+// CurrentVersion returns the latest applied schema version of the sp db, 0 means no migration is applied.
+func (m *SchemaMigrator) CurrentVersion() (uint32, error) {
+	if err := m.prepare(); err != nil {
+		return 0, err
+	}
+	return m.currentVersion()
+}
+
+func (m *SchemaMigrator) currentVersion() (uint32, error) {
+	var version uint32
+	result := m.db.Model(&SchemaVersionTable{}).Select("COALESCE(MAX(version), 0)").Scan(&version)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to query schema version table: %s", result.Error)
+	}
+	return version, nil
+}
+
+// Status returns the status of all the schema migrations known by the binary.
+func (m *SchemaMigrator) Status() ([]*SchemaMigrationStatus, error) {
+	if err := m.prepare(); err != nil {
+		return nil, err
+	}
+	var rows []*SchemaVersionTable
+	if result := m.db.Find(&rows); result.Error != nil {
+		return nil, fmt.Errorf("failed to query schema version table: %s", result.Error)
+	}
+	applied := make(map[uint32]*SchemaVersionTable, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	status := make([]*SchemaMigrationStatus, 0, len(schemaMigrations))
+	for _, migration := range schemaMigrations {
+		s := &SchemaMigrationStatus{Version: migration.Version, Description: migration.Description}
+		if row, ok := applied[migration.Version]; ok {
+			s.Applied = true
+			s.AppliedTimestampSecond = row.AppliedTimestampSecond
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// Apply applies the schema migrations after the current version up to the target version, 0 means the
+// latest version.
+func (m *SchemaMigrator) Apply(target uint32) error {
+	if err := m.prepare(); err != nil {
+		return err
+	}
+	if target == 0 {
+		target = LatestSchemaVersion()
+	}
+	if target > LatestSchemaVersion() {
+		return fmt.Errorf("target schema version %d is newer than the latest version %d", target, LatestSchemaVersion())
+	}
+	current, err := m.currentVersion()
+	if err != nil {
+		return err
+	}
+	if target < current {
+		return fmt.Errorf("target schema version %d is older than the current version %d", target, current)
+	}
+	for _, migration := range schemaMigrations[current:target] {
+		if err = m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx, m.dialect); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaVersionTable{
+				Version:                migration.Version,
+				Description:            migration.Description,
+				AppliedTimestampSecond: GetCurrentUnixTime(),
+			}).Error
+		}); err != nil {
+			return fmt.Errorf("failed to apply schema migration %d: %w", migration.Version, err)
+		}
+		log.Infow("succeed to apply schema migration", "version", migration.Version, "description", migration.Description)
+	}
+	return nil
+}
+
+// Rollback rolls back the schema migrations from the current version down to the target version, the
+// target version itself is kept.
+func (m *SchemaMigrator) Rollback(target uint32) error {
+	if err := m.prepare(); err != nil {
+		return err
+	}
+	current, err := m.currentVersion()
+	if err != nil {
+		return err
+	}
+	if target >= current {
+		return fmt.Errorf("target schema version %d is not older than the current version %d", target, current)
+	}
+	if current > LatestSchemaVersion() {
+		return fmt.Errorf("current schema version %d is unknown by the binary, the latest version is %d", current, LatestSchemaVersion())
+	}
+	for i := current; i > target; i-- {
+		migration := schemaMigrations[i-1]
+		if migration.Down == nil {
+			return fmt.Errorf("schema migration %d can not be rolled back", migration.Version)
+		}
+		if err = m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx, m.dialect); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaVersionTable{Version: migration.Version}).Error
+		}); err != nil {
+			return fmt.Errorf("failed to roll back schema migration %d: %w", migration.Version, err)
+		}
+		log.Infow("succeed to roll back schema migration", "version", migration.Version, "description", migration.Description)
+	}
+	return nil
+}
+
+// checkSchemaVersion refuses to start with a sp db whose schema version is not the one expected by the binary.
+// A new sp db is migrated to the latest version, and an existing sp db must be migrated by the migrate command.
+func (m *SchemaMigrator) checkSchemaVersion() error {
+	if err := m.prepare(); err != nil {
+		return err
+	}
+	current, err := m.currentVersion()
+	if err != nil {
+		return err
+	}
+	if current == 0 && !m.db.Migrator().HasTable(&UploadObjectProgressTable{}) {
+		if err = m.Apply(0); err != nil {
+			// the other sp services may initialize the new sp db at the same time
+			if !m.dialect.IsDuplicateEntry(err) {
+				return err
+			}
+		}
+		if current, err = m.currentVersion(); err != nil {
+			return err
+		}
+	}
+	if current < LatestSchemaVersion() {
+		return fmt.Errorf("sp db schema version %d is older than the version %d expected by the binary, "+
+			"run the spdb.migrate.apply command first", current, LatestSchemaVersion())
+	}
+	if current > LatestSchemaVersion() {
+		return fmt.Errorf("sp db schema version %d is newer than the version %d expected by the binary, "+
+			"run the spdb.migrate.rollback command of the newer binary first", current, LatestSchemaVersion())
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+)
+
+// baselineSchemaMigration creates the tables of the sp db, the sp dbs created before the schema migrations
+// were introduced are upgraded by it too.
+var baselineSchemaMigration = &SchemaMigration{
+	Version:     1,
+	Description: "create the baseline tables",
+	Up:          baselineUp,
+}
+
+// baselineTables are the frozen tables auto migrated by the baseline, the integrity meta shard tables are
+// migrated separately.
+var baselineTables = []interface{}{
+	&baselineUploadObjectProgressTable{},
+	&baselinePutObjectSuccessTable{},
+	&baselinePutObjectEventTable{},
+	&baselineUploadTimeoutTable{},
+	&baselineUploadFailedTable{},
+	&baselineReplicateTimeoutTable{},
+	&baselineReplicateFailedTable{},
+	&baselineSealTimeoutTable{},
+	&baselineSealFailedTable{},
+	&baselineGCObjectProgressTable{},
+	&baselineGCMetaProgressTable{},
+	&baselineMultipartUploadPartTable{},
+	&baselineTaskQueueTable{},
+	&baselineS3AccessKeyTable{},
+	&baselineBucketCORSTable{},
+	&baselineRateLimitCounterTable{},
+	&baselineEventSubscriptionTable{},
+	&baselineEventDeliveryTable{},
+	&baselineEventDeadLetterTable{},
+	&baselineSpInfoTable{},
+	&baselinePieceHashTable{},
+	&baselineBucketTrafficTable{},
+	&baselineReadRecordTable{},
+	&baselineReadUsageRollupTable{},
+	&baselineReadQuotaAlertTable{},
+	&baselineOffChainAuthKeyTable{},
+	&baselineMigrateSubscribeProgressTable{},
+	&baselineSwapOutTable{},
+	&baselineMigrateGVGTable{},
+}
+
+func baselineUp(tx *gorm.DB, d dialect.Dialect) error {
+	if err := renameLegacyIndexes(tx, d); err != nil {
+		return fmt.Errorf("failed to rename legacy indexes: %s", err)
+	}
+	for _, table := range baselineTables {
+		if err := tx.AutoMigrate(table); err != nil && !d.IsTableAlreadyExists(err) {
+			return fmt.Errorf("failed to create %T table: %s", table, err)
+		}
+	}
+	for i := 0; i < IntegrityMetasNumberOfShards; i++ {
+		shardTableName := GetIntegrityMetasTableNameByShardNumber(i)
+		if err := tx.Table(shardTableName).AutoMigrate(&baselineIntegrityMetaTable{}); err != nil && !d.IsTableAlreadyExists(err) {
+			return fmt.Errorf("failed to create integrity meta table %s: %s", shardTableName, err)
+		}
+	}
+	return nil
+}
+
+// legacyIndexNames are the index names which were shared by several tables, they are renamed to the
+// table unique names generated by gorm, because index names are unique in a postgres or sqlite database.
+var legacyIndexNames = map[string]string{
+	"update_time":             "update_time_index",
+	"object_id":               "object_id_index",
+	"bucket":                  "bucket_index",
+	"bucket_id":               "bucket_index",
+	"object":                  "object_index",
+	"update_timestamp_second": "update_timestamp_index",
+}
+
+// renameLegacyIndexes renames the legacy indexes of the existing mysql tables before auto migrating,
+// otherwise the indexes are created again under the new names.
+func renameLegacyIndexes(db *gorm.DB, d dialect.Dialect) error {
+	if d.Driver() != dialect.MySQLDriver {
+		return nil
+	}
+	models := []interface{}{&baselineUploadObjectProgressTable{}, &baselinePutObjectSuccessTable{},
+		&baselinePutObjectEventTable{}, &baselineUploadTimeoutTable{}, &baselineUploadFailedTable{},
+		&baselineReplicateTimeoutTable{}, &baselineReplicateFailedTable{}, &baselineSealTimeoutTable{},
+		&baselineSealFailedTable{}, &baselineGCObjectProgressTable{}, &baselineMigrateGVGTable{}}
+	migrator := db.Migrator()
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		for _, index := range stmt.Schema.ParseIndexes() {
+			if len(index.Fields) != 1 {
+				continue
+			}
+			legacyName, ok := legacyIndexNames[index.Fields[0].DBName]
+			if !ok || !migrator.HasIndex(model, legacyName) {
+				continue
+			}
+			if err := migrator.RenameIndex(model, legacyName, index.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,424 @@
+package sqldb
+
+import "time"
+
+// The tables below are the frozen schemas of the baseline migration, they are copied from the table schemas at
+// version 1 and must never be changed, otherwise the baseline creates different tables on the new sp dbs. The
+// later changes of the table schemas are made by appending schema migrations.
+
+type baselineUploadObjectProgressTable struct {
+	ObjectID              uint64 `gorm:"primary_key"`
+	TaskState             int32  `gorm:"index:state_index"`
+	GlobalVirtualGroupID  uint32
+	TaskStateDescription  string
+	ErrorDescription      string
+	SecondaryEndpoints    string
+	SecondarySignatures   string
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64 `gorm:"index"`
+}
+
+func (baselineUploadObjectProgressTable) TableName() string {
+	return "upload_object_progress"
+}
+
+type baselinePutObjectSuccessTable struct {
+	ID         uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime string `gorm:"index"`
+	ObjectID   uint64 `gorm:"index"`
+	Bucket     string `gorm:"index"`
+	Object     string `gorm:"index"`
+	State      string
+	Error      string
+	Logs       string
+}
+
+func (baselinePutObjectSuccessTable) TableName() string {
+	return "put_object_success_event_log"
+}
+
+type baselinePutObjectEventTable struct {
+	ID         uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime string `gorm:"index"`
+	ObjectID   uint64 `gorm:"index"`
+	Bucket     string `gorm:"index"`
+	Object     string `gorm:"index"`
+	State      string
+	Error      string
+	Logs       string
+}
+
+func (baselinePutObjectEventTable) TableName() string {
+	return "put_object_event_log"
+}
+
+type baselineUploadTimeoutTable struct {
+	ID         uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime string `gorm:"index"`
+	ObjectID   uint64 `gorm:"index"`
+	Bucket     string `gorm:"index"`
+	Object     string `gorm:"index"`
+	Error      string
+	Logs       string
+}
+
+func (baselineUploadTimeoutTable) TableName() string {
+	return "upload_timeout_event_log"
+}
+
+type baselineUploadFailedTable struct {
+	ID         uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime string `gorm:"index"`
+	ObjectID   uint64 `gorm:"index"`
+	Bucket     string `gorm:"index"`
+	Object     string `gorm:"index"`
+	Error      string
+	Logs       string
+}
+
+func (baselineUploadFailedTable) TableName() string {
+	return "upload_failed_event_log"
+}
+
+type baselineReplicateTimeoutTable struct {
+	ID         uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime string `gorm:"index"`
+	ObjectID   uint64 `gorm:"index"`
+	Bucket     string `gorm:"index"`
+	Object     string `gorm:"index"`
+	Error      string
+	Logs       string
+}
+
+func (baselineReplicateTimeoutTable) TableName() string {
+	return "replicate_timeout_event_log"
+}
+
+type baselineReplicateFailedTable struct {
+	ID         uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime string `gorm:"index"`
+	ObjectID   uint64 `gorm:"index"`
+	Bucket     string `gorm:"index"`
+	Object     string `gorm:"index"`
+	Error      string
+	Logs       string
+}
+
+func (baselineReplicateFailedTable) TableName() string {
+	return "replicate_failed_event_log"
+}
+
+type baselineSealTimeoutTable struct {
+	ID         uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime string `gorm:"index"`
+	ObjectID   uint64 `gorm:"index"`
+	Bucket     string `gorm:"index"`
+	Object     string `gorm:"index"`
+	Error      string
+	Logs       string
+}
+
+func (baselineSealTimeoutTable) TableName() string {
+	return "seal_timeout_event_log"
+}
+
+type baselineSealFailedTable struct {
+	ID         uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime string `gorm:"index"`
+	ObjectID   uint64 `gorm:"index"`
+	Bucket     string `gorm:"index"`
+	Object     string `gorm:"index"`
+	Error      string
+	Logs       string
+}
+
+func (baselineSealFailedTable) TableName() string {
+	return "seal_failed_event_log"
+}
+
+type baselineGCObjectProgressTable struct {
+	TaskKey               string `gorm:"primary_key"`
+	StartGCBlockID        uint64
+	EndGCBlockID          uint64
+	CurrentGCBlockID      uint64
+	LastDeletedObjectID   uint64
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64 `gorm:"index"`
+}
+
+func (baselineGCObjectProgressTable) TableName() string {
+	return "gc_object_progress"
+}
+
+type baselineGCMetaProgressTable struct {
+	TaskName              string `gorm:"primary_key"`
+	CurrentIdx            uint64
+	LastObjectID          uint64
+	UpdateTimestampSecond int64
+}
+
+func (baselineGCMetaProgressTable) TableName() string {
+	return "gc_meta_progress"
+}
+
+type baselineMultipartUploadPartTable struct {
+	ObjectID              uint64 `gorm:"primary_key;autoIncrement:false"`
+	PartNumber            uint32 `gorm:"primary_key;autoIncrement:false"`
+	Size                  uint64
+	ETag                  string `gorm:"column:etag"`
+	UpdateTimestampSecond int64
+}
+
+func (baselineMultipartUploadPartTable) TableName() string {
+	return "multipart_upload_part"
+}
+
+type baselineTaskQueueTable struct {
+	QueueName             string `gorm:"primary_key;size:64"`
+	TaskKeyHash           string `gorm:"primary_key;size:64"`
+	TaskKey               string
+	TaskType              int32
+	Priority              uint32
+	Retry                 int64
+	MaxRetry              int64
+	Logs                  string
+	TaskData              []byte
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64
+}
+
+func (baselineTaskQueueTable) TableName() string {
+	return "task_queue"
+}
+
+type baselineS3AccessKeyTable struct {
+	AccessKeyID           string `gorm:"primary_key;size:64"`
+	SecretAccessKey       string `gorm:"size:128"`
+	AccountAddress        string `gorm:"index:account_address_index;size:64"`
+	CreateTimestampSecond int64
+}
+
+func (baselineS3AccessKeyTable) TableName() string {
+	return "s3_access_key"
+}
+
+type baselineBucketCORSTable struct {
+	BucketName            string `gorm:"primary_key;size:64"`
+	AllowedOrigins        string `gorm:"size:4096"`
+	AllowedMethods        string `gorm:"size:256"`
+	AllowedHeaders        string `gorm:"size:4096"`
+	ExposedHeaders        string `gorm:"size:4096"`
+	AllowCredentials      bool
+	MaxAgeSec             int64
+	UpdateTimestampSecond int64
+}
+
+func (baselineBucketCORSTable) TableName() string {
+	return "bucket_cors"
+}
+
+type baselineRateLimitCounterTable struct {
+	LimiterKey            string `gorm:"primary_key;size:256"`
+	Count                 int64
+	ExpireTimestampSecond int64 `gorm:"index:expire_timestamp_index"`
+}
+
+func (baselineRateLimitCounterTable) TableName() string {
+	return "rate_limit_counter"
+}
+
+type baselineEventSubscriptionTable struct {
+	BucketName            string `gorm:"primary_key;size:64"`
+	SubscriptionID        string `gorm:"primary_key;size:64"`
+	Endpoint              string `gorm:"size:1024"`
+	Secret                string `gorm:"size:256"`
+	EventTypes            string `gorm:"size:256"`
+	UpdateTimestampSecond int64
+}
+
+func (baselineEventSubscriptionTable) TableName() string {
+	return "event_subscription"
+}
+
+type baselineEventDeliveryTable struct {
+	DeliveryID                 uint64 `gorm:"primary_key;autoIncrement"`
+	BucketName                 string `gorm:"size:64"`
+	SubscriptionID             string `gorm:"size:64"`
+	EventType                  string `gorm:"size:64"`
+	Payload                    string `gorm:"type:text"`
+	Attempts                   int
+	NextAttemptTimestampSecond int64  `gorm:"index:next_attempt_index"`
+	LastError                  string `gorm:"size:1024"`
+	CreateTimestampSecond      int64
+}
+
+func (baselineEventDeliveryTable) TableName() string {
+	return "event_delivery"
+}
+
+type baselineEventDeadLetterTable struct {
+	DeliveryID            uint64 `gorm:"primary_key;autoIncrement:false"`
+	BucketName            string `gorm:"size:64;index:bucket_to_dead_letter"`
+	SubscriptionID        string `gorm:"size:64"`
+	EventType             string `gorm:"size:64"`
+	Payload               string `gorm:"type:text"`
+	Attempts              int
+	LastError             string `gorm:"size:1024"`
+	CreateTimestampSecond int64
+	DeadTimestampSecond   int64
+}
+
+func (baselineEventDeadLetterTable) TableName() string {
+	return "event_dead_letter"
+}
+
+type baselineSpInfoTable struct {
+	OperatorAddress string `gorm:"primary_key"`
+	IsOwn           bool   `gorm:"primary_key"`
+	ID              uint32
+	FundingAddress  string
+	SealAddress     string
+	ApprovalAddress string
+	TotalDeposit    string
+	Status          int32
+	Endpoint        string
+	Moniker         string
+	Identity        string
+	Website         string
+	SecurityContact string
+	Details         string
+}
+
+func (baselineSpInfoTable) TableName() string {
+	return "sp_info"
+}
+
+type baselinePieceHashTable struct {
+	ObjectID        uint64 `gorm:"primary_key"`
+	SegmentIndex    uint32 `gorm:"primary_key"`
+	RedundancyIndex int32  `gorm:"primary_key"`
+	PieceChecksum   string
+}
+
+func (baselinePieceHashTable) TableName() string {
+	return "piece_hash"
+}
+
+type baselineBucketTrafficTable struct {
+	BucketID              uint64 `gorm:"primary_key"`
+	BucketName            string
+	ReadConsumedSize      uint64
+	FreeQuotaConsumedSize uint64
+	FreeQuotaSize         uint64
+	ChargedQuotaSize      uint64
+	ModifiedTime          time.Time
+}
+
+func (baselineBucketTrafficTable) TableName() string {
+	return "bucket_traffic"
+}
+
+type baselineReadRecordTable struct {
+	ReadRecordID    uint64 `gorm:"primary_key;autoIncrement"`
+	BucketID        uint64 `gorm:"index:bucket_to_read_record"`
+	ObjectID        uint64 `gorm:"index:object_to_read_record"`
+	UserAddress     string `gorm:"index:user_to_read_record"`
+	ReadTimestampUs int64  `gorm:"index:time_to_read_record"`
+	BucketName      string
+	ObjectName      string
+	ReadSize        uint64
+}
+
+func (baselineReadRecordTable) TableName() string {
+	return "read_record"
+}
+
+type baselineReadUsageRollupTable struct {
+	Dimension              string `gorm:"primary_key;size:16"`
+	BucketID               uint64 `gorm:"primary_key;autoIncrement:false"`
+	UsageKey               string `gorm:"primary_key;size:128"`
+	PeriodStartTimestampUs int64  `gorm:"primary_key;autoIncrement:false;index:period_to_read_usage"`
+	Name                   string
+	ReadSize               uint64
+	ReadCount              uint64
+}
+
+func (baselineReadUsageRollupTable) TableName() string {
+	return "read_usage_rollup"
+}
+
+type baselineReadQuotaAlertTable struct {
+	BucketID        uint64 `gorm:"primary_key;autoIncrement:false"`
+	NotifiedPercent uint32
+	ModifiedTime    time.Time
+}
+
+func (baselineReadQuotaAlertTable) TableName() string {
+	return "read_quota_alert"
+}
+
+type baselineOffChainAuthKeyTable struct {
+	UserAddress string `gorm:"primary_key"`
+	Domain      string `gorm:"primary_key"`
+
+	CurrentNonce     int32
+	CurrentPublicKey string
+	NextNonce        int32
+	ExpiryDate       time.Time
+
+	CreatedTime  time.Time
+	ModifiedTime time.Time
+}
+
+func (baselineOffChainAuthKeyTable) TableName() string {
+	return "off_chain_auth_key"
+}
+
+type baselineMigrateSubscribeProgressTable struct {
+	EventName                 string `gorm:"primary_key"`
+	LastSubscribedBlockHeight uint64
+}
+
+func (baselineMigrateSubscribeProgressTable) TableName() string {
+	return "migrate_subscribe_progress"
+}
+
+type baselineSwapOutTable struct {
+	SwapOutKey       string `gorm:"primary_key"`
+	IsDestSP         bool   `gorm:"primary_key"`
+	SwapOutMsg       string
+	CompletedGVGList string
+}
+
+func (baselineSwapOutTable) TableName() string {
+	return "swap_out_unit"
+}
+
+type baselineMigrateGVGTable struct {
+	MigrateKey            string `gorm:"primary_key"`
+	SwapOutKey            string `gorm:"index:swap_out_index"`
+	GlobalVirtualGroupID  uint32 `gorm:"index:gvg_index"`
+	VirtualGroupFamilyID  uint32 `gorm:"index:vgf_index"`
+	BucketID              uint64 `gorm:"index"`
+	RedundancyIndex       int32  `gorm:"index:redundancy_index"`
+	SrcSPID               uint32
+	DestSPID              uint32
+	LastMigratedObjectID  uint64
+	MigrateStatus         int   `gorm:"index:migrate_status_index"`
+	UpdateTimestampSecond int64 `gorm:"index"`
+}
+
+func (baselineMigrateGVGTable) TableName() string {
+	return "migrate_gvg"
+}
+
+type baselineIntegrityMetaTable struct {
+	ObjectID          uint64 `gorm:"primary_key"`
+	RedundancyIndex   int32  `gorm:"primary_key"`
+	IntegrityChecksum string
+	PieceChecksumList string
+}
+
+func (baselineIntegrityMetaTable) TableName() string {
+	return "integrity_meta"
+}
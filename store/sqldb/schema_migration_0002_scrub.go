@@ -0,0 +1,73 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+)
+
+// scrubSchemaMigration creates the tables which record the progress and the bad pieces of scrubbing.
+var scrubSchemaMigration = &SchemaMigration{
+	Version:     2,
+	Description: "create the scrub progress and scrub piece tables",
+	Up:          scrubUp,
+	Down:        scrubDown,
+}
+
+// scrubTables are the frozen tables created by the migration.
+var scrubTables = []interface{}{
+	&scrubMigrationProgressTable{},
+	&scrubMigrationPieceTable{},
+}
+
+type scrubMigrationProgressTable struct {
+	GlobalVirtualGroupID  uint32 `gorm:"primary_key;autoIncrement:false"`
+	RedundancyIndex       int32
+	LastObjectID          uint64
+	ScrubbedObjectCount   uint64
+	ScrubbedPieceCount    uint64
+	CorruptedPieceCount   uint64
+	MissingPieceCount     uint64
+	Finished              bool
+	StartTimestampSecond  int64
+	UpdateTimestampSecond int64
+}
+
+func (scrubMigrationProgressTable) TableName() string {
+	return "scrub_progress"
+}
+
+type scrubMigrationPieceTable struct {
+	PieceKey              string `gorm:"primary_key;size:256"`
+	GlobalVirtualGroupID  uint32 `gorm:"index:gvg_to_scrub_piece"`
+	ObjectID              uint64
+	SegmentIndex          uint32
+	RedundancyIndex       int32
+	State                 string `gorm:"size:16"`
+	Recovering            bool
+	DetectTimestampSecond int64
+}
+
+func (scrubMigrationPieceTable) TableName() string {
+	return "scrub_piece"
+}
+
+func scrubUp(tx *gorm.DB, d dialect.Dialect) error {
+	for _, table := range scrubTables {
+		if err := tx.AutoMigrate(table); err != nil && !d.IsTableAlreadyExists(err) {
+			return fmt.Errorf("failed to create %T table: %s", table, err)
+		}
+	}
+	return nil
+}
+
+func scrubDown(tx *gorm.DB, _ dialect.Dialect) error {
+	for _, table := range scrubTables {
+		if err := tx.Migrator().DropTable(table); err != nil {
+			return fmt.Errorf("failed to drop %T table: %s", table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+)
+
+// gcZombieSchemaMigration creates the table which records the progress of gc zombie piece tasks, so the
+// scanning is resumed from the last piece key after the manager restarts.
+var gcZombieSchemaMigration = &SchemaMigration{
+	Version:     3,
+	Description: "create the gc zombie progress table",
+	Up:          gcZombieUp,
+	Down:        gcZombieDown,
+}
+
+// gcZombieMigrationProgressTable is the frozen table created by the migration.
+type gcZombieMigrationProgressTable struct {
+	TaskName              string `gorm:"primary_key"`
+	LastPieceKey          string `gorm:"size:256"`
+	UpdateTimestampSecond int64
+}
+
+func (gcZombieMigrationProgressTable) TableName() string {
+	return "gc_zombie_progress"
+}
+
+func gcZombieUp(tx *gorm.DB, d dialect.Dialect) error {
+	if err := tx.AutoMigrate(&gcZombieMigrationProgressTable{}); err != nil && !d.IsTableAlreadyExists(err) {
+		return fmt.Errorf("failed to create gc zombie progress table: %s", err)
+	}
+	return nil
+}
+
+func gcZombieDown(tx *gorm.DB, _ dialect.Dialect) error {
+	if err := tx.Migrator().DropTable(&gcZombieMigrationProgressTable{}); err != nil {
+		return fmt.Errorf("failed to drop gc zombie progress table: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+)
+
+// taskLeaseSchemaMigration adds the lease column to the task queue table, the dispatched task is leased
+// instead of deleted, so it is loaded again after the manager restarts.
+var taskLeaseSchemaMigration = &SchemaMigration{
+	Version:     4,
+	Description: "add the lease timestamp column to the task queue table",
+	Up:          taskLeaseUp,
+	Down:        taskLeaseDown,
+}
+
+// taskLeaseMigrationTaskQueueTable is the frozen task queue table after the migration.
+type taskLeaseMigrationTaskQueueTable struct {
+	QueueName             string `gorm:"primary_key;size:64"`
+	TaskKeyHash           string `gorm:"primary_key;size:64"`
+	TaskKey               string
+	TaskType              int32
+	Priority              uint32
+	Retry                 int64
+	MaxRetry              int64
+	Logs                  string
+	TaskData              []byte
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64
+	LeaseTimestampSecond  int64
+}
+
+func (taskLeaseMigrationTaskQueueTable) TableName() string {
+	return "task_queue"
+}
+
+func taskLeaseUp(tx *gorm.DB, _ dialect.Dialect) error {
+	table := &taskLeaseMigrationTaskQueueTable{}
+	if tx.Migrator().HasColumn(table, "LeaseTimestampSecond") {
+		return nil
+	}
+	if err := tx.Migrator().AddColumn(table, "LeaseTimestampSecond"); err != nil {
+		return fmt.Errorf("failed to add lease timestamp column to task queue table: %s", err)
+	}
+	return nil
+}
+
+func taskLeaseDown(tx *gorm.DB, _ dialect.Dialect) error {
+	table := &taskLeaseMigrationTaskQueueTable{}
+	if !tx.Migrator().HasColumn(table, "LeaseTimestampSecond") {
+		return nil
+	}
+	if err := tx.Migrator().DropColumn(table, "LeaseTimestampSecond"); err != nil {
+		return fmt.Errorf("failed to drop lease timestamp column of task queue table: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package sqldb
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+)
+
+// eventTimestampSchemaMigration adds the update timestamp column to the event log tables, the expired event
+// logs are collected by the typed timestamp instead of the update time string recorded in the local time zone.
+var eventTimestampSchemaMigration = &SchemaMigration{
+	Version:     5,
+	Description: "add the update timestamp column to the event log tables",
+	Up:          eventTimestampUp,
+	Down:        eventTimestampDown,
+}
+
+// eventTimestampMigrationTables are the event log tables altered by the migration.
+var eventTimestampMigrationTables = []string{
+	PutObjectSuccessTableName,
+	PutObjectEventTableName,
+	UploadTimeoutTableName,
+	UploadFailedTableName,
+	ReplicateTimeoutTableName,
+	ReplicateFailedTableName,
+	SealTimeoutTableName,
+	SealFailedTableName,
+}
+
+// eventTimestampMigrationEventTable is the frozen column added to every event log table.
+type eventTimestampMigrationEventTable struct {
+	UpdateTimestampSecond int64
+}
+
+// eventTimestampIndexName returns the index name generated by gorm for the update timestamp column.
+func eventTimestampIndexName(table string) string {
+	return "idx_" + table + "_update_timestamp_second"
+}
+
+func eventTimestampUp(tx *gorm.DB, _ dialect.Dialect) error {
+	// the existing event logs are stamped by the migration time, they are collected one retention later
+	now := time.Now().Unix()
+	for _, table := range eventTimestampMigrationTables {
+		migrator := tx.Table(table).Migrator()
+		if !migrator.HasColumn(&eventTimestampMigrationEventTable{}, "UpdateTimestampSecond") {
+			if err := migrator.AddColumn(&eventTimestampMigrationEventTable{}, "UpdateTimestampSecond"); err != nil {
+				return fmt.Errorf("failed to add update timestamp column to %s table: %s", table, err)
+			}
+		}
+		if err := tx.Table(table).Where("update_timestamp_second IS NULL OR update_timestamp_second = 0").
+			Update("update_timestamp_second", now).Error; err != nil {
+			return fmt.Errorf("failed to fill update timestamp column of %s table: %s", table, err)
+		}
+		indexName := eventTimestampIndexName(table)
+		if migrator.HasIndex(&eventTimestampMigrationEventTable{}, indexName) {
+			continue
+		}
+		if err := tx.Exec(fmt.Sprintf("CREATE INDEX %s ON %s (update_timestamp_second)", indexName, table)).Error; err != nil {
+			return fmt.Errorf("failed to create update timestamp index of %s table: %s", table, err)
+		}
+	}
+	return nil
+}
+
+func eventTimestampDown(tx *gorm.DB, _ dialect.Dialect) error {
+	for _, table := range eventTimestampMigrationTables {
+		migrator := tx.Table(table).Migrator()
+		indexName := eventTimestampIndexName(table)
+		if migrator.HasIndex(&eventTimestampMigrationEventTable{}, indexName) {
+			if err := migrator.DropIndex(&eventTimestampMigrationEventTable{}, indexName); err != nil {
+				return fmt.Errorf("failed to drop update timestamp index of %s table: %s", table, err)
+			}
+		}
+		if !migrator.HasColumn(&eventTimestampMigrationEventTable{}, "UpdateTimestampSecond") {
+			continue
+		}
+		if err := migrator.DropColumn(&eventTimestampMigrationEventTable{}, "UpdateTimestampSecond"); err != nil {
+			return fmt.Errorf("failed to drop update timestamp column of %s table: %s", table, err)
+		}
+	}
+	return nil
+}
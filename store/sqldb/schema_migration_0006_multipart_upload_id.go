@@ -0,0 +1,55 @@
+package sqldb
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+)
+
+// multipartUploadIDSchemaMigration adds the upload id column to the multipart upload part table, every upload
+// of a part stages its chunks by its own upload id, and the part record points to the last recorded upload.
+var multipartUploadIDSchemaMigration = &SchemaMigration{
+	Version:     6,
+	Description: "add the upload id column to the multipart upload part table",
+	Up:          multipartUploadIDUp,
+	Down:        multipartUploadIDDown,
+}
+
+// multipartUploadIDMigrationPartTable is the frozen multipart upload part table after the migration.
+type multipartUploadIDMigrationPartTable struct {
+	ObjectID              uint64 `gorm:"primary_key;autoIncrement:false"`
+	PartNumber            uint32 `gorm:"primary_key;autoIncrement:false"`
+	UploadID              string `gorm:"size:32"`
+	Size                  uint64
+	ETag                  string `gorm:"column:etag"`
+	UpdateTimestampSecond int64
+}
+
+func (multipartUploadIDMigrationPartTable) TableName() string {
+	return "multipart_upload_part"
+}
+
+func multipartUploadIDUp(tx *gorm.DB, _ dialect.Dialect) error {
+	// the existing parts keep the empty upload id, their chunks are staged by the keys without upload id
+	table := &multipartUploadIDMigrationPartTable{}
+	if tx.Migrator().HasColumn(table, "UploadID") {
+		return nil
+	}
+	if err := tx.Migrator().AddColumn(table, "UploadID"); err != nil {
+		return fmt.Errorf("failed to add upload id column to multipart upload part table: %s", err)
+	}
+	return nil
+}
+
+func multipartUploadIDDown(tx *gorm.DB, _ dialect.Dialect) error {
+	table := &multipartUploadIDMigrationPartTable{}
+	if !tx.Migrator().HasColumn(table, "UploadID") {
+		return nil
+	}
+	if err := tx.Migrator().DropColumn(table, "UploadID"); err != nil {
+		return fmt.Errorf("failed to drop upload id column of multipart upload part table: %s", err)
+	}
+	return nil
+}
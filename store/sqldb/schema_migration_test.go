@@ -0,0 +1,209 @@
+package sqldb
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func TestSchemaMigrator_ApplyAndRollback(t *testing.T) {
+	db, d := openTestDB(t)
+	m := newSchemaMigrator(db, d)
+
+	version, err := m.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), version)
+
+	require.NoError(t, m.Apply(1))
+	version, err = m.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), version)
+	assert.True(t, db.Migrator().HasTable(UploadObjectProgressTableName))
+	assert.False(t, db.Migrator().HasTable(ScrubProgressTableName))
+
+	require.NoError(t, m.Apply(0))
+	version, err = m.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), version)
+	assert.True(t, db.Migrator().HasTable(ScrubProgressTableName))
+	assert.True(t, db.Migrator().HasTable(ScrubPieceTableName))
+
+	status, err := m.Status()
+	require.NoError(t, err)
+	require.Len(t, status, len(schemaMigrations))
+	for i, s := range status {
+		assert.Equal(t, uint32(i+1), s.Version)
+		assert.True(t, s.Applied)
+		assert.NotZero(t, s.AppliedTimestampSecond)
+	}
+
+	assert.Error(t, m.Apply(1), "the target is older than the current version")
+	assert.Error(t, m.Apply(LatestSchemaVersion()+1), "the target is unknown by the binary")
+
+	require.NoError(t, m.Rollback(1))
+	version, err = m.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), version)
+	assert.False(t, db.Migrator().HasTable(ScrubProgressTableName))
+	assert.False(t, db.Migrator().HasTable(ScrubPieceTableName))
+	status, err = m.Status()
+	require.NoError(t, err)
+	assert.True(t, status[0].Applied)
+	assert.False(t, status[1].Applied)
+
+	assert.Error(t, m.Rollback(1), "the target is not older than the current version")
+	assert.Error(t, m.Rollback(0), "the baseline can not be rolled back")
+	version, err = m.CurrentVersion()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), version)
+
+	// the rolled back migration can be applied again
+	require.NoError(t, m.Apply(0))
+	assert.True(t, db.Migrator().HasTable(ScrubProgressTableName))
+}
+
+func TestSchemaMigrator_CheckSchemaVersion(t *testing.T) {
+	t.Run("bootstrap new db", func(t *testing.T) {
+		db, d := openTestDB(t)
+		m := newSchemaMigrator(db, d)
+		require.NoError(t, m.checkSchemaVersion())
+		version, err := m.CurrentVersion()
+		require.NoError(t, err)
+		assert.Equal(t, LatestSchemaVersion(), version)
+		// the second start does nothing
+		require.NoError(t, m.checkSchemaVersion())
+	})
+
+	t.Run("refuse unversioned existing db", func(t *testing.T) {
+		db, d := openTestDB(t)
+		require.NoError(t, db.AutoMigrate(&baselineUploadObjectProgressTable{}))
+		m := newSchemaMigrator(db, d)
+		assert.ErrorContains(t, m.checkSchemaVersion(), "is older than")
+		version, err := m.CurrentVersion()
+		require.NoError(t, err)
+		assert.Equal(t, uint32(0), version)
+	})
+
+	t.Run("refuse older db", func(t *testing.T) {
+		db, d := openTestDB(t)
+		m := newSchemaMigrator(db, d)
+		require.NoError(t, m.Apply(LatestSchemaVersion()-1))
+		assert.ErrorContains(t, m.checkSchemaVersion(), "is older than")
+	})
+
+	t.Run("refuse newer db", func(t *testing.T) {
+		db, d := openTestDB(t)
+		m := newSchemaMigrator(db, d)
+		require.NoError(t, m.Apply(0))
+		require.NoError(t, db.Create(&SchemaVersionTable{Version: LatestSchemaVersion() + 1,
+			Description: "unknown"}).Error)
+		assert.ErrorContains(t, m.checkSchemaVersion(), "is newer than")
+		assert.Error(t, m.Rollback(1), "the unknown migration can not be rolled back")
+	})
+}
+
+// TestSchemaMigrator_LiveTables checks the latest migrated tables have all the columns of the live tables, a
+// column added to a live table without appending its migration is never created in the existing sp dbs.
+func TestSchemaMigrator_LiveTables(t *testing.T) {
+	db, d := openTestDB(t)
+	require.NoError(t, newSchemaMigrator(db, d).Apply(0))
+	liveTables := []schema.Tabler{
+		&UploadObjectProgressTable{}, &PutObjectSuccessTable{}, &PutObjectEventTable{}, &UploadTimeoutTable{},
+		&UploadFailedTable{}, &ReplicateTimeoutTable{}, &ReplicateFailedTable{}, &SealTimeoutTable{},
+		&SealFailedTable{}, &GCObjectProgressTable{}, &GCMetaProgressTable{}, &GCZombieProgressTable{},
+		&MultipartUploadPartTable{}, &TaskQueueTable{}, &S3AccessKeyTable{}, &BucketCORSTable{},
+		&RateLimitCounterTable{}, &EventSubscriptionTable{}, &EventDeliveryTable{}, &EventDeadLetterTable{},
+		&SpInfoTable{}, &PieceHashTable{}, &BucketTrafficTable{}, &ReadRecordTable{}, &ReadUsageRollupTable{},
+		&ReadQuotaAlertTable{}, &OffChainAuthKeyTable{}, &MigrateSubscribeProgressTable{}, &SwapOutTable{},
+		&MigrateGVGTable{}, &ScrubProgressTable{}, &ScrubPieceTable{}, &IntegrityMetaTable{},
+	}
+	for _, table := range liveTables {
+		s, err := schema.Parse(table, &sync.Map{}, db.NamingStrategy)
+		require.NoError(t, err)
+		tableName := table.TableName()
+		if _, ok := table.(*IntegrityMetaTable); ok {
+			tableName = GetIntegrityMetasTableNameByShardNumber(0)
+		}
+		for _, column := range s.DBNames {
+			assert.True(t, hasColumn(db, tableName, column), "column %s of table %s is not migrated", column, tableName)
+		}
+	}
+}
+
+func TestSchemaMigrator_GCZombie(t *testing.T) {
+	db, d := openTestDB(t)
+	m := newSchemaMigrator(db, d)
+	require.NoError(t, m.Apply(gcZombieSchemaMigration.Version))
+	assert.True(t, db.Migrator().HasTable(GCZombieProgressTableName))
+
+	require.NoError(t, m.Rollback(gcZombieSchemaMigration.Version-1))
+	assert.False(t, db.Migrator().HasTable(GCZombieProgressTableName))
+	assert.True(t, db.Migrator().HasTable(ScrubProgressTableName))
+}
+
+func TestSchemaMigrator_TaskLease(t *testing.T) {
+	db, d := openTestDB(t)
+	m := newSchemaMigrator(db, d)
+	require.NoError(t, m.Apply(taskLeaseSchemaMigration.Version-1))
+	require.NoError(t, db.Create(&baselineTaskQueueTable{QueueName: "q", TaskKeyHash: "h", TaskKey: "k"}).Error)
+
+	require.NoError(t, m.Apply(taskLeaseSchemaMigration.Version))
+	// the task queued before the migration is not leased
+	var task taskLeaseMigrationTaskQueueTable
+	require.NoError(t, db.First(&task).Error)
+	assert.Equal(t, "k", task.TaskKey)
+	assert.Zero(t, task.LeaseTimestampSecond)
+
+	require.NoError(t, m.Rollback(taskLeaseSchemaMigration.Version-1))
+	assert.False(t, hasColumn(db, TaskQueueTableName, "lease_timestamp_second"))
+	assert.True(t, hasColumn(db, TaskQueueTableName, "task_key"))
+}
+
+func TestSchemaMigrator_EventTimestamp(t *testing.T) {
+	db, d := openTestDB(t)
+	m := newSchemaMigrator(db, d)
+	require.NoError(t, m.Apply(eventTimestampSchemaMigration.Version-1))
+	require.NoError(t, db.Create(&baselinePutObjectEventTable{UpdateTime: "legacy", ObjectID: 1}).Error)
+
+	require.NoError(t, m.Apply(eventTimestampSchemaMigration.Version))
+	for _, table := range eventTimestampMigrationTables {
+		assert.True(t, hasColumn(db, table, "update_timestamp_second"))
+		assert.True(t, db.Migrator().HasIndex(table, eventTimestampIndexName(table)))
+	}
+	// the legacy event log is stamped by the migration time
+	var event PutObjectEventTable
+	require.NoError(t, db.First(&event).Error)
+	assert.NotZero(t, event.UpdateTimestampSecond)
+
+	require.NoError(t, m.Rollback(eventTimestampSchemaMigration.Version-1))
+	for _, table := range eventTimestampMigrationTables {
+		assert.False(t, hasColumn(db, table, "update_timestamp_second"))
+	}
+}
+
+func TestSchemaMigrator_MultipartUploadID(t *testing.T) {
+	db, d := openTestDB(t)
+	m := newSchemaMigrator(db, d)
+	require.NoError(t, m.Apply(multipartUploadIDSchemaMigration.Version-1))
+	require.NoError(t, db.Create(&baselineMultipartUploadPartTable{ObjectID: 1, PartNumber: 1, Size: 10, ETag: "e1"}).Error)
+
+	require.NoError(t, m.Apply(multipartUploadIDSchemaMigration.Version))
+	// the part staged before the migration keeps the empty upload id
+	var part MultipartUploadPartTable
+	require.NoError(t, db.First(&part).Error)
+	assert.Empty(t, part.UploadID)
+	assert.Equal(t, "e1", part.ETag)
+
+	require.NoError(t, m.Rollback(multipartUploadIDSchemaMigration.Version-1))
+	assert.False(t, db.Migrator().HasColumn(&baselineMultipartUploadPartTable{}, "upload_id"))
+}
+
+// hasColumn returns whether the table has the column, the generic gorm migrator of mysql and postgres can not look
+// up the column by the table name only.
+func hasColumn(db *gorm.DB, table, column string) bool {
+	return db.Table(table).Select(column).Limit(1).Find(&[]map[string]interface{}{}).Error == nil
+}
@@ -0,0 +1,14 @@
+package sqldb
+
+// SchemaVersionTable table schema, a row is inserted when a schema migration is applied and deleted when
+// it is rolled back.
+type SchemaVersionTable struct {
+	Version                uint32 `gorm:"primary_key;autoIncrement:false"`
+	Description            string
+	AppliedTimestampSecond int64
+}
+
+// TableName is used to set SchemaVersionTable Schema's table name in database.
+func (SchemaVersionTable) TableName() string {
+	return SchemaVersionTableName
+}
@@ -0,0 +1,134 @@
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+// UpdateScrubProgress is used to update the scrub progress of the gvg.
+// insert a new one if it is not found in db.
+func (s *SpDBImpl) UpdateScrubProgress(progress *spdb.ScrubProgress) error {
+	result := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "global_virtual_group_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"redundancy_index", "last_object_id", "scrubbed_object_count",
+			"scrubbed_piece_count", "corrupted_piece_count", "missing_piece_count", "finished",
+			"start_timestamp_second", "update_timestamp_second"}),
+	}).Create(&ScrubProgressTable{
+		GlobalVirtualGroupID:  progress.GlobalVirtualGroupID,
+		RedundancyIndex:       progress.RedundancyIndex,
+		LastObjectID:          progress.LastObjectID,
+		ScrubbedObjectCount:   progress.ScrubbedObjectCount,
+		ScrubbedPieceCount:    progress.ScrubbedPieceCount,
+		CorruptedPieceCount:   progress.CorruptedPieceCount,
+		MissingPieceCount:     progress.MissingPieceCount,
+		Finished:              progress.Finished,
+		StartTimestampSecond:  progress.StartTimestampSecond,
+		UpdateTimestampSecond: GetCurrentUnixTime(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to upsert scrub progress table: %s", result.Error)
+	}
+	return nil
+}
+
+// QueryScrubProgress returns the scrub progress of the gvg, returns (nil, nil) if it is not found in db.
+func (s *SpDBImpl) QueryScrubProgress(gvgID uint32) (*spdb.ScrubProgress, error) {
+	queryReturn := &ScrubProgressTable{}
+	result := s.db.First(queryReturn, "global_virtual_group_id = ?", gvgID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query scrub progress table: %s", result.Error)
+	}
+	return toScrubProgress(queryReturn), nil
+}
+
+// ListScrubProgresses returns the scrub progresses of all the gvgs in ascending order of gvg id.
+func (s *SpDBImpl) ListScrubProgresses() ([]*spdb.ScrubProgress, error) {
+	var queryReturns []ScrubProgressTable
+	result := s.db.Order("global_virtual_group_id").Find(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query scrub progress table: %s", result.Error)
+	}
+	progresses := make([]*spdb.ScrubProgress, 0, len(queryReturns))
+	for i := range queryReturns {
+		progresses = append(progresses, toScrubProgress(&queryReturns[i]))
+	}
+	return progresses, nil
+}
+
+func toScrubProgress(record *ScrubProgressTable) *spdb.ScrubProgress {
+	return &spdb.ScrubProgress{
+		GlobalVirtualGroupID:  record.GlobalVirtualGroupID,
+		RedundancyIndex:       record.RedundancyIndex,
+		LastObjectID:          record.LastObjectID,
+		ScrubbedObjectCount:   record.ScrubbedObjectCount,
+		ScrubbedPieceCount:    record.ScrubbedPieceCount,
+		CorruptedPieceCount:   record.CorruptedPieceCount,
+		MissingPieceCount:     record.MissingPieceCount,
+		Finished:              record.Finished,
+		StartTimestampSecond:  record.StartTimestampSecond,
+		UpdateTimestampSecond: record.UpdateTimestampSecond,
+	}
+}
+
+// UpdateScrubPiece records the bad piece, the piece found again overwrites the old one.
+func (s *SpDBImpl) UpdateScrubPiece(piece *spdb.ScrubPiece) error {
+	result := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "piece_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"global_virtual_group_id", "object_id", "segment_index",
+			"redundancy_index", "state", "recovering", "detect_timestamp_second"}),
+	}).Create(&ScrubPieceTable{
+		PieceKey:              piece.PieceKey,
+		GlobalVirtualGroupID:  piece.GlobalVirtualGroupID,
+		ObjectID:              piece.ObjectID,
+		SegmentIndex:          piece.SegmentIndex,
+		RedundancyIndex:       piece.RedundancyIndex,
+		State:                 string(piece.State),
+		Recovering:            piece.Recovering,
+		DetectTimestampSecond: piece.DetectTimestampSecond,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to upsert scrub piece table: %s", result.Error)
+	}
+	return nil
+}
+
+// ListScrubPieces returns at most limit bad pieces of the gvg, the latest found first.
+func (s *SpDBImpl) ListScrubPieces(gvgID uint32, limit int) ([]*spdb.ScrubPiece, error) {
+	var queryReturns []ScrubPieceTable
+	result := s.db.Where("global_virtual_group_id = ?", gvgID).
+		Order("detect_timestamp_second DESC").Limit(limit).Find(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query scrub piece table: %s", result.Error)
+	}
+	pieces := make([]*spdb.ScrubPiece, 0, len(queryReturns))
+	for _, record := range queryReturns {
+		pieces = append(pieces, &spdb.ScrubPiece{
+			GlobalVirtualGroupID:  record.GlobalVirtualGroupID,
+			ObjectID:              record.ObjectID,
+			SegmentIndex:          record.SegmentIndex,
+			RedundancyIndex:       record.RedundancyIndex,
+			PieceKey:              record.PieceKey,
+			State:                 spdb.ScrubPieceState(record.State),
+			Recovering:            record.Recovering,
+			DetectTimestampSecond: record.DetectTimestampSecond,
+		})
+	}
+	return pieces, nil
+}
+
+// DeleteScrubPieces deletes all the bad pieces of the gvg.
+func (s *SpDBImpl) DeleteScrubPieces(gvgID uint32) error {
+	result := s.db.Where("global_virtual_group_id = ?", gvgID).Delete(&ScrubPieceTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete records in scrub piece table: %s", result.Error)
+	}
+	return nil
+}
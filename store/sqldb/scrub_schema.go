@@ -0,0 +1,37 @@
+package sqldb
+
+// ScrubProgressTable table schema, there is one progress for every gvg served by the SP.
+type ScrubProgressTable struct {
+	GlobalVirtualGroupID  uint32 `gorm:"primary_key;autoIncrement:false"`
+	RedundancyIndex       int32
+	LastObjectID          uint64
+	ScrubbedObjectCount   uint64
+	ScrubbedPieceCount    uint64
+	CorruptedPieceCount   uint64
+	MissingPieceCount     uint64
+	Finished              bool
+	StartTimestampSecond  int64
+	UpdateTimestampSecond int64
+}
+
+// TableName is used to set ScrubProgressTable Schema's table name in database.
+func (ScrubProgressTable) TableName() string {
+	return ScrubProgressTableName
+}
+
+// ScrubPieceTable table schema, the piece is identified by the piece key in the piece store.
+type ScrubPieceTable struct {
+	PieceKey              string `gorm:"primary_key;size:256"`
+	GlobalVirtualGroupID  uint32 `gorm:"index:gvg_to_scrub_piece"`
+	ObjectID              uint64
+	SegmentIndex          uint32
+	RedundancyIndex       int32
+	State                 string `gorm:"size:16"`
+	Recovering            bool
+	DetectTimestampSecond int64
+}
+
+// TableName is used to set ScrubPieceTable Schema's table name in database.
+func (ScrubPieceTable) TableName() string {
+	return ScrubPieceTableName
+}
@@ -0,0 +1,78 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+func TestSpDBImpl_ScrubProgress(t *testing.T) {
+	s := setupSpDBTest(t)
+	progress, err := s.QueryScrubProgress(1)
+	require.NoError(t, err)
+	assert.Nil(t, progress)
+
+	require.NoError(t, s.UpdateScrubProgress(&spdb.ScrubProgress{GlobalVirtualGroupID: 2, RedundancyIndex: 1,
+		StartTimestampSecond: 100}))
+	require.NoError(t, s.UpdateScrubProgress(&spdb.ScrubProgress{GlobalVirtualGroupID: 1, RedundancyIndex: -1,
+		LastObjectID: 10, ScrubbedObjectCount: 1, ScrubbedPieceCount: 2, StartTimestampSecond: 100}))
+	// the progress of the gvg is overwritten
+	require.NoError(t, s.UpdateScrubProgress(&spdb.ScrubProgress{GlobalVirtualGroupID: 1, RedundancyIndex: -1,
+		LastObjectID: 20, ScrubbedObjectCount: 3, ScrubbedPieceCount: 4, CorruptedPieceCount: 1, MissingPieceCount: 2,
+		Finished: true, StartTimestampSecond: 100}))
+
+	progress, err = s.QueryScrubProgress(1)
+	require.NoError(t, err)
+	assert.NotZero(t, progress.UpdateTimestampSecond)
+	progress.UpdateTimestampSecond = 0
+	assert.Equal(t, &spdb.ScrubProgress{GlobalVirtualGroupID: 1, RedundancyIndex: -1, LastObjectID: 20,
+		ScrubbedObjectCount: 3, ScrubbedPieceCount: 4, CorruptedPieceCount: 1, MissingPieceCount: 2, Finished: true,
+		StartTimestampSecond: 100}, progress)
+
+	progresses, err := s.ListScrubProgresses()
+	require.NoError(t, err)
+	require.Len(t, progresses, 2)
+	assert.Equal(t, uint32(1), progresses[0].GlobalVirtualGroupID)
+	assert.Equal(t, uint32(2), progresses[1].GlobalVirtualGroupID)
+	assert.Equal(t, int32(1), progresses[1].RedundancyIndex)
+}
+
+func TestSpDBImpl_ScrubPiece(t *testing.T) {
+	s := setupSpDBTest(t)
+	pieces, err := s.ListScrubPieces(1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pieces)
+
+	require.NoError(t, s.UpdateScrubPiece(&spdb.ScrubPiece{GlobalVirtualGroupID: 1, ObjectID: 10, PieceKey: "s10_s0",
+		State: spdb.ScrubPieceMissing, DetectTimestampSecond: 100}))
+	require.NoError(t, s.UpdateScrubPiece(&spdb.ScrubPiece{GlobalVirtualGroupID: 1, ObjectID: 11, SegmentIndex: 1,
+		PieceKey: "s11_s1", State: spdb.ScrubPieceNoIntegrity, DetectTimestampSecond: 200}))
+	require.NoError(t, s.UpdateScrubPiece(&spdb.ScrubPiece{GlobalVirtualGroupID: 2, ObjectID: 12, PieceKey: "s12_s0",
+		State: spdb.ScrubPieceCorrupted, DetectTimestampSecond: 300}))
+	// the piece found again overwrites the old one
+	require.NoError(t, s.UpdateScrubPiece(&spdb.ScrubPiece{GlobalVirtualGroupID: 1, ObjectID: 10, PieceKey: "s10_s0",
+		State: spdb.ScrubPieceCorrupted, Recovering: true, DetectTimestampSecond: 300}))
+
+	pieces, err = s.ListScrubPieces(1, 10)
+	require.NoError(t, err)
+	require.Len(t, pieces, 2)
+	// the latest found first
+	assert.Equal(t, &spdb.ScrubPiece{GlobalVirtualGroupID: 1, ObjectID: 10, PieceKey: "s10_s0",
+		State: spdb.ScrubPieceCorrupted, Recovering: true, DetectTimestampSecond: 300}, pieces[0])
+	assert.Equal(t, &spdb.ScrubPiece{GlobalVirtualGroupID: 1, ObjectID: 11, SegmentIndex: 1, PieceKey: "s11_s1",
+		State: spdb.ScrubPieceNoIntegrity, DetectTimestampSecond: 200}, pieces[1])
+	pieces, err = s.ListScrubPieces(1, 1)
+	require.NoError(t, err)
+	assert.Len(t, pieces, 1)
+
+	require.NoError(t, s.DeleteScrubPieces(1))
+	pieces, err = s.ListScrubPieces(1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pieces)
+	pieces, err = s.ListScrubPieces(2, 10)
+	require.NoError(t, err)
+	assert.Len(t, pieces, 1)
+}
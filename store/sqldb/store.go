@@ -1,19 +1,20 @@
 package sqldb
 
 import (
-	"fmt"
 	"os"
 	"time"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
 	corespdb "github.com/bnb-chain/greenfield-storage-provider/core/spdb"
 	"github.com/bnb-chain/greenfield-storage-provider/pkg/log"
 	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
 )
 
 const (
+	// SpDBDriver defines env variable name for sp db driver, mysql, postgres or sqlite.
+	SpDBDriver = "SP_DB_DRIVER"
 	// SpDBUser defines env variable name for sp db user name.
 	SpDBUser = "SP_DB_USER"
 	// SpDBPasswd defines env variable name for sp db user passwd.
@@ -37,123 +38,49 @@ var _ corespdb.SPDB = &SpDBImpl{}
 
 // SpDBImpl storage provider database, implements SPDB interface
 type SpDBImpl struct {
-	db *gorm.DB
+	db      *gorm.DB
+	dialect dialect.Dialect
 }
 
 // NewSpDB return a database instance
 func NewSpDB(config *config.SQLDBConfig) (*SpDBImpl, error) {
 	LoadDBConfigFromEnv(config)
 	OverrideConfigVacancy(config)
-	db, err := InitDB(config)
+	db, d, err := InitDB(config)
 	if err != nil {
 		return nil, err
 	}
-	return &SpDBImpl{db: db}, err
+	return &SpDBImpl{db: db, dialect: d}, err
 }
 
-// InitDB init a db instance
-func InitDB(config *config.SQLDBConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.User, config.Passwd, config.Address, config.Database)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+// InitDB init a db instance by the driver of the config
+func InitDB(config *config.SQLDBConfig) (*gorm.DB, dialect.Dialect, error) {
+	db, d, err := dialect.Open(config, &gorm.Config{})
 	if err != nil {
-		log.Errorw("gorm failed to open db", "error", err)
-		return nil, err
+		log.Errorw("gorm failed to open db", "driver", config.Driver, "error", err)
+		return nil, nil, err
 	}
 	sqlDB, err := db.DB()
 	if err != nil {
 		log.Errorw("gorm failed to set db params", "error", err)
-		return nil, err
+		return nil, nil, err
 	}
 	sqlDB.SetConnMaxLifetime(time.Duration(config.ConnMaxLifetime) * time.Second)
 	sqlDB.SetConnMaxIdleTime(time.Duration(config.ConnMaxIdleTime) * time.Second)
 	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
-	// create if not exist
-	if err = db.AutoMigrate(&UploadObjectProgressTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to upload object progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&PutObjectSuccessTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to successfully put event progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&PutObjectEventTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to put event progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&UploadTimeoutTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to successfully put event progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&UploadFailedTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to put event progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&ReplicateTimeoutTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to successfully put event progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&ReplicateFailedTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to put event progress table", "error", err)
-		return nil, err
+	if err = newSchemaMigrator(db, d).checkSchemaVersion(); err != nil {
+		log.Errorw("failed to check sp db schema version", "error", err)
+		return nil, nil, err
 	}
-	if err = db.AutoMigrate(&SealTimeoutTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to successfully put event progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&SealFailedTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to put event progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&GCObjectProgressTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to gc object progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&SpInfoTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to create sp info table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&PieceHashTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to create piece hash table", "error", err)
-		return nil, err
-	}
-	for i := 0; i < IntegrityMetasNumberOfShards; i++ {
-		shardTableName := fmt.Sprintf(IntegrityMetaTable{}.TableName()+"_%02d", i)
-		if err = db.Table(shardTableName).AutoMigrate(&IntegrityMetaTable{}); err != nil && !isAlreadyExists(err) {
-			log.Errorw("failed to create integrity meta table", "error", err)
-			return nil, err
-		}
-	}
-	if err = db.AutoMigrate(&BucketTrafficTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to create bucket traffic table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&ReadRecordTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to create read record table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&OffChainAuthKeyTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to create off-chain authKey table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&MigrateSubscribeProgressTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to migrate subscribe progress table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&SwapOutTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to swap out table", "error", err)
-		return nil, err
-	}
-	if err = db.AutoMigrate(&MigrateGVGTable{}); err != nil && !isAlreadyExists(err) {
-		log.Errorw("failed to migrate gvg table", "error", err)
-		return nil, err
-	}
-	return db, nil
+	return db, d, nil
 }
 
 // LoadDBConfigFromEnv load db user and password from env vars
 func LoadDBConfigFromEnv(config *config.SQLDBConfig) {
+	if val, ok := os.LookupEnv(SpDBDriver); ok {
+		config.Driver = val
+	}
 	if val, ok := os.LookupEnv(SpDBUser); ok {
 		config.User = val
 	}
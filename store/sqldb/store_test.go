@@ -0,0 +1,91 @@
+package sqldb
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/bnb-chain/greenfield-storage-provider/store/config"
+	"github.com/bnb-chain/greenfield-storage-provider/store/dialect"
+)
+
+// The sp db tests run on a temporary sqlite file by default, they run on the mysql or postgres server if the
+// driver and the server are given by the env variables, e.g. in the ci. Every test creates its own database on
+// the server and drops it at the end.
+const (
+	// spDBTestDriver defines env variable name for the driver of the sp db tests, mysql, postgres or sqlite.
+	spDBTestDriver = "SP_DB_TEST_DRIVER"
+	// spDBTestAddress defines env variable name for the address of the mysql or postgres server of the tests.
+	spDBTestAddress = "SP_DB_TEST_ADDRESS"
+	// spDBTestUser defines env variable name for the user of the mysql or postgres server of the tests, the user
+	// must be able to create and drop databases.
+	spDBTestUser = "SP_DB_TEST_USER"
+	// spDBTestPasswd defines env variable name for the password of the user of the tests.
+	spDBTestPasswd = "SP_DB_TEST_PASSWORD"
+)
+
+// setupSpDBTest returns a sp db on an empty test database, which is migrated to the latest schema version.
+func setupSpDBTest(t *testing.T) *SpDBImpl {
+	cfg := newTestDBConfig(t)
+	t.Setenv(SpDBDriver, cfg.Driver)
+	t.Setenv(SpDBUser, cfg.User)
+	t.Setenv(SpDBPasswd, cfg.Passwd)
+	t.Setenv(SpDBAddress, cfg.Address)
+	t.Setenv(SpDBDataBase, cfg.Database)
+	spDB, err := NewSpDB(&config.SQLDBConfig{})
+	require.NoError(t, err)
+	closeTestDB(t, spDB.db)
+	return spDB
+}
+
+// openTestDB returns an empty test database without checking the schema version.
+func openTestDB(t *testing.T) (*gorm.DB, dialect.Dialect) {
+	db, d, err := dialect.Open(newTestDBConfig(t), &gorm.Config{})
+	require.NoError(t, err)
+	closeTestDB(t, db)
+	return db, d
+}
+
+// newTestDBConfig returns the config of an empty test database of the test driver.
+func newTestDBConfig(t *testing.T) *config.SQLDBConfig {
+	driver := os.Getenv(spDBTestDriver)
+	if driver == "" || driver == dialect.SQLiteDriver {
+		return &config.SQLDBConfig{Driver: dialect.SQLiteDriver, Database: filepath.Join(t.TempDir(), "sp.db")}
+	}
+	cfg := &config.SQLDBConfig{
+		Driver:  driver,
+		User:    os.Getenv(spDBTestUser),
+		Passwd:  os.Getenv(spDBTestPasswd),
+		Address: os.Getenv(spDBTestAddress),
+	}
+	// connect the server by the database which always exists to create the test database
+	if driver == dialect.PostgresDriver {
+		cfg.Database = "postgres"
+	}
+	server, _, err := dialect.Open(cfg, &gorm.Config{})
+	require.NoError(t, err)
+	closeTestDB(t, server)
+	suffix := make([]byte, 8)
+	_, err = rand.Read(suffix)
+	require.NoError(t, err)
+	database := "sp_test_" + hex.EncodeToString(suffix)
+	require.NoError(t, server.Exec(fmt.Sprintf("CREATE DATABASE %s", database)).Error)
+	t.Cleanup(func() {
+		require.NoError(t, server.Exec(fmt.Sprintf("DROP DATABASE %s", database)).Error)
+	})
+	cfg.Database = database
+	return cfg
+}
+
+// closeTestDB closes the connections of the db at the end of the test, so the test database can be dropped.
+func closeTestDB(t *testing.T, db *gorm.DB) {
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+}
@@ -0,0 +1,89 @@
+package sqldb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+// UpdateQueueTask is used to insert or update the task persisted by the task queue, the lease of the
+// updated task is released because it is pushed back to the queue.
+func (s *SpDBImpl) UpdateQueueTask(meta *spdb.TaskQueueMeta) error {
+	result := s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "queue_name"}, {Name: "task_key_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"task_type", "priority", "retry", "max_retry", "logs",
+			"task_data", "update_timestamp_second", "lease_timestamp_second"}),
+	}).Create(&TaskQueueTable{
+		QueueName:             meta.QueueName,
+		TaskKeyHash:           taskKeyHash(meta.TaskKey),
+		TaskKey:               meta.TaskKey,
+		TaskType:              meta.TaskType,
+		Priority:              meta.Priority,
+		Retry:                 meta.Retry,
+		MaxRetry:              meta.MaxRetry,
+		Logs:                  meta.Logs,
+		TaskData:              meta.TaskData,
+		CreateTimestampSecond: meta.CreateTimestampSecond,
+		UpdateTimestampSecond: GetCurrentUnixTime(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to upsert record in task queue table: %s", result.Error)
+	}
+	return nil
+}
+
+// LeaseQueueTask records the lease of the task which is popped to dispatch.
+func (s *SpDBImpl) LeaseQueueTask(queueName string, taskKey string, leaseTimestampSecond int64) error {
+	result := s.db.Model(&TaskQueueTable{}).
+		Where("queue_name = ? and task_key_hash = ?", queueName, taskKeyHash(taskKey)).
+		Update("lease_timestamp_second", leaseTimestampSecond)
+	if result.Error != nil {
+		return fmt.Errorf("failed to lease record in task queue table: %s", result.Error)
+	}
+	return nil
+}
+
+// DeleteQueueTask deletes the task persisted by the task queue.
+func (s *SpDBImpl) DeleteQueueTask(queueName string, taskKey string) error {
+	result := s.db.Where("queue_name = ? and task_key_hash = ?", queueName, taskKeyHash(taskKey)).
+		Delete(&TaskQueueTable{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete record in task queue table: %s", result.Error)
+	}
+	return nil
+}
+
+// ListQueueTasks returns all the tasks persisted by the task queue in ascending order of create time.
+func (s *SpDBImpl) ListQueueTasks(queueName string) ([]*spdb.TaskQueueMeta, error) {
+	var queryReturns []TaskQueueTable
+	result := s.db.Where("queue_name = ?", queueName).Order("create_timestamp_second asc").Find(&queryReturns)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to query task queue table: %s", result.Error)
+	}
+	metas := make([]*spdb.TaskQueueMeta, 0, len(queryReturns))
+	for _, task := range queryReturns {
+		metas = append(metas, &spdb.TaskQueueMeta{
+			QueueName:             task.QueueName,
+			TaskKey:               task.TaskKey,
+			TaskType:              task.TaskType,
+			Priority:              task.Priority,
+			Retry:                 task.Retry,
+			MaxRetry:              task.MaxRetry,
+			Logs:                  task.Logs,
+			TaskData:              task.TaskData,
+			CreateTimestampSecond: task.CreateTimestampSecond,
+			UpdateTimestampSecond: task.UpdateTimestampSecond,
+			LeaseTimestampSecond:  task.LeaseTimestampSecond,
+		})
+	}
+	return metas, nil
+}
+
+func taskKeyHash(taskKey string) string {
+	hash := sha256.Sum256([]byte(taskKey))
+	return hex.EncodeToString(hash[:])
+}
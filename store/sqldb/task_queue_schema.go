@@ -0,0 +1,23 @@
+package sqldb
+
+// TaskQueueTable table schema, the task key may be longer than the limit of the index, so the
+// hash of the task key is used as the primary key.
+type TaskQueueTable struct {
+	QueueName             string `gorm:"primary_key;size:64"`
+	TaskKeyHash           string `gorm:"primary_key;size:64"`
+	TaskKey               string
+	TaskType              int32
+	Priority              uint32
+	Retry                 int64
+	MaxRetry              int64
+	Logs                  string
+	TaskData              []byte
+	CreateTimestampSecond int64
+	UpdateTimestampSecond int64
+	LeaseTimestampSecond  int64
+}
+
+// TableName is used to set TaskQueueTable Schema's table name in database.
+func (TaskQueueTable) TableName() string {
+	return TaskQueueTableName
+}
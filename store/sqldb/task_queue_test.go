@@ -0,0 +1,60 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bnb-chain/greenfield-storage-provider/core/spdb"
+)
+
+func TestSpDBImpl_QueueTask(t *testing.T) {
+	s := setupSpDBTest(t)
+	meta := &spdb.TaskQueueMeta{
+		QueueName:             "test-queue",
+		TaskKey:               "test-task",
+		TaskType:              1,
+		Priority:              2,
+		MaxRetry:              3,
+		Logs:                  "log",
+		TaskData:              []byte("data"),
+		CreateTimestampSecond: 100,
+	}
+	require.NoError(t, s.UpdateQueueTask(meta))
+	metas, err := s.ListQueueTasks("test-queue")
+	require.NoError(t, err)
+	require.Len(t, metas, 1)
+	assert.Equal(t, "test-task", metas[0].TaskKey)
+	assert.Equal(t, []byte("data"), metas[0].TaskData)
+	assert.Zero(t, metas[0].LeaseTimestampSecond)
+
+	// the dispatched task is kept with the lease
+	require.NoError(t, s.LeaseQueueTask("test-queue", "test-task", 200))
+	metas, err = s.ListQueueTasks("test-queue")
+	require.NoError(t, err)
+	require.Len(t, metas, 1)
+	assert.Equal(t, int64(200), metas[0].LeaseTimestampSecond)
+
+	// the task pushed back is updated in place and the lease is released
+	meta.Retry = 1
+	meta.Logs = "log-retry"
+	meta.CreateTimestampSecond = 300
+	require.NoError(t, s.UpdateQueueTask(meta))
+	metas, err = s.ListQueueTasks("test-queue")
+	require.NoError(t, err)
+	require.Len(t, metas, 1)
+	assert.Equal(t, int64(1), metas[0].Retry)
+	assert.Equal(t, "log-retry", metas[0].Logs)
+	assert.Equal(t, int64(100), metas[0].CreateTimestampSecond)
+	assert.Zero(t, metas[0].LeaseTimestampSecond)
+
+	metas, err = s.ListQueueTasks("other-queue")
+	require.NoError(t, err)
+	assert.Empty(t, metas)
+
+	require.NoError(t, s.DeleteQueueTask("test-queue", "test-task"))
+	metas, err = s.ListQueueTasks("test-queue")
+	require.NoError(t, err)
+	assert.Empty(t, metas)
+}
@@ -37,6 +37,10 @@ const (
 	SPDBSuccessGetUserReadRecord = "get_user_read_record_success"
 	// SPDBFailureGetUserReadRecord defines the metrics label of unsuccessfully get user read record
 	SPDBFailureGetUserReadRecord = "get_user_read_record_failure"
+	// SPDBSuccessDelExpiredReadRecord defines the metrics label of successfully del expired read record
+	SPDBSuccessDelExpiredReadRecord = "del_expired_read_record_success"
+	// SPDBFailureDelExpiredReadRecord defines the metrics label of unsuccessfully del expired read record
+	SPDBFailureDelExpiredReadRecord = "del_expired_read_record_failure"
 )
 
 // CheckQuotaAndAddReadRecord check current quota, and add read record
@@ -382,3 +386,27 @@ func (s *SpDBImpl) GetUserReadRecord(userAddress string, timeRange *corespdb.Tra
 	}
 	return records, nil
 }
+
+// DeleteExpiredReadRecord deletes the read records before expiredTimestampUs, the number of deleted records is at most limit.
+func (s *SpDBImpl) DeleteExpiredReadRecord(expiredTimestampUs int64, limit int) (deleted int64, err error) {
+	startTime := time.Now()
+	defer func() {
+		if err != nil {
+			metrics.SPDBCounter.WithLabelValues(SPDBFailureDelExpiredReadRecord).Inc()
+			metrics.SPDBTime.WithLabelValues(SPDBFailureDelExpiredReadRecord).Observe(
+				time.Since(startTime).Seconds())
+			return
+		}
+		metrics.SPDBCounter.WithLabelValues(SPDBSuccessDelExpiredReadRecord).Inc()
+		metrics.SPDBTime.WithLabelValues(SPDBSuccessDelExpiredReadRecord).Observe(
+			time.Since(startTime).Seconds())
+	}()
+
+	deleted, err = deleteInBatch(s.db, &ReadRecordTable{}, "read_record_id", limit,
+		"read_timestamp_us < ?", expiredTimestampUs)
+	if err != nil {
+		err = fmt.Errorf("failed to delete expired read record: %s", err)
+		return 0, err
+	}
+	return deleted, nil
+}
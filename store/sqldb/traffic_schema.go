@@ -36,3 +36,31 @@ type ReadRecordTable struct {
 func (ReadRecordTable) TableName() string {
 	return ReadRecordTableName
 }
+
+// ReadUsageRollupTable table schema, the read records are rolled up by hour.
+type ReadUsageRollupTable struct {
+	Dimension              string `gorm:"primary_key;size:16"`
+	BucketID               uint64 `gorm:"primary_key;autoIncrement:false"`
+	UsageKey               string `gorm:"primary_key;size:128"` // the bucket id, the object id or the user address
+	PeriodStartTimestampUs int64  `gorm:"primary_key;autoIncrement:false;index:period_to_read_usage"`
+	Name                   string // the bucket name or the object name
+	ReadSize               uint64
+	ReadCount              uint64
+}
+
+// TableName is used to set ReadUsageRollup Schema's table name in database
+func (ReadUsageRollupTable) TableName() string {
+	return ReadUsageRollupTableName
+}
+
+// ReadQuotaAlertTable table schema
+type ReadQuotaAlertTable struct {
+	BucketID        uint64 `gorm:"primary_key;autoIncrement:false"`
+	NotifiedPercent uint32 // the highest threshold percent of the read quota which has been notified
+	ModifiedTime    time.Time
+}
+
+// TableName is used to set ReadQuotaAlert Schema's table name in database
+func (ReadQuotaAlertTable) TableName() string {
+	return ReadQuotaAlertTableName
+}
@@ -0,0 +1,31 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpDBImpl_DeleteExpiredReadRecord(t *testing.T) {
+	s := setupSpDBTest(t)
+	for i := int64(1); i <= 5; i++ {
+		require.NoError(t, s.db.Create(&ReadRecordTable{BucketID: 1, ObjectID: uint64(i), ReadTimestampUs: i * 100}).Error)
+	}
+
+	// the limit is honored by all the engines
+	deleted, err := s.DeleteExpiredReadRecord(450, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	deleted, err = s.DeleteExpiredReadRecord(450, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	deleted, err = s.DeleteExpiredReadRecord(450, 10)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	var remained []ReadRecordTable
+	require.NoError(t, s.db.Find(&remained).Error)
+	require.Len(t, remained, 1)
+	assert.Equal(t, int64(500), remained[0].ReadTimestampUs)
+}
@@ -1,6 +1,7 @@
 package sqldb
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/bnb-chain/greenfield-storage-provider/base/types/gfsptask"
@@ -22,45 +23,50 @@ func (s *SpDBImpl) InsertPutEvent(task coretask.Task) error {
 }
 
 func (s *SpDBImpl) InsertUploadEvent(task coretask.UploadObjectTask) error {
-	updateTime := time.Now().String()
+	now := time.Now()
+	updateTime, updateTimestampSecond := now.String(), now.Unix()
 	taskErr := ""
 	if task.Error() != nil {
 		taskErr = task.Error().Error()
 	}
 	s.db.Create(&PutObjectEventTable{
-		UpdateTime: updateTime,
-		ObjectID:   task.GetObjectInfo().Id.Uint64(),
-		Bucket:     task.GetObjectInfo().GetBucketName(),
-		Object:     task.GetObjectInfo().GetObjectName(),
-		State:      "Upload",
-		Error:      taskErr,
-		Logs:       task.GetLogs(),
+		UpdateTime:            updateTime,
+		UpdateTimestampSecond: updateTimestampSecond,
+		ObjectID:              task.GetObjectInfo().Id.Uint64(),
+		Bucket:                task.GetObjectInfo().GetBucketName(),
+		Object:                task.GetObjectInfo().GetObjectName(),
+		State:                 "Upload",
+		Error:                 taskErr,
+		Logs:                  task.GetLogs(),
 	})
 
 	if task.Error() != nil {
 		s.db.Create(&UploadFailedTable{
-			UpdateTime: updateTime,
-			ObjectID:   task.GetObjectInfo().Id.Uint64(),
-			Bucket:     task.GetObjectInfo().GetBucketName(),
-			Object:     task.GetObjectInfo().GetObjectName(),
-			Error:      taskErr,
-			Logs:       task.GetLogs(),
+			UpdateTime:            updateTime,
+			UpdateTimestampSecond: updateTimestampSecond,
+			ObjectID:              task.GetObjectInfo().Id.Uint64(),
+			Bucket:                task.GetObjectInfo().GetBucketName(),
+			Object:                task.GetObjectInfo().GetObjectName(),
+			Error:                 taskErr,
+			Logs:                  task.GetLogs(),
 		})
 	} else if time.Now().Unix()-task.GetCreateTime() > 2 {
 		s.db.Create(&UploadTimeoutTable{
-			UpdateTime: updateTime,
-			ObjectID:   task.GetObjectInfo().Id.Uint64(),
-			Bucket:     task.GetObjectInfo().GetBucketName(),
-			Object:     task.GetObjectInfo().GetObjectName(),
-			Error:      taskErr,
-			Logs:       task.GetLogs(),
+			UpdateTime:            updateTime,
+			UpdateTimestampSecond: updateTimestampSecond,
+			ObjectID:              task.GetObjectInfo().Id.Uint64(),
+			Bucket:                task.GetObjectInfo().GetBucketName(),
+			Object:                task.GetObjectInfo().GetObjectName(),
+			Error:                 taskErr,
+			Logs:                  task.GetLogs(),
 		})
 	}
 	return nil
 }
 
 func (s *SpDBImpl) InsertReplicateEvent(task coretask.ReplicatePieceTask) error {
-	updateTime := time.Now().String()
+	now := time.Now()
+	updateTime, updateTimestampSecond := now.String(), now.Unix()
 	state := "replicate"
 	if task.GetSealed() {
 		state = "Replicate + Seal"
@@ -70,91 +76,126 @@ func (s *SpDBImpl) InsertReplicateEvent(task coretask.ReplicatePieceTask) error
 		taskErr = task.Error().Error()
 	}
 	s.db.Create(&PutObjectEventTable{
-		UpdateTime: updateTime,
-		ObjectID:   task.GetObjectInfo().Id.Uint64(),
-		Bucket:     task.GetObjectInfo().GetBucketName(),
-		Object:     task.GetObjectInfo().GetObjectName(),
-		State:      state,
-		Error:      taskErr,
-		Logs:       task.GetLogs(),
+		UpdateTime:            updateTime,
+		UpdateTimestampSecond: updateTimestampSecond,
+		ObjectID:              task.GetObjectInfo().Id.Uint64(),
+		Bucket:                task.GetObjectInfo().GetBucketName(),
+		Object:                task.GetObjectInfo().GetObjectName(),
+		State:                 state,
+		Error:                 taskErr,
+		Logs:                  task.GetLogs(),
 	})
 
 	if task.Error() != nil {
 		s.db.Create(&ReplicateFailedTable{
-			UpdateTime: updateTime,
-			ObjectID:   task.GetObjectInfo().Id.Uint64(),
-			Bucket:     task.GetObjectInfo().GetBucketName(),
-			Object:     task.GetObjectInfo().GetObjectName(),
-			Error:      taskErr,
-			Logs:       task.GetLogs(),
+			UpdateTime:            updateTime,
+			UpdateTimestampSecond: updateTimestampSecond,
+			ObjectID:              task.GetObjectInfo().Id.Uint64(),
+			Bucket:                task.GetObjectInfo().GetBucketName(),
+			Object:                task.GetObjectInfo().GetObjectName(),
+			Error:                 taskErr,
+			Logs:                  task.GetLogs(),
 		})
 	} else if time.Now().Unix()-task.GetCreateTime() > 10 {
 		s.db.Create(&ReplicateTimeoutTable{
-			UpdateTime: updateTime,
-			ObjectID:   task.GetObjectInfo().Id.Uint64(),
-			Bucket:     task.GetObjectInfo().GetBucketName(),
-			Object:     task.GetObjectInfo().GetObjectName(),
-			Error:      taskErr,
-			Logs:       task.GetLogs(),
+			UpdateTime:            updateTime,
+			UpdateTimestampSecond: updateTimestampSecond,
+			ObjectID:              task.GetObjectInfo().Id.Uint64(),
+			Bucket:                task.GetObjectInfo().GetBucketName(),
+			Object:                task.GetObjectInfo().GetObjectName(),
+			Error:                 taskErr,
+			Logs:                  task.GetLogs(),
 		})
 	} else if task.GetSealed() {
 		s.db.Create(&PutObjectSuccessTable{
-			UpdateTime: updateTime,
-			ObjectID:   task.GetObjectInfo().Id.Uint64(),
-			Bucket:     task.GetObjectInfo().GetBucketName(),
-			Object:     task.GetObjectInfo().GetObjectName(),
-			State:      "replicate+seal",
-			Error:      taskErr,
-			Logs:       task.GetLogs(),
+			UpdateTime:            updateTime,
+			UpdateTimestampSecond: updateTimestampSecond,
+			ObjectID:              task.GetObjectInfo().Id.Uint64(),
+			Bucket:                task.GetObjectInfo().GetBucketName(),
+			Object:                task.GetObjectInfo().GetObjectName(),
+			State:                 "replicate+seal",
+			Error:                 taskErr,
+			Logs:                  task.GetLogs(),
 		})
 	}
 	return nil
 }
 
 func (s *SpDBImpl) InsertSealEvent(task coretask.SealObjectTask) error {
-	updateTime := time.Now().String()
+	now := time.Now()
+	updateTime, updateTimestampSecond := now.String(), now.Unix()
 	taskErr := ""
 	if task.Error() != nil {
 		taskErr = task.Error().Error()
 	}
 	s.db.Create(&PutObjectEventTable{
-		UpdateTime: updateTime,
-		ObjectID:   task.GetObjectInfo().Id.Uint64(),
-		Bucket:     task.GetObjectInfo().GetBucketName(),
-		Object:     task.GetObjectInfo().GetObjectName(),
-		State:      "Seal",
-		Error:      taskErr,
-		Logs:       task.GetLogs(),
+		UpdateTime:            updateTime,
+		UpdateTimestampSecond: updateTimestampSecond,
+		ObjectID:              task.GetObjectInfo().Id.Uint64(),
+		Bucket:                task.GetObjectInfo().GetBucketName(),
+		Object:                task.GetObjectInfo().GetObjectName(),
+		State:                 "Seal",
+		Error:                 taskErr,
+		Logs:                  task.GetLogs(),
 	})
 
 	if task.Error() != nil {
 		s.db.Create(&SealFailedTable{
-			UpdateTime: updateTime,
-			ObjectID:   task.GetObjectInfo().Id.Uint64(),
-			Bucket:     task.GetObjectInfo().GetBucketName(),
-			Object:     task.GetObjectInfo().GetObjectName(),
-			Error:      taskErr,
-			Logs:       task.GetLogs(),
+			UpdateTime:            updateTime,
+			UpdateTimestampSecond: updateTimestampSecond,
+			ObjectID:              task.GetObjectInfo().Id.Uint64(),
+			Bucket:                task.GetObjectInfo().GetBucketName(),
+			Object:                task.GetObjectInfo().GetObjectName(),
+			Error:                 taskErr,
+			Logs:                  task.GetLogs(),
 		})
 	} else if time.Now().Unix()-task.GetCreateTime() > 10 {
 		s.db.Create(&SealTimeoutTable{
-			UpdateTime: updateTime,
-			ObjectID:   task.GetObjectInfo().Id.Uint64(),
-			Bucket:     task.GetObjectInfo().GetBucketName(),
-			Object:     task.GetObjectInfo().GetObjectName(),
-			Error:      taskErr,
-			Logs:       task.GetLogs(),
+			UpdateTime:            updateTime,
+			UpdateTimestampSecond: updateTimestampSecond,
+			ObjectID:              task.GetObjectInfo().Id.Uint64(),
+			Bucket:                task.GetObjectInfo().GetBucketName(),
+			Object:                task.GetObjectInfo().GetObjectName(),
+			Error:                 taskErr,
+			Logs:                  task.GetLogs(),
 		})
 	} else {
 		s.db.Create(&PutObjectSuccessTable{
-			UpdateTime: updateTime,
-			ObjectID:   task.GetObjectInfo().Id.Uint64(),
-			Bucket:     task.GetObjectInfo().GetBucketName(),
-			Object:     task.GetObjectInfo().GetObjectName(),
-			State:      "seal",
-			Error:      taskErr,
-			Logs:       task.GetLogs(),
+			UpdateTime:            updateTime,
+			UpdateTimestampSecond: updateTimestampSecond,
+			ObjectID:              task.GetObjectInfo().Id.Uint64(),
+			Bucket:                task.GetObjectInfo().GetBucketName(),
+			Object:                task.GetObjectInfo().GetObjectName(),
+			State:                 "seal",
+			Error:                 taskErr,
+			Logs:                  task.GetLogs(),
 		})
 	}
 	return nil
 }
+
+// DeleteExpiredPutEvent deletes the event logs whose update time is before the expiredTime, the
+// number of deleted event logs in every event table is at most limit.
+func (s *SpDBImpl) DeleteExpiredPutEvent(expiredTime time.Time, limit int) (int64, error) {
+	var (
+		deleted     int64
+		eventTables = []interface{}{
+			&PutObjectSuccessTable{},
+			&PutObjectEventTable{},
+			&UploadTimeoutTable{},
+			&UploadFailedTable{},
+			&ReplicateTimeoutTable{},
+			&ReplicateFailedTable{},
+			&SealTimeoutTable{},
+			&SealFailedTable{},
+		}
+	)
+	for _, eventTable := range eventTables {
+		n, err := deleteInBatch(s.db, eventTable, "id", limit, "update_timestamp_second < ?", expiredTime.Unix())
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete expired event log: %s", err)
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
@@ -2,14 +2,15 @@ package sqldb
 
 // PutObjectSuccessTable table schema.
 type PutObjectSuccessTable struct {
-	ID         uint64 `gorm:"primary_key;autoIncrement"`
-	UpdateTime string `gorm:"index:update_time_index"`
-	ObjectID   uint64 `gorm:"index:object_id_index"`
-	Bucket     string `gorm:"index:bucket_index"`
-	Object     string `gorm:"index:object_index"`
-	State      string
-	Error      string
-	Logs       string
+	ID                    uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime            string `gorm:"index"`
+	UpdateTimestampSecond int64  `gorm:"index"`
+	ObjectID              uint64 `gorm:"index"`
+	Bucket                string `gorm:"index"`
+	Object                string `gorm:"index"`
+	State                 string
+	Error                 string
+	Logs                  string
 }
 
 // TableName is used to set UploadObjectProgressTable Schema's table name in database.
@@ -19,14 +20,15 @@ func (PutObjectSuccessTable) TableName() string {
 
 // PutObjectEventTable table schema.
 type PutObjectEventTable struct {
-	ID         uint64 `gorm:"primary_key;autoIncrement"`
-	UpdateTime string `gorm:"index:update_time_index"`
-	ObjectID   uint64 `gorm:"index:object_id_index"`
-	Bucket     string `gorm:"index:bucket_index"`
-	Object     string `gorm:"index:object_index"`
-	State      string
-	Error      string
-	Logs       string
+	ID                    uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime            string `gorm:"index"`
+	UpdateTimestampSecond int64  `gorm:"index"`
+	ObjectID              uint64 `gorm:"index"`
+	Bucket                string `gorm:"index"`
+	Object                string `gorm:"index"`
+	State                 string
+	Error                 string
+	Logs                  string
 }
 
 // TableName is used to set UploadObjectProgressTable Schema's table name in database.
@@ -36,13 +38,14 @@ func (PutObjectEventTable) TableName() string {
 
 // UploadTimeoutTable table schema.
 type UploadTimeoutTable struct {
-	ID         uint64 `gorm:"primary_key;autoIncrement"`
-	UpdateTime string `gorm:"index:update_time_index"`
-	ObjectID   uint64 `gorm:"index:object_id_index"`
-	Bucket     string `gorm:"index:bucket_index"`
-	Object     string `gorm:"index:object_index"`
-	Error      string
-	Logs       string
+	ID                    uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime            string `gorm:"index"`
+	UpdateTimestampSecond int64  `gorm:"index"`
+	ObjectID              uint64 `gorm:"index"`
+	Bucket                string `gorm:"index"`
+	Object                string `gorm:"index"`
+	Error                 string
+	Logs                  string
 }
 
 // TableName is used to set UploadTimeoutTable Schema's table name in database.
@@ -52,13 +55,14 @@ func (UploadTimeoutTable) TableName() string {
 
 // ReplicateTimeoutTable table schema.
 type ReplicateTimeoutTable struct {
-	ID         uint64 `gorm:"primary_key;autoIncrement"`
-	UpdateTime string `gorm:"index:update_time_index"`
-	ObjectID   uint64 `gorm:"index:object_id_index"`
-	Bucket     string `gorm:"index:bucket_index"`
-	Object     string `gorm:"index:object_index"`
-	Error      string
-	Logs       string
+	ID                    uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime            string `gorm:"index"`
+	UpdateTimestampSecond int64  `gorm:"index"`
+	ObjectID              uint64 `gorm:"index"`
+	Bucket                string `gorm:"index"`
+	Object                string `gorm:"index"`
+	Error                 string
+	Logs                  string
 }
 
 // TableName is used to set ReplicateTimeoutTable Schema's table name in database.
@@ -68,13 +72,14 @@ func (ReplicateTimeoutTable) TableName() string {
 
 // SealTimeoutTable table schema.
 type SealTimeoutTable struct {
-	ID         uint64 `gorm:"primary_key;autoIncrement"`
-	UpdateTime string `gorm:"index:update_time_index"`
-	ObjectID   uint64 `gorm:"index:object_id_index"`
-	Bucket     string `gorm:"index:bucket_index"`
-	Object     string `gorm:"index:object_index"`
-	Error      string
-	Logs       string
+	ID                    uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime            string `gorm:"index"`
+	UpdateTimestampSecond int64  `gorm:"index"`
+	ObjectID              uint64 `gorm:"index"`
+	Bucket                string `gorm:"index"`
+	Object                string `gorm:"index"`
+	Error                 string
+	Logs                  string
 }
 
 // TableName is used to set SealTimeoutTable Schema's table name in database.
@@ -84,13 +89,14 @@ func (SealTimeoutTable) TableName() string {
 
 // UploadFailedTable table schema.
 type UploadFailedTable struct {
-	ID         uint64 `gorm:"primary_key;autoIncrement"`
-	UpdateTime string `gorm:"index:update_time_index"`
-	ObjectID   uint64 `gorm:"index:object_id_index"`
-	Bucket     string `gorm:"index:bucket_index"`
-	Object     string `gorm:"index:object_index"`
-	Error      string
-	Logs       string
+	ID                    uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime            string `gorm:"index"`
+	UpdateTimestampSecond int64  `gorm:"index"`
+	ObjectID              uint64 `gorm:"index"`
+	Bucket                string `gorm:"index"`
+	Object                string `gorm:"index"`
+	Error                 string
+	Logs                  string
 }
 
 // TableName is used to set UploadTimeoutTable Schema's table name in database.
@@ -100,13 +106,14 @@ func (UploadFailedTable) TableName() string {
 
 // ReplicateFailedTable table schema.
 type ReplicateFailedTable struct {
-	ID         uint64 `gorm:"primary_key;autoIncrement"`
-	UpdateTime string `gorm:"index:update_time_index"`
-	ObjectID   uint64 `gorm:"index:object_id_index"`
-	Bucket     string `gorm:"index:bucket_index"`
-	Object     string `gorm:"index:object_index"`
-	Error      string
-	Logs       string
+	ID                    uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime            string `gorm:"index"`
+	UpdateTimestampSecond int64  `gorm:"index"`
+	ObjectID              uint64 `gorm:"index"`
+	Bucket                string `gorm:"index"`
+	Object                string `gorm:"index"`
+	Error                 string
+	Logs                  string
 }
 
 // TableName is used to set ReplicateTimeoutTable Schema's table name in database.
@@ -116,13 +123,14 @@ func (ReplicateFailedTable) TableName() string {
 
 // SealFailedTable table schema.
 type SealFailedTable struct {
-	ID         uint64 `gorm:"primary_key;autoIncrement"`
-	UpdateTime string `gorm:"index:update_time_index"`
-	ObjectID   uint64 `gorm:"index:object_id_index"`
-	Bucket     string `gorm:"index:bucket_index"`
-	Object     string `gorm:"index:object_index"`
-	Error      string
-	Logs       string
+	ID                    uint64 `gorm:"primary_key;autoIncrement"`
+	UpdateTime            string `gorm:"index"`
+	UpdateTimestampSecond int64  `gorm:"index"`
+	ObjectID              uint64 `gorm:"index"`
+	Bucket                string `gorm:"index"`
+	Object                string `gorm:"index"`
+	Error                 string
+	Logs                  string
 }
 
 // TableName is used to set SealTimeoutTable Schema's table name in database.
@@ -0,0 +1,38 @@
+package sqldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpDBImpl_DeleteExpiredPutEvent(t *testing.T) {
+	s := setupSpDBTest(t)
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		expired := now.Add(-time.Duration(i) * time.Hour).Unix()
+		require.NoError(t, s.db.Create(&PutObjectEventTable{UpdateTimestampSecond: expired, ObjectID: uint64(i)}).Error)
+		require.NoError(t, s.db.Create(&SealFailedTable{UpdateTimestampSecond: expired, ObjectID: uint64(i)}).Error)
+	}
+	// the update time string is not compared, it is recorded in the local time zone
+	require.NoError(t, s.db.Create(&PutObjectEventTable{UpdateTime: now.Add(-time.Hour).String(),
+		UpdateTimestampSecond: now.Unix(), ObjectID: 4}).Error)
+
+	// at most limit event logs are deleted from every event table
+	deleted, err := s.DeleteExpiredPutEvent(now.Add(-time.Minute), 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+	deleted, err = s.DeleteExpiredPutEvent(now.Add(-time.Minute), 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	var events []PutObjectEventTable
+	require.NoError(t, s.db.Find(&events).Error)
+	require.Len(t, events, 1)
+	assert.Equal(t, uint64(4), events[0].ObjectID)
+	var failures []SealFailedTable
+	require.NoError(t, s.db.Find(&failures).Error)
+	assert.Empty(t, failures)
+}
@@ -122,3 +122,26 @@ func (s *SpDBImpl) GetUploadMetasToSeal(limit int, timeoutSecond int64) ([]*core
 	}
 	return returnUploadObjectMetas, nil
 }
+
+// terminalUploadTaskStates are the states of the upload object progresses which will not be updated any
+// more, the progresses in the other states are used by the uploading objects.
+var terminalUploadTaskStates = []int32{
+	int32(storetypes.TaskState_TASK_STATE_UPLOAD_OBJECT_ERROR),
+	int32(storetypes.TaskState_TASK_STATE_ALLOC_SECONDARY_ERROR),
+	int32(storetypes.TaskState_TASK_STATE_REPLICATE_OBJECT_ERROR),
+	int32(storetypes.TaskState_TASK_STATE_SIGN_OBJECT_ERROR),
+	int32(storetypes.TaskState_TASK_STATE_SEAL_OBJECT_DONE),
+	int32(storetypes.TaskState_TASK_STATE_SEAL_OBJECT_ERROR),
+	int32(storetypes.TaskState_TASK_STATE_OBJECT_DISCONTINUED),
+}
+
+// DeleteExpiredUploadProgress deletes the upload object progresses in the terminal states which have not
+// been updated since the expiredTimestampSecond, the number of deleted progresses is at most limit.
+func (s *SpDBImpl) DeleteExpiredUploadProgress(expiredTimestampSecond int64, limit int) (int64, error) {
+	deleted, err := deleteInBatch(s.db, &UploadObjectProgressTable{}, "object_id", limit,
+		"update_timestamp_second < ? and task_state IN ?", expiredTimestampSecond, terminalUploadTaskStates)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired upload record: %s", err)
+	}
+	return deleted, nil
+}
@@ -10,7 +10,7 @@ type UploadObjectProgressTable struct {
 	SecondaryEndpoints    string
 	SecondarySignatures   string
 	CreateTimestampSecond int64
-	UpdateTimestampSecond int64 `gorm:"index:update_timestamp_index"`
+	UpdateTimestampSecond int64 `gorm:"index"`
 }
 
 // TableName is used to set UploadObjectProgressTable Schema's table name in database.
@@ -0,0 +1,45 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storetypes "github.com/bnb-chain/greenfield-storage-provider/store/types"
+)
+
+func TestSpDBImpl_DeleteExpiredUploadProgress(t *testing.T) {
+	s := setupSpDBTest(t)
+	for i := int64(1); i <= 5; i++ {
+		require.NoError(t, s.db.Create(&UploadObjectProgressTable{ObjectID: uint64(i), UpdateTimestampSecond: i * 100,
+			TaskState: int32(storetypes.TaskState_TASK_STATE_SEAL_OBJECT_DONE)}).Error)
+	}
+	// the progresses of the uploading objects are kept however long they are not updated
+	for i, state := range []storetypes.TaskState{storetypes.TaskState_TASK_STATE_INIT_UNSPECIFIED,
+		storetypes.TaskState_TASK_STATE_UPLOAD_OBJECT_DONE, storetypes.TaskState_TASK_STATE_REPLICATE_OBJECT_DOING,
+		storetypes.TaskState_TASK_STATE_SEAL_OBJECT_DOING} {
+		require.NoError(t, s.db.Create(&UploadObjectProgressTable{ObjectID: uint64(10 + i), UpdateTimestampSecond: 100,
+			TaskState: int32(state)}).Error)
+	}
+	require.NoError(t, s.db.Create(&UploadObjectProgressTable{ObjectID: 20, UpdateTimestampSecond: 100,
+		TaskState: int32(storetypes.TaskState_TASK_STATE_REPLICATE_OBJECT_ERROR)}).Error)
+
+	deleted, err := s.DeleteExpiredUploadProgress(450, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	deleted, err = s.DeleteExpiredUploadProgress(450, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	deleted, err = s.DeleteExpiredUploadProgress(450, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	var remained []UploadObjectProgressTable
+	require.NoError(t, s.db.Order("object_id").Find(&remained).Error)
+	require.Len(t, remained, 5)
+	assert.Equal(t, uint64(5), remained[0].ObjectID)
+	for _, progress := range remained[1:] {
+		assert.NotEqual(t, int32(storetypes.TaskState_TASK_STATE_SEAL_OBJECT_DONE), progress.TaskState)
+	}
+}
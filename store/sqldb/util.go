@@ -1,8 +1,9 @@
 package sqldb
 
 import (
-	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // GetCurrentYearMonth get current year and month
@@ -37,6 +38,17 @@ func TimeToYearMonth(t time.Time) string {
 	return t.Format("2006-01-02 15:04:05")[0:7]
 }
 
-func isAlreadyExists(err error) bool {
-	return strings.HasPrefix(err.Error(), TableAlreadyExistsErrorPrefix)
+// deleteInBatch deletes at most limit rows of the table which match the query, returns the number of
+// deleted rows. The primary keys of the rows are selected with the limit first because only mysql supports
+// the limit of delete, the other engines ignore the limit and delete all the matched rows.
+func deleteInBatch(db *gorm.DB, table interface{}, keyColumn string, limit int, query string, args ...interface{}) (int64, error) {
+	var keys []uint64
+	if err := db.Model(table).Where(query, args...).Limit(limit).Pluck(keyColumn, &keys).Error; err != nil {
+		return 0, err
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	result := db.Where(keyColumn+" IN ?", keys).Delete(table)
+	return result.RowsAffected, result.Error
 }